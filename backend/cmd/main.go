@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"license-manager/internal/api/routes"
@@ -16,6 +21,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout 退出时等待处理中请求结束的最长时间
+const shutdownTimeout = 30 * time.Second
+
 // @title License Manager API
 // @version 1.0
 // @description 软件授权管理系统API文档
@@ -36,6 +44,11 @@ import (
 // @name Authorization
 // @description Bearer token for authentication
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key for server-to-server entitlement checks
+
 func main() {
 	// 定义命令行参数
 	var customPath string
@@ -115,13 +128,30 @@ func main() {
 	gin.SetMode(cfg.Server.Mode)
 
 	// 设置路由
-	router := routes.SetupRouter()
+	router, stop := routes.SetupRouter()
 
 	// 启动服务器
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
 	log.Infof("服务器启动在 %s", addr)
 
-	if err := router.Run(addr); err != nil {
-		log.Fatalf("服务器启动失败: %v", err)
+	srv := &http.Server{Addr: addr, Handler: router}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("服务器启动失败: %v", err)
+		}
+	}()
+
+	// 收到退出信号后停止接收新请求，等待处理中的请求结束，再停止定时任务和后台任务
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Info("正在停止服务器...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Errorf("服务器停止失败: %v", err)
 	}
+	stop()
+	log.Info("服务器已停止")
 }
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthorizationCodeBatchHandler struct {
+	batchService service.AuthorizationCodeBatchService
+}
+
+// NewAuthorizationCodeBatchHandler 创建授权码批次处理器
+func NewAuthorizationCodeBatchHandler(batchService service.AuthorizationCodeBatchService) *AuthorizationCodeBatchHandler {
+	return &AuthorizationCodeBatchHandler{
+		batchService: batchService,
+	}
+}
+
+// CreateBatch 批量生成授权码
+// @Summary 批量生成授权码
+// @Description 按相同配置为客户（分销商）批量生成授权码，生成在后台执行，通过批次详情查询进度
+// @Tags 授权码批次
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param batch body models.AuthorizationCodeBatchCreateRequest true "批次信息"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeBatch} "创建成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "客户不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-code-batches [post]
+func (h *AuthorizationCodeBatchHandler) CreateBatch(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.AuthorizationCodeBatchCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.batchService.CreateBatch(ctx, getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetBatchList 获取授权码批次列表
+// @Summary 获取授权码批次列表
+// @Description 分页查询授权码批次，支持按客户、标签和状态筛选
+// @Tags 授权码批次
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param customer_id query string false "客户ID筛选"
+// @Param label query string false "批次标签模糊搜索"
+// @Param status query string false "状态筛选" Enums(pending, running, completed, failed, revoked)
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeBatchListResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-code-batches [get]
+func (h *AuthorizationCodeBatchHandler) GetBatchList(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.AuthorizationCodeBatchListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.batchService.GetBatchList(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetBatch 获取授权码批次详情
+// @Summary 获取授权码批次详情
+// @Description 查询批次信息及生成进度
+// @Tags 授权码批次
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "批次ID"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeBatch} "成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-code-batches/{id} [get]
+func (h *AuthorizationCodeBatchHandler) GetBatch(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.batchService.GetBatch(ctx, c.Param("id"))
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// ExportBatch 导出批次授权码
+// @Summary 导出批次授权码
+// @Description 下载批次内全部授权码及其ID、有效期，支持 CSV 和 XLSX 格式
+// @Tags 授权码批次
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "批次ID"
+// @Param format query string false "导出格式，默认csv" Enums(csv, xlsx)
+// @Success 200 {file} file "导出文件"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-code-batches/{id}/export [get]
+func (h *AuthorizationCodeBatchHandler) ExportBatch(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	format := c.DefaultQuery("format", models.BatchExportFormatCSV)
+	if format != models.BatchExportFormatCSV && format != models.BatchExportFormatXLSX {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message,
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, fileName, contentType, err := h.batchService.ExportBatch(ctx, c.Param("id"), format)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+	c.Header("Cache-Control", "must-revalidate")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// LockBatch 整批锁定/解锁授权码
+// @Summary 整批锁定/解锁授权码
+// @Description 锁定或解锁批次内全部授权码，并为每个授权码记录变更历史
+// @Tags 授权码批次
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "批次ID"
+// @Param request body models.AuthorizationCodeLockRequest true "锁定信息"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeBatchOperationResponse} "操作成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-code-batches/{id}/lock [put]
+func (h *AuthorizationCodeBatchHandler) LockBatch(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.AuthorizationCodeLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.batchService.LockBatch(ctx, c.Param("id"), getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// RevokeBatch 撤销整个批次
+// @Summary 撤销授权码批次
+// @Description 锁定批次内全部授权码并撤销其下已激活的许可证，操作不可恢复
+// @Tags 授权码批次
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "批次ID"
+// @Param request body models.AuthorizationCodeBatchRevokeRequest false "撤销原因"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeBatchOperationResponse} "操作成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-code-batches/{id}/revoke [post]
+func (h *AuthorizationCodeBatchHandler) RevokeBatch(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.AuthorizationCodeBatchRevokeRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+			c.JSON(status, models.ErrorResponse{
+				Code:      errCode,
+				Message:   message + ": " + err.Error(),
+				Timestamp: getCurrentTimestamp(),
+			})
+			return
+		}
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.batchService.RevokeBatch(ctx, c.Param("id"), getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
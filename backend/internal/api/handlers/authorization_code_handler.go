@@ -108,6 +108,9 @@ func (h *AuthorizationCodeHandler) CreateAuthorizationCode(c *gin.Context) {
 // @Param end_date query string false "创建结束时间"
 // @Param sort query string false "排序字段，默认created_at" Enums(created_at, updated_at, code)
 // @Param order query string false "排序方向，默认desc" Enums(asc, desc)
+// @Param tags query []string false "标签筛选，可重复传递，需同时具有全部标签" collectionFormat(multi)
+// @Param field_search query string false "模糊匹配标签和自定义字段值"
+// @Param custom_fields[key] query string false "自定义字段精确筛选，key 为字段标识"
 // @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeListResponse} "查询成功"
 // @Failure 400 {object} models.ErrorResponse "请求参数无效"
 // @Failure 401 {object} models.ErrorResponse "未认证"
@@ -126,6 +129,9 @@ func (h *AuthorizationCodeHandler) GetAuthorizationCodeList(c *gin.Context) {
 		return
 	}
 
+	// 自定义字段筛选：custom_fields[字段标识]=值
+	req.CustomFields = c.QueryMap("custom_fields")
+
 	// 直接传递 gin.Context
 	data, err := h.authCodeService.GetAuthorizationCodeList(c, &req)
 	if err != nil {
@@ -509,7 +515,7 @@ func (h *AuthorizationCodeHandler) LockUnlockAuthorizationCode(c *gin.Context) {
 
 // DeleteAuthorizationCode 删除授权码
 // @Summary 删除授权码
-// @Description 删除指定的授权码（软删除）
+// @Description 删除指定的授权码（软删除），删除后进入回收站，可在回收站恢复或彻底删除
 // @Tags 授权码管理
 // @Accept json
 // @Produce json
@@ -645,3 +651,225 @@ func (h *AuthorizationCodeHandler) GetAuthorizationChangeList(c *gin.Context) {
 		Data:    data,
 	})
 }
+
+// SplitAuthorizationCode 拆分授权码
+// @Summary 拆分授权码
+// @Description 将授权码拆分为多个授权码（如按部门分配），各部分席位数之和必须等于原授权码席位数。第一部分保留在原授权码上，超出的已激活许可证按激活顺序迁移到新授权码
+// @Tags 授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "授权码ID"
+// @Param split_request body models.AuthorizationCodeSplitRequest true "拆分方案"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeSplitResponse} "操作成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "授权码不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-codes/{id}/split [post]
+func (h *AuthorizationCodeHandler) SplitAuthorizationCode(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.AuthorizationCodeSplitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	// 直接传递 gin.Context，服务层从中获取操作人
+	data, err := h.authCodeService.SplitAuthorizationCode(c, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// MergeAuthorizationCodes 合并授权码
+// @Summary 合并授权码
+// @Description 将同一客户、相同配置的授权码并入目标授权码：席位数累加、有效期取并集、许可证迁移到目标授权码，来源授权码清零并锁定
+// @Tags 授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param merge_request body models.AuthorizationCodeMergeRequest true "合并信息"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeMergeResponse} "操作成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "授权码不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-codes/merge [post]
+func (h *AuthorizationCodeHandler) MergeAuthorizationCodes(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.AuthorizationCodeMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	// 直接传递 gin.Context，服务层从中获取操作人
+	data, err := h.authCodeService.MergeAuthorizationCodes(c, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetAuthorizationChangeDiff 对比授权变更快照
+// @Summary 对比授权变更快照
+// @Description 逐字段对比授权码两条变更记录的配置快照（JSON配置字段展开为点号路径），未指定终点变更记录时与授权码当前配置对比
+// @Tags 授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "授权码ID"
+// @Param from_change_id query string true "对比起点变更记录ID"
+// @Param from_snapshot query string false "起点快照，默认new" Enums(old, new)
+// @Param to_change_id query string false "对比终点变更记录ID，为空时与当前配置对比"
+// @Param to_snapshot query string false "终点快照，默认new" Enums(old, new)
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationChangeDiffResponse} "查询成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "授权码或变更记录不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-codes/{id}/changes/diff [get]
+func (h *AuthorizationCodeHandler) GetAuthorizationChangeDiff(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.AuthorizationChangeDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	// 直接传递 gin.Context
+	data, err := h.authCodeService.GetAuthorizationChangeDiff(c, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// RevertAuthorizationCode 回滚授权码配置
+// @Summary 回滚授权码配置
+// @Description 将授权码配置回滚到指定变更记录的快照（授权码本身和锁定状态不回滚），并记录一条 revert 类型的变更。回滚后的最大激活数不能小于当前已激活数，客户端在下一次心跳时获取回滚后的配置
+// @Tags 授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "授权码ID"
+// @Param revert_request body models.AuthorizationCodeRevertRequest true "回滚信息"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCode} "操作成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或回滚后席位数小于已激活数"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "授权码或变更记录不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-codes/{id}/revert [post]
+func (h *AuthorizationCodeHandler) RevertAuthorizationCode(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.AuthorizationCodeRevertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	// 直接传递 gin.Context，服务层从中获取操作人
+	data, err := h.authCodeService.RevertAuthorizationCode(c, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// UpdateAuthorizationCodeCustomFields 修改授权码自定义字段和标签
+// @Summary 修改授权码自定义字段和标签
+// @Description 只修改传入的自定义字段，值为空字符串表示清除（必填字段不能清除）；传入 tags 时整体替换标签，传空数组表示清除全部标签；修改了写入许可证文件的字段时，客户端下次心跳会收到新的许可证文件
+// @Tags 授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "授权码ID"
+// @Param fields body models.EntityCustomFieldsUpdateRequest true "自定义字段和标签"
+// @Success 200 {object} models.APIResponse{data=models.EntityCustomFields} "修改成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或字段校验失败"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "授权码不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-codes/{id}/custom-fields [put]
+func (h *AuthorizationCodeHandler) UpdateAuthorizationCodeCustomFields(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.EntityCustomFieldsUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.authCodeService.UpdateAuthorizationCodeCustomFields(ctx, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
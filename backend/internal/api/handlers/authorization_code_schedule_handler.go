@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthorizationCodeScheduleHandler struct {
+	scheduleService service.AuthorizationCodeScheduleService
+}
+
+func NewAuthorizationCodeScheduleHandler(scheduleService service.AuthorizationCodeScheduleService) *AuthorizationCodeScheduleHandler {
+	return &AuthorizationCodeScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// CreateScheduledAction 创建授权码计划操作
+// @Summary 创建授权码计划操作
+// @Description 为授权码安排在指定时间（at）或关联订单支付后（order_paid）执行的操作：锁定、解锁、延期或调整最大激活数。定时任务执行后记录到授权变更历史
+// @Tags 授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "授权码ID"
+// @Param request body models.AuthorizationCodeScheduledActionCreateRequest true "计划操作"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeScheduledAction} "创建成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "授权码或关联订单不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-codes/{id}/scheduled-actions [post]
+func (h *AuthorizationCodeScheduleHandler) CreateScheduledAction(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.AuthorizationCodeScheduledActionCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.scheduleService.CreateScheduledAction(ctx, c.Param("id"), getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetScheduledActions 查询授权码计划操作列表
+// @Summary 查询授权码计划操作列表
+// @Description 分页查询授权码的计划操作，待执行的排在前面
+// @Tags 授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "授权码ID"
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param status query string false "状态筛选" Enums(pending, done, failed, cancelled)
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeScheduledActionListResponse} "查询成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-codes/{id}/scheduled-actions [get]
+func (h *AuthorizationCodeScheduleHandler) GetScheduledActions(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.AuthorizationCodeScheduledActionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.scheduleService.GetScheduledActionList(ctx, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// CancelScheduledAction 取消授权码计划操作
+// @Summary 取消授权码计划操作
+// @Description 取消尚未执行的计划操作
+// @Tags 授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "授权码ID"
+// @Param action_id path string true "计划操作ID"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeScheduledAction} "取消成功"
+// @Failure 400 {object} models.ErrorResponse "计划操作已执行或已取消"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "计划操作不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/authorization-codes/{id}/scheduled-actions/{action_id}/cancel [post]
+func (h *AuthorizationCodeScheduleHandler) CancelScheduledAction(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.scheduleService.CancelScheduledAction(ctx, c.Param("id"), c.Param("action_id"))
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
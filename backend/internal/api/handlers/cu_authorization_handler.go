@@ -24,7 +24,7 @@ func NewCuAuthorizationHandler(authCodeService service.AuthorizationCodeService)
 
 // ShareAuthorizationCode 用户分享授权码
 // @Summary 用户分享授权码
-// @Description 用户可以将自己的授权码分享给其他用户（通过手机号或邮箱）
+// @Description 用户可以将自己的授权码分享给其他用户（通过手机号或邮箱）。mode=invite 时仅发出邀请，受赠方接受后才转移席位
 // @Tags 用户端授权码管理
 // @Accept json
 // @Produce json
@@ -70,7 +70,8 @@ func (h *CuAuthorizationHandler) ShareAuthorizationCode(c *gin.Context) {
 	}
 
 	// 调用服务层处理分享逻辑
-	data, err := h.authCodeService.ShareAuthorizationCode(c.Request.Context(), codeID, userID, &req)
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.authCodeService.ShareAuthorizationCode(ctx, codeID, userID, &req)
 	if err != nil {
 		// err已经是i18n.I18nError，直接使用
 		i18nErr, ok := err.(*i18n.I18nError)
@@ -276,3 +277,189 @@ func (h *CuAuthorizationHandler) GetCuAuthorizationCodeSummary(c *gin.Context) {
 		Timestamp: getCurrentTimestamp(),
 	})
 }
+
+// GetShareList 获取授权码分享记录
+// @Summary 获取授权码分享记录
+// @Description 查询当前用户分享出去和收到的分享记录，包含原授权码与分享生成的授权码
+// @Tags 用户端授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param direction query string false "方向：sent-我分享的，received-我收到的" Enums(sent, received)
+// @Param status query string false "状态筛选" Enums(pending, accepted, declined, cancelled)
+// @Param code_id query string false "授权码ID（原授权码或分享生成的授权码）"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeShareListResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/authorization-code-shares [get]
+func (h *CuAuthorizationHandler) GetShareList(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	var req models.AuthorizationCodeShareListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.authCodeService.GetShareList(ctx, claims.UserID, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// AcceptShare 接受分享邀请
+// @Summary 接受分享邀请
+// @Description 受赠方接受分享邀请，从原授权码转移席位并生成新的授权码
+// @Tags 用户端授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "分享记录ID"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeShareResponse} "接受成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/authorization-code-shares/{id}/accept [post]
+func (h *CuAuthorizationHandler) AcceptShare(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.authCodeService.AcceptShare(ctx, c.Param("id"), claims.UserID)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// DeclineShare 拒绝分享邀请
+// @Summary 拒绝分享邀请
+// @Description 受赠方拒绝分享邀请，席位保留在原授权码
+// @Tags 用户端授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "分享记录ID"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeShare} "操作成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/authorization-code-shares/{id}/decline [post]
+func (h *CuAuthorizationHandler) DeclineShare(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.authCodeService.DeclineShare(ctx, c.Param("id"), claims.UserID)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// CancelShare 取消分享邀请
+// @Summary 取消分享邀请
+// @Description 分享方取消尚未被接受的分享邀请
+// @Tags 用户端授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "分享记录ID"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeShare} "操作成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/authorization-code-shares/{id}/cancel [post]
+func (h *CuAuthorizationHandler) CancelShare(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.authCodeService.CancelShare(ctx, c.Param("id"), claims.UserID)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// ReclaimShare 收回分享的席位
+// @Summary 收回分享的席位
+// @Description 分享方将分享授权码中未激活的席位收回到原授权码，不传数量时收回全部未使用席位
+// @Tags 用户端授权码管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "分享记录ID"
+// @Param request body models.AuthorizationCodeShareReclaimRequest false "收回数量"
+// @Success 200 {object} models.APIResponse{data=models.AuthorizationCodeShare} "操作成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/authorization-code-shares/{id}/reclaim [post]
+func (h *CuAuthorizationHandler) ReclaimShare(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	var req models.AuthorizationCodeShareReclaimRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+			c.JSON(status, models.ErrorResponse{
+				Code:      errCode,
+				Message:   message + ": " + err.Error(),
+				Timestamp: getCurrentTimestamp(),
+			})
+			return
+		}
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.authCodeService.ReclaimShare(ctx, c.Param("id"), claims.UserID, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -14,11 +14,13 @@ import (
 
 type CuDeviceHandler struct {
 	cuDeviceService service.CuDeviceService
+	licenseService  service.LicenseService
 }
 
-func NewCuDeviceHandler(cuDeviceService service.CuDeviceService) *CuDeviceHandler {
+func NewCuDeviceHandler(cuDeviceService service.CuDeviceService, licenseService service.LicenseService) *CuDeviceHandler {
 	return &CuDeviceHandler{
 		cuDeviceService: cuDeviceService,
+		licenseService:  licenseService,
 	}
 }
 
@@ -201,3 +203,48 @@ func (h *CuDeviceHandler) GetDeviceSummary(c *gin.Context) {
 		Data:    result,
 	})
 }
+
+// TransferDevice 转移设备
+// @Summary 转移设备
+// @Description 将设备占用的席位转移到新设备：原设备许可证停用并返回签名的停用回执，新设备获得许可证文件（支持离线导入）。转移次数受授权码配置限制
+// @Tags 客户设备管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "许可证ID"
+// @Param request body models.LicenseTransferRequest true "转移请求"
+// @Success 200 {object} models.APIResponse{data=models.LicenseTransferResponse} "转移成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "设备不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/devices/{id}/transfer [post]
+func (h *CuDeviceHandler) TransferDevice(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	var req models.LicenseTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	result, err := h.licenseService.TransferLicense(ctx, c.Param("id"), claims.CustomerID, models.LicenseTransferOperatorCuUser, claims.UserID, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      result,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+	"license-manager/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CuLicenseCommandHandler struct {
+	commandService service.LicenseCommandService
+}
+
+// NewCuLicenseCommandHandler 创建客户设备远程命令处理器
+func NewCuLicenseCommandHandler(commandService service.LicenseCommandService) *CuLicenseCommandHandler {
+	return &CuLicenseCommandHandler{
+		commandService: commandService,
+	}
+}
+
+// CreateCommand 创建远程命令
+// @Summary 创建远程命令
+// @Description 为当前客户的设备创建远程命令（刷新许可证、停用并删除本地文件、显示消息、收集诊断信息），设备下次心跳时下发，执行结果在之后的心跳中确认
+// @Tags 客户设备管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "许可证ID"
+// @Param request body models.LicenseCommandCreateRequest true "命令信息"
+// @Success 200 {object} models.APIResponse{data=models.LicenseCommand} "创建成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或许可证未激活"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "设备不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/devices/{id}/commands [post]
+func (h *CuLicenseCommandHandler) CreateCommand(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	var req models.LicenseCommandCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.commandService.CreateCommand(ctx, c.Param("id"), claims.CustomerID, models.LicenseCommandIssuerCuUser, claims.UserID, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetCommands 获取远程命令列表
+// @Summary 获取远程命令列表
+// @Description 分页查询设备的远程命令及下发、确认状态，按创建时间倒序
+// @Tags 客户设备管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "许可证ID"
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param status query string false "状态筛选" Enums(pending, delivered, succeeded, failed, cancelled, expired)
+// @Success 200 {object} models.APIResponse{data=models.LicenseCommandListResponse} "查询成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "设备不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/devices/{id}/commands [get]
+func (h *CuLicenseCommandHandler) GetCommands(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	var req models.LicenseCommandListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.commandService.GetCommandList(ctx, c.Param("id"), claims.CustomerID, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// CancelCommand 取消远程命令
+// @Summary 取消远程命令
+// @Description 取消尚未确认的远程命令，取消后不再下发
+// @Tags 客户设备管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "许可证ID"
+// @Param command_id path string true "命令ID"
+// @Success 200 {object} models.APIResponse{data=models.LicenseCommand} "取消成功"
+// @Failure 400 {object} models.ErrorResponse "命令已完成或已取消"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "命令不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/devices/{id}/commands/{command_id}/cancel [post]
+func (h *CuLicenseCommandHandler) CancelCommand(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.commandService.CancelCommand(ctx, c.Param("id"), c.Param("command_id"), claims.CustomerID)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
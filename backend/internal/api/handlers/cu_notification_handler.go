@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+	"license-manager/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CuNotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+func NewCuNotificationHandler(notificationService service.NotificationService) *CuNotificationHandler {
+	return &CuNotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications 获取通知列表
+// @Summary 获取通知列表
+// @Description 获取当前客户的站内通知，支持按类型和已读状态筛选
+// @Tags 客户通知
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param type query string false "通知类型筛选"
+// @Param is_read query bool false "是否已读筛选：true已读，false未读"
+// @Success 200 {object} models.APIResponse{data=models.NotificationListResponse} "获取成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/notifications [get]
+func (h *CuNotificationHandler) GetNotifications(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	var req models.NotificationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	result, err := h.notificationService.GetCuNotificationList(c.Request.Context(), claims.CustomerID, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      result,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// MarkNotificationRead 标记通知为已读
+// @Summary 标记通知为已读
+// @Description 将当前客户的指定通知标记为已读
+// @Tags 客户通知
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "通知ID"
+// @Success 200 {object} models.APIResponse "操作成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "通知不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/notifications/{id}/read [put]
+func (h *CuNotificationHandler) MarkNotificationRead(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	if err := h.notificationService.MarkCuNotificationRead(c.Request.Context(), claims.CustomerID, c.Param("id")); err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Timestamp: getCurrentTimestamp(),
+	})
+}
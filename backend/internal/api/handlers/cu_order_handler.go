@@ -140,7 +140,7 @@ func (h *CuOrderHandler) CalculatePrice(c *gin.Context) {
 
 // CreateOrder 创建订单（支持支付）
 // @Summary 创建订单
-// @Description 创建新的产品套餐订单，支持免费和付费模式
+// @Description 创建新的产品套餐订单，支持免费和付费模式。order_type=maintenance_renewal 时为续订维护订单：按授权码席位数计价，支付后只顺延该授权码的维护截止时间
 // @Tags 客户订单管理
 // @Accept json
 // @Produce json
@@ -191,6 +191,16 @@ func (h *CuOrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	// 续订维护订单：校验授权码并按其席位数计价
+	if err := h.cuOrderService.PrepareMaintenanceRenewal(c.Request.Context(), customerID.(string), &req); err != nil {
+		c.JSON(err.(*i18n.I18nError).HttpCode, models.ErrorResponse{
+			Code:      err.(*i18n.I18nError).Code,
+			Message:   err.(*i18n.I18nError).Message,
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
 	// 先计算价格，根据金额决定处理逻辑
 	priceResult, err := h.cuOrderService.CalculatePrice(c.Request.Context(), req.PackageID, req.LicenseCount)
 	if err != nil {
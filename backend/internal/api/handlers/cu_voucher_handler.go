@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+	"license-manager/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CuVoucherHandler struct {
+	voucherService service.VoucherService
+}
+
+// NewCuVoucherHandler 创建客户兑换券处理器
+func NewCuVoucherHandler(voucherService service.VoucherService) *CuVoucherHandler {
+	return &CuVoucherHandler{
+		voucherService: voucherService,
+	}
+}
+
+// RedeemVoucher 兑换兑换券
+// @Summary 兑换兑换券
+// @Description 使用兑换码在当前用户所属客户下生成授权码，每个兑换码仅可使用一次
+// @Tags 客户兑换券
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.VoucherRedeemRequest true "兑换码"
+// @Success 200 {object} models.APIResponse{data=models.VoucherRedeemResponse} "兑换成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/cu/vouchers/redeem [post]
+func (h *CuVoucherHandler) RedeemVoucher(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+	claims := c.MustGet("cu_user").(*utils.CuClaims)
+
+	var req models.VoucherRedeemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.voucherService.RedeemVoucher(ctx, claims.UserID, claims.CustomerID, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CustomFieldHandler struct {
+	customFieldService service.CustomFieldService
+}
+
+// NewCustomFieldHandler 创建自定义字段处理器
+func NewCustomFieldHandler(customFieldService service.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		customFieldService: customFieldService,
+	}
+}
+
+// GetDefinitions 获取自定义字段定义
+// @Summary 获取自定义字段定义
+// @Description 查询客户、授权码和许可证的自定义字段定义，按对象类型和排序返回，用于渲染表单和列表筛选
+// @Tags 自定义字段
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param entity_type query string false "对象类型筛选" Enums(customer, authorization_code, license)
+// @Success 200 {object} models.APIResponse{data=[]models.CustomFieldDefinition} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/custom-fields [get]
+func (h *CustomFieldHandler) GetDefinitions(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.CustomFieldDefinitionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.customFieldService.GetDefinitions(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// CreateDefinition 创建自定义字段
+// @Summary 创建自定义字段
+// @Description 管理员为客户、授权码或许可证定义自定义字段；单选字段需提供可选值，文本字段可设置校验正则，授权码字段可选择写入签名许可证文件
+// @Tags 自定义字段
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param field body models.CustomFieldDefinitionCreateRequest true "字段定义"
+// @Success 200 {object} models.APIResponse{data=models.CustomFieldDefinition} "创建成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或字段标识已存在"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "需要管理员权限"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/custom-fields [post]
+func (h *CustomFieldHandler) CreateDefinition(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.CustomFieldDefinitionCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.customFieldService.CreateDefinition(ctx, getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// UpdateDefinition 更新自定义字段
+// @Summary 更新自定义字段
+// @Description 更新字段名称、可选值、校验规则、是否必填、是否写入许可证文件、排序或说明；对象类型、字段标识和字段类型不可修改，新规则只对之后保存的值生效
+// @Tags 自定义字段
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "字段ID"
+// @Param field body models.CustomFieldDefinitionUpdateRequest true "更新内容"
+// @Success 200 {object} models.APIResponse{data=models.CustomFieldDefinition} "更新成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "需要管理员权限"
+// @Failure 404 {object} models.ErrorResponse "字段不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/custom-fields/{id} [put]
+func (h *CustomFieldHandler) UpdateDefinition(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.CustomFieldDefinitionUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.customFieldService.UpdateDefinition(ctx, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// DeleteDefinition 删除自定义字段
+// @Summary 删除自定义字段
+// @Description 删除字段定义，所有对象上该字段的值一并删除
+// @Tags 自定义字段
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "字段ID"
+// @Success 200 {object} models.APIResponse "删除成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "需要管理员权限"
+// @Failure 404 {object} models.ErrorResponse "字段不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/custom-fields/{id} [delete]
+func (h *CustomFieldHandler) DeleteDefinition(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	if err := h.customFieldService.DeleteDefinition(ctx, c.Param("id")); err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetTags 获取标签列表
+// @Summary 获取标签列表
+// @Description 查询对象类型下已使用的标签及使用次数，按使用次数倒序，最多返回100个，用于标签输入提示
+// @Tags 自定义字段
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param entity_type query string true "对象类型" Enums(customer, authorization_code, license)
+// @Param search query string false "标签前缀"
+// @Success 200 {object} models.APIResponse{data=[]models.TagCount} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/tags [get]
+func (h *CustomFieldHandler) GetTags(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.TagListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.customFieldService.GetTags(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
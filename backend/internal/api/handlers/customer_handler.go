@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -39,6 +41,9 @@ func NewCustomerHandler(customerService service.CustomerService) *CustomerHandle
 // @Param status query string false "状态筛选" Enums(active, disabled)
 // @Param sort query string false "排序字段，默认created_at" Enums(created_at, updated_at, customer_name, customer_code)
 // @Param order query string false "排序方向，默认desc" Enums(asc, desc)
+// @Param tags query []string false "标签筛选，可重复传递，需同时具有全部标签" collectionFormat(multi)
+// @Param field_search query string false "模糊匹配标签和自定义字段值"
+// @Param custom_fields[key] query string false "自定义字段精确筛选，key 为字段标识"
 // @Success 200 {object} models.APIResponse{data=models.CustomerListResponse} "查询成功"
 // @Failure 400 {object} models.ErrorResponse "请求参数无效"
 // @Failure 401 {object} models.ErrorResponse "未认证"
@@ -57,6 +62,9 @@ func (h *CustomerHandler) GetCustomerList(c *gin.Context) {
 		return
 	}
 
+	// 自定义字段筛选：custom_fields[字段标识]=值
+	req.CustomFields = c.QueryMap("custom_fields")
+
 	// 设置语言到Context中
 	ctx := middleware.WithLanguage(c.Request.Context(), c)
 
@@ -154,6 +162,68 @@ func (h *CustomerHandler) GetCustomer(c *gin.Context) {
 	})
 }
 
+// GetCustomerHierarchy 获取客户层级
+// @Summary 获取客户层级
+// @Description 获取客户的上级路径（从最顶层开始）及其下级客户树
+// @Tags 客户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "客户ID"
+// @Success 200 {object} models.APIResponse{data=models.CustomerHierarchyResponse} "查询成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "客户不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/customers/{id}/hierarchy [get]
+func (h *CustomerHandler) GetCustomerHierarchy(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		lang := middleware.GetLanguage(c)
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message,
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	// 设置语言到Context中
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+
+	data, err := h.customerService.GetCustomerHierarchy(ctx, id)
+	if err != nil {
+		// 错误已经在Service层完全包装好了，直接使用
+		var i18nErr *i18n.I18nError
+		if errors.As(err, &i18nErr) {
+			c.JSON(i18nErr.HttpCode, models.ErrorResponse{
+				Code:      i18nErr.Code,
+				Message:   i18nErr.Message,
+				Timestamp: time.Now().Format(time.RFC3339),
+			})
+		} else {
+			// 兜底：理论上不应该到这里，因为Service层应该返回I18nError
+			lang := middleware.GetLanguage(c)
+			status, errCode, message := i18n.NewI18nErrorResponse("900004", lang)
+			c.JSON(status, models.ErrorResponse{
+				Code:      errCode,
+				Message:   message,
+				Timestamp: time.Now().Format(time.RFC3339),
+			})
+		}
+		return
+	}
+
+	lang := middleware.GetLanguage(c)
+	successMessage := i18n.GetErrorMessage("000000", lang)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:    "000000",
+		Message: successMessage,
+		Data:    data,
+	})
+}
+
 // CreateCustomer 创建客户
 // @Summary 创建客户
 // @Description 创建新的客户记录，自动生成客户编码
@@ -293,7 +363,7 @@ func (h *CustomerHandler) UpdateCustomer(c *gin.Context) {
 
 // DeleteCustomer 删除客户
 // @Summary 删除客户
-// @Description 直接删除客户记录，删除前会检查是否有关联的授权码或许可证，如有则提示先删除授权
+// @Description 删除客户并移入回收站，删除前会检查是否有关联的授权码或许可证，如有则提示先删除授权；回收站中的客户可恢复或彻底删除
 // @Tags 客户管理
 // @Accept json
 // @Produce json
@@ -428,3 +498,508 @@ func (h *CustomerHandler) UpdateCustomerStatus(c *gin.Context) {
 		Data:    data,
 	})
 }
+
+// MergeCustomers 合并重复客户
+// @Summary 合并重复客户
+// @Description 将来源客户的授权码、许可证、客户用户、订单、支付、发票及下级客户等迁移到目标客户，并删除来源客户，整个过程在一个事务中完成并记录审计日志；dry_run=true 时仅预览将迁移的数据数量
+// @Tags 客户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param merge_request body models.CustomerMergeRequest true "合并信息"
+// @Success 200 {object} models.APIResponse{data=models.CustomerMergeResponse} "操作成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "客户不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/customers/merge [post]
+func (h *CustomerHandler) MergeCustomers(c *gin.Context) {
+	var req models.CustomerMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		lang := middleware.GetLanguage(c)
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	// 设置语言到Context中
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+
+	data, err := h.customerService.MergeCustomers(ctx, getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, middleware.GetLanguage(c))
+		return
+	}
+
+	lang := middleware.GetLanguage(c)
+	successMessage := i18n.GetErrorMessage("000000", lang)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:    "000000",
+		Message: successMessage,
+		Data:    data,
+	})
+}
+
+// GetCustomerMerges 查询客户合并记录
+// @Summary 查询客户合并记录
+// @Description 分页查询客户合并审计记录（按时间倒序），包含来源客户快照和各类数据迁移数量
+// @Tags 客户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param customer_id query string false "目标客户ID筛选"
+// @Success 200 {object} models.APIResponse{data=models.CustomerMergeListResponse} "查询成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/customer-merges [get]
+func (h *CustomerHandler) GetCustomerMerges(c *gin.Context) {
+	var req models.CustomerMergeListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		lang := middleware.GetLanguage(c)
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	// 设置语言到Context中
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+
+	data, err := h.customerService.GetCustomerMerges(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, middleware.GetLanguage(c))
+		return
+	}
+
+	lang := middleware.GetLanguage(c)
+	successMessage := i18n.GetErrorMessage("000000", lang)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:    "000000",
+		Message: successMessage,
+		Data:    data,
+	})
+}
+
+// GetCustomerTimeline 获取客户360时间线
+// @Summary 获取客户360时间线
+// @Description 按发生时间倒序分页返回客户的创建、授权码、授权变更、许可证激活、订单、支付和发票事件，对象类型、事件和状态均返回多语言显示
+// @Tags 客户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "客户ID"
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param entity_types query []string false "对象类型筛选，可重复传递，默认全部" collectionFormat(multi) Enums(customer, authorization_code, authorization_change, license, order, payment, invoice)
+// @Param start_date query string false "开始日期（YYYY-MM-DD，含当天）"
+// @Param end_date query string false "结束日期（YYYY-MM-DD，含当天）"
+// @Param include_subsidiaries query bool false "是否包含所有层级的下级客户"
+// @Success 200 {object} models.APIResponse{data=models.CustomerTimelineResponse} "查询成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "客户不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/customers/{id}/timeline [get]
+func (h *CustomerHandler) GetCustomerTimeline(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.CustomerTimelineRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.customerService.GetCustomerTimeline(ctx, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetCustomerSeatPool 获取客户席位池
+// @Summary 获取客户席位池
+// @Description 获取客户席位池的总席位、从席位池取用席位的有效授权码数量、已占用席位和剩余席位
+// @Tags 客户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "客户ID"
+// @Success 200 {object} models.APIResponse{data=models.CustomerSeatPool} "查询成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "客户不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/customers/{id}/seat-pool [get]
+func (h *CustomerHandler) GetCustomerSeatPool(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.customerService.GetCustomerSeatPool(ctx, c.Param("id"))
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// SaveCustomerSeatPool 设置客户席位池
+// @Summary 设置客户席位池
+// @Description 创建或更新客户席位池。开启 use_seat_pool 的授权码共享席位池的总席位，激活时在同一事务中同时检查授权码的最大激活次数和席位池剩余席位；总席位不能少于已占用席位
+// @Tags 客户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "客户ID"
+// @Param pool body models.CustomerSeatPoolSaveRequest true "席位池设置"
+// @Success 200 {object} models.APIResponse{data=models.CustomerSeatPool} "设置成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "客户不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/customers/{id}/seat-pool [put]
+func (h *CustomerHandler) SaveCustomerSeatPool(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.CustomerSeatPoolSaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.customerService.SaveCustomerSeatPool(ctx, getUserID(c), c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// DeleteCustomerSeatPool 删除客户席位池
+// @Summary 删除客户席位池
+// @Description 删除客户席位池，并关闭该客户所有授权码的席位池取用，授权码恢复为仅按自身最大激活次数限制
+// @Tags 客户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "客户ID"
+// @Success 200 {object} models.APIResponse "删除成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "客户不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/customers/{id}/seat-pool [delete]
+func (h *CustomerHandler) DeleteCustomerSeatPool(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	if err := h.customerService.DeleteCustomerSeatPool(ctx, c.Param("id")); err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// customerImportMaxFileSize 客户导入文件大小上限
+const customerImportMaxFileSize = 10 << 20
+
+// ImportCustomers 批量导入客户
+// @Summary 批量导入客户
+// @Description 上传 CSV 或 XLSX 文件创建导入任务，表头使用导出文件的列名（customer_code、customer_name、customer_type、contact_person、contact_title、email、phone、address、company_size、customer_level、status、description），每行按创建客户的规则校验；按 customer_code 或 email 匹配已有客户时更新（空单元格保留原值），未匹配时新建。导入在后台执行，通过任务详情查询进度和逐行错误
+// @Tags 客户管理
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV或XLSX文件，最大10MB、10000行"
+// @Param upsert_key formData string false "匹配已有客户的方式，默认customer_code" Enums(customer_code, email)
+// @Success 200 {object} models.APIResponse{data=models.CustomerImportJob} "导入任务已创建"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/customer-imports [post]
+func (h *CustomerHandler) ImportCustomers(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.CustomerImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+	defer file.Close()
+
+	if header.Size > customerImportMaxFileSize {
+		status, errCode, message := i18n.NewI18nErrorResponse("200023", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message,
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, customerImportMaxFileSize))
+	if err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	job, err := h.customerService.ImportCustomers(ctx, getUserID(c), header.Filename, data, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      job,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetImportJob 查询客户导入任务
+// @Summary 查询客户导入任务
+// @Description 查询导入任务的进度、新建/更新/失败数量及逐行错误
+// @Tags 客户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "导入任务ID"
+// @Success 200 {object} models.APIResponse{data=models.CustomerImportJob} "查询成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "导入任务不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/customer-imports/{id} [get]
+func (h *CustomerHandler) GetImportJob(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	job, err := h.customerService.GetImportJob(ctx, c.Param("id"))
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      job,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// ExportImportErrors 下载客户导入错误报告
+// @Summary 下载客户导入错误报告
+// @Description 以 CSV 或 XLSX 格式下载导入任务的逐行错误（行号、客户编码、客户名称、邮箱、错误原因）
+// @Tags 客户管理
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "导入任务ID"
+// @Param format query string false "文件格式，默认csv" Enums(csv, xlsx)
+// @Success 200 {file} file "错误报告文件"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "导入任务不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/customer-imports/{id}/errors [get]
+func (h *CustomerHandler) ExportImportErrors(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	format := c.DefaultQuery("format", models.CustomerFileFormatCSV)
+	if format != models.CustomerFileFormatCSV && format != models.CustomerFileFormatXLSX {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message,
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, fileName, contentType, err := h.customerService.ExportImportErrors(ctx, c.Param("id"), format)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+	c.Header("Cache-Control", "must-revalidate")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ExportCustomers 导出客户
+// @Summary 导出客户
+// @Description 按客户列表的筛选条件导出全部匹配客户（不分页，流式输出），导出文件的列与导入模板一致，修改后可直接重新导入
+// @Tags 客户管理
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param format query string false "文件格式，默认csv" Enums(csv, xlsx)
+// @Param search query string false "搜索关键词(支持客户编码、名称、联系人、邮箱)"
+// @Param customer_type query string false "客户类型筛选" Enums(individual, enterprise, government, education)
+// @Param customer_level query string false "客户等级筛选"
+// @Param status query string false "状态筛选" Enums(active, disabled)
+// @Param parent_id query string false "上级客户筛选"
+// @Param include_subsidiaries query bool false "按上级客户筛选时包含所有层级的下级客户"
+// @Param partner_id query string false "所属经销商筛选"
+// @Param sort query string false "排序字段，默认created_at" Enums(created_at, updated_at, customer_name, customer_code)
+// @Param order query string false "排序方向，默认desc" Enums(asc, desc)
+// @Param tags query []string false "标签筛选，可重复传递，需同时具有全部标签" collectionFormat(multi)
+// @Param field_search query string false "模糊匹配标签和自定义字段值"
+// @Param custom_fields[key] query string false "自定义字段精确筛选，key 为字段标识"
+// @Success 200 {file} file "客户文件"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/customers/export [get]
+func (h *CustomerHandler) ExportCustomers(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.CustomerExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	// 自定义字段筛选：custom_fields[字段标识]=值
+	req.CustomFields = c.QueryMap("custom_fields")
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	fileName, contentType, write, err := h.customerService.ExportCustomers(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", fileName))
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+	// 响应已开始输出，出错时只能中断传输
+	if err := write(c.Writer); err != nil {
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// UpdateCustomerCustomFields 修改客户自定义字段和标签
+// @Summary 修改客户自定义字段和标签
+// @Description 只修改传入的自定义字段，值为空字符串表示清除（必填字段不能清除）；传入 tags 时整体替换标签，传空数组表示清除全部标签
+// @Tags 客户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "客户ID"
+// @Param fields body models.EntityCustomFieldsUpdateRequest true "自定义字段和标签"
+// @Success 200 {object} models.APIResponse{data=models.EntityCustomFields} "修改成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或字段校验失败"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "客户不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/customers/{id}/custom-fields [put]
+func (h *CustomerHandler) UpdateCustomerCustomFields(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.EntityCustomFieldsUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.customerService.UpdateCustomerCustomFields(ctx, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -156,4 +156,49 @@ func (h *DashboardHandler) GetRecentAuthorizations(c *gin.Context) {
 		Message: successMessage,
 		Data:    response,
 	})
-}
\ No newline at end of file
+}
+// GetLeadConversion 获取线索转化统计
+// @Summary 获取线索转化统计
+// @Description 根据时间类型（本周/本月/自定义）统计新增线索、转化为客户的线索数量、转化率及平均转化天数
+// @Tags 仪表盘
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param type query string true "时间类型" Enums(week,month,custom)
+// @Param start_date query string false "开始日期(YYYY-MM-DD格式，当type为custom时必填)"
+// @Param end_date query string false "结束日期(YYYY-MM-DD格式，当type为custom时必填)"
+// @Param timezone query string false "时区(如:Asia/Shanghai,UTC等，默认使用服务器本地时区)"
+// @Success 200 {object} models.APIResponse{data=models.DashboardLeadConversionResponse} "线索转化统计"
+// @Failure 400 {object} models.ErrorResponse "请求参数错误"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/dashboard/lead-conversion [get]
+func (h *DashboardHandler) GetLeadConversion(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.DashboardLeadConversionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	response, err := h.dashboardService.GetLeadConversion(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetErrorMessage("000000", lang),
+		Data:      response,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
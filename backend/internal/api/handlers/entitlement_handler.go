@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EntitlementHandler struct {
+	entitlementService service.EntitlementService
+}
+
+// NewEntitlementHandler 创建授权检查处理器
+func NewEntitlementHandler(entitlementService service.EntitlementService) *EntitlementHandler {
+	return &EntitlementHandler{
+		entitlementService: entitlementService,
+	}
+}
+
+// CheckEntitlement 授权检查
+// @Summary 授权检查
+// @Description 供云部署的服务端调用，按授权码或客户ID返回当前有效的功能、使用限制和剩余配额；指定 feature 时同时判断该功能是否启用。结果按配置的时间缓存，授权码修改、锁定或删除后立即失效。使用 X-API-Key 请求头认证
+// @Tags 授权检查
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param authorization_code query string false "授权码（与 customer_id 二选一）"
+// @Param customer_id query string false "客户ID，汇总该客户全部授权码（与 authorization_code 二选一）"
+// @Param feature query string false "要检查的功能"
+// @Success 200 {object} models.APIResponse{data=models.EntitlementCheckResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "API Key 无效"
+// @Failure 404 {object} models.ErrorResponse "授权码不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/entitlements/check [get]
+func (h *EntitlementHandler) CheckEntitlement(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.EntitlementCheckRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.entitlementService.CheckEntitlement(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
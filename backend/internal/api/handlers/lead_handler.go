@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 
 	"license-manager/internal/api/middleware"
@@ -71,6 +73,9 @@ func (h *LeadHandler) CreateLead(c *gin.Context) {
 // @Param page_size query int false "每页条数，默认10，最大100" minimum(1) maximum(100)
 // @Param search query string false "关键词检索"
 // @Param status query string false "状态筛选"
+// @Param assignee_id query string false "负责人筛选，unassigned 表示未分配"
+// @Param only_duplicates query bool false "仅显示疑似重复的线索"
+// @Param include_merged query bool false "是否包含已合并的线索，默认不包含"
 // @Success 200 {object} models.APIResponse{data=models.LeadListResponse} "成功"
 // @Failure 401 {object} models.ErrorResponse "未认证"
 // @Failure 403 {object} models.ErrorResponse "权限不足"
@@ -196,7 +201,7 @@ func (h *LeadHandler) UpdateLead(c *gin.Context) {
 		return
 	}
 
-	lead, err := h.leadService.UpdateLead(c.Request.Context(), id, &req)
+	lead, err := h.leadService.UpdateLead(c.Request.Context(), id, getUserID(c), &req)
 	if err != nil {
 		handleI18nError(c, err, lang)
 		return
@@ -212,7 +217,7 @@ func (h *LeadHandler) UpdateLead(c *gin.Context) {
 
 // DeleteLead 删除线索
 // @Summary 删除线索
-// @Description 删除指定线索
+// @Description 删除指定线索，删除后进入回收站，可在回收站恢复或彻底删除
 // @Tags 企业线索
 // @Accept json
 // @Produce json
@@ -292,7 +297,7 @@ func (h *LeadHandler) UpdateLeadStatus(c *gin.Context) {
 		Status: status,
 	}
 
-	lead, err := h.leadService.UpdateLead(c.Request.Context(), id, req)
+	lead, err := h.leadService.UpdateLead(c.Request.Context(), id, getUserID(c), req)
 	if err != nil {
 		handleI18nError(c, err, lang)
 		return
@@ -305,3 +310,257 @@ func (h *LeadHandler) UpdateLeadStatus(c *gin.Context) {
 		Timestamp: getCurrentTimestamp(),
 	})
 }
+
+// ConvertLead 线索转化为客户
+// @Summary 线索转化为客户
+// @Description 根据线索信息创建客户（自动生成客户编码），可选为联系人手机号创建客户端用户并签发试用授权码，线索状态更新为已成交并关联到客户。重复调用返回首次转化的结果
+// @Tags 企业线索
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "线索ID"
+// @Param request body models.LeadConvertRequest false "转化选项"
+// @Success 200 {object} models.APIResponse{data=models.LeadConvertResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或联系人手机号已注册"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "线索不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/leads/{id}/convert [post]
+func (h *LeadHandler) ConvertLead(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	// 请求体可省略，此时按默认选项转化
+	var req models.LeadConvertRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.leadService.ConvertLead(ctx, c.Param("id"), getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// AssignLead 分配线索负责人
+// @Summary 分配线索负责人
+// @Description 将线索分配给后台账号，负责人会收到站内通知；assignee_id 为空表示取消分配
+// @Tags 企业线索
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "线索ID"
+// @Param request body models.LeadAssignRequest true "负责人"
+// @Success 200 {object} models.APIResponse{data=models.LeadResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "线索不存在"
+// @Router /api/leads/{id}/assign [put]
+func (h *LeadHandler) AssignLead(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.LeadAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	lead, err := h.leadService.AssignLead(ctx, c.Param("id"), getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      lead.ToResponse(),
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetLeadDuplicates 获取疑似重复线索
+// @Summary 获取疑似重复线索
+// @Description 按标准化的手机号、邮箱、公司名称查找疑似重复的线索，作为合并建议
+// @Tags 企业线索
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "线索ID"
+// @Success 200 {object} models.APIResponse{data=models.LeadDuplicatesResponse} "成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "线索不存在"
+// @Router /api/leads/{id}/duplicates [get]
+func (h *LeadHandler) GetLeadDuplicates(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.leadService.GetLeadDuplicates(ctx, c.Param("id"))
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// MergeLead 合并线索
+// @Summary 合并线索
+// @Description 将当前线索合并到目标线索：目标线索补全缺失的信息，当前线索标记为已失效并记录合并去向。已转化为客户的线索只能作为合并目标
+// @Tags 企业线索
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "被合并的线索ID"
+// @Param request body models.LeadMergeRequest true "合并目标"
+// @Success 200 {object} models.APIResponse{data=models.LeadResponse} "成功，返回合并后的目标线索"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "线索不存在"
+// @Router /api/leads/{id}/merge [post]
+func (h *LeadHandler) MergeLead(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.LeadMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	lead, err := h.leadService.MergeLead(ctx, c.Param("id"), getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      lead.ToResponse(),
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetLeadActivities 获取线索跟进动态
+// @Summary 获取线索跟进动态
+// @Description 按时间倒序获取线索的跟进记录、备注、状态变更、分配、合并及转化记录
+// @Tags 企业线索
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "线索ID"
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Success 200 {object} models.APIResponse{data=models.LeadActivityListResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "线索不存在"
+// @Router /api/leads/{id}/activities [get]
+func (h *LeadHandler) GetLeadActivities(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.LeadActivityListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.leadService.GetLeadActivities(ctx, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// AddLeadActivity 添加线索跟进动态
+// @Summary 添加线索跟进动态
+// @Description 追加一条跟进记录或备注，已有记录不可修改。跟进记录会同步为线索的最近一次跟进记录
+// @Tags 企业线索
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "线索ID"
+// @Param request body models.LeadActivityCreateRequest true "跟进内容"
+// @Success 200 {object} models.APIResponse{data=models.LeadActivity} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 404 {object} models.ErrorResponse "线索不存在"
+// @Router /api/leads/{id}/activities [post]
+func (h *LeadHandler) AddLeadActivity(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.LeadActivityCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.leadService.AddLeadActivity(ctx, c.Param("id"), getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
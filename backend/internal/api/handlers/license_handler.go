@@ -42,10 +42,13 @@ func NewLicenseHandler(licenseService service.LicenseService) *LicenseHandler {
 // @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
 // @Param authorization_code_id query string false "授权码ID筛选"
 // @Param customer_id query string false "客户ID筛选"
-// @Param status query string false "状态筛选" Enums(active, inactive, revoked)
+// @Param status query string false "状态筛选" Enums(active, inactive, revoked, transferred)
 // @Param is_online query bool false "在线状态筛选"
 // @Param sort query string false "排序字段，默认created_at" Enums(created_at, updated_at, activated_at, last_heartbeat)
 // @Param order query string false "排序方向，默认desc" Enums(asc, desc)
+// @Param tags query []string false "标签筛选，可重复传递，需同时具有全部标签" collectionFormat(multi)
+// @Param field_search query string false "模糊匹配标签和自定义字段值"
+// @Param custom_fields[key] query string false "自定义字段精确筛选，key 为字段标识"
 // @Success 200 {object} models.APIResponse{data=models.LicenseListResponse} "查询成功"
 // @Failure 400 {object} models.ErrorResponse "请求参数无效"
 // @Failure 401 {object} models.ErrorResponse "未认证"
@@ -64,6 +67,9 @@ func (h *LicenseHandler) GetLicenseList(c *gin.Context) {
 		return
 	}
 
+	// 自定义字段筛选：custom_fields[字段标识]=值
+	req.CustomFields = c.QueryMap("custom_fields")
+
 	// 设置语言到Context中
 	ctx := middleware.WithLanguage(c.Request.Context(), c)
 
@@ -623,3 +629,193 @@ func (h *LicenseHandler) GetStatsOverview(c *gin.Context) {
 		Data:    data,
 	})
 }
+
+// TransferLicense 转移许可证到新设备
+// @Summary 转移许可证到新设备
+// @Description 停用原设备的许可证并签发停用回执，将席位转移到新的硬件指纹，受授权码转移次数限制
+// @Tags 许可证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "许可证ID"
+// @Param request body models.LicenseTransferRequest true "转移请求"
+// @Success 200 {object} models.APIResponse{data=models.LicenseTransferResponse} "转移成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "许可证不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/licenses/{id}/transfer [post]
+func (h *LicenseHandler) TransferLicense(c *gin.Context) {
+	var req models.LicenseTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		lang := middleware.GetLanguage(c)
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	// 设置语言到Context中
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+
+	data, err := h.licenseService.TransferLicense(ctx, c.Param("id"), "", models.LicenseTransferOperatorAdmin, getUserID(c), &req)
+	if err != nil {
+		lang := middleware.GetLanguage(c)
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	lang := middleware.GetLanguage(c)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// GetLicenseTransfers 查询许可证转移记录
+// @Summary 查询许可证转移记录
+// @Description 分页查询设备转移审计记录，支持按授权码、客户和许可证筛选
+// @Tags 许可证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param authorization_code_id query string false "授权码ID筛选"
+// @Param customer_id query string false "客户ID筛选"
+// @Param license_id query string false "许可证ID筛选（原或新许可证）"
+// @Success 200 {object} models.APIResponse{data=models.LicenseTransferListResponse} "查询成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/license-transfers [get]
+func (h *LicenseHandler) GetLicenseTransfers(c *gin.Context) {
+	var req models.LicenseTransferListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		lang := middleware.GetLanguage(c)
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	// 设置语言到Context中
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+
+	data, err := h.licenseService.GetLicenseTransferList(ctx, &req)
+	if err != nil {
+		lang := middleware.GetLanguage(c)
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	lang := middleware.GetLanguage(c)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// GetActivationViolations 查询使用限制违规事件
+// @Summary 查询使用限制违规事件
+// @Description 分页查询激活和心跳时触发授权码使用限制（IP网段、国家/地区、时间窗口、每日激活数）的事件
+// @Tags 许可证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param authorization_code_id query string false "授权码ID筛选"
+// @Param customer_id query string false "客户ID筛选"
+// @Param violation_type query string false "违规类型筛选" Enums(ip_not_allowed, country_not_allowed, outside_time_window, daily_limit_exceeded)
+// @Param stage query string false "环节筛选" Enums(activation, heartbeat)
+// @Success 200 {object} models.APIResponse{data=models.ActivationViolationListResponse} "查询成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/activation-violations [get]
+func (h *LicenseHandler) GetActivationViolations(c *gin.Context) {
+	var req models.ActivationViolationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		lang := middleware.GetLanguage(c)
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: time.Now().Format(time.RFC3339),
+		})
+		return
+	}
+
+	// 设置语言到Context中
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+
+	data, err := h.licenseService.GetActivationViolationList(ctx, &req)
+	if err != nil {
+		lang := middleware.GetLanguage(c)
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	lang := middleware.GetLanguage(c)
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: time.Now().Format(time.RFC3339),
+	})
+}
+
+// UpdateLicenseCustomFields 修改许可证自定义字段和标签
+// @Summary 修改许可证自定义字段和标签
+// @Description 只修改传入的自定义字段，值为空字符串表示清除（必填字段不能清除）；传入 tags 时整体替换标签，传空数组表示清除全部标签
+// @Tags 许可证管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "许可证ID"
+// @Param fields body models.EntityCustomFieldsUpdateRequest true "自定义字段和标签"
+// @Success 200 {object} models.APIResponse{data=models.EntityCustomFields} "修改成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或字段校验失败"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "许可证不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/licenses/{id}/custom-fields [put]
+func (h *LicenseHandler) UpdateLicenseCustomFields(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.EntityCustomFieldsUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.licenseService.UpdateLicenseCustomFields(ctx, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+// NewNotificationHandler 创建管理端站内通知处理器
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications 获取当前账号的通知列表
+// @Summary 获取当前账号的通知列表
+// @Description 获取当前后台账号的站内通知（如线索分配、跟进提醒），支持按类型和已读状态筛选
+// @Tags 站内通知
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页数量，默认20，最大100"
+// @Param type query string false "通知类型筛选"
+// @Param is_read query bool false "是否已读筛选：true已读，false未读"
+// @Success 200 {object} models.APIResponse{data=models.NotificationListResponse} "获取成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.NotificationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	result, err := h.notificationService.GetUserNotificationList(ctx, getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      result,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// MarkNotificationRead 标记通知为已读
+// @Summary 标记通知为已读
+// @Description 将当前后台账号的指定通知标记为已读
+// @Tags 站内通知
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "通知ID"
+// @Success 200 {object} models.APIResponse "操作成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "通知不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/notifications/{id}/read [put]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	if err := h.notificationService.MarkUserNotificationRead(ctx, getUserID(c), c.Param("id")); err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PartnerHandler struct {
+	partnerService service.PartnerService
+}
+
+// NewPartnerHandler 创建经销商管理处理器
+func NewPartnerHandler(partnerService service.PartnerService) *PartnerHandler {
+	return &PartnerHandler{
+		partnerService: partnerService,
+	}
+}
+
+// CreatePartner 创建经销商账号
+// @Summary 创建经销商账号
+// @Description 创建经销商（partner）角色的后台账号并设置席位额度。经销商只能管理自己名下的客户及其授权码、许可证
+// @Tags 经销商管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param partner body models.PartnerCreateRequest true "经销商信息"
+// @Success 200 {object} models.APIResponse{data=models.PartnerItem} "创建成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或用户名/邮箱已存在"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/partners [post]
+func (h *PartnerHandler) CreatePartner(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.PartnerCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.partnerService.CreatePartner(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetPartnerList 获取经销商列表
+// @Summary 获取经销商列表
+// @Description 分页查询经销商账号及其席位额度、已分配席位和名下客户数量
+// @Tags 经销商管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param search query string false "用户名/姓名/邮箱搜索"
+// @Param status query string false "状态筛选" Enums(active, disabled, locked)
+// @Success 200 {object} models.APIResponse{data=models.PartnerListResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/partners [get]
+func (h *PartnerHandler) GetPartnerList(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.PartnerListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.partnerService.GetPartnerList(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// UpdatePartner 更新经销商账号
+// @Summary 更新经销商账号
+// @Description 更新经销商的联系信息、席位额度或状态。额度下调到低于已分配席位时不影响已有授权码，但经销商无法再新增席位
+// @Tags 经销商管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "经销商账号ID"
+// @Param partner body models.PartnerUpdateRequest true "更新内容"
+// @Success 200 {object} models.APIResponse{data=models.PartnerItem} "更新成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或邮箱已存在"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "权限不足"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/partners/{id} [put]
+func (h *PartnerHandler) UpdatePartner(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.PartnerUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.partnerService.UpdatePartner(ctx, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetCurrentPartner 查看当前经销商的席位额度
+// @Summary 查看当前经销商的席位额度
+// @Description 经销商账号查看自己的席位额度、已分配席位和名下客户数量
+// @Tags 经销商管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=models.PartnerItem} "成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/partner/profile [get]
+func (h *PartnerHandler) GetCurrentPartner(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.partnerService.GetPartner(ctx, getUserID(c))
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetPartnerOrders 查看名下客户的订单
+// @Summary 查看名下客户的订单
+// @Description 经销商账号分页查看自己名下客户的订单及最近一次支付情况
+// @Tags 经销商管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param customer_id query string false "客户ID筛选"
+// @Param status query string false "订单状态筛选"
+// @Success 200 {object} models.APIResponse{data=models.PartnerOrderListResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或非经销商账号"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/partner/orders [get]
+func (h *PartnerHandler) GetPartnerOrders(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.PartnerOrderListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.partnerService.GetPartnerOrders(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeatReclaimHandler struct {
+	seatReclaimService service.SeatReclaimService
+}
+
+func NewSeatReclaimHandler(seatReclaimService service.SeatReclaimService) *SeatReclaimHandler {
+	return &SeatReclaimHandler{
+		seatReclaimService: seatReclaimService,
+	}
+}
+
+// GetSeatReclamations 获取席位回收记录列表
+// @Summary 获取席位回收记录列表
+// @Description 查询闲置席位回收记录，支持按授权码、客户和触发方式筛选
+// @Tags 席位回收
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param authorization_code_id query string false "授权码ID筛选"
+// @Param customer_id query string false "客户ID筛选"
+// @Param trigger_type query string false "触发方式筛选：scheduled/manual"
+// @Success 200 {object} models.APIResponse{data=models.SeatReclamationListResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/seat-reclamations [get]
+func (h *SeatReclaimHandler) GetSeatReclamations(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.SeatReclamationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	result, err := h.seatReclaimService.GetReclamationList(c.Request.Context(), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      result,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// RunSeatReclaim 立即执行一次闲置席位回收
+// @Summary 手动执行闲置席位回收
+// @Description 按授权码配置的回收策略，立即释放超过指定天数无心跳的许可证席位
+// @Tags 席位回收
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.APIResponse{data=models.SeatReclaimRunResponse} "成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/seat-reclamations/run [post]
+func (h *SeatReclaimHandler) RunSeatReclaim(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	result, err := h.seatReclaimService.ReclaimDormantSeats(c.Request.Context(), models.SeatReclaimTriggerManual)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      result,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SoftwareReleaseHandler struct {
+	releaseService service.SoftwareReleaseService
+}
+
+// NewSoftwareReleaseHandler 创建软件版本发布处理器
+func NewSoftwareReleaseHandler(releaseService service.SoftwareReleaseService) *SoftwareReleaseHandler {
+	return &SoftwareReleaseHandler{
+		releaseService: releaseService,
+	}
+}
+
+// CreateRelease 登记产品版本
+// @Summary 登记产品版本
+// @Description 按产品（授权码的软件ID）登记新版本，客户端心跳时会获得其授权可安装的最新版本
+// @Tags 版本发布管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param release body models.SoftwareReleaseCreateRequest true "版本信息"
+// @Success 200 {object} models.APIResponse{data=models.SoftwareRelease} "登记成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效或版本号已存在"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/software-releases [post]
+func (h *SoftwareReleaseHandler) CreateRelease(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.SoftwareReleaseCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.releaseService.CreateRelease(ctx, getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetReleaseList 获取版本列表
+// @Summary 获取版本列表
+// @Description 分页查询已登记的产品版本，按发布时间倒序
+// @Tags 版本发布管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param software_id query string false "产品（软件ID）筛选"
+// @Param channel query string false "发布渠道筛选" Enums(stable, beta)
+// @Success 200 {object} models.APIResponse{data=models.SoftwareReleaseListResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/software-releases [get]
+func (h *SoftwareReleaseHandler) GetReleaseList(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.SoftwareReleaseListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.releaseService.GetReleaseList(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// UpdateRelease 更新版本
+// @Summary 更新版本
+// @Description 更新版本的渠道、发布时间、下载地址、校验值、更新说明或发布状态，产品和版本号不可修改
+// @Tags 版本发布管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "版本记录ID"
+// @Param release body models.SoftwareReleaseUpdateRequest true "更新内容"
+// @Success 200 {object} models.APIResponse{data=models.SoftwareRelease} "更新成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "版本不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/software-releases/{id} [put]
+func (h *SoftwareReleaseHandler) UpdateRelease(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.SoftwareReleaseUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.releaseService.UpdateRelease(ctx, c.Param("id"), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// DeleteRelease 删除版本
+// @Summary 删除版本
+// @Description 删除已登记的版本，删除后不再推送给客户端
+// @Tags 版本发布管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "版本记录ID"
+// @Success 200 {object} models.APIResponse "删除成功"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 404 {object} models.ErrorResponse "版本不存在"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/software-releases/{id} [delete]
+func (h *SoftwareReleaseHandler) DeleteRelease(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	if err := h.releaseService.DeleteRelease(ctx, c.Param("id")); err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	trashService service.TrashService
+}
+
+// NewTrashHandler 创建回收站处理器
+func NewTrashHandler(trashService service.TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// ListTrash 获取回收站列表
+// @Summary 获取回收站列表
+// @Description 分页查询已删除的客户、授权码、套餐、线索和客户端用户，按删除时间倒序；开启自动清理时返回预计彻底删除时间
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param entity_type query string false "对象类型筛选" Enums(customer, authorization_code, package, lead, cu_user)
+// @Param search query string false "按名称搜索"
+// @Param page query int false "页码，默认1"
+// @Param page_size query int false "每页条数，默认20，最大100"
+// @Success 200 {object} models.APIResponse{data=models.TrashListResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "需要管理员权限"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.TrashListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.trashService.ListTrash(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// RestoreTrashItem 恢复回收站中的对象
+// @Summary 恢复回收站中的对象
+// @Description 恢复已删除的对象；授权码和客户端用户要求所属客户未删除，下级客户要求上级客户未删除，客户端用户要求手机号未被其他用户使用
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param entity_type path string true "对象类型" Enums(customer, authorization_code, package, lead, cu_user)
+// @Param id path string true "对象ID"
+// @Success 200 {object} models.APIResponse "恢复成功"
+// @Failure 400 {object} models.ErrorResponse "所属客户已删除或手机号已被使用"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "需要管理员权限"
+// @Failure 404 {object} models.ErrorResponse "对象不在回收站中"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/trash/{entity_type}/{id}/restore [post]
+func (h *TrashHandler) RestoreTrashItem(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	if err := h.trashService.Restore(ctx, c.Param("entity_type"), c.Param("id")); err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// PurgeTrashItem 彻底删除回收站中的对象
+// @Summary 彻底删除回收站中的对象
+// @Description 从数据库中彻底删除回收站中的对象，不可恢复；仍被其他数据（包括回收站中的数据）引用时不允许删除
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param entity_type path string true "对象类型" Enums(customer, authorization_code, package, lead, cu_user)
+// @Param id path string true "对象ID"
+// @Success 200 {object} models.APIResponse "删除成功"
+// @Failure 400 {object} models.ErrorResponse "对象仍被引用"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 403 {object} models.ErrorResponse "需要管理员权限"
+// @Failure 404 {object} models.ErrorResponse "对象不在回收站中"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/admin/trash/{entity_type}/{id} [delete]
+func (h *TrashHandler) PurgeTrashItem(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	if err := h.trashService.Purge(ctx, c.Param("entity_type"), c.Param("id")); err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+
+	"license-manager/internal/api/middleware"
+	"license-manager/internal/models"
+	"license-manager/internal/service"
+	"license-manager/pkg/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VoucherHandler struct {
+	voucherService service.VoucherService
+}
+
+// NewVoucherHandler 创建兑换券处理器
+func NewVoucherHandler(voucherService service.VoucherService) *VoucherHandler {
+	return &VoucherHandler{
+		voucherService: voucherService,
+	}
+}
+
+// CreateVouchers 批量生成兑换券
+// @Summary 批量生成兑换券
+// @Description 生成未绑定客户的兑换券，每张兑换券携带套餐和席位数，客户兑换后在其名下生成授权码
+// @Tags 兑换券管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param voucher body models.VoucherCreateRequest true "兑换券信息"
+// @Success 200 {object} models.APIResponse{data=models.VoucherCreateResponse} "生成成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/vouchers [post]
+func (h *VoucherHandler) CreateVouchers(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.VoucherCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.voucherService.CreateVouchers(ctx, getUserID(c), &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetVoucherList 获取兑换券列表
+// @Summary 获取兑换券列表
+// @Description 分页查询兑换券，支持按兑换码、标签、套餐和状态筛选
+// @Tags 兑换券管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param code query string false "兑换码精确查询"
+// @Param label query string false "标签模糊搜索"
+// @Param package_id query string false "套餐ID筛选"
+// @Param status query string false "状态筛选" Enums(unused, redeemed, expired)
+// @Success 200 {object} models.APIResponse{data=models.VoucherListResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/vouchers [get]
+func (h *VoucherHandler) GetVoucherList(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.VoucherListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.voucherService.GetVoucherList(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
+
+// GetRedemptionReport 获取兑换报表
+// @Summary 获取兑换券兑换报表
+// @Description 统计兑换券使用情况，并分页列出兑换记录（兑换客户、兑换用户、生成的授权码和订单）
+// @Tags 兑换券管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码，默认1" minimum(1)
+// @Param page_size query int false "每页条数，默认20，最大100" minimum(1) maximum(100)
+// @Param label query string false "标签模糊搜索"
+// @Param package_id query string false "套餐ID筛选"
+// @Param customer_id query string false "兑换客户ID筛选"
+// @Param start_date query string false "兑换开始日期（YYYY-MM-DD）"
+// @Param end_date query string false "兑换结束日期（YYYY-MM-DD）"
+// @Success 200 {object} models.APIResponse{data=models.VoucherRedemptionReportResponse} "成功"
+// @Failure 400 {object} models.ErrorResponse "请求参数无效"
+// @Failure 401 {object} models.ErrorResponse "未认证"
+// @Failure 500 {object} models.ErrorResponse "服务器内部错误"
+// @Router /api/v1/voucher-redemptions [get]
+func (h *VoucherHandler) GetRedemptionReport(c *gin.Context) {
+	lang := middleware.GetLanguage(c)
+
+	var req models.VoucherRedemptionReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		status, errCode, message := i18n.NewI18nErrorResponse("900001", lang)
+		c.JSON(status, models.ErrorResponse{
+			Code:      errCode,
+			Message:   message + ": " + err.Error(),
+			Timestamp: getCurrentTimestamp(),
+		})
+		return
+	}
+
+	ctx := middleware.WithLanguage(c.Request.Context(), c)
+	data, err := h.voucherService.GetRedemptionReport(ctx, &req)
+	if err != nil {
+		handleI18nError(c, err, lang)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Code:      "000000",
+		Message:   i18n.GetI18nErrorMessage("000000", lang),
+		Data:      data,
+		Timestamp: getCurrentTimestamp(),
+	})
+}
@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
+	"license-manager/internal/models"
+	pkgcontext "license-manager/pkg/context"
 	"license-manager/pkg/utils"
 
 	"github.com/gin-gonic/gin"
@@ -14,6 +17,7 @@ const (
 	AuthorizationTypeBearer = "bearer"
 	AuthorizationPayloadKey = "authorization_payload"
 	CuUserPayloadKey        = "cu_user"
+	APIKeyHeaderKey         = "X-API-Key"
 )
 
 // AuthMiddleware 认证中间件（支持自动延长）
@@ -97,6 +101,7 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
 		c.Set(AuthorizationPayloadKey, claims)
+		setPartnerScope(c, claims)
 
 		c.Next()
 	}
@@ -131,6 +136,7 @@ func OptionalAuthMiddleware() gin.HandlerFunc {
 			c.Set("username", claims.Username)
 			c.Set("role", claims.Role)
 			c.Set(AuthorizationPayloadKey, claims)
+			setPartnerScope(c, claims)
 		}
 
 		c.Next()
@@ -152,7 +158,7 @@ func AdminOnlyMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		if role != "administrator" {
+		if role != models.UserRoleAdministrator {
 			c.JSON(http.StatusForbidden, gin.H{
 				"code":      http.StatusForbidden,
 				"error":     "AUTH_004",
@@ -167,6 +173,33 @@ func AdminOnlyMiddleware() gin.HandlerFunc {
 	}
 }
 
+// StaffOnlyMiddleware 仅内部员工访问中间件，经销商账号无权访问未按经销商限定数据范围的接口
+func StaffOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role, _ := c.Get("role"); role == models.UserRolePartner {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":      http.StatusForbidden,
+				"error":     "AUTH_004",
+				"message":   "权限不足",
+				"timestamp": getCurrentTimestamp(),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setPartnerScope 经销商账号记录经销商ID，服务层据此将数据访问限定在其名下客户范围内
+func setPartnerScope(c *gin.Context, claims *utils.Claims) {
+	if claims.Role != models.UserRolePartner {
+		return
+	}
+	c.Set("partner_id", claims.UserID)
+	c.Request = c.Request.WithContext(pkgcontext.WithPartnerID(c.Request.Context(), claims.UserID))
+}
+
 // CustomerAuth 客户用户认证中间件（支持自动延长）
 func CustomerAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -250,6 +283,39 @@ func CustomerAuth() gin.HandlerFunc {
 	}
 }
 
+// APIKeyMiddleware 服务端API Key认证中间件（供SaaS后端等服务间调用）
+// 未配置任何API Key时拒绝所有请求
+func APIKeyMiddleware(apiKeys []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := strings.TrimSpace(c.GetHeader(APIKeyHeaderKey))
+		if apiKey == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"code":      http.StatusUnauthorized,
+				"error":     "AUTH_001",
+				"message":   "缺少API Key",
+				"timestamp": getCurrentTimestamp(),
+			})
+			c.Abort()
+			return
+		}
+
+		for _, key := range apiKeys {
+			if key != "" && subtle.ConstantTimeCompare([]byte(apiKey), []byte(key)) == 1 {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code":      http.StatusUnauthorized,
+			"error":     "AUTH_002",
+			"message":   "API Key无效",
+			"timestamp": getCurrentTimestamp(),
+		})
+		c.Abort()
+	}
+}
+
 // getCurrentTimestamp 获取当前时间戳
 func getCurrentTimestamp() string {
 	return "2024-07-30T12:00:00Z" // 简化实现，实际应该使用time.Now().Format(time.RFC3339)
@@ -1,25 +1,34 @@
 package routes
 
 import (
+	"context"
 	"fmt"
 	_ "license-manager/docs/swagger" // swagger docs
 	"license-manager/internal/api/handlers"
 	"license-manager/internal/api/middleware"
 	"license-manager/internal/config"
 	"license-manager/internal/database"
+	"license-manager/internal/models"
 	"license-manager/internal/repository"
+	"license-manager/internal/scheduler"
 	"license-manager/internal/service"
 	"license-manager/pkg/cache"
 	"license-manager/pkg/logger"
 	"license-manager/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRouter() *gin.Engine {
-	router := gin.New()
+// SetupRouter 初始化路由并启动后台任务，返回的 stop 在服务退出时停止定时任务和后台任务
+func SetupRouter() (*gin.Engine, func()) {
+	cfg := config.GetConfig()
+	router, err := newEngine(cfg.Server.TrustedProxies)
+	if err != nil {
+		logger.GetLogger().Fatalf("Failed to set trusted proxies: %v", err)
+	}
 
 	// 全局中间件
 	router.Use(middleware.CustomLoggerMiddleware())
@@ -27,7 +36,6 @@ func SetupRouter() *gin.Engine {
 	router.Use(middleware.CORSMiddleware())
 
 	// 多语言中间件
-	cfg := config.GetConfig()
 	if cfg.I18n.Enable {
 		i18nConfig := &middleware.I18nConfig{
 			Enable:       cfg.I18n.Enable,
@@ -51,6 +59,21 @@ func SetupRouter() *gin.Engine {
 	adminInvoiceRepo := repository.NewAdminInvoiceRepository(db)
 	packageRepo := repository.NewPackageRepository(db)
 	leadRepo := repository.NewLeadRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	seatReclaimRepo := repository.NewSeatReclaimRepository(db)
+	licenseTransferRepo := repository.NewLicenseTransferRepository(db)
+	partnerRepo := repository.NewPartnerRepository(db)
+	authCodeBatchRepo := repository.NewAuthorizationCodeBatchRepository(db)
+	voucherRepo := repository.NewVoucherRepository(db)
+	authCodeShareRepo := repository.NewAuthorizationCodeShareRepository(db)
+	authCodeScheduleRepo := repository.NewAuthorizationCodeScheduleRepository(db)
+	activationViolationRepo := repository.NewActivationViolationRepository(db)
+	softwareReleaseRepo := repository.NewSoftwareReleaseRepository(db)
+	licenseCommandRepo := repository.NewLicenseCommandRepository(db)
+	customFieldRepo := repository.NewCustomFieldRepository(db)
+	seatPoolRepo := repository.NewSeatPoolRepository(db)
+	trashRepo := repository.NewTrashRepository(db)
+	entitlementRepo := repository.NewEntitlementRepository(db)
 
 	// 获取logger实例
 	log := logger.GetLogger()
@@ -89,15 +112,16 @@ func SetupRouter() *gin.Engine {
 	// 初始化服务层
 	authService := service.NewAuthService(userRepo)
 	systemService := service.NewSystemService()
-	customerService := service.NewCustomerService(customerRepo)
+	entitlementService := service.NewEntitlementService(entitlementRepo, cacheInstance, cfg.License.EntitlementCacheTTL, log)
+	customerService := service.NewCustomerService(customerRepo, partnerRepo, customFieldRepo, seatPoolRepo, entitlementService, log)
 	cuUserService := service.NewCuUserService(cuUserRepo, customerRepo, smsService, db)
-	authCodeService := service.NewAuthorizationCodeService(authCodeRepo, customerRepo, cuUserRepo, licenseRepo)
+	authCodeService := service.NewAuthorizationCodeService(authCodeRepo, customerRepo, cuUserRepo, licenseRepo, authCodeShareRepo, partnerRepo, customFieldRepo, seatPoolRepo, entitlementService)
 	packageService := service.NewPackageService(packageRepo, db)
 	cuOrderService := service.NewCuOrderService(cuOrderRepo, cuUserRepo, authCodeRepo, packageRepo, paymentRepo, cuInvoiceRepo, db)
 	cuDeviceService := service.NewCuDeviceService(licenseRepo)
 	paymentService := service.NewPaymentService(paymentRepo, cuOrderRepo, convertPaymentConfig(cfg.Payment), db)
 	enumService := service.NewEnumService()
-	licenseService := service.NewLicenseService(licenseRepo, db, log)
+	licenseService := service.NewLicenseService(licenseRepo, licenseTransferRepo, activationViolationRepo, softwareReleaseRepo, licenseCommandRepo, customerRepo, partnerRepo, customFieldRepo, seatPoolRepo, loadGeoIPDatabase(cfg.License.GeoIPDatabasePath, log), db, log)
 	dashboardService := service.NewDashboardService(dashboardRepo)
 
 	// 初始化处理器层
@@ -107,7 +131,7 @@ func SetupRouter() *gin.Engine {
 	cuAuthHandler := handlers.NewCuAuthHandler(cuUserService)
 	cuProfileHandler := handlers.NewCuProfileHandler(cuUserService)
 	cuOrderHandler := handlers.NewCuOrderHandler(cuOrderService, paymentService, packageService)
-	cuDeviceHandler := handlers.NewCuDeviceHandler(cuDeviceService)
+	cuDeviceHandler := handlers.NewCuDeviceHandler(cuDeviceService, licenseService)
 	paymentHandler := handlers.NewPaymentHandler(paymentService)
 	cuAuthorizationHandler := handlers.NewCuAuthorizationHandler(authCodeService)
 	cuInvoiceService := service.NewCuInvoiceService(cuInvoiceRepo, cuOrderRepo, db)
@@ -119,8 +143,64 @@ func SetupRouter() *gin.Engine {
 	adminInvoiceService := service.NewAdminInvoiceService(adminInvoiceRepo, cuOrderRepo, userRepo, cuUserRepo, db)
 	adminInvoiceHandler := handlers.NewAdminInvoiceHandler(adminInvoiceService)
 	packageHandler := handlers.NewPackageHandler(packageService)
-	leadService := service.NewLeadService(leadRepo, db)
+	notificationService := service.NewNotificationService(notificationRepo)
+	cuNotificationHandler := handlers.NewCuNotificationHandler(notificationService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	leadService := service.NewLeadService(leadRepo, customerRepo, cuUserRepo, authCodeRepo, partnerRepo, notificationService, db, log)
 	leadHandler := handlers.NewLeadHandler(leadService)
+	seatReclaimService := service.NewSeatReclaimService(seatReclaimRepo, log)
+	seatReclaimHandler := handlers.NewSeatReclaimHandler(seatReclaimService)
+	authCodeBatchService := service.NewAuthorizationCodeBatchService(authCodeBatchRepo, customerRepo, customFieldRepo, entitlementService, log)
+	authCodeBatchHandler := handlers.NewAuthorizationCodeBatchHandler(authCodeBatchService)
+	voucherService := service.NewVoucherService(voucherRepo, packageRepo, log)
+	voucherHandler := handlers.NewVoucherHandler(voucherService)
+	cuVoucherHandler := handlers.NewCuVoucherHandler(voucherService)
+	authCodeScheduleService := service.NewAuthorizationCodeScheduleService(authCodeScheduleRepo, authCodeRepo, cuOrderRepo, entitlementService, log)
+	authCodeScheduleHandler := handlers.NewAuthorizationCodeScheduleHandler(authCodeScheduleService)
+	softwareReleaseService := service.NewSoftwareReleaseService(softwareReleaseRepo, log)
+	softwareReleaseHandler := handlers.NewSoftwareReleaseHandler(softwareReleaseService)
+	licenseCommandService := service.NewLicenseCommandService(licenseCommandRepo, licenseRepo, log)
+	licenseCommandHandler := handlers.NewLicenseCommandHandler(licenseCommandService)
+	cuLicenseCommandHandler := handlers.NewCuLicenseCommandHandler(licenseCommandService)
+	entitlementHandler := handlers.NewEntitlementHandler(entitlementService)
+	partnerService := service.NewPartnerService(partnerRepo)
+	partnerHandler := handlers.NewPartnerHandler(partnerService)
+	customFieldService := service.NewCustomFieldService(customFieldRepo)
+	customFieldHandler := handlers.NewCustomFieldHandler(customFieldService)
+	trashService := service.NewTrashService(trashRepo, entitlementService, cfg.Scheduler.TrashRetention, log)
+	trashHandler := handlers.NewTrashHandler(trashService)
+
+	// 服务退出时按启动的逆序停止
+	var stoppers []func()
+
+	// 续跑服务重启前未完成的授权码批次
+	go authCodeBatchService.ResumeUnfinishedBatches()
+	stoppers = append(stoppers, authCodeBatchService.Stop)
+	go customerService.ResumeUnfinishedImports()
+	stoppers = append(stoppers, customerService.Stop)
+
+	// 启动定时任务
+	if cfg.Scheduler.Enabled {
+		jobScheduler := scheduler.New(log)
+		jobScheduler.Register("seat_reclaim", cfg.Scheduler.SeatReclaimInterval, func(ctx context.Context) error {
+			_, err := seatReclaimService.ReclaimDormantSeats(ctx, models.SeatReclaimTriggerScheduled)
+			return err
+		})
+		jobScheduler.Register("authorization_code_scheduled_actions", cfg.Scheduler.ScheduledActionInterval, func(ctx context.Context) error {
+			_, err := authCodeScheduleService.RunDueActions(ctx)
+			return err
+		})
+		jobScheduler.Register("lead_reminders", cfg.Scheduler.LeadReminderInterval, func(ctx context.Context) error {
+			_, err := leadService.SendLeadReminders(ctx, cfg.Scheduler.LeadPendingTimeout)
+			return err
+		})
+		jobScheduler.Register("trash_purge", cfg.Scheduler.TrashPurgeInterval, func(ctx context.Context) error {
+			_, err := trashService.PurgeExpired(ctx)
+			return err
+		})
+		jobScheduler.Start()
+		stoppers = append(stoppers, jobScheduler.Stop)
+	}
 
 	// 健康检测接口（无需认证）
 	router.GET("/health", systemHandler.HealthCheck)
@@ -156,7 +236,7 @@ func SetupRouter() *gin.Engine {
 			public.POST("/leads", leadHandler.CreateLead)
 		}
 
-		// 需要认证的接口
+		// 需要认证的接口（经销商账号可访问，服务层按经销商限定数据范围）
 		auth := api.Group("")
 		auth.Use(middleware.AuthMiddleware())
 		{
@@ -166,11 +246,14 @@ func SetupRouter() *gin.Engine {
 
 			// 客户管理
 			auth.GET("/customers", customerHandler.GetCustomerList)
+			auth.GET("/customers/export", customerHandler.ExportCustomers)
 			auth.GET("/customers/:id", customerHandler.GetCustomer)
+			auth.GET("/customers/:id/hierarchy", customerHandler.GetCustomerHierarchy)
 			auth.POST("/customers", customerHandler.CreateCustomer)
 			auth.PUT("/customers/:id", customerHandler.UpdateCustomer)
 			auth.DELETE("/customers/:id", customerHandler.DeleteCustomer)
 			auth.PATCH("/customers/:id/status", customerHandler.UpdateCustomerStatus)
+			auth.PUT("/customers/:id/custom-fields", customerHandler.UpdateCustomerCustomFields)
 
 			// 枚举管理
 			auth.GET("/enums", enumHandler.GetAllEnums)
@@ -185,44 +268,124 @@ func SetupRouter() *gin.Engine {
 			auth.PUT("/v1/authorization-codes/:id/lock", authCodeHandler.LockUnlockAuthorizationCode)
 			auth.DELETE("/v1/authorization-codes/:id", authCodeHandler.DeleteAuthorizationCode)
 			auth.GET("/v1/authorization-codes/:id/changes", authCodeHandler.GetAuthorizationChangeList)
+			auth.PUT("/v1/authorization-codes/:id/custom-fields", authCodeHandler.UpdateAuthorizationCodeCustomFields)
 
 			// 许可证管理
 			auth.GET("/v1/licenses", licenseHandler.GetLicenseList)
 			auth.GET("/v1/licenses/:id", licenseHandler.GetLicense)
-			auth.POST("/v1/licenses", licenseHandler.CreateLicense)
 			auth.PUT("/v1/licenses/:id/revoke", licenseHandler.RevokeLicense)
 			auth.GET("/v1/licenses/:id/download", licenseHandler.DownloadLicenseFile)
+			auth.PUT("/v1/licenses/:id/custom-fields", licenseHandler.UpdateLicenseCustomFields)
+
+			// 自定义字段定义（用于渲染表单和列表筛选）
+			auth.GET("/v1/custom-fields", customFieldHandler.GetDefinitions)
+
+			// 经销商额度
+			auth.GET("/v1/partner/profile", partnerHandler.GetCurrentPartner)
+			auth.GET("/v1/partner/orders", partnerHandler.GetPartnerOrders)
+
+			// 站内通知（当前后台账号）
+			auth.GET("/v1/notifications", notificationHandler.GetNotifications)
+			auth.PUT("/v1/notifications/:id/read", notificationHandler.MarkNotificationRead)
+		}
+
+		// 需要认证的内部员工接口（经销商账号无权访问）
+		staff := api.Group("")
+		staff.Use(middleware.AuthMiddleware(), middleware.StaffOnlyMiddleware())
+		{
+			// 授权码拆分、合并、回滚及计划操作
+			staff.POST("/v1/authorization-codes/merge", authCodeHandler.MergeAuthorizationCodes)
+			staff.POST("/v1/authorization-codes/:id/split", authCodeHandler.SplitAuthorizationCode)
+			staff.POST("/v1/authorization-codes/:id/revert", authCodeHandler.RevertAuthorizationCode)
+			staff.GET("/v1/authorization-codes/:id/changes/diff", authCodeHandler.GetAuthorizationChangeDiff)
+			staff.GET("/v1/authorization-codes/:id/scheduled-actions", authCodeScheduleHandler.GetScheduledActions)
+			staff.POST("/v1/authorization-codes/:id/scheduled-actions", authCodeScheduleHandler.CreateScheduledAction)
+			staff.POST("/v1/authorization-codes/:id/scheduled-actions/:action_id/cancel", authCodeScheduleHandler.CancelScheduledAction)
+
+			// 授权码批次（批量生成）
+			staff.GET("/v1/authorization-code-batches", authCodeBatchHandler.GetBatchList)
+			staff.POST("/v1/authorization-code-batches", authCodeBatchHandler.CreateBatch)
+			staff.GET("/v1/authorization-code-batches/:id", authCodeBatchHandler.GetBatch)
+			staff.GET("/v1/authorization-code-batches/:id/export", authCodeBatchHandler.ExportBatch)
+			staff.PUT("/v1/authorization-code-batches/:id/lock", authCodeBatchHandler.LockBatch)
+			staff.POST("/v1/authorization-code-batches/:id/revoke", authCodeBatchHandler.RevokeBatch)
+
+			// 兑换券管理
+			staff.GET("/v1/vouchers", voucherHandler.GetVoucherList)
+			staff.POST("/v1/vouchers", voucherHandler.CreateVouchers)
+			staff.GET("/v1/voucher-redemptions", voucherHandler.GetRedemptionReport)
+
+			// 版本发布管理
+			staff.GET("/v1/software-releases", softwareReleaseHandler.GetReleaseList)
+			staff.POST("/v1/software-releases", softwareReleaseHandler.CreateRelease)
+			staff.PUT("/v1/software-releases/:id", softwareReleaseHandler.UpdateRelease)
+			staff.DELETE("/v1/software-releases/:id", softwareReleaseHandler.DeleteRelease)
+
+			// 许可证创建、转移及远程命令
+			staff.POST("/v1/licenses", licenseHandler.CreateLicense)
+			staff.POST("/v1/licenses/:id/transfer", licenseHandler.TransferLicense)
+			staff.GET("/v1/licenses/:id/commands", licenseCommandHandler.GetCommands)
+			staff.POST("/v1/licenses/:id/commands", licenseCommandHandler.CreateCommand)
+			staff.POST("/v1/licenses/:id/commands/:command_id/cancel", licenseCommandHandler.CancelCommand)
+			staff.GET("/v1/license-transfers", licenseHandler.GetLicenseTransfers)
+			staff.GET("/v1/activation-violations", licenseHandler.GetActivationViolations)
 
 			// 统计分析
-			auth.GET("/v1/stats/overview", licenseHandler.GetStatsOverview)
+			staff.GET("/v1/stats/overview", licenseHandler.GetStatsOverview)
 
 			// 仪表盘接口
-			auth.GET("/v1/dashboard/authorization-trend", dashboardHandler.GetAuthorizationTrend)
-			auth.GET("/v1/dashboard/recent-authorizations", dashboardHandler.GetRecentAuthorizations)
+			staff.GET("/v1/dashboard/authorization-trend", dashboardHandler.GetAuthorizationTrend)
+			staff.GET("/v1/dashboard/recent-authorizations", dashboardHandler.GetRecentAuthorizations)
+			staff.GET("/v1/dashboard/lead-conversion", dashboardHandler.GetLeadConversion)
 
 			// 发票管理（管理员）
-			auth.GET("/v1/invoices", adminInvoiceHandler.GetAdminInvoices)
-			auth.GET("/v1/invoices/:id", adminInvoiceHandler.GetAdminInvoiceDetail)
-			auth.GET("/v1/invoices/summary", adminInvoiceHandler.GetAdminInvoiceSummary)
-			auth.POST("/v1/invoices/:id/reject", adminInvoiceHandler.RejectInvoice)
-			auth.POST("/v1/invoices/:id/issue", adminInvoiceHandler.IssueInvoice)
-			auth.POST("/v1/invoices/upload", adminInvoiceHandler.UploadInvoiceFile)
+			staff.GET("/v1/invoices", adminInvoiceHandler.GetAdminInvoices)
+			staff.GET("/v1/invoices/:id", adminInvoiceHandler.GetAdminInvoiceDetail)
+			staff.GET("/v1/invoices/summary", adminInvoiceHandler.GetAdminInvoiceSummary)
+			staff.POST("/v1/invoices/:id/reject", adminInvoiceHandler.RejectInvoice)
+			staff.POST("/v1/invoices/:id/issue", adminInvoiceHandler.IssueInvoice)
+			staff.POST("/v1/invoices/upload", adminInvoiceHandler.UploadInvoiceFile)
 
 			// 套餐管理（管理员）
-			auth.GET("/packages", packageHandler.GetPackages)
-			auth.GET("/packages/:id", packageHandler.GetPackageDetail)
-			auth.POST("/packages", packageHandler.CreatePackage)
-			auth.PUT("/packages/:id", packageHandler.UpdatePackage)
-			auth.DELETE("/packages/:id", packageHandler.DeletePackage)
-			auth.PUT("/packages/:id/status", packageHandler.UpdatePackageStatus)
+			staff.GET("/packages", packageHandler.GetPackages)
+			staff.GET("/packages/:id", packageHandler.GetPackageDetail)
+			staff.POST("/packages", packageHandler.CreatePackage)
+			staff.PUT("/packages/:id", packageHandler.UpdatePackage)
+			staff.DELETE("/packages/:id", packageHandler.DeletePackage)
+			staff.PUT("/packages/:id/status", packageHandler.UpdatePackageStatus)
+
+			// 客户批量导入
+			staff.POST("/v1/customer-imports", customerHandler.ImportCustomers)
+			staff.GET("/v1/customer-imports/:id", customerHandler.GetImportJob)
+			staff.GET("/v1/customer-imports/:id/errors", customerHandler.ExportImportErrors)
 
 			// 线索管理（管理员）
-			auth.GET("/leads", leadHandler.GetLeads)
-			auth.GET("/leads/summary", leadHandler.GetLeadSummary)
-			auth.GET("/leads/:id", leadHandler.GetLead)
-			auth.PUT("/leads/:id", leadHandler.UpdateLead)
-			auth.PUT("/leads/:id/status", leadHandler.UpdateLeadStatus)
-			auth.DELETE("/leads/:id", leadHandler.DeleteLead)
+			staff.GET("/leads", leadHandler.GetLeads)
+			staff.GET("/leads/summary", leadHandler.GetLeadSummary)
+			staff.GET("/leads/:id", leadHandler.GetLead)
+			staff.PUT("/leads/:id", leadHandler.UpdateLead)
+			staff.PUT("/leads/:id/status", leadHandler.UpdateLeadStatus)
+			staff.POST("/leads/:id/convert", leadHandler.ConvertLead)
+			staff.PUT("/leads/:id/assign", leadHandler.AssignLead)
+			staff.GET("/leads/:id/duplicates", leadHandler.GetLeadDuplicates)
+			staff.POST("/leads/:id/merge", leadHandler.MergeLead)
+			staff.GET("/leads/:id/activities", leadHandler.GetLeadActivities)
+			staff.POST("/leads/:id/activities", leadHandler.AddLeadActivity)
+			staff.DELETE("/leads/:id", leadHandler.DeleteLead)
+
+			// 闲置席位回收
+			staff.GET("/v1/seat-reclamations", seatReclaimHandler.GetSeatReclamations)
+			staff.POST("/v1/seat-reclamations/run", seatReclaimHandler.RunSeatReclaim)
+
+			// 标签输入提示
+			staff.GET("/v1/tags", customFieldHandler.GetTags)
+		}
+
+		// 授权检查接口（云部署的服务端通过 API Key 调用）
+		entitlement := api.Group("")
+		entitlement.Use(middleware.APIKeyMiddleware(cfg.License.EntitlementAPIKeys))
+		{
+			entitlement.GET("/v1/entitlements/check", entitlementHandler.CheckEntitlement)
 		}
 
 		// 管理员接口
@@ -230,6 +393,29 @@ func SetupRouter() *gin.Engine {
 		admin.Use(middleware.AuthMiddleware(), middleware.AdminOnlyMiddleware())
 		{
 			admin.GET("/system/info", systemHandler.GetSystemInfo)
+
+			// 经销商管理
+			admin.GET("/partners", partnerHandler.GetPartnerList)
+			admin.POST("/partners", partnerHandler.CreatePartner)
+			admin.PUT("/partners/:id", partnerHandler.UpdatePartner)
+
+			// 重复客户合并
+			admin.POST("/customers/merge", customerHandler.MergeCustomers)
+			admin.GET("/customer-merges", customerHandler.GetCustomerMerges)
+			admin.GET("/customers/:id/timeline", customerHandler.GetCustomerTimeline)
+			admin.GET("/customers/:id/seat-pool", customerHandler.GetCustomerSeatPool)
+			admin.PUT("/customers/:id/seat-pool", customerHandler.SaveCustomerSeatPool)
+			admin.DELETE("/customers/:id/seat-pool", customerHandler.DeleteCustomerSeatPool)
+
+			// 自定义字段定义
+			admin.POST("/custom-fields", customFieldHandler.CreateDefinition)
+			admin.PUT("/custom-fields/:id", customFieldHandler.UpdateDefinition)
+			admin.DELETE("/custom-fields/:id", customFieldHandler.DeleteDefinition)
+
+			// 回收站
+			admin.GET("/trash", trashHandler.ListTrash)
+			admin.POST("/trash/:entity_type/:id/restore", trashHandler.RestoreTrashItem)
+			admin.DELETE("/trash/:entity_type/:id", trashHandler.PurgeTrashItem)
 		}
 	}
 
@@ -279,10 +465,21 @@ func SetupRouter() *gin.Engine {
 			cuAuth.GET("/authorization-codes", cuAuthorizationHandler.GetCuAuthorizationCodes)
 			cuAuth.GET("/authorization-codes/summary", cuAuthorizationHandler.GetCuAuthorizationCodeSummary)
 
+			// 授权码分享记录
+			cuAuth.GET("/authorization-code-shares", cuAuthorizationHandler.GetShareList)
+			cuAuth.POST("/authorization-code-shares/:id/accept", cuAuthorizationHandler.AcceptShare)
+			cuAuth.POST("/authorization-code-shares/:id/decline", cuAuthorizationHandler.DeclineShare)
+			cuAuth.POST("/authorization-code-shares/:id/cancel", cuAuthorizationHandler.CancelShare)
+			cuAuth.POST("/authorization-code-shares/:id/reclaim", cuAuthorizationHandler.ReclaimShare)
+
 			// 设备管理
 			cuAuth.GET("/devices", cuDeviceHandler.GetDevices)
 			cuAuth.GET("/devices/summary", cuDeviceHandler.GetDeviceSummary)
 			cuAuth.DELETE("/devices/:id", cuDeviceHandler.UnbindDevice)
+			cuAuth.POST("/devices/:id/transfer", cuDeviceHandler.TransferDevice)
+			cuAuth.GET("/devices/:id/commands", cuLicenseCommandHandler.GetCommands)
+			cuAuth.POST("/devices/:id/commands", cuLicenseCommandHandler.CreateCommand)
+			cuAuth.POST("/devices/:id/commands/:command_id/cancel", cuLicenseCommandHandler.CancelCommand)
 
 			// 发票管理
 			cuAuth.POST("/invoices", cuInvoiceHandler.CreateInvoice)
@@ -291,10 +488,21 @@ func SetupRouter() *gin.Engine {
 			cuAuth.PUT("/invoices/:id", cuInvoiceHandler.UpdateInvoice)
 			cuAuth.GET("/invoices/summary", cuInvoiceHandler.GetUserInvoiceSummary)
 			cuAuth.GET("/invoices/:id/download", cuInvoiceHandler.DownloadInvoice)
+
+			// 站内通知
+			cuAuth.GET("/notifications", cuNotificationHandler.GetNotifications)
+			cuAuth.PUT("/notifications/:id/read", cuNotificationHandler.MarkNotificationRead)
+
+			// 兑换券
+			cuAuth.POST("/vouchers/redeem", cuVoucherHandler.RedeemVoucher)
 		}
 	}
 
-	return router
+	return router, func() {
+		for i := len(stoppers) - 1; i >= 0; i-- {
+			stoppers[i]()
+		}
+	}
 }
 
 // convertPaymentConfig 转换支付配置
@@ -339,3 +547,27 @@ func convertPaymentConfig(cfg config.PaymentConfig) *service.PaymentConfig {
 
 	return config
 }
+
+// newEngine 创建gin引擎，仅信任配置的代理转发的 X-Forwarded-For/X-Real-IP，
+// 未配置时客户端IP取连接的对端地址，防止伪造请求头绕过IP网段和国家/地区限制
+func newEngine(trustedProxies []string) (*gin.Engine, error) {
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	return router, nil
+}
+
+// loadGeoIPDatabase 加载离线GeoIP库，未配置或加载失败时返回 nil（国家/地区限制无法通过）
+func loadGeoIPDatabase(path string, log *logrus.Logger) service.CountryResolver {
+	if path == "" {
+		return nil
+	}
+	geoIP, err := utils.LoadGeoIPDatabase(path)
+	if err != nil {
+		log.Warnf("Failed to load GeoIP database %s: %v", path, err)
+		return nil
+	}
+	log.Infof("GeoIP database loaded: %d ranges", geoIP.Size())
+	return geoIP
+}
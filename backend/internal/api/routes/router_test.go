@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewEngineClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		want           string
+	}{
+		{"no trusted proxy ignores spoofed header", nil, "198.51.100.7:40000", "198.51.100.7"},
+		{"untrusted peer ignores header", []string{"10.0.0.0/8"}, "198.51.100.7:40000", "198.51.100.7"},
+		{"trusted proxy forwards client ip", []string{"10.0.0.0/8"}, "10.1.2.3:40000", "203.0.113.9"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, err := newEngine(tc.trustedProxies)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			router.GET("/ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set("X-Forwarded-For", "203.0.113.9")
+			req.Header.Set("X-Real-IP", "203.0.113.9")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if got := w.Body.String(); got != tc.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := newEngine([]string{"not-an-ip"}); err == nil {
+		t.Error("expected error for invalid trusted proxy")
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// 授权码格式（与 pkg/utils 中的授权码生成器一致）
+var validCodeFormats = map[string]bool{
+	"legacy": true,
+	"base32": true,
+	"signed": true,
+}
+
+const (
+	// MaxAuthorizationCodeLength 授权码可存储的最大长度，取存储授权码的最短列 cu_orders.authorization_code varchar(500)
+	MaxAuthorizationCodeLength = 500
+	// signedCodeMaxEditionLength 签名码中版本标识的最大长度（创建授权码请求限制为50个字符）
+	signedCodeMaxEditionLength = 50
+)
+
+// SignedAuthorizationCodeMaxLength 计算指定RSA密钥字节数下签名码的最大长度
+// 格式：SC1.{base64url(payload)}.{base64url(签名)}，payload 为 {"e":"<版本>","x":<到期Unix秒>,"n":"<16位随机数>"}
+func SignedAuthorizationCodeMaxLength(keyBytes int) int {
+	payload := len(`{"e":"","x":,"n":""}`) + signedCodeMaxEditionLength + 12 + 16
+	return len("SC1.") + rawBase64Length(payload) + len(".") + rawBase64Length(keyBytes)
+}
+
+// rawBase64Length 无填充base64编码后的长度
+func rawBase64Length(n int) int {
+	return (n*8 + 5) / 6
+}
+
+// validateCodeFormats 校验授权码格式配置；配置了签名码时校验私钥可用，且生成的签名码不超过可存储的长度
+func (c *LicenseConfig) validateCodeFormats() error {
+	usesSigned := false
+	formats := map[string]string{"license.code_format": c.CodeFormat}
+	for product, format := range c.ProductCodeFormats {
+		formats["license.product_code_formats."+product] = format
+	}
+	for key, format := range formats {
+		if format == "" {
+			continue
+		}
+		if !validCodeFormats[format] {
+			return fmt.Errorf("%s: unsupported authorization code format %q", key, format)
+		}
+		if format == "signed" {
+			usesSigned = true
+		}
+	}
+	if !usesSigned {
+		return nil
+	}
+
+	keyBytes, err := rsaPrivateKeySize(c.RSA.PrivateKeyPath)
+	if err != nil {
+		return fmt.Errorf("signed authorization code requires RSA private key: %w", err)
+	}
+	if length := SignedAuthorizationCodeMaxLength(keyBytes); length > MaxAuthorizationCodeLength {
+		return fmt.Errorf("signed authorization code with a %d-bit RSA key can be %d characters, exceeding the storable %d characters",
+			keyBytes*8, length, MaxAuthorizationCodeLength)
+	}
+	return nil
+}
+
+// rsaPrivateKeySize 读取RSA私钥文件，返回密钥字节数（即签名长度）
+func rsaPrivateKeySize(path string) (int, error) {
+	if path == "" {
+		return 0, errors.New("license.rsa.private_key_path not configured")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return 0, errors.New("invalid PEM private key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key.Size(), nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return 0, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return 0, errors.New("not an RSA private key")
+	}
+	return key.Size(), nil
+}
@@ -9,21 +9,24 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Auth     AuthConfig     `mapstructure:"auth"`
-	Log      LogConfig      `mapstructure:"log"`
-	I18n     I18nConfig     `mapstructure:"i18n"`
-	License  LicenseConfig  `mapstructure:"license"`
-	Payment  PaymentConfig  `mapstructure:"payment"`
-	SMS      SMSConfig      `mapstructure:"sms"`
-	Cache    CacheConfig    `mapstructure:"cache"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Auth      AuthConfig      `mapstructure:"auth"`
+	Log       LogConfig       `mapstructure:"log"`
+	I18n      I18nConfig      `mapstructure:"i18n"`
+	License   LicenseConfig   `mapstructure:"license"`
+	Payment   PaymentConfig   `mapstructure:"payment"`
+	SMS       SMSConfig       `mapstructure:"sms"`
+	Cache     CacheConfig     `mapstructure:"cache"`
+	Scheduler SchedulerConfig `mapstructure:"scheduler"`
 }
 
 type ServerConfig struct {
 	Host string `mapstructure:"host"`
 	Port int    `mapstructure:"port"`
 	Mode string `mapstructure:"mode"`
+	// TrustedProxies 可信反向代理的IP或网段，仅信任其转发的客户端IP请求头；为空时不信任任何代理
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
@@ -84,6 +87,26 @@ type LicenseConfig struct {
 	HeartbeatTimeout int `mapstructure:"heartbeat_timeout"` // 心跳超时时间(秒)
 	OfflineTimeout   int `mapstructure:"offline_timeout"`   // 离线超时时间(分钟)
 	ExpiringDays     int `mapstructure:"expiring_days"`     // 即将过期天数
+
+	// 授权码格式：legacy/base32/signed
+	CodeFormat         string            `mapstructure:"code_format"`          // 默认授权码格式
+	ProductCodeFormats map[string]string `mapstructure:"product_code_formats"` // 按产品（software_id）指定的授权码格式
+
+	// 离线GeoIP库（CSV），用于授权码使用限制中的国家/地区校验
+	GeoIPDatabasePath string `mapstructure:"geoip_database_path"`
+
+	// 云部署授权检查接口：SaaS 后端通过 X-API-Key 调用，结果缓存在服务端
+	EntitlementAPIKeys  []string      `mapstructure:"entitlement_api_keys"`  // 允许调用授权检查接口的API Key
+	EntitlementCacheTTL time.Duration `mapstructure:"entitlement_cache_ttl"` // 授权检查结果缓存时间
+
+	// 按部署类型的离线容忍时间（最后一次成功心跳后客户端可离线使用的时长）
+	OfflineGrace OfflineGraceConfig `mapstructure:"offline_grace"`
+}
+
+type OfflineGraceConfig struct {
+	Standalone time.Duration `mapstructure:"standalone"` // 单机部署，允许长期离线
+	Cloud      time.Duration `mapstructure:"cloud"`      // 云部署，须保持心跳
+	Hybrid     time.Duration `mapstructure:"hybrid"`     // 混合部署，经本地中继心跳
 }
 
 type RSAConfig struct {
@@ -139,6 +162,16 @@ type MemoryConfig struct {
 	CleanupInterval time.Duration `mapstructure:"cleanup_interval"` // 清理间隔
 }
 
+type SchedulerConfig struct {
+	Enabled                 bool          `mapstructure:"enabled"`                   // 是否启用定时任务
+	SeatReclaimInterval     time.Duration `mapstructure:"seat_reclaim_interval"`     // 闲置席位回收执行间隔
+	ScheduledActionInterval time.Duration `mapstructure:"scheduled_action_interval"` // 授权码计划操作检查间隔
+	LeadReminderInterval    time.Duration `mapstructure:"lead_reminder_interval"`    // 线索跟进提醒检查间隔
+	LeadPendingTimeout      time.Duration `mapstructure:"lead_pending_timeout"`      // 线索待联系超过该时长发送提醒，0表示不提醒
+	TrashPurgeInterval      time.Duration `mapstructure:"trash_purge_interval"`      // 回收站自动清理执行间隔
+	TrashRetention          time.Duration `mapstructure:"trash_retention"`           // 回收站保留时长，超过后彻底删除，0表示不自动清理
+}
+
 type SMSConfig struct {
 	Enabled         bool         `mapstructure:"enabled"`           // 是否启用短信服务
 	AccessKeyID     string       `mapstructure:"access_key_id"`     // 阿里云AccessKey ID
@@ -185,6 +218,9 @@ func Load(configPath string) error {
 	if err := viper.Unmarshal(AppConfig); err != nil {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	if err := AppConfig.License.validateCodeFormats(); err != nil {
+		return fmt.Errorf("invalid license config: %w", err)
+	}
 
 	return nil
 }
@@ -194,6 +230,7 @@ func setDefaults() {
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.port", 18888)
 	viper.SetDefault("server.mode", "debug")
+	viper.SetDefault("server.trusted_proxies", []string{})
 
 	// Database defaults
 	viper.SetDefault("database.driver", "mysql")
@@ -241,6 +278,11 @@ func setDefaults() {
 	viper.SetDefault("license.heartbeat_timeout", 300)
 	viper.SetDefault("license.offline_timeout", 1440)
 	viper.SetDefault("license.expiring_days", 30)
+	viper.SetDefault("license.code_format", "legacy")
+	viper.SetDefault("license.entitlement_cache_ttl", "60s")
+	viper.SetDefault("license.offline_grace.standalone", "720h")
+	viper.SetDefault("license.offline_grace.cloud", "30m")
+	viper.SetDefault("license.offline_grace.hybrid", "72h")
 
 	// Payment defaults
 	viper.SetDefault("payment.default_method", "alipay")
@@ -273,6 +315,15 @@ func setDefaults() {
 	// Memory cache defaults
 	viper.SetDefault("cache.memory.max_size", 10000)
 	viper.SetDefault("cache.memory.cleanup_interval", "10m")
+
+	// Scheduler defaults
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.seat_reclaim_interval", "1h")
+	viper.SetDefault("scheduler.scheduled_action_interval", "1m")
+	viper.SetDefault("scheduler.lead_reminder_interval", "10m")
+	viper.SetDefault("scheduler.lead_pending_timeout", "72h")
+	viper.SetDefault("scheduler.trash_purge_interval", "24h")
+	viper.SetDefault("scheduler.trash_retention", "720h")
 }
 
 func GetConfig() *Config {
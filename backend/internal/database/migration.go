@@ -29,9 +29,26 @@ func AutoMigrate() error {
 		&models.AuthorizationCode{},
 		&models.License{},
 		&models.AuthorizationChange{},
-		&models.Invoice{}, // 发票表
-		&models.Package{}, // 套餐表
-		&models.Lead{},    // 线索表
+		&models.Invoice{},                          // 发票表
+		&models.Package{},                          // 套餐表
+		&models.Lead{},                             // 线索表
+		&models.LeadActivity{},                     // 线索跟进动态表
+		&models.SeatReclamation{},                  // 闲置席位回收记录表
+		&models.Notification{},                     // 站内通知表
+		&models.LicenseTransfer{},                  // 许可证设备转移记录表
+		&models.AuthorizationCodeBatch{},           // 授权码批次表
+		&models.Voucher{},                          // 兑换券表
+		&models.AuthorizationCodeShare{},           // 授权码分享记录表
+		&models.AuthorizationCodeScheduledAction{}, // 授权码计划操作表
+		&models.ActivationViolation{},              // 使用限制违规事件表
+		&models.SoftwareRelease{},                  // 产品版本发布表
+		&models.LicenseCommand{},                   // 远程命令表
+		&models.CustomerMerge{},                    // 客户合并审计记录表
+		&models.CustomerImportJob{},                // 客户导入任务表
+		&models.CustomFieldDefinition{},            // 自定义字段定义表
+		&models.CustomFieldValue{},                 // 自定义字段值表
+		&models.EntityTag{},                        // 对象标签表
+		&models.CustomerSeatPool{},                 // 客户席位池表
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
@@ -47,6 +64,11 @@ func AutoMigrate() error {
 		return fmt.Errorf("failed to initialize customer code sequence: %w", err)
 	}
 
+	// 补齐历史线索的去重字段
+	if err := backfillLeadNormalizedKeys(); err != nil {
+		return fmt.Errorf("failed to backfill lead normalized keys: %w", err)
+	}
+
 	// 初始化默认管理员用户
 	if err := initDefaultAdminUser(); err != nil {
 		return fmt.Errorf("failed to initialize default admin user: %w", err)
@@ -83,6 +105,28 @@ func initCustomerCodeSequence() error {
 	return nil
 }
 
+// backfillLeadNormalizedKeys 为新增去重字段之前创建的线索计算标准化的手机号、邮箱和公司名称
+func backfillLeadNormalizedKeys() error {
+	var leads []models.Lead
+	if err := DB.Where("normalized_phone = '' OR normalized_phone IS NULL").Find(&leads).Error; err != nil {
+		return err
+	}
+	for _, lead := range leads {
+		err := DB.Model(&models.Lead{}).Where("id = ?", lead.ID).UpdateColumns(map[string]interface{}{
+			"normalized_phone":   models.NormalizeLeadPhone(lead.ContactPhone),
+			"normalized_email":   models.NormalizeLeadEmail(lead.ContactEmail),
+			"normalized_company": models.NormalizeLeadCompany(lead.CompanyName),
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if len(leads) > 0 {
+		log.Printf("Backfilled normalized keys for %d leads", len(leads))
+	}
+	return nil
+}
+
 // RunMigrationIfEnabled 根据配置决定是否运行迁移
 func RunMigrationIfEnabled() error {
 	cfg := config.GetConfig()
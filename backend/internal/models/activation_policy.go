@@ -0,0 +1,110 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ActivationPolicy 授权码使用限制策略，在激活和心跳时校验，各项为空表示不限制
+type ActivationPolicy struct {
+	AllowedCIDRs        []string               `json:"allowed_cidrs,omitempty"`         // 允许的客户端IP网段（CIDR，单个IP视为/32或/128）
+	AllowedCountries    []string               `json:"allowed_countries,omitempty"`     // 允许的国家/地区（ISO 3166-1 二位代码），依赖离线GeoIP库
+	TimeWindows         []ActivationTimeWindow `json:"time_windows,omitempty"`          // 允许使用的时间窗口，满足任一窗口即可
+	Timezone            string                 `json:"timezone,omitempty"`              // 时间窗口和每日激活数使用的时区（IANA名称），默认服务器时区
+	MaxDailyActivations *int                   `json:"max_daily_activations,omitempty"` // 每天最多新增激活数
+}
+
+// ActivationTimeWindow 允许使用的时间窗口
+type ActivationTimeWindow struct {
+	Weekdays []int  `json:"weekdays,omitempty"` // 星期（0=周日 … 6=周六），为空表示每天
+	Start    string `json:"start"`              // 开始时间 HH:MM
+	End      string `json:"end"`                // 结束时间 HH:MM，早于开始时间表示跨越午夜
+}
+
+// IsEmpty 策略是否未设置任何限制
+func (p *ActivationPolicy) IsEmpty() bool {
+	return p == nil || (len(p.AllowedCIDRs) == 0 && len(p.AllowedCountries) == 0 &&
+		len(p.TimeWindows) == 0 && p.MaxDailyActivations == nil)
+}
+
+// GetActivationPolicy 解析授权码的使用限制策略，未设置时返回 nil
+func (a *AuthorizationCode) GetActivationPolicy() (*ActivationPolicy, error) {
+	if len(a.ActivationPolicy) == 0 || string(a.ActivationPolicy) == "null" {
+		return nil, nil
+	}
+	var policy ActivationPolicy
+	if err := json.Unmarshal(a.ActivationPolicy, &policy); err != nil {
+		return nil, err
+	}
+	if policy.IsEmpty() {
+		return nil, nil
+	}
+	return &policy, nil
+}
+
+// 使用限制违规类型
+const (
+	ActivationViolationIP         = "ip_not_allowed"       // 客户端IP不在允许的网段内
+	ActivationViolationCountry    = "country_not_allowed"  // 客户端所在国家/地区不在允许范围内
+	ActivationViolationTimeWindow = "outside_time_window"  // 不在允许使用的时间窗口内
+	ActivationViolationDailyLimit = "daily_limit_exceeded" // 超过每日新增激活数
+)
+
+// 违规发生的环节
+const (
+	ActivationViolationStageActivation = "activation" // 激活
+	ActivationViolationStageHeartbeat  = "heartbeat"  // 心跳
+)
+
+// ActivationViolation 使用限制违规事件
+type ActivationViolation struct {
+	ID                   string    `gorm:"type:varchar(36);primaryKey" json:"id"`                        // 事件ID
+	AuthorizationCodeID  string    `gorm:"type:varchar(36);not null;index" json:"authorization_code_id"` // 授权码ID
+	CustomerID           string    `gorm:"type:varchar(36);not null;index" json:"customer_id"`           // 客户ID
+	LicenseID            *string   `gorm:"type:varchar(36);index" json:"license_id"`                     // 许可证ID（心跳时）
+	Stage                string    `gorm:"type:varchar(20);not null" json:"stage"`                       // 环节：activation/heartbeat
+	ViolationType        string    `gorm:"type:varchar(30);not null;index" json:"violation_type"`        // 违规类型
+	ViolationTypeDisplay string    `gorm:"-" json:"violation_type_display,omitempty"`                    // 违规类型显示（多语言）
+	ClientIP             string    `gorm:"type:varchar(45)" json:"client_ip"`                            // 客户端IP
+	Country              *string   `gorm:"type:varchar(2)" json:"country"`                               // GeoIP识别的国家/地区
+	HardwareFingerprint  string    `gorm:"type:varchar(200)" json:"hardware_fingerprint"`                // 硬件指纹
+	CreatedAt            time.Time `gorm:"type:datetime(3);not null;index" json:"created_at"`            // 发生时间
+}
+
+// TableName 指定表名
+func (ActivationViolation) TableName() string {
+	return "activation_violations"
+}
+
+// BeforeCreate 创建前自动设置ID和时间
+func (v *ActivationViolation) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	if v.CreatedAt.IsZero() {
+		v.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// ActivationViolationListRequest 违规事件列表查询请求
+type ActivationViolationListRequest struct {
+	Page                int    `form:"page" binding:"omitempty,min=1"`                                                                                       // 页码，默认1
+	PageSize            int    `form:"page_size" binding:"omitempty,min=1,max=100"`                                                                          // 每页条数，默认20，最大100
+	AuthorizationCodeID string `form:"authorization_code_id" binding:"omitempty"`                                                                            // 授权码ID筛选
+	CustomerID          string `form:"customer_id" binding:"omitempty"`                                                                                      // 客户ID筛选
+	ViolationType       string `form:"violation_type" binding:"omitempty,oneof=ip_not_allowed country_not_allowed outside_time_window daily_limit_exceeded"` // 违规类型筛选
+	Stage               string `form:"stage" binding:"omitempty,oneof=activation heartbeat"`                                                                 // 环节筛选
+}
+
+// ActivationViolationListResponse 违规事件列表响应
+type ActivationViolationListResponse struct {
+	List       []*ActivationViolation `json:"list"`        // 违规事件列表
+	Total      int64                  `json:"total"`       // 总记录数
+	Page       int                    `json:"page"`        // 当前页码
+	PageSize   int                    `json:"page_size"`   // 每页条数
+	TotalPages int                    `json:"total_pages"` // 总页数
+}
@@ -45,8 +45,10 @@ type AuthorizationCode struct {
 	Description            *string                  `gorm:"type:text" json:"description"`                                          // 描述
 	StartDate              time.Time                `gorm:"type:datetime(3);not null" json:"start_date"`                           // 生效日期
 	EndDate                time.Time                `gorm:"type:datetime(3);not null" json:"end_date"`                             // 失效日期
+	MaintenanceUntil       *time.Time               `gorm:"type:datetime(3)" json:"maintenance_until"`                             // 维护（升级）截止时间，客户端可使用此前发布的任何版本，为空不限制
 	DeploymentType         string                   `gorm:"type:varchar(20);not null;default:'standalone'" json:"deployment_type"` // 部署类型：standalone/cloud/hybrid
 	DeploymentTypeDisplay  string                   `gorm:"-" json:"deployment_type_display,omitempty"`                            // 部署类型显示（多语言）
+	RelayFingerprint       *string                  `gorm:"type:varchar(200)" json:"relay_fingerprint"`                            // 混合部署指定的本地中继节点硬件指纹
 	EncryptionType         *string                  `gorm:"type:varchar(20);default:'standard'" json:"encryption_type"`            // 加密类型：standard/advanced
 	EncryptionTypeDisplay  string                   `gorm:"-" json:"encryption_type_display,omitempty"`                            // 加密类型显示（多语言）
 	SoftwareVersion        *string                  `gorm:"type:varchar(50)" json:"software_version"`                              // 软件版本
@@ -59,12 +61,22 @@ type AuthorizationCode struct {
 	FeatureConfig          JSON                     `gorm:"type:json" json:"feature_config" swaggertype:"object"`                  // 功能配置（JSON对象）
 	UsageLimits            JSON                     `gorm:"type:json" json:"usage_limits" swaggertype:"object"`                    // 使用限制（JSON对象）
 	CustomParameters       JSON                     `gorm:"type:json" json:"custom_parameters" swaggertype:"object"`               // 自定义参数（JSON对象）
+	DormantReclaimDays     *int                     `gorm:"type:int" json:"dormant_reclaim_days"`                                  // 闲置席位回收策略：超过N天无心跳自动释放，为空不回收
+	MaxTransfers           *int                     `gorm:"type:int" json:"max_transfers"`                                         // 每个席位在一个周期内最多可转移设备次数，为空不限制
+	TransferPeriodDays     *int                     `gorm:"type:int" json:"transfer_period_days"`                                  // 转移次数统计周期（天），为空表示整个授权期
+	ActivationPolicy       JSON                     `gorm:"type:json" json:"activation_policy" swaggertype:"object"`               // 使用限制策略（IP网段/国家/时间窗口/每日激活数），为空不限制
+	CodeFormat             string                   `gorm:"type:varchar(20);not null;default:'legacy'" json:"code_format"`         // 授权码格式：legacy/base32/signed
+	BatchID                *string                  `gorm:"type:varchar(36);index" json:"batch_id"`                                // 所属批次ID（批量生成时）
+	UseSeatPool            bool                     `gorm:"not null;default:false" json:"use_seat_pool"`                           // 是否从客户席位池取用席位
 	Status                 string                   `gorm:"-" json:"status,omitempty"`                                             // 状态：normal/locked/expired
 	StatusDisplay          string                   `gorm:"-" json:"status_display,omitempty"`                                     // 状态显示（多语言）
 	CreatedAt              time.Time                `gorm:"type:datetime(3);not null" json:"created_at"`                           // 创建时间
 	UpdatedAt              time.Time                `gorm:"type:datetime(3);not null" json:"updated_at"`                           // 更新时间
+	DeletedAt              gorm.DeletedAt           `gorm:"index" json:"-"`                                                        // 删除时间（软删除，进入回收站）
 	CustomerInfo           *CustomerInfoForAuthCode `gorm:"-" json:"customer_info,omitempty"`                                      // 客户信息（仅在详情接口返回）
 	ActivatedLicensesCount int64                    `gorm:"-" json:"activated_licenses_count,omitempty"`                           // 该授权码下已激活的许可证数量
+	CustomFields           map[string]string        `gorm:"-" json:"custom_fields,omitempty"`                                      // 自定义字段（字段标识 -> 值）
+	Tags                   []string                 `gorm:"-" json:"tags,omitempty"`                                               // 标签
 }
 
 // CustomerInfoForAuthCode 授权码详情中的客户信息结构
@@ -108,17 +120,29 @@ func (a *AuthorizationCode) BeforeUpdate(tx *gorm.DB) error {
 
 // AuthorizationCodeCreateRequest 创建授权码请求结构
 type AuthorizationCodeCreateRequest struct {
-	CustomerID       string      `json:"customer_id" binding:"required"`                                   // 客户ID
-	SoftwareID       *string     `json:"software_id" binding:"omitempty"`                                  // 软件ID
-	Description      *string     `json:"description" binding:"omitempty,max=1000"`                         // 描述
-	ValidityDays     int         `json:"validity_days" binding:"required,min=1,max=365000"`                // 有效天数（1-365000天，365000代表永久有效）
-	DeploymentType   string      `json:"deployment_type" binding:"required,oneof=standalone cloud hybrid"` // 部署类型：standalone/cloud/hybrid
-	EncryptionType   *string     `json:"encryption_type" binding:"omitempty,oneof=standard advanced"`      // 加密类型：standard/advanced
-	SoftwareVersion  *string     `json:"software_version" binding:"omitempty"`                             // 软件版本
-	MaxActivations   int         `json:"max_activations" binding:"required,min=1"`                         // 最大激活次数
-	FeatureConfig    interface{} `json:"feature_config" binding:"omitempty"`                               // 功能配置（JSON对象）
-	UsageLimits      interface{} `json:"usage_limits" binding:"omitempty"`                                 // 使用限制（JSON对象）
-	CustomParameters interface{} `json:"custom_parameters" binding:"omitempty"`                            // 自定义参数（JSON对象）
+	CustomerID         string            `json:"customer_id" binding:"required"`                                   // 客户ID
+	SoftwareID         *string           `json:"software_id" binding:"omitempty"`                                  // 软件ID
+	Description        *string           `json:"description" binding:"omitempty,max=1000"`                         // 描述
+	StartDate          *string           `json:"start_date" binding:"omitempty"`                                   // 生效日期（YYYY-MM-DD），可选，默认当天，可指定未来日期
+	ValidityDays       int               `json:"validity_days" binding:"required,min=1,max=365000"`                // 有效天数（1-365000天，365000代表永久有效），从生效日期起算
+	MaintenanceUntil   *string           `json:"maintenance_until" binding:"omitempty"`                            // 维护截止日期（YYYY-MM-DD），可选，为空不限制可用版本
+	DeploymentType     string            `json:"deployment_type" binding:"required,oneof=standalone cloud hybrid"` // 部署类型：standalone/cloud/hybrid
+	RelayFingerprint   *string           `json:"relay_fingerprint" binding:"omitempty,max=200"`                    // 本地中继节点硬件指纹，混合部署激活前必须指定
+	EncryptionType     *string           `json:"encryption_type" binding:"omitempty,oneof=standard advanced"`      // 加密类型：standard/advanced
+	SoftwareVersion    *string           `json:"software_version" binding:"omitempty"`                             // 软件版本
+	MaxActivations     int               `json:"max_activations" binding:"required,min=1"`                         // 最大激活次数
+	FeatureConfig      interface{}       `json:"feature_config" binding:"omitempty"`                               // 功能配置（JSON对象）
+	UsageLimits        interface{}       `json:"usage_limits" binding:"omitempty"`                                 // 使用限制（JSON对象）
+	CustomParameters   interface{}       `json:"custom_parameters" binding:"omitempty"`                            // 自定义参数（JSON对象）
+	DormantReclaimDays *int              `json:"dormant_reclaim_days" binding:"omitempty,min=1,max=3650"`          // 闲置席位回收天数，可选
+	MaxTransfers       *int              `json:"max_transfers" binding:"omitempty,min=1,max=1000"`                 // 每席位每周期最多转移次数，可选
+	TransferPeriodDays *int              `json:"transfer_period_days" binding:"omitempty,min=1,max=3650"`          // 转移次数统计周期（天），可选
+	UseSeatPool        bool              `json:"use_seat_pool"`                                                    // 是否从客户席位池取用席位，客户须已设置席位池
+	ActivationPolicy   *ActivationPolicy `json:"activation_policy" binding:"omitempty"`                            // 使用限制策略，可选
+	CodeFormat         *string           `json:"code_format" binding:"omitempty,oneof=legacy base32 signed"`       // 授权码格式，可选，默认按产品/系统配置
+	Edition            *string           `json:"edition" binding:"omitempty,max=50"`                               // 版本标识，签名码中编码，可选
+	CustomFields       map[string]string `json:"custom_fields"`                                                    // 自定义字段（字段标识 -> 值），必填字段必须提供
+	Tags               []string          `json:"tags" binding:"omitempty,max=20,dive,required,max=50"`             // 标签，可选
 }
 
 // AuthorizationCodeCreateResponse 创建授权码响应结构
@@ -138,6 +162,11 @@ type AuthorizationCodeListRequest struct {
 	EndDate    string `form:"end_date" binding:"omitempty"`                              // 创建结束时间
 	Sort       string `form:"sort" binding:"omitempty,oneof=created_at updated_at code"` // 排序字段，默认created_at
 	Order      string `form:"order" binding:"omitempty,oneof=asc desc"`                  // 排序方向，默认desc
+
+	IncludeSubsidiaries bool     `form:"include_subsidiaries"` // 按客户筛选时包含其所有下级客户
+	CustomerIDs         []string `form:"-"`                    // 限定客户ID范围（由服务层按层级关系展开）
+
+	CustomFieldFilter
 }
 
 // AuthorizationCodeListItem 授权码列表项结构
@@ -158,6 +187,9 @@ type AuthorizationCodeListItem struct {
 	IsLocked              bool    `json:"is_locked"`                         // 是否锁定
 	Description           *string `json:"description"`                       // 描述
 	CreatedAt             string  `json:"created_at"`                        // 创建时间
+
+	CustomFields map[string]string `json:"custom_fields,omitempty"` // 自定义字段（字段标识 -> 值）
+	Tags         []string          `json:"tags,omitempty"`          // 标签
 }
 
 // AuthorizationCodeListResponse 授权码列表响应结构
@@ -171,16 +203,23 @@ type AuthorizationCodeListResponse struct {
 
 // AuthorizationCodeUpdateRequest 更新授权码请求结构
 type AuthorizationCodeUpdateRequest struct {
-	SoftwareID       *string     `json:"software_id" binding:"omitempty"`                                   // 软件ID
-	Description      *string     `json:"description" binding:"omitempty,max=1000"`                          // 描述
-	ValidityDays     *int        `json:"validity_days" binding:"omitempty,min=1,max=365000"`                // 有效天数（1-365000天，365000代表永久有效）
-	DeploymentType   *string     `json:"deployment_type" binding:"omitempty,oneof=standalone cloud hybrid"` // 部署类型：standalone/cloud/hybrid
-	EncryptionType   *string     `json:"encryption_type" binding:"omitempty,oneof=standard advanced"`       // 加密类型：standard/advanced
-	SoftwareVersion  *string     `json:"software_version" binding:"omitempty"`                              // 软件版本
-	MaxActivations   *int        `json:"max_activations" binding:"omitempty,min=1"`                         // 最大激活次数
-	FeatureConfig    interface{} `json:"feature_config" binding:"omitempty"`                                // 功能配置
-	UsageLimits      interface{} `json:"usage_limits" binding:"omitempty"`                                  // 使用限制
-	CustomParameters interface{} `json:"custom_parameters" binding:"omitempty"`                             // 自定义参数
+	SoftwareID         *string           `json:"software_id" binding:"omitempty"`                                   // 软件ID
+	Description        *string           `json:"description" binding:"omitempty,max=1000"`                          // 描述
+	ValidityDays       *int              `json:"validity_days" binding:"omitempty,min=1,max=365000"`                // 有效天数（1-365000天，365000代表永久有效）
+	MaintenanceUntil   *string           `json:"maintenance_until" binding:"omitempty"`                             // 维护截止日期（YYYY-MM-DD），传空字符串表示不限制
+	DeploymentType     *string           `json:"deployment_type" binding:"omitempty,oneof=standalone cloud hybrid"` // 部署类型：standalone/cloud/hybrid
+	RelayFingerprint   *string           `json:"relay_fingerprint" binding:"omitempty,max=200"`                     // 本地中继节点硬件指纹，传空字符串表示清除
+	EncryptionType     *string           `json:"encryption_type" binding:"omitempty,oneof=standard advanced"`       // 加密类型：standard/advanced
+	SoftwareVersion    *string           `json:"software_version" binding:"omitempty"`                              // 软件版本
+	MaxActivations     *int              `json:"max_activations" binding:"omitempty,min=1"`                         // 最大激活次数
+	FeatureConfig      interface{}       `json:"feature_config" binding:"omitempty"`                                // 功能配置
+	UsageLimits        interface{}       `json:"usage_limits" binding:"omitempty"`                                  // 使用限制
+	CustomParameters   interface{}       `json:"custom_parameters" binding:"omitempty"`                             // 自定义参数
+	DormantReclaimDays *int              `json:"dormant_reclaim_days" binding:"omitempty,min=0,max=3650"`           // 闲置席位回收天数，传0表示关闭回收
+	MaxTransfers       *int              `json:"max_transfers" binding:"omitempty,min=0,max=1000"`                  // 每席位每周期最多转移次数，传0表示不限制
+	TransferPeriodDays *int              `json:"transfer_period_days" binding:"omitempty,min=0,max=3650"`           // 转移次数统计周期（天），传0表示整个授权期
+	UseSeatPool        *bool             `json:"use_seat_pool"`                                                     // 是否从客户席位池取用席位，开启时客户须已设置席位池且剩余席位足够容纳该授权码已激活的许可证
+	ActivationPolicy   *ActivationPolicy `json:"activation_policy" binding:"omitempty"`                             // 使用限制策略，传空对象表示取消所有限制
 	// 可选的起止时间（优先于 validity_days），格式：YYYY-MM-DD
 	StartDate  *string `json:"start_date" binding:"omitempty"`                                                      // 生效日期（YYYY-MM-DD）
 	EndDate    *string `json:"end_date" binding:"omitempty"`                                                        // 失效日期（YYYY-MM-DD）
@@ -195,6 +234,41 @@ type AuthorizationCodeLockRequest struct {
 	Reason     *string `json:"reason" binding:"omitempty,max=500"`      // 变更原因（记录到变更历史）
 }
 
+// AuthorizationCodeSplitPart 拆分后单个授权码的配置
+type AuthorizationCodeSplitPart struct {
+	MaxActivations int     `json:"max_activations" binding:"required,min=1"` // 席位数
+	Description    *string `json:"description" binding:"omitempty,max=500"`  // 描述（如部门名称），为空沿用原授权码描述
+}
+
+// AuthorizationCodeSplitRequest 拆分授权码请求结构
+// 第一部分保留在原授权码上，其余部分生成新的授权码，各部分席位数之和必须等于原授权码席位数
+type AuthorizationCodeSplitRequest struct {
+	Parts  []AuthorizationCodeSplitPart `json:"parts" binding:"required,min=2,max=50,dive"` // 拆分方案
+	Reason *string                      `json:"reason" binding:"omitempty,max=500"`         // 变更原因（记录到变更历史）
+}
+
+// AuthorizationCodeSplitResponse 拆分授权码响应结构
+type AuthorizationCodeSplitResponse struct {
+	Source        *AuthorizationCode   `json:"source"`         // 原授权码（保留第一部分席位）
+	Children      []*AuthorizationCode `json:"children"`       // 新生成的授权码
+	MovedLicenses int                  `json:"moved_licenses"` // 迁移到新授权码的许可证数量
+}
+
+// AuthorizationCodeMergeRequest 合并授权码请求结构
+// 来源授权码的席位和许可证并入目标授权码，来源授权码清零并锁定
+type AuthorizationCodeMergeRequest struct {
+	TargetID  string   `json:"target_id" binding:"required"`                             // 保留的目标授权码ID
+	SourceIDs []string `json:"source_ids" binding:"required,min=1,max=50,dive,required"` // 并入的授权码ID列表
+	Reason    *string  `json:"reason" binding:"omitempty,max=500"`                       // 变更原因（记录到变更历史）
+}
+
+// AuthorizationCodeMergeResponse 合并授权码响应结构
+type AuthorizationCodeMergeResponse struct {
+	Target        *AuthorizationCode `json:"target"`          // 合并后的目标授权码
+	MergedCodeIDs []string           `json:"merged_code_ids"` // 已并入的授权码ID
+	MovedLicenses int64              `json:"moved_licenses"`  // 迁移到目标授权码的许可证数量
+}
+
 // AuthorizationChange 授权变更历史模型
 type AuthorizationChange struct {
 	ID                  string    `gorm:"type:varchar(36);primaryKey" json:"id"`                        // 变更记录ID
@@ -262,15 +336,48 @@ type AuthorizationChangeListResponse struct {
 	TotalPages int                           `json:"total_pages"` // 总页数
 }
 
+// AuthorizationChangeDiffRequest 授权变更快照对比请求结构
+type AuthorizationChangeDiffRequest struct {
+	FromChangeID string `form:"from_change_id" binding:"required"`               // 对比起点变更记录ID
+	FromSnapshot string `form:"from_snapshot" binding:"omitempty,oneof=old new"` // 起点快照：old-变更前，new-变更后（默认）
+	ToChangeID   string `form:"to_change_id" binding:"omitempty"`                // 对比终点变更记录ID，为空时与授权码当前配置对比
+	ToSnapshot   string `form:"to_snapshot" binding:"omitempty,oneof=old new"`   // 终点快照：old-变更前，new-变更后（默认）
+}
+
+// AuthorizationConfigFieldDiff 配置快照字段级差异
+type AuthorizationConfigFieldDiff struct {
+	Field string      `json:"field"` // 字段路径，JSON配置字段使用点号分隔，如 feature_config.max_users
+	From  interface{} `json:"from"`  // 起点快照中的值，不存在时为 null
+	To    interface{} `json:"to"`    // 终点快照中的值，不存在时为 null
+}
+
+// AuthorizationChangeDiffResponse 授权变更快照对比响应结构
+type AuthorizationChangeDiffResponse struct {
+	FromChangeID string                         `json:"from_change_id"` // 对比起点变更记录ID
+	FromSnapshot string                         `json:"from_snapshot"`  // 起点快照
+	ToChangeID   string                         `json:"to_change_id"`   // 对比终点变更记录ID，与当前配置对比时为空
+	ToSnapshot   string                         `json:"to_snapshot"`    // 终点快照，与当前配置对比时为 current
+	Diffs        []AuthorizationConfigFieldDiff `json:"diffs"`          // 字段差异列表
+}
+
+// AuthorizationCodeRevertRequest 回滚授权码配置请求结构
+type AuthorizationCodeRevertRequest struct {
+	ChangeID string  `json:"change_id" binding:"required"`               // 目标变更记录ID
+	Snapshot string  `json:"snapshot" binding:"omitempty,oneof=old new"` // 回滚到的快照：old-变更前，new-变更后（默认）
+	Reason   *string `json:"reason" binding:"omitempty,max=500"`         // 回滚原因
+}
+
 // AuthorizationCodeShareRequest 授权码分享请求结构
 type AuthorizationCodeShareRequest struct {
-	TargetContact string `json:"target_contact" binding:"required"`    // 受赠用户联系方式（手机号或邮箱）
-	ShareCount    int    `json:"share_count" binding:"required,min=1"` // 分享激活次数
+	TargetContact string `json:"target_contact" binding:"required"`            // 受赠用户联系方式（手机号或邮箱）
+	ShareCount    int    `json:"share_count" binding:"required,min=1"`         // 分享激活次数
+	Mode          string `json:"mode" binding:"omitempty,oneof=direct invite"` // 分享方式：direct-直接分享（默认），invite-受赠方接受后生效
 }
 
 // AuthorizationCodeShareResponse 授权码分享响应结构
 type AuthorizationCodeShareResponse struct {
-	NewAuthorizationCode AuthorizationCodeShareResponseItem `json:"new_authorization_code"` // 新生成的授权码信息
+	NewAuthorizationCode *AuthorizationCodeShareResponseItem `json:"new_authorization_code,omitempty"` // 新生成的授权码信息（邀请待接受时为空）
+	Share                *AuthorizationCodeShare             `json:"share"`                            // 分享记录
 }
 
 // AuthorizationCodeShareResponseItem 分享响应中的授权码信息
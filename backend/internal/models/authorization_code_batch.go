@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuthorizationCodeBatchStatus 授权码批次状态
+type AuthorizationCodeBatchStatus string
+
+const (
+	AuthorizationCodeBatchStatusPending   AuthorizationCodeBatchStatus = "pending"   // 等待生成
+	AuthorizationCodeBatchStatusRunning   AuthorizationCodeBatchStatus = "running"   // 生成中
+	AuthorizationCodeBatchStatusCompleted AuthorizationCodeBatchStatus = "completed" // 已完成
+	AuthorizationCodeBatchStatusFailed    AuthorizationCodeBatchStatus = "failed"    // 生成失败
+	AuthorizationCodeBatchStatusRevoked   AuthorizationCodeBatchStatus = "revoked"   // 已撤销
+)
+
+// 批次导出格式
+const (
+	BatchExportFormatCSV  = "csv"
+	BatchExportFormatXLSX = "xlsx"
+)
+
+// AuthorizationCodeBatch 授权码批量生成批次
+type AuthorizationCodeBatch struct {
+	ID             string     `gorm:"type:varchar(36);primaryKey" json:"id"`                           // 批次ID
+	Label          string     `gorm:"type:varchar(100);not null;index" json:"label"`                   // 批次标签，用于渠道追踪
+	CustomerID     string     `gorm:"type:varchar(36);not null;index" json:"customer_id"`              // 客户ID（分销商）
+	Quantity       int        `gorm:"not null" json:"quantity"`                                        // 计划生成数量
+	GeneratedCount int        `gorm:"not null;default:0" json:"generated_count"`                       // 已生成数量
+	Status         string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // 状态：pending/running/completed/failed/revoked
+	StatusDisplay  string     `gorm:"-" json:"status_display,omitempty"`                               // 状态显示（多语言）
+	Settings       JSON       `gorm:"type:json" json:"settings" swaggertype:"object"`                  // 授权码配置（创建请求快照）
+	StartDate      time.Time  `gorm:"type:datetime(3);not null" json:"start_date"`                     // 生效日期
+	EndDate        time.Time  `gorm:"type:datetime(3);not null" json:"end_date"`                       // 失效日期
+	ErrorMessage   *string    `gorm:"type:text" json:"error_message"`                                  // 生成失败原因
+	IsLocked       bool       `gorm:"not null;default:false" json:"is_locked"`                         // 是否已整批锁定
+	RevokeReason   *string    `gorm:"type:varchar(500)" json:"revoke_reason"`                          // 撤销原因
+	RevokedAt      *time.Time `gorm:"type:datetime(3)" json:"revoked_at"`                              // 撤销时间
+	CreatedBy      string     `gorm:"type:varchar(36);not null" json:"created_by"`                     // 创建人ID
+	CompletedAt    *time.Time `gorm:"type:datetime(3)" json:"completed_at"`                            // 生成完成时间
+	LeaseOwner     *string    `gorm:"type:varchar(36)" json:"-"`                                       // 执行租约持有者（多实例下领取批次的标识）
+	LeaseExpiresAt *time.Time `gorm:"type:datetime(3)" json:"-"`                                       // 执行租约到期时间，过期后其他实例可接管续跑
+	CreatedAt      time.Time  `gorm:"type:datetime(3);not null" json:"created_at"`                     // 创建时间
+	UpdatedAt      time.Time  `gorm:"type:datetime(3);not null" json:"updated_at"`                     // 更新时间
+	CustomerName   string     `gorm:"-" json:"customer_name,omitempty"`                                // 客户名称
+}
+
+// TableName 指定表名
+func (AuthorizationCodeBatch) TableName() string {
+	return "authorization_code_batches"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (b *AuthorizationCodeBatch) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == "" {
+		b.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = now
+	}
+	if b.UpdatedAt.IsZero() {
+		b.UpdatedAt = now
+	}
+	return nil
+}
+
+// AuthorizationCodeBatchCreateRequest 批量生成授权码请求，批次内所有授权码使用相同配置
+type AuthorizationCodeBatchCreateRequest struct {
+	AuthorizationCodeCreateRequest
+	Label    string `json:"label" binding:"required,max=100"`           // 批次标签
+	Quantity int    `json:"quantity" binding:"required,min=1,max=5000"` // 生成数量
+}
+
+// AuthorizationCodeBatchListRequest 批次列表查询请求
+type AuthorizationCodeBatchListRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`                                            // 页码，默认1
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`                               // 每页条数，默认20，最大100
+	CustomerID string `form:"customer_id" binding:"omitempty"`                                           // 客户ID筛选
+	Label      string `form:"label" binding:"omitempty,max=100"`                                         // 批次标签模糊搜索
+	Status     string `form:"status" binding:"omitempty,oneof=pending running completed failed revoked"` // 状态筛选
+}
+
+// AuthorizationCodeBatchListResponse 批次列表响应
+type AuthorizationCodeBatchListResponse struct {
+	List       []*AuthorizationCodeBatch `json:"list"`        // 批次列表
+	Total      int64                     `json:"total"`       // 总记录数
+	Page       int                       `json:"page"`        // 当前页码
+	PageSize   int                       `json:"page_size"`   // 每页条数
+	TotalPages int                       `json:"total_pages"` // 总页数
+}
+
+// AuthorizationCodeBatchRevokeRequest 撤销批次请求
+type AuthorizationCodeBatchRevokeRequest struct {
+	Reason *string `json:"reason" binding:"omitempty,max=500"` // 撤销原因
+}
+
+// AuthorizationCodeBatchOperationResponse 整批锁定/撤销结果
+type AuthorizationCodeBatchOperationResponse struct {
+	BatchID         string `json:"batch_id"`         // 批次ID
+	AffectedCodes   int64  `json:"affected_codes"`   // 受影响的授权码数量
+	RevokedLicenses int64  `json:"revoked_licenses"` // 被撤销的许可证数量（仅撤销操作）
+}
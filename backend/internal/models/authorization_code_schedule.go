@@ -0,0 +1,108 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 计划操作类型
+const (
+	ScheduledActionLock              = "lock"                // 锁定授权码
+	ScheduledActionUnlock            = "unlock"              // 解锁授权码
+	ScheduledActionExtend            = "extend"              // 延长有效期
+	ScheduledActionSetMaxActivations = "set_max_activations" // 调整最大激活数
+)
+
+// 计划操作触发方式
+const (
+	ScheduledTriggerAt        = "at"         // 到达指定时间执行
+	ScheduledTriggerOrderPaid = "order_paid" // 关联订单支付后执行
+)
+
+// ScheduledActionStatus 计划操作状态
+type ScheduledActionStatus string
+
+const (
+	ScheduledActionStatusPending   ScheduledActionStatus = "pending"   // 待执行
+	ScheduledActionStatusDone      ScheduledActionStatus = "done"      // 已执行
+	ScheduledActionStatusFailed    ScheduledActionStatus = "failed"    // 执行失败
+	ScheduledActionStatusCancelled ScheduledActionStatus = "cancelled" // 已取消
+)
+
+// AuthorizationCodeScheduledAction 授权码计划操作，由定时任务在触发条件满足时执行并记录到授权变更历史
+type AuthorizationCodeScheduledAction struct {
+	ID                  string     `gorm:"type:varchar(36);primaryKey" json:"id"`                           // 计划操作ID
+	AuthorizationCodeID string     `gorm:"type:varchar(36);not null;index" json:"authorization_code_id"`    // 授权码ID
+	ActionType          string     `gorm:"type:varchar(30);not null" json:"action_type"`                    // 操作类型：lock/unlock/extend/set_max_activations
+	ActionTypeDisplay   string     `gorm:"-" json:"action_type_display,omitempty"`                          // 操作类型显示（多语言）
+	TriggerType         string     `gorm:"type:varchar(20);not null" json:"trigger_type"`                   // 触发方式：at/order_paid
+	RunAt               *time.Time `gorm:"type:datetime(3);index" json:"run_at"`                            // 计划执行时间（trigger_type=at）
+	OrderID             *string    `gorm:"type:varchar(36);index" json:"order_id"`                          // 关联订单ID（trigger_type=order_paid）
+	ExtendDays          *int       `gorm:"type:int" json:"extend_days"`                                     // 延长天数（action_type=extend）
+	MaxActivations      *int       `gorm:"type:int" json:"max_activations"`                                 // 新的最大激活数（action_type=set_max_activations）
+	Reason              *string    `gorm:"type:varchar(500)" json:"reason"`                                 // 操作原因，执行时写入变更历史
+	Status              string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // 状态：pending/done/failed/cancelled
+	StatusDisplay       string     `gorm:"-" json:"status_display,omitempty"`                               // 状态显示（多语言）
+	ExecutedAt          *time.Time `gorm:"type:datetime(3)" json:"executed_at"`                             // 执行（或失败、取消）时间
+	ChangeID            *string    `gorm:"type:varchar(36)" json:"change_id"`                               // 执行后生成的授权变更记录ID
+	ErrorMessage        *string    `gorm:"type:varchar(500)" json:"error_message"`                          // 执行失败原因
+	CreatedBy           string     `gorm:"type:varchar(36);not null" json:"created_by"`                     // 创建人ID
+	CreatedAt           time.Time  `gorm:"type:datetime(3);not null" json:"created_at"`                     // 创建时间
+	UpdatedAt           time.Time  `gorm:"type:datetime(3);not null" json:"updated_at"`                     // 更新时间
+}
+
+// TableName 指定表名
+func (AuthorizationCodeScheduledAction) TableName() string {
+	return "authorization_code_scheduled_actions"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (a *AuthorizationCodeScheduledAction) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = now
+	}
+	if a.UpdatedAt.IsZero() {
+		a.UpdatedAt = now
+	}
+	return nil
+}
+
+// AuthorizationCodeScheduledActionCreateRequest 创建计划操作请求
+type AuthorizationCodeScheduledActionCreateRequest struct {
+	ActionType     string     `json:"action_type" binding:"required,oneof=lock unlock extend set_max_activations"` // 操作类型
+	TriggerType    string     `json:"trigger_type" binding:"required,oneof=at order_paid"`                         // 触发方式
+	RunAt          *time.Time `json:"run_at" binding:"omitempty"`                                                  // 计划执行时间（RFC3339），trigger_type=at 时必填
+	OrderID        *string    `json:"order_id" binding:"omitempty"`                                                // 关联订单ID，trigger_type=order_paid 时必填
+	ExtendDays     *int       `json:"extend_days" binding:"omitempty,min=1,max=365000"`                            // 延长天数，action_type=extend 时必填
+	MaxActivations *int       `json:"max_activations" binding:"omitempty,min=1"`                                   // 新的最大激活数，action_type=set_max_activations 时必填
+	Reason         *string    `json:"reason" binding:"omitempty,max=500"`                                          // 操作原因
+}
+
+// AuthorizationCodeScheduledActionListRequest 计划操作列表查询请求
+type AuthorizationCodeScheduledActionListRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`                                 // 页码，默认1
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`                    // 每页条数，默认20，最大100
+	Status   string `form:"status" binding:"omitempty,oneof=pending done failed cancelled"` // 状态筛选
+}
+
+// AuthorizationCodeScheduledActionListResponse 计划操作列表响应
+type AuthorizationCodeScheduledActionListResponse struct {
+	List       []*AuthorizationCodeScheduledAction `json:"list"`        // 计划操作列表
+	Total      int64                               `json:"total"`       // 总记录数
+	Page       int                                 `json:"page"`        // 当前页码
+	PageSize   int                                 `json:"page_size"`   // 每页条数
+	TotalPages int                                 `json:"total_pages"` // 总页数
+}
+
+// ScheduledActionRunResponse 执行一次到期计划操作的结果
+type ScheduledActionRunResponse struct {
+	Due      int `json:"due"`      // 到期的计划操作数量
+	Executed int `json:"executed"` // 执行成功数量
+	Failed   int `json:"failed"`   // 执行失败数量
+}
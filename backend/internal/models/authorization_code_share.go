@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 分享方式
+const (
+	AuthorizationCodeShareModeDirect = "direct" // 直接分享，立即转移席位
+	AuthorizationCodeShareModeInvite = "invite" // 邀请分享，受赠方接受后才转移席位
+)
+
+// AuthorizationCodeShareStatus 分享状态
+type AuthorizationCodeShareStatus string
+
+const (
+	AuthorizationCodeShareStatusPending   AuthorizationCodeShareStatus = "pending"   // 待受赠方确认
+	AuthorizationCodeShareStatusAccepted  AuthorizationCodeShareStatus = "accepted"  // 已生效（直接分享或已接受邀请）
+	AuthorizationCodeShareStatusDeclined  AuthorizationCodeShareStatus = "declined"  // 受赠方已拒绝
+	AuthorizationCodeShareStatusCancelled AuthorizationCodeShareStatus = "cancelled" // 分享方已取消邀请
+)
+
+// 分享相关通知类型
+const (
+	NotificationTypeShareInvitation = "share_invitation" // 收到授权码分享邀请
+	NotificationTypeShareAccepted   = "share_accepted"   // 分享邀请已被接受
+	NotificationTypeShareDeclined   = "share_declined"   // 分享邀请已被拒绝
+	NotificationTypeShareReclaimed  = "share_reclaimed"  // 分享的席位已被收回
+)
+
+// AuthorizationCodeShare 授权码分享记录，关联原授权码（父）与分享生成的授权码（子）
+type AuthorizationCodeShare struct {
+	ID               string     `gorm:"type:varchar(36);primaryKey" json:"id"`                            // 分享记录ID
+	SourceCodeID     string     `gorm:"type:varchar(36);not null;index" json:"source_code_id"`            // 原授权码ID
+	TargetCodeID     *string    `gorm:"type:varchar(36);index" json:"target_code_id"`                     // 分享生成的授权码ID（邀请未接受时为空）
+	SourceCustomerID string     `gorm:"type:varchar(36);not null" json:"source_customer_id"`              // 分享方客户ID
+	SourceUserID     string     `gorm:"type:varchar(36);not null;index" json:"source_user_id"`            // 分享方用户ID
+	TargetCustomerID string     `gorm:"type:varchar(36);not null" json:"target_customer_id"`              // 受赠方客户ID
+	TargetUserID     string     `gorm:"type:varchar(36);not null;index" json:"target_user_id"`            // 受赠方用户ID
+	ShareCount       int        `gorm:"not null" json:"share_count"`                                      // 分享席位数
+	ReclaimedCount   int        `gorm:"not null;default:0" json:"reclaimed_count"`                        // 已收回席位数
+	Mode             string     `gorm:"type:varchar(20);not null;default:'direct'" json:"mode"`           // 分享方式：direct/invite
+	Status           string     `gorm:"type:varchar(20);not null;default:'accepted';index" json:"status"` // 状态：pending/accepted/declined/cancelled
+	StatusDisplay    string     `gorm:"-" json:"status_display,omitempty"`                                // 状态显示（多语言）
+	RespondedAt      *time.Time `gorm:"type:datetime(3)" json:"responded_at"`                             // 受赠方响应/取消时间
+	LastReclaimedAt  *time.Time `gorm:"type:datetime(3)" json:"last_reclaimed_at"`                        // 最近一次收回时间
+	CreatedAt        time.Time  `gorm:"type:datetime(3);not null;index" json:"created_at"`                // 分享时间
+	UpdatedAt        time.Time  `gorm:"type:datetime(3);not null" json:"updated_at"`                      // 更新时间
+	SourceCode       string     `gorm:"-" json:"source_code,omitempty"`                                   // 原授权码
+	TargetCode       string     `gorm:"-" json:"target_code,omitempty"`                                   // 分享生成的授权码
+}
+
+// TableName 指定表名
+func (AuthorizationCodeShare) TableName() string {
+	return "authorization_code_shares"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (s *AuthorizationCodeShare) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = now
+	}
+	if s.UpdatedAt.IsZero() {
+		s.UpdatedAt = now
+	}
+	return nil
+}
+
+// AuthorizationCodeShareListRequest 分享记录查询请求
+type AuthorizationCodeShareListRequest struct {
+	Page      int    `form:"page" binding:"omitempty,min=1"`                                       // 页码，默认1
+	PageSize  int    `form:"page_size" binding:"omitempty,min=1,max=100"`                          // 每页条数，默认20，最大100
+	Direction string `form:"direction" binding:"omitempty,oneof=sent received"`                    // 方向：sent-我分享的，received-我收到的，为空返回全部
+	Status    string `form:"status" binding:"omitempty,oneof=pending accepted declined cancelled"` // 状态筛选
+	CodeID    string `form:"code_id" binding:"omitempty"`                                          // 授权码ID（原授权码或分享生成的授权码）
+}
+
+// AuthorizationCodeShareListResponse 分享记录列表响应
+type AuthorizationCodeShareListResponse struct {
+	List       []*AuthorizationCodeShare `json:"list"`        // 分享记录列表
+	Total      int64                     `json:"total"`       // 总记录数
+	Page       int                       `json:"page"`        // 当前页码
+	PageSize   int                       `json:"page_size"`   // 每页条数
+	TotalPages int                       `json:"total_pages"` // 总页数
+}
+
+// AuthorizationCodeShareReclaimRequest 收回分享席位请求
+type AuthorizationCodeShareReclaimRequest struct {
+	Count *int `json:"count" binding:"omitempty,min=1"` // 收回席位数，为空时收回全部未使用席位
+}
@@ -10,15 +10,16 @@ type CuAuthorizationCodeListRequest struct {
 
 // CuAuthorizationCodeListItem 用户端授权码列表项
 type CuAuthorizationCodeListItem struct {
-	ID                   string `json:"id"`                       // 授权码ID
-	Code                 string `json:"code"`                     // 授权码
-	Status               string `json:"status"`                   // 状态
-	StatusDisplay        string `json:"status_display,omitempty"` // 状态显示
-	MaxActivations       int    `json:"max_activations"`          // 最大激活数量
-	CurrentActivations   int    `json:"current_activations"`      // 当前激活数量
-	RemainingActivations int    `json:"remaining_activations"`    // 剩余激活数量
-	CreatedAt            string `json:"created_at"`               // 创建时间
-	EndDate              string `json:"end_date"`                 // 到期时间
+	ID                   string  `json:"id"`                       // 授权码ID
+	Code                 string  `json:"code"`                     // 授权码
+	Status               string  `json:"status"`                   // 状态
+	StatusDisplay        string  `json:"status_display,omitempty"` // 状态显示
+	MaxActivations       int     `json:"max_activations"`          // 最大激活数量
+	CurrentActivations   int     `json:"current_activations"`      // 当前激活数量
+	RemainingActivations int     `json:"remaining_activations"`    // 剩余激活数量
+	CreatedAt            string  `json:"created_at"`               // 创建时间
+	EndDate              string  `json:"end_date"`                 // 到期时间
+	MaintenanceUntil     *string `json:"maintenance_until"`        // 维护截止时间，为空表示不限制可用版本
 }
 
 // CuAuthorizationCodeListResponse 用户端授权码列表响应
@@ -32,8 +33,9 @@ type CuAuthorizationCodeListResponse struct {
 
 // CuAuthorizationCodeSummaryResponse 用户端授权信息统计响应
 type CuAuthorizationCodeSummaryResponse struct {
-	TotalCount             int64 `json:"total_count"`               // 总授权码数量
-	ExpiredCount           int64 `json:"expired_count"`             // 已过期授权码数量
-	ValidCount             int64 `json:"valid_count"`               // 有效授权码数量
-	ValidMaxActivationsSum int64 `json:"valid_max_activations_sum"` // 有效授权码的 `max_activations` 之和
+	TotalCount             int64             `json:"total_count"`               // 总授权码数量
+	ExpiredCount           int64             `json:"expired_count"`             // 已过期授权码数量
+	ValidCount             int64             `json:"valid_count"`               // 有效授权码数量
+	ValidMaxActivationsSum int64             `json:"valid_max_activations_sum"` // 有效授权码的 `max_activations` 之和
+	SeatPool               *CustomerSeatPool `json:"seat_pool,omitempty"`       // 客户席位池及使用情况，未设置时不返回
 }
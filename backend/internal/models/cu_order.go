@@ -21,6 +21,8 @@ type CuOrder struct {
 	TotalAmount       float64        `gorm:"type:decimal(10,2);not null" json:"total_amount"`
 	Status            string         `gorm:"type:varchar(20);not null;default:'paid'" json:"status"`
 	AuthorizationCode *string        `gorm:"type:varchar(500)" json:"authorization_code"`
+	OrderType         string         `gorm:"type:varchar(30);not null;default:'new'" json:"order_type"`
+	RenewalCodeID     *string        `gorm:"type:varchar(36);index" json:"renewal_code_id"`
 	ExpiredAt         *time.Time     `gorm:"index" json:"expired_at"`
 	CreatedAt         time.Time      `gorm:"not null;index" json:"created_at"`
 	UpdatedAt         time.Time      `gorm:"not null" json:"updated_at"`
@@ -63,10 +65,20 @@ func (o *CuOrder) BeforeUpdate(tx *gorm.DB) error {
 // CuOrderCreateRequest 创建订单请求结构
 type CuOrderCreateRequest struct {
 	PackageID     string `json:"package_id" binding:"required"`
-	LicenseCount  int    `json:"license_count" binding:"required,min=1,max=1000"`
-	PaymentMethod string `json:"payment_method,omitempty"` // 可选：支付方式，不传则为免费订单 支持：alipay，wechat
+	LicenseCount  int    `json:"license_count" binding:"omitempty,min=1,max=1000"` // 许可数量，新购必填；续订维护按授权码席位数计算
+	PaymentMethod string `json:"payment_method,omitempty"`                         // 可选：支付方式，不传则为免费订单 支持：alipay，wechat
+
+	// 续订维护：只顺延已有授权码的维护截止时间，不生成新授权码
+	OrderType           string  `json:"order_type,omitempty" binding:"omitempty,oneof=new maintenance_renewal"` // 订单类型：new-新购（默认），maintenance_renewal-续订维护
+	AuthorizationCodeID *string `json:"authorization_code_id,omitempty"`                                        // 续订维护的授权码ID，order_type=maintenance_renewal 时必填
 }
 
+// 订单类型
+const (
+	CuOrderTypeNew                = "new"                 // 新购
+	CuOrderTypeMaintenanceRenewal = "maintenance_renewal" // 续订维护
+)
+
 // CuOrderListRequest 订单列表查询请求结构
 type CuOrderListRequest struct {
 	Page     int    `form:"page" binding:"omitempty,min=1"`              // 页码，默认1
@@ -90,6 +102,8 @@ type CuOrderResponse struct {
 	TotalAmount       float64    `json:"total_amount"`       // 订单总金额，单位元
 	Status            string     `json:"status"`             // 订单状态，pending-待支付/paid-已支付
 	AuthorizationCode *string    `json:"authorization_code"` // 授权码，已支付订单生成
+	OrderType         string     `json:"order_type"`         // 订单类型：new-新购，maintenance_renewal-续订维护
+	RenewalCodeID     *string    `json:"renewal_code_id"`    // 续订维护的授权码ID
 	ExpiredAt         *time.Time `json:"expired_at"`         // 订单过期时间
 	CreatedAt         time.Time  `json:"created_at"`         // 创建时间
 	UpdatedAt         time.Time  `json:"updated_at"`         // 更新时间
@@ -113,6 +127,8 @@ func (o *CuOrder) ToResponse() *CuOrderResponse {
 		TotalAmount:       o.TotalAmount,
 		Status:            o.Status,
 		AuthorizationCode: o.AuthorizationCode,
+		OrderType:         o.OrderType,
+		RenewalCodeID:     o.RenewalCodeID,
 		ExpiredAt:         o.ExpiredAt,
 		CreatedAt:         o.CreatedAt,
 		UpdatedAt:         o.UpdatedAt,
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 可挂载自定义字段和标签的对象类型
+const (
+	CustomFieldEntityCustomer          = "customer"           // 客户
+	CustomFieldEntityAuthorizationCode = "authorization_code" // 授权码
+	CustomFieldEntityLicense           = "license"            // 许可证
+)
+
+// 自定义字段类型
+const (
+	CustomFieldTypeText    = "text"    // 文本，可用正则表达式校验
+	CustomFieldTypeNumber  = "number"  // 数字
+	CustomFieldTypeBoolean = "boolean" // 布尔值（true/false）
+	CustomFieldTypeDate    = "date"    // 日期（YYYY-MM-DD）
+	CustomFieldTypeSelect  = "select"  // 单选，取值必须为预设选项之一
+)
+
+// CustomFieldDefinition 管理员定义的自定义字段
+// 字段值统一以字符串保存，按字段类型校验并规范化（数字去除多余的零、布尔值为 true/false、日期为 YYYY-MM-DD）
+type CustomFieldDefinition struct {
+	ID                string    `gorm:"type:varchar(36);primaryKey" json:"id"`                                                                      // 字段ID
+	EntityType        string    `gorm:"type:varchar(20);not null;uniqueIndex:uk_custom_field_definitions_entity_key,priority:1" json:"entity_type"` // 对象类型：customer/authorization_code/license
+	EntityTypeDisplay string    `gorm:"-" json:"entity_type_display,omitempty"`                                                                     // 对象类型显示（多语言）
+	FieldKey          string    `gorm:"type:varchar(64);not null;uniqueIndex:uk_custom_field_definitions_entity_key,priority:2" json:"field_key"`   // 字段标识，同一对象类型内唯一，创建后不可修改
+	Name              string    `gorm:"type:varchar(100);not null" json:"name"`                                                                     // 字段名称
+	FieldType         string    `gorm:"type:varchar(20);not null" json:"field_type"`                                                                // 字段类型：text/number/boolean/date/select
+	FieldTypeDisplay  string    `gorm:"-" json:"field_type_display,omitempty"`                                                                      // 字段类型显示（多语言）
+	Options           JSON      `gorm:"type:json" json:"options,omitempty" swaggertype:"array,string"`                                              // 单选字段的可选值
+	Required          bool      `gorm:"not null;default:false" json:"required"`                                                                     // 是否必填（创建对象时必须提供，且不能清空）
+	Pattern           *string   `gorm:"type:varchar(200)" json:"pattern"`                                                                           // 文本字段的校验正则表达式
+	EmbedInLicense    bool      `gorm:"not null;default:false" json:"embed_in_license"`                                                             // 是否写入签名许可证文件（仅授权码字段）
+	SortOrder         int       `gorm:"not null;default:0" json:"sort_order"`                                                                       // 排序，越小越靠前
+	Description       *string   `gorm:"type:varchar(500)" json:"description"`                                                                       // 字段说明
+	CreatedBy         string    `gorm:"type:varchar(36);not null" json:"created_by"`                                                                // 创建人ID
+	CreatedAt         time.Time `gorm:"type:datetime(3);not null" json:"created_at"`                                                                // 创建时间
+	UpdatedAt         time.Time `gorm:"type:datetime(3);not null" json:"updated_at"`                                                                // 更新时间
+}
+
+// TableName 指定表名
+func (CustomFieldDefinition) TableName() string {
+	return "custom_field_definitions"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (d *CustomFieldDefinition) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = now
+	}
+	if d.UpdatedAt.IsZero() {
+		d.UpdatedAt = now
+	}
+	return nil
+}
+
+// BeforeUpdate 更新前自动刷新更新时间
+func (d *CustomFieldDefinition) BeforeUpdate(tx *gorm.DB) error {
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+// CustomFieldValue 对象的自定义字段值
+type CustomFieldValue struct {
+	EntityType string    `gorm:"type:varchar(20);primaryKey;index:idx_custom_field_values_lookup,priority:1" json:"entity_type"` // 对象类型
+	EntityID   string    `gorm:"type:varchar(36);primaryKey" json:"entity_id"`                                                   // 对象ID
+	FieldKey   string    `gorm:"type:varchar(64);primaryKey;index:idx_custom_field_values_lookup,priority:2" json:"field_key"`   // 字段标识
+	Value      string    `gorm:"type:varchar(500);not null;index:idx_custom_field_values_lookup,priority:3" json:"value"`        // 字段值（规范化后的字符串）
+	UpdatedAt  time.Time `gorm:"type:datetime(3);not null" json:"updated_at"`                                                    // 更新时间
+}
+
+// TableName 指定表名
+func (CustomFieldValue) TableName() string {
+	return "custom_field_values"
+}
+
+// EntityTag 对象的标签
+type EntityTag struct {
+	EntityType string    `gorm:"type:varchar(20);primaryKey;index:idx_entity_tags_tag,priority:1" json:"entity_type"` // 对象类型
+	EntityID   string    `gorm:"type:varchar(36);primaryKey" json:"entity_id"`                                        // 对象ID
+	Tag        string    `gorm:"type:varchar(50);primaryKey;index:idx_entity_tags_tag,priority:2" json:"tag"`         // 标签
+	CreatedAt  time.Time `gorm:"type:datetime(3);not null" json:"created_at"`                                         // 添加时间
+}
+
+// TableName 指定表名
+func (EntityTag) TableName() string {
+	return "entity_tags"
+}
+
+// CustomFieldDefinitionCreateRequest 创建自定义字段请求
+type CustomFieldDefinitionCreateRequest struct {
+	EntityType     string   `json:"entity_type" binding:"required,oneof=customer authorization_code license"` // 对象类型
+	FieldKey       string   `json:"field_key" binding:"required,max=64"`                                      // 字段标识，小写字母开头，只能包含小写字母、数字和下划线
+	Name           string   `json:"name" binding:"required,max=100"`                                          // 字段名称
+	FieldType      string   `json:"field_type" binding:"required,oneof=text number boolean date select"`      // 字段类型
+	Options        []string `json:"options" binding:"omitempty,max=100,dive,required,max=100"`                // 单选字段的可选值，单选字段必填
+	Required       bool     `json:"required"`                                                                 // 是否必填
+	Pattern        *string  `json:"pattern" binding:"omitempty,max=200"`                                      // 文本字段的校验正则表达式
+	EmbedInLicense bool     `json:"embed_in_license"`                                                         // 是否写入签名许可证文件（仅授权码字段）
+	SortOrder      int      `json:"sort_order"`                                                               // 排序，越小越靠前
+	Description    *string  `json:"description" binding:"omitempty,max=500"`                                  // 字段说明
+}
+
+// CustomFieldDefinitionUpdateRequest 更新自定义字段请求（对象类型、字段标识和字段类型不可修改，修改校验规则不影响已保存的值）
+type CustomFieldDefinitionUpdateRequest struct {
+	Name           *string  `json:"name" binding:"omitempty,max=100"`                          // 字段名称
+	Options        []string `json:"options" binding:"omitempty,max=100,dive,required,max=100"` // 单选字段的可选值
+	Required       *bool    `json:"required"`                                                  // 是否必填
+	Pattern        *string  `json:"pattern" binding:"omitempty,max=200"`                       // 文本字段的校验正则表达式，传空字符串表示取消
+	EmbedInLicense *bool    `json:"embed_in_license"`                                          // 是否写入签名许可证文件（仅授权码字段）
+	SortOrder      *int     `json:"sort_order"`                                                // 排序
+	Description    *string  `json:"description" binding:"omitempty,max=500"`                   // 字段说明
+}
+
+// CustomFieldDefinitionListRequest 自定义字段列表请求
+type CustomFieldDefinitionListRequest struct {
+	EntityType string `form:"entity_type" binding:"omitempty,oneof=customer authorization_code license"` // 对象类型筛选
+}
+
+// EntityCustomFieldsUpdateRequest 设置对象的自定义字段和标签
+type EntityCustomFieldsUpdateRequest struct {
+	CustomFields map[string]string `json:"custom_fields"`                                        // 字段标识 -> 值，只修改传入的字段，值为空字符串表示清除
+	Tags         []string          `json:"tags" binding:"omitempty,max=20,dive,required,max=50"` // 标签，传入时整体替换，传空数组表示清除全部标签
+}
+
+// EntityCustomFields 对象的自定义字段和标签
+type EntityCustomFields struct {
+	CustomFields map[string]string `json:"custom_fields"` // 字段标识 -> 值
+	Tags         []string          `json:"tags"`          // 标签
+}
+
+// CustomFieldFilter 列表接口按标签和自定义字段筛选的条件
+type CustomFieldFilter struct {
+	Tags         []string          `form:"tags" binding:"omitempty,max=10,dive,max=50"` // 标签筛选，可重复传递，需同时具有全部标签
+	FieldSearch  string            `form:"field_search" binding:"omitempty,max=100"`    // 模糊匹配标签和自定义字段值
+	CustomFields map[string]string `form:"-"`                                           // 自定义字段精确筛选，查询参数格式 custom_fields[字段标识]=值（由处理器解析）
+}
+
+// TagListRequest 标签列表请求
+type TagListRequest struct {
+	EntityType string `form:"entity_type" binding:"required,oneof=customer authorization_code license"` // 对象类型
+	Search     string `form:"search" binding:"omitempty,max=50"`                                        // 标签前缀匹配
+}
+
+// TagCount 标签及使用次数
+type TagCount struct {
+	Tag   string `json:"tag"`   // 标签
+	Count int64  `json:"count"` // 使用该标签的对象数量
+}
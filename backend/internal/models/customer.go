@@ -12,6 +12,9 @@ type Customer struct {
 	ID                   string         `gorm:"type:varchar(36);primaryKey" json:"id"`
 	CustomerCode         string         `gorm:"type:varchar(20);uniqueIndex;not null" json:"customer_code"`
 	CustomerName         string         `gorm:"type:varchar(200);not null;index" json:"customer_name"`
+	ParentID             *string        `gorm:"type:varchar(36);index" json:"parent_id"`
+	ParentName           string         `gorm:"-" json:"parent_name,omitempty"`
+	PartnerID            *string        `gorm:"type:varchar(36);index" json:"partner_id"` // 所属经销商（经销商账号ID），为空表示直营客户
 	CustomerType         string         `gorm:"type:varchar(20);not null;default:'enterprise';index" json:"customer_type"`
 	CustomerTypeDisplay  string         `gorm:"-" json:"customer_type_display,omitempty"`
 	ContactPerson        string         `gorm:"type:varchar(100);not null" json:"contact_person"`
@@ -32,8 +35,13 @@ type Customer struct {
 	UpdatedBy            *string        `gorm:"type:varchar(36)" json:"updated_by"`
 	DeletedAt            gorm.DeletedAt `gorm:"index" json:"-"`
 
-	// 授权统计信息（仅在详情接口返回）
-	AuthorizationStats *AuthorizationStats `gorm:"-" json:"authorization_stats,omitempty"`
+	// 授权统计信息（仅在详情接口返回），包含全部下级客户
+	AuthorizationStats    *AuthorizationStats `gorm:"-" json:"authorization_stats,omitempty"`
+	OwnAuthorizationStats *AuthorizationStats `gorm:"-" json:"own_authorization_stats,omitempty"` // 本客户自身的授权统计（有下级客户时返回）
+	SubsidiaryCount       int                 `gorm:"-" json:"subsidiary_count"`                  // 直接下级客户数量
+	CustomFields          map[string]string   `gorm:"-" json:"custom_fields,omitempty"`           // 自定义字段（字段标识 -> 值）
+	Tags                  []string            `gorm:"-" json:"tags,omitempty"`                    // 标签
+	SeatPool              *CustomerSeatPool   `gorm:"-" json:"seat_pool,omitempty"`               // 客户席位池及使用情况（仅在详情接口返回，未设置时不返回）
 }
 
 // CustomerCodeSequence 客户编码序列模型
@@ -86,8 +94,15 @@ type CustomerListRequest struct {
 	CustomerType  string `form:"customer_type" binding:"omitempty,oneof=individual enterprise government education"` // 客户类型筛选
 	CustomerLevel string `form:"customer_level" binding:"omitempty"`                                                 // 客户等级筛选
 	Status        string `form:"status" binding:"omitempty,oneof=active disabled"`                                   // 状态筛选
+	ParentID      string `form:"parent_id" binding:"omitempty,max=36"`                                               // 上级客户筛选，默认只返回直接下级
 	Sort          string `form:"sort" binding:"omitempty,oneof=created_at updated_at customer_name customer_code"`   // 排序字段，默认created_at
 	Order         string `form:"order" binding:"omitempty,oneof=asc desc"`                                           // 排序方向，默认desc
+
+	PartnerID           string   `form:"partner_id" binding:"omitempty,max=36"` // 所属经销商筛选（经销商账号只能查看自己名下的客户）
+	IncludeSubsidiaries bool     `form:"include_subsidiaries"`                  // 按上级客户筛选时包含所有层级的下级客户
+	CustomerIDs         []string `form:"-"`                                     // 限定客户ID范围（由服务层按层级关系展开）
+
+	CustomFieldFilter
 }
 
 // CustomerListItem 客户列表项结构（用于列表展示，包含主要字段）
@@ -95,6 +110,8 @@ type CustomerListItem struct {
 	ID                   string  `json:"id"`
 	CustomerCode         string  `json:"customer_code"`
 	CustomerName         string  `json:"customer_name"`
+	ParentID             *string `json:"parent_id"`
+	PartnerID            *string `json:"partner_id"`
 	CustomerType         string  `json:"customer_type"`
 	CustomerTypeDisplay  string  `json:"customer_type_display,omitempty"`
 	ContactPerson        string  `json:"contact_person"`
@@ -104,6 +121,11 @@ type CustomerListItem struct {
 	Status               string  `json:"status"`
 	StatusDisplay        string  `json:"status_display,omitempty"`
 	CreatedAt            string  `json:"created_at"`
+
+	SubsidiaryCount    int                 `json:"subsidiary_count"`              // 直接下级客户数量
+	AuthorizationStats *AuthorizationStats `json:"authorization_stats,omitempty"` // 授权统计（包含全部下级客户）
+	CustomFields       map[string]string   `json:"custom_fields,omitempty"`       // 自定义字段（字段标识 -> 值）
+	Tags               []string            `json:"tags,omitempty"`                // 标签
 }
 
 // CustomerListResponse 客户列表响应结构
@@ -118,6 +140,8 @@ type CustomerListResponse struct {
 // CustomerCreateRequest 创建客户请求结构
 type CustomerCreateRequest struct {
 	CustomerName  string  `json:"customer_name" binding:"required,max=200"`                                          // 客户名称，必填
+	ParentID      *string `json:"parent_id" binding:"omitempty,max=36"`                                              // 上级客户ID，可选
+	PartnerID     *string `json:"partner_id" binding:"omitempty,max=36"`                                             // 所属经销商ID，可选（经销商账号创建时固定为自身）
 	CustomerType  string  `json:"customer_type" binding:"required,oneof=individual enterprise government education"` // 客户类型，必填
 	ContactPerson string  `json:"contact_person" binding:"required,max=100"`                                         // 联系人姓名，必填
 	ContactTitle  *string `json:"contact_title" binding:"omitempty,max=100"`                                         // 联系人职位，可选
@@ -128,11 +152,16 @@ type CustomerCreateRequest struct {
 	CustomerLevel string  `json:"customer_level" binding:"required"`                                                 // 客户等级，必填
 	Status        string  `json:"status" binding:"required,oneof=active disabled"`                                   // 状态，必填
 	Description   *string `json:"description" binding:"omitempty,max=1000"`                                          // 描述，可选
+
+	CustomFields map[string]string `json:"custom_fields"`                                        // 自定义字段（字段标识 -> 值），必填字段必须提供
+	Tags         []string          `json:"tags" binding:"omitempty,max=20,dive,required,max=50"` // 标签，可选
 }
 
 // CustomerUpdateRequest 更新客户请求结构（所有字段都是可选的）
 type CustomerUpdateRequest struct {
 	CustomerName  *string `json:"customer_name" binding:"omitempty,max=200"`                                          // 客户名称，可选
+	ParentID      *string `json:"parent_id" binding:"omitempty,max=36"`                                               // 上级客户ID，可选，传空字符串表示取消上级
+	PartnerID     *string `json:"partner_id" binding:"omitempty,max=36"`                                              // 所属经销商ID，可选，传空字符串表示转为直营（经销商账号不可修改）
 	CustomerType  *string `json:"customer_type" binding:"omitempty,oneof=individual enterprise government education"` // 客户类型，可选
 	ContactPerson *string `json:"contact_person" binding:"omitempty,max=100"`                                         // 联系人姓名，可选
 	ContactTitle  *string `json:"contact_title" binding:"omitempty,max=100"`                                          // 联系人职位，可选
@@ -160,3 +189,19 @@ type AuthorizationStats struct {
 	InactiveLicenses      int64 `json:"inactive_licenses"`        // 未激活许可证数量
 	ExpiredLicenses       int64 `json:"expired_licenses"`         // 已过期许可证数量
 }
+
+// CustomerHierarchyNode 客户层级树节点
+type CustomerHierarchyNode struct {
+	ID            string                   `json:"id"`                       // 客户ID
+	CustomerCode  string                   `json:"customer_code"`            // 客户编码
+	CustomerName  string                   `json:"customer_name"`            // 客户名称
+	Status        string                   `json:"status"`                   // 状态
+	StatusDisplay string                   `json:"status_display,omitempty"` // 状态显示
+	Children      []*CustomerHierarchyNode `json:"children,omitempty"`       // 下级客户
+}
+
+// CustomerHierarchyResponse 客户层级关系响应
+type CustomerHierarchyResponse struct {
+	Ancestors []*CustomerHierarchyNode `json:"ancestors"` // 上级客户路径，从最顶层开始
+	Tree      *CustomerHierarchyNode   `json:"tree"`      // 以该客户为根的下级客户树
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CustomerImportJobStatus 客户导入任务状态
+type CustomerImportJobStatus string
+
+const (
+	CustomerImportJobStatusPending   CustomerImportJobStatus = "pending"   // 等待导入
+	CustomerImportJobStatusRunning   CustomerImportJobStatus = "running"   // 导入中
+	CustomerImportJobStatusCompleted CustomerImportJobStatus = "completed" // 已完成（可能有部分行失败）
+	CustomerImportJobStatusFailed    CustomerImportJobStatus = "failed"    // 导入失败
+)
+
+// 客户导入匹配已有客户的方式
+const (
+	CustomerImportUpsertByCode  = "customer_code" // 按客户编码更新已有客户，编码为空的行新建客户
+	CustomerImportUpsertByEmail = "email"         // 按邮箱更新已有客户，未匹配的行新建客户
+)
+
+// 客户导入导出文件格式
+const (
+	CustomerFileFormatCSV  = "csv"
+	CustomerFileFormatXLSX = "xlsx"
+)
+
+// CustomerFileColumns 客户导入导出文件的列（导出文件可直接修改后重新导入）
+var CustomerFileColumns = []string{
+	"customer_code",
+	"customer_name",
+	"customer_type",
+	"contact_person",
+	"contact_title",
+	"email",
+	"phone",
+	"address",
+	"company_size",
+	"customer_level",
+	"status",
+	"description",
+}
+
+// CustomerImportJob 客户批量导入任务
+type CustomerImportJob struct {
+	ID             string                   `gorm:"type:varchar(36);primaryKey" json:"id"`                           // 任务ID
+	FileName       string                   `gorm:"type:varchar(255);not null" json:"file_name"`                     // 上传的文件名
+	FileFormat     string                   `gorm:"type:varchar(10);not null" json:"file_format"`                    // 文件格式：csv/xlsx
+	UpsertKey      string                   `gorm:"type:varchar(20);not null" json:"upsert_key"`                     // 匹配已有客户的方式：customer_code/email
+	Status         string                   `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // 状态：pending/running/completed/failed
+	StatusDisplay  string                   `gorm:"-" json:"status_display,omitempty"`                               // 状态显示（多语言）
+	TotalRows      int                      `gorm:"not null;default:0" json:"total_rows"`                            // 数据行数（不含表头）
+	ProcessedRows  int                      `gorm:"not null;default:0" json:"processed_rows"`                        // 已处理行数
+	CreatedCount   int                      `gorm:"not null;default:0" json:"created_count"`                         // 新建客户数
+	UpdatedCount   int                      `gorm:"not null;default:0" json:"updated_count"`                         // 更新客户数
+	FailedCount    int                      `gorm:"not null;default:0" json:"failed_count"`                          // 失败行数
+	Rows           JSON                     `gorm:"type:json" json:"-"`                                              // 待导入的数据行（解析后的快照，重启后续跑使用）
+	ErrorReport    JSON                     `gorm:"type:json" json:"-"`                                              // 逐行错误报告
+	ErrorMessage   *string                  `gorm:"type:text" json:"error_message"`                                  // 任务失败原因
+	CreatedBy      string                   `gorm:"type:varchar(36);not null" json:"created_by"`                     // 创建人ID
+	CompletedAt    *time.Time               `gorm:"type:datetime(3)" json:"completed_at"`                            // 完成时间
+	LeaseOwner     *string                  `gorm:"type:varchar(36)" json:"-"`                                       // 执行租约持有者（多实例下领取任务的标识）
+	LeaseExpiresAt *time.Time               `gorm:"type:datetime(3)" json:"-"`                                       // 执行租约到期时间，过期后其他实例可接管续跑
+	CreatedAt      time.Time                `gorm:"type:datetime(3);not null;index" json:"created_at"`               // 创建时间
+	UpdatedAt      time.Time                `gorm:"type:datetime(3);not null" json:"updated_at"`                     // 更新时间
+	Errors         []CustomerImportRowError `gorm:"-" json:"errors,omitempty"`                                       // 逐行错误（详情接口返回）
+}
+
+// TableName 指定表名
+func (CustomerImportJob) TableName() string {
+	return "customer_import_jobs"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (j *CustomerImportJob) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == "" {
+		j.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if j.CreatedAt.IsZero() {
+		j.CreatedAt = now
+	}
+	if j.UpdatedAt.IsZero() {
+		j.UpdatedAt = now
+	}
+	return nil
+}
+
+// CustomerImportRowError 导入失败的行
+type CustomerImportRowError struct {
+	Row          int      `json:"row"`                     // 文件中的行号（表头为第1行）
+	CustomerCode string   `json:"customer_code,omitempty"` // 客户编码
+	CustomerName string   `json:"customer_name,omitempty"` // 客户名称
+	Email        string   `json:"email,omitempty"`         // 邮箱
+	Errors       []string `json:"errors"`                  // 错误原因
+}
+
+// CustomerImportRequest 客户导入请求（multipart 表单，文件字段为 file）
+type CustomerImportRequest struct {
+	UpsertKey string `form:"upsert_key" binding:"omitempty,oneof=customer_code email"` // 匹配已有客户的方式，默认customer_code
+}
+
+// CustomerExportRequest 客户导出请求，筛选条件与客户列表一致
+type CustomerExportRequest struct {
+	CustomerListRequest
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx"` // 导出格式，默认csv
+}
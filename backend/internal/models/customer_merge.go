@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CustomerMerge 客户合并审计记录，只追加不修改
+type CustomerMerge struct {
+	ID                string    `gorm:"type:varchar(36);primaryKey" json:"id"`                                    // 合并记录ID
+	TargetCustomerID  string    `gorm:"type:varchar(36);not null;index" json:"target_customer_id"`                // 目标客户ID
+	SourceCustomerIDs JSON      `gorm:"type:json;not null" json:"source_customer_ids" swaggertype:"array,string"` // 来源客户ID列表
+	SourceCustomers   JSON      `gorm:"type:json" json:"source_customers" swaggertype:"object"`                   // 合并前的来源客户快照
+	MovedCounts       JSON      `gorm:"type:json" json:"moved_counts" swaggertype:"object"`                       // 各类数据迁移数量
+	OperatorID        string    `gorm:"type:varchar(36);not null;index" json:"operator_id"`                       // 操作人ID
+	OperatorName      string    `gorm:"->;-:migration" json:"operator_name,omitempty"`                            // 操作人姓名（查询时关联）
+	Reason            *string   `gorm:"type:varchar(500)" json:"reason"`                                          // 合并原因
+	CreatedAt         time.Time `gorm:"type:datetime(3);not null;index" json:"created_at"`                        // 合并时间
+}
+
+// TableName 指定表名
+func (CustomerMerge) TableName() string {
+	return "customer_merges"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (m *CustomerMerge) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// CustomerMergeRequest 合并客户请求
+// 来源客户的授权码、许可证、客户用户、订单、支付、发票等数据迁移到目标客户，来源客户随后被删除
+type CustomerMergeRequest struct {
+	TargetID  string   `json:"target_id" binding:"required,max=36"`                      // 保留的目标客户ID
+	SourceIDs []string `json:"source_ids" binding:"required,min=1,max=20,dive,required"` // 并入的客户ID列表
+	Reason    *string  `json:"reason" binding:"omitempty,max=500"`                       // 合并原因（记录到审计记录）
+	DryRun    bool     `json:"dry_run"`                                                  // 仅预览将迁移的数据，不实际合并
+}
+
+// CustomerMergeResponse 合并客户响应
+// moved 的键为数据类型（authorization_codes、licenses、cu_users、cu_orders、payments、invoices、subsidiaries 等），值为迁移数量
+type CustomerMergeResponse struct {
+	DryRun  bool             `json:"dry_run"`            // 是否为预览
+	MergeID string           `json:"merge_id,omitempty"` // 合并审计记录ID（实际合并时返回）
+	Target  *Customer        `json:"target"`             // 目标客户
+	Sources []*Customer      `json:"sources"`            // 来源客户
+	Moved   map[string]int64 `json:"moved"`              // 各类数据迁移数量（预览时为将迁移的数量）
+}
+
+// CustomerMergeListRequest 客户合并记录列表请求
+type CustomerMergeListRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`              // 页码，默认1
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"` // 每页条数，默认20，最大100
+	CustomerID string `form:"customer_id" binding:"omitempty,max=36"`      // 目标客户ID筛选
+}
+
+// CustomerMergeListResponse 客户合并记录列表响应（按时间倒序）
+type CustomerMergeListResponse struct {
+	List     []*CustomerMerge `json:"list"`
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// 客户时间线的对象类型
+const (
+	TimelineEntityCustomer            = "customer"             // 客户
+	TimelineEntityAuthorizationCode   = "authorization_code"   // 授权码
+	TimelineEntityAuthorizationChange = "authorization_change" // 授权变更
+	TimelineEntityLicense             = "license"              // 许可证
+	TimelineEntityOrder               = "order"                // 订单
+	TimelineEntityPayment             = "payment"              // 支付
+	TimelineEntityInvoice             = "invoice"              // 发票
+)
+
+// CustomerTimelineRequest 客户时间线请求
+type CustomerTimelineRequest struct {
+	Page                int      `form:"page" binding:"omitempty,min=1"`                                                                                             // 页码，默认1
+	PageSize            int      `form:"page_size" binding:"omitempty,min=1,max=100"`                                                                                // 每页条数，默认20，最大100
+	EntityTypes         []string `form:"entity_types" binding:"omitempty,dive,oneof=customer authorization_code authorization_change license order payment invoice"` // 对象类型筛选，可重复传递，默认全部
+	StartDate           string   `form:"start_date" binding:"omitempty,datetime=2006-01-02"`                                                                         // 开始日期（YYYY-MM-DD，含当天）
+	EndDate             string   `form:"end_date" binding:"omitempty,datetime=2006-01-02"`                                                                           // 结束日期（YYYY-MM-DD，含当天）
+	IncludeSubsidiaries bool     `form:"include_subsidiaries"`                                                                                                       // 是否包含所有层级的下级客户
+
+	// 以下字段由服务层设置
+	CustomerIDs []string   `form:"-" json:"-"` // 客户ID（含下级客户时为整个子树）
+	From        *time.Time `form:"-" json:"-"` // 起始时间（含）
+	To          *time.Time `form:"-" json:"-"` // 截止时间（不含）
+}
+
+// CustomerTimelineEntry 客户时间线条目
+type CustomerTimelineEntry struct {
+	EntityType        string    `json:"entity_type"`                   // 对象类型
+	EntityTypeDisplay string    `json:"entity_type_display,omitempty"` // 对象类型显示（多语言）
+	EntityID          string    `json:"entity_id"`                     // 对象ID
+	CustomerID        string    `json:"customer_id"`                   // 所属客户ID（包含下级客户时用于区分）
+	Event             string    `json:"event"`                         // 事件类型，授权变更为变更类型
+	EventDisplay      string    `json:"event_display,omitempty"`       // 事件类型显示（多语言）
+	Reference         *string   `json:"reference"`                     // 对象编号：客户名称、授权码、许可证密钥、订单号、支付单号或发票号
+	Status            *string   `json:"status"`                        // 对象当前状态（授权变更无状态）
+	StatusDisplay     string    `json:"status_display,omitempty"`      // 状态显示（多语言）
+	Amount            *float64  `json:"amount"`                        // 金额（订单、支付和发票）
+	OccurredAt        time.Time `json:"occurred_at"`                   // 发生时间
+}
+
+// CustomerTimelineResponse 客户时间线响应（按发生时间倒序）
+type CustomerTimelineResponse struct {
+	List     []*CustomerTimelineEntry `json:"list"`
+	Total    int64                    `json:"total"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"page_size"`
+}
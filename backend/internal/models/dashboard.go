@@ -69,4 +69,36 @@ type RecentAuthorization struct {
 	CurrentActivations int       `json:"current_activations"`  // 当前激活数量
 	CreatedAt          time.Time `json:"created_at"`           // 创建时间
 	UpdatedAt          time.Time `json:"updated_at"`           // 更新时间
-}
\ No newline at end of file
+}
+// DashboardLeadConversionRequest 线索转化统计查询请求
+type DashboardLeadConversionRequest struct {
+	Type      string `form:"type" binding:"required,oneof=week month custom" json:"type"` // 时间类型: week/month/custom
+	StartDate string `form:"start_date" json:"start_date,omitempty"`                      // 开始日期 (YYYY-MM-DD格式，当type为custom时必填)
+	EndDate   string `form:"end_date" json:"end_date,omitempty"`                          // 结束日期 (YYYY-MM-DD格式，当type为custom时必填)
+	Timezone  string `form:"timezone" json:"timezone,omitempty"`                          // 时区 (如: Asia/Shanghai, UTC等，默认使用服务器本地时区)
+}
+
+// DashboardLeadConversionResponse 线索转化统计响应
+type DashboardLeadConversionResponse struct {
+	Period    TrendPeriod               `json:"period"`     // 时间段信息
+	TrendData []LeadConversionTrendData `json:"trend_data"` // 每日线索及转化数据
+	Summary   LeadConversionSummary     `json:"summary"`    // 汇总信息
+}
+
+// LeadConversionTrendData 单日线索转化数据
+type LeadConversionTrendData struct {
+	Date           string `json:"date"`            // 日期 (YYYY-MM-DD)
+	NewLeads       int64  `json:"new_leads"`       // 当日新增线索数
+	ConvertedLeads int64  `json:"converted_leads"` // 当日转化为客户的线索数
+}
+
+// LeadConversionSummary 线索转化汇总信息
+type LeadConversionSummary struct {
+	TotalLeads            int64   `json:"total_leads"`             // 线索总数
+	TotalConverted        int64   `json:"total_converted"`         // 累计转化为客户的线索数
+	OverallConversionRate float64 `json:"overall_conversion_rate"` // 累计转化率(百分比)
+	NewLeads              int64   `json:"new_leads"`               // 期间新增线索数
+	ConvertedLeads        int64   `json:"converted_leads"`         // 期间转化为客户的线索数
+	ConversionRate        float64 `json:"conversion_rate"`         // 期间新增线索中已转化的比例(百分比)
+	AvgConversionDays     float64 `json:"avg_conversion_days"`     // 期间转化线索从提交到转化的平均天数
+}
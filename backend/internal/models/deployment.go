@@ -0,0 +1,23 @@
+package models
+
+// 部署类型
+const (
+	DeploymentTypeStandalone = "standalone" // 单机部署：允许离线激活，离线容忍时间长
+	DeploymentTypeCloud      = "cloud"      // 云部署：必须在线激活并保持心跳，离线容忍时间短
+	DeploymentTypeHybrid     = "hybrid"     // 混合部署：设备须通过指定的本地中继激活和心跳
+)
+
+// 激活方式
+const (
+	ActivationModeOnline  = "online"  // 设备直接联网激活
+	ActivationModeOffline = "offline" // 离线激活：激活请求由其他联网设备代为提交，设备之后不发送心跳
+	ActivationModeRelay   = "relay"   // 通过本地中继激活（仅混合部署）
+)
+
+// DeploymentPolicy 按部署类型生效的许可证使用规则，随激活/心跳响应返回并写入签名的许可证文件
+type DeploymentPolicy struct {
+	DeploymentType      string `json:"deployment_type"`       // 部署类型
+	ActivationMode      string `json:"activation_mode"`       // 本设备的激活方式
+	RequiresHeartbeat   bool   `json:"requires_heartbeat"`    // 是否必须保持心跳
+	OfflineGraceSeconds int64  `json:"offline_grace_seconds"` // 最后一次成功心跳后可离线使用的时长（秒）
+}
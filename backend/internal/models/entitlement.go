@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// 授权检查结果原因
+const (
+	EntitlementReasonActive            = "active"              // 授权有效
+	EntitlementReasonLocked            = "locked"              // 授权码已锁定
+	EntitlementReasonNotStarted        = "not_started"         // 授权码尚未生效
+	EntitlementReasonExpired           = "expired"             // 授权码已过期
+	EntitlementReasonNoEntitlement     = "no_entitlement"      // 客户没有有效的授权码
+	EntitlementReasonFeatureNotEnabled = "feature_not_enabled" // 授权未包含该功能
+)
+
+// EntitlementQuotaSeats 席位配额名称（按最大激活数和当前激活数计算）
+const EntitlementQuotaSeats = "seats"
+
+// EntitlementCheckRequest 授权检查请求，授权码和客户ID二选一
+type EntitlementCheckRequest struct {
+	AuthorizationCode string `form:"authorization_code" binding:"omitempty,max=100"` // 授权码
+	CustomerID        string `form:"customer_id" binding:"omitempty,max=36"`         // 客户ID，汇总该客户所有授权码
+	Feature           string `form:"feature" binding:"omitempty,max=100"`            // 要检查的功能（feature_config 中的键），可选
+}
+
+// EntitlementQuota 配额使用情况
+type EntitlementQuota struct {
+	Name      string  `json:"name"`      // 配额名称（usage_limits 中的键，或 seats）
+	Limit     float64 `json:"limit"`     // 上限
+	Used      float64 `json:"used"`      // 已使用（按激活设备心跳上报的使用数据汇总）
+	Remaining float64 `json:"remaining"` // 剩余
+}
+
+// EntitlementCode 参与计算的授权码
+type EntitlementCode struct {
+	ID             string    `json:"id"`              // 授权码ID
+	Code           string    `json:"code"`            // 授权码
+	Status         string    `json:"status"`          // 授权状态：active/locked/not_started/expired
+	DeploymentType string    `json:"deployment_type"` // 部署类型
+	StartDate      time.Time `json:"start_date"`      // 生效日期
+	EndDate        time.Time `json:"end_date"`        // 失效日期
+	MaxActivations int       `json:"max_activations"` // 最大激活数
+	ActiveLicenses int64     `json:"active_licenses"` // 当前激活数
+}
+
+// EntitlementSnapshot 解析后的授权（缓存内容）
+type EntitlementSnapshot struct {
+	CustomerID         string                 `json:"customer_id"`         // 客户ID
+	Reason             string                 `json:"reason"`              // 授权状态
+	Features           map[string]interface{} `json:"features"`            // 合并后的功能配置
+	Limits             map[string]interface{} `json:"limits"`              // 合并后的使用限制
+	Quotas             []EntitlementQuota     `json:"quotas"`              // 配额使用情况
+	AuthorizationCodes []EntitlementCode      `json:"authorization_codes"` // 参与计算的授权码
+	ExpiresAt          *time.Time             `json:"expires_at"`          // 有效授权的最晚失效时间
+	ResolvedAt         time.Time              `json:"resolved_at"`         // 解析时间
+}
+
+// EntitlementCheckResponse 授权检查响应
+type EntitlementCheckResponse struct {
+	Allowed bool   `json:"allowed"`           // 是否允许使用（指定 feature 时同时要求该功能已启用）
+	Feature string `json:"feature,omitempty"` // 检查的功能
+	Cached  bool   `json:"cached"`            // 是否命中缓存
+	EntitlementSnapshot
+}
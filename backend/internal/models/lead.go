@@ -1,6 +1,8 @@
 package models
 
 import (
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,20 +21,39 @@ const (
 
 // Lead 企业线索
 type Lead struct {
-	ID             string     `gorm:"type:varchar(36);primaryKey" json:"id"`
-	LeadNo         string     `gorm:"type:varchar(50);not null;uniqueIndex" json:"lead_no"`
-	CompanyName    string     `gorm:"type:varchar(200);not null" json:"company_name"`
-	ContactName    string     `gorm:"type:varchar(100);not null" json:"contact_name"`
-	ContactPhone   string     `gorm:"type:varchar(20);not null" json:"contact_phone"`
-	ContactEmail   string     `gorm:"type:varchar(100)" json:"contact_email"`
-	Requirement    string     `gorm:"type:text;not null" json:"requirement"`
-	ExtraInfo      string     `gorm:"type:text" json:"extra_info"`
-	Status         string     `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
-	FollowUpDate   *time.Time `gorm:"" json:"follow_up_date"`
-	FollowUpRecord string     `gorm:"type:text" json:"follow_up_record"`
-	InternalNote   string     `gorm:"type:text" json:"internal_note"`
-	CreatedAt      time.Time  `gorm:"not null" json:"created_at"`
-	UpdatedAt      time.Time  `gorm:"not null" json:"updated_at"`
+	ID             string         `gorm:"type:varchar(36);primaryKey" json:"id"`
+	LeadNo         string         `gorm:"type:varchar(50);not null;uniqueIndex" json:"lead_no"`
+	CompanyName    string         `gorm:"type:varchar(200);not null" json:"company_name"`
+	ContactName    string         `gorm:"type:varchar(100);not null" json:"contact_name"`
+	ContactPhone   string         `gorm:"type:varchar(20);not null" json:"contact_phone"`
+	ContactEmail   string         `gorm:"type:varchar(100)" json:"contact_email"`
+	Requirement    string         `gorm:"type:text;not null" json:"requirement"`
+	ExtraInfo      string         `gorm:"type:text" json:"extra_info"`
+	Status         string         `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	FollowUpDate   *time.Time     `gorm:"" json:"follow_up_date"`
+	FollowUpRecord string         `gorm:"type:text" json:"follow_up_record"` // 最近一次跟进记录，完整记录见跟进动态
+	InternalNote   string         `gorm:"type:text" json:"internal_note"`
+	CreatedAt      time.Time      `gorm:"not null" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"not null" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"` // 删除时间（软删除，进入回收站）
+
+	// 转化信息：线索转化为客户后记录关联的客户、客户端用户及试用授权码
+	CustomerID               *string    `gorm:"type:varchar(36);index" json:"customer_id"`
+	CuUserID                 *string    `gorm:"type:varchar(36)" json:"cu_user_id"`
+	TrialAuthorizationCodeID *string    `gorm:"type:varchar(36)" json:"trial_authorization_code_id"`
+	ConvertedAt              *time.Time `gorm:"index" json:"converted_at"`
+
+	// 分配与去重
+	AssigneeID        *string `gorm:"type:varchar(36);index" json:"assignee_id"`     // 负责人（后台账号ID）
+	DuplicateOfID     *string `gorm:"type:varchar(36);index" json:"duplicate_of_id"` // 疑似重复的原线索ID
+	MergedIntoID      *string `gorm:"type:varchar(36);index" json:"merged_into_id"`  // 已合并到的线索ID
+	NormalizedPhone   string  `gorm:"type:varchar(20);index" json:"-"`
+	NormalizedEmail   string  `gorm:"type:varchar(100);index" json:"-"`
+	NormalizedCompany string  `gorm:"type:varchar(200);index" json:"-"`
+
+	// 提醒记录，避免重复发送
+	FollowUpRemindedAt *time.Time `json:"-"` // 最近一次跟进日期提醒时间
+	PendingRemindedAt  *time.Time `json:"-"` // 长时间未跟进提醒时间
 }
 
 // TableName 指定表名
@@ -59,6 +80,57 @@ func (l *Lead) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// BeforeSave 保存前更新去重用的标准化字段
+func (l *Lead) BeforeSave(tx *gorm.DB) error {
+	l.NormalizedPhone = NormalizeLeadPhone(l.ContactPhone)
+	l.NormalizedEmail = NormalizeLeadEmail(l.ContactEmail)
+	l.NormalizedCompany = NormalizeLeadCompany(l.CompanyName)
+	return nil
+}
+
+var (
+	leadNonDigitPattern    = regexp.MustCompile(`\D`)
+	leadPunctuationPattern = regexp.MustCompile(`[\p{P}\p{S}]`)
+)
+
+// leadCompanyWordSuffixes 英文公司名称末尾不参与去重比较的公司类型词
+var leadCompanyWordSuffixes = map[string]bool{
+	"co": true, "company": true, "corp": true, "corporation": true,
+	"inc": true, "llc": true, "ltd": true, "limited": true, "group": true,
+}
+
+// leadCompanySuffixes 中文公司名称中不参与去重比较的常见后缀，较长的后缀在前
+var leadCompanySuffixes = []string{"股份有限公司", "有限责任公司", "有限公司", "集团", "公司"}
+
+// NormalizeLeadPhone 手机号仅保留数字，去掉中国大陆国家码前缀
+func NormalizeLeadPhone(phone string) string {
+	digits := leadNonDigitPattern.ReplaceAllString(phone, "")
+	if len(digits) == 13 && strings.HasPrefix(digits, "86") {
+		digits = digits[2:]
+	}
+	return digits
+}
+
+// NormalizeLeadEmail 邮箱去除首尾空白并转为小写
+func NormalizeLeadEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeLeadCompany 公司名称转小写、去除空白和标点，并去掉常见的公司类型后缀
+func NormalizeLeadCompany(name string) string {
+	words := strings.Fields(leadPunctuationPattern.ReplaceAllString(strings.ToLower(name), " "))
+	for len(words) > 1 && leadCompanyWordSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	normalized := strings.Join(words, "")
+	for _, suffix := range leadCompanySuffixes {
+		if trimmed := strings.TrimSuffix(normalized, suffix); trimmed != "" && trimmed != normalized {
+			return trimmed
+		}
+	}
+	return normalized
+}
+
 // generateLeadNo 生成线索编号
 func generateLeadNo() string {
 	return "LEAD" + time.Now().Format("20060102150405") + randomString(6)
@@ -92,6 +164,15 @@ func (l *Lead) ToResponse() *LeadResponse {
 		InternalNote:   l.InternalNote,
 		CreatedAt:      l.CreatedAt,
 		UpdatedAt:      l.UpdatedAt,
+
+		CustomerID:               l.CustomerID,
+		CuUserID:                 l.CuUserID,
+		TrialAuthorizationCodeID: l.TrialAuthorizationCodeID,
+		ConvertedAt:              l.ConvertedAt,
+
+		AssigneeID:    l.AssigneeID,
+		DuplicateOfID: l.DuplicateOfID,
+		MergedIntoID:  l.MergedIntoID,
 	}
 }
 
@@ -111,6 +192,15 @@ type LeadResponse struct {
 	InternalNote   string     `json:"internal_note"`    // 内部备注
 	CreatedAt      time.Time  `json:"created_at"`       // 创建时间
 	UpdatedAt      time.Time  `json:"updated_at"`       // 更新时间
+
+	CustomerID               *string    `json:"customer_id"`                 // 转化后的客户ID
+	CuUserID                 *string    `json:"cu_user_id"`                  // 转化时创建的客户端用户ID
+	TrialAuthorizationCodeID *string    `json:"trial_authorization_code_id"` // 转化时签发的试用授权码ID
+	ConvertedAt              *time.Time `json:"converted_at"`                // 转化时间
+
+	AssigneeID    *string `json:"assignee_id"`     // 负责人（后台账号ID）
+	DuplicateOfID *string `json:"duplicate_of_id"` // 疑似重复的原线索ID
+	MergedIntoID  *string `json:"merged_into_id"`  // 已合并到的线索ID
 }
 
 // LeadCreateRequest 创建线索请求
@@ -133,16 +223,71 @@ type LeadUpdateRequest struct {
 	ExtraInfo      string     `json:"extra_info"`                                                           // 补充信息
 	Status         string     `json:"status" binding:"omitempty,oneof=pending contacted converted invalid"` // 状态
 	FollowUpDate   *time.Time `json:"follow_up_date"`                                                       // 跟进日期（RFC3339，如 2026-02-05T10:00:00Z）
-	FollowUpRecord string     `json:"follow_up_record"`                                                     // 跟进记录
+	FollowUpRecord string     `json:"follow_up_record"`                                                     // 跟进记录，与最近一次跟进记录不同时追加为跟进动态
 	InternalNote   string     `json:"internal_note"`                                                        // 内部备注
 }
 
+// LeadConvertRequest 线索转化为客户请求
+type LeadConvertRequest struct {
+	CustomerType       string                  `json:"customer_type" binding:"omitempty,oneof=individual enterprise government education"` // 客户类型，默认enterprise
+	CustomerLevel      string                  `json:"customer_level" binding:"omitempty,oneof=basic active vip strategic"`                // 客户等级，默认basic
+	CompanySize        *string                 `json:"company_size" binding:"omitempty,oneof=small medium large enterprise"`               // 企业规模，可选
+	PartnerID          *string                 `json:"partner_id" binding:"omitempty,max=36"`                                              // 所属经销商，可选
+	CreateCuUser       bool                    `json:"create_cu_user"`                                                                     // 是否为联系人手机号创建客户端用户
+	TrialAuthorization *LeadTrialAuthorization `json:"trial_authorization" binding:"omitempty"`                                            // 试用授权码，可选，为空时不签发
+}
+
+// LeadTrialAuthorization 线索转化时签发的试用授权码配置
+type LeadTrialAuthorization struct {
+	SoftwareID     *string     `json:"software_id" binding:"omitempty"`                                   // 软件ID
+	ValidityDays   int         `json:"validity_days" binding:"omitempty,min=1,max=365"`                   // 试用天数，默认30天
+	MaxActivations int         `json:"max_activations" binding:"omitempty,min=1"`                         // 最大激活次数，默认1
+	DeploymentType string      `json:"deployment_type" binding:"omitempty,oneof=standalone cloud hybrid"` // 部署类型，默认standalone
+	FeatureConfig  interface{} `json:"feature_config" binding:"omitempty"`                                // 功能配置（JSON对象）
+}
+
+// LeadConvertResponse 线索转化结果
+type LeadConvertResponse struct {
+	Lead                     *LeadResponse `json:"lead"`                                  // 转化后的线索
+	CustomerID               string        `json:"customer_id"`                           // 客户ID
+	CustomerCode             string        `json:"customer_code"`                         // 客户编码
+	CustomerName             string        `json:"customer_name"`                         // 客户名称
+	CuUserID                 *string       `json:"cu_user_id,omitempty"`                  // 客户端用户ID
+	TrialAuthorizationCodeID *string       `json:"trial_authorization_code_id,omitempty"` // 试用授权码ID
+	TrialAuthorizationCode   *string       `json:"trial_authorization_code,omitempty"`    // 试用授权码
+	AlreadyConverted         bool          `json:"already_converted"`                     // 是否为重复请求（线索此前已转化，返回原转化结果）
+}
+
 // LeadListRequest 线索列表请求
 type LeadListRequest struct {
-	Page     int    `form:"page" binding:"omitempty,min=1"`
-	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
-	Search   string `form:"search"` // 关键词检索
-	Status   string `form:"status"` // 状态筛选
+	Page           int    `form:"page" binding:"omitempty,min=1"`
+	PageSize       int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Search         string `form:"search"`          // 关键词检索
+	Status         string `form:"status"`          // 状态筛选
+	AssigneeID     string `form:"assignee_id"`     // 负责人筛选，unassigned 表示未分配
+	OnlyDuplicates bool   `form:"only_duplicates"` // 仅显示疑似重复的线索
+	IncludeMerged  bool   `form:"include_merged"`  // 是否包含已合并的线索，默认不包含
+}
+
+// LeadAssignRequest 分配线索负责人请求
+type LeadAssignRequest struct {
+	AssigneeID *string `json:"assignee_id" binding:"omitempty,max=36"` // 负责人（后台账号ID），为空表示取消分配
+}
+
+// LeadMergeRequest 合并线索请求，将当前线索合并到目标线索
+type LeadMergeRequest struct {
+	TargetID string `json:"target_id" binding:"required,max=36"` // 保留的目标线索ID
+}
+
+// LeadDuplicateCandidate 疑似重复线索
+type LeadDuplicateCandidate struct {
+	Lead          *LeadResponse `json:"lead"`           // 疑似重复的线索
+	MatchedFields []string      `json:"matched_fields"` // 匹配的字段：phone/email/company
+}
+
+// LeadDuplicatesResponse 疑似重复线索列表（合并建议）
+type LeadDuplicatesResponse struct {
+	List []*LeadDuplicateCandidate `json:"list"`
 }
 
 // LeadListResponse 线索列表响应
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 线索跟进动态类型
+const (
+	LeadActivityTypeNote         = "note"          // 备注
+	LeadActivityTypeFollowUp     = "follow_up"     // 跟进记录
+	LeadActivityTypeStatusChange = "status_change" // 状态变更
+	LeadActivityTypeAssignment   = "assignment"    // 分配负责人
+	LeadActivityTypeMerge        = "merge"         // 线索合并
+	LeadActivityTypeConversion   = "conversion"    // 转化为客户
+)
+
+// 线索相关通知类型（发送给后台账号）
+const (
+	NotificationTypeLeadAssigned       = "lead_assigned"        // 线索已分配给你
+	NotificationTypeLeadFollowUpDue    = "lead_follow_up_due"   // 线索跟进日期已到
+	NotificationTypeLeadPendingTooLong = "lead_pending_timeout" // 线索长时间未跟进
+)
+
+// LeadActivity 线索跟进动态，只追加不修改
+type LeadActivity struct {
+	ID           string    `gorm:"type:varchar(36);primaryKey" json:"id"`             // 动态ID
+	LeadID       string    `gorm:"type:varchar(36);not null;index" json:"lead_id"`    // 线索ID
+	Type         string    `gorm:"type:varchar(20);not null" json:"type"`             // 动态类型
+	TypeDisplay  string    `gorm:"-" json:"type_display,omitempty"`                   // 动态类型显示（多语言）
+	Content      string    `gorm:"type:text;not null" json:"content"`                 // 内容
+	OperatorID   *string   `gorm:"type:varchar(36)" json:"operator_id"`               // 操作人（后台账号ID），系统生成时为空
+	OperatorName string    `gorm:"->;-:migration" json:"operator_name,omitempty"`     // 操作人姓名（查询时关联）
+	CreatedAt    time.Time `gorm:"type:datetime(3);not null;index" json:"created_at"` // 创建时间
+}
+
+// TableName 指定表名
+func (LeadActivity) TableName() string {
+	return "lead_activities"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (a *LeadActivity) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// NewLeadActivity 创建线索跟进动态，operatorID 为空表示系统生成
+func NewLeadActivity(leadID, activityType, content, operatorID string) *LeadActivity {
+	activity := &LeadActivity{
+		LeadID:  leadID,
+		Type:    activityType,
+		Content: content,
+	}
+	if operatorID != "" {
+		activity.OperatorID = &operatorID
+	}
+	return activity
+}
+
+// LeadActivityCreateRequest 添加线索跟进动态请求
+type LeadActivityCreateRequest struct {
+	Type    string `json:"type" binding:"omitempty,oneof=note follow_up"` // 动态类型：note/follow_up，默认follow_up
+	Content string `json:"content" binding:"required,min=1,max=5000"`     // 内容
+}
+
+// LeadActivityListRequest 线索跟进动态列表请求
+type LeadActivityListRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`              // 页码，默认1
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"` // 每页条数，默认20，最大100
+}
+
+// LeadActivityListResponse 线索跟进动态列表响应（按时间倒序）
+type LeadActivityListResponse struct {
+	List     []*LeadActivity `json:"list"`
+	Total    int64           `json:"total"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+}
@@ -16,6 +16,7 @@ type License struct {
 	HardwareFingerprint string         `gorm:"type:varchar(200);not null;index" json:"hardware_fingerprint"`
 	DeviceInfo          JSON           `gorm:"type:json" json:"device_info,omitempty" swaggertype:"object"`
 	ActivationIP        *string        `gorm:"type:varchar(45)" json:"activation_ip"`
+	ActivationMode      string         `gorm:"type:varchar(20);not null;default:'online'" json:"activation_mode"`
 	Status              string         `gorm:"type:varchar(20);not null;default:'inactive';index" json:"status"`
 	StatusDisplay       string         `gorm:"-" json:"status_display,omitempty"`
 	ActivatedAt         *time.Time     `gorm:"index" json:"activated_at"`
@@ -67,10 +68,15 @@ type LicenseListRequest struct {
 	PageSize            int     `form:"page_size" binding:"omitempty,min=1,max=100"`                                      // 每页条数，默认20，最大100
 	AuthorizationCodeID string  `form:"authorization_code_id" binding:"omitempty"`                                        // 授权码ID筛选
 	CustomerID          string  `form:"customer_id" binding:"omitempty"`                                                  // 客户ID筛选
-	Status              string  `form:"status" binding:"omitempty,oneof=active inactive revoked"`                         // 状态筛选
+	Status              string  `form:"status" binding:"omitempty,oneof=active inactive revoked transferred"`             // 状态筛选
 	IsOnline            *string `form:"is_online" binding:"omitempty"`                                                    // 在线状态筛选
 	Sort                string  `form:"sort" binding:"omitempty,oneof=created_at updated_at activated_at last_heartbeat"` // 排序字段，默认created_at
 	Order               string  `form:"order" binding:"omitempty,oneof=asc desc"`                                         // 排序方向，默认desc
+
+	IncludeSubsidiaries bool     `form:"include_subsidiaries"` // 按客户筛选时包含其所有下级客户
+	CustomerIDs         []string `form:"-"`                    // 限定客户ID范围（由服务层按层级关系展开）
+
+	CustomFieldFilter
 }
 
 // LicenseListItem 许可证列表项结构
@@ -89,6 +95,9 @@ type LicenseListItem struct {
 	LastOnlineIP        *string `json:"last_online_ip"`              // 最后在线IP
 	ActivatedAt         *string `json:"activated_at"`                // 激活时间
 	LastHeartbeat       *string `json:"last_heartbeat"`              // 最后心跳时间
+
+	CustomFields map[string]string `json:"custom_fields,omitempty"` // 自定义字段（字段标识 -> 值）
+	Tags         []string          `json:"tags,omitempty"`          // 标签
 }
 
 // LicenseListResponse 许可证列表响应结构
@@ -111,6 +120,7 @@ type LicenseDetailResponse struct {
 	HardwareFingerprint string                 `json:"hardware_fingerprint"`        // 硬件指纹
 	DeviceInfo          map[string]interface{} `json:"device_info,omitempty"`       // 设备信息
 	ActivationIP        *string                `json:"activation_ip"`               // 激活IP
+	ActivationMode      string                 `json:"activation_mode"`             // 激活方式：online/offline/relay
 	Status              string                 `json:"status"`                      // 许可证状态
 	StatusDisplay       string                 `json:"status_display,omitempty"`    // 状态显示名称
 	IsOnline            bool                   `json:"is_online"`                   // 是否在线
@@ -122,6 +132,8 @@ type LicenseDetailResponse struct {
 	UsageData           map[string]interface{} `json:"usage_data,omitempty"`        // 使用数据
 	CreatedAt           string                 `json:"created_at"`                  // 创建时间
 	UpdatedAt           string                 `json:"updated_at"`                  // 更新时间
+	CustomFields        map[string]string      `json:"custom_fields,omitempty"`     // 自定义字段（字段标识 -> 值）
+	Tags                []string               `json:"tags,omitempty"`              // 标签
 }
 
 // LicenseCreateRequest 手动添加许可证请求结构
@@ -143,6 +155,9 @@ type ActivateRequest struct {
 	HardwareFingerprint string                 `json:"hardware_fingerprint" binding:"required"` // 硬件指纹，必填
 	DeviceInfo          map[string]interface{} `json:"device_info,omitempty"`                   // 设备信息，可选
 	SoftwareVersion     *string                `json:"software_version" binding:"omitempty"`    // 软件版本，可选
+	ActivationMode      string                 `json:"activation_mode,omitempty"`               // 激活方式：online（默认）/offline/relay，须符合授权码的部署类型
+	RelayTimestamp      int64                  `json:"relay_timestamp,omitempty"`               // 中继签名时间（Unix 秒），通过中继激活时必填
+	RelaySignature      string                 `json:"relay_signature,omitempty"`               // 中继签名：HMAC-SHA256(中继许可证密钥, "硬件指纹\n时间戳")，十六进制
 }
 
 // ActivateResponse 软件激活响应结构
@@ -150,6 +165,8 @@ type ActivateResponse struct {
 	LicenseKey        string `json:"license_key"`        // 许可证密钥
 	LicenseFile       string `json:"license_file"`       // base64编码的加密许可证文件
 	HeartbeatInterval int    `json:"heartbeat_interval"` // 心跳间隔(秒)
+
+	DeploymentPolicy *DeploymentPolicy `json:"deployment_policy,omitempty"` // 部署类型规则（许可证文件中同样包含）
 }
 
 // HeartbeatRequest 心跳检测请求结构
@@ -159,14 +176,34 @@ type HeartbeatRequest struct {
 	ConfigUpdatedAt     *string                `json:"config_updated_at,omitempty"`             // 客户端配置更新时间，可选
 	UsageData           map[string]interface{} `json:"usage_data,omitempty"`                    // 使用数据，可选
 	SoftwareVersion     *string                `json:"software_version,omitempty"`              // 软件版本，可选
+	UpdateChannel       string                 `json:"update_channel,omitempty"`                // 更新渠道：stable（默认）/beta，beta 同时包含正式版
+	CommandAcks         []LicenseCommandAck    `json:"command_acks,omitempty"`                  // 上次心跳下发命令的执行结果，可选
+	RelayTimestamp      int64                  `json:"relay_timestamp,omitempty"`               // 中继签名时间（Unix 秒），混合部署经中继转发心跳时必填
+	RelaySignature      string                 `json:"relay_signature,omitempty"`               // 中继签名：HMAC-SHA256(中继许可证密钥, "硬件指纹\n时间戳")，十六进制
 }
 
 // HeartbeatResponse 心跳检测响应结构
 type HeartbeatResponse struct {
-	Status            string  `json:"status"`             // 许可证状态
-	ConfigUpdated     bool    `json:"config_updated"`     // 配置是否有更新
-	LicenseFile       *string `json:"license_file"`       // base64编码的新许可证文件(如有更新)
-	HeartbeatInterval int     `json:"heartbeat_interval"` // 下次心跳间隔(秒)
+	Status              string  `json:"status"`                         // 许可证状态
+	ConfigUpdated       bool    `json:"config_updated"`                 // 配置是否有更新
+	LicenseFile         *string `json:"license_file"`                   // base64编码的新许可证文件(如有更新)
+	HeartbeatInterval   int     `json:"heartbeat_interval"`             // 下次心跳间隔(秒)
+	DeactivationReceipt *string `json:"deactivation_receipt,omitempty"` // 许可证已转移时返回的停用回执(base64，RSA签名)
+
+	// 升级授权：客户端可使用发布时间不晚于 maintenance_until 的任何版本（许可证文件中同样包含该签名字段）
+	MaintenanceUntil  *time.Time `json:"maintenance_until,omitempty"` // 维护截止时间，为空表示不限制可用版本
+	MaintenanceActive bool       `json:"maintenance_active"`          // 当前是否在维护期内（可获取新版本）
+
+	// 版本更新：按授权码的产品和维护期筛选出客户端可安装的最新版本
+	LatestVersion          *ReleaseAdvertisement `json:"latest_version,omitempty"` // 可安装的最新版本，未登记版本时为空
+	UpdateAvailable        bool                  `json:"update_available"`         // 最新版本是否高于客户端上报的软件版本
+	UpgradeRequiresRenewal bool                  `json:"upgrade_requires_renewal"` // 存在维护期后发布的更新版本，需续订维护才能安装
+
+	// 远程命令：客户端执行后在下一次心跳的 command_acks 中确认，未确认的命令会重复下发
+	Commands []LicenseCommandDelivery `json:"commands,omitempty"` // 待执行的远程命令
+
+	// 部署类型规则：客户端据此控制离线可用时长
+	DeploymentPolicy *DeploymentPolicy `json:"deployment_policy,omitempty"` // 部署类型规则
 }
 
 // StatsOverviewResponse stats overview API response
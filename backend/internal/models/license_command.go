@@ -0,0 +1,130 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 远程命令类型
+const (
+	LicenseCommandRefreshLicense     = "refresh_license"     // 刷新许可证文件（随心跳下发新的许可证文件）
+	LicenseCommandDeactivate         = "deactivate"          // 停用并删除本地许可证文件，确认成功后释放席位
+	LicenseCommandShowMessage        = "show_message"        // 向用户显示消息
+	LicenseCommandCollectDiagnostics = "collect_diagnostics" // 收集诊断信息，结果随确认上报
+)
+
+// 远程命令状态
+const (
+	LicenseCommandStatusPending   = "pending"   // 待下发
+	LicenseCommandStatusDelivered = "delivered" // 已下发，等待客户端确认
+	LicenseCommandStatusSucceeded = "succeeded" // 客户端执行成功
+	LicenseCommandStatusFailed    = "failed"    // 客户端执行失败
+	LicenseCommandStatusCancelled = "cancelled" // 已取消
+	LicenseCommandStatusExpired   = "expired"   // 超过有效期未确认
+)
+
+// LicenseCommandIssuerType 命令发起人类型
+type LicenseCommandIssuerType string
+
+const (
+	LicenseCommandIssuerAdmin  LicenseCommandIssuerType = "admin"   // 管理员
+	LicenseCommandIssuerCuUser LicenseCommandIssuerType = "cu_user" // 客户用户
+)
+
+// LicenseCommand 下发到设备的远程命令
+// 命令在心跳响应中下发，客户端在下一次心跳中确认；确认前每次心跳都会重新下发，客户端需按命令ID去重
+type LicenseCommand struct {
+	ID                  string     `gorm:"type:varchar(36);primaryKey" json:"id"`                           // 命令ID
+	LicenseID           string     `gorm:"type:varchar(36);not null;index" json:"license_id"`               // 许可证ID
+	AuthorizationCodeID string     `gorm:"type:varchar(36);not null;index" json:"authorization_code_id"`    // 授权码ID
+	CustomerID          string     `gorm:"type:varchar(36);not null;index" json:"customer_id"`              // 客户ID
+	CommandType         string     `gorm:"type:varchar(30);not null" json:"command_type"`                   // 命令类型
+	CommandTypeDisplay  string     `gorm:"-" json:"command_type_display,omitempty"`                         // 命令类型显示（多语言）
+	Payload             JSON       `gorm:"type:json" json:"payload" swaggertype:"object"`                   // 命令参数
+	Status              string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"` // 状态
+	StatusDisplay       string     `gorm:"-" json:"status_display,omitempty"`                               // 状态显示（多语言）
+	Result              JSON       `gorm:"type:json" json:"result" swaggertype:"object"`                    // 客户端上报的执行结果（如诊断信息）
+	ErrorMessage        *string    `gorm:"type:varchar(1000)" json:"error_message"`                         // 客户端上报的失败原因
+	IssuerType          string     `gorm:"type:varchar(20);not null" json:"issuer_type"`                    // 发起人类型：admin/cu_user
+	IssuerID            string     `gorm:"type:varchar(36);not null" json:"issuer_id"`                      // 发起人ID
+	ExpiresAt           time.Time  `gorm:"type:datetime(3);not null" json:"expires_at"`                     // 有效期，过期未确认的命令不再下发
+	DeliveredAt         *time.Time `gorm:"type:datetime(3)" json:"delivered_at"`                            // 首次下发时间
+	DeliveryCount       int        `gorm:"not null;default:0" json:"delivery_count"`                        // 下发次数
+	AcknowledgedAt      *time.Time `gorm:"type:datetime(3)" json:"acknowledged_at"`                         // 客户端确认时间
+	CreatedAt           time.Time  `gorm:"type:datetime(3);not null;index" json:"created_at"`               // 创建时间
+	UpdatedAt           time.Time  `gorm:"type:datetime(3);not null" json:"updated_at"`                     // 更新时间
+}
+
+// TableName 指定表名
+func (LicenseCommand) TableName() string {
+	return "license_commands"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (c *LicenseCommand) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = now
+	}
+	if c.UpdatedAt.IsZero() {
+		c.UpdatedAt = now
+	}
+	return nil
+}
+
+// BeforeUpdate 更新前自动设置时间戳
+func (c *LicenseCommand) BeforeUpdate(tx *gorm.DB) error {
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsOpen 命令是否仍在等待执行（待下发或已下发未确认）
+func (c *LicenseCommand) IsOpen() bool {
+	return c.Status == LicenseCommandStatusPending || c.Status == LicenseCommandStatusDelivered
+}
+
+// LicenseCommandCreateRequest 创建远程命令请求
+type LicenseCommandCreateRequest struct {
+	CommandType    string                 `json:"command_type" binding:"required,oneof=refresh_license deactivate show_message collect_diagnostics"` // 命令类型
+	Message        *string                `json:"message" binding:"omitempty,max=1000"`                                                              // 消息内容，show_message 必填
+	Parameters     map[string]interface{} `json:"parameters" binding:"omitempty"`                                                                    // 附加参数，原样下发给客户端
+	ExpiresInHours *int                   `json:"expires_in_hours" binding:"omitempty,min=1,max=720"`                                                // 有效小时数，默认72小时
+}
+
+// LicenseCommandListRequest 远程命令列表查询请求
+type LicenseCommandListRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`                                                        // 页码，默认1
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`                                           // 每页条数，默认20，最大100
+	Status   string `form:"status" binding:"omitempty,oneof=pending delivered succeeded failed cancelled expired"` // 状态筛选，expired 按有效期计算
+}
+
+// LicenseCommandListResponse 远程命令列表响应
+type LicenseCommandListResponse struct {
+	List       []*LicenseCommand `json:"list"`        // 命令列表
+	Total      int64             `json:"total"`       // 总记录数
+	Page       int               `json:"page"`        // 当前页码
+	PageSize   int               `json:"page_size"`   // 每页条数
+	TotalPages int               `json:"total_pages"` // 总页数
+}
+
+// LicenseCommandDelivery 心跳响应中下发的命令
+type LicenseCommandDelivery struct {
+	ID          string                 `json:"id"`                // 命令ID，确认时回传
+	CommandType string                 `json:"command_type"`      // 命令类型
+	Payload     map[string]interface{} `json:"payload,omitempty"` // 命令参数
+	IssuedAt    time.Time              `json:"issued_at"`         // 创建时间
+	ExpiresAt   time.Time              `json:"expires_at"`        // 有效期
+}
+
+// LicenseCommandAck 客户端在心跳中上报的命令执行结果
+type LicenseCommandAck struct {
+	CommandID string                 `json:"command_id"`       // 命令ID
+	Success   bool                   `json:"success"`          // 是否执行成功
+	Result    map[string]interface{} `json:"result,omitempty"` // 执行结果，如诊断信息
+	Error     *string                `json:"error,omitempty"`  // 失败原因
+}
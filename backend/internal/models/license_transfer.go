@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LicenseStatusTransferred 许可证已转移到其他设备
+const LicenseStatusTransferred = "transferred"
+
+// LicenseTransferOperatorType 转移操作人类型
+type LicenseTransferOperatorType string
+
+const (
+	LicenseTransferOperatorCuUser LicenseTransferOperatorType = "cu_user" // 客户用户自助转移
+	LicenseTransferOperatorAdmin  LicenseTransferOperatorType = "admin"   // 管理员代为转移
+)
+
+// LicenseTransfer 许可证设备转移记录
+type LicenseTransfer struct {
+	ID                  string    `gorm:"type:varchar(36);primaryKey" json:"id"`                        // 转移记录ID
+	SeatID              string    `gorm:"type:varchar(36);not null;index" json:"seat_id"`               // 席位ID（该席位最初的许可证ID，用于按席位统计转移次数）
+	AuthorizationCodeID string    `gorm:"type:varchar(36);not null;index" json:"authorization_code_id"` // 授权码ID
+	CustomerID          string    `gorm:"type:varchar(36);not null;index" json:"customer_id"`           // 客户ID
+	FromLicenseID       string    `gorm:"type:varchar(36);not null;index" json:"from_license_id"`       // 原许可证ID
+	FromLicenseKey      string    `gorm:"type:varchar(200);not null" json:"from_license_key"`           // 原许可证密钥
+	FromFingerprint     string    `gorm:"type:varchar(200);not null" json:"from_fingerprint"`           // 原设备硬件指纹
+	ToLicenseID         string    `gorm:"type:varchar(36);not null;index" json:"to_license_id"`         // 新许可证ID
+	ToLicenseKey        string    `gorm:"type:varchar(200);not null" json:"to_license_key"`             // 新许可证密钥
+	ToFingerprint       string    `gorm:"type:varchar(200);not null" json:"to_fingerprint"`             // 新设备硬件指纹
+	OperatorType        string    `gorm:"type:varchar(20);not null" json:"operator_type"`               // 操作人类型：cu_user/admin
+	OperatorID          string    `gorm:"type:varchar(36)" json:"operator_id"`                          // 操作人ID
+	Reason              *string   `gorm:"type:varchar(500)" json:"reason"`                              // 转移原因
+	DeactivationReceipt string    `gorm:"type:text;not null" json:"-"`                                  // 签名的停用回执（原设备可离线校验）
+	CreatedAt           time.Time `gorm:"type:datetime(3);not null;index" json:"created_at"`            // 转移时间
+}
+
+// TableName 指定表名
+func (LicenseTransfer) TableName() string {
+	return "license_transfers"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (t *LicenseTransfer) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// LicenseTransferRequest 许可证转移请求
+type LicenseTransferRequest struct {
+	NewHardwareFingerprint string                 `json:"new_hardware_fingerprint" binding:"required,max=200"` // 新设备硬件指纹，必填
+	DeviceInfo             map[string]interface{} `json:"device_info" binding:"omitempty"`                     // 新设备信息，可选
+	Reason                 *string                `json:"reason" binding:"omitempty,max=500"`                  // 转移原因，可选
+}
+
+// LicenseTransferResponse 许可证转移响应
+type LicenseTransferResponse struct {
+	TransferID          string `json:"transfer_id"`                   // 转移记录ID
+	OldLicenseID        string `json:"old_license_id"`                // 原许可证ID
+	OldLicenseKey       string `json:"old_license_key"`               // 原许可证密钥
+	NewLicenseID        string `json:"new_license_id"`                // 新许可证ID
+	NewLicenseKey       string `json:"new_license_key"`               // 新许可证密钥
+	NewLicenseFile      string `json:"new_license_file"`              // 新设备的许可证文件（base64，支持离线导入）
+	DeactivationReceipt string `json:"deactivation_receipt"`          // 原设备的停用回执（base64，RSA签名）
+	TransfersUsed       int64  `json:"transfers_used"`                // 当前周期内该席位已转移次数（含本次）
+	TransfersRemaining  *int64 `json:"transfers_remaining,omitempty"` // 当前周期内剩余可转移次数，不限制时为空
+}
+
+// LicenseTransferListRequest 许可证转移记录列表查询请求
+type LicenseTransferListRequest struct {
+	Page                int    `form:"page" binding:"omitempty,min=1"`              // 页码，默认1
+	PageSize            int    `form:"page_size" binding:"omitempty,min=1,max=100"` // 每页条数，默认20，最大100
+	AuthorizationCodeID string `form:"authorization_code_id" binding:"omitempty"`   // 授权码ID筛选
+	CustomerID          string `form:"customer_id" binding:"omitempty"`             // 客户ID筛选
+	LicenseID           string `form:"license_id" binding:"omitempty"`              // 许可证ID筛选（原或新许可证）
+}
+
+// LicenseTransferListResponse 许可证转移记录列表响应
+type LicenseTransferListResponse struct {
+	List       []*LicenseTransfer `json:"list"`        // 转移记录列表
+	Total      int64              `json:"total"`       // 总记录数
+	Page       int                `json:"page"`        // 当前页码
+	PageSize   int                `json:"page_size"`   // 每页条数
+	TotalPages int                `json:"total_pages"` // 总页数
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationRecipientType 通知接收方类型
+type NotificationRecipientType string
+
+const (
+	NotificationRecipientCustomer NotificationRecipientType = "customer" // 客户（客户下所有用户可见）
+	NotificationRecipientUser     NotificationRecipientType = "user"     // 管理端用户
+)
+
+// 通知类型
+const (
+	NotificationTypeSeatReclaimed = "seat_reclaimed" // 闲置席位已回收
+)
+
+// Notification 站内通知
+type Notification struct {
+	ID            string     `gorm:"type:varchar(36);primaryKey" json:"id"`                                             // 通知ID
+	RecipientType string     `gorm:"type:varchar(20);not null;index:idx_notifications_recipient" json:"recipient_type"` // 接收方类型：customer/user
+	RecipientID   string     `gorm:"type:varchar(36);not null;index:idx_notifications_recipient" json:"recipient_id"`   // 接收方ID
+	Type          string     `gorm:"type:varchar(50);not null;index" json:"type"`                                       // 通知类型
+	TypeDisplay   string     `gorm:"-" json:"type_display,omitempty"`                                                   // 通知类型显示（多语言）
+	Payload       JSON       `gorm:"type:json" json:"payload,omitempty" swaggertype:"object"`                           // 通知内容参数（JSON对象）
+	ReadAt        *time.Time `gorm:"type:datetime(3)" json:"read_at"`                                                   // 阅读时间
+	CreatedAt     time.Time  `gorm:"type:datetime(3);not null;index" json:"created_at"`                                 // 创建时间
+}
+
+// TableName 指定表名
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == "" {
+		n.ID = uuid.New().String()
+	}
+	if n.CreatedAt.IsZero() {
+		n.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// NotificationListRequest 通知列表查询请求
+type NotificationListRequest struct {
+	Page     int     `form:"page" binding:"omitempty,min=1"`              // 页码，默认1
+	PageSize int     `form:"page_size" binding:"omitempty,min=1,max=100"` // 每页条数，默认20，最大100
+	Type     string  `form:"type" binding:"omitempty"`                    // 通知类型筛选
+	IsRead   *string `form:"is_read" binding:"omitempty"`                 // 是否已读筛选（true/false）
+}
+
+// NotificationListResponse 通知列表响应
+type NotificationListResponse struct {
+	List        []Notification `json:"list"`         // 通知列表
+	Total       int64          `json:"total"`        // 总记录数
+	UnreadCount int64          `json:"unread_count"` // 未读数量
+	Page        int            `json:"page"`         // 当前页码
+	PageSize    int            `json:"page_size"`    // 每页条数
+}
@@ -30,6 +30,9 @@ type Package struct {
 	Status              int            `gorm:"type:tinyint(1);not null;default:1" json:"status"`
 	SortOrder           int            `gorm:"type:int;not null;default:0" json:"sort_order"`
 	Remark              string         `gorm:"type:varchar(500);default:''" json:"remark"`
+	DormantReclaimDays  *int           `gorm:"type:int" json:"dormant_reclaim_days"`           // 闲置席位回收天数，下单生成授权码时继承
+	CodeFormat          string         `gorm:"type:varchar(20);default:''" json:"code_format"` // 下单生成授权码的格式：legacy/base32/signed，为空使用系统配置
+	MaintenanceMonths   *int           `gorm:"type:int" json:"maintenance_months"`             // 维护（升级）月数：新购授权码的维护期，续订维护订单按此顺延；为空不限制版本且不支持续订
 	CreatedAt           time.Time      `gorm:"not null" json:"created_at"`
 	UpdatedAt           time.Time      `gorm:"not null" json:"updated_at"`
 	DeletedAt           gorm.DeletedAt `gorm:"index" json:"-"`
@@ -75,6 +78,9 @@ func (p *Package) ToResponse() *PackageResponse {
 		Status:              p.Status,
 		SortOrder:           p.SortOrder,
 		Remark:              p.Remark,
+		DormantReclaimDays:  p.DormantReclaimDays,
+		CodeFormat:          p.CodeFormat,
+		MaintenanceMonths:   p.MaintenanceMonths,
 		CreatedAt:           p.CreatedAt,
 		UpdatedAt:           p.UpdatedAt,
 	}
@@ -93,6 +99,9 @@ type PackageResponse struct {
 	Status              int       `json:"status"`
 	SortOrder           int       `json:"sort_order"`
 	Remark              string    `json:"remark"`
+	DormantReclaimDays  *int      `json:"dormant_reclaim_days"`
+	CodeFormat          string    `json:"code_format"`
+	MaintenanceMonths   *int      `json:"maintenance_months"`
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at"`
 }
@@ -109,6 +118,9 @@ type PackageCreateRequest struct {
 	Status              int     `json:"status" binding:"oneof=0 1"`
 	SortOrder           int     `json:"sort_order"`
 	Remark              string  `json:"remark" binding:"max=500"`
+	DormantReclaimDays  *int    `json:"dormant_reclaim_days" binding:"omitempty,min=1,max=3650"`    // 闲置席位回收天数，可选
+	CodeFormat          string  `json:"code_format" binding:"omitempty,oneof=legacy base32 signed"` // 授权码格式，可选
+	MaintenanceMonths   *int    `json:"maintenance_months" binding:"omitempty,min=1,max=120"`       // 维护月数，可选
 }
 
 // PackageUpdateRequest 更新套餐请求
@@ -123,6 +135,9 @@ type PackageUpdateRequest struct {
 	Status              *int    `json:"status" binding:"omitempty,oneof=0 1"`
 	SortOrder           *int    `json:"sort_order"`
 	Remark              string  `json:"remark" binding:"omitempty,max=500"`
+	DormantReclaimDays  *int    `json:"dormant_reclaim_days" binding:"omitempty,min=0,max=3650"`            // 闲置席位回收天数，传0表示关闭回收
+	CodeFormat          *string `json:"code_format" binding:"omitempty,oneof=legacy base32 signed default"` // 授权码格式，传default表示使用系统配置
+	MaintenanceMonths   *int    `json:"maintenance_months" binding:"omitempty,min=0,max=120"`               // 维护月数，传0表示不限制版本
 }
 
 // PackageListRequest 套餐列表请求
@@ -139,14 +154,15 @@ type PackageListResponse struct {
 
 // CuPackageResponse 用户端套餐响应（兼容原有结构）
 type CuPackageResponse struct {
-	ID               string  `json:"id"`
-	Name             string  `json:"name"`
-	Type             string  `json:"type"`
-	Price            float64 `json:"price"`
-	DisplayPrice     string  `json:"display_price"`
-	PriceDescription string  `json:"price_description"`
-	MaxDevices       int     `json:"max_devices"` // 从features中解析
-	Description      string  `json:"description"`
-	Features         string  `json:"features"`
-	Details          string  `json:"details"`
+	ID                string  `json:"id"`
+	Name              string  `json:"name"`
+	Type              string  `json:"type"`
+	Price             float64 `json:"price"`
+	DisplayPrice      string  `json:"display_price"`
+	PriceDescription  string  `json:"price_description"`
+	MaxDevices        int     `json:"max_devices"`        // 从features中解析
+	MaintenanceMonths *int    `json:"maintenance_months"` // 维护月数，为空表示不支持续订维护
+	Description       string  `json:"description"`
+	Features          string  `json:"features"`
+	Details           string  `json:"details"`
 }
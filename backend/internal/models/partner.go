@@ -0,0 +1,97 @@
+package models
+
+import "time"
+
+// PartnerCreateRequest 创建经销商账号请求
+type PartnerCreateRequest struct {
+	Username  string  `json:"username" binding:"required,min=3,max=50"`
+	Email     string  `json:"email" binding:"required,email,max=255"`
+	Password  string  `json:"password" binding:"required,min=8,max=50"`
+	FullName  string  `json:"full_name" binding:"required,max=100"`
+	Phone     *string `json:"phone" binding:"omitempty,max=20"`
+	SeatQuota int     `json:"seat_quota" binding:"min=0"` // 席位额度：名下客户授权码的最大激活数合计上限
+}
+
+// PartnerUpdateRequest 更新经销商账号请求（所有字段都是可选的）
+type PartnerUpdateRequest struct {
+	Email     *string `json:"email" binding:"omitempty,email,max=255"`
+	FullName  *string `json:"full_name" binding:"omitempty,max=100"`
+	Phone     *string `json:"phone" binding:"omitempty,max=20"`
+	SeatQuota *int    `json:"seat_quota" binding:"omitempty,min=0"`
+	Status    *string `json:"status" binding:"omitempty,oneof=active disabled"`
+}
+
+// PartnerListRequest 经销商列表查询请求
+type PartnerListRequest struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Search   string `form:"search" binding:"omitempty,max=100"` // 用户名/姓名/邮箱
+	Status   string `form:"status" binding:"omitempty,oneof=active disabled locked"`
+}
+
+// PartnerItem 经销商账号及额度使用情况
+type PartnerItem struct {
+	ID             string     `json:"id"`
+	Username       string     `json:"username"`
+	Email          string     `json:"email"`
+	FullName       string     `json:"full_name"`
+	Phone          *string    `json:"phone"`
+	Status         string     `json:"status"`
+	SeatQuota      int        `json:"seat_quota"`      // 席位额度
+	UsedSeats      int64      `json:"used_seats"`      // 已分配席位（名下客户授权码的最大激活数合计）
+	AvailableSeats int64      `json:"available_seats"` // 剩余可分配席位
+	CustomerCount  int64      `json:"customer_count"`  // 名下客户数量
+	LastLoginAt    *time.Time `json:"last_login_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PartnerListResponse 经销商列表响应
+type PartnerListResponse struct {
+	List       []*PartnerItem `json:"list"`
+	Total      int64          `json:"total"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"page_size"`
+	TotalPages int            `json:"total_pages"`
+}
+
+// PartnerUsage 经销商名下客户数量及已分配席位
+type PartnerUsage struct {
+	PartnerID     string
+	CustomerCount int64
+	UsedSeats     int64
+}
+
+// PartnerOrderListRequest 经销商订单列表查询请求
+type PartnerOrderListRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	CustomerID string `form:"customer_id" binding:"omitempty,max=36"` // 客户ID筛选
+	Status     string `form:"status" binding:"omitempty,max=20"`      // 订单状态筛选
+}
+
+// PartnerOrderItem 经销商名下客户的订单及最近一次支付情况
+type PartnerOrderItem struct {
+	ID            string     `json:"id"`
+	OrderNo       string     `json:"order_no"`
+	CustomerID    string     `json:"customer_id"`
+	CustomerName  string     `json:"customer_name"`
+	PackageName   string     `json:"package_name"`
+	OrderType     string     `json:"order_type"`
+	LicenseCount  int        `json:"license_count"`
+	TotalAmount   float64    `json:"total_amount"`
+	Status        string     `json:"status"`         // 订单状态
+	PaymentNo     *string    `json:"payment_no"`     // 最近一次支付单号，免费订单为空
+	PaymentMethod *string    `json:"payment_method"` // 支付方式
+	PaymentStatus *string    `json:"payment_status"` // 支付状态
+	PaymentTime   *time.Time `json:"payment_time"`   // 支付时间
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// PartnerOrderListResponse 经销商订单列表响应
+type PartnerOrderListResponse struct {
+	List       []*PartnerOrderItem `json:"list"`
+	Total      int64               `json:"total"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"page_size"`
+	TotalPages int                 `json:"total_pages"`
+}
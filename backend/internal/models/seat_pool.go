@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CustomerSeatPool 客户级席位池
+// 开启 use_seat_pool 的授权码共享席位池的总席位：激活时以席位池的剩余席位为上限，不再受授权码自身的最大激活次数限制
+type CustomerSeatPool struct {
+	ID          string    `gorm:"type:varchar(36);primaryKey" json:"id"`                    // 席位池ID
+	CustomerID  string    `gorm:"type:varchar(36);not null;uniqueIndex" json:"customer_id"` // 客户ID，每个客户最多一个席位池
+	TotalSeats  int       `gorm:"not null" json:"total_seats"`                              // 总席位数
+	Description *string   `gorm:"type:varchar(500)" json:"description"`                     // 说明
+	CreatedBy   string    `gorm:"type:varchar(36);not null" json:"created_by"`              // 创建人ID
+	UpdatedBy   string    `gorm:"type:varchar(36);not null" json:"updated_by"`              // 最后修改人ID
+	CreatedAt   time.Time `gorm:"type:datetime(3);not null" json:"created_at"`              // 创建时间
+	UpdatedAt   time.Time `gorm:"type:datetime(3);not null" json:"updated_at"`              // 更新时间
+
+	// 使用情况（由仓储层统计）
+	PoolCodeCount  int64 `gorm:"-" json:"pool_code_count"` // 从席位池取用席位的有效授权码数量
+	UsedSeats      int64 `gorm:"-" json:"used_seats"`      // 已占用席位数（有效授权码下的激活许可证）
+	RemainingSeats int64 `gorm:"-" json:"remaining_seats"` // 剩余席位数，占用超出总数时为0
+}
+
+// TableName 指定表名
+func (CustomerSeatPool) TableName() string {
+	return "customer_seat_pools"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (p *CustomerSeatPool) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = now
+	}
+	if p.UpdatedAt.IsZero() {
+		p.UpdatedAt = now
+	}
+	return nil
+}
+
+// SetUsage 设置已占用席位并计算剩余席位
+func (p *CustomerSeatPool) SetUsage(codeCount, usedSeats int64) {
+	p.PoolCodeCount = codeCount
+	p.UsedSeats = usedSeats
+	p.RemainingSeats = int64(p.TotalSeats) - usedSeats
+	if p.RemainingSeats < 0 {
+		p.RemainingSeats = 0
+	}
+}
+
+// CustomerSeatPoolSaveRequest 创建或更新客户席位池请求
+type CustomerSeatPoolSaveRequest struct {
+	TotalSeats  int     `json:"total_seats" binding:"required,min=1,max=1000000"` // 总席位数，不能少于已占用席位
+	Description *string `json:"description" binding:"omitempty,max=500"`          // 说明，可选
+}
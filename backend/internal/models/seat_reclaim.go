@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SeatReclaimTrigger 席位回收触发方式
+type SeatReclaimTrigger string
+
+const (
+	SeatReclaimTriggerScheduled SeatReclaimTrigger = "scheduled" // 定时任务触发
+	SeatReclaimTriggerManual    SeatReclaimTrigger = "manual"    // 管理员手动触发
+)
+
+// SeatReclamation 闲置席位回收记录
+type SeatReclamation struct {
+	ID                  string     `gorm:"type:varchar(36);primaryKey" json:"id"`                        // 回收记录ID
+	LicenseID           string     `gorm:"type:varchar(36);not null;index" json:"license_id"`            // 许可证ID
+	LicenseKey          string     `gorm:"type:varchar(200);not null" json:"license_key"`                // 许可证密钥
+	AuthorizationCodeID string     `gorm:"type:varchar(36);not null;index" json:"authorization_code_id"` // 授权码ID
+	CustomerID          string     `gorm:"type:varchar(36);not null;index" json:"customer_id"`           // 客户ID
+	HardwareFingerprint string     `gorm:"type:varchar(200);not null" json:"hardware_fingerprint"`       // 硬件指纹
+	LastHeartbeat       *time.Time `gorm:"type:datetime(3)" json:"last_heartbeat"`                       // 回收时的最后心跳时间
+	PolicyDays          int        `gorm:"not null" json:"policy_days"`                                  // 生效的回收策略（无心跳天数）
+	TriggerType         string     `gorm:"type:varchar(20);not null" json:"trigger_type"`                // 触发方式：scheduled/manual
+	ReclaimedAt         time.Time  `gorm:"type:datetime(3);not null;index" json:"reclaimed_at"`          // 回收时间
+}
+
+// TableName 指定表名
+func (SeatReclamation) TableName() string {
+	return "seat_reclamations"
+}
+
+// BeforeCreate 创建前自动设置ID和回收时间
+func (r *SeatReclamation) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	if r.ReclaimedAt.IsZero() {
+		r.ReclaimedAt = time.Now()
+	}
+	return nil
+}
+
+// DormantLicense 待回收的闲置许可证（查询结果）
+type DormantLicense struct {
+	ID                  string     `json:"id"`                    // 许可证ID
+	LicenseKey          string     `json:"license_key"`           // 许可证密钥
+	AuthorizationCodeID string     `json:"authorization_code_id"` // 授权码ID
+	AuthorizationCode   string     `json:"authorization_code"`    // 授权码
+	CustomerID          string     `json:"customer_id"`           // 客户ID
+	HardwareFingerprint string     `json:"hardware_fingerprint"`  // 硬件指纹
+	DeviceInfo          JSON       `json:"device_info"`           // 设备信息
+	LastHeartbeat       *time.Time `json:"last_heartbeat"`        // 最后心跳时间
+	DormantReclaimDays  int        `json:"dormant_reclaim_days"`  // 授权码的回收策略（天）
+}
+
+// SeatReclamationListRequest 席位回收记录列表查询请求
+type SeatReclamationListRequest struct {
+	Page                int    `form:"page" binding:"omitempty,min=1"`                          // 页码，默认1
+	PageSize            int    `form:"page_size" binding:"omitempty,min=1,max=100"`             // 每页条数，默认20，最大100
+	AuthorizationCodeID string `form:"authorization_code_id" binding:"omitempty"`               // 授权码ID筛选
+	CustomerID          string `form:"customer_id" binding:"omitempty"`                         // 客户ID筛选
+	TriggerType         string `form:"trigger_type" binding:"omitempty,oneof=scheduled manual"` // 触发方式筛选
+}
+
+// SeatReclamationListItem 席位回收记录列表项
+type SeatReclamationListItem struct {
+	ID                  string  `json:"id"`                             // 回收记录ID
+	LicenseID           string  `json:"license_id"`                     // 许可证ID
+	LicenseKey          string  `json:"license_key"`                    // 许可证密钥
+	AuthorizationCodeID string  `json:"authorization_code_id"`          // 授权码ID
+	AuthorizationCode   string  `json:"authorization_code"`             // 授权码
+	CustomerID          string  `json:"customer_id"`                    // 客户ID
+	CustomerName        string  `json:"customer_name"`                  // 客户名称
+	HardwareFingerprint string  `json:"hardware_fingerprint"`           // 硬件指纹
+	LastHeartbeat       *string `json:"last_heartbeat"`                 // 回收时的最后心跳时间
+	PolicyDays          int     `json:"policy_days"`                    // 回收策略（天）
+	TriggerType         string  `json:"trigger_type"`                   // 触发方式
+	TriggerTypeDisplay  string  `json:"trigger_type_display,omitempty"` // 触发方式显示（多语言）
+	ReclaimedAt         string  `json:"reclaimed_at"`                   // 回收时间
+}
+
+// SeatReclamationListResponse 席位回收记录列表响应
+type SeatReclamationListResponse struct {
+	List       []SeatReclamationListItem `json:"list"`        // 回收记录列表
+	Total      int64                     `json:"total"`       // 总记录数
+	Page       int                       `json:"page"`        // 当前页码
+	PageSize   int                       `json:"page_size"`   // 每页条数
+	TotalPages int                       `json:"total_pages"` // 总页数
+}
+
+// SeatReclaimRunResponse 执行一次席位回收的结果
+type SeatReclaimRunResponse struct {
+	Scanned   int `json:"scanned"`   // 扫描到的闲置许可证数量
+	Reclaimed int `json:"reclaimed"` // 实际回收的许可证数量
+	Failed    int `json:"failed"`    // 回收失败数量
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// 发布渠道
+const (
+	ReleaseChannelStable = "stable" // 正式版
+	ReleaseChannelBeta   = "beta"   // 测试版
+)
+
+// SoftwareRelease 产品版本发布记录，按产品（授权码的 software_id）管理
+type SoftwareRelease struct {
+	ID             string    `gorm:"type:varchar(36);primaryKey" json:"id"`                                                            // 版本记录ID
+	SoftwareID     string    `gorm:"type:varchar(50);not null;uniqueIndex:uk_software_releases_version,priority:1" json:"software_id"` // 产品（软件ID）
+	Version        string    `gorm:"type:varchar(50);not null;uniqueIndex:uk_software_releases_version,priority:2" json:"version"`     // 版本号，如 2.3.1、2.4.0-beta.1
+	Channel        string    `gorm:"type:varchar(20);not null;default:'stable';index" json:"channel"`                                  // 发布渠道：stable/beta
+	ChannelDisplay string    `gorm:"-" json:"channel_display,omitempty"`                                                               // 发布渠道显示（多语言）
+	ReleasedAt     time.Time `gorm:"type:datetime(3);not null;index" json:"released_at"`                                               // 发布时间，早于维护截止时间的版本才可安装
+	DownloadURL    string    `gorm:"type:varchar(1000);not null" json:"download_url"`                                                  // 下载地址
+	Checksum       string    `gorm:"type:varchar(200);not null" json:"checksum"`                                                       // 安装包校验值，如 sha256:<hex>
+	ReleaseNotes   *string   `gorm:"type:text" json:"release_notes"`                                                                   // 更新说明
+	IsPublished    bool      `gorm:"not null;default:true" json:"is_published"`                                                        // 是否发布，未发布的版本不会推送给客户端
+	CreatedBy      string    `gorm:"type:varchar(36);not null" json:"created_by"`                                                      // 创建人ID
+	CreatedAt      time.Time `gorm:"type:datetime(3);not null" json:"created_at"`                                                      // 创建时间
+	UpdatedAt      time.Time `gorm:"type:datetime(3);not null" json:"updated_at"`                                                      // 更新时间
+}
+
+// TableName 指定表名
+func (SoftwareRelease) TableName() string {
+	return "software_releases"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (r *SoftwareRelease) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = now
+	}
+	if r.UpdatedAt.IsZero() {
+		r.UpdatedAt = now
+	}
+	return nil
+}
+
+// BeforeUpdate 更新前自动设置时间戳
+func (r *SoftwareRelease) BeforeUpdate(tx *gorm.DB) error {
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// SoftwareReleaseCreateRequest 创建版本请求
+type SoftwareReleaseCreateRequest struct {
+	SoftwareID   string     `json:"software_id" binding:"required,max=50"`         // 产品（软件ID）
+	Version      string     `json:"version" binding:"required,max=50"`             // 版本号
+	Channel      string     `json:"channel" binding:"omitempty,oneof=stable beta"` // 发布渠道，默认stable
+	ReleasedAt   *time.Time `json:"released_at" binding:"omitempty"`               // 发布时间，默认当前时间
+	DownloadURL  string     `json:"download_url" binding:"required,url,max=1000"`  // 下载地址
+	Checksum     string     `json:"checksum" binding:"required,max=200"`           // 安装包校验值
+	ReleaseNotes *string    `json:"release_notes" binding:"omitempty"`             // 更新说明
+	IsPublished  *bool      `json:"is_published" binding:"omitempty"`              // 是否发布，默认true
+}
+
+// SoftwareReleaseUpdateRequest 更新版本请求（产品和版本号不可修改）
+type SoftwareReleaseUpdateRequest struct {
+	Channel      *string    `json:"channel" binding:"omitempty,oneof=stable beta"` // 发布渠道
+	ReleasedAt   *time.Time `json:"released_at" binding:"omitempty"`               // 发布时间
+	DownloadURL  *string    `json:"download_url" binding:"omitempty,url,max=1000"` // 下载地址
+	Checksum     *string    `json:"checksum" binding:"omitempty,max=200"`          // 安装包校验值
+	ReleaseNotes *string    `json:"release_notes" binding:"omitempty"`             // 更新说明
+	IsPublished  *bool      `json:"is_published" binding:"omitempty"`              // 是否发布
+}
+
+// SoftwareReleaseListRequest 版本列表查询请求
+type SoftwareReleaseListRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`                // 页码，默认1
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`   // 每页条数，默认20，最大100
+	SoftwareID string `form:"software_id" binding:"omitempty"`               // 产品筛选
+	Channel    string `form:"channel" binding:"omitempty,oneof=stable beta"` // 发布渠道筛选
+}
+
+// SoftwareReleaseListResponse 版本列表响应
+type SoftwareReleaseListResponse struct {
+	List       []*SoftwareRelease `json:"list"`        // 版本列表
+	Total      int64              `json:"total"`       // 总记录数
+	Page       int                `json:"page"`        // 当前页码
+	PageSize   int                `json:"page_size"`   // 每页条数
+	TotalPages int                `json:"total_pages"` // 总页数
+}
+
+// ReleaseAdvertisement 心跳中下发的可安装版本
+type ReleaseAdvertisement struct {
+	Version      string    `json:"version"`       // 版本号
+	Channel      string    `json:"channel"`       // 发布渠道
+	ReleasedAt   time.Time `json:"released_at"`   // 发布时间
+	DownloadURL  string    `json:"download_url"`  // 下载地址
+	Checksum     string    `json:"checksum"`      // 安装包校验值
+	ReleaseNotes *string   `json:"release_notes"` // 更新说明
+}
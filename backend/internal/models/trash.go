@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// 回收站的对象类型
+const (
+	TrashEntityCustomer          = "customer"           // 客户
+	TrashEntityAuthorizationCode = "authorization_code" // 授权码
+	TrashEntityPackage           = "package"            // 套餐
+	TrashEntityLead              = "lead"               // 线索
+	TrashEntityCuUser            = "cu_user"            // 客户端用户
+)
+
+// TrashListRequest 回收站列表请求
+type TrashListRequest struct {
+	EntityType string `form:"entity_type" binding:"omitempty,oneof=customer authorization_code package lead cu_user"` // 对象类型筛选，默认全部
+	Search     string `form:"search" binding:"omitempty,max=100"`                                                     // 按名称搜索（客户名称、授权码、套餐名称、公司名称或手机号）
+	Page       int    `form:"page" binding:"omitempty,min=1"`                                                         // 页码，默认1
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`                                            // 每页条数，默认20，最大100
+}
+
+// TrashItem 回收站条目
+type TrashItem struct {
+	EntityType        string     `json:"entity_type"`                   // 对象类型
+	EntityTypeDisplay string     `json:"entity_type_display,omitempty"` // 对象类型显示（多语言）
+	EntityID          string     `json:"entity_id"`                     // 对象ID
+	Name              string     `json:"name"`                          // 对象名称：客户名称、授权码、套餐名称、线索公司名称或客户端用户手机号
+	CustomerID        *string    `json:"customer_id"`                   // 所属客户ID（套餐没有）
+	DeletedAt         time.Time  `json:"deleted_at"`                    // 删除时间
+	PurgeAt           *time.Time `json:"purge_at"`                      // 预计自动彻底删除时间，未开启自动清理时为空
+}
+
+// TrashListResponse 回收站列表响应（按删除时间倒序）
+type TrashListResponse struct {
+	List     []*TrashItem `json:"list"`
+	Total    int64        `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+}
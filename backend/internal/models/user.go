@@ -7,6 +7,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// 后台用户角色
+const (
+	UserRoleAdmin         = "admin"
+	UserRoleAdministrator = "administrator"
+	UserRolePartner       = "partner" // 经销商：只能管理其名下客户
+)
+
 // User 用户模型
 type User struct {
 	ID            string    `gorm:"type:varchar(36);primaryKey" json:"id"`
@@ -21,6 +28,7 @@ type User struct {
 	LastLoginIP   *string   `gorm:"type:varchar(45)" json:"last_login_ip"`
 	LoginAttempts int       `gorm:"not null;default:0" json:"login_attempts"`
 	LockedUntil   *time.Time `gorm:"index" json:"locked_until"`
+	SeatQuota     int       `gorm:"not null;default:0" json:"seat_quota"` // 经销商席位额度（仅经销商账号使用）
 	CreatedAt     time.Time `gorm:"not null;index" json:"created_at"`
 	UpdatedAt     time.Time `gorm:"not null" json:"updated_at"`
 }
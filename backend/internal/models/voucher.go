@@ -0,0 +1,169 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VoucherStatus 兑换券状态
+type VoucherStatus string
+
+const (
+	VoucherStatusUnused   VoucherStatus = "unused"   // 未兑换
+	VoucherStatusRedeemed VoucherStatus = "redeemed" // 已兑换
+	VoucherStatusExpired  VoucherStatus = "expired"  // 已过期（未兑换且超过兑换截止时间，查询时计算）
+)
+
+// Voucher 预付费兑换券，生成时不绑定客户，兑换后在兑换人所属客户下生成授权码
+type Voucher struct {
+	ID                  string     `gorm:"type:varchar(36);primaryKey" json:"id"`                          // 兑换券ID
+	Code                string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"code"`              // 兑换码
+	Label               string     `gorm:"type:varchar(100);not null;default:'';index" json:"label"`       // 标签（渠道/批次追踪）
+	PackageID           string     `gorm:"type:varchar(36);not null;index" json:"package_id"`              // 套餐ID
+	PackageName         string     `gorm:"type:varchar(100);not null" json:"package_name"`                 // 套餐名称（生成时快照）
+	LicenseCount        int        `gorm:"not null" json:"license_count"`                                  // 席位数
+	CreateOrder         bool       `gorm:"not null;default:false" json:"create_order"`                     // 兑换时是否生成零金额订单
+	ExpiresAt           *time.Time `gorm:"type:datetime(3);index" json:"expires_at"`                       // 兑换截止时间，为空不过期
+	Status              string     `gorm:"type:varchar(20);not null;default:'unused';index" json:"status"` // 状态：unused/redeemed
+	StatusDisplay       string     `gorm:"-" json:"status_display,omitempty"`                              // 状态显示（多语言）
+	Remark              *string    `gorm:"type:varchar(500)" json:"remark"`                                // 备注
+	CreatedBy           string     `gorm:"type:varchar(36);not null" json:"created_by"`                    // 创建人ID
+	RedeemedAt          *time.Time `gorm:"type:datetime(3);index" json:"redeemed_at"`                      // 兑换时间
+	RedeemedCustomerID  *string    `gorm:"type:varchar(36);index" json:"redeemed_customer_id"`             // 兑换客户ID
+	RedeemedCuUserID    *string    `gorm:"type:varchar(36)" json:"redeemed_cu_user_id"`                    // 兑换用户ID
+	AuthorizationCodeID *string    `gorm:"type:varchar(36)" json:"authorization_code_id"`                  // 兑换生成的授权码ID
+	OrderID             *string    `gorm:"type:varchar(36)" json:"order_id"`                               // 兑换生成的订单ID
+	CreatedAt           time.Time  `gorm:"type:datetime(3);not null" json:"created_at"`                    // 创建时间
+	UpdatedAt           time.Time  `gorm:"type:datetime(3);not null" json:"updated_at"`                    // 更新时间
+}
+
+// TableName 指定表名
+func (Voucher) TableName() string {
+	return "vouchers"
+}
+
+// BeforeCreate 创建前自动设置ID和时间戳
+func (v *Voucher) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if v.CreatedAt.IsZero() {
+		v.CreatedAt = now
+	}
+	if v.UpdatedAt.IsZero() {
+		v.UpdatedAt = now
+	}
+	return nil
+}
+
+// EffectiveStatus 计算兑换券的展示状态，未兑换且已过截止时间视为过期
+func (v *Voucher) EffectiveStatus(now time.Time) string {
+	if v.Status == string(VoucherStatusUnused) && v.ExpiresAt != nil && now.After(*v.ExpiresAt) {
+		return string(VoucherStatusExpired)
+	}
+	return v.Status
+}
+
+// VoucherCreateRequest 生成兑换券请求
+type VoucherCreateRequest struct {
+	PackageID    string     `json:"package_id" binding:"required"`              // 套餐ID
+	LicenseCount int        `json:"license_count" binding:"required,min=1"`     // 每张兑换券的席位数
+	Quantity     int        `json:"quantity" binding:"required,min=1,max=1000"` // 生成数量
+	ExpiresAt    *time.Time `json:"expires_at" binding:"omitempty"`             // 兑换截止时间（RFC3339），为空不过期
+	CreateOrder  bool       `json:"create_order"`                               // 兑换时是否生成零金额订单
+	Label        string     `json:"label" binding:"omitempty,max=100"`          // 标签
+	Remark       *string    `json:"remark" binding:"omitempty,max=500"`         // 备注
+}
+
+// VoucherCreateResponse 生成兑换券响应
+type VoucherCreateResponse struct {
+	Count    int        `json:"count"`    // 生成数量
+	Vouchers []*Voucher `json:"vouchers"` // 兑换券列表
+}
+
+// VoucherListRequest 兑换券列表查询请求
+type VoucherListRequest struct {
+	Page      int    `form:"page" binding:"omitempty,min=1"`                           // 页码，默认1
+	PageSize  int    `form:"page_size" binding:"omitempty,min=1,max=100"`              // 每页条数，默认20，最大100
+	Code      string `form:"code" binding:"omitempty"`                                 // 兑换码精确查询
+	Label     string `form:"label" binding:"omitempty,max=100"`                        // 标签模糊搜索
+	PackageID string `form:"package_id" binding:"omitempty"`                           // 套餐ID筛选
+	Status    string `form:"status" binding:"omitempty,oneof=unused redeemed expired"` // 状态筛选
+}
+
+// VoucherListResponse 兑换券列表响应
+type VoucherListResponse struct {
+	List       []*Voucher `json:"list"`        // 兑换券列表
+	Total      int64      `json:"total"`       // 总记录数
+	Page       int        `json:"page"`        // 当前页码
+	PageSize   int        `json:"page_size"`   // 每页条数
+	TotalPages int        `json:"total_pages"` // 总页数
+}
+
+// VoucherRedeemRequest 兑换请求
+type VoucherRedeemRequest struct {
+	Code string `json:"code" binding:"required,max=64"` // 兑换码
+}
+
+// VoucherRedeemResponse 兑换结果
+type VoucherRedeemResponse struct {
+	VoucherID            string    `json:"voucher_id"`             // 兑换券ID
+	PackageName          string    `json:"package_name"`           // 套餐名称
+	LicenseCount         int       `json:"license_count"`          // 席位数
+	AuthorizationCodeID  string    `json:"authorization_code_id"`  // 生成的授权码ID
+	AuthorizationCode    string    `json:"authorization_code"`     // 生成的授权码
+	AuthorizationEndDate time.Time `json:"authorization_end_date"` // 授权到期时间
+	OrderID              *string   `json:"order_id"`               // 生成的订单ID（未配置生成订单时为空）
+	OrderNo              *string   `json:"order_no"`               // 生成的订单号
+	RedeemedAt           time.Time `json:"redeemed_at"`            // 兑换时间
+}
+
+// VoucherRedemptionReportRequest 兑换报表查询请求
+type VoucherRedemptionReportRequest struct {
+	Page       int    `form:"page" binding:"omitempty,min=1"`              // 页码，默认1
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"` // 每页条数，默认20，最大100
+	Label      string `form:"label" binding:"omitempty,max=100"`           // 标签模糊搜索
+	PackageID  string `form:"package_id" binding:"omitempty"`              // 套餐ID筛选
+	CustomerID string `form:"customer_id" binding:"omitempty"`             // 兑换客户筛选
+	StartDate  string `form:"start_date" binding:"omitempty"`              // 兑换开始日期（YYYY-MM-DD）
+	EndDate    string `form:"end_date" binding:"omitempty"`                // 兑换结束日期（YYYY-MM-DD）
+}
+
+// VoucherRedemptionItem 兑换记录
+type VoucherRedemptionItem struct {
+	VoucherID           string  `json:"voucher_id"`            // 兑换券ID
+	Code                string  `json:"code"`                  // 兑换码
+	Label               string  `json:"label"`                 // 标签
+	PackageID           string  `json:"package_id"`            // 套餐ID
+	PackageName         string  `json:"package_name"`          // 套餐名称
+	LicenseCount        int     `json:"license_count"`         // 席位数
+	CustomerID          string  `json:"customer_id"`           // 兑换客户ID
+	CustomerName        string  `json:"customer_name"`         // 兑换客户名称
+	CuUserID            string  `json:"cu_user_id"`            // 兑换用户ID
+	CuUserPhone         string  `json:"cu_user_phone"`         // 兑换用户手机号
+	AuthorizationCodeID string  `json:"authorization_code_id"` // 生成的授权码ID
+	OrderID             *string `json:"order_id"`              // 生成的订单ID
+	RedeemedAt          string  `json:"redeemed_at"`           // 兑换时间
+}
+
+// VoucherRedemptionSummary 兑换统计（受标签和套餐筛选影响）
+type VoucherRedemptionSummary struct {
+	Total         int64 `json:"total"`          // 兑换券总数
+	Unused        int64 `json:"unused"`         // 未兑换且未过期
+	Redeemed      int64 `json:"redeemed"`       // 已兑换
+	Expired       int64 `json:"expired"`        // 已过期未兑换
+	RedeemedSeats int64 `json:"redeemed_seats"` // 已兑换席位总数
+}
+
+// VoucherRedemptionReportResponse 兑换报表响应
+type VoucherRedemptionReportResponse struct {
+	Summary    VoucherRedemptionSummary `json:"summary"`     // 统计
+	List       []VoucherRedemptionItem  `json:"list"`        // 兑换记录
+	Total      int64                    `json:"total"`       // 兑换记录总数
+	Page       int                      `json:"page"`        // 当前页码
+	PageSize   int                      `json:"page_size"`   // 每页条数
+	TotalPages int                      `json:"total_pages"` // 总页数
+}
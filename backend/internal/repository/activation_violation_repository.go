@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ActivationViolationRepository 使用限制违规事件仓储接口
+type ActivationViolationRepository interface {
+	Create(ctx context.Context, violation *models.ActivationViolation) error
+	GetList(ctx context.Context, req *models.ActivationViolationListRequest) ([]*models.ActivationViolation, int64, error)
+}
+
+type activationViolationRepository struct {
+	db *gorm.DB
+}
+
+// NewActivationViolationRepository 创建使用限制违规事件仓储
+func NewActivationViolationRepository(db *gorm.DB) ActivationViolationRepository {
+	return &activationViolationRepository{db: db}
+}
+
+// Create 记录违规事件
+func (r *activationViolationRepository) Create(ctx context.Context, violation *models.ActivationViolation) error {
+	return r.db.WithContext(ctx).Create(violation).Error
+}
+
+// GetList 分页查询违规事件，按发生时间倒序
+func (r *activationViolationRepository) GetList(ctx context.Context, req *models.ActivationViolationListRequest) ([]*models.ActivationViolation, int64, error) {
+	var violations []*models.ActivationViolation
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.ActivationViolation{})
+	if req.AuthorizationCodeID != "" {
+		query = query.Where("authorization_code_id = ?", req.AuthorizationCodeID)
+	}
+	if req.CustomerID != "" {
+		query = query.Where("customer_id = ?", req.CustomerID)
+	}
+	if req.ViolationType != "" {
+		query = query.Where("violation_type = ?", req.ViolationType)
+	}
+	if req.Stage != "" {
+		query = query.Where("stage = ?", req.Stage)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&violations).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return violations, total, nil
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AuthorizationCodeBatchRepository 授权码批次仓储接口
+type AuthorizationCodeBatchRepository interface {
+	Create(ctx context.Context, batch *models.AuthorizationCodeBatch) error
+	GetByID(ctx context.Context, id string) (*models.AuthorizationCodeBatch, error)
+	GetList(ctx context.Context, req *models.AuthorizationCodeBatchListRequest) ([]*models.AuthorizationCodeBatch, int64, error)
+	GetUnfinished(ctx context.Context) ([]*models.AuthorizationCodeBatch, error)
+	// Claim 以租约方式领取待生成或租约已过期的批次，多实例下同一批次只由一个实例生成
+	Claim(ctx context.Context, id, owner string, lease time.Duration) (bool, error)
+	UpdateFields(ctx context.Context, id string, updates map[string]interface{}) error
+	// UpdateLeasedFields 以租约持有者身份更新批次字段并续约，租约已被接管时返回 ErrJobLeaseLost
+	UpdateLeasedFields(ctx context.Context, id, owner string, lease time.Duration, updates map[string]interface{}) error
+	// CreateCodes 写入一组授权码、累加已生成数量并续约，租约已被接管时返回 ErrJobLeaseLost
+	CreateCodes(ctx context.Context, batchID, owner string, lease time.Duration, codes []*models.AuthorizationCode) error
+	GetCodes(ctx context.Context, batchID string) ([]*models.AuthorizationCode, error)
+	LockCodes(ctx context.Context, batchID string, updates map[string]interface{}, isLocked bool, changes []*models.AuthorizationChange) (int64, error)
+	RevokeBatch(ctx context.Context, batch *models.AuthorizationCodeBatch, codeUpdates map[string]interface{}, changes []*models.AuthorizationChange) (int64, int64, error)
+}
+
+type authorizationCodeBatchRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthorizationCodeBatchRepository 创建授权码批次仓储
+func NewAuthorizationCodeBatchRepository(db *gorm.DB) AuthorizationCodeBatchRepository {
+	return &authorizationCodeBatchRepository{db: db}
+}
+
+// Create 创建批次
+func (r *authorizationCodeBatchRepository) Create(ctx context.Context, batch *models.AuthorizationCodeBatch) error {
+	return r.db.WithContext(ctx).Create(batch).Error
+}
+
+// GetByID 根据ID获取批次
+func (r *authorizationCodeBatchRepository) GetByID(ctx context.Context, id string) (*models.AuthorizationCodeBatch, error) {
+	var batch models.AuthorizationCodeBatch
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&batch).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAuthorizationCodeBatchNotFound
+		}
+		return nil, err
+	}
+	r.fillCustomerNames(ctx, []*models.AuthorizationCodeBatch{&batch})
+	return &batch, nil
+}
+
+// GetList 查询批次列表
+func (r *authorizationCodeBatchRepository) GetList(ctx context.Context, req *models.AuthorizationCodeBatchListRequest) ([]*models.AuthorizationCodeBatch, int64, error) {
+	var batches []*models.AuthorizationCodeBatch
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AuthorizationCodeBatch{})
+
+	if req.CustomerID != "" {
+		query = query.Where("customer_id = ?", req.CustomerID)
+	}
+	if req.Label != "" {
+		query = query.Where("label LIKE ?", "%"+req.Label+"%")
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&batches).Error; err != nil {
+		return nil, 0, err
+	}
+
+	r.fillCustomerNames(ctx, batches)
+	return batches, total, nil
+}
+
+// fillCustomerNames 批量填充客户名称
+func (r *authorizationCodeBatchRepository) fillCustomerNames(ctx context.Context, batches []*models.AuthorizationCodeBatch) {
+	if len(batches) == 0 {
+		return
+	}
+	customerIDs := make([]string, 0, len(batches))
+	for _, batch := range batches {
+		customerIDs = append(customerIDs, batch.CustomerID)
+	}
+
+	var customers []struct {
+		ID           string
+		CustomerName string
+	}
+	if err := r.db.WithContext(ctx).Model(&models.Customer{}).Select("id, customer_name").
+		Where("id IN ?", customerIDs).Scan(&customers).Error; err != nil {
+		return
+	}
+
+	names := make(map[string]string, len(customers))
+	for _, customer := range customers {
+		names[customer.ID] = customer.CustomerName
+	}
+	for _, batch := range batches {
+		batch.CustomerName = names[batch.CustomerID]
+	}
+}
+
+// GetUnfinished 获取尚未生成完成的批次（服务重启后续跑）
+func (r *authorizationCodeBatchRepository) GetUnfinished(ctx context.Context) ([]*models.AuthorizationCodeBatch, error) {
+	var batches []*models.AuthorizationCodeBatch
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []string{
+			string(models.AuthorizationCodeBatchStatusPending),
+			string(models.AuthorizationCodeBatchStatusRunning),
+		}).
+		Order("created_at ASC").Find(&batches).Error
+	return batches, err
+}
+
+// Claim 以租约方式领取批次
+func (r *authorizationCodeBatchRepository) Claim(ctx context.Context, id, owner string, lease time.Duration) (bool, error) {
+	return claimJobLease(r.db.WithContext(ctx), &models.AuthorizationCodeBatch{}, id, owner, lease)
+}
+
+// UpdateFields 更新批次字段
+func (r *authorizationCodeBatchRepository) UpdateFields(ctx context.Context, id string, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	return r.db.WithContext(ctx).Model(&models.AuthorizationCodeBatch{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// UpdateLeasedFields 以租约持有者身份更新批次字段并续约
+func (r *authorizationCodeBatchRepository) UpdateLeasedFields(ctx context.Context, id, owner string, lease time.Duration, updates map[string]interface{}) error {
+	return updateLeasedJob(r.db.WithContext(ctx), &models.AuthorizationCodeBatch{}, id, owner, lease, updates)
+}
+
+// CreateCodes 在事务中写入一组授权码并累加批次已生成数量，租约已被接管时整体回滚
+func (r *authorizationCodeBatchRepository) CreateCodes(ctx context.Context, batchID, owner string, lease time.Duration, codes []*models.AuthorizationCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateLeasedJob(tx, &models.AuthorizationCodeBatch{}, batchID, owner, lease, map[string]interface{}{
+			"generated_count": gorm.Expr("generated_count + ?", len(codes)),
+		}); err != nil {
+			return err
+		}
+		return tx.Create(codes).Error
+	})
+}
+
+// GetCodes 获取批次内全部授权码
+func (r *authorizationCodeBatchRepository) GetCodes(ctx context.Context, batchID string) ([]*models.AuthorizationCode, error) {
+	var codes []*models.AuthorizationCode
+	err := r.db.WithContext(ctx).Where("batch_id = ?", batchID).Order("created_at ASC, id ASC").Find(&codes).Error
+	return codes, err
+}
+
+// LockCodes 在事务中整批锁定/解锁授权码并记录变更历史
+func (r *authorizationCodeBatchRepository) LockCodes(ctx context.Context, batchID string, updates map[string]interface{}, isLocked bool, changes []*models.AuthorizationChange) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AuthorizationCode{}).Where("batch_id = ?", batchID).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+
+		if len(changes) > 0 {
+			if err := tx.CreateInBatches(changes, 200).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.AuthorizationCodeBatch{}).Where("id = ?", batchID).
+			Updates(map[string]interface{}{"is_locked": isLocked, "updated_at": time.Now()}).Error
+	})
+	return affected, err
+}
+
+// RevokeBatch 在事务中撤销批次：锁定全部授权码、撤销其下未撤销的许可证并标记批次状态
+func (r *authorizationCodeBatchRepository) RevokeBatch(ctx context.Context, batch *models.AuthorizationCodeBatch, codeUpdates map[string]interface{}, changes []*models.AuthorizationChange) (int64, int64, error) {
+	var affectedCodes, revokedLicenses int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AuthorizationCode{}).Where("batch_id = ?", batch.ID).Updates(codeUpdates)
+		if result.Error != nil {
+			return result.Error
+		}
+		affectedCodes = result.RowsAffected
+
+		result = tx.Model(&models.License{}).
+			Where("authorization_code_id IN (?)", tx.Model(&models.AuthorizationCode{}).Select("id").Where("batch_id = ?", batch.ID)).
+			Where("status IN ? AND deleted_at IS NULL", []string{"active", "inactive"}).
+			Updates(map[string]interface{}{"status": "revoked", "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		revokedLicenses = result.RowsAffected
+
+		if len(changes) > 0 {
+			if err := tx.CreateInBatches(changes, 200).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.AuthorizationCodeBatch{}).Where("id = ?", batch.ID).
+			Updates(map[string]interface{}{
+				"status":        batch.Status,
+				"is_locked":     batch.IsLocked,
+				"revoke_reason": batch.RevokeReason,
+				"revoked_at":    batch.RevokedAt,
+				"updated_at":    time.Now(),
+			}).Error
+	})
+	return affectedCodes, revokedLicenses, err
+}
@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"license-manager/internal/models"
 	"math"
 	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type authorizationCodeRepository struct {
@@ -77,10 +79,13 @@ func (r *authorizationCodeRepository) GetAuthorizationCodeList(ctx context.Conte
 			FROM licenses
 			WHERE status = 'active' AND deleted_at IS NULL
 			GROUP BY authorization_code_id
-		) l ON ac.id = l.authorization_code_id`)
+		) l ON ac.id = l.authorization_code_id`).
+		Where("ac.deleted_at IS NULL")
 
 	// 添加筛选条件
-	if req.CustomerID != "" {
+	if req.CustomerIDs != nil {
+		query = query.Where("ac.customer_id IN ?", req.CustomerIDs)
+	} else if req.CustomerID != "" {
 		query = query.Where("ac.customer_id = ?", req.CustomerID)
 	}
 
@@ -107,6 +112,9 @@ func (r *authorizationCodeRepository) GetAuthorizationCodeList(ctx context.Conte
 		query = query.Where("ac.code LIKE ?", like)
 	}
 
+	// 标签和自定义字段筛选
+	query = applyCustomFieldFilter(query, models.CustomFieldEntityAuthorizationCode, "ac.id", &req.CustomFieldFilter)
+
 	// 获取总数
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -193,7 +201,7 @@ func (r *authorizationCodeRepository) GetCuAuthorizationCodeList(ctx context.Con
 	}
 
 	query := r.db.WithContext(ctx).Table("authorization_codes ac").
-		Select(`ac.id, ac.code, ac.created_at, ac.end_date, ac.max_activations,
+		Select(`ac.id, ac.code, ac.created_at, ac.end_date, ac.maintenance_until, ac.max_activations,
 				COALESCE(l.active_count, 0) AS current_activations,
 				CASE
 					WHEN ac.is_locked = true THEN 'locked'
@@ -207,7 +215,7 @@ func (r *authorizationCodeRepository) GetCuAuthorizationCodeList(ctx context.Con
 			WHERE status = 'active' AND deleted_at IS NULL
 			GROUP BY authorization_code_id
 		) l ON ac.id = l.authorization_code_id`).
-		Where("ac.customer_id = ?", customerID)
+		Where("ac.customer_id = ? AND ac.deleted_at IS NULL", customerID)
 
 	if status != "" {
 		switch status {
@@ -232,13 +240,14 @@ func (r *authorizationCodeRepository) GetCuAuthorizationCodeList(ctx context.Con
 
 	offset := (page - 1) * pageSize
 	var results []struct {
-		ID                 string    `json:"id"`
-		Code               string    `json:"code"`
-		CreatedAt          time.Time `json:"created_at"`
-		EndDate            time.Time `json:"end_date"`
-		MaxActivations     int       `json:"max_activations"`
-		CurrentActivations int       `json:"current_activations" gorm:"column:current_activations"`
-		Status             string    `json:"status"`
+		ID                 string     `json:"id"`
+		Code               string     `json:"code"`
+		CreatedAt          time.Time  `json:"created_at"`
+		EndDate            time.Time  `json:"end_date"`
+		MaintenanceUntil   *time.Time `json:"maintenance_until"`
+		MaxActivations     int        `json:"max_activations"`
+		CurrentActivations int        `json:"current_activations" gorm:"column:current_activations"`
+		Status             string     `json:"status"`
 	}
 
 	if err := query.Order("ac.created_at DESC").Limit(pageSize).Offset(offset).Scan(&results).Error; err != nil {
@@ -261,6 +270,10 @@ func (r *authorizationCodeRepository) GetCuAuthorizationCodeList(ctx context.Con
 			CreatedAt:            item.CreatedAt.Format(time.RFC3339),
 			EndDate:              item.EndDate.Format(time.RFC3339),
 		}
+		if item.MaintenanceUntil != nil {
+			maintenanceUntil := item.MaintenanceUntil.Format(time.RFC3339)
+			list[i].MaintenanceUntil = &maintenanceUntil
+		}
 	}
 
 	totalPages := int((total + int64(pageSize) - 1) / int64(pageSize))
@@ -292,7 +305,7 @@ func (r *authorizationCodeRepository) GetCuAuthorizationCodeSummary(ctx context.
 				THEN ac.max_activations
 				ELSE 0 END) AS valid_max_activations_sum
 		`).
-		Where("ac.customer_id = ?", customerID).
+		Where("ac.customer_id = ? AND ac.deleted_at IS NULL", customerID).
 		Scan(&result).Error
 	if err != nil {
 		return nil, err
@@ -312,8 +325,19 @@ func (r *authorizationCodeRepository) UpdateAuthorizationCode(ctx context.Contex
 }
 
 // DeleteAuthorizationCode 删除授权码（软删除）
+// 授权码下的许可证以相同的删除时间一并软删除，使其无法再心跳和下载，恢复授权码时按删除时间一并恢复
 func (r *authorizationCodeRepository) DeleteAuthorizationCode(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).Delete(&models.AuthorizationCode{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deletedAt := time.Now()
+		if err := tx.Model(&models.License{}).
+			Where("authorization_code_id = ?", id).
+			UpdateColumn("deleted_at", deletedAt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.AuthorizationCode{}).
+			Where("id = ?", id).
+			UpdateColumn("deleted_at", deletedAt).Error
+	})
 }
 
 // CheckCustomerExists 检查客户是否存在
@@ -432,6 +456,18 @@ func (r *authorizationCodeRepository) GetAuthorizationChangeList(ctx context.Con
 	}, nil
 }
 
+// GetAuthorizationChangeByID 根据ID获取授权变更记录（含配置快照）
+func (r *authorizationCodeRepository) GetAuthorizationChangeByID(ctx context.Context, id string) (*models.AuthorizationChange, error) {
+	var change models.AuthorizationChange
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&change).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrAuthorizationChangeNotFound
+		}
+		return nil, err
+	}
+	return &change, nil
+}
+
 // RecordAuthorizationChange 记录授权变更历史
 func (r *authorizationCodeRepository) RecordAuthorizationChange(ctx context.Context, change *models.AuthorizationChange) error {
 	return r.db.WithContext(ctx).Create(change).Error
@@ -450,6 +486,14 @@ func (r *authorizationCodeRepository) CreateAuthorizationCodeWithTx(ctx context.
 	return ErrInvalidTransaction
 }
 
+// UpdateAuthorizationCodeWithTx 在事务中更新授权码
+func (r *authorizationCodeRepository) UpdateAuthorizationCodeWithTx(ctx context.Context, tx interface{}, authCode *models.AuthorizationCode) error {
+	if gormTx, ok := tx.(*gorm.DB); ok {
+		return gormTx.WithContext(ctx).Save(authCode).Error
+	}
+	return ErrInvalidTransaction
+}
+
 // UpdateMaxActivationsWithTx 在事务中更新授权码的最大激活次数
 func (r *authorizationCodeRepository) UpdateMaxActivationsWithTx(ctx context.Context, tx interface{}, authCodeID string, newMaxActivations int) error {
 	if gormTx, ok := tx.(*gorm.DB); ok {
@@ -457,3 +501,242 @@ func (r *authorizationCodeRepository) UpdateMaxActivationsWithTx(ctx context.Con
 	}
 	return ErrInvalidTransaction
 }
+
+// authorizationCodeConfigColumns 授权码配置快照可回滚的字段
+var authorizationCodeConfigColumns = []string{
+	"software_id", "description", "start_date", "end_date", "maintenance_until",
+	"deployment_type", "relay_fingerprint", "encryption_type", "software_version",
+	"max_activations", "dormant_reclaim_days", "max_transfers", "transfer_period_days",
+	"feature_config", "usage_limits", "custom_parameters", "activation_policy", "updated_at",
+}
+
+// RevertAuthorizationCode 在事务中回滚授权码配置
+// 锁定授权码后由 apply 在最新数据上应用快照，按事务内的激活数校验席位，与并发激活和其他变更串行执行；
+// 只更新配置字段，不覆盖锁定状态、席位池等其他字段
+func (r *authorizationCodeRepository) RevertAuthorizationCode(ctx context.Context, id string, apply func(authCode *models.AuthorizationCode) error) (*models.AuthorizationCode, error) {
+	var authCode models.AuthorizationCode
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).First(&authCode).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAuthorizationCodeNotFound
+			}
+			return err
+		}
+
+		if err := apply(&authCode); err != nil {
+			return err
+		}
+
+		// 回滚后的席位数不能小于当前已激活数
+		var active int64
+		if err := tx.Model(&models.License{}).
+			Where("authorization_code_id = ? AND status = ?", id, "active").
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if int64(authCode.MaxActivations) < active {
+			return ErrAuthorizationCodeSeatsShort
+		}
+
+		authCode.UpdatedAt = time.Now()
+		return tx.Model(&authCode).Select(authorizationCodeConfigColumns).Updates(&authCode).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &authCode, nil
+}
+
+// SplitAuthorizationCode 在事务中更新原授权码、创建拆分出的授权码并迁移许可证
+// 锁定原授权码后核对席位数并在事务内读取已激活许可证，由 allocate 按拆分顺序分配（第一部分对应原授权码），
+// 与并发激活、分享和其他拆分合并串行执行；原授权码只更新席位数和描述
+func (r *authorizationCodeRepository) SplitAuthorizationCode(ctx context.Context, source *models.AuthorizationCode, children []*models.AuthorizationCode, allocate func(licenseIDs []string) [][]string) (int, error) {
+	moved := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockAuthorizationCodes(tx, []string{source.ID})
+		if err != nil {
+			return err
+		}
+		current := locked[source.ID]
+		if current.IsLocked {
+			return ErrAuthorizationCodeLocked
+		}
+		// 读取后席位数被修改时，拆分各部分之和不再等于原授权码席位数
+		total := source.MaxActivations
+		for _, child := range children {
+			total += child.MaxActivations
+		}
+		if total != current.MaxActivations {
+			return ErrAuthorizationCodeSeatsChanged
+		}
+
+		var licenseIDs []string
+		if err := tx.Model(&models.License{}).
+			Where("authorization_code_id = ? AND status = ?", source.ID, "active").
+			Order("activated_at ASC, id ASC").
+			Pluck("id", &licenseIDs).Error; err != nil {
+			return err
+		}
+		allocation := allocate(licenseIDs)
+
+		now := time.Now()
+		if err := tx.Model(&models.AuthorizationCode{}).Where("id = ?", source.ID).
+			Updates(map[string]interface{}{
+				"max_activations": source.MaxActivations,
+				"description":     source.Description,
+				"updated_at":      now,
+			}).Error; err != nil {
+			return err
+		}
+		for i, child := range children {
+			if err := tx.Create(child).Error; err != nil {
+				return err
+			}
+			licenseIDs := allocation[i+1]
+			if len(licenseIDs) == 0 {
+				continue
+			}
+			if err := tx.Model(&models.License{}).
+				Where("id IN ? AND authorization_code_id = ?", licenseIDs, source.ID).
+				Updates(map[string]interface{}{
+					"authorization_code_id": child.ID,
+					"updated_at":            now,
+				}).Error; err != nil {
+				return err
+			}
+			moved += len(licenseIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+// MergeAuthorizationCodes 在事务中将来源授权码并入目标授权码并迁移许可证，返回迁移的许可证数量
+// 锁定全部授权码后按最新的席位和有效期重新计算目标授权码，并回写到 target 和 sources；
+// 来源授权码的设备与目标授权码已有设备硬件指纹重复时不能合并
+func (r *authorizationCodeRepository) MergeAuthorizationCodes(ctx context.Context, target *models.AuthorizationCode, sources []*models.AuthorizationCode) (int64, error) {
+	var moved int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := []string{target.ID}
+		sourceIDs := make([]string, 0, len(sources))
+		for _, source := range sources {
+			sourceIDs = append(sourceIDs, source.ID)
+		}
+		ids = append(ids, sourceIDs...)
+
+		locked, err := lockAuthorizationCodes(tx, ids)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if locked[id].IsLocked {
+				return ErrAuthorizationCodeLocked
+			}
+		}
+
+		// 授权码下硬件指纹唯一（含已删除的许可证），合并前检查来源与目标之间是否有重复设备
+		var duplicates []string
+		if err := tx.Unscoped().Model(&models.License{}).
+			Where("(authorization_code_id IN ? AND deleted_at IS NULL) OR authorization_code_id = ?", sourceIDs, target.ID).
+			Group("hardware_fingerprint").
+			Having("COUNT(*) > 1").
+			Limit(1).
+			Pluck("hardware_fingerprint", &duplicates).Error; err != nil {
+			return err
+		}
+		if len(duplicates) > 0 {
+			return ErrAuthorizationCodeFingerprintConflict
+		}
+
+		current := locked[target.ID]
+		target.MaxActivations = current.MaxActivations
+		target.StartDate = current.StartDate
+		target.EndDate = current.EndDate
+		for _, source := range sources {
+			latest := locked[source.ID]
+			target.MaxActivations += latest.MaxActivations
+			if latest.StartDate.Before(target.StartDate) {
+				target.StartDate = latest.StartDate
+			}
+			if latest.EndDate.After(target.EndDate) {
+				target.EndDate = latest.EndDate
+			}
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.AuthorizationCode{}).Where("id = ?", target.ID).
+			Updates(map[string]interface{}{
+				"max_activations": target.MaxActivations,
+				"start_date":      target.StartDate,
+				"end_date":        target.EndDate,
+				"updated_at":      now,
+			}).Error; err != nil {
+			return err
+		}
+		for _, source := range sources {
+			source.MaxActivations = 0
+			source.IsLocked = true
+			if err := tx.Model(&models.AuthorizationCode{}).Where("id = ?", source.ID).
+				Updates(map[string]interface{}{
+					"max_activations": 0,
+					"is_locked":       true,
+					"lock_reason":     source.LockReason,
+					"locked_at":       source.LockedAt,
+					"locked_by":       source.LockedBy,
+					"updated_at":      now,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		// 包括已停用的许可证，保证设备历史跟随合并后的授权码
+		result := tx.Model(&models.License{}).
+			Where("authorization_code_id IN ?", sourceIDs).
+			Updates(map[string]interface{}{
+				"authorization_code_id": target.ID,
+				"updated_at":            now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+// lockAuthorizationCodes 按ID顺序锁定授权码并读取席位、有效期和锁定状态，避免多个授权码互相等待
+func lockAuthorizationCodes(tx *gorm.DB, ids []string) (map[string]*models.AuthorizationCode, error) {
+	var codes []*models.AuthorizationCode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "max_activations", "start_date", "end_date", "is_locked").
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&codes).Error; err != nil {
+		return nil, err
+	}
+
+	locked := make(map[string]*models.AuthorizationCode, len(codes))
+	for _, code := range codes {
+		locked[code.ID] = code
+	}
+	for _, id := range ids {
+		if locked[id] == nil {
+			return nil, ErrAuthorizationCodeNotFound
+		}
+	}
+	return locked, nil
+}
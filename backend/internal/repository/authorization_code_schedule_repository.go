@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuthorizationCodeScheduleRepository 授权码计划操作仓储接口
+type AuthorizationCodeScheduleRepository interface {
+	Create(ctx context.Context, action *models.AuthorizationCodeScheduledAction) error
+	GetByID(ctx context.Context, id string) (*models.AuthorizationCodeScheduledAction, error)
+	GetList(ctx context.Context, authCodeID string, req *models.AuthorizationCodeScheduledActionListRequest) ([]*models.AuthorizationCodeScheduledAction, int64, error)
+	Cancel(ctx context.Context, id string, now time.Time) error
+	FindDueActions(ctx context.Context, now time.Time, limit int) ([]*models.AuthorizationCodeScheduledAction, error)
+	Execute(ctx context.Context, action *models.AuthorizationCodeScheduledAction, apply func(authCode *models.AuthorizationCode) (*models.AuthorizationChange, error)) (*models.AuthorizationCode, error)
+	MarkFailed(ctx context.Context, id string, message string, now time.Time) error
+}
+
+type authorizationCodeScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthorizationCodeScheduleRepository 创建授权码计划操作仓储
+func NewAuthorizationCodeScheduleRepository(db *gorm.DB) AuthorizationCodeScheduleRepository {
+	return &authorizationCodeScheduleRepository{db: db}
+}
+
+// Create 创建计划操作
+func (r *authorizationCodeScheduleRepository) Create(ctx context.Context, action *models.AuthorizationCodeScheduledAction) error {
+	return r.db.WithContext(ctx).Create(action).Error
+}
+
+// GetByID 获取计划操作
+func (r *authorizationCodeScheduleRepository) GetByID(ctx context.Context, id string) (*models.AuthorizationCodeScheduledAction, error) {
+	var action models.AuthorizationCodeScheduledAction
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&action).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduledActionNotFound
+		}
+		return nil, err
+	}
+	return &action, nil
+}
+
+// GetList 查询授权码的计划操作，待执行的按执行时间排在前面
+func (r *authorizationCodeScheduleRepository) GetList(ctx context.Context, authCodeID string, req *models.AuthorizationCodeScheduledActionListRequest) ([]*models.AuthorizationCodeScheduledAction, int64, error) {
+	var actions []*models.AuthorizationCodeScheduledAction
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AuthorizationCodeScheduledAction{}).
+		Where("authorization_code_id = ?", authCodeID)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("status = 'pending' DESC, run_at ASC, created_at DESC").
+		Offset(offset).Limit(req.PageSize).Find(&actions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return actions, total, nil
+}
+
+// Cancel 取消计划操作，仅待执行的计划操作可以取消
+func (r *authorizationCodeScheduleRepository) Cancel(ctx context.Context, id string, now time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.AuthorizationCodeScheduledAction{}).
+		Where("id = ? AND status = ?", id, models.ScheduledActionStatusPending).
+		Updates(map[string]interface{}{
+			"status":      models.ScheduledActionStatusCancelled,
+			"executed_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrScheduledActionNotPending
+	}
+	return nil
+}
+
+// FindDueActions 查询触发条件已满足的待执行计划操作：到达执行时间，或关联订单已支付
+func (r *authorizationCodeScheduleRepository) FindDueActions(ctx context.Context, now time.Time, limit int) ([]*models.AuthorizationCodeScheduledAction, error) {
+	var actions []*models.AuthorizationCodeScheduledAction
+
+	err := r.db.WithContext(ctx).Model(&models.AuthorizationCodeScheduledAction{}).
+		Where("status = ?", models.ScheduledActionStatusPending).
+		Where(r.db.Where("trigger_type = ? AND run_at <= ?", models.ScheduledTriggerAt, now).
+			Or("trigger_type = ? AND EXISTS (SELECT 1 FROM cu_orders WHERE cu_orders.id = authorization_code_scheduled_actions.order_id AND cu_orders.status = ? AND cu_orders.deleted_at IS NULL)",
+				models.ScheduledTriggerOrderPaid, "paid")).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&actions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return actions, nil
+}
+
+// scheduledActionColumns 计划操作可能修改的授权码字段
+var scheduledActionColumns = []string{
+	"is_locked", "lock_reason", "locked_at", "locked_by", "end_date", "max_activations", "updated_at",
+}
+
+// Execute 在事务中锁定授权码，由 apply 在最新数据上应用计划操作并生成授权变更记录，
+// 只更新计划操作涉及的字段，记录变更历史并将计划操作标记为已执行，返回执行后的授权码
+// 仅待执行的计划操作会被执行，避免与取消或多实例调度冲突；调整后的席位数不能小于事务内的已激活数
+func (r *authorizationCodeScheduleRepository) Execute(ctx context.Context, action *models.AuthorizationCodeScheduledAction, apply func(authCode *models.AuthorizationCode) (*models.AuthorizationChange, error)) (*models.AuthorizationCode, error) {
+	var authCode models.AuthorizationCode
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", action.AuthorizationCodeID).First(&authCode).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAuthorizationCodeNotFound
+			}
+			return err
+		}
+
+		maxActivations := authCode.MaxActivations
+		change, err := apply(&authCode)
+		if err != nil {
+			return err
+		}
+
+		if authCode.MaxActivations < maxActivations {
+			var active int64
+			if err := tx.Model(&models.License{}).
+				Where("authorization_code_id = ? AND status = ?", authCode.ID, "active").
+				Count(&active).Error; err != nil {
+				return err
+			}
+			if int64(authCode.MaxActivations) < active {
+				return ErrAuthorizationCodeSeatsShort
+			}
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.AuthorizationCodeScheduledAction{}).
+			Where("id = ? AND status = ?", action.ID, models.ScheduledActionStatusPending).
+			Updates(map[string]interface{}{
+				"status":      models.ScheduledActionStatusDone,
+				"executed_at": action.ExecutedAt,
+				"change_id":   change.ID,
+				"updated_at":  now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrScheduledActionNotPending
+		}
+
+		authCode.UpdatedAt = now
+		if err := tx.Model(&authCode).Select(scheduledActionColumns).Updates(&authCode).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+
+		action.Status = string(models.ScheduledActionStatusDone)
+		action.ChangeID = &change.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &authCode, nil
+}
+
+// MarkFailed 将计划操作标记为执行失败
+func (r *authorizationCodeScheduleRepository) MarkFailed(ctx context.Context, id string, message string, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.AuthorizationCodeScheduledAction{}).
+		Where("id = ? AND status = ?", id, models.ScheduledActionStatusPending).
+		Updates(map[string]interface{}{
+			"status":        models.ScheduledActionStatusFailed,
+			"error_message": message,
+			"executed_at":   now,
+			"updated_at":    now,
+		}).Error
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AuthorizationCodeShareRepository 授权码分享记录仓储接口
+type AuthorizationCodeShareRepository interface {
+	CreateShare(ctx context.Context, share *models.AuthorizationCodeShare, child *models.AuthorizationCode, notification *models.Notification) error
+	AcceptShare(ctx context.Context, share *models.AuthorizationCodeShare, child *models.AuthorizationCode, notification *models.Notification) error
+	RespondShare(ctx context.Context, share *models.AuthorizationCodeShare, notification *models.Notification) error
+	ReclaimShare(ctx context.Context, share *models.AuthorizationCodeShare, count int, notification *models.Notification) error
+	GetByID(ctx context.Context, id string) (*models.AuthorizationCodeShare, error)
+	GetList(ctx context.Context, userID string, req *models.AuthorizationCodeShareListRequest) ([]*models.AuthorizationCodeShare, int64, error)
+}
+
+type authorizationCodeShareRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthorizationCodeShareRepository 创建授权码分享记录仓储
+func NewAuthorizationCodeShareRepository(db *gorm.DB) AuthorizationCodeShareRepository {
+	return &authorizationCodeShareRepository{db: db}
+}
+
+// moveSharedSeats 从原授权码扣减席位并创建分享生成的授权码
+func moveSharedSeats(tx *gorm.DB, share *models.AuthorizationCodeShare, child *models.AuthorizationCode) error {
+	// 锁定原授权码后按事务内的激活数核对未使用席位，避免与并发激活或分享同时通过检查
+	unused, err := lockUnusedSeats(tx, share.SourceCodeID)
+	if err != nil {
+		return err
+	}
+	if share.ShareCount > unused {
+		return ErrAuthorizationCodeSeatsShort
+	}
+
+	result := tx.Model(&models.AuthorizationCode{}).
+		Where("id = ? AND max_activations >= ?", share.SourceCodeID, share.ShareCount).
+		Updates(map[string]interface{}{
+			"max_activations": gorm.Expr("max_activations - ?", share.ShareCount),
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAuthorizationCodeSeatsShort
+	}
+
+	if err := tx.Create(child).Error; err != nil {
+		return err
+	}
+	share.TargetCodeID = &child.ID
+	return nil
+}
+
+// lockUnusedSeats 锁定授权码并返回未被激活占用的席位数，需在事务中调用
+// 激活新设备时同样先锁定授权码，席位的转出与激活串行执行
+func lockUnusedSeats(tx *gorm.DB, codeID string) (int, error) {
+	var code models.AuthorizationCode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "max_activations").Where("id = ?", codeID).First(&code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrAuthorizationCodeNotFound
+		}
+		return 0, err
+	}
+	var active int64
+	if err := tx.Model(&models.License{}).
+		Where("authorization_code_id = ? AND status = ?", codeID, "active").
+		Count(&active).Error; err != nil {
+		return 0, err
+	}
+	return code.MaxActivations - int(active), nil
+}
+
+// CreateShare 在事务中创建分享记录；直接分享时同时转移席位（child 非空）
+func (r *authorizationCodeShareRepository) CreateShare(ctx context.Context, share *models.AuthorizationCodeShare, child *models.AuthorizationCode, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if child != nil {
+			if err := moveSharedSeats(tx, share, child); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(share).Error; err != nil {
+			return err
+		}
+		if notification != nil {
+			if err := tx.Create(notification).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AcceptShare 在事务中接受分享邀请并转移席位，仅待确认的邀请可以接受
+func (r *authorizationCodeShareRepository) AcceptShare(ctx context.Context, share *models.AuthorizationCodeShare, child *models.AuthorizationCode, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := moveSharedSeats(tx, share, child); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.AuthorizationCodeShare{}).
+			Where("id = ? AND status = ?", share.ID, models.AuthorizationCodeShareStatusPending).
+			Updates(map[string]interface{}{
+				"status":         share.Status,
+				"target_code_id": share.TargetCodeID,
+				"responded_at":   share.RespondedAt,
+				"updated_at":     time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAuthorizationCodeShareNotPending
+		}
+
+		if notification != nil {
+			if err := tx.Create(notification).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RespondShare 拒绝或取消分享邀请（不涉及席位变动），仅待确认的邀请可以操作
+func (r *authorizationCodeShareRepository) RespondShare(ctx context.Context, share *models.AuthorizationCodeShare, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AuthorizationCodeShare{}).
+			Where("id = ? AND status = ?", share.ID, models.AuthorizationCodeShareStatusPending).
+			Updates(map[string]interface{}{
+				"status":       share.Status,
+				"responded_at": share.RespondedAt,
+				"updated_at":   time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAuthorizationCodeShareNotPending
+		}
+
+		if notification != nil {
+			if err := tx.Create(notification).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReclaimShare 在事务中将分享生成的授权码中未使用的席位退回原授权码
+func (r *authorizationCodeShareRepository) ReclaimShare(ctx context.Context, share *models.AuthorizationCodeShare, count int, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// 锁定受赠方授权码后按事务内的激活数核对未使用席位，收回期间新激活的席位不能被收回
+		unused, err := lockUnusedSeats(tx, *share.TargetCodeID)
+		if err != nil {
+			return err
+		}
+		if count > unused {
+			return ErrAuthorizationCodeSeatsShort
+		}
+
+		result := tx.Model(&models.AuthorizationCode{}).
+			Where("id = ? AND max_activations >= ?", *share.TargetCodeID, count).
+			Updates(map[string]interface{}{
+				"max_activations": gorm.Expr("max_activations - ?", count),
+				"updated_at":      now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAuthorizationCodeSeatsShort
+		}
+
+		if err := tx.Model(&models.AuthorizationCode{}).
+			Where("id = ?", share.SourceCodeID).
+			Updates(map[string]interface{}{
+				"max_activations": gorm.Expr("max_activations + ?", count),
+				"updated_at":      now,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.AuthorizationCodeShare{}).
+			Where("id = ?", share.ID).
+			Updates(map[string]interface{}{
+				"reclaimed_count":   gorm.Expr("reclaimed_count + ?", count),
+				"last_reclaimed_at": now,
+				"updated_at":        now,
+			}).Error; err != nil {
+			return err
+		}
+
+		if notification != nil {
+			if err := tx.Create(notification).Error; err != nil {
+				return err
+			}
+		}
+
+		share.ReclaimedCount += count
+		share.LastReclaimedAt = &now
+		return nil
+	})
+}
+
+// GetByID 获取分享记录
+func (r *authorizationCodeShareRepository) GetByID(ctx context.Context, id string) (*models.AuthorizationCodeShare, error) {
+	var share models.AuthorizationCodeShare
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&share).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAuthorizationCodeShareNotFound
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+// GetList 查询用户作为分享方或受赠方的分享记录，并填充授权码
+func (r *authorizationCodeShareRepository) GetList(ctx context.Context, userID string, req *models.AuthorizationCodeShareListRequest) ([]*models.AuthorizationCodeShare, int64, error) {
+	var shares []*models.AuthorizationCodeShare
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AuthorizationCodeShare{})
+
+	switch req.Direction {
+	case "sent":
+		query = query.Where("source_user_id = ?", userID)
+	case "received":
+		query = query.Where("target_user_id = ?", userID)
+	default:
+		query = query.Where("source_user_id = ? OR target_user_id = ?", userID, userID)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.CodeID != "" {
+		query = query.Where("source_code_id = ? OR target_code_id = ?", req.CodeID, req.CodeID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&shares).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 填充原授权码与分享生成的授权码
+	codeIDs := make([]string, 0, len(shares)*2)
+	for _, share := range shares {
+		codeIDs = append(codeIDs, share.SourceCodeID)
+		if share.TargetCodeID != nil {
+			codeIDs = append(codeIDs, *share.TargetCodeID)
+		}
+	}
+	if len(codeIDs) > 0 {
+		var codes []struct {
+			ID   string
+			Code string
+		}
+		if err := r.db.WithContext(ctx).Model(&models.AuthorizationCode{}).
+			Select("id, code").
+			Where("id IN ?", codeIDs).
+			Scan(&codes).Error; err != nil {
+			return nil, 0, err
+		}
+		codeMap := make(map[string]string, len(codes))
+		for _, code := range codes {
+			codeMap[code.ID] = code.Code
+		}
+		for _, share := range shares {
+			share.SourceCode = codeMap[share.SourceCodeID]
+			if share.TargetCodeID != nil {
+				share.TargetCode = codeMap[*share.TargetCodeID]
+			}
+		}
+	}
+
+	return shares, total, nil
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CustomFieldRepository 自定义字段与标签仓储接口
+type CustomFieldRepository interface {
+	GetDefinitions(ctx context.Context, entityType string) ([]*models.CustomFieldDefinition, error)
+	GetDefinitionByID(ctx context.Context, id string) (*models.CustomFieldDefinition, error)
+	CreateDefinition(ctx context.Context, definition *models.CustomFieldDefinition) error
+	UpdateDefinition(ctx context.Context, definition *models.CustomFieldDefinition) error
+	DeleteDefinition(ctx context.Context, definition *models.CustomFieldDefinition) error
+
+	GetEntityCustomFields(ctx context.Context, entityType string, entityIDs []string) (map[string]*models.EntityCustomFields, error)
+	SaveEntityCustomFields(ctx context.Context, entityType string, entityIDs []string, values map[string]string, tags []string) error
+	GetTagCounts(ctx context.Context, req *models.TagListRequest) ([]*models.TagCount, error)
+}
+
+type customFieldRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomFieldRepository 创建自定义字段仓储
+func NewCustomFieldRepository(db *gorm.DB) CustomFieldRepository {
+	return &customFieldRepository{db: db}
+}
+
+// GetDefinitions 查询字段定义，entityType 为空时返回全部对象类型的字段
+func (r *customFieldRepository) GetDefinitions(ctx context.Context, entityType string) ([]*models.CustomFieldDefinition, error) {
+	var definitions []*models.CustomFieldDefinition
+	query := r.db.WithContext(ctx).Model(&models.CustomFieldDefinition{})
+	if entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	err := query.Order("entity_type ASC, sort_order ASC, created_at ASC").Find(&definitions).Error
+	return definitions, err
+}
+
+// GetDefinitionByID 根据ID获取字段定义
+func (r *customFieldRepository) GetDefinitionByID(ctx context.Context, id string) (*models.CustomFieldDefinition, error) {
+	var definition models.CustomFieldDefinition
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&definition).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCustomFieldNotFound
+		}
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// CreateDefinition 创建字段定义，同一对象类型的字段标识不能重复
+func (r *customFieldRepository) CreateDefinition(ctx context.Context, definition *models.CustomFieldDefinition) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.CustomFieldDefinition{}).
+		Where("entity_type = ? AND field_key = ?", definition.EntityType, definition.FieldKey).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCustomFieldKeyExists
+	}
+	return r.db.WithContext(ctx).Create(definition).Error
+}
+
+// UpdateDefinition 更新字段定义
+func (r *customFieldRepository) UpdateDefinition(ctx context.Context, definition *models.CustomFieldDefinition) error {
+	return r.db.WithContext(ctx).Save(definition).Error
+}
+
+// DeleteDefinition 删除字段定义及所有对象上该字段的值
+func (r *customFieldRepository) DeleteDefinition(ctx context.Context, definition *models.CustomFieldDefinition) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("entity_type = ? AND field_key = ?", definition.EntityType, definition.FieldKey).
+			Delete(&models.CustomFieldValue{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", definition.ID).Delete(&models.CustomFieldDefinition{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCustomFieldNotFound
+		}
+		return nil
+	})
+}
+
+// GetEntityCustomFields 批量查询对象的自定义字段值和标签，返回 对象ID -> 字段和标签（没有任何值的对象也会返回空结构）
+func (r *customFieldRepository) GetEntityCustomFields(ctx context.Context, entityType string, entityIDs []string) (map[string]*models.EntityCustomFields, error) {
+	result := make(map[string]*models.EntityCustomFields, len(entityIDs))
+	if len(entityIDs) == 0 {
+		return result, nil
+	}
+	for _, id := range entityIDs {
+		result[id] = &models.EntityCustomFields{CustomFields: map[string]string{}, Tags: []string{}}
+	}
+
+	var values []models.CustomFieldValue
+	if err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs).
+		Find(&values).Error; err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		if fields, ok := result[value.EntityID]; ok {
+			fields.CustomFields[value.FieldKey] = value.Value
+		}
+	}
+
+	var tags []models.EntityTag
+	if err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs).
+		Order("tag ASC").
+		Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if fields, ok := result[tag.EntityID]; ok {
+			fields.Tags = append(fields.Tags, tag.Tag)
+		}
+	}
+	return result, nil
+}
+
+// SaveEntityCustomFields 在同一事务中为一个或多个对象保存相同的自定义字段值和标签
+// values 中值为空字符串的字段被删除；tags 为 nil 时不修改标签，否则整体替换
+func (r *customFieldRepository) SaveEntityCustomFields(ctx context.Context, entityType string, entityIDs []string, values map[string]string, tags []string) error {
+	if len(entityIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveEntityCustomFields(tx, entityType, entityIDs, values, tags)
+	})
+}
+
+// saveEntityCustomFields 在事务中保存对象的自定义字段值和标签，规则同 SaveEntityCustomFields
+func saveEntityCustomFields(tx *gorm.DB, entityType string, entityIDs []string, values map[string]string, tags []string) error {
+	now := time.Now()
+	var rows []*models.CustomFieldValue
+	for _, key := range sortedKeys(values) {
+		if values[key] == "" {
+			if err := tx.Where("entity_type = ? AND entity_id IN ? AND field_key = ?", entityType, entityIDs, key).
+				Delete(&models.CustomFieldValue{}).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		for _, entityID := range entityIDs {
+			rows = append(rows, &models.CustomFieldValue{EntityType: entityType, EntityID: entityID, FieldKey: key, Value: values[key], UpdatedAt: now})
+		}
+	}
+	if len(rows) > 0 {
+		if err := tx.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"})}).
+			CreateInBatches(rows, 500).Error; err != nil {
+			return err
+		}
+	}
+
+	if tags == nil {
+		return nil
+	}
+	if err := tx.Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs).
+		Delete(&models.EntityTag{}).Error; err != nil {
+		return err
+	}
+	tagRows := make([]*models.EntityTag, 0, len(tags)*len(entityIDs))
+	for _, entityID := range entityIDs {
+		for _, tag := range tags {
+			tagRows = append(tagRows, &models.EntityTag{EntityType: entityType, EntityID: entityID, Tag: tag, CreatedAt: now})
+		}
+	}
+	if len(tagRows) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(tagRows, 500).Error
+}
+
+// GetTagCounts 查询对象类型下已使用的标签及使用次数，按使用次数倒序，最多返回100个
+func (r *customFieldRepository) GetTagCounts(ctx context.Context, req *models.TagListRequest) ([]*models.TagCount, error) {
+	var counts []*models.TagCount
+	query := r.db.WithContext(ctx).Model(&models.EntityTag{}).
+		Select("tag, COUNT(*) AS count").
+		Where("entity_type = ?", req.EntityType)
+	if search := strings.TrimSpace(req.Search); search != "" {
+		query = query.Where("tag LIKE ?", search+"%")
+	}
+	err := query.Group("tag").Order("count DESC, tag ASC").Limit(100).Scan(&counts).Error
+	return counts, err
+}
+
+// deleteEntityCustomFields 删除对象的全部自定义字段值和标签（对象被物理删除时调用）
+func deleteEntityCustomFields(tx *gorm.DB, entityType string, entityIDs []string) error {
+	if err := tx.Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs).
+		Delete(&models.CustomFieldValue{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("entity_type = ? AND entity_id IN ?", entityType, entityIDs).
+		Delete(&models.EntityTag{}).Error
+}
+
+// applyCustomFieldFilter 按标签、自定义字段值和关键词筛选列表，idColumn 为列表主表的ID列
+func applyCustomFieldFilter(query *gorm.DB, entityType, idColumn string, filter *models.CustomFieldFilter) *gorm.DB {
+	for _, tag := range filter.Tags {
+		if tag = strings.TrimSpace(tag); tag == "" {
+			continue
+		}
+		query = query.Where(idColumn+" IN (SELECT entity_id FROM entity_tags WHERE entity_type = ? AND tag = ?)", entityType, tag)
+	}
+	for _, key := range sortedKeys(filter.CustomFields) {
+		query = query.Where(idColumn+" IN (SELECT entity_id FROM custom_field_values WHERE entity_type = ? AND field_key = ? AND value = ?)",
+			entityType, key, filter.CustomFields[key])
+	}
+	if search := strings.TrimSpace(filter.FieldSearch); search != "" {
+		like := "%" + search + "%"
+		query = query.Where(customFieldSearchCondition(idColumn), entityType, like, entityType, like)
+	}
+	return query
+}
+
+// customFieldSearchCondition 模糊匹配自定义字段值或标签的条件，参数依次为 entity_type、关键词、entity_type、关键词
+func customFieldSearchCondition(idColumn string) string {
+	return "(" + idColumn + " IN (SELECT entity_id FROM custom_field_values WHERE entity_type = ? AND value LIKE ?) OR " +
+		idColumn + " IN (SELECT entity_id FROM entity_tags WHERE entity_type = ? AND tag LIKE ?))"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// customerSubtreeSQL 递归查询客户及其全部下级客户，path 记录已经过的客户，数据中即使存在环也会终止
+const customerSubtreeSQL = `
+WITH RECURSIVE subtree (id, parent_id, path) AS (
+	SELECT id, parent_id, CAST(id AS CHAR(4000))
+	FROM customers WHERE id IN ? AND deleted_at IS NULL
+	UNION ALL
+	SELECT c.id, c.parent_id, CONCAT(s.path, ',', c.id)
+	FROM customers c INNER JOIN subtree s ON c.parent_id = s.id
+	WHERE c.deleted_at IS NULL AND FIND_IN_SET(c.id, s.path) = 0
+)
+SELECT DISTINCT id, parent_id FROM subtree WHERE parent_id IS NOT NULL AND parent_id <> ''`
+
+// customerAncestorsSQL 递归查询客户到最顶层上级客户的链路
+const customerAncestorsSQL = `
+WITH RECURSIVE ancestry (id, parent_id, path) AS (
+	SELECT id, parent_id, CAST(id AS CHAR(4000))
+	FROM customers WHERE id = ? AND deleted_at IS NULL
+	UNION ALL
+	SELECT c.id, c.parent_id, CONCAT(a.path, ',', c.id)
+	FROM customers c INNER JOIN ancestry a ON c.id = a.parent_id
+	WHERE c.deleted_at IS NULL AND FIND_IN_SET(c.id, a.path) = 0
+)
+SELECT id, parent_id FROM ancestry WHERE parent_id IS NOT NULL AND parent_id <> ''`
+
+// GetCustomerSubtreeParents 获取指定客户及其全部下级客户的上级关系（客户ID -> 上级客户ID），仅包含有上级的客户
+func (r *customerRepository) GetCustomerSubtreeParents(ctx context.Context, rootIDs []string) (map[string]string, error) {
+	if len(rootIDs) == 0 {
+		return map[string]string{}, nil
+	}
+	return r.queryCustomerParents(ctx, customerSubtreeSQL, rootIDs)
+}
+
+// GetCustomerAncestorParents 获取客户到最顶层上级客户链路上的上级关系（客户ID -> 上级客户ID）
+func (r *customerRepository) GetCustomerAncestorParents(ctx context.Context, customerID string) (map[string]string, error) {
+	return r.queryCustomerParents(ctx, customerAncestorsSQL, customerID)
+}
+
+func (r *customerRepository) queryCustomerParents(ctx context.Context, query string, args ...interface{}) (map[string]string, error) {
+	var rows []struct {
+		ID       string
+		ParentID string
+	}
+	if err := r.db.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query customer hierarchy: %w", err)
+	}
+
+	parents := make(map[string]string, len(rows))
+	for _, row := range rows {
+		parents[row.ID] = row.ParentID
+	}
+	return parents, nil
+}
+
+// UpdateCustomerWithParent 更新客户；设置了上级客户时，在同一事务中锁定客户自身及新上级的整条上级链路，
+// 确认客户不在链路上（即不会形成循环）后再保存，避免并发调整上级时各自校验通过而形成环
+func (r *customerRepository) UpdateCustomerWithParent(ctx context.Context, customer *models.Customer) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if customer.ParentID != nil && *customer.ParentID != "" {
+			if err := checkCustomerParentLocked(tx, customer.ID, *customer.ParentID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Save(customer).Error; err != nil {
+			return fmt.Errorf("failed to update customer: %w", err)
+		}
+		return nil
+	})
+}
+
+// checkCustomerParentLocked 自新上级起逐级加锁读取上级客户，遇到客户自身说明会形成循环
+// 使用加锁读取而非递归查询：加锁读取总是读到最新提交的数据，并使并发修改同一链路的事务串行执行
+func checkCustomerParentLocked(tx *gorm.DB, customerID, parentID string) error {
+	locking := clause.Locking{Strength: "UPDATE"}
+	if err := tx.Clauses(locking).Select("id").Where("id = ?", customerID).First(&models.Customer{}).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCustomerNotFound
+		}
+		return fmt.Errorf("failed to lock customer: %w", err)
+	}
+
+	return walkCustomerParentChain(customerID, parentID, func(id string) (*string, bool, error) {
+		var node models.Customer
+		if err := tx.Clauses(locking).Select("id", "parent_id").Where("id = ?", id).First(&node).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, false, nil
+			}
+			return nil, false, fmt.Errorf("failed to lock parent customer: %w", err)
+		}
+		return node.ParentID, true, nil
+	})
+}
+
+// walkCustomerParentChain 自新上级起沿 lookup 返回的上级逐级向上，遇到客户自身说明会形成循环
+// lookup 返回客户的上级ID及客户是否存在；新上级不存在时返回 ErrCustomerParentNotFound，
+// 链路上更高层的上级客户已删除时链路到此为止；已存在的循环不会导致死循环
+func walkCustomerParentChain(customerID, parentID string, lookup func(id string) (*string, bool, error)) error {
+	visited := make(map[string]bool)
+	for id := parentID; id != "" && !visited[id]; {
+		if id == customerID {
+			return ErrCustomerHierarchyCycle
+		}
+		visited[id] = true
+
+		next, found, err := lookup(id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			if id == parentID {
+				return ErrCustomerParentNotFound
+			}
+			return nil
+		}
+		if next == nil {
+			return nil
+		}
+		id = *next
+	}
+	return nil
+}
+
+// GetCustomersByIDs 批量获取客户
+func (r *customerRepository) GetCustomersByIDs(ctx context.Context, ids []string) ([]*models.Customer, error) {
+	var customers []*models.Customer
+	if len(ids) == 0 {
+		return customers, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&customers).Error; err != nil {
+		return nil, fmt.Errorf("failed to query customers: %w", err)
+	}
+	return customers, nil
+}
+
+// GetAuthorizationStatsByCustomers 批量获取客户授权统计信息，口径与 GetCustomerAuthorizationStats 一致
+func (r *customerRepository) GetAuthorizationStatsByCustomers(ctx context.Context, customerIDs []string) (map[string]*models.AuthorizationStats, error) {
+	result := make(map[string]*models.AuthorizationStats, len(customerIDs))
+	if len(customerIDs) == 0 {
+		return result, nil
+	}
+	statsOf := func(customerID string) *models.AuthorizationStats {
+		stats, ok := result[customerID]
+		if !ok {
+			stats = &models.AuthorizationStats{}
+			result[customerID] = stats
+		}
+		return stats
+	}
+	db := r.db.WithContext(ctx)
+
+	// 1. 授权码统计
+	var authCodeRows []struct {
+		CustomerID   string
+		Total        int64
+		Expired      int64
+		ExpiringSoon int64
+	}
+	if err := db.Model(&models.AuthorizationCode{}).
+		Select(`customer_id,
+			COUNT(*) AS total,
+			COALESCE(SUM(CASE WHEN end_date < NOW() THEN 1 ELSE 0 END), 0) AS expired,
+			COALESCE(SUM(CASE WHEN end_date >= NOW() AND end_date <= DATE_ADD(NOW(), INTERVAL 30 DAY) THEN 1 ELSE 0 END), 0) AS expiring_soon`).
+		Where("customer_id IN ?", customerIDs).
+		Group("customer_id").
+		Scan(&authCodeRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count auth codes: %w", err)
+	}
+	for _, row := range authCodeRows {
+		stats := statsOf(row.CustomerID)
+		stats.TotalAuthCodes = row.Total
+		stats.ExpiredAuthCodes = row.Expired
+		stats.ExpiringSoonAuthCodes = row.ExpiringSoon
+	}
+
+	// 2. 许可证统计
+	var licenseRows []struct {
+		CustomerID string
+		Total      int64
+		Active     int64
+	}
+	if err := db.Model(&models.License{}).
+		Select(`customer_id,
+			COUNT(*) AS total,
+			COALESCE(SUM(CASE WHEN status = 'active' THEN 1 ELSE 0 END), 0) AS active`).
+		Where("customer_id IN ? AND deleted_at IS NULL", customerIDs).
+		Group("customer_id").
+		Scan(&licenseRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count licenses: %w", err)
+	}
+	for _, row := range licenseRows {
+		stats := statsOf(row.CustomerID)
+		stats.TotalLicenses = row.Total
+		stats.ActiveLicenses = row.Active
+		stats.InactiveLicenses = row.Total - row.Active
+	}
+
+	// 已过期许可证数量（关联的授权码已过期）
+	var expiredRows []struct {
+		CustomerID string
+		Total      int64
+	}
+	if err := db.Model(&models.License{}).
+		Select("licenses.customer_id AS customer_id, COUNT(*) AS total").
+		Joins("INNER JOIN authorization_codes ON licenses.authorization_code_id = authorization_codes.id").
+		Where("licenses.customer_id IN ? AND authorization_codes.end_date < NOW() AND licenses.deleted_at IS NULL", customerIDs).
+		Group("licenses.customer_id").
+		Scan(&expiredRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count expired licenses: %w", err)
+	}
+	for _, row := range expiredRows {
+		statsOf(row.CustomerID).ExpiredLicenses = row.Total
+	}
+
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWalkCustomerParentChain(t *testing.T) {
+	// 集团 -> 华东 -> 上海；loop-a 与 loop-b 为已存在的循环；orphan 的上级已删除
+	parents := map[string]string{
+		"east":     "group",
+		"shanghai": "east",
+		"loop-a":   "loop-b",
+		"loop-b":   "loop-a",
+		"orphan":   "deleted",
+	}
+	customers := map[string]bool{
+		"group": true, "east": true, "shanghai": true, "south": true,
+		"loop-a": true, "loop-b": true, "orphan": true,
+	}
+	lookup := func(id string) (*string, bool, error) {
+		if !customers[id] {
+			return nil, false, nil
+		}
+		if parent, ok := parents[id]; ok {
+			return &parent, true, nil
+		}
+		return nil, true, nil
+	}
+
+	cases := []struct {
+		name       string
+		customerID string
+		parentID   string
+		want       error
+	}{
+		{"move under sibling", "south", "east", nil},
+		{"move under descendant", "group", "shanghai", ErrCustomerHierarchyCycle},
+		{"move under child", "east", "shanghai", ErrCustomerHierarchyCycle},
+		{"parent is self", "east", "east", ErrCustomerHierarchyCycle},
+		{"parent missing", "south", "missing", ErrCustomerParentNotFound},
+		{"ancestor deleted", "south", "orphan", nil},
+		{"existing cycle above", "south", "loop-a", nil},
+	}
+	for _, c := range cases {
+		if got := walkCustomerParentChain(c.customerID, c.parentID, lookup); !errors.Is(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	lookupErr := errors.New("lock wait timeout")
+	failing := func(id string) (*string, bool, error) { return nil, false, lookupErr }
+	if got := walkCustomerParentChain("south", "east", failing); !errors.Is(got, lookupErr) {
+		t.Errorf("lookup error: got %v, want %v", got, lookupErr)
+	}
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CreateImportJob 创建客户导入任务
+func (r *customerRepository) CreateImportJob(ctx context.Context, job *models.CustomerImportJob) error {
+	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
+		return fmt.Errorf("failed to create customer import job: %w", err)
+	}
+	return nil
+}
+
+// GetImportJob 获取客户导入任务
+func (r *customerRepository) GetImportJob(ctx context.Context, id string) (*models.CustomerImportJob, error) {
+	var job models.CustomerImportJob
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCustomerImportJobNotFound
+		}
+		return nil, fmt.Errorf("failed to query customer import job: %w", err)
+	}
+	return &job, nil
+}
+
+// GetUnfinishedImportJobs 查询未完成的客户导入任务（服务重启后续跑）
+func (r *customerRepository) GetUnfinishedImportJobs(ctx context.Context) ([]*models.CustomerImportJob, error) {
+	var jobs []*models.CustomerImportJob
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []string{
+			string(models.CustomerImportJobStatusPending),
+			string(models.CustomerImportJobStatusRunning),
+		}).
+		Order("created_at ASC").Find(&jobs).Error
+	return jobs, err
+}
+
+// UpdateImportJobFields 更新客户导入任务字段
+func (r *customerRepository) UpdateImportJobFields(ctx context.Context, id string, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	return r.db.WithContext(ctx).Model(&models.CustomerImportJob{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// ClaimImportJob 以租约方式领取导入任务
+func (r *customerRepository) ClaimImportJob(ctx context.Context, id, owner string, lease time.Duration) (bool, error) {
+	return claimJobLease(r.db.WithContext(ctx), &models.CustomerImportJob{}, id, owner, lease)
+}
+
+// UpdateLeasedImportJobFields 以租约持有者身份更新导入任务字段并续约
+func (r *customerRepository) UpdateLeasedImportJobFields(ctx context.Context, id, owner string, lease time.Duration, updates map[string]interface{}) error {
+	return updateLeasedJob(r.db.WithContext(ctx), &models.CustomerImportJob{}, id, owner, lease, updates)
+}
+
+// ImportCustomerRow 在一个事务中写入导入行的客户数据并保存导入进度
+// 进度与客户数据同时提交，续跑时从已提交的进度继续，不会重复导入；租约已被接管时返回 ErrJobLeaseLost 并整体回滚
+func (r *customerRepository) ImportCustomerRow(ctx context.Context, jobID, owner string, lease time.Duration, customer *models.Customer, customFields map[string]string, tags []string, progress map[string]interface{}) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateLeasedJob(tx, &models.CustomerImportJob{}, jobID, owner, lease, progress); err != nil {
+			return err
+		}
+
+		if customer.ID == "" {
+			customerCode, err := generateCustomerCode(tx)
+			if err != nil {
+				return fmt.Errorf("failed to generate customer code: %w", err)
+			}
+			customer.CustomerCode = customerCode
+			if err := tx.Create(customer).Error; err != nil {
+				return fmt.Errorf("failed to create customer: %w", err)
+			}
+		} else if err := tx.Save(customer).Error; err != nil {
+			return fmt.Errorf("failed to update customer: %w", err)
+		}
+
+		return saveEntityCustomFields(tx, models.CustomFieldEntityCustomer, []string{customer.ID}, customFields, tags)
+	})
+}
+
+// GetCustomerByCode 根据客户编码获取客户
+func (r *customerRepository) GetCustomerByCode(ctx context.Context, code string) (*models.Customer, error) {
+	var customer models.Customer
+	if err := r.db.WithContext(ctx).Where("customer_code = ?", code).First(&customer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("failed to query customer: %w", err)
+	}
+	return &customer, nil
+}
+
+// GetCustomersByEmail 根据邮箱获取客户（不区分大小写）
+func (r *customerRepository) GetCustomersByEmail(ctx context.Context, email string) ([]*models.Customer, error) {
+	var customers []*models.Customer
+	if err := r.db.WithContext(ctx).
+		Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(email))).
+		Order("created_at ASC").
+		Find(&customers).Error; err != nil {
+		return nil, fmt.Errorf("failed to query customers by email: %w", err)
+	}
+	return customers, nil
+}
+
+// EachCustomer 按客户列表的筛选和排序条件分批读取全部客户，用于流式导出
+func (r *customerRepository) EachCustomer(ctx context.Context, req *models.CustomerListRequest, batchSize int, fn func(customers []*models.Customer) error) error {
+	sort, order := req.Sort, strings.ToUpper(req.Order)
+	if sort == "" {
+		sort = "created_at"
+	}
+	if order == "" {
+		order = "DESC"
+	}
+
+	for offset := 0; ; offset += batchSize {
+		var customers []*models.Customer
+		err := applyCustomerListFilters(r.db.WithContext(ctx).Model(&models.Customer{}), req).
+			Order(fmt.Sprintf("%s %s, id ASC", sort, order)).
+			Offset(offset).Limit(batchSize).
+			Find(&customers).Error
+		if err != nil {
+			return fmt.Errorf("failed to query customers: %w", err)
+		}
+		if len(customers) == 0 {
+			return nil
+		}
+		if err := fn(customers); err != nil {
+			return err
+		}
+		if len(customers) < batchSize {
+			return nil
+		}
+	}
+}
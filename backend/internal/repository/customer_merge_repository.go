@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// customerReference 引用客户ID的数据表字段，合并客户时需要迁移
+type customerReference struct {
+	key    string // 迁移数量统计的键
+	table  string
+	column string
+	where  string // 附加条件，为空表示无
+}
+
+// customerReferences 合并客户时迁移的数据，包含已软删除的记录，保证历史数据跟随目标客户
+var customerReferences = []customerReference{
+	{key: "authorization_codes", table: "authorization_codes", column: "customer_id"},
+	{key: "licenses", table: "licenses", column: "customer_id"},
+	{key: "cu_users", table: "cu_users", column: "customer_id"},
+	{key: "cu_orders", table: "cu_orders", column: "customer_id"},
+	{key: "payments", table: "payments", column: "customer_id"},
+	{key: "invoices", table: "invoices", column: "customer_id"},
+	{key: "authorization_code_batches", table: "authorization_code_batches", column: "customer_id"},
+	{key: "authorization_code_shares_sent", table: "authorization_code_shares", column: "source_customer_id"},
+	{key: "authorization_code_shares_received", table: "authorization_code_shares", column: "target_customer_id"},
+	{key: "license_transfers", table: "license_transfers", column: "customer_id"},
+	{key: "license_commands", table: "license_commands", column: "customer_id"},
+	{key: "seat_reclamations", table: "seat_reclamations", column: "customer_id"},
+	{key: "activation_violations", table: "activation_violations", column: "customer_id"},
+	{key: "vouchers", table: "vouchers", column: "redeemed_customer_id"},
+	{key: "leads", table: "leads", column: "customer_id"},
+	{key: "notifications", table: "notifications", column: "recipient_id", where: "recipient_type = 'customer'"},
+	{key: "subsidiaries", table: "customers", column: "parent_id", where: "deleted_at IS NULL"},
+}
+
+func (ref customerReference) scope(tx *gorm.DB, customerIDs []string) *gorm.DB {
+	query := tx.Table(ref.table).Where(ref.column+" IN ?", customerIDs)
+	if ref.where != "" {
+		query = query.Where(ref.where)
+	}
+	return query
+}
+
+// CountCustomerMergeRows 统计合并客户时将迁移的数据数量（用于预览）
+func (r *customerRepository) CountCustomerMergeRows(ctx context.Context, sourceIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(customerReferences))
+	db := r.db.WithContext(ctx)
+	for _, ref := range customerReferences {
+		var count int64
+		if err := ref.scope(db, sourceIDs).Count(&count).Error; err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", ref.key, err)
+		}
+		counts[ref.key] = count
+	}
+
+	// 来源客户的席位池并入目标客户的席位池
+	var seatPools int64
+	if err := db.Model(&models.CustomerSeatPool{}).Where("customer_id IN ?", sourceIDs).Count(&seatPools).Error; err != nil {
+		return nil, fmt.Errorf("failed to count seat_pools: %w", err)
+	}
+	counts["seat_pools"] = seatPools
+	return counts, nil
+}
+
+// MergeCustomers 在事务中将来源客户的数据迁移到目标客户，删除来源客户并写入合并审计记录，返回各类数据迁移数量
+func (r *customerRepository) MergeCustomers(ctx context.Context, targetID string, sourceIDs []string, merge *models.CustomerMerge) (map[string]int64, error) {
+	counts := make(map[string]int64, len(customerReferences))
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定参与合并的客户，并发合并同一批客户时后执行的一方会发现客户已不存在
+		var locked []*models.Customer
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", append([]string{targetID}, sourceIDs...)).
+			Find(&locked).Error; err != nil {
+			return err
+		}
+		if len(locked) != len(sourceIDs)+1 {
+			return ErrCustomerNotFound
+		}
+
+		for _, ref := range customerReferences {
+			result := ref.scope(tx, sourceIDs).Update(ref.column, targetID)
+			if result.Error != nil {
+				return fmt.Errorf("failed to move %s: %w", ref.key, result.Error)
+			}
+			counts[ref.key] = result.RowsAffected
+		}
+
+		moved, err := mergeSeatPools(tx, targetID, sourceIDs, merge.OperatorID)
+		if err != nil {
+			return err
+		}
+		counts["seat_pools"] = moved
+
+		if err := tx.Where("id IN ?", sourceIDs).Delete(&models.Customer{}).Error; err != nil {
+			return fmt.Errorf("failed to delete merged customers: %w", err)
+		}
+
+		movedCounts, err := json.Marshal(counts)
+		if err != nil {
+			return err
+		}
+		merge.MovedCounts = models.JSON(movedCounts)
+		if err := tx.Create(merge).Error; err != nil {
+			return fmt.Errorf("failed to create customer merge record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// mergeSeatPools 将来源客户的席位池总席位并入目标客户的席位池（目标客户没有时创建），并删除来源客户的席位池
+// 迁移过来的授权码保留席位池取用设置，改为从目标客户的席位池取用
+func mergeSeatPools(tx *gorm.DB, targetID string, sourceIDs []string, operatorID string) (int64, error) {
+	var pools []*models.CustomerSeatPool
+	if err := tx.Where("customer_id IN ?", sourceIDs).Find(&pools).Error; err != nil {
+		return 0, fmt.Errorf("failed to query seat pools: %w", err)
+	}
+	if len(pools) == 0 {
+		return 0, nil
+	}
+
+	seats := 0
+	for _, pool := range pools {
+		seats += pool.TotalSeats
+	}
+	target := &models.CustomerSeatPool{
+		CustomerID: targetID,
+		TotalSeats: seats,
+		CreatedBy:  operatorID,
+		UpdatedBy:  operatorID,
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "customer_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"total_seats": gorm.Expr("total_seats + ?", seats),
+			"updated_by":  operatorID,
+			"updated_at":  time.Now(),
+		}),
+	}).Create(target).Error; err != nil {
+		return 0, fmt.Errorf("failed to merge seat pools: %w", err)
+	}
+	if err := tx.Where("customer_id IN ?", sourceIDs).Delete(&models.CustomerSeatPool{}).Error; err != nil {
+		return 0, fmt.Errorf("failed to delete merged seat pools: %w", err)
+	}
+	return int64(len(pools)), nil
+}
+
+// GetCustomerMerges 分页查询客户合并审计记录
+func (r *customerRepository) GetCustomerMerges(ctx context.Context, req *models.CustomerMergeListRequest) ([]*models.CustomerMerge, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.CustomerMerge{})
+	if req.CustomerID != "" {
+		query = query.Where("customer_merges.target_customer_id = ?", req.CustomerID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count customer merges: %w", err)
+	}
+
+	merges := []*models.CustomerMerge{}
+	offset := (req.Page - 1) * req.PageSize
+	err := query.
+		Select("customer_merges.*, users.full_name AS operator_name").
+		Joins("LEFT JOIN users ON users.id = customer_merges.operator_id").
+		Order("customer_merges.created_at DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&merges).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query customer merges: %w", err)
+	}
+	return merges, total, nil
+}
@@ -42,31 +42,7 @@ func (r *customerRepository) GetCustomerList(ctx context.Context, req *models.Cu
 	}
 
 	// 构建查询
-	query := r.db.Model(&models.Customer{})
-
-	// 搜索关键词筛选
-	if req.Search != "" {
-		searchTerm := "%" + strings.TrimSpace(req.Search) + "%"
-		query = query.Where(
-			"customer_code LIKE ? OR customer_name LIKE ? OR contact_person LIKE ? OR email LIKE ?",
-			searchTerm, searchTerm, searchTerm, searchTerm,
-		)
-	}
-
-	// 客户类型筛选
-	if req.CustomerType != "" {
-		query = query.Where("customer_type = ?", req.CustomerType)
-	}
-
-	// 客户等级筛选
-	if req.CustomerLevel != "" {
-		query = query.Where("customer_level = ?", req.CustomerLevel)
-	}
-
-	// 状态筛选
-	if req.Status != "" {
-		query = query.Where("status = ?", req.Status)
-	}
+	query := applyCustomerListFilters(r.db.Model(&models.Customer{}), req)
 
 	// 计算总数
 	var total int64
@@ -92,6 +68,8 @@ func (r *customerRepository) GetCustomerList(ctx context.Context, req *models.Cu
 			ID:            customer.ID,
 			CustomerCode:  customer.CustomerCode,
 			CustomerName:  customer.CustomerName,
+			ParentID:      customer.ParentID,
+			PartnerID:     customer.PartnerID,
 			CustomerType:  customer.CustomerType,
 			ContactPerson: customer.ContactPerson,
 			Email:         customer.Email,
@@ -116,6 +94,49 @@ func (r *customerRepository) GetCustomerList(ctx context.Context, req *models.Cu
 	}, nil
 }
 
+// applyCustomerListFilters 应用客户列表的筛选条件（列表查询与导出共用）
+func applyCustomerListFilters(query *gorm.DB, req *models.CustomerListRequest) *gorm.DB {
+	// 搜索关键词筛选
+	if req.Search != "" {
+		searchTerm := "%" + strings.TrimSpace(req.Search) + "%"
+		query = query.Where(
+			"customer_code LIKE ? OR customer_name LIKE ? OR contact_person LIKE ? OR email LIKE ? OR "+customFieldSearchCondition("id"),
+			searchTerm, searchTerm, searchTerm, searchTerm,
+			models.CustomFieldEntityCustomer, searchTerm, models.CustomFieldEntityCustomer, searchTerm,
+		)
+	}
+
+	// 客户类型筛选
+	if req.CustomerType != "" {
+		query = query.Where("customer_type = ?", req.CustomerType)
+	}
+
+	// 客户等级筛选
+	if req.CustomerLevel != "" {
+		query = query.Where("customer_level = ?", req.CustomerLevel)
+	}
+
+	// 状态筛选
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	// 所属经销商筛选
+	if req.PartnerID != "" {
+		query = query.Where("partner_id = ?", req.PartnerID)
+	}
+
+	// 客户范围筛选（按上级客户展开的下级客户）
+	if req.CustomerIDs != nil {
+		query = query.Where("id IN ?", req.CustomerIDs)
+	}
+
+	// 标签和自定义字段筛选
+	query = applyCustomFieldFilter(query, models.CustomFieldEntityCustomer, "id", &req.CustomFieldFilter)
+
+	return query
+}
+
 // GetCustomerByID 根据ID获取客户信息
 func (r *customerRepository) GetCustomerByID(ctx context.Context, id string) (*models.Customer, error) {
 	var customer models.Customer
@@ -134,7 +155,7 @@ func (r *customerRepository) CreateCustomer(ctx context.Context, customer *model
 	// 使用事务确保数据一致性
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		// 生成客户编码
-		customerCode, err := r.generateCustomerCode(tx)
+		customerCode, err := generateCustomerCode(tx)
 		if err != nil {
 			return fmt.Errorf("failed to generate customer code: %w", err)
 		}
@@ -148,8 +169,8 @@ func (r *customerRepository) CreateCustomer(ctx context.Context, customer *model
 	})
 }
 
-// generateCustomerCode 生成客户编码
-func (r *customerRepository) generateCustomerCode(tx *gorm.DB) (string, error) {
+// generateCustomerCode 生成客户编码，需在事务中调用
+func generateCustomerCode(tx *gorm.DB) (string, error) {
 	currentYear := time.Now().Year()
 	
 	// 查询或创建当年的序列号记录
@@ -189,9 +210,9 @@ func (r *customerRepository) UpdateCustomer(ctx context.Context, customer *model
 	return nil
 }
 
-// DeleteCustomer 删除客户（物理删除）
+// DeleteCustomer 删除客户（软删除，移入回收站，自定义字段和席位池在彻底删除时清理）
 func (r *customerRepository) DeleteCustomer(ctx context.Context, id string) error {
-	result := r.db.Unscoped().Where("id = ?", id).Delete(&models.Customer{})
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Customer{})
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete customer: %w", result.Error)
 	}
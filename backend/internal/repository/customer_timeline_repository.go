@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"license-manager/internal/models"
+)
+
+// customerTimelineSource 客户时间线的一类事件来源，每个来源是UNION ALL中的一个分支
+type customerTimelineSource struct {
+	entityType string
+	query      string // 查询语句，必须返回统一的列并使用一个 IN ? 占位符接收客户ID
+}
+
+// customerTimelineSources 客户时间线的事件来源，列依次为 entity_type, entity_id, customer_id, event, reference, status, amount, occurred_at
+var customerTimelineSources = []customerTimelineSource{
+	{entityType: models.TimelineEntityCustomer, query: `
+		SELECT 'customer' AS entity_type, id AS entity_id, id AS customer_id, 'created' AS event,
+			customer_name AS reference, status, CAST(NULL AS DECIMAL(10,2)) AS amount, created_at AS occurred_at
+		FROM customers WHERE id IN ? AND deleted_at IS NULL`},
+	{entityType: models.TimelineEntityAuthorizationCode, query: `
+		SELECT 'authorization_code', id, customer_id, 'created', code,
+			CASE WHEN is_locked = true THEN 'locked' WHEN end_date < NOW() THEN 'expired' ELSE 'normal' END,
+			CAST(NULL AS DECIMAL(10,2)), created_at
+		FROM authorization_codes WHERE customer_id IN ? AND deleted_at IS NULL`},
+	{entityType: models.TimelineEntityAuthorizationChange, query: `
+		SELECT 'authorization_change', ach.id, ac.customer_id, ach.change_type, ac.code,
+			CAST(NULL AS CHAR), CAST(NULL AS DECIMAL(10,2)), ach.created_at
+		FROM authorization_changes ach JOIN authorization_codes ac ON ac.id = ach.authorization_code_id
+		WHERE ac.customer_id IN ?`},
+	{entityType: models.TimelineEntityLicense, query: `
+		SELECT 'license', id, customer_id, 'activated', license_key, status,
+			CAST(NULL AS DECIMAL(10,2)), COALESCE(activated_at, created_at)
+		FROM licenses WHERE customer_id IN ? AND deleted_at IS NULL`},
+	{entityType: models.TimelineEntityOrder, query: `
+		SELECT 'order', id, customer_id, 'created', order_no, status, total_amount, created_at
+		FROM cu_orders WHERE customer_id IN ? AND deleted_at IS NULL`},
+	{entityType: models.TimelineEntityPayment, query: `
+		SELECT 'payment', CAST(id AS CHAR), customer_id, 'created', payment_no, status, amount, created_at
+		FROM payments WHERE customer_id IN ?`},
+	{entityType: models.TimelineEntityPayment, query: `
+		SELECT 'payment', CAST(id AS CHAR), customer_id, 'paid', payment_no, status, amount, payment_time
+		FROM payments WHERE customer_id IN ? AND payment_time IS NOT NULL`},
+	{entityType: models.TimelineEntityInvoice, query: `
+		SELECT 'invoice', id, customer_id, 'created', invoice_no, status, amount, created_at
+		FROM invoices WHERE customer_id IN ? AND deleted_at IS NULL`},
+	{entityType: models.TimelineEntityInvoice, query: `
+		SELECT 'invoice', id, customer_id, 'issued', invoice_no, status, amount, issued_at
+		FROM invoices WHERE customer_id IN ? AND deleted_at IS NULL AND issued_at IS NOT NULL`},
+	{entityType: models.TimelineEntityInvoice, query: `
+		SELECT 'invoice', id, customer_id, 'rejected', invoice_no, status, amount, rejected_at
+		FROM invoices WHERE customer_id IN ? AND deleted_at IS NULL AND rejected_at IS NOT NULL`},
+}
+
+// customerTimelineUnion 拼接所选对象类型的事件来源，entityTypes为空表示全部
+func customerTimelineUnion(entityTypes []string, customerIDs []string) (string, []interface{}) {
+	selected := make(map[string]bool, len(entityTypes))
+	for _, entityType := range entityTypes {
+		selected[entityType] = true
+	}
+
+	var parts []string
+	var args []interface{}
+	for _, source := range customerTimelineSources {
+		if len(selected) > 0 && !selected[source.entityType] {
+			continue
+		}
+		parts = append(parts, source.query)
+		args = append(args, customerIDs)
+	}
+	return strings.Join(parts, "\n\t\tUNION ALL"), args
+}
+
+// GetCustomerTimeline 分页查询客户时间线，按发生时间倒序
+func (r *customerRepository) GetCustomerTimeline(ctx context.Context, req *models.CustomerTimelineRequest) ([]*models.CustomerTimelineEntry, int64, error) {
+	union, args := customerTimelineUnion(req.EntityTypes, req.CustomerIDs)
+	if union == "" || len(req.CustomerIDs) == 0 {
+		return []*models.CustomerTimelineEntry{}, 0, nil
+	}
+
+	where := "occurred_at IS NOT NULL"
+	if req.From != nil {
+		where += " AND occurred_at >= ?"
+		args = append(args, *req.From)
+	}
+	if req.To != nil {
+		where += " AND occurred_at < ?"
+		args = append(args, *req.To)
+	}
+	from := "FROM (" + union + "\n\t) t WHERE " + where
+
+	db := r.db.WithContext(ctx)
+	var total int64
+	if err := db.Raw("SELECT COUNT(*) "+from, args...).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	entries := []*models.CustomerTimelineEntry{}
+	if total == 0 {
+		return entries, 0, nil
+	}
+	offset := (req.Page - 1) * req.PageSize
+	pageArgs := append(args, req.PageSize, offset)
+	if err := db.Raw("SELECT * "+from+" ORDER BY occurred_at DESC, entity_type, entity_id, event LIMIT ? OFFSET ?", pageArgs...).
+		Scan(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
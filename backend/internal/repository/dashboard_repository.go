@@ -102,7 +102,7 @@ func (r *dashboardRepository) GetRecentAuthorizations(ctx context.Context, req *
 			WHERE status = 'active' AND deleted_at IS NULL 
 			GROUP BY authorization_code_id
 		) l ON ac.id = l.authorization_code_id`).
-		Where("1=1").
+		Where("ac.deleted_at IS NULL").
 		Order("ac.created_at DESC")
 
 	// 添加筛选条件
@@ -204,3 +204,98 @@ func (r *dashboardRepository) GetRecentAuthorizations(ctx context.Context, req *
 		Total: total,
 	}, nil
 }
+
+// GetLeadConversionTrendData 获取每日新增线索及转化数据
+func (r *dashboardRepository) GetLeadConversionTrendData(ctx context.Context, startDate, endDate time.Time) ([]models.LeadConversionTrendData, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+	trendData := make([]models.LeadConversionTrendData, 0)
+
+	for d := startDate; !d.After(endDate); d = d.AddDate(0, 0, 1) {
+		dayStart := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, d.Location())
+		dayEnd := dayStart.Add(24*time.Hour - time.Nanosecond)
+
+		// 当日新增线索数
+		var newCount int64
+		err := r.db.WithContext(ctx).Model(&models.Lead{}).
+			Where("created_at >= ? AND created_at <= ?", dayStart, dayEnd).
+			Count(&newCount).Error
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+
+		// 当日转化为客户的线索数
+		var convertedCount int64
+		err = r.db.WithContext(ctx).Model(&models.Lead{}).
+			Where("converted_at >= ? AND converted_at <= ?", dayStart, dayEnd).
+			Count(&convertedCount).Error
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+
+		trendData = append(trendData, models.LeadConversionTrendData{
+			Date:           d.Format("2006-01-02"),
+			NewLeads:       newCount,
+			ConvertedLeads: convertedCount,
+		})
+	}
+
+	return trendData, nil
+}
+
+// GetLeadConversionSummary 获取线索转化汇总数据
+// 转化以线索关联到客户为准（converted_at 非空），手工标记为已成交但未关联客户的线索不计入
+func (r *dashboardRepository) GetLeadConversionSummary(ctx context.Context, startDate, endDate time.Time) (*models.LeadConversionSummary, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	var totals struct {
+		TotalLeads     int64
+		TotalConverted int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Lead{}).
+		Select("COUNT(*) AS total_leads, COALESCE(SUM(CASE WHEN converted_at IS NOT NULL THEN 1 ELSE 0 END), 0) AS total_converted").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	// 期间新增线索及其中已转化的数量
+	var period struct {
+		NewLeads          int64
+		NewLeadsConverted int64
+	}
+	err = r.db.WithContext(ctx).Model(&models.Lead{}).
+		Select("COUNT(*) AS new_leads, COALESCE(SUM(CASE WHEN converted_at IS NOT NULL THEN 1 ELSE 0 END), 0) AS new_leads_converted").
+		Where("created_at >= ? AND created_at <= ?", startDate, endDate).
+		Scan(&period).Error
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	// 期间完成转化的线索数及平均转化周期
+	var converted struct {
+		ConvertedLeads    int64
+		AvgConversionSecs float64
+	}
+	err = r.db.WithContext(ctx).Model(&models.Lead{}).
+		Select("COUNT(*) AS converted_leads, COALESCE(AVG(TIMESTAMPDIFF(SECOND, created_at, converted_at)), 0) AS avg_conversion_secs").
+		Where("converted_at >= ? AND converted_at <= ?", startDate, endDate).
+		Scan(&converted).Error
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	summary := &models.LeadConversionSummary{
+		TotalLeads:        totals.TotalLeads,
+		TotalConverted:    totals.TotalConverted,
+		NewLeads:          period.NewLeads,
+		ConvertedLeads:    converted.ConvertedLeads,
+		AvgConversionDays: converted.AvgConversionSecs / 86400,
+	}
+	if totals.TotalLeads > 0 {
+		summary.OverallConversionRate = float64(totals.TotalConverted) / float64(totals.TotalLeads) * 100
+	}
+	if period.NewLeads > 0 {
+		summary.ConversionRate = float64(period.NewLeadsConverted) / float64(period.NewLeads) * 100
+	}
+	return summary, nil
+}
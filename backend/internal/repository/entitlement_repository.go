@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// EntitlementRepository 授权检查仓储接口
+type EntitlementRepository interface {
+	GetAuthorizationCodeByCode(ctx context.Context, code string) (*models.AuthorizationCode, error)
+	GetCustomerAuthorizationCodes(ctx context.Context, customerID string) ([]*models.AuthorizationCode, error)
+	GetActiveLicenses(ctx context.Context, authCodeIDs []string) ([]*models.License, error)
+}
+
+type entitlementRepository struct {
+	db *gorm.DB
+}
+
+// NewEntitlementRepository 创建授权检查仓储
+func NewEntitlementRepository(db *gorm.DB) EntitlementRepository {
+	return &entitlementRepository{db: db}
+}
+
+// GetAuthorizationCodeByCode 根据授权码获取授权码信息
+func (r *entitlementRepository) GetAuthorizationCodeByCode(ctx context.Context, code string) (*models.AuthorizationCode, error) {
+	var authCode models.AuthorizationCode
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&authCode).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAuthorizationCodeNotFound
+		}
+		return nil, err
+	}
+	return &authCode, nil
+}
+
+// GetCustomerAuthorizationCodes 获取客户的全部授权码
+func (r *entitlementRepository) GetCustomerAuthorizationCodes(ctx context.Context, customerID string) ([]*models.AuthorizationCode, error) {
+	var authCodes []*models.AuthorizationCode
+	err := r.db.WithContext(ctx).Where("customer_id = ?", customerID).
+		Order("created_at ASC").
+		Find(&authCodes).Error
+	return authCodes, err
+}
+
+// GetActiveLicenses 获取授权码下激活状态的许可证（仅加载统计配额所需字段）
+func (r *entitlementRepository) GetActiveLicenses(ctx context.Context, authCodeIDs []string) ([]*models.License, error) {
+	var licenses []*models.License
+	if len(authCodeIDs) == 0 {
+		return licenses, nil
+	}
+	err := r.db.WithContext(ctx).
+		Select("id", "authorization_code_id", "usage_data").
+		Where("authorization_code_id IN ? AND status = ?", authCodeIDs, "active").
+		Find(&licenses).Error
+	return licenses, err
+}
@@ -4,9 +4,12 @@ import "errors"
 
 // 客户领域的业务错误（给开发者看的，英文即可）
 var (
-	ErrCustomerNotFound      = errors.New("customer not found")
-	ErrCustomerAlreadyExists = errors.New("customer already exists")
-	ErrCustomerCodeDuplicate = errors.New("customer code already exists")
+	ErrCustomerNotFound          = errors.New("customer not found")
+	ErrCustomerAlreadyExists     = errors.New("customer already exists")
+	ErrCustomerCodeDuplicate     = errors.New("customer code already exists")
+	ErrCustomerImportJobNotFound = errors.New("customer import job not found")
+	ErrCustomerParentNotFound    = errors.New("parent customer not found")
+	ErrCustomerHierarchyCycle    = errors.New("customer hierarchy would contain a cycle")
 )
 
 // 授权码领域的业务错误
@@ -14,6 +17,51 @@ var (
 	ErrAuthorizationCodeNotFound      = errors.New("authorization code not found")
 	ErrAuthorizationCodeAlreadyExists = errors.New("authorization code already exists")
 	ErrAuthorizationCodeDuplicate     = errors.New("authorization code already exists")
+	ErrAuthorizationChangeNotFound    = errors.New("authorization change not found")
+	ErrAuthorizationCodeLocked        = errors.New("authorization code is locked")
+	ErrAuthorizationCodeSeatsChanged  = errors.New("authorization code seats changed")
+	ErrAuthorizationCodeFingerprintConflict = errors.New("authorization codes have licenses with the same hardware fingerprint")
+)
+
+// 授权码批次领域的业务错误
+var (
+	ErrAuthorizationCodeBatchNotFound = errors.New("authorization code batch not found")
+)
+
+// 后台任务领域的业务错误
+var (
+	ErrJobLeaseLost = errors.New("background job lease taken over by another instance")
+)
+
+// 授权码分享领域的业务错误
+var (
+	ErrAuthorizationCodeShareNotFound   = errors.New("authorization code share not found")
+	ErrAuthorizationCodeShareNotPending = errors.New("authorization code share is not pending")
+	ErrAuthorizationCodeSeatsShort      = errors.New("not enough seats on authorization code")
+)
+
+// 授权码计划操作领域的业务错误
+var (
+	ErrScheduledActionNotFound   = errors.New("scheduled action not found")
+	ErrScheduledActionNotPending = errors.New("scheduled action is not pending")
+)
+
+// 兑换券领域的业务错误
+var (
+	ErrVoucherNotFound        = errors.New("voucher not found")
+	ErrVoucherAlreadyRedeemed = errors.New("voucher already redeemed")
+)
+
+// 远程命令领域的业务错误
+var (
+	ErrLicenseCommandNotFound = errors.New("license command not found")
+	ErrLicenseCommandNotOpen  = errors.New("license command is already finished")
+)
+
+// 软件版本发布领域的业务错误
+var (
+	ErrSoftwareReleaseNotFound = errors.New("software release not found")
+	ErrSoftwareReleaseExists   = errors.New("software release version already exists")
 )
 
 // 许可证领域的业务错误
@@ -23,6 +71,33 @@ var (
 	ErrLicenseDuplicate     = errors.New("license already exists")
 )
 
+// 经销商领域的业务错误
+var (
+	ErrPartnerNotFound      = errors.New("partner not found")
+	ErrPartnerQuotaExceeded = errors.New("partner seat quota exceeded")
+)
+
+// 自定义字段领域的业务错误
+var (
+	ErrCustomFieldNotFound  = errors.New("custom field not found")
+	ErrCustomFieldKeyExists = errors.New("custom field key already exists")
+)
+
+// 客户席位池领域的业务错误
+var (
+	ErrSeatPoolNotFound  = errors.New("seat pool not found")
+	ErrSeatPoolSeatsUsed = errors.New("seat pool total is less than used seats")
+	ErrSeatPoolExhausted = errors.New("seat pool has no remaining seats")
+)
+
+// 回收站领域的业务错误
+var (
+	ErrTrashItemNotFound     = errors.New("trash item not found")
+	ErrTrashItemReferenced   = errors.New("trash item is still referenced")
+	ErrTrashParentDeleted    = errors.New("parent customer of trash item is deleted")
+	ErrTrashCuUserPhoneInUse = errors.New("phone is used by another client user")
+)
+
 // 通用的数据库/系统错误（给开发者看的）
 var (
 	ErrDatabaseConnection = errors.New("database connection failed")
@@ -33,6 +108,11 @@ var (
 	ErrInvalidTransaction = errors.New("invalid transaction object")
 )
 
+// 线索领域的业务错误
+var (
+	ErrLeadAlreadyConverted = errors.New("lead already converted")
+)
+
 // 辅助函数：判断错误类型
 func IsCustomerNotFound(err error) bool {
 	return errors.Is(err, ErrCustomerNotFound)
@@ -20,7 +20,7 @@ type CustomerRepository interface {
 	// UpdateCustomer 更新客户信息
 	UpdateCustomer(ctx context.Context, customer *models.Customer) error
 
-	// DeleteCustomer 删除客户（物理删除）
+	// DeleteCustomer 删除客户（软删除，移入回收站）
 	DeleteCustomer(ctx context.Context, id string) error
 
 	// GetCustomerCount 获取客户总数（用于统计）
@@ -34,6 +34,63 @@ type CustomerRepository interface {
 
 	// GetCustomerAuthorizationStats 获取客户授权统计信息
 	GetCustomerAuthorizationStats(ctx context.Context, customerID string) (*models.AuthorizationStats, error)
+
+	// GetCustomerSubtreeParents 获取指定客户及其全部下级客户的上级关系（客户ID -> 上级客户ID）
+	GetCustomerSubtreeParents(ctx context.Context, rootIDs []string) (map[string]string, error)
+
+	// GetCustomerAncestorParents 获取客户到最顶层上级客户链路上的上级关系（客户ID -> 上级客户ID）
+	GetCustomerAncestorParents(ctx context.Context, customerID string) (map[string]string, error)
+
+	// UpdateCustomerWithParent 更新客户，并在同一事务中加锁校验新的上级客户不会形成循环
+	UpdateCustomerWithParent(ctx context.Context, customer *models.Customer) error
+
+	// GetCustomersByIDs 批量获取客户
+	GetCustomersByIDs(ctx context.Context, ids []string) ([]*models.Customer, error)
+
+	// GetAuthorizationStatsByCustomers 批量获取客户授权统计信息
+	GetAuthorizationStatsByCustomers(ctx context.Context, customerIDs []string) (map[string]*models.AuthorizationStats, error)
+
+	// CountCustomerMergeRows 统计合并客户时将迁移的数据数量（用于预览）
+	CountCustomerMergeRows(ctx context.Context, sourceIDs []string) (map[string]int64, error)
+
+	// MergeCustomers 在事务中将来源客户的数据迁移到目标客户，删除来源客户并写入合并审计记录
+	MergeCustomers(ctx context.Context, targetID string, sourceIDs []string, merge *models.CustomerMerge) (map[string]int64, error)
+
+	// GetCustomerMerges 分页查询客户合并审计记录
+	GetCustomerMerges(ctx context.Context, req *models.CustomerMergeListRequest) ([]*models.CustomerMerge, int64, error)
+
+	// GetCustomerTimeline 分页查询客户（及下级客户）的授权、许可证和账务时间线，按发生时间倒序
+	GetCustomerTimeline(ctx context.Context, req *models.CustomerTimelineRequest) ([]*models.CustomerTimelineEntry, int64, error)
+
+	// GetCustomerByCode 根据客户编码获取客户
+	GetCustomerByCode(ctx context.Context, code string) (*models.Customer, error)
+
+	// GetCustomersByEmail 根据邮箱获取客户（不区分大小写）
+	GetCustomersByEmail(ctx context.Context, email string) ([]*models.Customer, error)
+
+	// EachCustomer 按客户列表的筛选和排序条件分批读取全部客户，用于流式导出
+	EachCustomer(ctx context.Context, req *models.CustomerListRequest, batchSize int, fn func(customers []*models.Customer) error) error
+
+	// CreateImportJob 创建客户导入任务
+	CreateImportJob(ctx context.Context, job *models.CustomerImportJob) error
+
+	// GetImportJob 获取客户导入任务
+	GetImportJob(ctx context.Context, id string) (*models.CustomerImportJob, error)
+
+	// GetUnfinishedImportJobs 查询未完成的客户导入任务
+	GetUnfinishedImportJobs(ctx context.Context) ([]*models.CustomerImportJob, error)
+
+	// UpdateImportJobFields 更新客户导入任务字段
+	UpdateImportJobFields(ctx context.Context, id string, updates map[string]interface{}) error
+
+	// ClaimImportJob 以租约方式领取待导入或租约已过期的导入任务，多实例下同一任务只由一个实例执行
+	ClaimImportJob(ctx context.Context, id, owner string, lease time.Duration) (bool, error)
+
+	// UpdateLeasedImportJobFields 以租约持有者身份更新导入任务字段并续约，租约已被接管时返回 ErrJobLeaseLost
+	UpdateLeasedImportJobFields(ctx context.Context, id, owner string, lease time.Duration, updates map[string]interface{}) error
+
+	// ImportCustomerRow 在一个事务中写入导入行的客户（ID 为空时新建）及其自定义字段和标签，并保存导入进度
+	ImportCustomerRow(ctx context.Context, jobID, owner string, lease time.Duration, customer *models.Customer, customFields map[string]string, tags []string, progress map[string]interface{}) error
 }
 
 // UserRepository 用户数据访问接口
@@ -71,6 +128,9 @@ type AuthorizationCodeRepository interface {
 	// UpdateAuthorizationCode 更新授权码
 	UpdateAuthorizationCode(ctx context.Context, authCode *models.AuthorizationCode) error
 
+	// RevertAuthorizationCode 在事务中锁定授权码，由 apply 回滚配置后校验席位并只更新配置字段，返回回滚后的授权码
+	RevertAuthorizationCode(ctx context.Context, id string, apply func(authCode *models.AuthorizationCode) error) (*models.AuthorizationCode, error)
+
 	// DeleteAuthorizationCode 删除授权码（软删除）
 	DeleteAuthorizationCode(ctx context.Context, id string) error
 
@@ -80,6 +140,9 @@ type AuthorizationCodeRepository interface {
 	// GetAuthorizationChangeList 查询授权变更历史列表
 	GetAuthorizationChangeList(ctx context.Context, authCodeID string, req *models.AuthorizationChangeListRequest) (*models.AuthorizationChangeListResponse, error)
 
+	// GetAuthorizationChangeByID 根据ID获取授权变更记录（含配置快照）
+	GetAuthorizationChangeByID(ctx context.Context, id string) (*models.AuthorizationChange, error)
+
 	// RecordAuthorizationChange 记录授权变更历史
 	RecordAuthorizationChange(ctx context.Context, change *models.AuthorizationChange) error
 
@@ -90,9 +153,18 @@ type AuthorizationCodeRepository interface {
 	// CreateAuthorizationCodeWithTx 在事务中创建授权码
 	CreateAuthorizationCodeWithTx(ctx context.Context, tx interface{}, authCode *models.AuthorizationCode) error
 
+	// UpdateAuthorizationCodeWithTx 在事务中更新授权码
+	UpdateAuthorizationCodeWithTx(ctx context.Context, tx interface{}, authCode *models.AuthorizationCode) error
+
 	// UpdateMaxActivationsWithTx 在事务中更新授权码的最大激活次数
 	UpdateMaxActivationsWithTx(ctx context.Context, tx interface{}, authCodeID string, newMaxActivations int) error
 
+	// SplitAuthorizationCode 在事务中锁定原授权码，更新原授权码、创建拆分出的授权码并按 allocate 的分配迁移许可证，返回迁移的许可证数量
+	SplitAuthorizationCode(ctx context.Context, source *models.AuthorizationCode, children []*models.AuthorizationCode, allocate func(licenseIDs []string) [][]string) (int, error)
+
+	// MergeAuthorizationCodes 在事务中锁定并按最新席位合并来源授权码到目标授权码并迁移许可证，返回迁移的许可证数量
+	MergeAuthorizationCodes(ctx context.Context, target *models.AuthorizationCode, sources []*models.AuthorizationCode) (int64, error)
+
 	// 用户端：查询用户授权码列表
 	GetCuAuthorizationCodeList(ctx context.Context, customerID string, req *models.CuAuthorizationCodeListRequest) (*models.CuAuthorizationCodeListResponse, error)
 
@@ -114,6 +186,9 @@ type LicenseRepository interface {
 	// UpdateLicense 更新许可证信息
 	UpdateLicense(ctx context.Context, license *models.License) error
 
+	// UpdateLicenseHeartbeat 仅在许可证仍为读取时的状态时更新心跳相关字段，返回是否更新成功
+	UpdateLicenseHeartbeat(ctx context.Context, licenseID, loadedStatus string, updates map[string]interface{}) (bool, error)
+
 	// CheckAuthorizationCodeExists 检查授权码是否存在
 	CheckAuthorizationCodeExists(ctx context.Context, authCodeID string) (bool, error)
 
@@ -129,6 +204,9 @@ type LicenseRepository interface {
 	// GetActiveLicenseCount 获取指定授权码的激活许可证数量
 	GetActiveLicenseCount(ctx context.Context, authCodeID string) (int64, error)
 
+	// GetLicenseByHardwareFingerprint 获取授权码下指定硬件指纹设备的许可证
+	GetLicenseByHardwareFingerprint(ctx context.Context, authCodeID, hardwareFingerprint string) (*models.License, error)
+
 	// GetCustomerDeviceList 查询客户设备列表（关联授权码信息）
 	GetCustomerDeviceList(ctx context.Context, customerID string, req *models.DeviceListRequest) (*models.DeviceListResponse, error)
 
@@ -149,6 +227,12 @@ type DashboardRepository interface {
 
 	// GetRecentAuthorizations 获取最近授权列表
 	GetRecentAuthorizations(ctx context.Context, req *models.DashboardRecentAuthorizationsRequest) (*models.DashboardRecentAuthorizationsResponse, error)
+
+	// GetLeadConversionTrendData 获取每日新增线索及转化数据
+	GetLeadConversionTrendData(ctx context.Context, startDate, endDate time.Time) ([]models.LeadConversionTrendData, error)
+
+	// GetLeadConversionSummary 获取线索转化汇总数据
+	GetLeadConversionSummary(ctx context.Context, startDate, endDate time.Time) (*models.LeadConversionSummary, error)
 }
 
 // PaymentRepository 支付数据访问接口
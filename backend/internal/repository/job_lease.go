@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 后台任务（授权码批次、客户导入）的执行租约
+// 多实例部署时，任务由领取到租约的实例执行，执行中按进度续约；实例退出后租约过期，其他实例可接管续跑
+
+// claimJobLease 领取后台任务：待执行的任务或租约已过期的执行中任务才能领取，成功时返回 true
+// 批次和导入任务的状态取值一致（pending/running）
+func claimJobLease(db *gorm.DB, model interface{}, id, owner string, lease time.Duration) (bool, error) {
+	now := time.Now()
+	result := db.Model(model).
+		Where("id = ?", id).
+		Where("status = ? OR (status = ? AND (lease_expires_at IS NULL OR lease_expires_at < ?))", "pending", "running", now).
+		Updates(map[string]interface{}{
+			"status":           "running",
+			"lease_owner":      owner,
+			"lease_expires_at": now.Add(lease),
+			"updated_at":       now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// updateLeasedJob 以租约持有者身份更新任务字段并续约，租约已被其他实例接管时返回 ErrJobLeaseLost
+func updateLeasedJob(tx *gorm.DB, model interface{}, id, owner string, lease time.Duration, updates map[string]interface{}) error {
+	now := time.Now()
+	updates["lease_expires_at"] = now.Add(lease)
+	updates["updated_at"] = now
+	result := tx.Model(model).Where("id = ? AND lease_owner = ?", id, owner).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrJobLeaseLost
+	}
+	return nil
+}
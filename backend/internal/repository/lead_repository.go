@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"license-manager/internal/models"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // LeadRepository 线索仓储接口
@@ -15,6 +20,29 @@ type LeadRepository interface {
 	GetByID(id string) (*models.Lead, error)
 	GetList(ctx context.Context, req *models.LeadListRequest) ([]*models.Lead, int64, error)
 	GetSummary(ctx context.Context) (*models.LeadSummaryResponse, error)
+	// ConvertLead 在同一事务中创建客户（及可选的客户端用户、试用授权码）并关联到线索
+	// 线索已转化时返回 ErrLeadAlreadyConverted，不重复创建
+	ConvertLead(ctx context.Context, leadID string, customer *models.Customer, cuUser *models.CuUser, authCode *models.AuthorizationCode) (*models.Lead, error)
+
+	// SaveWithActivities 在同一事务中保存线索并追加跟进动态
+	SaveWithActivities(ctx context.Context, lead *models.Lead, activities ...*models.LeadActivity) error
+	// FindDuplicates 按标准化的手机号、邮箱、公司名称查找未合并的疑似重复线索
+	FindDuplicates(ctx context.Context, lead *models.Lead, limit int) ([]*models.Lead, error)
+	// MergeLead 将 source 合并到 target：标记来源线索已合并，并将指向来源线索的重复标记改为指向目标线索
+	MergeLead(ctx context.Context, source, target *models.Lead, activities ...*models.LeadActivity) error
+	CreateActivity(ctx context.Context, activity *models.LeadActivity) error
+	GetActivities(ctx context.Context, leadID string, req *models.LeadActivityListRequest) ([]*models.LeadActivity, int64, error)
+
+	// GetDueFollowUps 获取跟进日期已到且尚未提醒的线索
+	GetDueFollowUps(ctx context.Context, now time.Time, limit int) ([]*models.Lead, error)
+	// GetStalePending 获取在 before 之前创建、仍为待联系且尚未提醒的线索
+	GetStalePending(ctx context.Context, before time.Time, limit int) ([]*models.Lead, error)
+	MarkFollowUpReminded(ctx context.Context, ids []string, at time.Time) error
+	MarkPendingReminded(ctx context.Context, ids []string, at time.Time) error
+	// GetAdminUserIDs 获取启用状态的管理员账号，未分配负责人的线索提醒发送给管理员
+	GetAdminUserIDs(ctx context.Context) ([]string, error)
+	// IsStaffUser 账号是否为启用状态的后台账号（不含经销商）
+	IsStaffUser(ctx context.Context, userID string) (bool, error)
 }
 
 type leadRepository struct {
@@ -35,7 +63,7 @@ func (r *leadRepository) Update(lead *models.Lead) error {
 }
 
 func (r *leadRepository) Delete(id string) error {
-	return r.db.Unscoped().Where("id = ?", id).Delete(&models.Lead{}).Error
+	return r.db.Where("id = ?", id).Delete(&models.Lead{}).Error
 }
 
 func (r *leadRepository) GetByID(id string) (*models.Lead, error) {
@@ -56,6 +84,9 @@ func (r *leadRepository) GetList(ctx context.Context, req *models.LeadListReques
 	pageSize := 10
 	search := ""
 	status := ""
+	assigneeID := ""
+	onlyDuplicates := false
+	includeMerged := false
 
 	if req != nil {
 		if req.Page > 0 {
@@ -69,6 +100,9 @@ func (r *leadRepository) GetList(ctx context.Context, req *models.LeadListReques
 		}
 		search = req.Search
 		status = req.Status
+		assigneeID = req.AssigneeID
+		onlyDuplicates = req.OnlyDuplicates
+		includeMerged = req.IncludeMerged
 	}
 	offset := (page - 1) * pageSize
 
@@ -78,6 +112,20 @@ func (r *leadRepository) GetList(ctx context.Context, req *models.LeadListReques
 		query = query.Where("status = ?", status)
 	}
 
+	if assigneeID == "unassigned" {
+		query = query.Where("assignee_id IS NULL")
+	} else if assigneeID != "" {
+		query = query.Where("assignee_id = ?", assigneeID)
+	}
+
+	if onlyDuplicates {
+		query = query.Where("duplicate_of_id IS NOT NULL")
+	}
+
+	if !includeMerged {
+		query = query.Where("merged_into_id IS NULL")
+	}
+
 	if search != "" {
 		like := "%" + search + "%"
 		query = query.Where("(company_name LIKE ? OR contact_name LIKE ? OR contact_phone LIKE ?)", like, like, like)
@@ -129,3 +177,227 @@ func (r *leadRepository) GetSummary(ctx context.Context) (*models.LeadSummaryRes
 		InvalidCount:   row.InvalidCount,
 	}, nil
 }
+
+func (r *leadRepository) ConvertLead(ctx context.Context, leadID string, customer *models.Customer, cuUser *models.CuUser, authCode *models.AuthorizationCode) (*models.Lead, error) {
+	var lead models.Lead
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁定线索，避免并发转化重复创建客户
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", leadID).First(&lead).Error; err != nil {
+			return err
+		}
+		if lead.CustomerID != nil {
+			return ErrLeadAlreadyConverted
+		}
+
+		customerCode, err := generateCustomerCode(tx)
+		if err != nil {
+			return fmt.Errorf("failed to generate customer code: %w", err)
+		}
+		customer.CustomerCode = customerCode
+		if err := tx.Create(customer).Error; err != nil {
+			return fmt.Errorf("failed to create customer: %w", err)
+		}
+
+		if cuUser != nil {
+			cuUser.CustomerID = customer.ID
+			if err := tx.Create(cuUser).Error; err != nil {
+				return fmt.Errorf("failed to create cu user: %w", err)
+			}
+			lead.CuUserID = &cuUser.ID
+		}
+
+		if authCode != nil {
+			authCode.CustomerID = customer.ID
+			if err := tx.Create(authCode).Error; err != nil {
+				return fmt.Errorf("failed to create trial authorization code: %w", err)
+			}
+			lead.TrialAuthorizationCodeID = &authCode.ID
+		}
+
+		now := time.Now()
+		lead.CustomerID = &customer.ID
+		lead.ConvertedAt = &now
+		lead.Status = string(models.LeadStatusConverted)
+		lead.UpdatedAt = now
+		if err := tx.Save(&lead).Error; err != nil {
+			return fmt.Errorf("failed to update lead: %w", err)
+		}
+
+		activity := models.NewLeadActivity(lead.ID, models.LeadActivityTypeConversion, customer.CustomerCode+" "+customer.CustomerName, customer.CreatedBy)
+		if err := tx.Create(activity).Error; err != nil {
+			return fmt.Errorf("failed to create lead activity: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrLeadAlreadyConverted) {
+			return &lead, err
+		}
+		return nil, err
+	}
+	return &lead, nil
+}
+
+func (r *leadRepository) SaveWithActivities(ctx context.Context, lead *models.Lead, activities ...*models.LeadActivity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(lead).Error; err != nil {
+			return fmt.Errorf("failed to update lead: %w", err)
+		}
+		for _, activity := range activities {
+			if err := tx.Create(activity).Error; err != nil {
+				return fmt.Errorf("failed to create lead activity: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *leadRepository) FindDuplicates(ctx context.Context, lead *models.Lead, limit int) ([]*models.Lead, error) {
+	conditions := make([]string, 0, 3)
+	args := make([]interface{}, 0, 3)
+	if lead.NormalizedPhone != "" {
+		conditions = append(conditions, "normalized_phone = ?")
+		args = append(args, lead.NormalizedPhone)
+	}
+	if lead.NormalizedEmail != "" {
+		conditions = append(conditions, "normalized_email = ?")
+		args = append(args, lead.NormalizedEmail)
+	}
+	if lead.NormalizedCompany != "" {
+		conditions = append(conditions, "normalized_company = ?")
+		args = append(args, lead.NormalizedCompany)
+	}
+
+	leads := []*models.Lead{}
+	if len(conditions) == 0 {
+		return leads, nil
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.Lead{}).
+		Where("merged_into_id IS NULL").
+		Where("("+strings.Join(conditions, " OR ")+")", args...)
+	if lead.ID != "" {
+		query = query.Where("id <> ?", lead.ID)
+	}
+	if err := query.Order("created_at ASC").Limit(limit).Find(&leads).Error; err != nil {
+		return nil, fmt.Errorf("failed to find duplicate leads: %w", err)
+	}
+	return leads, nil
+}
+
+func (r *leadRepository) MergeLead(ctx context.Context, source, target *models.Lead, activities ...*models.LeadActivity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(target).Error; err != nil {
+			return fmt.Errorf("failed to update target lead: %w", err)
+		}
+		if err := tx.Save(source).Error; err != nil {
+			return fmt.Errorf("failed to update source lead: %w", err)
+		}
+		if err := tx.Model(&models.Lead{}).
+			Where("duplicate_of_id = ? AND id <> ?", source.ID, target.ID).
+			Update("duplicate_of_id", target.ID).Error; err != nil {
+			return fmt.Errorf("failed to update duplicate leads: %w", err)
+		}
+		for _, activity := range activities {
+			if err := tx.Create(activity).Error; err != nil {
+				return fmt.Errorf("failed to create lead activity: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *leadRepository) CreateActivity(ctx context.Context, activity *models.LeadActivity) error {
+	if err := r.db.WithContext(ctx).Create(activity).Error; err != nil {
+		return fmt.Errorf("failed to create lead activity: %w", err)
+	}
+	return nil
+}
+
+func (r *leadRepository) GetActivities(ctx context.Context, leadID string, req *models.LeadActivityListRequest) ([]*models.LeadActivity, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.LeadActivity{}).Where("lead_activities.lead_id = ?", leadID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count lead activities: %w", err)
+	}
+
+	activities := []*models.LeadActivity{}
+	offset := (req.Page - 1) * req.PageSize
+	err := query.
+		Select("lead_activities.*, users.full_name AS operator_name").
+		Joins("LEFT JOIN users ON users.id = lead_activities.operator_id").
+		Order("lead_activities.created_at DESC").
+		Offset(offset).Limit(req.PageSize).
+		Find(&activities).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query lead activities: %w", err)
+	}
+	return activities, total, nil
+}
+
+func (r *leadRepository) GetDueFollowUps(ctx context.Context, now time.Time, limit int) ([]*models.Lead, error) {
+	leads := []*models.Lead{}
+	err := r.db.WithContext(ctx).
+		Where("follow_up_date IS NOT NULL AND follow_up_date <= ?", now).
+		Where("follow_up_reminded_at IS NULL OR follow_up_reminded_at < follow_up_date").
+		Where("status NOT IN ? AND merged_into_id IS NULL", []string{string(models.LeadStatusConverted), string(models.LeadStatusInvalid)}).
+		Order("follow_up_date ASC").
+		Limit(limit).
+		Find(&leads).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due follow-ups: %w", err)
+	}
+	return leads, nil
+}
+
+func (r *leadRepository) GetStalePending(ctx context.Context, before time.Time, limit int) ([]*models.Lead, error) {
+	leads := []*models.Lead{}
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND created_at <= ?", string(models.LeadStatusPending), before).
+		Where("pending_reminded_at IS NULL AND merged_into_id IS NULL").
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&leads).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stale pending leads: %w", err)
+	}
+	return leads, nil
+}
+
+func (r *leadRepository) MarkFollowUpReminded(ctx context.Context, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	// 只更新提醒时间，不修改 updated_at
+	return r.db.WithContext(ctx).Model(&models.Lead{}).Where("id IN ?", ids).UpdateColumn("follow_up_reminded_at", at).Error
+}
+
+func (r *leadRepository) MarkPendingReminded(ctx context.Context, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&models.Lead{}).Where("id IN ?", ids).UpdateColumn("pending_reminded_at", at).Error
+}
+
+func (r *leadRepository) GetAdminUserIDs(ctx context.Context) ([]string, error) {
+	ids := []string{}
+	err := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("status = ? AND role IN ?", "active", []string{models.UserRoleAdmin, models.UserRoleAdministrator}).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query admin users: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *leadRepository) IsStaffUser(ctx context.Context, userID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND status = ? AND role <> ?", userID, "active", models.UserRolePartner).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check staff user: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// openLicenseCommandStatuses 仍在等待执行的命令状态
+var openLicenseCommandStatuses = []string{models.LicenseCommandStatusPending, models.LicenseCommandStatusDelivered}
+
+// LicenseCommandRepository 远程命令仓储接口
+type LicenseCommandRepository interface {
+	Create(ctx context.Context, command *models.LicenseCommand) error
+	GetByID(ctx context.Context, id string) (*models.LicenseCommand, error)
+	GetList(ctx context.Context, licenseID string, req *models.LicenseCommandListRequest, now time.Time) ([]*models.LicenseCommand, int64, error)
+	Cancel(ctx context.Context, id string) error
+	Acknowledge(ctx context.Context, licenseID, id, status string, result models.JSON, errorMessage *string, now time.Time) (*models.LicenseCommand, error)
+	ExpireOverdue(ctx context.Context, licenseID string, now time.Time) error
+	GetDeliverable(ctx context.Context, licenseID string, now time.Time) ([]*models.LicenseCommand, error)
+	MarkDelivered(ctx context.Context, ids []string, now time.Time) error
+}
+
+type licenseCommandRepository struct {
+	db *gorm.DB
+}
+
+// NewLicenseCommandRepository 创建远程命令仓储
+func NewLicenseCommandRepository(db *gorm.DB) LicenseCommandRepository {
+	return &licenseCommandRepository{db: db}
+}
+
+// Create 创建命令
+func (r *licenseCommandRepository) Create(ctx context.Context, command *models.LicenseCommand) error {
+	return r.db.WithContext(ctx).Create(command).Error
+}
+
+// GetByID 根据ID获取命令
+func (r *licenseCommandRepository) GetByID(ctx context.Context, id string) (*models.LicenseCommand, error) {
+	var command models.LicenseCommand
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&command).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLicenseCommandNotFound
+		}
+		return nil, err
+	}
+	return &command, nil
+}
+
+// GetList 分页查询许可证的命令，按创建时间倒序；expired 状态按有效期计算
+func (r *licenseCommandRepository) GetList(ctx context.Context, licenseID string, req *models.LicenseCommandListRequest, now time.Time) ([]*models.LicenseCommand, int64, error) {
+	var commands []*models.LicenseCommand
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.LicenseCommand{}).Where("license_id = ?", licenseID)
+	switch req.Status {
+	case "":
+	case models.LicenseCommandStatusPending, models.LicenseCommandStatusDelivered:
+		query = query.Where("status = ? AND expires_at >= ?", req.Status, now)
+	case models.LicenseCommandStatusExpired:
+		query = query.Where("status = ? OR (status IN ? AND expires_at < ?)", models.LicenseCommandStatusExpired, openLicenseCommandStatuses, now)
+	default:
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&commands).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return commands, total, nil
+}
+
+// Cancel 取消尚未确认的命令
+func (r *licenseCommandRepository) Cancel(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Model(&models.LicenseCommand{}).
+		Where("id = ? AND status IN ?", id, openLicenseCommandStatuses).
+		Updates(map[string]interface{}{
+			"status":     models.LicenseCommandStatusCancelled,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLicenseCommandNotOpen
+	}
+	return nil
+}
+
+// Acknowledge 记录客户端上报的执行结果，仅处理属于该许可证且尚未确认的命令
+func (r *licenseCommandRepository) Acknowledge(ctx context.Context, licenseID, id, status string, result models.JSON, errorMessage *string, now time.Time) (*models.LicenseCommand, error) {
+	var command models.LicenseCommand
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND license_id = ?", id, licenseID).First(&command).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrLicenseCommandNotFound
+			}
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":          status,
+			"result":          result,
+			"error_message":   errorMessage,
+			"acknowledged_at": now,
+			"updated_at":      now,
+		}
+		res := tx.Model(&models.LicenseCommand{}).
+			Where("id = ? AND status IN ?", id, openLicenseCommandStatuses).
+			Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrLicenseCommandNotOpen
+		}
+
+		command.Status = status
+		command.Result = result
+		command.ErrorMessage = errorMessage
+		command.AcknowledgedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &command, nil
+}
+
+// ExpireOverdue 将超过有效期仍未确认的命令标记为过期
+func (r *licenseCommandRepository) ExpireOverdue(ctx context.Context, licenseID string, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.LicenseCommand{}).
+		Where("license_id = ? AND status IN ? AND expires_at < ?", licenseID, openLicenseCommandStatuses, now).
+		Updates(map[string]interface{}{
+			"status":     models.LicenseCommandStatusExpired,
+			"updated_at": now,
+		}).Error
+}
+
+// GetDeliverable 获取需要随心跳下发的命令（待下发和已下发未确认），按创建时间顺序
+func (r *licenseCommandRepository) GetDeliverable(ctx context.Context, licenseID string, now time.Time) ([]*models.LicenseCommand, error) {
+	var commands []*models.LicenseCommand
+	err := r.db.WithContext(ctx).
+		Where("license_id = ? AND status IN ? AND expires_at >= ?", licenseID, openLicenseCommandStatuses, now).
+		Order("created_at ASC").
+		Find(&commands).Error
+	return commands, err
+}
+
+// MarkDelivered 标记命令已下发并累计下发次数，首次下发时间保持不变
+func (r *licenseCommandRepository) MarkDelivered(ctx context.Context, ids []string, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&models.LicenseCommand{}).
+		Where("id IN ? AND status IN ?", ids, openLicenseCommandStatuses).
+		Updates(map[string]interface{}{
+			"status":         models.LicenseCommandStatusDelivered,
+			"delivered_at":   gorm.Expr("COALESCE(delivered_at, ?)", now),
+			"delivery_count": gorm.Expr("delivery_count + 1"),
+			"updated_at":     now,
+		}).Error
+}
@@ -56,7 +56,9 @@ func (r *licenseRepository) GetLicenseList(ctx context.Context, req *models.Lice
 	}
 
 	// 客户ID筛选
-	if req.CustomerID != "" {
+	if req.CustomerIDs != nil {
+		query = query.Where("licenses.customer_id IN ?", req.CustomerIDs)
+	} else if req.CustomerID != "" {
 		query = query.Where("licenses.customer_id = ?", req.CustomerID)
 	}
 
@@ -81,6 +83,9 @@ func (r *licenseRepository) GetLicenseList(ctx context.Context, req *models.Lice
 		}
 	}
 
+	// 标签和自定义字段筛选
+	query = applyCustomFieldFilter(query, models.CustomFieldEntityLicense, "licenses.id", &req.CustomFieldFilter)
+
 	// 获取总数
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -203,6 +208,18 @@ func (r *licenseRepository) UpdateLicense(ctx context.Context, license *models.L
 	return r.db.Save(license).Error
 }
 
+// UpdateLicenseHeartbeat 仅更新心跳、使用数据与配置时间等字段，并以读取时的状态为条件，
+// 避免心跳期间许可证被转移、撤销或回收后又被整行保存覆盖回原状态
+func (r *licenseRepository) UpdateLicenseHeartbeat(ctx context.Context, licenseID, loadedStatus string, updates map[string]interface{}) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.License{}).
+		Where("id = ? AND status = ?", licenseID, loadedStatus).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // CheckAuthorizationCodeExists 检查授权码是否存在
 func (r *licenseRepository) CheckAuthorizationCodeExists(ctx context.Context, authCodeID string) (bool, error) {
 	var count int64
@@ -267,6 +284,21 @@ func (r *licenseRepository) GetLicenseByKey(ctx context.Context, licenseKey stri
 	return &license, nil
 }
 
+// GetLicenseByHardwareFingerprint 获取授权码下指定硬件指纹设备的许可证
+func (r *licenseRepository) GetLicenseByHardwareFingerprint(ctx context.Context, authCodeID, hardwareFingerprint string) (*models.License, error) {
+	var license models.License
+	err := r.db.WithContext(ctx).
+		Where("authorization_code_id = ? AND hardware_fingerprint = ?", authCodeID, hardwareFingerprint).
+		First(&license).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrLicenseNotFound
+		}
+		return nil, err
+	}
+	return &license, nil
+}
+
 // GetActiveLicenseCount 获取指定授权码的激活许可证数量
 func (r *licenseRepository) GetActiveLicenseCount(ctx context.Context, authCodeID string) (int64, error) {
 	var count int64
@@ -417,7 +449,12 @@ func (r *licenseRepository) GetCustomerDeviceList(ctx context.Context, customerI
 
 // DeleteLicenseByID 根据ID删除许可证（物理删除，用于设备解绑）
 func (r *licenseRepository) DeleteLicenseByID(ctx context.Context, id string) error {
-	return r.db.Unscoped().Delete(&models.License{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(&models.License{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		return deleteEntityCustomFields(tx, models.CustomFieldEntityLicense, []string{id})
+	})
 }
 
 // GetCustomerDeviceSummary 获取客户设备汇总统计
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// LicenseTransferRepository 许可证转移仓储接口
+type LicenseTransferRepository interface {
+	GetSeatIDWithTx(ctx context.Context, tx interface{}, licenseID string) (string, error)
+	CountSeatTransfersWithTx(ctx context.Context, tx interface{}, seatID string, since *time.Time) (int64, error)
+	GetLatestByFromLicenseID(ctx context.Context, licenseID string) (*models.LicenseTransfer, error)
+	GetList(ctx context.Context, req *models.LicenseTransferListRequest) ([]*models.LicenseTransfer, int64, error)
+}
+
+type licenseTransferRepository struct {
+	db *gorm.DB
+}
+
+// NewLicenseTransferRepository 创建许可证转移仓储
+func NewLicenseTransferRepository(db *gorm.DB) LicenseTransferRepository {
+	return &licenseTransferRepository{db: db}
+}
+
+// GetSeatIDWithTx 在事务中获取许可证所属席位ID
+// 转移产生的许可证沿用原席位ID，否则许可证自身即为席位
+func (r *licenseTransferRepository) GetSeatIDWithTx(ctx context.Context, tx interface{}, licenseID string) (string, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return "", ErrInvalidTransaction
+	}
+
+	var transfer models.LicenseTransfer
+	err := gormTx.WithContext(ctx).Where("to_license_id = ?", licenseID).
+		Order("created_at DESC").First(&transfer).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return licenseID, nil
+		}
+		return "", err
+	}
+	return transfer.SeatID, nil
+}
+
+// CountSeatTransfersWithTx 在事务中统计席位在指定时间之后的转移次数，since为空时统计全部
+func (r *licenseTransferRepository) CountSeatTransfersWithTx(ctx context.Context, tx interface{}, seatID string, since *time.Time) (int64, error) {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return 0, ErrInvalidTransaction
+	}
+
+	var count int64
+	query := gormTx.WithContext(ctx).Model(&models.LicenseTransfer{}).Where("seat_id = ?", seatID)
+	if since != nil {
+		query = query.Where("created_at >= ?", *since)
+	}
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// GetLatestByFromLicenseID 获取许可证最近一次被转出的记录
+func (r *licenseTransferRepository) GetLatestByFromLicenseID(ctx context.Context, licenseID string) (*models.LicenseTransfer, error) {
+	var transfer models.LicenseTransfer
+	err := r.db.WithContext(ctx).Where("from_license_id = ?", licenseID).
+		Order("created_at DESC").First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// GetList 查询许可证转移记录列表
+func (r *licenseTransferRepository) GetList(ctx context.Context, req *models.LicenseTransferListRequest) ([]*models.LicenseTransfer, int64, error) {
+	var transfers []*models.LicenseTransfer
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.LicenseTransfer{})
+
+	if req.AuthorizationCodeID != "" {
+		query = query.Where("authorization_code_id = ?", req.AuthorizationCodeID)
+	}
+	if req.CustomerID != "" {
+		query = query.Where("customer_id = ?", req.CustomerID)
+	}
+	if req.LicenseID != "" {
+		query = query.Where("(from_license_id = ? OR to_license_id = ?)", req.LicenseID, req.LicenseID)
+	}
+
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&transfers).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return transfers, total, nil
+}
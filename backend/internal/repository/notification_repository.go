@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository 站内通知仓储接口
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	GetByID(ctx context.Context, id string) (*models.Notification, error)
+	GetList(ctx context.Context, recipientType, recipientID string, req *models.NotificationListRequest) ([]models.Notification, int64, error)
+	CountUnread(ctx context.Context, recipientType, recipientID string) (int64, error)
+	MarkRead(ctx context.Context, id string, readAt time.Time) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建站内通知仓储
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+func (r *notificationRepository) GetByID(ctx context.Context, id string) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&notification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *notificationRepository) GetList(ctx context.Context, recipientType, recipientID string, req *models.NotificationListRequest) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("recipient_type = ? AND recipient_id = ?", recipientType, recipientID)
+
+	if req.Type != "" {
+		query = query.Where("type = ?", req.Type)
+	}
+	if req.IsRead != nil && *req.IsRead != "" {
+		if *req.IsRead == "true" {
+			query = query.Where("read_at IS NOT NULL")
+		} else if *req.IsRead == "false" {
+			query = query.Where("read_at IS NULL")
+		}
+	}
+
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, recipientType, recipientID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("recipient_type = ? AND recipient_id = ? AND read_at IS NULL", recipientType, recipientID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, id string, readAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND read_at IS NULL", id).
+		Update("read_at", readAt).Error
+}
@@ -13,6 +13,8 @@ type PackageRepository interface {
 	Update(pkg *models.Package) error
 	Delete(id string) error
 	GetByID(id string) (*models.Package, error)
+	// GetByIDUnscoped 获取套餐，包括已删除的套餐（用于兑现已售出的兑换券等）
+	GetByIDUnscoped(id string) (*models.Package, error)
 	GetList(ctx context.Context, req *models.PackageListRequest) ([]*models.Package, int64, error)
 	GetEnabledList(ctx context.Context) ([]*models.Package, error)
 	GetByType(ctx context.Context, pkgType string) ([]*models.Package, error)
@@ -48,6 +50,15 @@ func (r *packageRepository) GetByID(id string) (*models.Package, error) {
 	return &pkg, nil
 }
 
+func (r *packageRepository) GetByIDUnscoped(id string) (*models.Package, error) {
+	var pkg models.Package
+	err := r.db.Unscoped().Where("id = ?", id).First(&pkg).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pkg, nil
+}
+
 func (r *packageRepository) GetList(ctx context.Context, req *models.PackageListRequest) ([]*models.Package, int64, error) {
 	var packages []*models.Package
 	var total int64
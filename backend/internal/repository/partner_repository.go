@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PartnerRepository 经销商数据访问接口
+type PartnerRepository interface {
+	CreatePartner(ctx context.Context, partner *models.User) error
+	GetPartnerByID(ctx context.Context, id string) (*models.User, error)
+	UpdatePartner(ctx context.Context, partner *models.User) error
+	GetPartnerList(ctx context.Context, req *models.PartnerListRequest) ([]*models.User, int64, error)
+	// GetPartnerUsage 批量统计经销商名下客户数量及已分配席位
+	GetPartnerUsage(ctx context.Context, partnerIDs []string) (map[string]*models.PartnerUsage, error)
+	// GetPartnerCustomerIDs 获取经销商名下的全部客户ID
+	GetPartnerCustomerIDs(ctx context.Context, partnerID string) ([]string, error)
+	// GetPartnerOrders 分页查询经销商名下客户的订单及最近一次支付情况
+	GetPartnerOrders(ctx context.Context, partnerID string, req *models.PartnerOrderListRequest) ([]*models.PartnerOrderItem, int64, error)
+	// ExistsUsernameOrEmail 用户名或邮箱是否已被后台账号占用
+	ExistsUsernameOrEmail(ctx context.Context, username, email, excludeID string) (bool, error)
+	// CheckSeatQuotaWithTx 在写入授权码的事务中锁定经销商账号并校验席位额度，超出时返回 ErrPartnerQuotaExceeded
+	CheckSeatQuotaWithTx(ctx context.Context, tx interface{}, partnerID, excludeCodeID string, seats int) error
+}
+
+type partnerRepository struct {
+	db *gorm.DB
+}
+
+// NewPartnerRepository 创建经销商数据访问实例
+func NewPartnerRepository(db *gorm.DB) PartnerRepository {
+	return &partnerRepository{db: db}
+}
+
+func (r *partnerRepository) CreatePartner(ctx context.Context, partner *models.User) error {
+	if err := r.db.WithContext(ctx).Create(partner).Error; err != nil {
+		return fmt.Errorf("failed to create partner: %w", err)
+	}
+	return nil
+}
+
+func (r *partnerRepository) GetPartnerByID(ctx context.Context, id string) (*models.User, error) {
+	var partner models.User
+	err := r.db.WithContext(ctx).Where("id = ? AND role = ?", id, models.UserRolePartner).First(&partner).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPartnerNotFound
+		}
+		return nil, fmt.Errorf("failed to get partner: %w", err)
+	}
+	return &partner, nil
+}
+
+func (r *partnerRepository) UpdatePartner(ctx context.Context, partner *models.User) error {
+	if err := r.db.WithContext(ctx).Save(partner).Error; err != nil {
+		return fmt.Errorf("failed to update partner: %w", err)
+	}
+	return nil
+}
+
+func (r *partnerRepository) GetPartnerList(ctx context.Context, req *models.PartnerListRequest) ([]*models.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.User{}).Where("role = ?", models.UserRolePartner)
+	if req.Search != "" {
+		searchTerm := "%" + strings.TrimSpace(req.Search) + "%"
+		query = query.Where("username LIKE ? OR full_name LIKE ? OR email LIKE ?", searchTerm, searchTerm, searchTerm)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count partners: %w", err)
+	}
+
+	var partners []*models.User
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.PageSize).Find(&partners).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query partners: %w", err)
+	}
+	return partners, total, nil
+}
+
+func (r *partnerRepository) GetPartnerUsage(ctx context.Context, partnerIDs []string) (map[string]*models.PartnerUsage, error) {
+	result := make(map[string]*models.PartnerUsage, len(partnerIDs))
+	for _, id := range partnerIDs {
+		result[id] = &models.PartnerUsage{PartnerID: id}
+	}
+	if len(partnerIDs) == 0 {
+		return result, nil
+	}
+	db := r.db.WithContext(ctx)
+
+	var customerRows []struct {
+		PartnerID string
+		Total     int64
+	}
+	if err := db.Model(&models.Customer{}).
+		Select("partner_id, COUNT(*) AS total").
+		Where("partner_id IN ?", partnerIDs).
+		Group("partner_id").
+		Scan(&customerRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count partner customers: %w", err)
+	}
+	for _, row := range customerRows {
+		result[row.PartnerID].CustomerCount = row.Total
+	}
+
+	var seatRows []struct {
+		PartnerID string
+		Total     int64
+	}
+	if err := partnerSeats(db, partnerIDs).
+		Select("customers.partner_id AS partner_id, COALESCE(SUM(authorization_codes.max_activations), 0) AS total").
+		Group("customers.partner_id").
+		Scan(&seatRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to sum partner seats: %w", err)
+	}
+	for _, row := range seatRows {
+		result[row.PartnerID].UsedSeats = row.Total
+	}
+
+	return result, nil
+}
+
+// partnerSeats 经销商名下客户的授权码，已分配席位按授权码的最大激活数统计
+func partnerSeats(db *gorm.DB, partnerIDs []string) *gorm.DB {
+	return db.Model(&models.AuthorizationCode{}).
+		Joins("INNER JOIN customers ON customers.id = authorization_codes.customer_id").
+		Where("customers.partner_id IN ? AND customers.deleted_at IS NULL", partnerIDs)
+}
+
+// CheckSeatQuotaWithTx 在写入授权码的事务中锁定经销商账号并校验席位额度，超出时返回 ErrPartnerQuotaExceeded
+// 同一经销商的新增席位串行执行；excludeCodeID 为正在修改的授权码，按其修改后的席位数 seats 重新计算
+func (r *partnerRepository) CheckSeatQuotaWithTx(ctx context.Context, tx interface{}, partnerID, excludeCodeID string, seats int) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return ErrInvalidTransaction
+	}
+	gormTx = gormTx.WithContext(ctx)
+
+	var partner models.User
+	err := gormTx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "seat_quota").
+		Where("id = ? AND role = ?", partnerID, models.UserRolePartner).
+		First(&partner).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPartnerNotFound
+		}
+		return fmt.Errorf("failed to lock partner: %w", err)
+	}
+
+	query := partnerSeats(gormTx, []string{partnerID})
+	if excludeCodeID != "" {
+		query = query.Where("authorization_codes.id <> ?", excludeCodeID)
+	}
+	var usedSeats int64
+	if err := query.Select("COALESCE(SUM(authorization_codes.max_activations), 0)").Scan(&usedSeats).Error; err != nil {
+		return fmt.Errorf("failed to sum partner seats: %w", err)
+	}
+	if usedSeats+int64(seats) > int64(partner.SeatQuota) {
+		return ErrPartnerQuotaExceeded
+	}
+	return nil
+}
+
+func (r *partnerRepository) GetPartnerCustomerIDs(ctx context.Context, partnerID string) ([]string, error) {
+	ids := []string{}
+	if err := r.db.WithContext(ctx).Model(&models.Customer{}).
+		Where("partner_id = ?", partnerID).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to query partner customers: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *partnerRepository) GetPartnerOrders(ctx context.Context, partnerID string, req *models.PartnerOrderListRequest) ([]*models.PartnerOrderItem, int64, error) {
+	query := r.db.WithContext(ctx).Table("cu_orders").
+		Joins("INNER JOIN customers ON customers.id = cu_orders.customer_id").
+		Where("customers.partner_id = ? AND cu_orders.deleted_at IS NULL", partnerID)
+	if req.CustomerID != "" {
+		query = query.Where("cu_orders.customer_id = ?", req.CustomerID)
+	}
+	if req.Status != "" {
+		query = query.Where("cu_orders.status = ?", req.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count partner orders: %w", err)
+	}
+
+	items := []*models.PartnerOrderItem{}
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.
+		Select(`cu_orders.id, cu_orders.order_no, cu_orders.customer_id, customers.customer_name,
+			cu_orders.package_name, cu_orders.order_type, cu_orders.license_count, cu_orders.total_amount,
+			cu_orders.status, payments.payment_no, payments.payment_method, payments.status AS payment_status,
+			payments.payment_time, cu_orders.created_at`).
+		Joins(`LEFT JOIN payments ON payments.id = (SELECT MAX(p.id) FROM payments p
+			WHERE p.business_type = ? AND p.business_id = cu_orders.id)`, models.BusinessTypePackageOrder).
+		Order("cu_orders.created_at DESC").
+		Offset(offset).Limit(req.PageSize).
+		Scan(&items).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query partner orders: %w", err)
+	}
+	return items, total, nil
+}
+
+func (r *partnerRepository) ExistsUsernameOrEmail(ctx context.Context, username, email, excludeID string) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&models.User{}).Where("username = ? OR email = ?", username, email)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check partner account: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SeatPoolRepository 客户席位池仓储接口
+type SeatPoolRepository interface {
+	// GetSeatPool 获取客户席位池及使用情况
+	GetSeatPool(ctx context.Context, customerID string) (*models.CustomerSeatPool, error)
+	// SaveSeatPool 创建或更新客户席位池，总席位少于已占用席位时返回 ErrSeatPoolSeatsUsed
+	SaveSeatPool(ctx context.Context, pool *models.CustomerSeatPool) error
+	// DeleteSeatPool 删除客户席位池，并关闭该客户所有授权码的席位池取用
+	DeleteSeatPool(ctx context.Context, customerID string) error
+	// CheckSeatPoolWithTx 在激活事务中锁定客户席位池并检查剩余席位，席位已满时返回 ErrSeatPoolExhausted，客户未设置席位池时返回 ErrSeatPoolNotFound
+	CheckSeatPoolWithTx(ctx context.Context, tx interface{}, customerID string) error
+}
+
+type seatPoolRepository struct {
+	db *gorm.DB
+}
+
+// NewSeatPoolRepository 创建客户席位池仓储
+func NewSeatPoolRepository(db *gorm.DB) SeatPoolRepository {
+	return &seatPoolRepository{db: db}
+}
+
+// seatPoolCodes 从席位池取用席位的有效授权码（未锁定且未过期），锁定或过期的授权码不占用席位池
+func seatPoolCodes(db *gorm.DB, customerID string) *gorm.DB {
+	return db.Table("authorization_codes ac").
+		Where("ac.customer_id = ? AND ac.deleted_at IS NULL AND ac.use_seat_pool = true AND ac.is_locked = false AND ac.end_date >= NOW()", customerID)
+}
+
+// fillSeatPoolUsage 统计席位池的授权码数量和已占用席位
+func fillSeatPoolUsage(db *gorm.DB, pool *models.CustomerSeatPool) error {
+	var codeCount, usedSeats int64
+	if err := seatPoolCodes(db, pool.CustomerID).Count(&codeCount).Error; err != nil {
+		return fmt.Errorf("failed to count seat pool codes: %w", err)
+	}
+	if err := seatPoolCodes(db, pool.CustomerID).
+		Joins("JOIN licenses ON licenses.authorization_code_id = ac.id").
+		Where("licenses.status = ? AND licenses.deleted_at IS NULL", "active").
+		Count(&usedSeats).Error; err != nil {
+		return fmt.Errorf("failed to count seat pool usage: %w", err)
+	}
+	pool.SetUsage(codeCount, usedSeats)
+	return nil
+}
+
+// lockSeatPool 加行锁读取客户席位池，同一客户的激活和席位池修改串行执行
+func lockSeatPool(tx *gorm.DB, customerID string) (*models.CustomerSeatPool, error) {
+	var pool models.CustomerSeatPool
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("customer_id = ?", customerID).First(&pool).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSeatPoolNotFound
+		}
+		return nil, err
+	}
+	return &pool, nil
+}
+
+// GetSeatPool 获取客户席位池及使用情况
+func (r *seatPoolRepository) GetSeatPool(ctx context.Context, customerID string) (*models.CustomerSeatPool, error) {
+	db := r.db.WithContext(ctx)
+	var pool models.CustomerSeatPool
+	if err := db.Where("customer_id = ?", customerID).First(&pool).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSeatPoolNotFound
+		}
+		return nil, err
+	}
+	if err := fillSeatPoolUsage(db, &pool); err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+// SaveSeatPool 创建或更新客户席位池，总席位少于已占用席位时返回 ErrSeatPoolSeatsUsed
+func (r *seatPoolRepository) SaveSeatPool(ctx context.Context, pool *models.CustomerSeatPool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := lockSeatPool(tx, pool.CustomerID)
+		if err != nil && !errors.Is(err, ErrSeatPoolNotFound) {
+			return err
+		}
+		if err := fillSeatPoolUsage(tx, pool); err != nil {
+			return err
+		}
+		if err := checkSeatPoolTotal(pool); err != nil {
+			return err
+		}
+
+		if existing == nil {
+			return tx.Create(pool).Error
+		}
+		pool.ID = existing.ID
+		pool.CreatedBy = existing.CreatedBy
+		pool.CreatedAt = existing.CreatedAt
+		pool.UpdatedAt = time.Now()
+		return tx.Model(existing).Updates(map[string]interface{}{
+			"total_seats": pool.TotalSeats,
+			"description": pool.Description,
+			"updated_by":  pool.UpdatedBy,
+			"updated_at":  pool.UpdatedAt,
+		}).Error
+	})
+}
+
+// DeleteSeatPool 删除客户席位池，并关闭该客户所有授权码的席位池取用
+func (r *seatPoolRepository) DeleteSeatPool(ctx context.Context, customerID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return deleteSeatPool(tx, customerID)
+	})
+}
+
+// deleteSeatPool 在事务中删除客户席位池并关闭授权码的席位池取用
+func deleteSeatPool(tx *gorm.DB, customerID string) error {
+	result := tx.Where("customer_id = ?", customerID).Delete(&models.CustomerSeatPool{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete seat pool: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrSeatPoolNotFound
+	}
+	if err := tx.Model(&models.AuthorizationCode{}).
+		Where("customer_id = ? AND use_seat_pool = true", customerID).
+		Update("use_seat_pool", false).Error; err != nil {
+		return fmt.Errorf("failed to detach authorization codes from seat pool: %w", err)
+	}
+	return nil
+}
+
+// checkSeatPoolTotal 席位池总席位不能少于已占用席位
+func checkSeatPoolTotal(pool *models.CustomerSeatPool) error {
+	if int64(pool.TotalSeats) < pool.UsedSeats {
+		return ErrSeatPoolSeatsUsed
+	}
+	return nil
+}
+
+// checkSeatPoolRemaining 席位池须仍有剩余席位才能激活
+func checkSeatPoolRemaining(pool *models.CustomerSeatPool) error {
+	if pool.RemainingSeats <= 0 {
+		return ErrSeatPoolExhausted
+	}
+	return nil
+}
+
+// CheckSeatPoolWithTx 在激活事务中锁定客户席位池并检查剩余席位，席位已满时返回 ErrSeatPoolExhausted
+// 客户未设置席位池时返回 ErrSeatPoolNotFound，由调用方按授权码自身的最大激活次数限制（删除席位池会同时关闭授权码的席位池取用）
+func (r *seatPoolRepository) CheckSeatPoolWithTx(ctx context.Context, tx interface{}, customerID string) error {
+	gormTx, ok := tx.(*gorm.DB)
+	if !ok {
+		return ErrInvalidTransaction
+	}
+	gormTx = gormTx.WithContext(ctx)
+
+	pool, err := lockSeatPool(gormTx, customerID)
+	if err != nil {
+		return err
+	}
+	if err := fillSeatPoolUsage(gormTx, pool); err != nil {
+		return err
+	}
+	return checkSeatPoolRemaining(pool)
+}
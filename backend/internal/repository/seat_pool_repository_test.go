@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"license-manager/internal/models"
+)
+
+func TestCheckSeatPoolTotal(t *testing.T) {
+	cases := []struct {
+		name       string
+		totalSeats int
+		usedSeats  int64
+		want       error
+	}{
+		{"below used seats", 4, 5, ErrSeatPoolSeatsUsed},
+		{"equal to used seats", 5, 5, nil},
+		{"above used seats", 10, 5, nil},
+		{"no usage", 1, 0, nil},
+	}
+	for _, c := range cases {
+		pool := &models.CustomerSeatPool{TotalSeats: c.totalSeats}
+		pool.SetUsage(1, c.usedSeats)
+		if got := checkSeatPoolTotal(pool); !errors.Is(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestCheckSeatPoolRemaining(t *testing.T) {
+	cases := []struct {
+		name       string
+		totalSeats int
+		usedSeats  int64
+		want       error
+	}{
+		{"seats remaining", 10, 9, nil},
+		{"all seats used", 10, 10, ErrSeatPoolExhausted},
+		// 总席位调低到已占用席位以下时剩余席位按0处理
+		{"over used", 5, 8, ErrSeatPoolExhausted},
+	}
+	for _, c := range cases {
+		pool := &models.CustomerSeatPool{TotalSeats: c.totalSeats}
+		pool.SetUsage(1, c.usedSeats)
+		if got := checkSeatPoolRemaining(pool); !errors.Is(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SeatReclaimRepository 闲置席位回收仓储接口
+type SeatReclaimRepository interface {
+	FindDormantLicenses(ctx context.Context, now time.Time) ([]*models.DormantLicense, error)
+	ReclaimLicense(ctx context.Context, record *models.SeatReclamation, notification *models.Notification) (bool, error)
+	GetReclamationList(ctx context.Context, req *models.SeatReclamationListRequest) ([]*models.SeatReclamationListItem, int64, error)
+}
+
+type seatReclaimRepository struct {
+	db *gorm.DB
+}
+
+// NewSeatReclaimRepository 创建闲置席位回收仓储
+func NewSeatReclaimRepository(db *gorm.DB) SeatReclaimRepository {
+	return &seatReclaimRepository{db: db}
+}
+
+// FindDormantLicenses 查询超过授权码回收策略天数未心跳的激活许可证
+// 从未上报过心跳的许可证（如离线手工创建）不参与回收
+func (r *seatReclaimRepository) FindDormantLicenses(ctx context.Context, now time.Time) ([]*models.DormantLicense, error) {
+	var licenses []*models.DormantLicense
+
+	err := r.db.WithContext(ctx).Table("licenses").
+		Select(`licenses.id, licenses.license_key, licenses.authorization_code_id,
+				authorization_codes.code as authorization_code, licenses.customer_id,
+				licenses.hardware_fingerprint, licenses.device_info, licenses.last_heartbeat,
+				authorization_codes.dormant_reclaim_days`).
+		Joins("INNER JOIN authorization_codes ON licenses.authorization_code_id = authorization_codes.id").
+		Where("licenses.status = ? AND licenses.deleted_at IS NULL", "active").
+		Where("authorization_codes.dormant_reclaim_days > 0").
+		Where("licenses.last_heartbeat IS NOT NULL").
+		Where("licenses.last_heartbeat < DATE_SUB(?, INTERVAL authorization_codes.dormant_reclaim_days DAY)", now).
+		Order("licenses.last_heartbeat ASC").
+		Scan(&licenses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return licenses, nil
+}
+
+// ReclaimLicense 在事务中释放许可证席位、记录回收并通知客户
+// 仅当许可证仍处于激活状态时才回收，返回是否实际发生了回收
+func (r *seatReclaimRepository) ReclaimLicense(ctx context.Context, record *models.SeatReclamation, notification *models.Notification) (bool, error) {
+	reclaimed := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 带状态条件更新，避免与并发心跳/解绑冲突
+		result := tx.Model(&models.License{}).
+			Where("id = ? AND status = ? AND deleted_at IS NULL", record.LicenseID, "active").
+			Updates(map[string]interface{}{
+				"status":     "inactive",
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(record).Error; err != nil {
+			return err
+		}
+		if notification != nil {
+			if err := tx.Create(notification).Error; err != nil {
+				return err
+			}
+		}
+
+		reclaimed = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return reclaimed, nil
+}
+
+// GetReclamationList 查询席位回收记录列表
+func (r *seatReclaimRepository) GetReclamationList(ctx context.Context, req *models.SeatReclamationListRequest) ([]*models.SeatReclamationListItem, int64, error) {
+	var rows []struct {
+		models.SeatReclamation
+		AuthorizationCode string `gorm:"column:authorization_code"`
+		CustomerName      string `gorm:"column:customer_name"`
+	}
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SeatReclamation{}).
+		Select(`seat_reclamations.*, authorization_codes.code as authorization_code,
+				customers.customer_name`).
+		Joins("LEFT JOIN authorization_codes ON seat_reclamations.authorization_code_id = authorization_codes.id").
+		Joins("LEFT JOIN customers ON seat_reclamations.customer_id = customers.id")
+
+	if req.AuthorizationCodeID != "" {
+		query = query.Where("seat_reclamations.authorization_code_id = ?", req.AuthorizationCodeID)
+	}
+	if req.CustomerID != "" {
+		query = query.Where("seat_reclamations.customer_id = ?", req.CustomerID)
+	}
+	if req.TriggerType != "" {
+		query = query.Where("seat_reclamations.trigger_type = ?", req.TriggerType)
+	}
+
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("seat_reclamations.reclaimed_at DESC").Offset(offset).Limit(req.PageSize).Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]*models.SeatReclamationListItem, 0, len(rows))
+	for _, row := range rows {
+		item := &models.SeatReclamationListItem{
+			ID:                  row.ID,
+			LicenseID:           row.LicenseID,
+			LicenseKey:          row.LicenseKey,
+			AuthorizationCodeID: row.AuthorizationCodeID,
+			AuthorizationCode:   row.AuthorizationCode,
+			CustomerID:          row.CustomerID,
+			CustomerName:        row.CustomerName,
+			HardwareFingerprint: row.HardwareFingerprint,
+			PolicyDays:          row.PolicyDays,
+			TriggerType:         row.TriggerType,
+			ReclaimedAt:         row.ReclaimedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if row.LastHeartbeat != nil {
+			lastHeartbeat := row.LastHeartbeat.Format("2006-01-02T15:04:05Z07:00")
+			item.LastHeartbeat = &lastHeartbeat
+		}
+		items = append(items, item)
+	}
+
+	return items, total, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SoftwareReleaseRepository 软件版本发布仓储接口
+type SoftwareReleaseRepository interface {
+	Create(ctx context.Context, release *models.SoftwareRelease) error
+	GetByID(ctx context.Context, id string) (*models.SoftwareRelease, error)
+	Update(ctx context.Context, release *models.SoftwareRelease) error
+	Delete(ctx context.Context, id string) error
+	GetList(ctx context.Context, req *models.SoftwareReleaseListRequest) ([]*models.SoftwareRelease, int64, error)
+	FindPublished(ctx context.Context, softwareID string, channels []string, releasedBefore time.Time) ([]*models.SoftwareRelease, error)
+}
+
+type softwareReleaseRepository struct {
+	db *gorm.DB
+}
+
+// NewSoftwareReleaseRepository 创建软件版本发布仓储
+func NewSoftwareReleaseRepository(db *gorm.DB) SoftwareReleaseRepository {
+	return &softwareReleaseRepository{db: db}
+}
+
+// Create 登记新版本，同一产品的版本号不能重复
+func (r *softwareReleaseRepository) Create(ctx context.Context, release *models.SoftwareRelease) error {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.SoftwareRelease{}).
+		Where("software_id = ? AND version = ?", release.SoftwareID, release.Version).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSoftwareReleaseExists
+	}
+	return r.db.WithContext(ctx).Create(release).Error
+}
+
+// GetByID 根据ID获取版本
+func (r *softwareReleaseRepository) GetByID(ctx context.Context, id string) (*models.SoftwareRelease, error) {
+	var release models.SoftwareRelease
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&release).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSoftwareReleaseNotFound
+		}
+		return nil, err
+	}
+	return &release, nil
+}
+
+// Update 更新版本信息
+func (r *softwareReleaseRepository) Update(ctx context.Context, release *models.SoftwareRelease) error {
+	return r.db.WithContext(ctx).Save(release).Error
+}
+
+// Delete 删除版本
+func (r *softwareReleaseRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.SoftwareRelease{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSoftwareReleaseNotFound
+	}
+	return nil
+}
+
+// GetList 分页查询版本，按发布时间倒序
+func (r *softwareReleaseRepository) GetList(ctx context.Context, req *models.SoftwareReleaseListRequest) ([]*models.SoftwareRelease, int64, error) {
+	var releases []*models.SoftwareRelease
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SoftwareRelease{})
+	if req.SoftwareID != "" {
+		query = query.Where("software_id = ?", req.SoftwareID)
+	}
+	if req.Channel != "" {
+		query = query.Where("channel = ?", req.Channel)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("released_at DESC, created_at DESC").Offset(offset).Limit(req.PageSize).Find(&releases).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return releases, total, nil
+}
+
+// FindPublished 查询产品在指定渠道中已发布（发布时间不晚于 releasedBefore）的全部版本
+// 版本号需按语义化规则比较，排序由调用方完成
+func (r *softwareReleaseRepository) FindPublished(ctx context.Context, softwareID string, channels []string, releasedBefore time.Time) ([]*models.SoftwareRelease, error) {
+	var releases []*models.SoftwareRelease
+	err := r.db.WithContext(ctx).
+		Where("software_id = ? AND channel IN ? AND is_published = ? AND released_at <= ?", softwareID, channels, true, releasedBefore).
+		Find(&releases).Error
+	return releases, err
+}
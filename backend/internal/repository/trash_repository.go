@@ -0,0 +1,346 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TrashRepository 回收站仓储接口
+type TrashRepository interface {
+	// ListTrash 分页查询回收站中的对象，按删除时间倒序
+	ListTrash(ctx context.Context, req *models.TrashListRequest) ([]*models.TrashItem, int64, error)
+	// GetExpiredTrash 查询删除时间早于 before 的对象，按删除时间正序分批返回
+	GetExpiredTrash(ctx context.Context, before time.Time, offset, limit int) ([]*models.TrashItem, error)
+	// GetTrashItem 获取回收站中的单个对象，不在回收站时返回 ErrTrashItemNotFound
+	GetTrashItem(ctx context.Context, entityType, id string) (*models.TrashItem, error)
+	// Restore 恢复回收站中的对象
+	Restore(ctx context.Context, entityType, id string) error
+	// Purge 彻底删除回收站中的对象，仍被其他数据引用时返回 ErrTrashItemReferenced
+	Purge(ctx context.Context, entityType, id string) error
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+// NewTrashRepository 创建回收站仓储
+func NewTrashRepository(db *gorm.DB) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+// trashSource 一类可进入回收站的对象，每个来源是回收站列表UNION ALL中的一个分支
+type trashSource struct {
+	entityType string
+	table      string
+	name       string                             // 名称列表达式
+	customerID string                             // 所属客户ID列表达式
+	where      string                             // 附加条件，为空表示无
+	parent     string                             // 恢复前要求未删除的客户ID列，为空表示无
+	references []customerReference                // 彻底删除前要求没有引用的数据（包含已软删除的记录）
+	cleanup    func(tx *gorm.DB, id string) error // 彻底删除时一并清理的附属数据，为空表示无
+	restore    func(tx *gorm.DB, id string) error // 恢复时一并恢复的附属数据（在对象本身恢复前调用），为空表示无
+}
+
+// trashSources 回收站的对象来源
+var trashSources = []trashSource{
+	{
+		entityType: models.TrashEntityCustomer,
+		table:      "customers",
+		name:       "customer_name",
+		customerID: "id",
+		// 被合并的来源客户不进入回收站，其数据已迁移到目标客户
+		where:  "NOT EXISTS (SELECT 1 FROM customer_merges m WHERE JSON_CONTAINS(m.source_customer_ids, JSON_QUOTE(customers.id)))",
+		parent: "parent_id",
+		references: []customerReference{
+			{key: "authorization_codes", table: "authorization_codes", column: "customer_id"},
+			{key: "licenses", table: "licenses", column: "customer_id"},
+			{key: "cu_users", table: "cu_users", column: "customer_id"},
+			{key: "cu_orders", table: "cu_orders", column: "customer_id"},
+			{key: "payments", table: "payments", column: "customer_id"},
+			{key: "invoices", table: "invoices", column: "customer_id"},
+			{key: "authorization_code_batches", table: "authorization_code_batches", column: "customer_id"},
+			{key: "vouchers", table: "vouchers", column: "redeemed_customer_id"},
+			{key: "subsidiaries", table: "customers", column: "parent_id"},
+		},
+		cleanup: func(tx *gorm.DB, id string) error {
+			if err := deleteEntityCustomFields(tx, models.CustomFieldEntityCustomer, []string{id}); err != nil {
+				return fmt.Errorf("failed to delete customer custom fields: %w", err)
+			}
+			if err := deleteSeatPool(tx, id); err != nil && !errors.Is(err, ErrSeatPoolNotFound) {
+				return err
+			}
+			return nil
+		},
+	},
+	{
+		entityType: models.TrashEntityAuthorizationCode,
+		table:      "authorization_codes",
+		name:       "code",
+		customerID: "customer_id",
+		parent:     "customer_id",
+		// 授权码下的许可证随授权码一并软删除，恢复时只恢复与授权码同时删除的许可证
+		restore: func(tx *gorm.DB, id string) error {
+			if err := tx.Exec(`UPDATE licenses l JOIN authorization_codes a ON a.id = l.authorization_code_id
+				SET l.deleted_at = NULL
+				WHERE a.id = ? AND l.deleted_at = a.deleted_at`, id).Error; err != nil {
+				return fmt.Errorf("failed to restore licenses: %w", err)
+			}
+			return nil
+		},
+		// 彻底删除时许可证随授权码一并删除（与 licenses 外键的 ON DELETE CASCADE 一致）
+		cleanup: func(tx *gorm.DB, id string) error {
+			var licenseIDs []string
+			if err := tx.Unscoped().Model(&models.License{}).
+				Where("authorization_code_id = ?", id).
+				Pluck("id", &licenseIDs).Error; err != nil {
+				return fmt.Errorf("failed to load licenses: %w", err)
+			}
+			if len(licenseIDs) > 0 {
+				if err := deleteEntityCustomFields(tx, models.CustomFieldEntityLicense, licenseIDs); err != nil {
+					return fmt.Errorf("failed to delete license custom fields: %w", err)
+				}
+				if err := tx.Unscoped().Where("id IN ?", licenseIDs).Delete(&models.License{}).Error; err != nil {
+					return fmt.Errorf("failed to delete licenses: %w", err)
+				}
+			}
+			if err := deleteEntityCustomFields(tx, models.CustomFieldEntityAuthorizationCode, []string{id}); err != nil {
+				return fmt.Errorf("failed to delete authorization code custom fields: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		entityType: models.TrashEntityPackage,
+		table:      "packages",
+		name:       "name",
+		customerID: "CAST(NULL AS CHAR)",
+		references: []customerReference{
+			{key: "cu_orders", table: "cu_orders", column: "package_id"},
+		},
+	},
+	{
+		entityType: models.TrashEntityLead,
+		table:      "leads",
+		name:       "company_name",
+		customerID: "customer_id",
+		cleanup: func(tx *gorm.DB, id string) error {
+			if err := tx.Where("lead_id = ?", id).Delete(&models.LeadActivity{}).Error; err != nil {
+				return fmt.Errorf("failed to delete lead activities: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		entityType: models.TrashEntityCuUser,
+		table:      "cu_users",
+		name:       "CONCAT(phone_country_code, ' ', phone)",
+		customerID: "customer_id",
+		parent:     "customer_id",
+		references: []customerReference{
+			{key: "cu_orders", table: "cu_orders", column: "cu_user_id"},
+			{key: "payments", table: "payments", column: "cu_user_id"},
+			{key: "invoices", table: "invoices", column: "cu_user_id"},
+		},
+	},
+}
+
+// findTrashSource 按对象类型查找回收站来源
+func findTrashSource(entityType string) (*trashSource, bool) {
+	for i := range trashSources {
+		if trashSources[i].entityType == entityType {
+			return &trashSources[i], true
+		}
+	}
+	return nil, false
+}
+
+// query 回收站列表中该来源的查询语句，列依次为 entity_type, entity_id, name, customer_id, deleted_at
+func (s *trashSource) query() string {
+	query := fmt.Sprintf(`
+		SELECT '%s' AS entity_type, id AS entity_id, %s AS name, %s AS customer_id, deleted_at
+		FROM %s WHERE deleted_at IS NOT NULL`, s.entityType, s.name, s.customerID, s.table)
+	if s.where != "" {
+		query += " AND " + s.where
+	}
+	return query
+}
+
+// trashUnion 拼接所选对象类型的回收站来源，entityType为空表示全部
+func trashUnion(entityType string) string {
+	var parts []string
+	for i := range trashSources {
+		if entityType != "" && trashSources[i].entityType != entityType {
+			continue
+		}
+		parts = append(parts, trashSources[i].query())
+	}
+	return strings.Join(parts, "\n\t\tUNION ALL")
+}
+
+// ListTrash 分页查询回收站中的对象，按删除时间倒序
+func (r *trashRepository) ListTrash(ctx context.Context, req *models.TrashListRequest) ([]*models.TrashItem, int64, error) {
+	union := trashUnion(req.EntityType)
+	if union == "" {
+		return []*models.TrashItem{}, 0, nil
+	}
+
+	from := "FROM (" + union + "\n\t) t"
+	var args []interface{}
+	if search := strings.TrimSpace(req.Search); search != "" {
+		from += " WHERE name LIKE ?"
+		args = append(args, "%"+search+"%")
+	}
+
+	db := r.db.WithContext(ctx)
+	var total int64
+	if err := db.Raw("SELECT COUNT(*) "+from, args...).Scan(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count trash: %w", err)
+	}
+
+	items := []*models.TrashItem{}
+	if total == 0 {
+		return items, 0, nil
+	}
+	offset := (req.Page - 1) * req.PageSize
+	pageArgs := append(args, req.PageSize, offset)
+	if err := db.Raw("SELECT * "+from+" ORDER BY deleted_at DESC, entity_type, entity_id LIMIT ? OFFSET ?", pageArgs...).
+		Scan(&items).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to query trash: %w", err)
+	}
+	return items, total, nil
+}
+
+// GetExpiredTrash 查询删除时间早于 before 的对象，按删除时间正序分批返回
+func (r *trashRepository) GetExpiredTrash(ctx context.Context, before time.Time, offset, limit int) ([]*models.TrashItem, error) {
+	items := []*models.TrashItem{}
+	err := r.db.WithContext(ctx).
+		Raw("SELECT * FROM ("+trashUnion("")+"\n\t) t WHERE deleted_at < ? ORDER BY deleted_at, entity_type, entity_id LIMIT ? OFFSET ?", before, limit, offset).
+		Scan(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired trash: %w", err)
+	}
+	return items, nil
+}
+
+// GetTrashItem 获取回收站中的单个对象，不在回收站时返回 ErrTrashItemNotFound
+func (r *trashRepository) GetTrashItem(ctx context.Context, entityType, id string) (*models.TrashItem, error) {
+	source, ok := findTrashSource(entityType)
+	if !ok {
+		return nil, ErrTrashItemNotFound
+	}
+	return getTrashItem(r.db.WithContext(ctx), source, id)
+}
+
+// getTrashItem 查询来源中的单个已删除对象
+func getTrashItem(db *gorm.DB, source *trashSource, id string) (*models.TrashItem, error) {
+	var items []*models.TrashItem
+	if err := db.Raw(source.query()+" AND id = ?", id).Scan(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to query trash item: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, ErrTrashItemNotFound
+	}
+	return items[0], nil
+}
+
+// Restore 恢复回收站中的对象
+// 所属客户（或上级客户）仍在回收站时返回 ErrTrashParentDeleted，客户端用户的手机号已被其他用户使用时返回 ErrTrashCuUserPhoneInUse
+func (r *trashRepository) Restore(ctx context.Context, entityType, id string) error {
+	source, ok := findTrashSource(entityType)
+	if !ok {
+		return ErrTrashItemNotFound
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := getTrashItem(tx, source, id); err != nil {
+			return err
+		}
+
+		if source.parent != "" {
+			var orphaned int64
+			if err := tx.Table(source.table+" t").
+				Where("t.id = ? AND t."+source.parent+" IS NOT NULL", id).
+				Where("NOT EXISTS (SELECT 1 FROM customers c WHERE c.id = t." + source.parent + " AND c.deleted_at IS NULL)").
+				Count(&orphaned).Error; err != nil {
+				return fmt.Errorf("failed to check parent customer: %w", err)
+			}
+			if orphaned > 0 {
+				return ErrTrashParentDeleted
+			}
+		}
+
+		if source.entityType == models.TrashEntityCuUser {
+			var conflicts int64
+			if err := tx.Table("cu_users u").
+				Joins("JOIN cu_users d ON d.phone = u.phone AND d.phone_country_code = u.phone_country_code").
+				Where("d.id = ? AND u.id <> d.id AND u.deleted_at IS NULL", id).
+				Count(&conflicts).Error; err != nil {
+				return fmt.Errorf("failed to check client user phone: %w", err)
+			}
+			if conflicts > 0 {
+				return ErrTrashCuUserPhoneInUse
+			}
+		}
+
+		if source.restore != nil {
+			if err := source.restore(tx, id); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Exec("UPDATE "+source.table+" SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to restore %s: %w", source.entityType, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrTrashItemNotFound
+		}
+		return nil
+	})
+}
+
+// Purge 彻底删除回收站中的对象，仍被其他数据引用时返回 ErrTrashItemReferenced
+func (r *trashRepository) Purge(ctx context.Context, entityType, id string) error {
+	source, ok := findTrashSource(entityType)
+	if !ok {
+		return ErrTrashItemNotFound
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := getTrashItem(tx, source, id); err != nil {
+			return err
+		}
+
+		for _, ref := range source.references {
+			var count int64
+			if err := ref.scope(tx, []string{id}).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to count %s: %w", ref.key, err)
+			}
+			if count > 0 {
+				return ErrTrashItemReferenced
+			}
+		}
+
+		if source.cleanup != nil {
+			if err := source.cleanup(tx, id); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Exec("DELETE FROM "+source.table+" WHERE id = ? AND deleted_at IS NOT NULL", id)
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge %s: %w", source.entityType, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrTrashItemNotFound
+		}
+		return nil
+	})
+}
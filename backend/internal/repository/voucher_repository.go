@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"license-manager/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// VoucherRepository 兑换券仓储接口
+type VoucherRepository interface {
+	CreateBatch(ctx context.Context, vouchers []*models.Voucher) error
+	GetByCode(ctx context.Context, code string) (*models.Voucher, error)
+	GetList(ctx context.Context, req *models.VoucherListRequest, now time.Time) ([]*models.Voucher, int64, error)
+	Redeem(ctx context.Context, voucher *models.Voucher, authCode *models.AuthorizationCode, order *models.CuOrder) error
+	GetRedemptionSummary(ctx context.Context, req *models.VoucherRedemptionReportRequest, now time.Time) (*models.VoucherRedemptionSummary, error)
+	GetRedemptionList(ctx context.Context, req *models.VoucherRedemptionReportRequest) ([]models.VoucherRedemptionItem, int64, error)
+}
+
+type voucherRepository struct {
+	db *gorm.DB
+}
+
+// NewVoucherRepository 创建兑换券仓储
+func NewVoucherRepository(db *gorm.DB) VoucherRepository {
+	return &voucherRepository{db: db}
+}
+
+// CreateBatch 批量创建兑换券
+func (r *voucherRepository) CreateBatch(ctx context.Context, vouchers []*models.Voucher) error {
+	return r.db.WithContext(ctx).CreateInBatches(vouchers, 200).Error
+}
+
+// GetByCode 根据兑换码获取兑换券
+func (r *voucherRepository) GetByCode(ctx context.Context, code string) (*models.Voucher, error) {
+	var voucher models.Voucher
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&voucher).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVoucherNotFound
+		}
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+// GetList 查询兑换券列表，expired 状态按兑换截止时间计算
+func (r *voucherRepository) GetList(ctx context.Context, req *models.VoucherListRequest, now time.Time) ([]*models.Voucher, int64, error) {
+	var vouchers []*models.Voucher
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Voucher{})
+
+	if req.Code != "" {
+		query = query.Where("code = ?", req.Code)
+	}
+	if req.Label != "" {
+		query = query.Where("label LIKE ?", "%"+req.Label+"%")
+	}
+	if req.PackageID != "" {
+		query = query.Where("package_id = ?", req.PackageID)
+	}
+	switch req.Status {
+	case string(models.VoucherStatusUnused):
+		query = query.Where("status = ? AND (expires_at IS NULL OR expires_at >= ?)", models.VoucherStatusUnused, now)
+	case string(models.VoucherStatusExpired):
+		query = query.Where("status = ? AND expires_at < ?", models.VoucherStatusUnused, now)
+	case string(models.VoucherStatusRedeemed):
+		query = query.Where("status = ?", models.VoucherStatusRedeemed)
+	}
+
+	// 获取总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	if err := query.Order("created_at DESC, id ASC").Offset(offset).Limit(req.PageSize).Find(&vouchers).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return vouchers, total, nil
+}
+
+// Redeem 在事务中核销兑换券并写入授权码和订单（订单可为空）
+// 仅当兑换券仍未兑换时才核销，保证一次性使用
+func (r *voucherRepository) Redeem(ctx context.Context, voucher *models.Voucher, authCode *models.AuthorizationCode, order *models.CuOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Voucher{}).
+			Where("id = ? AND status = ?", voucher.ID, models.VoucherStatusUnused).
+			Updates(map[string]interface{}{
+				"status":                voucher.Status,
+				"redeemed_at":           voucher.RedeemedAt,
+				"redeemed_customer_id":  voucher.RedeemedCustomerID,
+				"redeemed_cu_user_id":   voucher.RedeemedCuUserID,
+				"authorization_code_id": voucher.AuthorizationCodeID,
+				"order_id":              voucher.OrderID,
+				"updated_at":            time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVoucherAlreadyRedeemed
+		}
+
+		if err := tx.Create(authCode).Error; err != nil {
+			return err
+		}
+		if order != nil {
+			if err := tx.Create(order).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// applyRedemptionFilters 兑换报表的公共筛选条件（标签、套餐）
+func applyRedemptionFilters(query *gorm.DB, req *models.VoucherRedemptionReportRequest) *gorm.DB {
+	if req.Label != "" {
+		query = query.Where("vouchers.label LIKE ?", "%"+req.Label+"%")
+	}
+	if req.PackageID != "" {
+		query = query.Where("vouchers.package_id = ?", req.PackageID)
+	}
+	return query
+}
+
+// GetRedemptionSummary 统计兑换券使用情况
+func (r *voucherRepository) GetRedemptionSummary(ctx context.Context, req *models.VoucherRedemptionReportRequest, now time.Time) (*models.VoucherRedemptionSummary, error) {
+	var summary models.VoucherRedemptionSummary
+
+	query := applyRedemptionFilters(r.db.WithContext(ctx).Model(&models.Voucher{}), req)
+	err := query.Select(`COUNT(*) AS total,
+			COALESCE(SUM(CASE WHEN vouchers.status = 'unused' AND (vouchers.expires_at IS NULL OR vouchers.expires_at >= ?) THEN 1 ELSE 0 END), 0) AS unused,
+			COALESCE(SUM(CASE WHEN vouchers.status = 'redeemed' THEN 1 ELSE 0 END), 0) AS redeemed,
+			COALESCE(SUM(CASE WHEN vouchers.status = 'unused' AND vouchers.expires_at < ? THEN 1 ELSE 0 END), 0) AS expired,
+			COALESCE(SUM(CASE WHEN vouchers.status = 'redeemed' THEN vouchers.license_count ELSE 0 END), 0) AS redeemed_seats`, now, now).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// GetRedemptionList 查询兑换记录（关联客户名称与兑换用户手机号）
+func (r *voucherRepository) GetRedemptionList(ctx context.Context, req *models.VoucherRedemptionReportRequest) ([]models.VoucherRedemptionItem, int64, error) {
+	var total int64
+
+	query := r.db.WithContext(ctx).Table("vouchers").
+		Joins("LEFT JOIN customers ON customers.id = vouchers.redeemed_customer_id").
+		Joins("LEFT JOIN cu_users ON cu_users.id = vouchers.redeemed_cu_user_id").
+		Where("vouchers.status = ?", models.VoucherStatusRedeemed)
+	query = applyRedemptionFilters(query, req)
+
+	if req.CustomerID != "" {
+		query = query.Where("vouchers.redeemed_customer_id = ?", req.CustomerID)
+	}
+	if req.StartDate != "" {
+		if startTime, err := time.Parse("2006-01-02", req.StartDate); err == nil {
+			query = query.Where("vouchers.redeemed_at >= ?", startTime)
+		}
+	}
+	if req.EndDate != "" {
+		if endTime, err := time.Parse("2006-01-02", req.EndDate); err == nil {
+			// 结束时间加一天，以包含当天的所有时间
+			query = query.Where("vouchers.redeemed_at < ?", endTime.AddDate(0, 0, 1))
+		}
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var results []struct {
+		ID                  string
+		Code                string
+		Label               string
+		PackageID           string
+		PackageName         string
+		LicenseCount        int
+		RedeemedCustomerID  *string
+		CustomerName        *string
+		RedeemedCuUserID    *string
+		CuUserPhone         *string
+		AuthorizationCodeID *string
+		OrderID             *string
+		RedeemedAt          *time.Time
+	}
+
+	offset := (req.Page - 1) * req.PageSize
+	err := query.Select(`vouchers.id, vouchers.code, vouchers.label, vouchers.package_id, vouchers.package_name,
+			vouchers.license_count, vouchers.redeemed_customer_id, customers.customer_name,
+			vouchers.redeemed_cu_user_id, cu_users.phone AS cu_user_phone,
+			vouchers.authorization_code_id, vouchers.order_id, vouchers.redeemed_at`).
+		Order("vouchers.redeemed_at DESC").Offset(offset).Limit(req.PageSize).
+		Scan(&results).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]models.VoucherRedemptionItem, 0, len(results))
+	for _, result := range results {
+		item := models.VoucherRedemptionItem{
+			VoucherID:    result.ID,
+			Code:         result.Code,
+			Label:        result.Label,
+			PackageID:    result.PackageID,
+			PackageName:  result.PackageName,
+			LicenseCount: result.LicenseCount,
+			OrderID:      result.OrderID,
+		}
+		if result.RedeemedCustomerID != nil {
+			item.CustomerID = *result.RedeemedCustomerID
+		}
+		if result.CustomerName != nil {
+			item.CustomerName = *result.CustomerName
+		}
+		if result.RedeemedCuUserID != nil {
+			item.CuUserID = *result.RedeemedCuUserID
+		}
+		if result.CuUserPhone != nil {
+			item.CuUserPhone = *result.CuUserPhone
+		}
+		if result.AuthorizationCodeID != nil {
+			item.AuthorizationCodeID = *result.AuthorizationCodeID
+		}
+		if result.RedeemedAt != nil {
+			item.RedeemedAt = result.RedeemedAt.Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+
+	return items, total, nil
+}
@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// JobFunc 定时任务执行函数
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+}
+
+// Scheduler 进程内定时任务调度器
+// 每个任务在独立的goroutine中按固定间隔执行，同一任务不会并发执行
+type Scheduler struct {
+	logger *logrus.Logger
+	jobs   []*job
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	start  bool
+}
+
+// New 创建调度器
+func New(logger *logrus.Logger) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Register 注册定时任务，interval<=0 时忽略该任务
+// 调度器启动后注册的任务会立即开始调度
+func (s *Scheduler) Register(name string, interval time.Duration, run JobFunc) {
+	if interval <= 0 || run == nil {
+		s.logger.Warnf("定时任务 %s 未启用: 执行间隔无效", name)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j := &job{name: name, interval: interval, run: run}
+	s.jobs = append(s.jobs, j)
+	if s.start {
+		s.launch(j)
+	}
+}
+
+// Start 启动所有已注册的任务
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.start {
+		return
+	}
+	s.start = true
+	for _, j := range s.jobs {
+		s.launch(j)
+	}
+}
+
+// Stop 停止调度并等待正在执行的任务结束
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) launch(j *job) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		s.logger.Infof("定时任务 %s 已启动，执行间隔 %s", j.name, j.interval)
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				s.execute(j)
+			}
+		}
+	}()
+}
+
+// execute 执行一次任务，捕获panic避免影响其他任务和主进程
+func (s *Scheduler) execute(j *job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Errorf("定时任务 %s 发生panic: %v", j.name, r)
+		}
+	}()
+
+	startedAt := time.Now()
+	if err := j.run(s.ctx); err != nil {
+		s.logger.WithError(err).Errorf("定时任务 %s 执行失败", j.name)
+		return
+	}
+	s.logger.Debugf("定时任务 %s 执行完成，耗时 %s", j.name, time.Since(startedAt))
+}
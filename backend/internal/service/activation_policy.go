@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"license-manager/internal/models"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errDailyActivationLimitExceeded 今日新增激活数已达上限
+var errDailyActivationLimitExceeded = errors.New("daily activation limit exceeded")
+
+// activationViolationErrorCodes 违规类型对应的错误码
+var activationViolationErrorCodes = map[string]string{
+	models.ActivationViolationIP:         "300012",
+	models.ActivationViolationCountry:    "300013",
+	models.ActivationViolationTimeWindow: "300014",
+	models.ActivationViolationDailyLimit: "300015",
+}
+
+// CountryResolver 根据IP解析国家/地区代码，未知时返回空字符串
+type CountryResolver interface {
+	LookupCountry(ip string) string
+}
+
+// activationPolicyLocation 策略使用的时区，未设置或无效时使用服务器时区
+func activationPolicyLocation(policy *models.ActivationPolicy) *time.Location {
+	if policy == nil || policy.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(policy.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// activationPolicyDayStart 策略时区下当天零点，用于统计每日新增激活数
+func activationPolicyDayStart(policy *models.ActivationPolicy, now time.Time) time.Time {
+	local := now.In(activationPolicyLocation(policy))
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+}
+
+// checkActivationPolicy 校验客户端IP、国家/地区和时间窗口，返回违规类型，通过时返回空字符串
+// 设置了允许的国家/地区但无法识别客户端所在地时视为不允许
+func checkActivationPolicy(policy *models.ActivationPolicy, clientIP, country string, now time.Time) string {
+	if policy.IsEmpty() {
+		return ""
+	}
+
+	if len(policy.AllowedCIDRs) > 0 && !ipAllowed(policy.AllowedCIDRs, clientIP) {
+		return models.ActivationViolationIP
+	}
+
+	if len(policy.AllowedCountries) > 0 {
+		allowed := false
+		for _, code := range policy.AllowedCountries {
+			if country != "" && strings.EqualFold(code, country) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return models.ActivationViolationCountry
+		}
+	}
+
+	if len(policy.TimeWindows) > 0 && !withinTimeWindows(policy.TimeWindows, now.In(activationPolicyLocation(policy))) {
+		return models.ActivationViolationTimeWindow
+	}
+
+	return ""
+}
+
+func ipAllowed(cidrs []string, clientIP string) bool {
+	ip := net.ParseIP(strings.TrimSpace(clientIP))
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range cidrs {
+		network, err := parseAllowedCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAllowedCIDR 解析允许的网段，单个IP视为 /32 或 /128
+func parseAllowedCIDR(cidr string) (*net.IPNet, error) {
+	cidr = strings.TrimSpace(cidr)
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip: %s", cidr)
+		}
+		if ip.To4() != nil {
+			cidr += "/32"
+		} else {
+			cidr += "/128"
+		}
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	return network, err
+}
+
+// withinTimeWindows 判断本地时间是否落在任一时间窗口内
+// 跨越午夜的窗口（结束早于开始），午夜后的部分按前一天的星期判断
+func withinTimeWindows(windows []models.ActivationTimeWindow, local time.Time) bool {
+	minute := local.Hour()*60 + local.Minute()
+	today := int(local.Weekday())
+	yesterday := (today + 6) % 7
+
+	for _, window := range windows {
+		start, err := parseClockMinutes(window.Start)
+		if err != nil {
+			continue
+		}
+		end, err := parseClockMinutes(window.End)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case start == end:
+			// 全天
+			if weekdayAllowed(window.Weekdays, today) {
+				return true
+			}
+		case start < end:
+			if minute >= start && minute < end && weekdayAllowed(window.Weekdays, today) {
+				return true
+			}
+		default:
+			if minute >= start && weekdayAllowed(window.Weekdays, today) {
+				return true
+			}
+			if minute < end && weekdayAllowed(window.Weekdays, yesterday) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func weekdayAllowed(weekdays []int, day int) bool {
+	if len(weekdays) == 0 {
+		return true
+	}
+	for _, d := range weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClockMinutes 解析 HH:MM 为当天分钟数
+func parseClockMinutes(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// normalizeActivationPolicy 校验并规范化使用限制策略，空策略返回 nil 表示不限制
+func normalizeActivationPolicy(policy *models.ActivationPolicy) (*models.ActivationPolicy, error) {
+	if policy.IsEmpty() {
+		return nil, nil
+	}
+
+	normalized := &models.ActivationPolicy{
+		Timezone:            strings.TrimSpace(policy.Timezone),
+		MaxDailyActivations: policy.MaxDailyActivations,
+	}
+
+	for _, cidr := range policy.AllowedCIDRs {
+		if _, err := parseAllowedCIDR(cidr); err != nil {
+			return nil, fmt.Errorf("invalid allowed_cidrs entry: %s", cidr)
+		}
+		normalized.AllowedCIDRs = append(normalized.AllowedCIDRs, strings.TrimSpace(cidr))
+	}
+
+	for _, code := range policy.AllowedCountries {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if len(code) != 2 {
+			return nil, fmt.Errorf("invalid allowed_countries entry: %s", code)
+		}
+		normalized.AllowedCountries = append(normalized.AllowedCountries, code)
+	}
+
+	for _, window := range policy.TimeWindows {
+		if _, err := parseClockMinutes(window.Start); err != nil {
+			return nil, fmt.Errorf("invalid time window start: %s", window.Start)
+		}
+		if _, err := parseClockMinutes(window.End); err != nil {
+			return nil, fmt.Errorf("invalid time window end: %s", window.End)
+		}
+		for _, day := range window.Weekdays {
+			if day < 0 || day > 6 {
+				return nil, fmt.Errorf("invalid time window weekday: %d", day)
+			}
+		}
+		normalized.TimeWindows = append(normalized.TimeWindows, models.ActivationTimeWindow{
+			Weekdays: window.Weekdays,
+			Start:    strings.TrimSpace(window.Start),
+			End:      strings.TrimSpace(window.End),
+		})
+	}
+
+	if normalized.Timezone != "" {
+		if _, err := time.LoadLocation(normalized.Timezone); err != nil {
+			return nil, fmt.Errorf("invalid timezone: %s", normalized.Timezone)
+		}
+	}
+
+	if normalized.MaxDailyActivations != nil && *normalized.MaxDailyActivations < 1 {
+		return nil, fmt.Errorf("max_daily_activations must be at least 1")
+	}
+
+	return normalized, nil
+}
+
+// marshalActivationPolicy 校验并序列化使用限制策略，空策略返回 nil
+func marshalActivationPolicy(policy *models.ActivationPolicy) (models.JSON, error) {
+	normalized, err := normalizeActivationPolicy(policy)
+	if err != nil || normalized == nil {
+		return nil, err
+	}
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, err
+	}
+	return models.JSON(data), nil
+}
+
+// checkDailyActivationLimit 在激活事务中统计策略时区当天的新增激活数
+// 统计前锁定授权码，同一授权码的并发激活串行执行，避免同时通过检查后超出每日上限
+func checkDailyActivationLimit(tx *gorm.DB, authCodeID string, policy *models.ActivationPolicy, now time.Time) error {
+	if policy == nil || policy.MaxDailyActivations == nil {
+		return nil
+	}
+	var locked models.AuthorizationCode
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").Where("id = ?", authCodeID).First(&locked).Error; err != nil {
+		return err
+	}
+	var count int64
+	if err := tx.Model(&models.License{}).
+		Where("authorization_code_id = ? AND activated_at >= ?", authCodeID, activationPolicyDayStart(policy, now)).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count >= int64(*policy.MaxDailyActivations) {
+		return errDailyActivationLimitExceeded
+	}
+	return nil
+}
+
+// recordActivationViolation 记录违规事件，失败只记录日志不影响响应
+func (s *licenseService) recordActivationViolation(ctx context.Context, authCode *models.AuthorizationCode, licenseID *string, stage, violationType, clientIP, country, fingerprint string) {
+	if s.violationRepo == nil {
+		return
+	}
+	violation := &models.ActivationViolation{
+		AuthorizationCodeID: authCode.ID,
+		CustomerID:          authCode.CustomerID,
+		LicenseID:           licenseID,
+		Stage:               stage,
+		ViolationType:       violationType,
+		ClientIP:            clientIP,
+		HardwareFingerprint: fingerprint,
+	}
+	if country != "" {
+		violation.Country = &country
+	}
+	if err := s.violationRepo.Create(ctx, violation); err != nil {
+		s.logger.WithError(err).WithField("authorization_code_id", authCode.ID).Error("记录使用限制违规事件失败")
+	}
+}
+
+// lookupCountry 查询客户端所在国家/地区，未加载GeoIP库时返回空字符串
+func (s *licenseService) lookupCountry(clientIP string) string {
+	if s.geoIP == nil {
+		return ""
+	}
+	return s.geoIP.LookupCountry(clientIP)
+}
+
+// GetActivationViolationList 查询使用限制违规事件
+func (s *licenseService) GetActivationViolationList(ctx context.Context, req *models.ActivationViolationListRequest) (*models.ActivationViolationListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	violations, total, err := s.violationRepo.GetList(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	for _, violation := range violations {
+		violation.ViolationTypeDisplay = i18n.GetEnumMessage("activation_violation_type", violation.ViolationType, lang)
+	}
+
+	return &models.ActivationViolationListResponse{
+		List:       violations,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+)
+
+func TestCheckActivationPolicy(t *testing.T) {
+	max := 3
+	policy := &models.ActivationPolicy{
+		AllowedCIDRs:     []string{"10.0.0.0/8", "192.168.1.5"},
+		AllowedCountries: []string{"CN"},
+		TimeWindows: []models.ActivationTimeWindow{
+			{Weekdays: []int{1, 2, 3, 4, 5}, Start: "09:00", End: "18:00"},
+			{Weekdays: []int{5}, Start: "22:00", End: "02:00"},
+		},
+		Timezone:            "Asia/Shanghai",
+		MaxDailyActivations: &max,
+	}
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	// 2026-10-19 是周一
+	monday10 := time.Date(2026, 10, 19, 10, 0, 0, 0, loc)
+	saturday1 := time.Date(2026, 10, 24, 1, 0, 0, 0, loc)
+	saturday3 := time.Date(2026, 10, 24, 3, 0, 0, 0, loc)
+
+	cases := []struct {
+		name    string
+		ip      string
+		country string
+		now     time.Time
+		want    string
+	}{
+		{"allowed", "10.1.2.3", "CN", monday10, ""},
+		{"single ip", "192.168.1.5", "cn", monday10, ""},
+		{"ip not allowed", "192.168.1.6", "CN", monday10, models.ActivationViolationIP},
+		{"invalid ip", "", "CN", monday10, models.ActivationViolationIP},
+		{"country not allowed", "10.1.2.3", "US", monday10, models.ActivationViolationCountry},
+		{"country unknown", "10.1.2.3", "", monday10, models.ActivationViolationCountry},
+		{"other timezone", "10.1.2.3", "CN", time.Date(2026, 10, 19, 2, 30, 0, 0, time.UTC), ""},
+		{"outside window", "10.1.2.3", "CN", time.Date(2026, 10, 19, 19, 0, 0, 0, loc), models.ActivationViolationTimeWindow},
+		{"after midnight of friday window", "10.1.2.3", "CN", saturday1, ""},
+		{"after cross midnight window", "10.1.2.3", "CN", saturday3, models.ActivationViolationTimeWindow},
+	}
+	for _, tc := range cases {
+		if got := checkActivationPolicy(policy, tc.ip, tc.country, tc.now); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	if got := checkActivationPolicy(nil, "1.1.1.1", "", monday10); got != "" {
+		t.Errorf("nil policy: got %q", got)
+	}
+}
+
+func TestNormalizeActivationPolicy(t *testing.T) {
+	normalized, err := normalizeActivationPolicy(&models.ActivationPolicy{
+		AllowedCIDRs:     []string{" 10.0.0.0/8 "},
+		AllowedCountries: []string{" cn"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normalized.AllowedCIDRs[0] != "10.0.0.0/8" || normalized.AllowedCountries[0] != "CN" {
+		t.Fatalf("unexpected normalized policy: %+v", normalized)
+	}
+
+	if normalized, err := normalizeActivationPolicy(&models.ActivationPolicy{}); err != nil || normalized != nil {
+		t.Fatalf("empty policy should normalize to nil, got %+v, %v", normalized, err)
+	}
+
+	zero := 0
+	invalid := []*models.ActivationPolicy{
+		{AllowedCIDRs: []string{"10.0.0.0/33"}},
+		{AllowedCountries: []string{"CHN"}},
+		{TimeWindows: []models.ActivationTimeWindow{{Start: "25:00", End: "18:00"}}},
+		{TimeWindows: []models.ActivationTimeWindow{{Weekdays: []int{7}, Start: "09:00", End: "18:00"}}},
+		{TimeWindows: []models.ActivationTimeWindow{{Start: "09:00", End: "18:00"}}, Timezone: "Mars/Base"},
+		{MaxDailyActivations: &zero},
+	}
+	for i, policy := range invalid {
+		if _, err := normalizeActivationPolicy(policy); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
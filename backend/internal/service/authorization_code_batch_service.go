@@ -0,0 +1,573 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+	"license-manager/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// batchInsertSize 批量生成时每个事务写入的授权码数量
+const batchInsertSize = 100
+
+// AuthorizationCodeBatchService 授权码批量生成服务接口
+type AuthorizationCodeBatchService interface {
+	// CreateBatch 创建批次并在后台生成授权码，立即返回批次信息
+	CreateBatch(ctx context.Context, operatorID string, req *models.AuthorizationCodeBatchCreateRequest) (*models.AuthorizationCodeBatch, error)
+	GetBatch(ctx context.Context, id string) (*models.AuthorizationCodeBatch, error)
+	GetBatchList(ctx context.Context, req *models.AuthorizationCodeBatchListRequest) (*models.AuthorizationCodeBatchListResponse, error)
+	// ExportBatch 导出批次授权码，返回文件内容、文件名和Content-Type
+	ExportBatch(ctx context.Context, id, format string) ([]byte, string, string, error)
+	LockBatch(ctx context.Context, id, operatorID string, req *models.AuthorizationCodeLockRequest) (*models.AuthorizationCodeBatchOperationResponse, error)
+	RevokeBatch(ctx context.Context, id, operatorID string, req *models.AuthorizationCodeBatchRevokeRequest) (*models.AuthorizationCodeBatchOperationResponse, error)
+	// ResumeUnfinishedBatches 续跑服务重启前未完成的批次，其他实例执行中的批次等待其租约过期后接管
+	ResumeUnfinishedBatches()
+	// Stop 停止后台生成并等待退出，未完成的批次由续跑继续
+	Stop()
+}
+
+type authorizationCodeBatchService struct {
+	batchRepo    repository.AuthorizationCodeBatchRepository
+	customerRepo repository.CustomerRepository
+	customFields repository.CustomFieldRepository
+	entitlements EntitlementInvalidator
+	logger       *logrus.Logger
+
+	// 后台生成任务的上下文，Stop 时取消
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAuthorizationCodeBatchService 创建授权码批量生成服务
+func NewAuthorizationCodeBatchService(batchRepo repository.AuthorizationCodeBatchRepository, customerRepo repository.CustomerRepository, customFields repository.CustomFieldRepository, entitlements EntitlementInvalidator, logger *logrus.Logger) AuthorizationCodeBatchService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &authorizationCodeBatchService{
+		batchRepo:    batchRepo,
+		customerRepo: customerRepo,
+		customFields: customFields,
+		entitlements: entitlements,
+		logger:       logger,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// Stop 取消后台生成任务并等待退出
+func (s *authorizationCodeBatchService) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *authorizationCodeBatchService) CreateBatch(ctx context.Context, operatorID string, req *models.AuthorizationCodeBatchCreateRequest) (*models.AuthorizationCodeBatch, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if req == nil {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+
+	// 验证客户是否存在并检查状态
+	customer, err := s.customerRepo.GetCustomerByID(ctx, req.CustomerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomerNotFound) {
+			return nil, i18n.NewI18nError("200001", lang) // 客户不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if customer.Status == "disabled" {
+		return nil, i18n.NewI18nError("200007", lang) // 客户已停用，无法创建授权
+	}
+
+	if operatorID == "" {
+		return nil, i18n.NewI18nError("100004", lang) // 缺少认证信息
+	}
+
+	// 批次内授权码使用相同的自定义字段和标签，快照中保存规范化后的值
+	customFields, tags, err := prepareEntityCustomFields(ctx, s.customFields, models.CustomFieldEntityAuthorizationCode, req.CustomFields, req.Tags, true)
+	if err != nil {
+		return nil, err
+	}
+	req.CustomFields = customFields
+	req.Tags = tags
+
+	// 保存授权码配置快照，后台任务与重启续跑都以快照为准
+	settings, err := json.Marshal(req.AuthorizationCodeCreateRequest)
+	if err != nil {
+		return nil, i18n.NewI18nError("300010", lang) // 配置参数错误
+	}
+
+	// 批次内授权码共享同一有效期
+	startDate, endDate := authorizationCodeValidity(time.Now(), req.ValidityDays)
+
+	batch := &models.AuthorizationCodeBatch{
+		Label:      strings.TrimSpace(req.Label),
+		CustomerID: req.CustomerID,
+		Quantity:   req.Quantity,
+		Status:     string(models.AuthorizationCodeBatchStatusPending),
+		Settings:   models.JSON(settings),
+		StartDate:  startDate,
+		EndDate:    endDate,
+		CreatedBy:  operatorID,
+	}
+	if err := s.batchRepo.Create(ctx, batch); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	// 后台任务使用独立副本，避免与响应数据共享；请求结束不影响后台任务，服务停止时取消
+	background := *batch
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runBatch(s.ctx, &background)
+	}()
+
+	batch.CustomerName = customer.CustomerName
+	batch.StatusDisplay = i18n.GetEnumMessage("authorization_code_batch_status", batch.Status, lang)
+	return batch, nil
+}
+
+// runBatch 领取批次后在后台生成授权码，从已生成数量处继续，失败时记录原因
+// 批次已由其他实例领取时直接返回；服务停止或租约被接管时中止，不标记失败
+func (s *authorizationCodeBatchService) runBatch(ctx context.Context, batch *models.AuthorizationCodeBatch) {
+	log := s.logger.WithField("batch_id", batch.ID)
+
+	owner := uuid.New().String()
+	claimed, err := s.batchRepo.Claim(ctx, batch.ID, owner, backgroundJobLease)
+	if err != nil {
+		log.WithError(err).Error("领取授权码批次失败")
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	// 以最新的已生成数量续跑（领取前可能由其他实例生成了一部分）
+	latest, err := s.batchRepo.GetByID(ctx, batch.ID)
+	if err != nil {
+		log.WithError(err).Error("查询授权码批次失败")
+		return
+	}
+	batch = latest
+	log.Infof("开始生成授权码批次，已生成 %d/%d", batch.GeneratedCount, batch.Quantity)
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("授权码批次生成异常: %v", r)
+			s.failBatch(ctx, batch.ID, owner, fmt.Sprintf("panic: %v", r))
+		}
+	}()
+
+	var req models.AuthorizationCodeCreateRequest
+	if err := json.Unmarshal(batch.Settings, &req); err != nil {
+		s.failBatch(ctx, batch.ID, owner, err.Error())
+		return
+	}
+
+	codeFormat, codeParams := authorizationCodeGenerationParams(&req, batch.EndDate)
+	generator, err := newConfiguredCodeGenerator(codeFormat)
+	if err != nil {
+		s.failBatch(ctx, batch.ID, owner, err.Error())
+		return
+	}
+
+	batchID := batch.ID
+	for generated := batch.GeneratedCount; generated < batch.Quantity; {
+		if ctx.Err() != nil {
+			log.Infof("服务停止，授权码批次已生成 %d/%d，待续跑", generated, batch.Quantity)
+			return
+		}
+		size := batch.Quantity - generated
+		if size > batchInsertSize {
+			size = batchInsertSize
+		}
+
+		codes := make([]*models.AuthorizationCode, 0, size)
+		for i := 0; i < size; i++ {
+			code, err := generator.Generate(codeParams)
+			if err != nil {
+				s.failBatch(ctx, batch.ID, owner, err.Error())
+				return
+			}
+			entity, err := newAuthorizationCodeEntity(&req, code, codeFormat, batch.CreatedBy, batch.StartDate, batch.EndDate)
+			if err != nil {
+				s.failBatch(ctx, batch.ID, owner, err.Error())
+				return
+			}
+			entity.BatchID = &batchID
+			codes = append(codes, entity)
+		}
+
+		if err := s.batchRepo.CreateCodes(ctx, batch.ID, owner, backgroundJobLease, codes); err != nil {
+			s.failBatch(ctx, batch.ID, owner, err.Error())
+			return
+		}
+		if len(req.CustomFields) > 0 || len(req.Tags) > 0 {
+			ids := make([]string, 0, len(codes))
+			for _, code := range codes {
+				ids = append(ids, code.ID)
+			}
+			if err := s.customFields.SaveEntityCustomFields(ctx, models.CustomFieldEntityAuthorizationCode, ids, req.CustomFields, req.Tags); err != nil {
+				s.failBatch(ctx, batch.ID, owner, err.Error())
+				return
+			}
+		}
+		generated += size
+	}
+
+	now := time.Now()
+	if err := s.batchRepo.UpdateLeasedFields(ctx, batch.ID, owner, backgroundJobLease, map[string]interface{}{
+		"status":        models.AuthorizationCodeBatchStatusCompleted,
+		"error_message": nil,
+		"completed_at":  now,
+	}); err != nil {
+		log.WithError(err).Error("更新授权码批次状态失败")
+		return
+	}
+
+	log.Infof("授权码批次 %s 生成完成，共 %d 个", batch.Label, batch.Quantity)
+}
+
+// failBatch 标记批次生成失败，已生成的授权码保留
+// 服务停止导致的失败不标记，租约过期后续跑；租约已被其他实例接管时由接管的实例继续
+func (s *authorizationCodeBatchService) failBatch(ctx context.Context, id, owner, message string) {
+	log := s.logger.WithField("batch_id", id)
+	if ctx.Err() != nil {
+		log.Infof("服务停止，授权码批次待续跑: %s", message)
+		return
+	}
+	log.Errorf("授权码批次生成失败: %s", message)
+	if err := s.batchRepo.UpdateLeasedFields(ctx, id, owner, backgroundJobLease, map[string]interface{}{
+		"status":        models.AuthorizationCodeBatchStatusFailed,
+		"error_message": message,
+	}); err != nil {
+		log.WithError(err).Error("更新授权码批次状态失败")
+	}
+}
+
+func (s *authorizationCodeBatchService) ResumeUnfinishedBatches() {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	resumeUnfinishedJobs(s.ctx, s.logger, "授权码批次", func() (int, error) {
+		batches, err := s.batchRepo.GetUnfinished(s.ctx)
+		if err != nil {
+			return 0, err
+		}
+		for _, batch := range batches {
+			if s.ctx.Err() != nil {
+				break
+			}
+			s.runBatch(s.ctx, batch)
+		}
+		return len(batches), nil
+	})
+}
+
+func (s *authorizationCodeBatchService) GetBatch(ctx context.Context, id string) (*models.AuthorizationCodeBatch, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	batch, err := s.getBatch(ctx, id, lang)
+	if err != nil {
+		return nil, err
+	}
+	batch.StatusDisplay = i18n.GetEnumMessage("authorization_code_batch_status", batch.Status, lang)
+	return batch, nil
+}
+
+func (s *authorizationCodeBatchService) GetBatchList(ctx context.Context, req *models.AuthorizationCodeBatchListRequest) (*models.AuthorizationCodeBatchListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	batches, total, err := s.batchRepo.GetList(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	for _, batch := range batches {
+		batch.StatusDisplay = i18n.GetEnumMessage("authorization_code_batch_status", batch.Status, lang)
+	}
+
+	return &models.AuthorizationCodeBatchListResponse{
+		List:       batches,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+func (s *authorizationCodeBatchService) ExportBatch(ctx context.Context, id, format string) ([]byte, string, string, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	batch, err := s.getBatch(ctx, id, lang)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if isBatchGenerating(batch) {
+		return nil, "", "", i18n.NewI18nError("300302", lang) // 批次尚未生成完成
+	}
+
+	codes, err := s.batchRepo.GetCodes(ctx, batch.ID)
+	if err != nil {
+		return nil, "", "", i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	// 自定义字段按字段定义顺序追加在固定列之后
+	definitions, err := s.customFields.GetDefinitions(ctx, models.CustomFieldEntityAuthorizationCode)
+	if err != nil {
+		return nil, "", "", i18n.NewI18nError("900004", lang, err.Error())
+	}
+	codeIDs := make([]string, 0, len(codes))
+	for _, code := range codes {
+		codeIDs = append(codeIDs, code.ID)
+	}
+	fields, err := s.customFields.GetEntityCustomFields(ctx, models.CustomFieldEntityAuthorizationCode, codeIDs)
+	if err != nil {
+		return nil, "", "", i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	now := time.Now()
+	rows := make([][]string, 0, len(codes)+1)
+	rows = append(rows, append([]string{"id", "code", "customer_id", "batch_label", "start_date", "end_date", "max_activations", "status"}, customFieldColumns(definitions)...))
+	for _, code := range codes {
+		status := "normal"
+		if code.IsLocked {
+			status = "locked"
+		} else if now.After(code.EndDate) {
+			status = "expired"
+		}
+		rows = append(rows, append([]string{
+			code.ID,
+			code.Code,
+			code.CustomerID,
+			sanitizeSpreadsheetCell(batch.Label),
+			code.StartDate.Format(time.RFC3339),
+			code.EndDate.Format(time.RFC3339),
+			strconv.Itoa(code.MaxActivations),
+			status,
+		}, customFieldCells(definitions, fields[code.ID])...))
+	}
+
+	baseName := fmt.Sprintf("authorization_code_batch_%s", batch.ID)
+	switch format {
+	case models.BatchExportFormatXLSX:
+		data, err := utils.WriteXLSX("codes", rows)
+		if err != nil {
+			return nil, "", "", i18n.NewI18nError("900004", lang, err.Error())
+		}
+		return data, baseName + ".xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", nil
+	default:
+		var buffer bytes.Buffer
+		buffer.WriteString("\xEF\xBB\xBF") // UTF-8 BOM，Excel 直接打开不乱码
+		writer := csv.NewWriter(&buffer)
+		if err := writer.WriteAll(rows); err != nil {
+			return nil, "", "", i18n.NewI18nError("900004", lang, err.Error())
+		}
+		return buffer.Bytes(), baseName + ".csv", "text/csv; charset=utf-8", nil
+	}
+}
+
+func (s *authorizationCodeBatchService) LockBatch(ctx context.Context, id, operatorID string, req *models.AuthorizationCodeLockRequest) (*models.AuthorizationCodeBatchOperationResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if req == nil {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+
+	batch, err := s.getBatch(ctx, id, lang)
+	if err != nil {
+		return nil, err
+	}
+	if isBatchGenerating(batch) {
+		return nil, i18n.NewI18nError("300302", lang)
+	}
+	if batch.Status == string(models.AuthorizationCodeBatchStatusRevoked) {
+		return nil, i18n.NewI18nError("300303", lang) // 批次已撤销
+	}
+
+	if operatorID == "" {
+		return nil, i18n.NewI18nError("100004", lang)
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"is_locked":   req.IsLocked,
+		"lock_reason": nil,
+		"locked_at":   nil,
+		"locked_by":   nil,
+		"updated_at":  now,
+	}
+	changeType := "unlock"
+	if req.IsLocked {
+		updates["lock_reason"] = req.LockReason
+		updates["locked_at"] = now
+		updates["locked_by"] = operatorID
+		changeType = "lock"
+	}
+
+	codes, changes, err := s.buildBatchChanges(ctx, batch.ID, changeType, req.Reason, operatorID, func(code *models.AuthorizationCode) {
+		code.IsLocked = req.IsLocked
+		code.LockReason = nil
+		if req.IsLocked {
+			code.LockReason = req.LockReason
+		}
+	})
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	affected, err := s.batchRepo.LockCodes(ctx, batch.ID, updates, req.IsLocked, changes)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	s.invalidateEntitlements(ctx, codes)
+
+	return &models.AuthorizationCodeBatchOperationResponse{
+		BatchID:       batch.ID,
+		AffectedCodes: affected,
+	}, nil
+}
+
+func (s *authorizationCodeBatchService) RevokeBatch(ctx context.Context, id, operatorID string, req *models.AuthorizationCodeBatchRevokeRequest) (*models.AuthorizationCodeBatchOperationResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if req == nil {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+
+	batch, err := s.getBatch(ctx, id, lang)
+	if err != nil {
+		return nil, err
+	}
+	if isBatchGenerating(batch) {
+		return nil, i18n.NewI18nError("300302", lang)
+	}
+	if batch.Status == string(models.AuthorizationCodeBatchStatusRevoked) {
+		return nil, i18n.NewI18nError("300303", lang)
+	}
+
+	if operatorID == "" {
+		return nil, i18n.NewI18nError("100004", lang)
+	}
+
+	// 撤销即整批锁定并撤销已激活的许可证，不可恢复
+	now := time.Now()
+	codeUpdates := map[string]interface{}{
+		"is_locked":   true,
+		"lock_reason": req.Reason,
+		"locked_at":   now,
+		"locked_by":   operatorID,
+		"updated_at":  now,
+	}
+
+	codes, changes, err := s.buildBatchChanges(ctx, batch.ID, "lock", req.Reason, operatorID, func(code *models.AuthorizationCode) {
+		code.IsLocked = true
+		code.LockReason = req.Reason
+	})
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	batch.Status = string(models.AuthorizationCodeBatchStatusRevoked)
+	batch.IsLocked = true
+	batch.RevokeReason = req.Reason
+	batch.RevokedAt = &now
+
+	affectedCodes, revokedLicenses, err := s.batchRepo.RevokeBatch(ctx, batch, codeUpdates, changes)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	s.invalidateEntitlements(ctx, codes)
+
+	s.logger.Infof("[RevokeBatch] 授权码批次 %s 已撤销，授权码 %d 个，许可证 %d 个，操作人 %s",
+		batch.ID, affectedCodes, revokedLicenses, operatorID)
+
+	return &models.AuthorizationCodeBatchOperationResponse{
+		BatchID:         batch.ID,
+		AffectedCodes:   affectedCodes,
+		RevokedLicenses: revokedLicenses,
+	}, nil
+}
+
+// buildBatchChanges 为批次内每个授权码构建变更历史，apply 用于在快照上模拟本次变更，同时返回批次内的授权码
+func (s *authorizationCodeBatchService) buildBatchChanges(ctx context.Context, batchID, changeType string, reason *string, operatorID string, apply func(code *models.AuthorizationCode)) ([]*models.AuthorizationCode, []*models.AuthorizationChange, error) {
+	codes, err := s.batchRepo.GetCodes(ctx, batchID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes := make([]*models.AuthorizationChange, 0, len(codes))
+	for _, code := range codes {
+		oldConfig := buildConfigSnapshot(code)
+		apply(code)
+		change, err := newAuthorizationChange(code.ID, changeType, reason, operatorID, oldConfig, buildConfigSnapshot(code))
+		if err != nil {
+			return nil, nil, err
+		}
+		changes = append(changes, change)
+	}
+	return codes, changes, nil
+}
+
+// invalidateEntitlements 批量锁定、解锁或撤销后清除批次内授权码及其客户的授权检查缓存
+func (s *authorizationCodeBatchService) invalidateEntitlements(ctx context.Context, codes []*models.AuthorizationCode) {
+	if s.entitlements == nil {
+		return
+	}
+	for _, code := range codes {
+		s.entitlements.InvalidateAuthorizationCode(ctx, code)
+	}
+}
+
+func (s *authorizationCodeBatchService) getBatch(ctx context.Context, id, lang string) (*models.AuthorizationCodeBatch, error) {
+	if id == "" {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+	batch, err := s.batchRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeBatchNotFound) {
+			return nil, i18n.NewI18nError("300301", lang) // 批次不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return batch, nil
+}
+
+// isBatchGenerating 批次是否仍在生成中
+func isBatchGenerating(batch *models.AuthorizationCodeBatch) bool {
+	return batch.Status == string(models.AuthorizationCodeBatchStatusPending) ||
+		batch.Status == string(models.AuthorizationCodeBatchStatusRunning)
+}
+
+// sanitizeSpreadsheetCell 防止以公式字符开头的文本在表格软件中被当作公式执行
+func sanitizeSpreadsheetCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"license-manager/internal/config"
+	"license-manager/pkg/utils"
+)
+
+// resolveCodeFormat 确定授权码格式
+// 优先级：显式指定 > 套餐配置 > 产品配置（按 software_id） > 系统默认 > 旧格式
+func resolveCodeFormat(explicit, packageFormat string, softwareID *string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if packageFormat != "" {
+		return packageFormat
+	}
+
+	cfg := config.GetConfig()
+	if cfg != nil {
+		if softwareID != nil && *softwareID != "" {
+			if format, ok := cfg.License.ProductCodeFormats[strings.ToLower(*softwareID)]; ok && format != "" {
+				return format
+			}
+		}
+		if cfg.License.CodeFormat != "" {
+			return cfg.License.CodeFormat
+		}
+	}
+	return utils.AuthorizationCodeFormatLegacy
+}
+
+// generateAuthorizationCodeWithFormat 按指定格式生成授权码
+func generateAuthorizationCodeWithFormat(format string, params utils.AuthorizationCodeParams) (string, error) {
+	generator, err := newConfiguredCodeGenerator(format)
+	if err != nil {
+		return "", err
+	}
+	return generator.Generate(params)
+}
+
+// newConfiguredCodeGenerator 创建授权码生成器，签名码使用系统RSA私钥
+// 批量生成时复用同一生成器，避免重复加载私钥
+func newConfiguredCodeGenerator(format string) (utils.AuthorizationCodeGenerator, error) {
+	var privateKey *utils.RSAPrivateKey
+	if format == utils.AuthorizationCodeFormatSigned {
+		cfg := config.GetConfig()
+		if cfg == nil || cfg.License.RSA.PrivateKeyPath == "" {
+			return nil, errors.New("RSA private key path not configured")
+		}
+		key, err := utils.LoadRSAPrivateKeyFromFile(cfg.License.RSA.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		// 套餐或请求指定签名码时，启动时的配置校验未覆盖，生成前同样检查长度
+		if length := config.SignedAuthorizationCodeMaxLength(key.Size()); length > config.MaxAuthorizationCodeLength {
+			return nil, fmt.Errorf("signed authorization code can be %d characters, exceeding the storable %d characters", length, config.MaxAuthorizationCodeLength)
+		}
+		privateKey = key
+	}
+
+	return utils.NewAuthorizationCodeGenerator(format, privateKey)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+
+	"gorm.io/gorm"
+)
+
+// parseMaintenanceUntil 解析维护截止日期（YYYY-MM-DD），截止到当天23:59:59，空字符串返回 nil 表示不限制
+func parseMaintenanceUntil(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("maintenance_until must be in YYYY-MM-DD format")
+	}
+	until := time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 23, 59, 59, 0, time.Local)
+	return &until, nil
+}
+
+// packageMaintenanceUntil 套餐授权码的初始维护截止时间，套餐未配置维护期时返回 nil
+func packageMaintenanceUntil(pkgEntity *models.Package, startDate time.Time) *time.Time {
+	if pkgEntity == nil || pkgEntity.MaintenanceMonths == nil || pkgEntity.Type == string(models.PackageTypeTrial) {
+		return nil
+	}
+	until := startDate.AddDate(0, *pkgEntity.MaintenanceMonths, 0).Add(-time.Second)
+	return &until
+}
+
+// maintenanceRenewalUntil 计算续订后的维护截止时间：仍在维护期内的在原截止时间基础上顺延，
+// 已过期或未设置的从当天起算
+func maintenanceRenewalUntil(current *time.Time, months int, now time.Time) time.Time {
+	if current != nil && current.After(now) {
+		return current.AddDate(0, months, 0)
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return today.AddDate(0, months, 0).Add(-time.Second)
+}
+
+// maintenanceActive 当前是否在维护期内，未设置维护截止时间视为不限制
+func maintenanceActive(until *time.Time, now time.Time) bool {
+	return until == nil || !now.After(*until)
+}
+
+// PrepareMaintenanceRenewal 校验续订维护订单：授权码须属于当前客户，套餐须配置维护月数；
+// 许可数量按授权码的最大激活数计算
+func (s *cuOrderService) PrepareMaintenanceRenewal(ctx context.Context, customerID string, req *models.CuOrderCreateRequest) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if req.OrderType != models.CuOrderTypeMaintenanceRenewal {
+		return nil
+	}
+	if req.AuthorizationCodeID == nil || *req.AuthorizationCodeID == "" {
+		return i18n.NewI18nError("900001", lang, "authorization_code_id is required")
+	}
+
+	authCode, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, *req.AuthorizationCodeID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+			return i18n.NewI18nError("601008", lang) // 续订维护的授权码不存在
+		}
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if authCode.CustomerID != customerID {
+		return i18n.NewI18nError("601008", lang)
+	}
+
+	pkgEntity, _, err := s.getPackageForOrder(ctx, req.PackageID)
+	if err != nil {
+		return err
+	}
+	if pkgEntity.MaintenanceMonths == nil || pkgEntity.Type == string(models.PackageTypeTrial) {
+		return i18n.NewI18nError("601009", lang) // 套餐不支持续订维护
+	}
+
+	req.LicenseCount = authCode.MaxActivations
+	return nil
+}
+
+// renewOrderMaintenance 在事务中按续订维护订单顺延授权码的维护截止时间，并记录授权变更历史
+// 授权码更新时间随之变化，客户端下次心跳即可获取包含新维护截止时间的许可证文件
+func renewOrderMaintenance(tx *gorm.DB, order *models.CuOrder, pkgEntity *models.Package, now time.Time) (*models.AuthorizationCode, error) {
+	if order.RenewalCodeID == nil {
+		return nil, errors.New("maintenance renewal order has no authorization code")
+	}
+	if pkgEntity == nil || pkgEntity.MaintenanceMonths == nil {
+		return nil, fmt.Errorf("package %s does not support maintenance renewal", order.PackageID)
+	}
+
+	var authCode models.AuthorizationCode
+	if err := tx.Where("id = ? AND customer_id = ?", *order.RenewalCodeID, order.CustomerID).First(&authCode).Error; err != nil {
+		return nil, err
+	}
+
+	oldConfig := buildConfigSnapshot(&authCode)
+	until := maintenanceRenewalUntil(authCode.MaintenanceUntil, *pkgEntity.MaintenanceMonths, now)
+	authCode.MaintenanceUntil = &until
+	if err := tx.Save(&authCode).Error; err != nil {
+		return nil, err
+	}
+
+	newConfig := buildConfigSnapshot(&authCode)
+	newConfig["order_id"] = order.ID
+	reason := fmt.Sprintf("续订维护，订单号：%s", order.OrderNo)
+	change, err := newAuthorizationChange(authCode.ID, "maintenance_renewal", &reason, order.CuUserID, oldConfig, newConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Create(change).Error; err != nil {
+		return nil, err
+	}
+
+	return &authCode, nil
+}
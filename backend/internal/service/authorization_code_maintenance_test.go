@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+)
+
+func TestMaintenanceRenewalUntil(t *testing.T) {
+	now := time.Date(2026, 10, 19, 15, 0, 0, 0, time.Local)
+
+	// 维护期内：在原截止时间基础上顺延
+	current := time.Date(2026, 12, 31, 23, 59, 59, 0, time.Local)
+	if got := maintenanceRenewalUntil(&current, 12, now); !got.Equal(time.Date(2027, 12, 31, 23, 59, 59, 0, time.Local)) {
+		t.Errorf("active maintenance: got %v", got)
+	}
+
+	// 已过期或未设置：从当天起算
+	want := time.Date(2027, 10, 18, 23, 59, 59, 0, time.Local)
+	expired := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	if got := maintenanceRenewalUntil(&expired, 12, now); !got.Equal(want) {
+		t.Errorf("expired maintenance: got %v, want %v", got, want)
+	}
+	if got := maintenanceRenewalUntil(nil, 12, now); !got.Equal(want) {
+		t.Errorf("no maintenance: got %v, want %v", got, want)
+	}
+}
+
+func TestPackageMaintenanceUntil(t *testing.T) {
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+	months := 12
+
+	pkg := &models.Package{Type: string(models.PackageTypeProfessional), MaintenanceMonths: &months}
+	got := packageMaintenanceUntil(pkg, start)
+	if got == nil || !got.Equal(time.Date(2027, 10, 18, 23, 59, 59, 0, time.Local)) {
+		t.Errorf("unexpected maintenance until: %v", got)
+	}
+
+	if got := packageMaintenanceUntil(&models.Package{Type: string(models.PackageTypeBasic)}, start); got != nil {
+		t.Errorf("package without maintenance months should not limit versions, got %v", got)
+	}
+	trial := &models.Package{Type: string(models.PackageTypeTrial), MaintenanceMonths: &months}
+	if got := packageMaintenanceUntil(trial, start); got != nil {
+		t.Errorf("trial package should not set maintenance, got %v", got)
+	}
+}
+
+func TestParseMaintenanceUntil(t *testing.T) {
+	got, err := parseMaintenanceUntil("2027-06-30")
+	if err != nil || got == nil || !got.Equal(time.Date(2027, 6, 30, 23, 59, 59, 0, time.Local)) {
+		t.Fatalf("unexpected result: %v, %v", got, err)
+	}
+	if got, err := parseMaintenanceUntil(""); err != nil || got != nil {
+		t.Fatalf("empty value should clear maintenance, got %v, %v", got, err)
+	}
+	if _, err := parseMaintenanceUntil("2027/06/30"); err == nil {
+		t.Fatal("expected format error")
+	}
+}
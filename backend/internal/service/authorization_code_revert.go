@@ -0,0 +1,352 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"reflect"
+	"sort"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+)
+
+// errInvalidConfigSnapshot 配置快照无法应用到授权码
+var errInvalidConfigSnapshot = errors.New("invalid config snapshot")
+
+// 变更快照选择
+const (
+	changeSnapshotOld = "old" // 变更前配置
+	changeSnapshotNew = "new" // 变更后配置
+)
+
+// GetAuthorizationChangeDiff 对比授权码两条变更记录的配置快照，未指定终点时与当前配置对比
+func (s *authorizationCodeService) GetAuthorizationChangeDiff(ctx context.Context, authCodeID string, req *models.AuthorizationChangeDiffRequest) (*models.AuthorizationChangeDiffResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 业务逻辑：参数验证
+	if authCodeID == "" || req == nil || req.FromChangeID == "" {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+	if req.FromSnapshot == "" {
+		req.FromSnapshot = changeSnapshotNew
+	}
+
+	fromChange, err := s.getAuthorizationChange(ctx, authCodeID, req.FromChangeID)
+	if err != nil {
+		return nil, err
+	}
+	fromConfig, err := decodeChangeSnapshot(fromChange, req.FromSnapshot)
+	if err != nil {
+		return nil, i18n.NewI18nError("300010", lang)
+	}
+
+	resp := &models.AuthorizationChangeDiffResponse{
+		FromChangeID: fromChange.ID,
+		FromSnapshot: req.FromSnapshot,
+	}
+
+	var toConfig map[string]interface{}
+	if req.ToChangeID != "" {
+		if req.ToSnapshot == "" {
+			req.ToSnapshot = changeSnapshotNew
+		}
+		toChange, err := s.getAuthorizationChange(ctx, authCodeID, req.ToChangeID)
+		if err != nil {
+			return nil, err
+		}
+		if toConfig, err = decodeChangeSnapshot(toChange, req.ToSnapshot); err != nil {
+			return nil, i18n.NewI18nError("300010", lang)
+		}
+		resp.ToChangeID = toChange.ID
+		resp.ToSnapshot = req.ToSnapshot
+	} else {
+		authCode, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, authCodeID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+				return nil, i18n.NewI18nError("300001", lang) // 授权码不存在
+			}
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		// 经过一次JSON编解码，使当前配置与已存储快照的值类型一致
+		if toConfig, err = normalizeConfigSnapshot(buildConfigSnapshot(authCode)); err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		resp.ToSnapshot = "current"
+	}
+
+	resp.Diffs = diffConfigSnapshots(fromConfig, toConfig)
+	return resp, nil
+}
+
+// RevertAuthorizationCode 将授权码配置回滚到指定变更记录的快照，并记录一条 revert 类型的变更
+// 授权码更新时间随之刷新，客户端在下一次心跳时即可获取回滚后的配置
+func (s *authorizationCodeService) RevertAuthorizationCode(ctx context.Context, id string, req *models.AuthorizationCodeRevertRequest) (*models.AuthorizationCode, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 业务逻辑：参数验证
+	if id == "" || req == nil || req.ChangeID == "" {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+	if req.Snapshot == "" {
+		req.Snapshot = changeSnapshotNew
+	}
+
+	// 获取当前用户ID
+	currentUserID := pkgcontext.GetUserIDFromContext(ctx)
+	if currentUserID == "" {
+		return nil, i18n.NewI18nError("100004", lang)
+	}
+
+	change, err := s.getAuthorizationChange(ctx, id, req.ChangeID)
+	if err != nil {
+		return nil, err
+	}
+	raw := change.NewConfig
+	if req.Snapshot == changeSnapshotOld {
+		raw = change.OldConfig
+	}
+	var snapshot map[string]json.RawMessage
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &snapshot); err != nil {
+			return nil, i18n.NewI18nError("300010", lang)
+		}
+	}
+	if len(snapshot) == 0 {
+		return nil, i18n.NewI18nError("300602", lang) // 没有可回滚的配置快照
+	}
+
+	// 在锁定授权码的事务内应用快照并校验席位，避免与并发激活或其他修改交错
+	var oldConfig map[string]interface{}
+	authCode, err := s.authCodeRepo.RevertAuthorizationCode(ctx, id, func(authCode *models.AuthorizationCode) error {
+		// 记录变更前的配置
+		oldConfig = buildConfigSnapshot(authCode)
+		if err := applyConfigSnapshot(authCode, snapshot); err != nil {
+			return errInvalidConfigSnapshot
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAuthorizationCodeNotFound):
+			return nil, i18n.NewI18nError("300001", lang) // 授权码不存在
+		case errors.Is(err, errInvalidConfigSnapshot):
+			return nil, i18n.NewI18nError("300010", lang)
+		case errors.Is(err, repository.ErrAuthorizationCodeSeatsShort):
+			return nil, i18n.NewI18nError("300603", lang) // 回滚后的席位数小于当前已激活数
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	s.invalidateEntitlements(ctx, authCode)
+
+	// 填充多语言显示字段和计算状态
+	s.fillAuthorizationCodeDisplayFields(authCode, lang)
+
+	// 记录变更历史到 authorization_changes 表
+	newConfig := buildConfigSnapshot(authCode)
+	newConfig["reverted_from"] = map[string]interface{}{
+		"change_id": change.ID,
+		"snapshot":  req.Snapshot,
+	}
+	if err := s.recordAuthorizationChange(ctx, authCode.ID, "revert", req.Reason, currentUserID, oldConfig, newConfig); err != nil {
+		log.Printf("记录授权变更历史失败: %v", err)
+	}
+
+	return authCode, nil
+}
+
+// getAuthorizationChange 获取属于指定授权码的变更记录
+func (s *authorizationCodeService) getAuthorizationChange(ctx context.Context, authCodeID, changeID string) (*models.AuthorizationChange, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	change, err := s.authCodeRepo.GetAuthorizationChangeByID(ctx, changeID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationChangeNotFound) {
+			return nil, i18n.NewI18nError("300601", lang) // 变更记录不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if change.AuthorizationCodeID != authCodeID {
+		return nil, i18n.NewI18nError("300601", lang)
+	}
+	return change, nil
+}
+
+// decodeChangeSnapshot 解析变更记录中的变更前或变更后配置快照，快照为空时返回空 map
+func decodeChangeSnapshot(change *models.AuthorizationChange, side string) (map[string]interface{}, error) {
+	raw := change.NewConfig
+	if side == changeSnapshotOld {
+		raw = change.OldConfig
+	}
+	config := make(map[string]interface{})
+	if len(raw) == 0 {
+		return config, nil
+	}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+	return config, nil
+}
+
+// normalizeConfigSnapshot 将配置快照经过一次JSON编解码，得到与已存储快照相同的值类型
+func normalizeConfigSnapshot(config map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	normalized := make(map[string]interface{})
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// diffConfigSnapshots 逐字段对比两个配置快照，JSON对象字段展开为点号分隔的路径，结果按字段名排序
+func diffConfigSnapshots(from, to map[string]interface{}) []models.AuthorizationConfigFieldDiff {
+	diffs := make([]models.AuthorizationConfigFieldDiff, 0)
+	appendConfigDiffs(&diffs, "", from, to)
+	return diffs
+}
+
+// appendConfigDiffs 递归对比两个JSON对象并追加差异
+func appendConfigDiffs(diffs *[]models.AuthorizationConfigFieldDiff, prefix string, from, to map[string]interface{}) {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+		fromValue, toValue := from[key], to[key]
+
+		fromMap, fromIsMap := fromValue.(map[string]interface{})
+		toMap, toIsMap := toValue.(map[string]interface{})
+		if (fromIsMap || fromValue == nil) && (toIsMap || toValue == nil) && (fromIsMap || toIsMap) {
+			appendConfigDiffs(diffs, field, fromMap, toMap)
+			continue
+		}
+
+		if !reflect.DeepEqual(fromValue, toValue) {
+			*diffs = append(*diffs, models.AuthorizationConfigFieldDiff{
+				Field: field,
+				From:  fromValue,
+				To:    toValue,
+			})
+		}
+	}
+}
+
+// configSnapshotValues 配置快照中可回滚的字段
+type configSnapshotValues struct {
+	SoftwareID         *string     `json:"software_id"`
+	Description        *string     `json:"description"`
+	StartDate          *time.Time  `json:"start_date"`
+	EndDate            *time.Time  `json:"end_date"`
+	MaintenanceUntil   *time.Time  `json:"maintenance_until"`
+	DeploymentType     *string     `json:"deployment_type"`
+	RelayFingerprint   *string     `json:"relay_fingerprint"`
+	EncryptionType     *string     `json:"encryption_type"`
+	SoftwareVersion    *string     `json:"software_version"`
+	MaxActivations     *int        `json:"max_activations"`
+	DormantReclaimDays *int        `json:"dormant_reclaim_days"`
+	MaxTransfers       *int        `json:"max_transfers"`
+	TransferPeriodDays *int        `json:"transfer_period_days"`
+	FeatureConfig      models.JSON `json:"feature_config"`
+	UsageLimits        models.JSON `json:"usage_limits"`
+	CustomParameters   models.JSON `json:"custom_parameters"`
+	ActivationPolicy   models.JSON `json:"activation_policy"`
+}
+
+// applyConfigSnapshot 将配置快照中的字段应用到授权码
+// 授权码本身、锁定状态和席位池取用不参与回滚（锁定需通过锁定接口操作，席位池取用需通过更新接口校验席位）；快照中缺少的普通字段保持不变，
+// 缺少的JSON配置字段表示当时为空，回滚后清空
+func applyConfigSnapshot(authCode *models.AuthorizationCode, snapshot map[string]json.RawMessage) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	var values configSnapshotValues
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	has := func(field string) bool {
+		_, ok := snapshot[field]
+		return ok
+	}
+
+	// 可为空的字段：快照中为 null 时回滚为空
+	if has("software_id") {
+		authCode.SoftwareID = values.SoftwareID
+	}
+	if has("description") {
+		authCode.Description = values.Description
+	}
+	if has("encryption_type") {
+		authCode.EncryptionType = values.EncryptionType
+	}
+	if has("software_version") {
+		authCode.SoftwareVersion = values.SoftwareVersion
+	}
+	if has("relay_fingerprint") {
+		authCode.RelayFingerprint = values.RelayFingerprint
+	}
+	if has("maintenance_until") {
+		authCode.MaintenanceUntil = values.MaintenanceUntil
+	}
+	if has("dormant_reclaim_days") {
+		authCode.DormantReclaimDays = values.DormantReclaimDays
+	}
+	if has("max_transfers") {
+		authCode.MaxTransfers = values.MaxTransfers
+	}
+	if has("transfer_period_days") {
+		authCode.TransferPeriodDays = values.TransferPeriodDays
+	}
+
+	// 必填字段：仅在快照中有值时回滚
+	if values.StartDate != nil {
+		authCode.StartDate = *values.StartDate
+	}
+	if values.EndDate != nil {
+		authCode.EndDate = *values.EndDate
+	}
+	if values.DeploymentType != nil {
+		authCode.DeploymentType = *values.DeploymentType
+	}
+	if values.MaxActivations != nil {
+		authCode.MaxActivations = *values.MaxActivations
+	}
+
+	// JSON配置字段
+	authCode.FeatureConfig = nullableJSON(values.FeatureConfig)
+	authCode.UsageLimits = nullableJSON(values.UsageLimits)
+	authCode.CustomParameters = nullableJSON(values.CustomParameters)
+	authCode.ActivationPolicy = nullableJSON(values.ActivationPolicy)
+
+	return nil
+}
+
+// nullableJSON 将 null 转换为空值
+func nullableJSON(value models.JSON) models.JSON {
+	if len(value) == 0 || bytes.Equal(value, []byte("null")) {
+		return nil
+	}
+	return value
+}
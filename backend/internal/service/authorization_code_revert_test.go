@@ -0,0 +1,83 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+)
+
+func TestDiffConfigSnapshots(t *testing.T) {
+	from := map[string]interface{}{
+		"max_activations": float64(5),
+		"description":     "old",
+		"feature_config":  map[string]interface{}{"a": true, "b": float64(1)},
+	}
+	to := map[string]interface{}{
+		"max_activations": float64(5),
+		"description":     nil,
+		"feature_config":  map[string]interface{}{"a": true, "c": "x"},
+		"usage_limits":    map[string]interface{}{"api": float64(10)},
+	}
+
+	got := diffConfigSnapshots(from, to)
+	want := []models.AuthorizationConfigFieldDiff{
+		{Field: "description", From: "old", To: nil},
+		{Field: "feature_config.b", From: float64(1), To: nil},
+		{Field: "feature_config.c", From: nil, To: "x"},
+		{Field: "usage_limits.api", From: nil, To: float64(10)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected diff: %+v", got)
+	}
+
+	if diffs := diffConfigSnapshots(from, from); len(diffs) != 0 {
+		t.Fatalf("expected no diff, got %+v", diffs)
+	}
+}
+
+func TestApplyConfigSnapshot(t *testing.T) {
+	description := "current"
+	version := "2.0"
+	authCode := &models.AuthorizationCode{
+		Code:            "CODE",
+		Description:     &description,
+		SoftwareVersion: &version,
+		MaxActivations:  10,
+		DeploymentType:  "cloud",
+		IsLocked:        true,
+		FeatureConfig:   models.JSON(`{"a":true}`),
+	}
+	oldConfig := buildConfigSnapshot(authCode)
+
+	var snapshot map[string]json.RawMessage
+	raw := `{"code":"OTHER","description":null,"software_version":"1.0","max_activations":3,
+		"start_date":"2026-01-01T00:00:00Z","is_locked":false,"usage_limits":{"api":5}}`
+	if err := json.Unmarshal([]byte(raw), &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfigSnapshot(authCode, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	if authCode.Code != "CODE" || !authCode.IsLocked {
+		t.Fatalf("code and lock state must not be reverted")
+	}
+	if authCode.Description != nil || authCode.SoftwareVersion == nil || *authCode.SoftwareVersion != "1.0" {
+		t.Fatalf("unexpected nullable fields: %v %v", authCode.Description, authCode.SoftwareVersion)
+	}
+	if authCode.MaxActivations != 3 || authCode.DeploymentType != "cloud" {
+		t.Fatalf("unexpected value fields: %d %s", authCode.MaxActivations, authCode.DeploymentType)
+	}
+	if !authCode.StartDate.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected start date: %v", authCode.StartDate)
+	}
+	if authCode.FeatureConfig != nil || string(authCode.UsageLimits) != `{"api":5}` {
+		t.Fatalf("unexpected json fields: %s %s", authCode.FeatureConfig, authCode.UsageLimits)
+	}
+	if *oldConfig["software_version"].(*string) != "2.0" {
+		t.Fatalf("snapshot taken before revert must not change")
+	}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// scheduledActionBatchSize 每次定时任务最多执行的计划操作数量
+const scheduledActionBatchSize = 100
+
+// AuthorizationCodeScheduleService 授权码计划操作服务接口
+type AuthorizationCodeScheduleService interface {
+	CreateScheduledAction(ctx context.Context, authCodeID, operatorID string, req *models.AuthorizationCodeScheduledActionCreateRequest) (*models.AuthorizationCodeScheduledAction, error)
+	GetScheduledActionList(ctx context.Context, authCodeID string, req *models.AuthorizationCodeScheduledActionListRequest) (*models.AuthorizationCodeScheduledActionListResponse, error)
+	CancelScheduledAction(ctx context.Context, authCodeID, actionID string) (*models.AuthorizationCodeScheduledAction, error)
+	// RunDueActions 执行触发条件已满足的计划操作，并记录到授权变更历史
+	RunDueActions(ctx context.Context) (*models.ScheduledActionRunResponse, error)
+}
+
+type authorizationCodeScheduleService struct {
+	scheduleRepo repository.AuthorizationCodeScheduleRepository
+	authCodeRepo repository.AuthorizationCodeRepository
+	cuOrderRepo  repository.CuOrderRepository
+	entitlements EntitlementInvalidator
+	logger       *logrus.Logger
+}
+
+// NewAuthorizationCodeScheduleService 创建授权码计划操作服务
+func NewAuthorizationCodeScheduleService(
+	scheduleRepo repository.AuthorizationCodeScheduleRepository,
+	authCodeRepo repository.AuthorizationCodeRepository,
+	cuOrderRepo repository.CuOrderRepository,
+	entitlements EntitlementInvalidator,
+	logger *logrus.Logger,
+) AuthorizationCodeScheduleService {
+	return &authorizationCodeScheduleService{
+		scheduleRepo: scheduleRepo,
+		authCodeRepo: authCodeRepo,
+		cuOrderRepo:  cuOrderRepo,
+		entitlements: entitlements,
+		logger:       logger,
+	}
+}
+
+// CreateScheduledAction 为授权码创建计划操作
+func (s *authorizationCodeScheduleService) CreateScheduledAction(ctx context.Context, authCodeID, operatorID string, req *models.AuthorizationCodeScheduledActionCreateRequest) (*models.AuthorizationCodeScheduledAction, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	authCode, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, authCodeID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+			return nil, i18n.NewI18nError("300001", lang) // 授权码不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	action := &models.AuthorizationCodeScheduledAction{
+		AuthorizationCodeID: authCode.ID,
+		ActionType:          req.ActionType,
+		TriggerType:         req.TriggerType,
+		Reason:              req.Reason,
+		Status:              string(models.ScheduledActionStatusPending),
+		CreatedBy:           operatorID,
+	}
+
+	// 触发条件参数校验
+	switch req.TriggerType {
+	case models.ScheduledTriggerAt:
+		if req.RunAt == nil || !req.RunAt.After(time.Now()) {
+			return nil, i18n.NewI18nError("900001", lang, "run_at must be in the future")
+		}
+		action.RunAt = req.RunAt
+	case models.ScheduledTriggerOrderPaid:
+		if req.OrderID == nil || *req.OrderID == "" {
+			return nil, i18n.NewI18nError("900001", lang, "order_id is required")
+		}
+		order, err := s.cuOrderRepo.GetByID(*req.OrderID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, i18n.NewI18nError("300703", lang) // 关联订单不存在
+			}
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		if order.CustomerID != authCode.CustomerID {
+			return nil, i18n.NewI18nError("300703", lang) // 订单不属于授权码所属客户
+		}
+		action.OrderID = &order.ID
+	}
+
+	// 操作参数校验
+	switch req.ActionType {
+	case models.ScheduledActionExtend:
+		if req.ExtendDays == nil {
+			return nil, i18n.NewI18nError("900001", lang, "extend_days is required")
+		}
+		action.ExtendDays = req.ExtendDays
+	case models.ScheduledActionSetMaxActivations:
+		if req.MaxActivations == nil {
+			return nil, i18n.NewI18nError("900001", lang, "max_activations is required")
+		}
+		action.MaxActivations = req.MaxActivations
+	}
+
+	if err := s.scheduleRepo.Create(ctx, action); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	s.fillDisplayFields(action, lang)
+	return action, nil
+}
+
+// GetScheduledActionList 查询授权码的计划操作列表
+func (s *authorizationCodeScheduleService) GetScheduledActionList(ctx context.Context, authCodeID string, req *models.AuthorizationCodeScheduledActionListRequest) (*models.AuthorizationCodeScheduledActionListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	actions, total, err := s.scheduleRepo.GetList(ctx, authCodeID, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	for _, action := range actions {
+		s.fillDisplayFields(action, lang)
+	}
+
+	return &models.AuthorizationCodeScheduledActionListResponse{
+		List:       actions,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+// CancelScheduledAction 取消待执行的计划操作
+func (s *authorizationCodeScheduleService) CancelScheduledAction(ctx context.Context, authCodeID, actionID string) (*models.AuthorizationCodeScheduledAction, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	action, err := s.scheduleRepo.GetByID(ctx, actionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrScheduledActionNotFound) {
+			return nil, i18n.NewI18nError("300701", lang) // 计划操作不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if action.AuthorizationCodeID != authCodeID {
+		return nil, i18n.NewI18nError("300701", lang)
+	}
+
+	now := time.Now()
+	if err := s.scheduleRepo.Cancel(ctx, action.ID, now); err != nil {
+		if errors.Is(err, repository.ErrScheduledActionNotPending) {
+			return nil, i18n.NewI18nError("300702", lang) // 计划操作已执行或已取消
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	action.Status = string(models.ScheduledActionStatusCancelled)
+	action.ExecutedAt = &now
+	s.fillDisplayFields(action, lang)
+	return action, nil
+}
+
+// RunDueActions 执行触发条件已满足的计划操作，单个操作失败不影响其他操作
+func (s *authorizationCodeScheduleService) RunDueActions(ctx context.Context) (*models.ScheduledActionRunResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	now := time.Now()
+	actions, err := s.scheduleRepo.FindDueActions(ctx, now, scheduledActionBatchSize)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	result := &models.ScheduledActionRunResponse{Due: len(actions)}
+	for _, action := range actions {
+		if err := s.executeAction(ctx, action, now); err != nil {
+			if errors.Is(err, repository.ErrScheduledActionNotPending) {
+				// 已被取消或由其他实例执行
+				continue
+			}
+			result.Failed++
+			s.logger.WithError(err).WithField("action_id", action.ID).Error("执行授权码计划操作失败")
+			if markErr := s.scheduleRepo.MarkFailed(ctx, action.ID, err.Error(), now); markErr != nil {
+				s.logger.WithError(markErr).WithField("action_id", action.ID).Error("标记计划操作失败状态失败")
+			}
+			continue
+		}
+		result.Executed++
+	}
+
+	if result.Due > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"due":      result.Due,
+			"executed": result.Executed,
+			"failed":   result.Failed,
+		}).Info("Scheduled authorization code actions processed")
+	}
+	return result, nil
+}
+
+// executeAction 执行单个计划操作并记录授权变更历史，变更记录的操作人为计划创建人
+func (s *authorizationCodeScheduleService) executeAction(ctx context.Context, action *models.AuthorizationCodeScheduledAction, now time.Time) error {
+	// 在锁定授权码的事务内基于最新数据应用计划操作，避免覆盖并发的修改
+	action.ExecutedAt = &now
+	authCode, err := s.scheduleRepo.Execute(ctx, action, func(authCode *models.AuthorizationCode) (*models.AuthorizationChange, error) {
+		oldConfig := buildConfigSnapshot(authCode)
+		changeType, err := applyScheduledAction(authCode, action, now)
+		if err != nil {
+			return nil, err
+		}
+		newConfig := buildConfigSnapshot(authCode)
+		newConfig["scheduled_action_id"] = action.ID
+
+		change, err := newAuthorizationChange(authCode.ID, changeType, action.Reason, action.CreatedBy, oldConfig, newConfig)
+		if err != nil {
+			return nil, err
+		}
+		change.ID = uuid.New().String()
+		return change, nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeSeatsShort) && action.MaxActivations != nil {
+			// 调整席位数时不能小于当前已激活数
+			return fmt.Errorf("max_activations %d is less than active licenses", *action.MaxActivations)
+		}
+		return err
+	}
+	// 锁定、延期或调整席位后清除授权检查缓存
+	if s.entitlements != nil {
+		s.entitlements.InvalidateAuthorizationCode(ctx, authCode)
+	}
+	return nil
+}
+
+// applyScheduledAction 将计划操作应用到授权码，返回对应的授权变更类型
+// 延期时，已过期的授权码从执行当天起算，未过期的在原失效日期基础上顺延
+func applyScheduledAction(authCode *models.AuthorizationCode, action *models.AuthorizationCodeScheduledAction, now time.Time) (string, error) {
+	switch action.ActionType {
+	case models.ScheduledActionLock:
+		authCode.IsLocked = true
+		authCode.LockReason = action.Reason
+		authCode.LockedAt = &now
+		authCode.LockedBy = &action.CreatedBy
+		return "lock", nil
+	case models.ScheduledActionUnlock:
+		authCode.IsLocked = false
+		authCode.LockReason = nil
+		authCode.LockedAt = nil
+		authCode.LockedBy = nil
+		return "unlock", nil
+	case models.ScheduledActionExtend:
+		if action.ExtendDays == nil {
+			return "", errors.New("extend_days is missing")
+		}
+		if authCode.EndDate.Before(now) {
+			_, authCode.EndDate = authorizationCodeValidity(now, *action.ExtendDays)
+		} else {
+			authCode.EndDate = authCode.EndDate.AddDate(0, 0, *action.ExtendDays)
+		}
+		return "renewal", nil
+	case models.ScheduledActionSetMaxActivations:
+		if action.MaxActivations == nil {
+			return "", errors.New("max_activations is missing")
+		}
+		authCode.MaxActivations = *action.MaxActivations
+		return "feature_limit_change", nil
+	}
+	return "", fmt.Errorf("unsupported action type: %s", action.ActionType)
+}
+
+// fillDisplayFields 填充多语言显示字段
+func (s *authorizationCodeScheduleService) fillDisplayFields(action *models.AuthorizationCodeScheduledAction, lang string) {
+	action.ActionTypeDisplay = i18n.GetEnumMessage("scheduled_action_type", action.ActionType, lang)
+	action.StatusDisplay = i18n.GetEnumMessage("scheduled_action_status", action.Status, lang)
+}
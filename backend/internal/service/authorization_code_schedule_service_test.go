@@ -0,0 +1,65 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+)
+
+func TestApplyScheduledActionExtend(t *testing.T) {
+	now := time.Date(2026, 6, 1, 10, 0, 0, 0, time.Local)
+	days := 30
+
+	t.Run("active code extends from end date", func(t *testing.T) {
+		end := time.Date(2026, 6, 30, 23, 59, 59, 0, time.Local)
+		authCode := &models.AuthorizationCode{EndDate: end}
+		action := &models.AuthorizationCodeScheduledAction{ActionType: models.ScheduledActionExtend, ExtendDays: &days}
+
+		changeType, err := applyScheduledAction(authCode, action, now)
+		if err != nil || changeType != "renewal" {
+			t.Fatalf("unexpected result: %s %v", changeType, err)
+		}
+		if !authCode.EndDate.Equal(end.AddDate(0, 0, days)) {
+			t.Fatalf("unexpected end date: %v", authCode.EndDate)
+		}
+	})
+
+	t.Run("expired code extends from today", func(t *testing.T) {
+		authCode := &models.AuthorizationCode{EndDate: time.Date(2026, 1, 1, 23, 59, 59, 0, time.Local)}
+		action := &models.AuthorizationCodeScheduledAction{ActionType: models.ScheduledActionExtend, ExtendDays: &days}
+
+		if _, err := applyScheduledAction(authCode, action, now); err != nil {
+			t.Fatal(err)
+		}
+		want := time.Date(2026, 6, 30, 23, 59, 59, 0, time.Local)
+		if !authCode.EndDate.Equal(want) {
+			t.Fatalf("unexpected end date: %v", authCode.EndDate)
+		}
+	})
+}
+
+func TestApplyScheduledActionLockAndSeats(t *testing.T) {
+	now := time.Now()
+	reason := "contract ended"
+	seats := 8
+	authCode := &models.AuthorizationCode{MaxActivations: 5}
+
+	changeType, err := applyScheduledAction(authCode, &models.AuthorizationCodeScheduledAction{
+		ActionType: models.ScheduledActionLock, Reason: &reason, CreatedBy: "admin",
+	}, now)
+	if err != nil || changeType != "lock" || !authCode.IsLocked || authCode.LockReason != &reason {
+		t.Fatalf("lock not applied: %s %v", changeType, err)
+	}
+
+	changeType, err = applyScheduledAction(authCode, &models.AuthorizationCodeScheduledAction{
+		ActionType: models.ScheduledActionSetMaxActivations, MaxActivations: &seats,
+	}, now)
+	if err != nil || changeType != "feature_limit_change" || authCode.MaxActivations != seats {
+		t.Fatalf("max activations not applied: %s %v", changeType, err)
+	}
+
+	if _, err := applyScheduledAction(authCode, &models.AuthorizationCodeScheduledAction{ActionType: "unknown"}, now); err == nil {
+		t.Fatal("expected error for unsupported action")
+	}
+}
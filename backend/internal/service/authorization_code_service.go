@@ -26,6 +26,11 @@ type authorizationCodeService struct {
 	customerRepo repository.CustomerRepository
 	cuUserRepo   repository.CuUserRepository
 	licenseRepo  repository.LicenseRepository
+	shareRepo    repository.AuthorizationCodeShareRepository
+	partnerRepo  repository.PartnerRepository
+	customFields repository.CustomFieldRepository
+	seatPools    repository.SeatPoolRepository
+	entitlements EntitlementInvalidator
 }
 
 // NewAuthorizationCodeService 创建授权码服务实例
@@ -34,12 +39,22 @@ func NewAuthorizationCodeService(
 	customerRepo repository.CustomerRepository,
 	cuUserRepo repository.CuUserRepository,
 	licenseRepo repository.LicenseRepository,
+	shareRepo repository.AuthorizationCodeShareRepository,
+	partnerRepo repository.PartnerRepository,
+	customFields repository.CustomFieldRepository,
+	seatPools repository.SeatPoolRepository,
+	entitlements EntitlementInvalidator,
 ) AuthorizationCodeService {
 	return &authorizationCodeService{
 		authCodeRepo: authCodeRepo,
 		customerRepo: customerRepo,
 		cuUserRepo:   cuUserRepo,
 		licenseRepo:  licenseRepo,
+		shareRepo:    shareRepo,
+		partnerRepo:  partnerRepo,
+		customFields: customFields,
+		seatPools:    seatPools,
+		entitlements: entitlements,
 	}
 }
 
@@ -61,15 +76,51 @@ func (s *authorizationCodeService) CreateAuthorizationCode(ctx context.Context,
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
+	if !customerInPartnerScope(pkgcontext.GetPartnerIDFromContext(ctx), customer) {
+		return nil, i18n.NewI18nError("200001", lang) // 不在经销商名下按客户不存在处理
+	}
+
 	// 检查客户状态：如果客户状态为停用（disabled），不允许创建授权
 	if customer.Status == "disabled" {
 		return nil, i18n.NewI18nError("200007", lang) // 客户已停用，无法创建授权
 	}
 
-	// 业务逻辑：计算开始时间和结束时间
-	now := time.Now()
-	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())                     // 当天00:00:00开始
-	endDate := startDate.AddDate(0, 0, req.ValidityDays-1).Add(23*time.Hour + 59*time.Minute + 59*time.Second) // 有效期最后一天的23:59:59结束
+	// 从席位池取用席位时客户须已设置席位池
+	if req.UseSeatPool {
+		if _, err := s.getSeatPool(ctx, req.CustomerID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 业务逻辑：计算开始时间和结束时间，指定生效日期时从该日期起算（不能早于当天）
+	startFrom := time.Now()
+	if req.StartDate != nil && *req.StartDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *req.StartDate, time.Local)
+		if err != nil {
+			return nil, i18n.NewI18nError("900001", lang, "start_date must be in YYYY-MM-DD format")
+		}
+		if parsed.Before(time.Date(startFrom.Year(), startFrom.Month(), startFrom.Day(), 0, 0, 0, 0, time.Local)) {
+			return nil, i18n.NewI18nError("900001", lang, "start_date cannot be earlier than today")
+		}
+		startFrom = parsed
+	}
+	startDate, endDate := authorizationCodeValidity(startFrom, req.ValidityDays)
+
+	// 校验使用限制策略和维护截止日期
+	if _, err := normalizeActivationPolicy(req.ActivationPolicy); err != nil {
+		return nil, i18n.NewI18nError("900001", lang, err.Error())
+	}
+	if req.MaintenanceUntil != nil {
+		if _, err := parseMaintenanceUntil(*req.MaintenanceUntil); err != nil {
+			return nil, i18n.NewI18nError("900001", lang, err.Error())
+		}
+	}
+
+	// 自定义字段和标签在创建授权码前校验，必填字段必须提供
+	customFields, tags, err := prepareEntityCustomFields(ctx, s.customFields, models.CustomFieldEntityAuthorizationCode, req.CustomFields, req.Tags, true)
+	if err != nil {
+		return nil, err
+	}
 
 	// 获取当前用户ID
 	currentUserID := pkgcontext.GetUserIDFromContext(ctx)
@@ -77,71 +128,37 @@ func (s *authorizationCodeService) CreateAuthorizationCode(ctx context.Context,
 		return nil, i18n.NewI18nError("100004", lang) // 缺少认证信息
 	}
 
-	// 授权码生成规则回退/统一为旧规则（不再自包含配置）
-	authCode, err := s.generateAuthorizationCode(req.CustomerID)
+	// 按显式指定/产品配置/系统默认确定授权码格式
+	codeFormat, codeParams := authorizationCodeGenerationParams(req, endDate)
+	authCode, err := generateAuthorizationCodeWithFormat(codeFormat, codeParams)
 	if err != nil {
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
-	// 处理JSON字段（用于数据库存储）
-	var featureConfig, usageLimits, customParameters models.JSON
-	if req.FeatureConfig != nil {
-		featureConfigBytes, err := json.Marshal(req.FeatureConfig)
-		if err != nil {
-			return nil, i18n.NewI18nError("300010", lang) // 配置参数错误
-		}
-		featureConfig = models.JSON(featureConfigBytes)
-	}
-	if req.UsageLimits != nil {
-		usageLimitsBytes, err := json.Marshal(req.UsageLimits)
-		if err != nil {
-			return nil, i18n.NewI18nError("300010", lang) // 配置参数错误
-		}
-		usageLimits = models.JSON(usageLimitsBytes)
-	}
-	if req.CustomParameters != nil {
-		customParametersBytes, err := json.Marshal(req.CustomParameters)
-		if err != nil {
-			return nil, i18n.NewI18nError("300010", lang) // 配置参数错误
-		}
-		customParameters = models.JSON(customParametersBytes)
+	// 构建授权码实体
+	authCodeEntity, err := newAuthorizationCodeEntity(req, authCode, codeFormat, currentUserID, startDate, endDate)
+	if err != nil {
+		return nil, i18n.NewI18nError("300010", lang) // 配置参数错误
 	}
 
-	// 设置默认加密类型
-	encryptionType := req.EncryptionType
-	if encryptionType == nil {
-		defaultEncryption := "standard"
-		encryptionType = &defaultEncryption
-	}
+	// 委托给Repository层进行数据创建，经销商账号在同一事务内校验席位额度
+	err = s.saveWithPartnerSeatQuota(ctx, authCodeEntity, req.MaxActivations > 0, func(tx interface{}) error {
+		if err := s.authCodeRepo.CreateAuthorizationCodeWithTx(ctx, tx, authCodeEntity); err != nil {
+			// 根据错误类型包装为完整的I18nError
+			if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "unique") {
+				return i18n.NewI18nError("300002", lang) // 授权码已存在
+			}
 
-	// 构建授权码实体
-	authCodeEntity := &models.AuthorizationCode{
-		ID:               uuid.New().String(), // 生成新的UUID作为主键
-		Code:             authCode,
-		CustomerID:       req.CustomerID,
-		CreatedBy:        currentUserID,
-		SoftwareID:       req.SoftwareID,
-		Description:      req.Description,
-		StartDate:        startDate,
-		EndDate:          endDate,
-		DeploymentType:   req.DeploymentType,
-		EncryptionType:   encryptionType,
-		SoftwareVersion:  req.SoftwareVersion,
-		MaxActivations:   req.MaxActivations,
-		IsLocked:         false,
-		FeatureConfig:    featureConfig,
-		UsageLimits:      usageLimits,
-		CustomParameters: customParameters,
-	}
-
-	// 委托给Repository层进行数据创建
-	if err := s.authCodeRepo.CreateAuthorizationCode(ctx, authCodeEntity); err != nil {
-		// 根据错误类型包装为完整的I18nError
-		if strings.Contains(err.Error(), "duplicate") || strings.Contains(err.Error(), "unique") {
-			return nil, i18n.NewI18nError("300002", lang) // 授权码已存在
+			// 数据库相关错误
+			return i18n.NewI18nError("900004", lang, err.Error())
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		// 数据库相关错误
+	if err := s.customFields.SaveEntityCustomFields(ctx, models.CustomFieldEntityAuthorizationCode, []string{authCodeEntity.ID}, customFields, tags); err != nil {
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
@@ -160,6 +177,26 @@ func (s *authorizationCodeService) GetAuthorizationCodeList(ctx context.Context,
 		return nil, i18n.NewI18nError("900001", lang)
 	}
 
+	// 按客户筛选时可包含其所有下级客户
+	if req.IncludeSubsidiaries && req.CustomerID != "" {
+		customerIDs, err := expandCustomerSubtree(ctx, s.customerRepo, req.CustomerID)
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		req.CustomerIDs = customerIDs
+	}
+
+	// 经销商账号只能查看名下客户的授权码
+	customerIDs, err := scopeCustomerFilter(ctx, s.partnerRepo, req.CustomerIDs, req.CustomerID)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	req.CustomerIDs = customerIDs
+
+	if err := normalizeCustomFieldFilter(ctx, s.customFields, models.CustomFieldEntityAuthorizationCode, &req.CustomFieldFilter); err != nil {
+		return nil, err
+	}
+
 	// 委托给Repository层进行数据访问
 	result, err := s.authCodeRepo.GetAuthorizationCodeList(ctx, req)
 	if err != nil {
@@ -167,6 +204,20 @@ func (s *authorizationCodeService) GetAuthorizationCodeList(ctx context.Context,
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
+	// 自定义字段和标签
+	ids := make([]string, 0, len(result.List))
+	for _, item := range result.List {
+		ids = append(ids, item.ID)
+	}
+	fields, err := s.customFields.GetEntityCustomFields(ctx, models.CustomFieldEntityAuthorizationCode, ids)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	for i := range result.List {
+		result.List[i].CustomFields = fields[result.List[i].ID].CustomFields
+		result.List[i].Tags = fields[result.List[i].ID].Tags
+	}
+
 	// 业务逻辑：添加多语言显示字段和状态计算
 	now := time.Now()
 	for i := range result.List {
@@ -203,6 +254,9 @@ func (s *authorizationCodeService) GetAuthorizationCode(ctx context.Context, id
 		// 数据库相关错误
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if err := s.ensureAuthorizationCodeInScope(ctx, authCode); err != nil {
+		return nil, err
+	}
 
 	// 查询客户信息
 	customer, err := s.customerRepo.GetCustomerByID(ctx, authCode.CustomerID)
@@ -231,12 +285,58 @@ func (s *authorizationCodeService) GetAuthorizationCode(ctx context.Context, id
 	}
 	authCode.ActivatedLicensesCount = activatedCount
 
+	// 自定义字段和标签
+	fields, err := s.customFields.GetEntityCustomFields(ctx, models.CustomFieldEntityAuthorizationCode, []string{id})
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	authCode.CustomFields = fields[id].CustomFields
+	authCode.Tags = fields[id].Tags
+
 	// 填充多语言显示字段和计算状态
 	s.fillAuthorizationCodeDisplayFields(authCode, lang)
 
 	return authCode, nil
 }
 
+// UpdateAuthorizationCodeCustomFields 修改授权码的自定义字段和标签
+// 修改了写入许可证文件的字段时刷新授权码更新时间，客户端下次心跳会收到新的许可证文件
+func (s *authorizationCodeService) UpdateAuthorizationCodeCustomFields(ctx context.Context, id string, req *models.EntityCustomFieldsUpdateRequest) (*models.EntityCustomFields, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if id == "" || req == nil {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+
+	authCode, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+			return nil, i18n.NewI18nError("300001", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if err := s.ensureAuthorizationCodeInScope(ctx, authCode); err != nil {
+		return nil, err
+	}
+
+	fields, err := updateEntityCustomFields(ctx, s.customFields, models.CustomFieldEntityAuthorizationCode, id, req)
+	if err != nil {
+		return nil, err
+	}
+
+	embedded, err := embedsLicenseCustomFields(ctx, s.customFields, req.CustomFields)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if embedded {
+		if err := s.authCodeRepo.UpdateAuthorizationCode(ctx, authCode); err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+	}
+
+	return fields, nil
+}
+
 // GenerateAuthorizationFile 生成授权码文件内容
 func (s *authorizationCodeService) GenerateAuthorizationFile(ctx context.Context, id string) ([]byte, string, string, error) {
 	lang := pkgcontext.GetLanguageFromContext(ctx)
@@ -252,6 +352,9 @@ func (s *authorizationCodeService) GenerateAuthorizationFile(ctx context.Context
 		}
 		return nil, "", "", i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if err := s.ensureAuthorizationCodeInScope(ctx, authCode); err != nil {
+		return nil, "", "", err
+	}
 
 	now := time.Now()
 	if authCode.IsLocked {
@@ -277,6 +380,7 @@ func (s *authorizationCodeService) GetProductActivationCode(ctx context.Context,
 	if idx := strings.Index(code, "&"); idx > 0 {
 		code = strings.TrimSpace(code[:idx])
 	}
+	code = utils.NormalizeAuthorizationCode(code) // 兼容口述录入的 Base32 码（大小写、分隔符、易混字符）
 	if code == "" {
 		return nil, i18n.NewI18nError("900001", lang)
 	}
@@ -407,6 +511,9 @@ func (s *authorizationCodeService) UpdateAuthorizationCode(ctx context.Context,
 		}
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if err := s.ensureAuthorizationCodeInScope(ctx, existingAuthCode); err != nil {
+		return nil, err
+	}
 
 	// 获取当前用户ID
 	currentUserID := pkgcontext.GetUserIDFromContext(ctx)
@@ -415,7 +522,7 @@ func (s *authorizationCodeService) UpdateAuthorizationCode(ctx context.Context,
 	}
 
 	// 记录变更前的配置
-	oldConfig := s.buildConfigSnapshot(existingAuthCode)
+	oldConfig := buildConfigSnapshot(existingAuthCode)
 
 	// 只更新提供的字段
 	if req.SoftwareID != nil {
@@ -450,16 +557,31 @@ func (s *authorizationCodeService) UpdateAuthorizationCode(ctx context.Context,
 		existingAuthCode.StartDate = startDate
 		existingAuthCode.EndDate = endDate
 	}
+	if req.MaintenanceUntil != nil {
+		// 传空字符串表示不限制可用版本
+		maintenanceUntil, err := parseMaintenanceUntil(*req.MaintenanceUntil)
+		if err != nil {
+			return nil, i18n.NewI18nError("900001", lang, err.Error())
+		}
+		existingAuthCode.MaintenanceUntil = maintenanceUntil
+	}
 	if req.DeploymentType != nil {
 		existingAuthCode.DeploymentType = *req.DeploymentType
 	}
+	if req.RelayFingerprint != nil {
+		// 传空字符串表示清除中继节点
+		existingAuthCode.RelayFingerprint = normalizeRelayFingerprint(req.RelayFingerprint)
+	}
 	if req.EncryptionType != nil {
 		existingAuthCode.EncryptionType = req.EncryptionType
 	}
 	if req.SoftwareVersion != nil {
 		existingAuthCode.SoftwareVersion = req.SoftwareVersion
 	}
+	addsSeats := false
 	if req.MaxActivations != nil {
+		// 经销商账号增加席位时在更新事务内校验额度
+		addsSeats = *req.MaxActivations > existingAuthCode.MaxActivations
 		existingAuthCode.MaxActivations = *req.MaxActivations
 	}
 
@@ -485,17 +607,65 @@ func (s *authorizationCodeService) UpdateAuthorizationCode(ctx context.Context,
 		}
 		existingAuthCode.CustomParameters = models.JSON(customParametersBytes)
 	}
+	if req.ActivationPolicy != nil {
+		// 传空对象表示取消所有限制
+		activationPolicy, err := marshalActivationPolicy(req.ActivationPolicy)
+		if err != nil {
+			return nil, i18n.NewI18nError("900001", lang, err.Error())
+		}
+		existingAuthCode.ActivationPolicy = activationPolicy
+	}
+	if req.DormantReclaimDays != nil {
+		// 传0表示关闭闲置席位回收
+		if *req.DormantReclaimDays == 0 {
+			existingAuthCode.DormantReclaimDays = nil
+		} else {
+			existingAuthCode.DormantReclaimDays = req.DormantReclaimDays
+		}
+	}
+	if req.MaxTransfers != nil {
+		// 传0表示不限制转移次数
+		if *req.MaxTransfers == 0 {
+			existingAuthCode.MaxTransfers = nil
+		} else {
+			existingAuthCode.MaxTransfers = req.MaxTransfers
+		}
+	}
+	if req.TransferPeriodDays != nil {
+		// 传0表示在整个授权期内统计转移次数
+		if *req.TransferPeriodDays == 0 {
+			existingAuthCode.TransferPeriodDays = nil
+		} else {
+			existingAuthCode.TransferPeriodDays = req.TransferPeriodDays
+		}
+	}
+	if req.UseSeatPool != nil && *req.UseSeatPool != existingAuthCode.UseSeatPool {
+		// 开启时校验客户席位池能容纳该授权码已激活的许可证
+		if *req.UseSeatPool {
+			if err := s.checkSeatPoolAttach(ctx, existingAuthCode); err != nil {
+				return nil, err
+			}
+		}
+		existingAuthCode.UseSeatPool = *req.UseSeatPool
+	}
 
 	// 委托给Repository层进行数据更新
-	if err := s.authCodeRepo.UpdateAuthorizationCode(ctx, existingAuthCode); err != nil {
-		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	err = s.saveWithPartnerSeatQuota(ctx, existingAuthCode, addsSeats, func(tx interface{}) error {
+		if err := s.authCodeRepo.UpdateAuthorizationCodeWithTx(ctx, tx, existingAuthCode); err != nil {
+			return i18n.NewI18nError("900004", lang, err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.invalidateEntitlements(ctx, existingAuthCode)
 
 	// 填充多语言显示字段和计算状态
 	s.fillAuthorizationCodeDisplayFields(existingAuthCode, lang)
 
 	// 记录变更历史到 authorization_changes 表
-	newConfig := s.buildConfigSnapshot(existingAuthCode)
+	newConfig := buildConfigSnapshot(existingAuthCode)
 	if err := s.recordAuthorizationChange(ctx, id, req.ChangeType, req.Reason, currentUserID, oldConfig, newConfig); err != nil {
 		log.Printf("记录授权变更历史失败: %v", err)
 	}
@@ -523,6 +693,9 @@ func (s *authorizationCodeService) LockUnlockAuthorizationCode(ctx context.Conte
 		}
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if err := s.ensureAuthorizationCodeInScope(ctx, existingAuthCode); err != nil {
+		return nil, err
+	}
 
 	// 获取当前用户ID
 	currentUserID := pkgcontext.GetUserIDFromContext(ctx)
@@ -531,7 +704,7 @@ func (s *authorizationCodeService) LockUnlockAuthorizationCode(ctx context.Conte
 	}
 
 	// 记录变更前的配置
-	oldConfig := s.buildConfigSnapshot(existingAuthCode)
+	oldConfig := buildConfigSnapshot(existingAuthCode)
 
 	// 更新锁定状态
 	now := time.Now()
@@ -553,6 +726,7 @@ func (s *authorizationCodeService) LockUnlockAuthorizationCode(ctx context.Conte
 	if err := s.authCodeRepo.UpdateAuthorizationCode(ctx, existingAuthCode); err != nil {
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
+	s.invalidateEntitlements(ctx, existingAuthCode)
 
 	// 填充多语言显示字段和计算状态
 	s.fillAuthorizationCodeDisplayFields(existingAuthCode, lang)
@@ -562,7 +736,7 @@ func (s *authorizationCodeService) LockUnlockAuthorizationCode(ctx context.Conte
 	if !req.IsLocked {
 		changeType = "unlock"
 	}
-	newConfig := s.buildConfigSnapshot(existingAuthCode)
+	newConfig := buildConfigSnapshot(existingAuthCode)
 	if err := s.recordAuthorizationChange(ctx, id, changeType, req.Reason, currentUserID, oldConfig, newConfig); err != nil {
 		log.Printf("记录授权变更历史失败: %v", err)
 	}
@@ -593,14 +767,18 @@ func (s *authorizationCodeService) DeleteAuthorizationCode(ctx context.Context,
 		}
 		return i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if err := s.ensureAuthorizationCodeInScope(ctx, existingAuthCode); err != nil {
+		return err
+	}
 
 	// 记录变更前的配置
-	oldConfig := s.buildConfigSnapshot(existingAuthCode)
+	oldConfig := buildConfigSnapshot(existingAuthCode)
 
 	// 委托给Repository层进行软删除
 	if err := s.authCodeRepo.DeleteAuthorizationCode(ctx, id); err != nil {
 		return i18n.NewI18nError("900004", lang, err.Error())
 	}
+	s.invalidateEntitlements(ctx, existingAuthCode)
 
 	// 记录变更历史到 authorization_changes 表
 	emptyConfig := make(map[string]interface{})
@@ -611,9 +789,27 @@ func (s *authorizationCodeService) DeleteAuthorizationCode(ctx context.Context,
 	return nil
 }
 
+// invalidateEntitlements 授权码变更后清除授权检查缓存
+func (s *authorizationCodeService) invalidateEntitlements(ctx context.Context, authCode *models.AuthorizationCode) {
+	if s.entitlements != nil {
+		s.entitlements.InvalidateAuthorizationCode(ctx, authCode)
+	}
+}
+
 // recordAuthorizationChange 记录授权变更历史
 func (s *authorizationCodeService) recordAuthorizationChange(ctx context.Context, authCodeID string, changeType string, reason *string, operatorID string, oldConfig, newConfig map[string]interface{}) error {
 	// 构建变更历史记录
+	change, err := newAuthorizationChange(authCodeID, changeType, reason, operatorID, oldConfig, newConfig)
+	if err != nil {
+		return err
+	}
+
+	// 委托给Repository层记录变更历史
+	return s.authCodeRepo.RecordAuthorizationChange(ctx, change)
+}
+
+// newAuthorizationChange 构建授权变更历史记录，配置快照序列化为JSON
+func newAuthorizationChange(authCodeID string, changeType string, reason *string, operatorID string, oldConfig, newConfig map[string]interface{}) (*models.AuthorizationChange, error) {
 	change := &models.AuthorizationChange{
 		AuthorizationCodeID: authCodeID,
 		ChangeType:          changeType,
@@ -625,7 +821,7 @@ func (s *authorizationCodeService) recordAuthorizationChange(ctx context.Context
 	if oldConfig != nil {
 		oldConfigBytes, err := json.Marshal(oldConfig)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		change.OldConfig = models.JSON(oldConfigBytes)
 	}
@@ -633,17 +829,16 @@ func (s *authorizationCodeService) recordAuthorizationChange(ctx context.Context
 	if newConfig != nil {
 		newConfigBytes, err := json.Marshal(newConfig)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		change.NewConfig = models.JSON(newConfigBytes)
 	}
 
-	// 委托给Repository层记录变更历史
-	return s.authCodeRepo.RecordAuthorizationChange(ctx, change)
+	return change, nil
 }
 
 // buildConfigSnapshot 构建配置快照，用于记录变更历史
-func (s *authorizationCodeService) buildConfigSnapshot(authCode *models.AuthorizationCode) map[string]interface{} {
+func buildConfigSnapshot(authCode *models.AuthorizationCode) map[string]interface{} {
 	config := make(map[string]interface{})
 
 	// 基础配置
@@ -652,12 +847,21 @@ func (s *authorizationCodeService) buildConfigSnapshot(authCode *models.Authoriz
 	config["description"] = authCode.Description
 	config["start_date"] = authCode.StartDate.Format(time.RFC3339)
 	config["end_date"] = authCode.EndDate.Format(time.RFC3339)
+	config["maintenance_until"] = nil
+	if authCode.MaintenanceUntil != nil {
+		config["maintenance_until"] = authCode.MaintenanceUntil.Format(time.RFC3339)
+	}
 	config["deployment_type"] = authCode.DeploymentType
+	config["relay_fingerprint"] = authCode.RelayFingerprint
 	config["encryption_type"] = authCode.EncryptionType
 	config["software_version"] = authCode.SoftwareVersion
 	config["max_activations"] = authCode.MaxActivations
 	config["is_locked"] = authCode.IsLocked
 	config["lock_reason"] = authCode.LockReason
+	config["dormant_reclaim_days"] = authCode.DormantReclaimDays
+	config["max_transfers"] = authCode.MaxTransfers
+	config["transfer_period_days"] = authCode.TransferPeriodDays
+	config["use_seat_pool"] = authCode.UseSeatPool
 
 	// JSON配置字段
 	if len(authCode.FeatureConfig) > 0 {
@@ -681,6 +885,13 @@ func (s *authorizationCodeService) buildConfigSnapshot(authCode *models.Authoriz
 		}
 	}
 
+	if len(authCode.ActivationPolicy) > 0 {
+		var activationPolicy map[string]interface{}
+		if err := json.Unmarshal(authCode.ActivationPolicy, &activationPolicy); err == nil {
+			config["activation_policy"] = activationPolicy
+		}
+	}
+
 	return config
 }
 
@@ -703,11 +914,114 @@ func (s *authorizationCodeService) fillAuthCodeDisplayFields(item *models.Author
 	item.CustomerNameDisplay = item.CustomerName // 客户名称暂时不需要翻译
 }
 
-// generateAuthorizationCode 生成授权码
-// 格式: LIC-{4位客户代码}-{12位随机}-{4位校验码}
-// 安全性：12位随机字符（62字符集）提供约 3.23 × 10^21 种可能组合
-func (s *authorizationCodeService) generateAuthorizationCode(customerID string) (string, error) {
-	return utils.GenerateLegacyAuthorizationCode(customerID)
+// authorizationCodeValidity 计算授权码有效期：当天00:00:00开始，有效期最后一天的23:59:59结束
+func authorizationCodeValidity(now time.Time, validityDays int) (time.Time, time.Time) {
+	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endDate := startDate.AddDate(0, 0, validityDays-1).Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+	return startDate, endDate
+}
+
+// authorizationCodeGenerationParams 根据创建请求确定授权码格式与生成参数
+func authorizationCodeGenerationParams(req *models.AuthorizationCodeCreateRequest, endDate time.Time) (string, utils.AuthorizationCodeParams) {
+	var explicitFormat, edition string
+	if req.CodeFormat != nil {
+		explicitFormat = *req.CodeFormat
+	}
+	if req.Edition != nil {
+		edition = *req.Edition
+	}
+	return resolveCodeFormat(explicitFormat, "", req.SoftwareID), utils.AuthorizationCodeParams{
+		CustomerID: req.CustomerID,
+		Edition:    edition,
+		ExpiresAt:  endDate,
+	}
+}
+
+// newAuthorizationCodeEntity 根据创建请求构建授权码实体，JSON配置无法序列化时返回错误
+func newAuthorizationCodeEntity(req *models.AuthorizationCodeCreateRequest, code, codeFormat, operatorID string, startDate, endDate time.Time) (*models.AuthorizationCode, error) {
+	// 处理JSON字段（用于数据库存储）
+	var featureConfig, usageLimits, customParameters models.JSON
+	if req.FeatureConfig != nil {
+		featureConfigBytes, err := json.Marshal(req.FeatureConfig)
+		if err != nil {
+			return nil, err
+		}
+		featureConfig = models.JSON(featureConfigBytes)
+	}
+	if req.UsageLimits != nil {
+		usageLimitsBytes, err := json.Marshal(req.UsageLimits)
+		if err != nil {
+			return nil, err
+		}
+		usageLimits = models.JSON(usageLimitsBytes)
+	}
+	if req.CustomParameters != nil {
+		customParametersBytes, err := json.Marshal(req.CustomParameters)
+		if err != nil {
+			return nil, err
+		}
+		customParameters = models.JSON(customParametersBytes)
+	}
+	activationPolicy, err := marshalActivationPolicy(req.ActivationPolicy)
+	if err != nil {
+		return nil, err
+	}
+	var maintenanceUntil *time.Time
+	if req.MaintenanceUntil != nil {
+		if maintenanceUntil, err = parseMaintenanceUntil(*req.MaintenanceUntil); err != nil {
+			return nil, err
+		}
+	}
+
+	// 设置默认加密类型
+	encryptionType := req.EncryptionType
+	if encryptionType == nil {
+		defaultEncryption := "standard"
+		encryptionType = &defaultEncryption
+	}
+
+	return &models.AuthorizationCode{
+		ID:                 uuid.New().String(), // 生成新的UUID作为主键
+		Code:               code,
+		CustomerID:         req.CustomerID,
+		CreatedBy:          operatorID,
+		SoftwareID:         req.SoftwareID,
+		Description:        req.Description,
+		StartDate:          startDate,
+		EndDate:            endDate,
+		MaintenanceUntil:   maintenanceUntil,
+		DeploymentType:     req.DeploymentType,
+		RelayFingerprint:   normalizeRelayFingerprint(req.RelayFingerprint),
+		EncryptionType:     encryptionType,
+		SoftwareVersion:    req.SoftwareVersion,
+		MaxActivations:     req.MaxActivations,
+		IsLocked:           false,
+		FeatureConfig:      featureConfig,
+		UsageLimits:        usageLimits,
+		CustomParameters:   customParameters,
+		ActivationPolicy:   activationPolicy,
+		DormantReclaimDays: req.DormantReclaimDays,
+		MaxTransfers:       req.MaxTransfers,
+		TransferPeriodDays: req.TransferPeriodDays,
+		CodeFormat:         codeFormat,
+		UseSeatPool:        req.UseSeatPool,
+	}, nil
+}
+
+// generateSharedAuthorizationCode 为分享生成新授权码，沿用原授权码的格式；
+// 签名码沿用原码中的版本标识
+func (s *authorizationCodeService) generateSharedAuthorizationCode(source *models.AuthorizationCode, customerID string) (string, error) {
+	var edition string
+	if source.CodeFormat == utils.AuthorizationCodeFormatSigned {
+		if payload, err := utils.ParseSignedAuthorizationCode(source.Code, nil); err == nil {
+			edition = payload.Edition
+		}
+	}
+	return generateAuthorizationCodeWithFormat(source.CodeFormat, utils.AuthorizationCodeParams{
+		CustomerID: customerID,
+		Edition:    edition,
+		ExpiresAt:  source.EndDate,
+	})
 }
 
 // GetAuthorizationChangeList 查询授权变更历史列表
@@ -723,13 +1037,16 @@ func (s *authorizationCodeService) GetAuthorizationChangeList(ctx context.Contex
 	}
 
 	// 验证授权码是否存在
-	_, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, authCodeID)
+	authCode, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, authCodeID)
 	if err != nil {
 		if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
 			return nil, i18n.NewI18nError("300001", lang) // 授权码不存在
 		}
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if err := s.ensureAuthorizationCodeInScope(ctx, authCode); err != nil {
+		return nil, err
+	}
 
 	// 委托给Repository层进行数据访问
 	result, err := s.authCodeRepo.GetAuthorizationChangeList(ctx, authCodeID, req)
@@ -810,86 +1127,66 @@ func (s *authorizationCodeService) ShareAuthorizationCode(ctx context.Context, a
 		return nil, i18n.NewI18nError("300103", lang) // 分享数量超过可用激活数
 	}
 
-	var newAuthCode *models.AuthorizationCode
-
-	// 开启事务
-	tx := s.authCodeRepo.BeginTransaction(ctx)
-	if tx == nil {
-		return nil, i18n.NewI18nError("300106", lang) // 数据库事务失败
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			if gormTx, ok := tx.(*gorm.DB); ok {
-				gormTx.Rollback()
-			}
-			panic(r)
-		}
-	}()
-
-	// 在事务中执行操作
-	txErr := func() error {
-		// 1. 减少原授权码的max_activations
-		newMaxActivations := authCode.MaxActivations - req.ShareCount
-		err := s.authCodeRepo.UpdateMaxActivationsWithTx(ctx, tx, authCodeID, newMaxActivations)
-		if err != nil {
-			return err
-		}
-
-		// 2. 为目标用户创建新的授权码
-		now := time.Now()
-		code, err := s.generateAuthorizationCode(targetUser.CustomerID)
+	now := time.Now()
+	share := &models.AuthorizationCodeShare{
+		SourceCodeID:     authCode.ID,
+		SourceCustomerID: authCode.CustomerID,
+		SourceUserID:     userID,
+		TargetCustomerID: targetUser.CustomerID,
+		TargetUserID:     targetUser.ID,
+		ShareCount:       req.ShareCount,
+		Mode:             models.AuthorizationCodeShareModeDirect,
+		Status:           string(models.AuthorizationCodeShareStatusAccepted),
+	}
+
+	// 邀请模式：仅创建待确认的分享记录并通知受赠方，席位在对方接受后才转移
+	if req.Mode == models.AuthorizationCodeShareModeInvite {
+		share.Mode = models.AuthorizationCodeShareModeInvite
+		share.Status = string(models.AuthorizationCodeShareStatusPending)
+
+		notification, err := newShareNotification(share.TargetCustomerID, models.NotificationTypeShareInvitation, share, share.ShareCount)
 		if err != nil {
-			return i18n.NewI18nError("900004", lang, err.Error())
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
 		}
-		newAuthCode = &models.AuthorizationCode{
-			Code:             code,
-			CustomerID:       targetUser.CustomerID, // 使用目标用户的客户ID
-			CreatedBy:        targetUser.ID,         // 记录为目标用户创建的
-			SoftwareID:       authCode.SoftwareID,
-			Description:      authCode.Description,
-			StartDate:        now,              // 从分享时刻开始
-			EndDate:          authCode.EndDate, // 到原授权码结束时间
-			DeploymentType:   authCode.DeploymentType,
-			EncryptionType:   authCode.EncryptionType,
-			SoftwareVersion:  authCode.SoftwareVersion,
-			MaxActivations:   req.ShareCount,
-			IsLocked:         false,
-			FeatureConfig:    authCode.FeatureConfig,
-			UsageLimits:      authCode.UsageLimits,
-			CustomParameters: authCode.CustomParameters,
-		}
-
-		err = s.authCodeRepo.CreateAuthorizationCodeWithTx(ctx, tx, newAuthCode)
-		if err != nil {
-			return err
+		if err := s.shareRepo.CreateShare(ctx, share, nil, notification); err != nil {
+			return nil, i18n.NewI18nError("300106", lang, err.Error()) // 数据库事务失败
 		}
 
-		return nil
-	}()
+		share.SourceCode = authCode.Code
+		s.fillShareDisplayFields(share, lang)
+		return &models.AuthorizationCodeShareResponse{Share: share}, nil
+	}
 
-	if txErr != nil {
-		if gormTx, ok := tx.(*gorm.DB); ok {
-			gormTx.Rollback()
-		}
-		return nil, i18n.NewI18nError("300106", lang, txErr.Error()) // 数据库事务失败
+	// 直接分享：扣减原授权码席位并为目标用户创建新的授权码
+	share.RespondedAt = &now
+	newAuthCode, err := s.newSharedAuthorizationCode(authCode, targetUser, req.ShareCount, now)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
-	// 提交事务
-	if gormTx, ok := tx.(*gorm.DB); ok {
-		if err := gormTx.Commit().Error; err != nil {
-			return nil, i18n.NewI18nError("300106", lang, err.Error()) // 数据库事务失败
+	if err := s.shareRepo.CreateShare(ctx, share, newAuthCode, nil); err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeSeatsShort) {
+			return nil, i18n.NewI18nError("300103", lang) // 分享数量超过可用激活数
 		}
+		return nil, i18n.NewI18nError("300106", lang, err.Error()) // 数据库事务失败
 	}
+	s.invalidateEntitlements(ctx, authCode)
+	s.invalidateEntitlements(ctx, newAuthCode)
+
+	share.SourceCode = authCode.Code
+	share.TargetCode = newAuthCode.Code
+	s.fillShareDisplayFields(share, lang)
 
 	// 返回新创建的授权码信息
 	response := &models.AuthorizationCodeShareResponse{
-		NewAuthorizationCode: models.AuthorizationCodeShareResponseItem{
+		NewAuthorizationCode: &models.AuthorizationCodeShareResponseItem{
 			ID:             newAuthCode.ID,
 			Code:           newAuthCode.Code,
 			StartDate:      newAuthCode.StartDate.Format(time.RFC3339),
 			EndDate:        newAuthCode.EndDate.Format(time.RFC3339),
 			MaxActivations: newAuthCode.MaxActivations,
 		},
+		Share: share,
 	}
 
 	return response, nil
@@ -964,5 +1261,12 @@ func (s *authorizationCodeService) GetCuAuthorizationCodeSummary(ctx context.Con
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
+	// 客户设置了席位池时返回席位池使用情况
+	pool, err := s.seatPools.GetSeatPool(ctx, customerID)
+	if err != nil && !errors.Is(err, repository.ErrSeatPoolNotFound) {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	result.SeatPool = pool
+
 	return result, nil
 }
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+)
+
+// newSharedAuthorizationCode 按原授权码配置为受赠用户构建新的授权码，从分享生效时刻开始，到原授权码结束时间
+func (s *authorizationCodeService) newSharedAuthorizationCode(source *models.AuthorizationCode, targetUser *models.CuUser, shareCount int, now time.Time) (*models.AuthorizationCode, error) {
+	code, err := s.generateSharedAuthorizationCode(source, targetUser.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	// 使用目标用户的客户ID，并记录为目标用户创建的
+	return cloneAuthorizationCode(source, code, targetUser.CustomerID, targetUser.ID, now, shareCount), nil
+}
+
+// cloneAuthorizationCode 复制授权码配置生成新的授权码实体（分享、拆分共用），到原授权码结束时间
+func cloneAuthorizationCode(source *models.AuthorizationCode, code, customerID, createdBy string, startDate time.Time, maxActivations int) *models.AuthorizationCode {
+	return &models.AuthorizationCode{
+		Code:               code,
+		CustomerID:         customerID,
+		CreatedBy:          createdBy,
+		SoftwareID:         source.SoftwareID,
+		Description:        source.Description,
+		StartDate:          startDate,
+		EndDate:            source.EndDate,
+		MaintenanceUntil:   source.MaintenanceUntil,
+		DeploymentType:     source.DeploymentType,
+		RelayFingerprint:   source.RelayFingerprint,
+		EncryptionType:     source.EncryptionType,
+		SoftwareVersion:    source.SoftwareVersion,
+		MaxActivations:     maxActivations,
+		IsLocked:           false,
+		FeatureConfig:      source.FeatureConfig,
+		UsageLimits:        source.UsageLimits,
+		CustomParameters:   source.CustomParameters,
+		ActivationPolicy:   source.ActivationPolicy,
+		DormantReclaimDays: source.DormantReclaimDays,
+		MaxTransfers:       source.MaxTransfers,
+		TransferPeriodDays: source.TransferPeriodDays,
+		CodeFormat:         source.CodeFormat,
+	}
+}
+
+// newShareNotification 构建分享相关的客户通知
+func newShareNotification(customerID, notificationType string, share *models.AuthorizationCodeShare, seatCount int) (*models.Notification, error) {
+	return BuildNotification(models.NotificationRecipientCustomer, customerID, notificationType, map[string]interface{}{
+		"share_id":       share.ID,
+		"source_code_id": share.SourceCodeID,
+		"seat_count":     seatCount,
+	})
+}
+
+// fillShareDisplayFields 填充分享记录多语言显示字段
+func (s *authorizationCodeService) fillShareDisplayFields(share *models.AuthorizationCodeShare, lang string) {
+	share.StatusDisplay = i18n.GetEnumMessage("authorization_code_share_status", share.Status, lang)
+}
+
+// GetShareList 查询当前用户分享出去和收到的分享记录
+func (s *authorizationCodeService) GetShareList(ctx context.Context, userID string, req *models.AuthorizationCodeShareListRequest) (*models.AuthorizationCodeShareListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	shares, total, err := s.shareRepo.GetList(ctx, userID, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	for _, share := range shares {
+		s.fillShareDisplayFields(share, lang)
+	}
+
+	return &models.AuthorizationCodeShareListResponse{
+		List:       shares,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+// AcceptShare 受赠方接受分享邀请，此时才从原授权码转移席位
+func (s *authorizationCodeService) AcceptShare(ctx context.Context, shareID, userID string) (*models.AuthorizationCodeShareResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	share, err := s.getPendingShare(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+	if share.TargetUserID != userID {
+		return nil, i18n.NewI18nError("300401", lang) // 分享记录不存在（无权限访问）
+	}
+
+	targetUser, err := s.cuUserRepo.GetByID(userID)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	// 重新校验原授权码状态和可用席位，邀请期间原授权码可能已被锁定或激活
+	source, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, share.SourceCodeID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+			return nil, i18n.NewI18nError("300101", lang) // 授权码不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if source.IsLocked {
+		return nil, i18n.NewI18nError("300102", lang) // 授权码已被锁定
+	}
+	currentActivations, err := s.licenseRepo.GetActiveLicenseCount(ctx, source.ID)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if share.ShareCount > source.MaxActivations-int(currentActivations) {
+		return nil, i18n.NewI18nError("300103", lang) // 分享数量超过可用激活数
+	}
+
+	now := time.Now()
+	newAuthCode, err := s.newSharedAuthorizationCode(source, targetUser, share.ShareCount, now)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	share.Status = string(models.AuthorizationCodeShareStatusAccepted)
+	share.RespondedAt = &now
+	notification, err := newShareNotification(share.SourceCustomerID, models.NotificationTypeShareAccepted, share, share.ShareCount)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	if err := s.shareRepo.AcceptShare(ctx, share, newAuthCode, notification); err != nil {
+		return nil, s.shareRepositoryError(err, lang)
+	}
+	s.invalidateEntitlements(ctx, source)
+	s.invalidateEntitlements(ctx, newAuthCode)
+
+	share.SourceCode = source.Code
+	share.TargetCode = newAuthCode.Code
+	s.fillShareDisplayFields(share, lang)
+
+	return &models.AuthorizationCodeShareResponse{
+		NewAuthorizationCode: &models.AuthorizationCodeShareResponseItem{
+			ID:             newAuthCode.ID,
+			Code:           newAuthCode.Code,
+			StartDate:      newAuthCode.StartDate.Format(time.RFC3339),
+			EndDate:        newAuthCode.EndDate.Format(time.RFC3339),
+			MaxActivations: newAuthCode.MaxActivations,
+		},
+		Share: share,
+	}, nil
+}
+
+// DeclineShare 受赠方拒绝分享邀请
+func (s *authorizationCodeService) DeclineShare(ctx context.Context, shareID, userID string) (*models.AuthorizationCodeShare, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	share, err := s.getPendingShare(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+	if share.TargetUserID != userID {
+		return nil, i18n.NewI18nError("300401", lang) // 分享记录不存在（无权限访问）
+	}
+
+	return s.closePendingShare(ctx, share, models.AuthorizationCodeShareStatusDeclined, true)
+}
+
+// CancelShare 分享方取消尚未被接受的分享邀请
+func (s *authorizationCodeService) CancelShare(ctx context.Context, shareID, userID string) (*models.AuthorizationCodeShare, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	share, err := s.getPendingShare(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+	if share.SourceUserID != userID {
+		return nil, i18n.NewI18nError("300401", lang) // 分享记录不存在（无权限访问）
+	}
+
+	return s.closePendingShare(ctx, share, models.AuthorizationCodeShareStatusCancelled, false)
+}
+
+// ReclaimShare 分享方收回分享授权码中未使用的席位，退回原授权码
+func (s *authorizationCodeService) ReclaimShare(ctx context.Context, shareID, userID string, req *models.AuthorizationCodeShareReclaimRequest) (*models.AuthorizationCodeShare, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	share, err := s.shareRepo.GetByID(ctx, shareID)
+	if err != nil {
+		return nil, s.shareRepositoryError(err, lang)
+	}
+	if share.SourceUserID != userID {
+		return nil, i18n.NewI18nError("300401", lang) // 分享记录不存在（无权限访问）
+	}
+	if share.Status != string(models.AuthorizationCodeShareStatusAccepted) || share.TargetCodeID == nil {
+		return nil, i18n.NewI18nError("300402", lang) // 分享尚未生效
+	}
+
+	target, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, *share.TargetCodeID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+			return nil, i18n.NewI18nError("300101", lang) // 授权码不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	// 只能收回受赠方未激活的席位
+	currentActivations, err := s.licenseRepo.GetActiveLicenseCount(ctx, target.ID)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	unused := target.MaxActivations - int(currentActivations)
+
+	count := unused
+	if req != nil && req.Count != nil {
+		count = *req.Count
+	}
+	if count <= 0 || count > unused {
+		return nil, i18n.NewI18nError("300403", lang) // 可收回的未使用席位不足
+	}
+
+	notification, err := newShareNotification(share.TargetCustomerID, models.NotificationTypeShareReclaimed, share, count)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	if err := s.shareRepo.ReclaimShare(ctx, share, count, notification); err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeSeatsShort) {
+			return nil, i18n.NewI18nError("300403", lang)
+		}
+		return nil, i18n.NewI18nError("300106", lang, err.Error()) // 数据库事务失败
+	}
+	s.invalidateEntitlements(ctx, target)
+	if source, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, share.SourceCodeID); err == nil {
+		s.invalidateEntitlements(ctx, source)
+	}
+
+	share.TargetCode = target.Code
+	s.fillShareDisplayFields(share, lang)
+	return share, nil
+}
+
+// getPendingShare 获取待确认的分享邀请
+func (s *authorizationCodeService) getPendingShare(ctx context.Context, shareID string) (*models.AuthorizationCodeShare, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	share, err := s.shareRepo.GetByID(ctx, shareID)
+	if err != nil {
+		return nil, s.shareRepositoryError(err, lang)
+	}
+	if share.Status != string(models.AuthorizationCodeShareStatusPending) {
+		return nil, i18n.NewI18nError("300402", lang) // 分享邀请已处理
+	}
+	return share, nil
+}
+
+// closePendingShare 拒绝或取消分享邀请，拒绝时通知分享方
+func (s *authorizationCodeService) closePendingShare(ctx context.Context, share *models.AuthorizationCodeShare, status models.AuthorizationCodeShareStatus, notifySource bool) (*models.AuthorizationCodeShare, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	now := time.Now()
+	share.Status = string(status)
+	share.RespondedAt = &now
+
+	var notification *models.Notification
+	if notifySource {
+		var err error
+		notification, err = newShareNotification(share.SourceCustomerID, models.NotificationTypeShareDeclined, share, share.ShareCount)
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+	}
+
+	if err := s.shareRepo.RespondShare(ctx, share, notification); err != nil {
+		return nil, s.shareRepositoryError(err, lang)
+	}
+
+	s.fillShareDisplayFields(share, lang)
+	return share, nil
+}
+
+// shareRepositoryError 将分享仓储错误转换为多语言错误
+func (s *authorizationCodeService) shareRepositoryError(err error, lang string) error {
+	switch {
+	case errors.Is(err, repository.ErrAuthorizationCodeShareNotFound):
+		return i18n.NewI18nError("300401", lang) // 分享记录不存在
+	case errors.Is(err, repository.ErrAuthorizationCodeShareNotPending):
+		return i18n.NewI18nError("300402", lang) // 分享邀请已处理
+	case errors.Is(err, repository.ErrAuthorizationCodeSeatsShort):
+		return i18n.NewI18nError("300103", lang) // 分享数量超过可用激活数
+	default:
+		return i18n.NewI18nError("300106", lang, err.Error()) // 数据库事务失败
+	}
+}
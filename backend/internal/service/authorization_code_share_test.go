@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	"license-manager/pkg/utils"
+)
+
+// stubShareRepository 按ID保存分享记录，仅允许处理待确认的邀请
+type stubShareRepository struct {
+	repository.AuthorizationCodeShareRepository
+	shares        map[string]models.AuthorizationCodeShare
+	notifications []*models.Notification
+	reclaimed     int
+}
+
+func (r *stubShareRepository) GetByID(ctx context.Context, id string) (*models.AuthorizationCodeShare, error) {
+	share, ok := r.shares[id]
+	if !ok {
+		return nil, repository.ErrAuthorizationCodeShareNotFound
+	}
+	return &share, nil
+}
+
+func (r *stubShareRepository) respond(share *models.AuthorizationCodeShare, notification *models.Notification) error {
+	if r.shares[share.ID].Status != string(models.AuthorizationCodeShareStatusPending) {
+		return repository.ErrAuthorizationCodeShareNotPending
+	}
+	r.shares[share.ID] = *share
+	if notification != nil {
+		r.notifications = append(r.notifications, notification)
+	}
+	return nil
+}
+
+func (r *stubShareRepository) AcceptShare(ctx context.Context, share *models.AuthorizationCodeShare, child *models.AuthorizationCode, notification *models.Notification) error {
+	child.ID = "code-shared"
+	share.TargetCodeID = &child.ID
+	return r.respond(share, notification)
+}
+
+func (r *stubShareRepository) RespondShare(ctx context.Context, share *models.AuthorizationCodeShare, notification *models.Notification) error {
+	return r.respond(share, notification)
+}
+
+func (r *stubShareRepository) ReclaimShare(ctx context.Context, share *models.AuthorizationCodeShare, count int, notification *models.Notification) error {
+	r.reclaimed += count
+	return nil
+}
+
+// stubShareAuthCodeRepository 按ID返回授权码
+type stubShareAuthCodeRepository struct {
+	repository.AuthorizationCodeRepository
+	codes map[string]*models.AuthorizationCode
+}
+
+func (r *stubShareAuthCodeRepository) GetAuthorizationCodeByID(ctx context.Context, id string) (*models.AuthorizationCode, error) {
+	code, ok := r.codes[id]
+	if !ok {
+		return nil, repository.ErrAuthorizationCodeNotFound
+	}
+	return code, nil
+}
+
+// stubShareLicenseRepository 按授权码返回激活许可证数量
+type stubShareLicenseRepository struct {
+	repository.LicenseRepository
+	active map[string]int64
+}
+
+func (r *stubShareLicenseRepository) GetActiveLicenseCount(ctx context.Context, authCodeID string) (int64, error) {
+	return r.active[authCodeID], nil
+}
+
+type stubShareCuUserRepository struct {
+	repository.CuUserRepository
+}
+
+func (r *stubShareCuUserRepository) GetByID(id string) (*models.CuUser, error) {
+	return &models.CuUser{ID: id, CustomerID: "cust-" + id}, nil
+}
+
+func newTestShareService(shares ...models.AuthorizationCodeShare) (*authorizationCodeService, *stubShareRepository, *stubShareLicenseRepository) {
+	shareRepo := &stubShareRepository{shares: map[string]models.AuthorizationCodeShare{}}
+	for _, share := range shares {
+		shareRepo.shares[share.ID] = share
+	}
+	licenseRepo := &stubShareLicenseRepository{active: map[string]int64{}}
+	authCodeRepo := &stubShareAuthCodeRepository{codes: map[string]*models.AuthorizationCode{
+		"code-source": {
+			ID:             "code-source",
+			Code:           "SOURCE",
+			CodeFormat:     utils.AuthorizationCodeFormatLegacy,
+			EndDate:        time.Now().AddDate(1, 0, 0),
+			MaxActivations: 5,
+		},
+		"code-target": {ID: "code-target", Code: "TARGET", MaxActivations: 4},
+	}}
+
+	svc := &authorizationCodeService{
+		authCodeRepo: authCodeRepo,
+		cuUserRepo:   &stubShareCuUserRepository{},
+		licenseRepo:  licenseRepo,
+		shareRepo:    shareRepo,
+	}
+	return svc, shareRepo, licenseRepo
+}
+
+func pendingShare(id string) models.AuthorizationCodeShare {
+	return models.AuthorizationCodeShare{
+		ID:               id,
+		SourceCodeID:     "code-source",
+		SourceCustomerID: "cust-source",
+		SourceUserID:     "source",
+		TargetCustomerID: "cust-target",
+		TargetUserID:     "target",
+		ShareCount:       2,
+		Mode:             "invite",
+		Status:           string(models.AuthorizationCodeShareStatusPending),
+	}
+}
+
+func TestShareInviteTransitions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("accept", func(t *testing.T) {
+		svc, repo, _ := newTestShareService(pendingShare("s1"))
+
+		resp, err := svc.AcceptShare(ctx, "s1", "target")
+		if err != nil {
+			t.Fatalf("accept: %v", err)
+		}
+		if resp.NewAuthorizationCode.MaxActivations != 2 {
+			t.Fatalf("shared code seats = %d, want 2", resp.NewAuthorizationCode.MaxActivations)
+		}
+		stored := repo.shares["s1"]
+		if stored.Status != string(models.AuthorizationCodeShareStatusAccepted) || stored.RespondedAt == nil || stored.TargetCodeID == nil {
+			t.Fatalf("share not accepted: %+v", stored)
+		}
+		if len(repo.notifications) != 1 || repo.notifications[0].RecipientID != "cust-source" {
+			t.Fatalf("expected source notification, got %+v", repo.notifications)
+		}
+
+		// 已接受的邀请不能再被拒绝或取消
+		_, err = svc.DeclineShare(ctx, "s1", "target")
+		assertI18nErrorCode(t, err, "300402")
+		_, err = svc.CancelShare(ctx, "s1", "source")
+		assertI18nErrorCode(t, err, "300402")
+	})
+
+	t.Run("accept by source user", func(t *testing.T) {
+		svc, _, _ := newTestShareService(pendingShare("s2"))
+
+		_, err := svc.AcceptShare(ctx, "s2", "source")
+		assertI18nErrorCode(t, err, "300401")
+	})
+
+	t.Run("accept exceeding unused seats", func(t *testing.T) {
+		svc, _, licenses := newTestShareService(pendingShare("s3"))
+		licenses.active["code-source"] = 4
+
+		_, err := svc.AcceptShare(ctx, "s3", "target")
+		assertI18nErrorCode(t, err, "300103")
+	})
+
+	t.Run("decline", func(t *testing.T) {
+		svc, repo, _ := newTestShareService(pendingShare("s4"))
+
+		share, err := svc.DeclineShare(ctx, "s4", "target")
+		if err != nil {
+			t.Fatalf("decline: %v", err)
+		}
+		if share.Status != string(models.AuthorizationCodeShareStatusDeclined) || len(repo.notifications) != 1 {
+			t.Fatalf("unexpected decline result: %+v, notifications %d", share, len(repo.notifications))
+		}
+
+		_, err = svc.AcceptShare(ctx, "s4", "target")
+		assertI18nErrorCode(t, err, "300402")
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		svc, repo, _ := newTestShareService(pendingShare("s5"))
+
+		_, err := svc.CancelShare(ctx, "s5", "target")
+		assertI18nErrorCode(t, err, "300401")
+
+		share, err := svc.CancelShare(ctx, "s5", "source")
+		if err != nil {
+			t.Fatalf("cancel: %v", err)
+		}
+		// 取消不通知任何一方
+		if share.Status != string(models.AuthorizationCodeShareStatusCancelled) || len(repo.notifications) != 0 {
+			t.Fatalf("unexpected cancel result: %+v, notifications %d", share, len(repo.notifications))
+		}
+
+		_, err = svc.AcceptShare(ctx, "s5", "target")
+		assertI18nErrorCode(t, err, "300402")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc, _, _ := newTestShareService()
+
+		_, err := svc.DeclineShare(ctx, "missing", "target")
+		assertI18nErrorCode(t, err, "300401")
+	})
+}
+
+func TestReclaimShareBounds(t *testing.T) {
+	ctx := context.Background()
+	accepted := pendingShare("s1")
+	accepted.Status = string(models.AuthorizationCodeShareStatusAccepted)
+	targetCodeID := "code-target"
+	accepted.TargetCodeID = &targetCodeID
+	count := func(n int) *models.AuthorizationCodeShareReclaimRequest {
+		return &models.AuthorizationCodeShareReclaimRequest{Count: &n}
+	}
+
+	cases := []struct {
+		name    string
+		req     *models.AuthorizationCodeShareReclaimRequest
+		code    string
+		reclaim int
+	}{
+		{"all unused by default", nil, "", 3},
+		{"partial", count(2), "", 2},
+		{"exactly unused", count(3), "", 3},
+		{"more than unused", count(4), "300403", 0},
+		{"zero", count(0), "300403", 0},
+		{"negative", count(-1), "300403", 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			svc, repo, licenses := newTestShareService(accepted)
+			// 分享授权码4个席位已激活1个，可收回3个
+			licenses.active["code-target"] = 1
+
+			_, err := svc.ReclaimShare(ctx, "s1", "source", c.req)
+			if c.code != "" {
+				assertI18nErrorCode(t, err, c.code)
+			} else if err != nil {
+				t.Fatalf("reclaim: %v", err)
+			}
+			if repo.reclaimed != c.reclaim {
+				t.Fatalf("reclaimed %d, want %d", repo.reclaimed, c.reclaim)
+			}
+		})
+	}
+
+	t.Run("pending share", func(t *testing.T) {
+		svc, _, _ := newTestShareService(pendingShare("s2"))
+
+		_, err := svc.ReclaimShare(ctx, "s2", "source", nil)
+		assertI18nErrorCode(t, err, "300402")
+	})
+
+	t.Run("target user", func(t *testing.T) {
+		svc, _, _ := newTestShareService(accepted)
+
+		_, err := svc.ReclaimShare(ctx, "s1", "target", nil)
+		assertI18nErrorCode(t, err, "300401")
+	})
+}
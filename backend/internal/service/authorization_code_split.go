@@ -0,0 +1,316 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+
+	"github.com/google/uuid"
+)
+
+// SplitAuthorizationCode 将授权码拆分为多个授权码
+// 第一部分席位保留在原授权码上，其余部分生成新的授权码；超出原授权码保留席位的已激活许可证按激活顺序迁移到新授权码
+func (s *authorizationCodeService) SplitAuthorizationCode(ctx context.Context, id string, req *models.AuthorizationCodeSplitRequest) (*models.AuthorizationCodeSplitResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 业务逻辑：参数验证
+	if id == "" || req == nil || len(req.Parts) < 2 {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+
+	// 获取当前用户ID
+	currentUserID := pkgcontext.GetUserIDFromContext(ctx)
+	if currentUserID == "" {
+		return nil, i18n.NewI18nError("100004", lang)
+	}
+
+	source, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+			return nil, i18n.NewI18nError("300001", lang) // 授权码不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if source.IsLocked {
+		return nil, i18n.NewI18nError("300003", lang) // 授权码已被锁定
+	}
+
+	// 各部分席位数之和必须等于原授权码席位数
+	seats := make([]int, len(req.Parts))
+	total := 0
+	for i, part := range req.Parts {
+		seats[i] = part.MaxActivations
+		total += part.MaxActivations
+	}
+	if total != source.MaxActivations {
+		return nil, i18n.NewI18nError("300501", lang) // 拆分席位数之和与原授权码不一致
+	}
+
+	// 记录变更前的配置
+	oldConfig := buildConfigSnapshot(source)
+
+	// 原授权码保留第一部分
+	source.MaxActivations = req.Parts[0].MaxActivations
+	if req.Parts[0].Description != nil {
+		source.Description = req.Parts[0].Description
+	}
+
+	children := make([]*models.AuthorizationCode, 0, len(req.Parts)-1)
+	for _, part := range req.Parts[1:] {
+		code, err := s.generateSharedAuthorizationCode(source, source.CustomerID)
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		child := cloneAuthorizationCode(source, code, source.CustomerID, currentUserID, source.StartDate, part.MaxActivations)
+		child.ID = uuid.New().String()
+		if part.Description != nil {
+			child.Description = part.Description
+		}
+		// 拆分后的授权码仍属于同一客户，沿用席位池取用设置
+		child.UseSeatPool = source.UseSeatPool
+		children = append(children, child)
+	}
+
+	// 已激活许可证在事务内锁定原授权码后读取并分配，避免与并发激活交错
+	movedLicenses, err := s.authCodeRepo.SplitAuthorizationCode(ctx, source, children, func(licenseIDs []string) [][]string {
+		return allocateSplitLicenses(licenseIDs, seats)
+	})
+	if err != nil {
+		return nil, splitMergeRepositoryError(err, lang)
+	}
+	s.invalidateEntitlements(ctx, source)
+
+	// 新授权码沿用原授权码的自定义字段和标签
+	childIDs := make([]string, 0, len(children))
+	for _, child := range children {
+		childIDs = append(childIDs, child.ID)
+	}
+	if fields, err := s.customFields.GetEntityCustomFields(ctx, models.CustomFieldEntityAuthorizationCode, []string{source.ID}); err != nil {
+		log.Printf("复制授权码自定义字段失败: %v", err)
+	} else if err := s.customFields.SaveEntityCustomFields(ctx, models.CustomFieldEntityAuthorizationCode, childIDs, fields[source.ID].CustomFields, fields[source.ID].Tags); err != nil {
+		log.Printf("复制授权码自定义字段失败: %v", err)
+	}
+
+	// 记录变更历史：原授权码和每个新授权码各记录一条
+	newConfig := buildConfigSnapshot(source)
+	newConfig["split_into"] = childIDs
+	if err := s.recordAuthorizationChange(ctx, source.ID, "split", req.Reason, currentUserID, oldConfig, newConfig); err != nil {
+		log.Printf("记录授权变更历史失败: %v", err)
+	}
+	for _, child := range children {
+		childConfig := buildConfigSnapshot(child)
+		childConfig["split_from"] = source.ID
+		if err := s.recordAuthorizationChange(ctx, child.ID, "split", req.Reason, currentUserID, nil, childConfig); err != nil {
+			log.Printf("记录授权变更历史失败: %v", err)
+		}
+	}
+
+	// 填充多语言显示字段和计算状态
+	s.fillAuthorizationCodeDisplayFields(source, lang)
+	for _, child := range children {
+		s.fillAuthorizationCodeDisplayFields(child, lang)
+	}
+
+	return &models.AuthorizationCodeSplitResponse{
+		Source:        source,
+		Children:      children,
+		MovedLicenses: movedLicenses,
+	}, nil
+}
+
+// MergeAuthorizationCodes 将同一客户、相同配置的授权码合并到目标授权码
+// 来源授权码的席位和许可证并入目标授权码，目标授权码有效期取所有授权码的并集，来源授权码清零并锁定
+func (s *authorizationCodeService) MergeAuthorizationCodes(ctx context.Context, req *models.AuthorizationCodeMergeRequest) (*models.AuthorizationCodeMergeResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 业务逻辑：参数验证
+	if req == nil || req.TargetID == "" || len(req.SourceIDs) == 0 {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+
+	// 获取当前用户ID
+	currentUserID := pkgcontext.GetUserIDFromContext(ctx)
+	if currentUserID == "" {
+		return nil, i18n.NewI18nError("100004", lang)
+	}
+
+	seen := map[string]bool{req.TargetID: true}
+	for _, sourceID := range req.SourceIDs {
+		if seen[sourceID] {
+			return nil, i18n.NewI18nError("900001", lang, "duplicate authorization code: "+sourceID)
+		}
+		seen[sourceID] = true
+	}
+
+	target, err := s.getMergeableAuthorizationCode(ctx, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]*models.AuthorizationCode, 0, len(req.SourceIDs))
+	for _, sourceID := range req.SourceIDs {
+		source, err := s.getMergeableAuthorizationCode(ctx, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		if !authorizationCodesCompatible(target, source) {
+			return nil, i18n.NewI18nError("300502", lang) // 授权码客户或配置不一致，无法合并
+		}
+		sources = append(sources, source)
+	}
+
+	// 记录变更前的配置
+	now := time.Now()
+	targetOldConfig := buildConfigSnapshot(target)
+	sourceOldConfigs := make([]map[string]interface{}, len(sources))
+
+	mergedIDs := make([]string, 0, len(sources))
+	for i, source := range sources {
+		sourceOldConfigs[i] = buildConfigSnapshot(source)
+
+		// 席位和有效期由仓储在事务内按锁定后的最新数据合并
+		lockReason := fmt.Sprintf("merged into authorization code %s", target.ID)
+		source.LockReason = &lockReason
+		source.LockedAt = &now
+		source.LockedBy = &currentUserID
+
+		mergedIDs = append(mergedIDs, source.ID)
+	}
+
+	moved, err := s.authCodeRepo.MergeAuthorizationCodes(ctx, target, sources)
+	if err != nil {
+		return nil, splitMergeRepositoryError(err, lang)
+	}
+	s.invalidateEntitlements(ctx, target)
+	for _, source := range sources {
+		s.invalidateEntitlements(ctx, source)
+	}
+
+	// 记录变更历史：目标授权码和每个来源授权码各记录一条
+	targetNewConfig := buildConfigSnapshot(target)
+	targetNewConfig["merged_from"] = mergedIDs
+	if err := s.recordAuthorizationChange(ctx, target.ID, "merge", req.Reason, currentUserID, targetOldConfig, targetNewConfig); err != nil {
+		log.Printf("记录授权变更历史失败: %v", err)
+	}
+	for i, source := range sources {
+		sourceNewConfig := buildConfigSnapshot(source)
+		sourceNewConfig["merged_into"] = target.ID
+		if err := s.recordAuthorizationChange(ctx, source.ID, "merge", req.Reason, currentUserID, sourceOldConfigs[i], sourceNewConfig); err != nil {
+			log.Printf("记录授权变更历史失败: %v", err)
+		}
+	}
+
+	// 填充多语言显示字段和计算状态
+	s.fillAuthorizationCodeDisplayFields(target, lang)
+
+	return &models.AuthorizationCodeMergeResponse{
+		Target:        target,
+		MergedCodeIDs: mergedIDs,
+		MovedLicenses: moved,
+	}, nil
+}
+
+// getMergeableAuthorizationCode 获取参与合并的授权码，锁定的授权码不能合并
+func (s *authorizationCodeService) getMergeableAuthorizationCode(ctx context.Context, id string) (*models.AuthorizationCode, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	authCode, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+			return nil, i18n.NewI18nError("300001", lang) // 授权码不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if authCode.IsLocked {
+		return nil, i18n.NewI18nError("300003", lang) // 授权码已被锁定
+	}
+	return authCode, nil
+}
+
+// splitMergeRepositoryError 将拆分、合并事务中的仓储错误转换为多语言错误
+func splitMergeRepositoryError(err error, lang string) error {
+	switch {
+	case errors.Is(err, repository.ErrAuthorizationCodeNotFound):
+		return i18n.NewI18nError("300001", lang) // 授权码不存在
+	case errors.Is(err, repository.ErrAuthorizationCodeLocked):
+		return i18n.NewI18nError("300003", lang) // 授权码已被锁定
+	case errors.Is(err, repository.ErrAuthorizationCodeSeatsChanged):
+		return i18n.NewI18nError("300501", lang) // 拆分席位数之和与原授权码不一致
+	case errors.Is(err, repository.ErrAuthorizationCodeFingerprintConflict):
+		return i18n.NewI18nError("300503", lang) // 授权码下存在相同硬件指纹的设备
+	default:
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+}
+
+// allocateSplitLicenses 按激活顺序把已激活许可证分配给拆分后的各部分
+// 第一部分对应原授权码；席位数不足以容纳的许可证留在原授权码上
+func allocateSplitLicenses(licenseIDs []string, seats []int) [][]string {
+	allocation := make([][]string, len(seats))
+	next := 0
+	for i, count := range seats {
+		end := next + count
+		if end > len(licenseIDs) {
+			end = len(licenseIDs)
+		}
+		allocation[i] = licenseIDs[next:end]
+		next = end
+	}
+	if next < len(licenseIDs) {
+		allocation[0] = append(append([]string{}, allocation[0]...), licenseIDs[next:]...)
+	}
+	return allocation
+}
+
+// authorizationCodesCompatible 判断两个授权码是否属于同一客户且配置一致，可以合并
+func authorizationCodesCompatible(a, b *models.AuthorizationCode) bool {
+	return a.CustomerID == b.CustomerID &&
+		a.DeploymentType == b.DeploymentType &&
+		reflect.DeepEqual(a.RelayFingerprint, b.RelayFingerprint) &&
+		timePtrEqual(a.MaintenanceUntil, b.MaintenanceUntil) &&
+		reflect.DeepEqual(a.SoftwareID, b.SoftwareID) &&
+		reflect.DeepEqual(a.EncryptionType, b.EncryptionType) &&
+		reflect.DeepEqual(a.SoftwareVersion, b.SoftwareVersion) &&
+		reflect.DeepEqual(a.DormantReclaimDays, b.DormantReclaimDays) &&
+		reflect.DeepEqual(a.MaxTransfers, b.MaxTransfers) &&
+		reflect.DeepEqual(a.TransferPeriodDays, b.TransferPeriodDays) &&
+		jsonConfigEqual(a.FeatureConfig, b.FeatureConfig) &&
+		jsonConfigEqual(a.UsageLimits, b.UsageLimits) &&
+		jsonConfigEqual(a.ActivationPolicy, b.ActivationPolicy)
+}
+
+// timePtrEqual 比较两个可为空的时间
+func timePtrEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// jsonConfigEqual 按语义比较两个JSON配置，空值与 null 视为相同
+func jsonConfigEqual(a, b models.JSON) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb interface{}
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &va); err != nil {
+			return false
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &vb); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"license-manager/internal/models"
+)
+
+func TestAllocateSplitLicenses(t *testing.T) {
+	t.Run("fits source", func(t *testing.T) {
+		got := allocateSplitLicenses([]string{"a", "b"}, []int{3, 2})
+		want := [][]string{{"a", "b"}, {}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected allocation: %v", got)
+		}
+	})
+
+	t.Run("overflow to children", func(t *testing.T) {
+		got := allocateSplitLicenses([]string{"a", "b", "c", "d", "e"}, []int{2, 2, 2})
+		want := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected allocation: %v", got)
+		}
+	})
+
+	t.Run("more licenses than seats stay on source", func(t *testing.T) {
+		got := allocateSplitLicenses([]string{"a", "b", "c", "d"}, []int{1, 2})
+		want := [][]string{{"a", "d"}, {"b", "c"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected allocation: %v", got)
+		}
+	})
+}
+
+func TestAuthorizationCodesCompatible(t *testing.T) {
+	softwareID := "app"
+	base := func() *models.AuthorizationCode {
+		return &models.AuthorizationCode{
+			CustomerID:     "c1",
+			SoftwareID:     &softwareID,
+			DeploymentType: "standalone",
+			FeatureConfig:  models.JSON(`{"a":1,"b":2}`),
+		}
+	}
+
+	a, b := base(), base()
+	b.FeatureConfig = models.JSON(`{"b":2, "a":1}`)
+	if !authorizationCodesCompatible(a, b) {
+		t.Fatal("expected codes with equivalent config to be compatible")
+	}
+
+	b.CustomerID = "c2"
+	if authorizationCodesCompatible(a, b) {
+		t.Fatal("expected codes of different customers to be incompatible")
+	}
+
+	b = base()
+	b.UsageLimits = models.JSON(`{"type":"standard"}`)
+	if authorizationCodesCompatible(a, b) {
+		t.Fatal("expected codes with different usage limits to be incompatible")
+	}
+}
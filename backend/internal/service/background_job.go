@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// backgroundJobLease 后台任务（授权码批次生成、客户导入）的执行租约时长，执行中按进度续约
+// 实例退出后租约过期，其他实例或重启后的实例可领取续跑
+const backgroundJobLease = 2 * time.Minute
+
+// resumeUnfinishedJobs 续跑未完成的后台任务：每轮执行 runUnfinished 领取并执行可领取的任务，
+// 仍有未完成的任务（其他实例执行中或租约未过期）时等待一个租约时长后再检查，直到全部完成或服务停止
+func resumeUnfinishedJobs(ctx context.Context, logger *logrus.Logger, name string, runUnfinished func() (int, error)) {
+	for {
+		unfinished, err := runUnfinished()
+		if err != nil {
+			logger.WithError(err).Errorf("查询未完成的%s失败", name)
+			return
+		}
+		if unfinished == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backgroundJobLease):
+		}
+	}
+}
@@ -23,6 +23,8 @@ type CuOrderService interface {
 	GetUserOrders(ctx context.Context, cuUserID string, req *models.CuOrderListRequest) ([]*models.CuOrder, int64, error)
 	GetOrderSummary(ctx context.Context, customerID string) (*models.OrderSummaryResponse, error)
 	CalculatePrice(ctx context.Context, packageID string, licenseCount int) (*PriceCalculationResult, error)
+	// PrepareMaintenanceRenewal 校验续订维护订单并按授权码席位数设置许可数量，新购订单直接返回
+	PrepareMaintenanceRenewal(ctx context.Context, customerID string, req *models.CuOrderCreateRequest) error
 	CancelOrder(ctx context.Context, orderID, cuUserID string) (*models.CuOrder, error)
 	DeleteOrder(ctx context.Context, orderID, cuUserID string) error
 	UpdateOrderStatus(ctx context.Context, orderID string, status string) error
@@ -94,6 +96,11 @@ func NewCuOrderService(
 func (s *cuOrderService) CreatePendingOrder(ctx context.Context, cuUserID, customerID string, req *models.CuOrderCreateRequest) (*models.CuOrder, error) {
 	lang := pkgcontext.GetLanguageFromContext(ctx)
 
+	// 续订维护订单校验
+	if err := s.PrepareMaintenanceRenewal(ctx, customerID, req); err != nil {
+		return nil, err
+	}
+
 	// 计算价格
 	priceResult, err := s.CalculatePrice(ctx, req.PackageID, req.LicenseCount)
 	if err != nil {
@@ -136,7 +143,7 @@ func (s *cuOrderService) CreatePendingOrder(ctx context.Context, cuUserID, custo
 	}
 
 	// 生成订单号
-	orderNo := s.generateOrderNo()
+	orderNo := generateOrderNo()
 
 	// 创建订单（pending状态）
 	order := &models.CuOrder{
@@ -151,6 +158,7 @@ func (s *cuOrderService) CreatePendingOrder(ctx context.Context, cuUserID, custo
 		TotalAmount:  priceResult.TotalAmount,
 		Status:       "pending", // 待支付状态
 	}
+	setOrderType(order, req)
 
 	// 对于试用版，设置过期时间（根据套餐类型判断）
 	if pkgEntity.Type == string(models.PackageTypeTrial) {
@@ -182,7 +190,14 @@ func (s *cuOrderService) CreateOrder(ctx context.Context, cuUserID, customerID s
 	lang := pkgcontext.GetLanguageFromContext(ctx)
 
 	// 参数验证
-	if req == nil || req.PackageID == "" || req.LicenseCount <= 0 {
+	if req == nil || req.PackageID == "" {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+	// 续订维护订单校验
+	if err := s.PrepareMaintenanceRenewal(ctx, customerID, req); err != nil {
+		return nil, err
+	}
+	if req.LicenseCount <= 0 {
 		return nil, i18n.NewI18nError("900001", lang)
 	}
 
@@ -228,7 +243,7 @@ func (s *cuOrderService) CreateOrder(ctx context.Context, cuUserID, customerID s
 	}
 
 	// 生成订单号
-	orderNo := s.generateOrderNo()
+	orderNo := generateOrderNo()
 
 	// 创建订单
 	order := &models.CuOrder{
@@ -243,6 +258,7 @@ func (s *cuOrderService) CreateOrder(ctx context.Context, cuUserID, customerID s
 		TotalAmount:  priceResult.TotalAmount,
 		Status:       "paid", // 直接设置为已支付状态
 	}
+	setOrderType(order, req)
 
 	// 开启事务
 	tx := s.db.Begin()
@@ -263,96 +279,31 @@ func (s *cuOrderService) CreateOrder(ctx context.Context, cuUserID, customerID s
 	now := time.Now()
 	order.UpdatedAt = now
 
-	// 计算授权时间
-	var startDate, endDate time.Time
-	var expiredAt *time.Time
-	if pkgEntity.Type == string(models.PackageTypeTrial) {
-		// 试用版：从今天到当月25日
-		trialExpiry := time.Date(now.Year(), now.Month(), 25, 23, 59, 59, 0, now.Location())
-		if now.Day() > 25 {
-			// 如果今天已经过了25日，则无法购买
+	// 续订维护：顺延已有授权码的维护截止时间，不生成新授权码
+	if order.OrderType == models.CuOrderTypeMaintenanceRenewal {
+		renewedCode, err := renewOrderMaintenance(tx, order, pkgEntity, now)
+		if err != nil {
 			tx.Rollback()
-			return nil, i18n.NewI18nError("600003", lang) // 试用版购买时间限制
-		}
-		startDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-		endDate = trialExpiry
-		expiredAt = &trialExpiry
-	} else {
-		// 永久版：从今天开始，结束时间设为很远的未来
-		startDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-		endDate = startDate.AddDate(1000, 0, 0) // 1000年后
-		expiredAt = nil
-	}
-
-	// 构建授权配置（用于后续生成产品激活码 payload）
-	var featureConfigMap, usageLimitsMap map[string]interface{}
-
-	// 根据套餐类型设置配置信息
-	if pkgEntity.Type == string(models.PackageTypeBasic) {
-		usageLimitsMap = map[string]interface{}{
-			"type": "standard",
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
 		}
-	}
-
-	customParametersMap := map[string]interface{}{
-		"package_id":    req.PackageID,
-		"package_name":  pkgEntity.Name,
-		"license_count": req.LicenseCount,
-	}
-
-	// 授权码生成规则回退/统一为旧规则（不再自包含配置）
-	authCode, err := utils.GenerateLegacyAuthorizationCode(customerID)
-	if err != nil {
-		tx.Rollback()
-		return nil, i18n.NewI18nError("900004", lang, err.Error())
-	}
-
-	// 构建订单描述
-	description := fmt.Sprintf("%s - %d个许可", pkgEntity.Name, req.LicenseCount)
-
-	// 处理JSON字段（用于数据库存储与后续生成产品激活码 payload）
-	var featureConfig, usageLimits, customParameters models.JSON
-
-	b, err := json.Marshal(featureConfigMap)
-	if err != nil {
-		tx.Rollback()
-		return nil, i18n.NewI18nError("900004", lang, err.Error())
-	}
-	featureConfig = models.JSON(b)
-
-	if usageLimitsMap != nil {
-		d, err := json.Marshal(usageLimitsMap)
-		if err != nil {
+		order.AuthorizationCode = &renewedCode.Code
+		if err := tx.Save(order).Error; err != nil {
 			tx.Rollback()
 			return nil, i18n.NewI18nError("900004", lang, err.Error())
 		}
-		usageLimits = models.JSON(d)
+		if err := tx.Commit().Error; err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		return order, nil
 	}
 
-	c, err := json.Marshal(customParametersMap)
+	// 按套餐生成授权码
+	authCodeEntity, expiredAt, err := newPackageAuthorizationCode(pkgEntity, customerID, cuUserID, req.LicenseCount, now)
 	if err != nil {
 		tx.Rollback()
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
-	customParameters = models.JSON(c)
-
-	// 构建授权码实体（使用生成的授权码）
-	authCodeEntity := &models.AuthorizationCode{
-		ID:               uuid.New().String(), // 生成新的UUID作为主键
-		Code:             authCode,
-		CustomerID:       customerID,
-		CreatedBy:        cuUserID,
-		Description:      &description,
-		StartDate:        startDate,
-		EndDate:          endDate,
-		DeploymentType:   "cloud",
-		EncryptionType:   &[]string{"standard"}[0],
-		MaxActivations:   req.LicenseCount,
-		IsLocked:         false,
-		FeatureConfig:    featureConfig,
-		UsageLimits:      usageLimits,
-		CustomParameters: customParameters,
-	}
+	authCode := authCodeEntity.Code
 
 	// 创建授权码
 	if err := s.authCodeRepo.CreateAuthorizationCode(ctx, authCodeEntity); err != nil {
@@ -579,7 +530,7 @@ func (s *cuOrderService) CalculatePrice(ctx context.Context, packageID string, l
 }
 
 // 生成订单号
-func (s *cuOrderService) generateOrderNo() string {
+func generateOrderNo() string {
 	now := time.Now()
 	dateStr := now.Format("20060102")
 
@@ -590,6 +541,112 @@ func (s *cuOrderService) generateOrderNo() string {
 	return fmt.Sprintf("ORD%s%09d", dateStr, nano)
 }
 
+// setOrderType 根据下单请求设置订单类型
+func setOrderType(order *models.CuOrder, req *models.CuOrderCreateRequest) {
+	order.OrderType = models.CuOrderTypeNew
+	if req.OrderType == models.CuOrderTypeMaintenanceRenewal {
+		order.OrderType = models.CuOrderTypeMaintenanceRenewal
+		order.RenewalCodeID = req.AuthorizationCodeID
+	}
+}
+
+// newPackageAuthorizationCode 按套餐构建授权码实体（下单与兑换券兑换共用）
+// 试用版有效期到当月25日，并返回订单到期时间；其他套餐为永久授权
+func newPackageAuthorizationCode(pkgEntity *models.Package, customerID, createdBy string, licenseCount int, now time.Time) (*models.AuthorizationCode, *time.Time, error) {
+	// 计算授权时间
+	var startDate, endDate time.Time
+	var expiredAt *time.Time
+	if pkgEntity.Type == string(models.PackageTypeTrial) {
+		// 试用版：从今天到当月25日
+		trialExpiry := time.Date(now.Year(), now.Month(), 25, 23, 59, 59, 0, now.Location())
+		startDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		endDate = trialExpiry
+		expiredAt = &trialExpiry
+	} else {
+		// 永久版：从今天开始，结束时间设为很远的未来
+		startDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		endDate = startDate.AddDate(1000, 0, 0) // 1000年后
+		expiredAt = nil
+	}
+
+	// 构建授权配置（用于后续生成产品激活码 payload）
+	var featureConfigMap, usageLimitsMap map[string]interface{}
+
+	// 根据套餐类型设置配置信息
+	if pkgEntity.Type == string(models.PackageTypeBasic) {
+		usageLimitsMap = map[string]interface{}{
+			"type": "standard",
+		}
+	}
+
+	customParametersMap := map[string]interface{}{
+		"package_id":    pkgEntity.ID,
+		"package_name":  pkgEntity.Name,
+		"license_count": licenseCount,
+	}
+
+	// 按套餐/系统配置确定授权码格式
+	codeFormat := resolveCodeFormat("", pkgEntity.CodeFormat, nil)
+	authCode, err := generateAuthorizationCodeWithFormat(codeFormat, utils.AuthorizationCodeParams{
+		CustomerID: customerID,
+		Edition:    pkgEntity.Type,
+		ExpiresAt:  endDate,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 构建订单描述
+	description := fmt.Sprintf("%s - %d个许可", pkgEntity.Name, licenseCount)
+
+	// 处理JSON字段（用于数据库存储与后续生成产品激活码 payload）
+	var featureConfig, usageLimits, customParameters models.JSON
+
+	b, err := json.Marshal(featureConfigMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	featureConfig = models.JSON(b)
+
+	if usageLimitsMap != nil {
+		d, err := json.Marshal(usageLimitsMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		usageLimits = models.JSON(d)
+	}
+
+	c, err := json.Marshal(customParametersMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	customParameters = models.JSON(c)
+
+	// 构建授权码实体（使用生成的授权码）
+	authCodeEntity := &models.AuthorizationCode{
+		ID:               uuid.New().String(), // 生成新的UUID作为主键
+		Code:             authCode,
+		CustomerID:       customerID,
+		CreatedBy:        createdBy,
+		Description:      &description,
+		StartDate:        startDate,
+		EndDate:          endDate,
+		DeploymentType:   "cloud",
+		EncryptionType:   &[]string{"standard"}[0],
+		MaxActivations:   licenseCount,
+		IsLocked:         false,
+		FeatureConfig:    featureConfig,
+		UsageLimits:      usageLimits,
+		CustomParameters: customParameters,
+		CodeFormat:       codeFormat,
+	}
+	// 继承套餐的闲置席位回收策略和维护期
+	authCodeEntity.DormantReclaimDays = pkgEntity.DormantReclaimDays
+	authCodeEntity.MaintenanceUntil = packageMaintenanceUntil(pkgEntity, startDate)
+
+	return authCodeEntity, expiredAt, nil
+}
+
 // getPackageForOrder 从数据库获取套餐配置，并解析最大许可数量
 func (s *cuOrderService) getPackageForOrder(ctx context.Context, packageID string) (*models.Package, int, error) {
 	lang := pkgcontext.GetLanguageFromContext(ctx)
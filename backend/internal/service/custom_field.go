@@ -0,0 +1,527 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+)
+
+const (
+	customFieldValueMaxLength = 500 // 字段值最大长度（字符）
+	entityTagMaxCount         = 20  // 单个对象最多标签数
+	entityTagMaxLength        = 50  // 标签最大长度（字符）
+)
+
+// 导入导出文件中的标签列和自定义字段列
+const (
+	entityTagColumn         = "tags" // 标签列，多个标签以分号分隔
+	entityTagSeparator      = ";"
+	customFieldColumnPrefix = "cf:" // 自定义字段列名前缀，后接字段标识
+)
+
+// customFieldKeyPattern 字段标识：小写字母开头，只能包含小写字母、数字和下划线
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// CustomFieldService 自定义字段与标签服务接口
+type CustomFieldService interface {
+	GetDefinitions(ctx context.Context, req *models.CustomFieldDefinitionListRequest) ([]*models.CustomFieldDefinition, error)
+	CreateDefinition(ctx context.Context, operatorID string, req *models.CustomFieldDefinitionCreateRequest) (*models.CustomFieldDefinition, error)
+	UpdateDefinition(ctx context.Context, id string, req *models.CustomFieldDefinitionUpdateRequest) (*models.CustomFieldDefinition, error)
+	DeleteDefinition(ctx context.Context, id string) error
+	GetTags(ctx context.Context, req *models.TagListRequest) ([]*models.TagCount, error)
+}
+
+type customFieldService struct {
+	customFieldRepo repository.CustomFieldRepository
+}
+
+// NewCustomFieldService 创建自定义字段服务
+func NewCustomFieldService(customFieldRepo repository.CustomFieldRepository) CustomFieldService {
+	return &customFieldService{
+		customFieldRepo: customFieldRepo,
+	}
+}
+
+// GetDefinitions 查询自定义字段定义，按对象类型和排序返回
+func (s *customFieldService) GetDefinitions(ctx context.Context, req *models.CustomFieldDefinitionListRequest) ([]*models.CustomFieldDefinition, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	definitions, err := s.customFieldRepo.GetDefinitions(ctx, req.EntityType)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	for _, definition := range definitions {
+		fillCustomFieldDisplay(definition, lang)
+	}
+	return definitions, nil
+}
+
+// CreateDefinition 创建自定义字段定义
+func (s *customFieldService) CreateDefinition(ctx context.Context, operatorID string, req *models.CustomFieldDefinitionCreateRequest) (*models.CustomFieldDefinition, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if operatorID == "" {
+		return nil, i18n.NewI18nError("100004", lang)
+	}
+
+	definition := &models.CustomFieldDefinition{
+		EntityType:     req.EntityType,
+		FieldKey:       strings.TrimSpace(req.FieldKey),
+		Name:           strings.TrimSpace(req.Name),
+		FieldType:      req.FieldType,
+		Required:       req.Required,
+		EmbedInLicense: req.EmbedInLicense,
+		SortOrder:      req.SortOrder,
+		Description:    req.Description,
+		CreatedBy:      operatorID,
+	}
+	if err := applyCustomFieldRules(definition, req.Options, req.Pattern); err != nil {
+		return nil, i18n.NewI18nError("900001", lang, err.Error())
+	}
+	if err := checkCustomFieldDefinition(definition); err != nil {
+		return nil, i18n.NewI18nError("900001", lang, err.Error())
+	}
+
+	if err := s.customFieldRepo.CreateDefinition(ctx, definition); err != nil {
+		if errors.Is(err, repository.ErrCustomFieldKeyExists) {
+			return nil, i18n.NewI18nError("660002", lang) // 字段标识已存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	fillCustomFieldDisplay(definition, lang)
+	return definition, nil
+}
+
+// UpdateDefinition 更新自定义字段定义，对象类型、字段标识和字段类型不可修改
+func (s *customFieldService) UpdateDefinition(ctx context.Context, id string, req *models.CustomFieldDefinitionUpdateRequest) (*models.CustomFieldDefinition, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	definition, err := s.getDefinition(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		definition.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Required != nil {
+		definition.Required = *req.Required
+	}
+	if req.EmbedInLicense != nil {
+		definition.EmbedInLicense = *req.EmbedInLicense
+	}
+	if req.SortOrder != nil {
+		definition.SortOrder = *req.SortOrder
+	}
+	if req.Description != nil {
+		definition.Description = req.Description
+	}
+	options := customFieldOptions(definition)
+	if req.Options != nil {
+		options = req.Options
+	}
+	pattern := definition.Pattern
+	if req.Pattern != nil {
+		pattern = req.Pattern
+	}
+	if err := applyCustomFieldRules(definition, options, pattern); err != nil {
+		return nil, i18n.NewI18nError("900001", lang, err.Error())
+	}
+	if err := checkCustomFieldDefinition(definition); err != nil {
+		return nil, i18n.NewI18nError("900001", lang, err.Error())
+	}
+
+	if err := s.customFieldRepo.UpdateDefinition(ctx, definition); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	fillCustomFieldDisplay(definition, lang)
+	return definition, nil
+}
+
+// DeleteDefinition 删除自定义字段定义，所有对象上该字段的值一并删除
+func (s *customFieldService) DeleteDefinition(ctx context.Context, id string) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	definition, err := s.getDefinition(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.customFieldRepo.DeleteDefinition(ctx, definition); err != nil {
+		if errors.Is(err, repository.ErrCustomFieldNotFound) {
+			return i18n.NewI18nError("660001", lang)
+		}
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return nil
+}
+
+// GetTags 查询对象类型下已使用的标签及使用次数（用于标签输入提示）
+func (s *customFieldService) GetTags(ctx context.Context, req *models.TagListRequest) ([]*models.TagCount, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	counts, err := s.customFieldRepo.GetTagCounts(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return counts, nil
+}
+
+func (s *customFieldService) getDefinition(ctx context.Context, id string) (*models.CustomFieldDefinition, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	definition, err := s.customFieldRepo.GetDefinitionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomFieldNotFound) {
+			return nil, i18n.NewI18nError("660001", lang) // 自定义字段不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return definition, nil
+}
+
+func fillCustomFieldDisplay(definition *models.CustomFieldDefinition, lang string) {
+	definition.EntityTypeDisplay = i18n.GetEnumMessage("custom_field_entity_type", definition.EntityType, lang)
+	definition.FieldTypeDisplay = i18n.GetEnumMessage("custom_field_type", definition.FieldType, lang)
+}
+
+// applyCustomFieldRules 设置单选字段的可选值和文本字段的正则表达式，空正则表示不限制
+func applyCustomFieldRules(definition *models.CustomFieldDefinition, options []string, pattern *string) error {
+	definition.Options = nil
+	if len(options) > 0 {
+		trimmed := make([]string, 0, len(options))
+		for _, option := range options {
+			option = strings.TrimSpace(option)
+			if option == "" || slices.Contains(trimmed, option) {
+				return fmt.Errorf("options must be non-empty and unique: %q", option)
+			}
+			trimmed = append(trimmed, option)
+		}
+		data, err := json.Marshal(trimmed)
+		if err != nil {
+			return err
+		}
+		definition.Options = models.JSON(data)
+	}
+
+	definition.Pattern = nil
+	if pattern != nil && strings.TrimSpace(*pattern) != "" {
+		value := strings.TrimSpace(*pattern)
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		definition.Pattern = &value
+	}
+	return nil
+}
+
+// checkCustomFieldDefinition 校验字段定义的组合规则
+func checkCustomFieldDefinition(definition *models.CustomFieldDefinition) error {
+	if !customFieldKeyPattern.MatchString(definition.FieldKey) {
+		return errors.New("field_key must start with a lowercase letter and contain only lowercase letters, digits and underscores")
+	}
+	if definition.Name == "" {
+		return errors.New("name is required")
+	}
+	hasOptions := len(customFieldOptions(definition)) > 0
+	if definition.FieldType == models.CustomFieldTypeSelect && !hasOptions {
+		return errors.New("options are required for select fields")
+	}
+	if definition.FieldType != models.CustomFieldTypeSelect && hasOptions {
+		return errors.New("options are only allowed for select fields")
+	}
+	if definition.Pattern != nil && definition.FieldType != models.CustomFieldTypeText {
+		return errors.New("pattern is only allowed for text fields")
+	}
+	if definition.EmbedInLicense && definition.EntityType != models.CustomFieldEntityAuthorizationCode {
+		return errors.New("embed_in_license is only allowed for authorization code fields")
+	}
+	return nil
+}
+
+func customFieldOptions(definition *models.CustomFieldDefinition) []string {
+	var options []string
+	if len(definition.Options) > 0 {
+		_ = json.Unmarshal(definition.Options, &options)
+	}
+	return options
+}
+
+// normalizeCustomFieldValue 按字段类型校验并规范化单个字段值
+func normalizeCustomFieldValue(definition *models.CustomFieldDefinition, raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	switch definition.FieldType {
+	case models.CustomFieldTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsInf(number, 0) || math.IsNaN(number) {
+			return "", errors.New("must be a number")
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case models.CustomFieldTypeBoolean:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", errors.New("must be true or false")
+		}
+		return strconv.FormatBool(b), nil
+	case models.CustomFieldTypeDate:
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return "", errors.New("must be a date in YYYY-MM-DD format")
+		}
+		return date.Format("2006-01-02"), nil
+	case models.CustomFieldTypeSelect:
+		options := customFieldOptions(definition)
+		if !slices.Contains(options, value) {
+			return "", fmt.Errorf("must be one of: %s", strings.Join(options, ", "))
+		}
+		return value, nil
+	default:
+		if utf8.RuneCountInString(value) > customFieldValueMaxLength {
+			return "", fmt.Errorf("must be at most %d characters", customFieldValueMaxLength)
+		}
+		if definition.Pattern != nil {
+			if re, err := regexp.Compile(*definition.Pattern); err == nil && !re.MatchString(value) {
+				return "", fmt.Errorf("must match pattern %s", *definition.Pattern)
+			}
+		}
+		return value, nil
+	}
+}
+
+// normalizeCustomFieldValues 按字段定义校验并规范化字段值，返回规范化后的值和 "字段标识: 原因" 形式的错误
+// creating 为 true（创建对象）时必填字段必须提供、空值被忽略；否则空值表示清除该字段，必填字段不能清除
+func normalizeCustomFieldValues(definitions []*models.CustomFieldDefinition, values map[string]string, creating bool) (map[string]string, []string) {
+	byKey := make(map[string]*models.CustomFieldDefinition, len(definitions))
+	for _, definition := range definitions {
+		byKey[definition.FieldKey] = definition
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	normalized := make(map[string]string, len(values))
+	var messages []string
+	for _, key := range keys {
+		definition, ok := byKey[key]
+		if !ok {
+			messages = append(messages, key+": unknown field")
+			continue
+		}
+		if strings.TrimSpace(values[key]) == "" {
+			if definition.Required {
+				messages = append(messages, key+": required")
+			} else if !creating {
+				normalized[key] = ""
+			}
+			continue
+		}
+		value, err := normalizeCustomFieldValue(definition, values[key])
+		if err != nil {
+			messages = append(messages, key+": "+err.Error())
+			continue
+		}
+		normalized[key] = value
+	}
+
+	if creating {
+		for _, definition := range definitions {
+			if _, ok := values[definition.FieldKey]; definition.Required && !ok {
+				messages = append(messages, definition.FieldKey+": required")
+			}
+		}
+	}
+	return normalized, messages
+}
+
+// normalizeEntityTags 去除标签首尾空白、空标签和重复标签（不区分大小写，保留首次出现的写法），nil 表示不修改标签
+func normalizeEntityTags(tags []string) ([]string, []string) {
+	if tags == nil {
+		return nil, nil
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	var messages []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > entityTagMaxLength {
+			messages = append(messages, fmt.Sprintf("tags: %q must be at most %d characters", tag, entityTagMaxLength))
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > entityTagMaxCount {
+		messages = append(messages, fmt.Sprintf("tags: at most %d tags are allowed", entityTagMaxCount))
+	}
+	return normalized, messages
+}
+
+// prepareEntityCustomFields 校验并规范化对象的自定义字段和标签，校验失败时返回 660003 并附带逐项原因
+func prepareEntityCustomFields(ctx context.Context, repo repository.CustomFieldRepository, entityType string, values map[string]string, tags []string, creating bool) (map[string]string, []string, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	var definitions []*models.CustomFieldDefinition
+	if len(values) > 0 || creating {
+		var err error
+		definitions, err = repo.GetDefinitions(ctx, entityType)
+		if err != nil {
+			return nil, nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+	}
+
+	normalizedValues, messages := normalizeCustomFieldValues(definitions, values, creating)
+	normalizedTags, tagMessages := normalizeEntityTags(tags)
+	messages = append(messages, tagMessages...)
+	if len(messages) > 0 {
+		return nil, nil, i18n.NewI18nError("660003", lang, i18n.GetI18nErrorMessage("660003", lang)+": "+strings.Join(messages, "; "))
+	}
+	return normalizedValues, normalizedTags, nil
+}
+
+// updateEntityCustomFields 校验并保存对象的自定义字段和标签，返回保存后的全部字段和标签
+func updateEntityCustomFields(ctx context.Context, repo repository.CustomFieldRepository, entityType, entityID string, req *models.EntityCustomFieldsUpdateRequest) (*models.EntityCustomFields, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	values, tags, err := prepareEntityCustomFields(ctx, repo, entityType, req.CustomFields, req.Tags, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.SaveEntityCustomFields(ctx, entityType, []string{entityID}, values, tags); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	fields, err := repo.GetEntityCustomFields(ctx, entityType, []string{entityID})
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return fields[entityID], nil
+}
+
+// normalizeCustomFieldFilter 校验列表筛选中的自定义字段并按字段类型规范化筛选值，使其与保存的值一致
+func normalizeCustomFieldFilter(ctx context.Context, repo repository.CustomFieldRepository, entityType string, filter *models.CustomFieldFilter) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if len(filter.CustomFields) == 0 {
+		return nil
+	}
+	definitions, err := repo.GetDefinitions(ctx, entityType)
+	if err != nil {
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	values := make(map[string]string, len(filter.CustomFields))
+	for key, value := range filter.CustomFields {
+		if strings.TrimSpace(value) != "" {
+			values[key] = value
+		}
+	}
+	normalized, messages := normalizeCustomFieldValues(definitions, values, false)
+	if len(messages) > 0 {
+		return i18n.NewI18nError("900001", lang, "custom_fields: "+strings.Join(messages, "; "))
+	}
+	filter.CustomFields = normalized
+	return nil
+}
+
+// licenseCustomFields 返回需要写入签名许可证文件的授权码自定义字段，没有时返回 nil
+func licenseCustomFields(ctx context.Context, repo repository.CustomFieldRepository, authCodeID string) (map[string]string, error) {
+	definitions, err := repo.GetDefinitions(ctx, models.CustomFieldEntityAuthorizationCode)
+	if err != nil {
+		return nil, err
+	}
+	embedded := make(map[string]bool)
+	for _, definition := range definitions {
+		if definition.EmbedInLicense {
+			embedded[definition.FieldKey] = true
+		}
+	}
+	if len(embedded) == 0 {
+		return nil, nil
+	}
+
+	fields, err := repo.GetEntityCustomFields(ctx, models.CustomFieldEntityAuthorizationCode, []string{authCodeID})
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	for key, value := range fields[authCodeID].CustomFields {
+		if embedded[key] {
+			result[key] = value
+		}
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// embedsLicenseCustomFields 修改的字段中是否包含写入许可证文件的字段
+func embedsLicenseCustomFields(ctx context.Context, repo repository.CustomFieldRepository, values map[string]string) (bool, error) {
+	if len(values) == 0 {
+		return false, nil
+	}
+	definitions, err := repo.GetDefinitions(ctx, models.CustomFieldEntityAuthorizationCode)
+	if err != nil {
+		return false, err
+	}
+	for _, definition := range definitions {
+		if _, ok := values[definition.FieldKey]; ok && definition.EmbedInLicense {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// customFieldColumns 导出文件中标签列和自定义字段列的表头
+func customFieldColumns(definitions []*models.CustomFieldDefinition) []string {
+	columns := make([]string, 0, len(definitions)+1)
+	columns = append(columns, entityTagColumn)
+	for _, definition := range definitions {
+		columns = append(columns, customFieldColumnPrefix+definition.FieldKey)
+	}
+	return columns
+}
+
+// customFieldCells 导出一个对象的标签和自定义字段，列顺序与 customFieldColumns 一致
+func customFieldCells(definitions []*models.CustomFieldDefinition, fields *models.EntityCustomFields) []string {
+	cells := make([]string, 0, len(definitions)+1)
+	if fields == nil {
+		fields = &models.EntityCustomFields{}
+	}
+	cells = append(cells, sanitizeSpreadsheetCell(strings.Join(fields.Tags, entityTagSeparator)))
+	for _, definition := range definitions {
+		cells = append(cells, sanitizeSpreadsheetCell(fields.CustomFields[definition.FieldKey]))
+	}
+	return cells
+}
+
+// splitEntityTags 拆分导入文件中以分号分隔的标签
+func splitEntityTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, entityTagSeparator) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"license-manager/internal/models"
+)
+
+func TestNormalizeCustomFieldValue(t *testing.T) {
+	pattern := `^HT-\d+$`
+	definitions := map[string]*models.CustomFieldDefinition{
+		"number":  {FieldType: models.CustomFieldTypeNumber},
+		"boolean": {FieldType: models.CustomFieldTypeBoolean},
+		"date":    {FieldType: models.CustomFieldTypeDate},
+		"select":  {FieldType: models.CustomFieldTypeSelect, Options: models.JSON(`["east","west"]`)},
+		"text":    {FieldType: models.CustomFieldTypeText, Pattern: &pattern},
+	}
+	valid := []struct{ field, input, want string }{
+		{"number", " 1.50 ", "1.5"},
+		{"number", "-3", "-3"},
+		{"boolean", "1", "true"},
+		{"boolean", "FALSE", "false"},
+		{"date", "2026-01-31", "2026-01-31"},
+		{"select", "west", "west"},
+		{"text", "HT-2024", "HT-2024"},
+	}
+	for _, c := range valid {
+		got, err := normalizeCustomFieldValue(definitions[c.field], c.input)
+		if err != nil || got != c.want {
+			t.Errorf("%s(%q) = %q, %v; want %q", c.field, c.input, got, err, c.want)
+		}
+	}
+
+	invalid := []struct{ field, input string }{
+		{"number", "abc"},
+		{"number", "NaN"},
+		{"boolean", "maybe"},
+		{"date", "2026-02-30"},
+		{"select", "north"},
+		{"text", "contract 1"},
+	}
+	for _, c := range invalid {
+		if _, err := normalizeCustomFieldValue(definitions[c.field], c.input); err == nil {
+			t.Errorf("%s(%q): expected error", c.field, c.input)
+		}
+	}
+
+	if _, err := normalizeCustomFieldValue(&models.CustomFieldDefinition{FieldType: models.CustomFieldTypeText}, strings.Repeat("x", customFieldValueMaxLength+1)); err == nil {
+		t.Error("overlong text: expected error")
+	}
+}
+
+func TestNormalizeCustomFieldValues(t *testing.T) {
+	definitions := []*models.CustomFieldDefinition{
+		{FieldKey: "contract_no", FieldType: models.CustomFieldTypeText, Required: true},
+		{FieldKey: "seats", FieldType: models.CustomFieldTypeNumber},
+		{FieldKey: "region", FieldType: models.CustomFieldTypeText},
+	}
+
+	values, messages := normalizeCustomFieldValues(definitions, map[string]string{"seats": "010", "region": ""}, true)
+	if !reflect.DeepEqual(values, map[string]string{"seats": "10"}) {
+		t.Errorf("creating values = %v, want seats=10", values)
+	}
+	if !reflect.DeepEqual(messages, []string{"contract_no: required"}) {
+		t.Errorf("creating messages = %q, want missing contract_no", messages)
+	}
+
+	values, messages = normalizeCustomFieldValues(definitions, map[string]string{"region": "", "seats": "x", "owner": "bob", "contract_no": " "}, false)
+	if !reflect.DeepEqual(values, map[string]string{"region": ""}) {
+		t.Errorf("updating values = %v, want region cleared", values)
+	}
+	want := []string{"contract_no: required", "owner: unknown field", "seats: must be a number"}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("updating messages = %q, want %q", messages, want)
+	}
+}
+
+func TestNormalizeEntityTags(t *testing.T) {
+	if tags, messages := normalizeEntityTags(nil); tags != nil || messages != nil {
+		t.Errorf("nil tags = %v, %v; want unchanged", tags, messages)
+	}
+
+	tags, messages := normalizeEntityTags([]string{" VIP ", "vip", "", "renewal"})
+	if !reflect.DeepEqual(tags, []string{"VIP", "renewal"}) || len(messages) != 0 {
+		t.Errorf("tags = %q, %q; want [VIP renewal]", tags, messages)
+	}
+
+	many := make([]string, entityTagMaxCount+1)
+	for i := range many {
+		many[i] = strings.Repeat("t", i+1)
+	}
+	if _, messages := normalizeEntityTags(many); len(messages) != 1 {
+		t.Errorf("too many tags: messages = %q", messages)
+	}
+	if _, messages := normalizeEntityTags([]string{strings.Repeat("长", entityTagMaxLength+1)}); len(messages) != 1 {
+		t.Errorf("overlong tag: messages = %q", messages)
+	}
+}
+
+func TestCheckCustomFieldDefinition(t *testing.T) {
+	pattern := ".*"
+	valid := []*models.CustomFieldDefinition{
+		{EntityType: models.CustomFieldEntityCustomer, FieldKey: "region", Name: "Region", FieldType: models.CustomFieldTypeText, Pattern: &pattern},
+		{EntityType: models.CustomFieldEntityCustomer, FieldKey: "tier", Name: "Tier", FieldType: models.CustomFieldTypeSelect, Options: models.JSON(`["a"]`)},
+		{EntityType: models.CustomFieldEntityAuthorizationCode, FieldKey: "contract_no", Name: "Contract", FieldType: models.CustomFieldTypeText, EmbedInLicense: true},
+	}
+	for _, definition := range valid {
+		if err := checkCustomFieldDefinition(definition); err != nil {
+			t.Errorf("%s: unexpected error %v", definition.FieldKey, err)
+		}
+	}
+
+	invalid := map[string]*models.CustomFieldDefinition{
+		"bad key":            {EntityType: models.CustomFieldEntityCustomer, FieldKey: "Region", Name: "Region", FieldType: models.CustomFieldTypeText},
+		"select no options":  {EntityType: models.CustomFieldEntityCustomer, FieldKey: "tier", Name: "Tier", FieldType: models.CustomFieldTypeSelect},
+		"options on number":  {EntityType: models.CustomFieldEntityCustomer, FieldKey: "n", Name: "N", FieldType: models.CustomFieldTypeNumber, Options: models.JSON(`["1"]`)},
+		"pattern on date":    {EntityType: models.CustomFieldEntityCustomer, FieldKey: "d", Name: "D", FieldType: models.CustomFieldTypeDate, Pattern: &pattern},
+		"embed on customer":  {EntityType: models.CustomFieldEntityCustomer, FieldKey: "c", Name: "C", FieldType: models.CustomFieldTypeText, EmbedInLicense: true},
+		"empty display name": {EntityType: models.CustomFieldEntityLicense, FieldKey: "x", FieldType: models.CustomFieldTypeText},
+	}
+	for name, definition := range invalid {
+		if err := checkCustomFieldDefinition(definition); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestApplyCustomFieldRules(t *testing.T) {
+	definition := &models.CustomFieldDefinition{}
+	blank := " "
+	if err := applyCustomFieldRules(definition, []string{" east ", "west"}, &blank); err != nil {
+		t.Fatalf("applyCustomFieldRules: %v", err)
+	}
+	if !reflect.DeepEqual(customFieldOptions(definition), []string{"east", "west"}) || definition.Pattern != nil {
+		t.Errorf("options = %q, pattern = %v", customFieldOptions(definition), definition.Pattern)
+	}
+
+	if err := applyCustomFieldRules(definition, []string{"a", "a"}, nil); err == nil {
+		t.Error("duplicate options: expected error")
+	}
+	badPattern := "("
+	if err := applyCustomFieldRules(definition, nil, &badPattern); err == nil {
+		t.Error("invalid pattern: expected error")
+	}
+}
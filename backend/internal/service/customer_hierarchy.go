@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+)
+
+// customerTree 客户上下级关系，遍历时记录已访问节点，数据中即使存在环也不会死循环
+type customerTree struct {
+	parents  map[string]string
+	children map[string][]string
+}
+
+func newCustomerTree(parents map[string]string) *customerTree {
+	children := make(map[string][]string)
+	for id, parentID := range parents {
+		children[parentID] = append(children[parentID], id)
+	}
+	for _, ids := range children {
+		sort.Strings(ids)
+	}
+	return &customerTree{parents: parents, children: children}
+}
+
+// subtree 返回客户及其所有层级的下级客户ID（客户自身在第一个）
+func (t *customerTree) subtree(id string) []string {
+	ids := []string{id}
+	visited := map[string]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range t.children[ids[i]] {
+			if !visited[child] {
+				visited[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// ancestors 返回客户的上级客户ID，从最顶层开始
+func (t *customerTree) ancestors(id string) []string {
+	var ids []string
+	visited := map[string]bool{id: true}
+	for parentID, ok := t.parents[id]; ok && !visited[parentID]; parentID, ok = t.parents[parentID] {
+		visited[parentID] = true
+		ids = append([]string{parentID}, ids...)
+	}
+	return ids
+}
+
+// rollUpAuthorizationStats 汇总多个客户的授权统计
+func rollUpAuthorizationStats(statsByCustomer map[string]*models.AuthorizationStats, customerIDs []string) *models.AuthorizationStats {
+	total := &models.AuthorizationStats{}
+	for _, id := range customerIDs {
+		stats, ok := statsByCustomer[id]
+		if !ok {
+			continue
+		}
+		total.TotalAuthCodes += stats.TotalAuthCodes
+		total.ExpiredAuthCodes += stats.ExpiredAuthCodes
+		total.ExpiringSoonAuthCodes += stats.ExpiringSoonAuthCodes
+		total.TotalLicenses += stats.TotalLicenses
+		total.ActiveLicenses += stats.ActiveLicenses
+		total.InactiveLicenses += stats.InactiveLicenses
+		total.ExpiredLicenses += stats.ExpiredLicenses
+	}
+	return total
+}
+
+// loadCustomerSubtrees 加载指定客户及其全部下级客户的层级关系（数据库递归查询，不加载全部客户）
+func loadCustomerSubtrees(ctx context.Context, customerRepo repository.CustomerRepository, rootIDs ...string) (*customerTree, error) {
+	parents, err := customerRepo.GetCustomerSubtreeParents(ctx, rootIDs)
+	if err != nil {
+		return nil, err
+	}
+	return newCustomerTree(parents), nil
+}
+
+// loadCustomerAncestors 加载客户到最顶层上级客户的层级关系
+func loadCustomerAncestors(ctx context.Context, customerRepo repository.CustomerRepository, customerID string) (*customerTree, error) {
+	parents, err := customerRepo.GetCustomerAncestorParents(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return newCustomerTree(parents), nil
+}
+
+// expandCustomerSubtree 列表按客户筛选且要求包含下级客户时，展开为客户ID范围
+func expandCustomerSubtree(ctx context.Context, customerRepo repository.CustomerRepository, customerID string) ([]string, error) {
+	tree, err := loadCustomerSubtrees(ctx, customerRepo, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return tree.subtree(customerID), nil
+}
+
+// GetCustomerHierarchy 获取客户的上级路径和下级客户树
+func (s *customerService) GetCustomerHierarchy(ctx context.Context, id string) (*models.CustomerHierarchyResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	customer, err := s.customerRepo.GetCustomerByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomerNotFound) {
+			return nil, i18n.NewI18nError("200001", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	partnerID := pkgcontext.GetPartnerIDFromContext(ctx)
+	if !customerInPartnerScope(partnerID, customer) {
+		return nil, i18n.NewI18nError("200001", lang)
+	}
+
+	parents, err := s.customerRepo.GetCustomerAncestorParents(ctx, id)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	subtreeParents, err := s.customerRepo.GetCustomerSubtreeParents(ctx, []string{id})
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	for childID, parentID := range subtreeParents {
+		parents[childID] = parentID
+	}
+	tree := newCustomerTree(parents)
+	ancestorIDs := tree.ancestors(id)
+	subtreeIDs := tree.subtree(id)
+
+	customers, err := s.customerRepo.GetCustomersByIDs(ctx, append(append([]string{}, ancestorIDs...), subtreeIDs...))
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	nodes := make(map[string]*models.CustomerHierarchyNode, len(customers))
+	for _, customer := range customers {
+		// 经销商账号只展示自己名下的客户
+		if !customerInPartnerScope(partnerID, customer) {
+			continue
+		}
+		nodes[customer.ID] = &models.CustomerHierarchyNode{
+			ID:            customer.ID,
+			CustomerCode:  customer.CustomerCode,
+			CustomerName:  customer.CustomerName,
+			Status:        customer.Status,
+			StatusDisplay: i18n.GetEnumMessage("customer_status", customer.Status, lang),
+		}
+	}
+
+	response := &models.CustomerHierarchyResponse{
+		Ancestors: []*models.CustomerHierarchyNode{},
+		Tree:      nodes[id],
+	}
+	for _, ancestorID := range ancestorIDs {
+		if node, ok := nodes[ancestorID]; ok {
+			response.Ancestors = append(response.Ancestors, node)
+		}
+	}
+	// 按广度优先顺序挂接下级节点，已删除的客户及其下级不展示
+	for _, childID := range subtreeIDs[1:] {
+		node, ok := nodes[childID]
+		parent, parentOK := nodes[tree.parents[childID]]
+		if ok && parentOK {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	return response, nil
+}
+
+// resolveParentCustomer 校验上级客户必须存在且在当前账号范围内，返回规范化的上级客户ID
+// 是否形成循环在更新客户的事务中加锁校验（见 UpdateCustomerWithParent）
+func (s *customerService) resolveParentCustomer(ctx context.Context, parentID *string) (*string, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if parentID == nil || *parentID == "" {
+		return nil, nil
+	}
+	parent, err := s.customerRepo.GetCustomerByID(ctx, *parentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomerNotFound) {
+			return nil, i18n.NewI18nError("200010", lang) // 上级客户不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if !customerInPartnerScope(pkgcontext.GetPartnerIDFromContext(ctx), parent) {
+		return nil, i18n.NewI18nError("200010", lang)
+	}
+	return parentID, nil
+}
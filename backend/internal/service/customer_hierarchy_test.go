@@ -0,0 +1,64 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"license-manager/internal/models"
+)
+
+// 集团 -> 华东、华南；华东 -> 上海
+func testCustomerTree() *customerTree {
+	return newCustomerTree(map[string]string{
+		"east":     "group",
+		"south":    "group",
+		"shanghai": "east",
+	})
+}
+
+func TestCustomerTreeSubtree(t *testing.T) {
+	tree := testCustomerTree()
+
+	if got, want := tree.subtree("group"), []string{"group", "east", "south", "shanghai"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("subtree(group) = %v, want %v", got, want)
+	}
+	if got, want := tree.subtree("south"), []string{"south"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("subtree(south) = %v, want %v", got, want)
+	}
+}
+
+func TestCustomerTreeAncestors(t *testing.T) {
+	tree := testCustomerTree()
+
+	if got, want := tree.ancestors("shanghai"), []string{"group", "east"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ancestors(shanghai) = %v, want %v", got, want)
+	}
+	if got := tree.ancestors("group"); len(got) != 0 {
+		t.Errorf("ancestors(group) = %v, want empty", got)
+	}
+}
+
+func TestCustomerTreeCyclicData(t *testing.T) {
+	// 历史数据中存在环时遍历也必须终止
+	tree := newCustomerTree(map[string]string{"a": "b", "b": "a"})
+
+	if got := tree.subtree("a"); len(got) != 2 {
+		t.Errorf("subtree(a) = %v, want 2 nodes", got)
+	}
+	if got := tree.ancestors("a"); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("ancestors(a) = %v, want [b]", got)
+	}
+}
+
+func TestRollUpAuthorizationStats(t *testing.T) {
+	statsByCustomer := map[string]*models.AuthorizationStats{
+		"group": {TotalAuthCodes: 1, TotalLicenses: 2, ActiveLicenses: 2},
+		"east":  {TotalAuthCodes: 3, ExpiredAuthCodes: 1, TotalLicenses: 4, ActiveLicenses: 1, InactiveLicenses: 3},
+	}
+
+	got := rollUpAuthorizationStats(statsByCustomer, []string{"group", "east", "south"})
+	want := &models.AuthorizationStats{TotalAuthCodes: 4, ExpiredAuthCodes: 1, TotalLicenses: 6, ActiveLicenses: 3, InactiveLicenses: 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rollUpAuthorizationStats = %+v, want %+v", got, want)
+	}
+}
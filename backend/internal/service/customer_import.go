@@ -0,0 +1,747 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+	"license-manager/pkg/utils"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+const (
+	customerImportMaxRows          = 10000 // 单次导入最多数据行数
+	customerImportProgressInterval = 100   // 连续失败的行每处理多少行保存一次进度（成功的行逐行保存）
+	customerExportBatchSize        = 500   // 导出时每批读取的客户数
+)
+
+// 客户导入导出相关错误码
+const (
+	errCodeCustomerFileFormat    = "200019" // 仅支持 CSV 或 XLSX 文件
+	errCodeCustomerFileParse     = "200020" // 导入文件解析失败
+	errCodeCustomerFileHeader    = "200021" // 导入文件表头无效
+	errCodeCustomerFileEmpty     = "200022" // 导入文件没有数据行
+	errCodeCustomerFileTooLarge  = "200023" // 导入文件超过上限
+	errCodeCustomerImportMissing = "200024" // 客户导入任务不存在
+)
+
+// 导入时除 CustomerCreateRequest 校验规则外额外限定取值的列
+var (
+	customerImportLevels       = []string{"basic", "active", "vip", "strategic"}
+	customerImportCompanySizes = []string{"small", "medium", "large", "enterprise"}
+)
+
+// customerImportRow 导入文件中的一行数据
+type customerImportRow struct {
+	Line   int               `json:"line"`   // 文件中的行号（表头为第1行）
+	Values map[string]string `json:"values"` // 列名 -> 单元格内容
+}
+
+// ImportCustomers 创建客户导入任务，文件在请求内解析和校验表头，数据行由后台任务逐行导入
+func (s *customerService) ImportCustomers(ctx context.Context, operatorID, fileName string, data []byte, req *models.CustomerImportRequest) (*models.CustomerImportJob, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if operatorID == "" {
+		return nil, i18n.NewI18nError("100004", lang)
+	}
+	upsertKey := models.CustomerImportUpsertByCode
+	if req != nil && req.UpsertKey != "" {
+		upsertKey = req.UpsertKey
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	if format != models.CustomerFileFormatCSV && format != models.CustomerFileFormatXLSX {
+		return nil, i18n.NewI18nError(errCodeCustomerFileFormat, lang)
+	}
+
+	records, err := readCustomerFile(format, data)
+	if err != nil {
+		return nil, i18n.NewI18nError(errCodeCustomerFileParse, lang, i18n.GetI18nErrorMessage(errCodeCustomerFileParse, lang)+": "+err.Error())
+	}
+	rows, err := parseCustomerImportRecords(records)
+	if err != nil {
+		return nil, i18n.NewI18nError(errCodeCustomerFileHeader, lang, i18n.GetI18nErrorMessage(errCodeCustomerFileHeader, lang)+": "+err.Error())
+	}
+	if len(rows) == 0 {
+		return nil, i18n.NewI18nError(errCodeCustomerFileEmpty, lang)
+	}
+	if len(rows) > customerImportMaxRows {
+		return nil, i18n.NewI18nError(errCodeCustomerFileTooLarge, lang)
+	}
+
+	rowsJSON, err := json.Marshal(rows)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	job := &models.CustomerImportJob{
+		FileName:   filepath.Base(fileName),
+		FileFormat: format,
+		UpsertKey:  upsertKey,
+		Status:     string(models.CustomerImportJobStatusPending),
+		TotalRows:  len(rows),
+		Rows:       models.JSON(rowsJSON),
+		CreatedBy:  operatorID,
+	}
+	if err := s.customerRepo.CreateImportJob(ctx, job); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	// 后台任务使用独立副本，避免与响应数据共享；请求结束不影响后台任务，服务停止时取消
+	background := *job
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runImportJob(s.ctx, &background)
+	}()
+
+	job.StatusDisplay = i18n.GetEnumMessage("customer_import_job_status", job.Status, lang)
+	return job, nil
+}
+
+// GetImportJob 获取客户导入任务进度及逐行错误报告
+func (s *customerService) GetImportJob(ctx context.Context, id string) (*models.CustomerImportJob, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	job, err := s.getImportJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	job.Errors = decodeCustomerImportErrors(job.ErrorReport)
+	job.StatusDisplay = i18n.GetEnumMessage("customer_import_job_status", job.Status, lang)
+	return job, nil
+}
+
+// ExportImportErrors 导出客户导入任务的逐行错误报告
+func (s *customerService) ExportImportErrors(ctx context.Context, id, format string) ([]byte, string, string, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	job, err := s.getImportJob(ctx, id)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	rows := [][]string{{"row", "customer_code", "customer_name", "email", "errors"}}
+	for _, rowErr := range decodeCustomerImportErrors(job.ErrorReport) {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", rowErr.Row),
+			sanitizeSpreadsheetCell(rowErr.CustomerCode),
+			sanitizeSpreadsheetCell(rowErr.CustomerName),
+			sanitizeSpreadsheetCell(rowErr.Email),
+			sanitizeSpreadsheetCell(strings.Join(rowErr.Errors, "; ")),
+		})
+	}
+
+	baseName := fmt.Sprintf("customer_import_%s_errors", job.ID)
+	var buffer bytes.Buffer
+	contentType, err := writeCustomerFile(&buffer, format, "errors", func(write func(row []string) error) error {
+		for _, row := range rows {
+			if err := write(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", "", i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return buffer.Bytes(), baseName + "." + format, contentType, nil
+}
+
+// ExportCustomers 按客户列表的筛选条件导出客户，返回的 write 函数分批读取并直接写出，适合大数据量
+func (s *customerService) ExportCustomers(ctx context.Context, req *models.CustomerExportRequest) (string, string, func(w io.Writer) error, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if req == nil {
+		return "", "", nil, i18n.NewI18nError("900001", lang)
+	}
+	format := req.Format
+	if format == "" {
+		format = models.CustomerFileFormatCSV
+	}
+	listReq := req.CustomerListRequest
+	if err := s.scopeCustomerListRequest(ctx, &listReq); err != nil {
+		return "", "", nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	if err := normalizeCustomFieldFilter(ctx, s.customFieldRepo, models.CustomFieldEntityCustomer, &listReq.CustomFieldFilter); err != nil {
+		return "", "", nil, err
+	}
+	// 标签和自定义字段列追加在固定列之后，导出文件可直接修改后重新导入
+	definitions, err := s.customFieldRepo.GetDefinitions(ctx, models.CustomFieldEntityCustomer)
+	if err != nil {
+		return "", "", nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	fileName := fmt.Sprintf("customers_%s.%s", time.Now().Format("20060102150405"), format)
+	contentType := customerFileContentType(format)
+	write := func(w io.Writer) error {
+		_, err := writeCustomerFile(w, format, "customers", func(write func(row []string) error) error {
+			if err := write(append(slices.Clone(models.CustomerFileColumns), customFieldColumns(definitions)...)); err != nil {
+				return err
+			}
+			return s.customerRepo.EachCustomer(ctx, &listReq, customerExportBatchSize, func(customers []*models.Customer) error {
+				ids := make([]string, 0, len(customers))
+				for _, customer := range customers {
+					ids = append(ids, customer.ID)
+				}
+				fields, err := s.customFieldRepo.GetEntityCustomFields(ctx, models.CustomFieldEntityCustomer, ids)
+				if err != nil {
+					return err
+				}
+				for _, customer := range customers {
+					if err := write(append(customerExportRow(customer), customFieldCells(definitions, fields[customer.ID])...)); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		})
+		return err
+	}
+	return fileName, contentType, write, nil
+}
+
+// runImportJob 领取导入任务后在后台逐行导入客户，从已提交的进度处继续，单行失败记入错误报告不影响其他行
+// 每个导入成功的行与进度在同一事务中提交，续跑不会重复导入；任务已由其他实例领取时直接返回，
+// 服务停止或租约被接管时中止，不标记失败
+func (s *customerService) runImportJob(ctx context.Context, job *models.CustomerImportJob) {
+	log := s.logger.WithField("import_job_id", job.ID)
+
+	owner := uuid.New().String()
+	claimed, err := s.customerRepo.ClaimImportJob(ctx, job.ID, owner, backgroundJobLease)
+	if err != nil {
+		log.WithError(err).Error("领取客户导入任务失败")
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	// 以最新的已提交进度续跑（领取前可能由其他实例处理了一部分）
+	job, err = s.customerRepo.GetImportJob(ctx, job.ID)
+	if err != nil {
+		log.WithError(err).Error("查询客户导入任务失败")
+		return
+	}
+	log.Infof("开始客户导入任务，已处理 %d/%d", job.ProcessedRows, job.TotalRows)
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("客户导入任务异常: %v", r)
+			s.failImportJob(ctx, job.ID, owner, fmt.Sprintf("panic: %v", r))
+		}
+	}()
+
+	var rows []customerImportRow
+	if err := json.Unmarshal(job.Rows, &rows); err != nil {
+		s.failImportJob(ctx, job.ID, owner, err.Error())
+		return
+	}
+	rowErrors := decodeCustomerImportErrors(job.ErrorReport)
+	// 失败的行不写入数据，错误报告在下一个成功行的事务或定期保存时随进度一并保存，
+	// 中途退出时未保存的失败行在续跑时重新处理
+	reportDirty := false
+
+	progress := func() (map[string]interface{}, error) {
+		updates := map[string]interface{}{
+			"processed_rows": job.ProcessedRows,
+			"created_count":  job.CreatedCount,
+			"updated_count":  job.UpdatedCount,
+			"failed_count":   job.FailedCount,
+		}
+		if reportDirty {
+			report, err := json.Marshal(rowErrors)
+			if err != nil {
+				return nil, err
+			}
+			updates["error_report"] = models.JSON(report)
+		}
+		return updates, nil
+	}
+	saveProgress := func(extra map[string]interface{}) error {
+		updates, err := progress()
+		if err != nil {
+			return err
+		}
+		for key, value := range extra {
+			updates[key] = value
+		}
+		if err := s.customerRepo.UpdateLeasedImportJobFields(ctx, job.ID, owner, backgroundJobLease, updates); err != nil {
+			return err
+		}
+		reportDirty = false
+		return nil
+	}
+
+	definitions, err := s.customFieldRepo.GetDefinitions(ctx, models.CustomFieldEntityCustomer)
+	if err != nil {
+		s.failImportJob(ctx, job.ID, owner, err.Error())
+		return
+	}
+
+	for job.ProcessedRows < len(rows) {
+		if ctx.Err() != nil {
+			log.Infof("服务停止，客户导入任务已处理 %d/%d，待续跑", job.ProcessedRows, job.TotalRows)
+			return
+		}
+
+		row := rows[job.ProcessedRows]
+		customer, customFields, tags, messages := s.prepareCustomerImportRow(ctx, job, definitions, &row)
+		if len(messages) == 0 {
+			created := customer.ID == ""
+			job.ProcessedRows++
+			if created {
+				job.CreatedCount++
+			} else {
+				job.UpdatedCount++
+			}
+			updates, err := progress()
+			if err == nil {
+				err = s.customerRepo.ImportCustomerRow(ctx, job.ID, owner, backgroundJobLease, customer, customFields, tags, updates)
+			}
+			if err == nil {
+				reportDirty = false
+				continue
+			}
+			if errors.Is(err, repository.ErrJobLeaseLost) || ctx.Err() != nil {
+				log.Info("客户导入任务已由其他实例接管或服务停止，中止导入")
+				return
+			}
+
+			// 写入失败时事务已回滚，撤销计数后按失败行记录
+			job.ProcessedRows--
+			if created {
+				job.CreatedCount--
+			} else {
+				job.UpdatedCount--
+			}
+			messages = []string{err.Error()}
+		}
+
+		job.FailedCount++
+		rowErrors = append(rowErrors, newCustomerImportRowError(&row, messages))
+		reportDirty = true
+		job.ProcessedRows++
+
+		if job.ProcessedRows%customerImportProgressInterval == 0 {
+			if err := saveProgress(nil); err != nil {
+				if errors.Is(err, repository.ErrJobLeaseLost) || ctx.Err() != nil {
+					log.Info("客户导入任务已由其他实例接管或服务停止，中止导入")
+					return
+				}
+				log.WithError(err).Error("保存客户导入进度失败")
+			}
+		}
+	}
+
+	reportDirty = true
+	if err := saveProgress(map[string]interface{}{
+		"status":        models.CustomerImportJobStatusCompleted,
+		"error_message": nil,
+		"completed_at":  time.Now(),
+	}); err != nil {
+		log.WithError(err).Error("更新客户导入任务状态失败")
+		return
+	}
+
+	log.Infof("客户导入任务完成：新建 %d，更新 %d，失败 %d", job.CreatedCount, job.UpdatedCount, job.FailedCount)
+}
+
+// prepareCustomerImportRow 校验一行客户数据并准备要写入的客户（ID 为空表示新建）、自定义字段和标签；失败时返回错误原因
+// 标签列有值时整体替换客户标签，空单元格的自定义字段保留原值
+func (s *customerService) prepareCustomerImportRow(ctx context.Context, job *models.CustomerImportJob, definitions []*models.CustomFieldDefinition, row *customerImportRow) (*models.Customer, map[string]string, []string, []string) {
+	req := newCustomerImportRequest(row.Values)
+	if messages := validateCustomerImportRequest(req); len(messages) > 0 {
+		return nil, nil, nil, messages
+	}
+
+	existing, message := s.findImportTarget(ctx, job.UpsertKey, row.Values)
+	if message != "" {
+		return nil, nil, nil, []string{message}
+	}
+
+	customFields, messages := normalizeCustomFieldValues(definitions, req.CustomFields, existing == nil)
+	tags, tagMessages := normalizeEntityTags(req.Tags)
+	if messages = append(messages, tagMessages...); len(messages) > 0 {
+		return nil, nil, nil, messages
+	}
+
+	if existing == nil {
+		return &models.Customer{
+			CustomerName:  req.CustomerName,
+			CustomerType:  req.CustomerType,
+			ContactPerson: req.ContactPerson,
+			ContactTitle:  req.ContactTitle,
+			Email:         req.Email,
+			Phone:         req.Phone,
+			Address:       req.Address,
+			CompanySize:   req.CompanySize,
+			CustomerLevel: req.CustomerLevel,
+			Status:        req.Status,
+			Description:   req.Description,
+			CreatedBy:     job.CreatedBy,
+		}, customFields, tags, nil
+	}
+
+	applyCustomerImportRequest(existing, req)
+	existing.UpdatedBy = &job.CreatedBy
+	return existing, customFields, tags, nil
+}
+
+// findImportTarget 按导入任务的匹配方式查找要更新的已有客户，未匹配时返回 nil 表示新建
+func (s *customerService) findImportTarget(ctx context.Context, upsertKey string, values map[string]string) (*models.Customer, string) {
+	switch upsertKey {
+	case models.CustomerImportUpsertByEmail:
+		email := values["email"]
+		if email == "" {
+			return nil, ""
+		}
+		customers, err := s.customerRepo.GetCustomersByEmail(ctx, email)
+		if err != nil {
+			return nil, err.Error()
+		}
+		if len(customers) > 1 {
+			return nil, "email: matches multiple customers"
+		}
+		if len(customers) == 1 {
+			return customers[0], ""
+		}
+		return nil, ""
+	default:
+		code := values["customer_code"]
+		if code == "" {
+			return nil, ""
+		}
+		customer, err := s.customerRepo.GetCustomerByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, repository.ErrCustomerNotFound) {
+				return nil, "customer_code: customer not found"
+			}
+			return nil, err.Error()
+		}
+		return customer, ""
+	}
+}
+
+// failImportJob 标记导入任务失败，已导入的客户保留
+// 服务停止导致的失败不标记，租约过期后续跑
+func (s *customerService) failImportJob(ctx context.Context, id, owner, message string) {
+	log := s.logger.WithField("import_job_id", id)
+	if ctx.Err() != nil {
+		log.Infof("服务停止，客户导入任务待续跑: %s", message)
+		return
+	}
+	log.Errorf("客户导入任务失败: %s", message)
+	if err := s.customerRepo.UpdateLeasedImportJobFields(ctx, id, owner, backgroundJobLease, map[string]interface{}{
+		"status":        models.CustomerImportJobStatusFailed,
+		"error_message": message,
+	}); err != nil {
+		log.WithError(err).Error("更新客户导入任务状态失败")
+	}
+}
+
+// ResumeUnfinishedImports 续跑服务重启前未完成的导入任务
+func (s *customerService) ResumeUnfinishedImports() {
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	resumeUnfinishedJobs(s.ctx, s.logger, "客户导入任务", func() (int, error) {
+		jobs, err := s.customerRepo.GetUnfinishedImportJobs(s.ctx)
+		if err != nil {
+			return 0, err
+		}
+		for _, job := range jobs {
+			if s.ctx.Err() != nil {
+				break
+			}
+			s.runImportJob(s.ctx, job)
+		}
+		return len(jobs), nil
+	})
+}
+
+// Stop 取消后台导入任务并等待退出
+func (s *customerService) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *customerService) getImportJob(ctx context.Context, id string) (*models.CustomerImportJob, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	job, err := s.customerRepo.GetImportJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomerImportJobNotFound) {
+			return nil, i18n.NewI18nError(errCodeCustomerImportMissing, lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return job, nil
+}
+
+// readCustomerFile 读取 CSV 或 XLSX 文件的全部行
+func readCustomerFile(format string, data []byte) ([][]string, error) {
+	if format == models.CustomerFileFormatXLSX {
+		return utils.ReadXLSX(data)
+	}
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))))
+	reader.FieldsPerRecord = -1
+	return reader.ReadAll()
+}
+
+// writeCustomerFile 以 CSV 或 XLSX 格式逐行写出，返回文件的 Content-Type
+func writeCustomerFile(w io.Writer, format, sheetName string, rows func(write func(row []string) error) error) (string, error) {
+	if format == models.CustomerFileFormatXLSX {
+		writer, err := utils.NewXLSXWriter(w, sheetName)
+		if err != nil {
+			return "", err
+		}
+		if err := rows(writer.WriteRow); err != nil {
+			return "", err
+		}
+		return customerFileContentType(format), writer.Close()
+	}
+
+	if _, err := io.WriteString(w, "\xEF\xBB\xBF"); err != nil { // UTF-8 BOM，Excel 直接打开不乱码
+		return "", err
+	}
+	writer := csv.NewWriter(w)
+	if err := rows(writer.Write); err != nil {
+		return "", err
+	}
+	writer.Flush()
+	return customerFileContentType(format), writer.Error()
+}
+
+func customerFileContentType(format string) string {
+	if format == models.CustomerFileFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// parseCustomerImportRecords 按表头把文件行转换为导入数据，跳过空行；表头必须包含必填列且不能有未知列
+func parseCustomerImportRecords(records [][]string) ([]customerImportRow, error) {
+	if len(records) == 0 {
+		return nil, errors.New("missing header row")
+	}
+
+	known := make(map[string]bool, len(models.CustomerFileColumns))
+	for _, column := range models.CustomerFileColumns {
+		known[column] = true
+	}
+	header := make([]string, len(records[0]))
+	seen := make(map[string]bool, len(records[0]))
+	for i, cell := range records[0] {
+		column := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(cell, "\xEF\xBB\xBF")))
+		if column == "" {
+			continue
+		}
+		if !known[column] && column != entityTagColumn && !isCustomFieldColumn(column) {
+			return nil, fmt.Errorf("unknown column %q", cell)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("duplicate column %q", column)
+		}
+		seen[column] = true
+		header[i] = column
+	}
+	for _, column := range []string{"customer_name", "customer_type", "contact_person", "customer_level", "status"} {
+		if !seen[column] {
+			return nil, fmt.Errorf("missing column %q", column)
+		}
+	}
+
+	rows := []customerImportRow{}
+	for i, record := range records[1:] {
+		values := make(map[string]string, len(header))
+		for j, cell := range record {
+			if j < len(header) && header[j] != "" {
+				if value := normalizeCustomerImportCell(cell); value != "" {
+					values[header[j]] = value
+				}
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		rows = append(rows, customerImportRow{Line: i + 2, Values: values})
+	}
+	return rows, nil
+}
+
+// isCustomFieldColumn 是否为自定义字段列（cf:字段标识），字段是否存在在导入每行时校验
+func isCustomFieldColumn(column string) bool {
+	return strings.HasPrefix(column, customFieldColumnPrefix) && len(column) > len(customFieldColumnPrefix)
+}
+
+// normalizeCustomerImportCell 去除首尾空白，并还原导出时为防止公式执行而添加的前导单引号
+func normalizeCustomerImportCell(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// newCustomerImportRequest 将一行数据转换为创建客户请求，空单元格对应的可选字段为 nil
+func newCustomerImportRequest(values map[string]string) *models.CustomerCreateRequest {
+	optional := func(column string) *string {
+		if value, ok := values[column]; ok {
+			return &value
+		}
+		return nil
+	}
+	var customFields map[string]string
+	for column, value := range values {
+		if isCustomFieldColumn(column) {
+			if customFields == nil {
+				customFields = make(map[string]string)
+			}
+			customFields[strings.TrimPrefix(column, customFieldColumnPrefix)] = value
+		}
+	}
+	var tags []string
+	if value, ok := values[entityTagColumn]; ok {
+		tags = splitEntityTags(value)
+	}
+	return &models.CustomerCreateRequest{
+		CustomerName:  values["customer_name"],
+		CustomerType:  strings.ToLower(values["customer_type"]),
+		ContactPerson: values["contact_person"],
+		ContactTitle:  optional("contact_title"),
+		Email:         optional("email"),
+		Phone:         optional("phone"),
+		Address:       optional("address"),
+		CompanySize:   optional("company_size"),
+		CustomerLevel: strings.ToLower(values["customer_level"]),
+		Status:        strings.ToLower(values["status"]),
+		Description:   optional("description"),
+		CustomFields:  customFields,
+		Tags:          tags,
+	}
+}
+
+// validateCustomerImportRequest 按 CustomerCreateRequest 的校验规则校验一行数据，返回 "列名: 规则" 形式的错误
+func validateCustomerImportRequest(req *models.CustomerCreateRequest) []string {
+	var messages []string
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			return []string{err.Error()}
+		}
+		for _, fieldErr := range validationErrors {
+			message := customerImportColumnName(fieldErr.StructField()) + ": " + fieldErr.Tag()
+			if fieldErr.Param() != "" {
+				message += "=" + fieldErr.Param()
+			}
+			messages = append(messages, message)
+		}
+	}
+	if req.CustomerLevel != "" && !slices.Contains(customerImportLevels, req.CustomerLevel) {
+		messages = append(messages, "customer_level: oneof="+strings.Join(customerImportLevels, " "))
+	}
+	if req.CompanySize != nil && !slices.Contains(customerImportCompanySizes, *req.CompanySize) {
+		messages = append(messages, "company_size: oneof="+strings.Join(customerImportCompanySizes, " "))
+	}
+	return messages
+}
+
+// customerImportColumnName 返回 CustomerCreateRequest 字段对应的列名（json 标签）
+func customerImportColumnName(field string) string {
+	if structField, ok := reflect.TypeOf(models.CustomerCreateRequest{}).FieldByName(field); ok {
+		if name := strings.Split(structField.Tag.Get("json"), ",")[0]; name != "" {
+			return name
+		}
+	}
+	return field
+}
+
+// applyCustomerImportRequest 用导入数据更新已有客户，空单元格保留原值
+func applyCustomerImportRequest(customer *models.Customer, req *models.CustomerCreateRequest) {
+	customer.CustomerName = req.CustomerName
+	customer.CustomerType = req.CustomerType
+	customer.ContactPerson = req.ContactPerson
+	customer.CustomerLevel = req.CustomerLevel
+	customer.Status = req.Status
+	if req.ContactTitle != nil {
+		customer.ContactTitle = req.ContactTitle
+	}
+	if req.Email != nil {
+		customer.Email = req.Email
+	}
+	if req.Phone != nil {
+		customer.Phone = req.Phone
+	}
+	if req.Address != nil {
+		customer.Address = req.Address
+	}
+	if req.CompanySize != nil {
+		customer.CompanySize = req.CompanySize
+	}
+	if req.Description != nil {
+		customer.Description = req.Description
+	}
+}
+
+// customerExportRow 导出一行客户数据，列顺序与 models.CustomerFileColumns 一致
+func customerExportRow(customer *models.Customer) []string {
+	optional := func(value *string) string {
+		if value == nil {
+			return ""
+		}
+		return *value
+	}
+	row := []string{
+		customer.CustomerCode,
+		customer.CustomerName,
+		customer.CustomerType,
+		customer.ContactPerson,
+		optional(customer.ContactTitle),
+		optional(customer.Email),
+		optional(customer.Phone),
+		optional(customer.Address),
+		optional(customer.CompanySize),
+		customer.CustomerLevel,
+		customer.Status,
+		optional(customer.Description),
+	}
+	for i := range row {
+		row[i] = sanitizeSpreadsheetCell(row[i])
+	}
+	return row
+}
+
+func newCustomerImportRowError(row *customerImportRow, messages []string) models.CustomerImportRowError {
+	return models.CustomerImportRowError{
+		Row:          row.Line,
+		CustomerCode: row.Values["customer_code"],
+		CustomerName: row.Values["customer_name"],
+		Email:        row.Values["email"],
+		Errors:       messages,
+	}
+}
+
+func decodeCustomerImportErrors(report models.JSON) []models.CustomerImportRowError {
+	rowErrors := []models.CustomerImportRowError{}
+	if len(report) > 0 {
+		_ = json.Unmarshal(report, &rowErrors)
+	}
+	return rowErrors
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"license-manager/internal/models"
+)
+
+func TestParseCustomerImportRecords(t *testing.T) {
+	records := [][]string{
+		{"\xEF\xBB\xBFCustomer_Name", "customer_type", "contact_person", "customer_level", "status", "email", ""},
+		{" Acme ", "enterprise", "Alice", "vip", "active", "", "ignored"},
+		{"", "", "", "", "", "", ""},
+		{"Beta", "individual", "'=Bob", "basic", "disabled", "bob@example.com"},
+	}
+	rows, err := parseCustomerImportRecords(records)
+	if err != nil {
+		t.Fatalf("parseCustomerImportRecords: %v", err)
+	}
+	want := []customerImportRow{
+		{Line: 2, Values: map[string]string{"customer_name": "Acme", "customer_type": "enterprise", "contact_person": "Alice", "customer_level": "vip", "status": "active"}},
+		{Line: 4, Values: map[string]string{"customer_name": "Beta", "customer_type": "individual", "contact_person": "=Bob", "customer_level": "basic", "status": "disabled", "email": "bob@example.com"}},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %+v, want %+v", rows, want)
+	}
+
+	invalid := map[string][][]string{
+		"empty":            {},
+		"unknown column":   {{"customer_name", "customer_type", "contact_person", "customer_level", "status", "fax"}},
+		"duplicate column": {{"customer_name", "customer_type", "contact_person", "customer_level", "status", "status"}},
+		"missing column":   {{"customer_name", "customer_type", "contact_person", "customer_level"}},
+	}
+	for name, records := range invalid {
+		if _, err := parseCustomerImportRecords(records); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNormalizeCustomerImportCell(t *testing.T) {
+	cases := map[string]string{
+		"  Acme  ":  "Acme",
+		"'=SUM(A1)": "=SUM(A1)",
+		"'+86 123":  "+86 123",
+		"'quoted":   "'quoted",
+		"'":         "'",
+	}
+	for input, want := range cases {
+		if got := normalizeCustomerImportCell(input); got != want {
+			t.Errorf("normalizeCustomerImportCell(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestValidateCustomerImportRequest(t *testing.T) {
+	valid := map[string]string{
+		"customer_name":  "Acme",
+		"customer_type":  "Enterprise",
+		"contact_person": "Alice",
+		"customer_level": "VIP",
+		"status":         "active",
+		"company_size":   "large",
+	}
+	if messages := validateCustomerImportRequest(newCustomerImportRequest(valid)); len(messages) != 0 {
+		t.Errorf("valid row: unexpected errors %v", messages)
+	}
+
+	invalid := map[string]string{
+		"customer_name":  "Acme",
+		"customer_type":  "alien",
+		"customer_level": "gold",
+		"status":         "active",
+		"email":          "not-an-email",
+		"company_size":   "huge",
+	}
+	want := []string{
+		"customer_type: oneof=individual enterprise government education",
+		"contact_person: required",
+		"email: email",
+		"customer_level: oneof=basic active vip strategic",
+		"company_size: oneof=small medium large enterprise",
+	}
+	if messages := validateCustomerImportRequest(newCustomerImportRequest(invalid)); !reflect.DeepEqual(messages, want) {
+		t.Errorf("invalid row errors = %q, want %q", messages, want)
+	}
+}
+
+func TestApplyCustomerImportRequest(t *testing.T) {
+	email, phone, newPhone := "old@example.com", "123", "456"
+	customer := &models.Customer{CustomerName: "Old", Email: &email, Phone: &phone}
+	req := newCustomerImportRequest(map[string]string{
+		"customer_name":  "New",
+		"customer_type":  "enterprise",
+		"contact_person": "Alice",
+		"customer_level": "basic",
+		"status":         "active",
+		"phone":          newPhone,
+	})
+	applyCustomerImportRequest(customer, req)
+
+	if customer.CustomerName != "New" || customer.ContactPerson != "Alice" {
+		t.Errorf("required fields not applied: %+v", customer)
+	}
+	if customer.Email == nil || *customer.Email != email {
+		t.Errorf("empty email cell should keep existing value, got %v", customer.Email)
+	}
+	if customer.Phone == nil || *customer.Phone != newPhone {
+		t.Errorf("phone = %v, want %q", customer.Phone, newPhone)
+	}
+}
+
+func TestCustomerImportCustomFieldColumns(t *testing.T) {
+	records := [][]string{
+		{"customer_name", "customer_type", "contact_person", "customer_level", "status", "Tags", "cf:region"},
+		{"Acme", "enterprise", "Alice", "vip", "active", " key ; renewal;", "east"},
+	}
+	rows, err := parseCustomerImportRecords(records)
+	if err != nil {
+		t.Fatalf("parseCustomerImportRecords: %v", err)
+	}
+	req := newCustomerImportRequest(rows[0].Values)
+	if !reflect.DeepEqual(req.Tags, []string{"key", "renewal"}) {
+		t.Errorf("tags = %q, want [key renewal]", req.Tags)
+	}
+	if !reflect.DeepEqual(req.CustomFields, map[string]string{"region": "east"}) {
+		t.Errorf("custom fields = %v, want region=east", req.CustomFields)
+	}
+
+	if _, err := parseCustomerImportRecords([][]string{{"customer_name", "customer_type", "contact_person", "customer_level", "status", "cf:"}}); err == nil {
+		t.Error("empty custom field key: expected error")
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+)
+
+// MergeCustomers 将重复的来源客户合并到目标客户
+// 来源客户的授权码、许可证、客户用户、订单、支付、发票及下级客户等迁移到目标客户，来源客户被删除，合并记录写入审计表；
+// DryRun 时只返回将迁移的数据数量，不做任何修改
+func (s *customerService) MergeCustomers(ctx context.Context, operatorID string, req *models.CustomerMergeRequest) (*models.CustomerMergeResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 业务逻辑：参数验证
+	if req == nil || req.TargetID == "" || len(req.SourceIDs) == 0 {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+	if operatorID == "" {
+		return nil, i18n.NewI18nError("100004", lang)
+	}
+
+	seen := map[string]bool{req.TargetID: true}
+	for _, sourceID := range req.SourceIDs {
+		if seen[sourceID] {
+			return nil, i18n.NewI18nError("900001", lang, "duplicate customer: "+sourceID)
+		}
+		seen[sourceID] = true
+	}
+
+	target, err := s.getMergeCustomer(ctx, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]*models.Customer, 0, len(req.SourceIDs))
+	for _, sourceID := range req.SourceIDs {
+		source, err := s.getMergeCustomer(ctx, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	tree, err := loadCustomerAncestors(ctx, s.customerRepo, target.ID)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if code := checkCustomerMerge(target, sources, tree); code != "" {
+		return nil, i18n.NewI18nError(code, lang)
+	}
+
+	response := &models.CustomerMergeResponse{
+		DryRun:  req.DryRun,
+		Target:  target,
+		Sources: sources,
+	}
+	s.fillCustomerDisplayFields(target, lang)
+	for _, source := range sources {
+		s.fillCustomerDisplayFields(source, lang)
+	}
+
+	if req.DryRun {
+		counts, err := s.customerRepo.CountCustomerMergeRows(ctx, req.SourceIDs)
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		response.Moved = counts
+		return response, nil
+	}
+
+	merge, err := newCustomerMerge(target.ID, sources, operatorID, req.Reason)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	counts, err := s.customerRepo.MergeCustomers(ctx, target.ID, req.SourceIDs, merge)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomerNotFound) {
+			return nil, i18n.NewI18nError("200001", lang) // 客户已被并发删除或合并
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	// 来源客户的授权码已迁移到目标客户，清除相关客户和授权码的授权检查缓存
+	if s.entitlements != nil {
+		s.entitlements.InvalidateCustomers(ctx, append([]string{target.ID}, req.SourceIDs...)...)
+	}
+	response.MergeID = merge.ID
+	response.Moved = counts
+	return response, nil
+}
+
+// GetCustomerMerges 查询客户合并审计记录
+func (s *customerService) GetCustomerMerges(ctx context.Context, req *models.CustomerMergeListRequest) (*models.CustomerMergeListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	merges, total, err := s.customerRepo.GetCustomerMerges(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return &models.CustomerMergeListResponse{
+		List:     merges,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// getMergeCustomer 获取参与合并的客户
+func (s *customerService) getMergeCustomer(ctx context.Context, id string) (*models.Customer, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	customer, err := s.customerRepo.GetCustomerByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomerNotFound) {
+			return nil, i18n.NewI18nError("200001", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return customer, nil
+}
+
+// checkCustomerMerge 校验客户能否合并，返回错误码，可以合并时返回空字符串
+// 来源客户必须与目标客户属于同一经销商（避免经销商席位额度被绕过），且不能是目标客户的上级
+func checkCustomerMerge(target *models.Customer, sources []*models.Customer, tree *customerTree) string {
+	ancestors := make(map[string]bool)
+	for _, id := range tree.ancestors(target.ID) {
+		ancestors[id] = true
+	}
+	for _, source := range sources {
+		if !sameCustomerPartner(source, target) {
+			return "200017" // 来源客户与目标客户所属经销商不一致
+		}
+		if ancestors[source.ID] {
+			return "200018" // 不能将上级客户合并到其下级客户
+		}
+	}
+	return ""
+}
+
+// sameCustomerPartner 两个客户是否属于同一经销商（均为直营客户也视为相同）
+func sameCustomerPartner(a, b *models.Customer) bool {
+	if a.PartnerID == nil || b.PartnerID == nil {
+		return a.PartnerID == nil && b.PartnerID == nil
+	}
+	return *a.PartnerID == *b.PartnerID
+}
+
+// newCustomerMerge 构建合并审计记录，保存来源客户合并前的关键信息
+func newCustomerMerge(targetID string, sources []*models.Customer, operatorID string, reason *string) (*models.CustomerMerge, error) {
+	sourceIDs := make([]string, 0, len(sources))
+	snapshots := make([]map[string]interface{}, 0, len(sources))
+	for _, source := range sources {
+		sourceIDs = append(sourceIDs, source.ID)
+		snapshots = append(snapshots, map[string]interface{}{
+			"id":             source.ID,
+			"customer_code":  source.CustomerCode,
+			"customer_name":  source.CustomerName,
+			"contact_person": source.ContactPerson,
+			"phone":          source.Phone,
+			"email":          source.Email,
+			"parent_id":      source.ParentID,
+			"partner_id":     source.PartnerID,
+			"status":         source.Status,
+		})
+	}
+	idsJSON, err := json.Marshal(sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	snapshotJSON, err := json.Marshal(snapshots)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CustomerMerge{
+		TargetCustomerID:  targetID,
+		SourceCustomerIDs: models.JSON(idsJSON),
+		SourceCustomers:   models.JSON(snapshotJSON),
+		OperatorID:        operatorID,
+		Reason:            reason,
+	}, nil
+}
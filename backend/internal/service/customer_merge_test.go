@@ -0,0 +1,62 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"license-manager/internal/models"
+)
+
+func TestCheckCustomerMerge(t *testing.T) {
+	tree := testCustomerTree()
+	partnerA, partnerB := "partner-a", "partner-b"
+
+	cases := []struct {
+		name    string
+		target  *models.Customer
+		sources []*models.Customer
+		want    string
+	}{
+		{"direct customers", &models.Customer{ID: "shanghai"}, []*models.Customer{{ID: "south"}, {ID: "other"}}, ""},
+		{"subsidiary into parent", &models.Customer{ID: "group"}, []*models.Customer{{ID: "shanghai"}}, ""},
+		{"same partner", &models.Customer{ID: "x", PartnerID: &partnerA}, []*models.Customer{{ID: "y", PartnerID: &partnerA}}, ""},
+		{"different partner", &models.Customer{ID: "x", PartnerID: &partnerA}, []*models.Customer{{ID: "y", PartnerID: &partnerB}}, "200017"},
+		{"partner and direct", &models.Customer{ID: "x"}, []*models.Customer{{ID: "y", PartnerID: &partnerA}}, "200017"},
+		{"parent into subsidiary", &models.Customer{ID: "shanghai"}, []*models.Customer{{ID: "south"}, {ID: "group"}}, "200018"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := checkCustomerMerge(tc.target, tc.sources, tree); got != tc.want {
+				t.Errorf("checkCustomerMerge = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewCustomerMerge(t *testing.T) {
+	reason := "duplicate registration"
+	sources := []*models.Customer{
+		{ID: "s1", CustomerCode: "CUS-2025-0001", CustomerName: "Acme"},
+		{ID: "s2", CustomerCode: "CUS-2025-0002", CustomerName: "Acme Ltd"},
+	}
+
+	merge, err := newCustomerMerge("t1", sources, "admin-1", &reason)
+	if err != nil {
+		t.Fatalf("newCustomerMerge: %v", err)
+	}
+	if merge.TargetCustomerID != "t1" || merge.OperatorID != "admin-1" || merge.Reason != &reason {
+		t.Errorf("unexpected merge record: %+v", merge)
+	}
+
+	var ids []string
+	if err := json.Unmarshal(merge.SourceCustomerIDs, &ids); err != nil || len(ids) != 2 || ids[0] != "s1" || ids[1] != "s2" {
+		t.Errorf("source_customer_ids = %s", merge.SourceCustomerIDs)
+	}
+	var snapshots []map[string]interface{}
+	if err := json.Unmarshal(merge.SourceCustomers, &snapshots); err != nil || len(snapshots) != 2 {
+		t.Fatalf("source_customers = %s", merge.SourceCustomers)
+	}
+	if snapshots[1]["customer_code"] != "CUS-2025-0002" || snapshots[1]["customer_name"] != "Acme Ltd" {
+		t.Errorf("snapshot = %v", snapshots[1])
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+)
+
+// GetCustomerSeatPool 获取客户席位池及使用情况
+func (s *customerService) GetCustomerSeatPool(ctx context.Context, id string) (*models.CustomerSeatPool, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if _, err := s.getSeatPoolCustomer(ctx, id); err != nil {
+		return nil, err
+	}
+	pool, err := s.seatPools.GetSeatPool(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSeatPoolNotFound) {
+			return nil, i18n.NewI18nError("670001", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return pool, nil
+}
+
+// SaveCustomerSeatPool 创建或更新客户席位池，总席位不能少于已占用席位
+func (s *customerService) SaveCustomerSeatPool(ctx context.Context, operatorID, id string, req *models.CustomerSeatPoolSaveRequest) (*models.CustomerSeatPool, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if _, err := s.getSeatPoolCustomer(ctx, id); err != nil {
+		return nil, err
+	}
+	pool := &models.CustomerSeatPool{
+		CustomerID:  id,
+		TotalSeats:  req.TotalSeats,
+		Description: req.Description,
+		CreatedBy:   operatorID,
+		UpdatedBy:   operatorID,
+	}
+	if err := s.seatPools.SaveSeatPool(ctx, pool); err != nil {
+		if errors.Is(err, repository.ErrSeatPoolSeatsUsed) {
+			return nil, i18n.NewI18nError("670002", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return pool, nil
+}
+
+// DeleteCustomerSeatPool 删除客户席位池，授权码恢复为仅按自身最大激活次数限制
+func (s *customerService) DeleteCustomerSeatPool(ctx context.Context, id string) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if _, err := s.getSeatPoolCustomer(ctx, id); err != nil {
+		return err
+	}
+	if err := s.seatPools.DeleteSeatPool(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrSeatPoolNotFound) {
+			return i18n.NewI18nError("670001", lang)
+		}
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return nil
+}
+
+// getSeatPoolCustomer 获取设置席位池的客户
+func (s *customerService) getSeatPoolCustomer(ctx context.Context, id string) (*models.Customer, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	customer, err := s.customerRepo.GetCustomerByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomerNotFound) {
+			return nil, i18n.NewI18nError("200001", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return customer, nil
+}
+
+// getSeatPool 获取授权码所属客户的席位池，未设置时返回 670001
+func (s *authorizationCodeService) getSeatPool(ctx context.Context, customerID string) (*models.CustomerSeatPool, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	pool, err := s.seatPools.GetSeatPool(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSeatPoolNotFound) {
+			return nil, i18n.NewI18nError("670001", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return pool, nil
+}
+
+// checkSeatPoolAttach 授权码开启席位池取用前，校验席位池剩余席位能容纳该授权码已激活的许可证
+func (s *authorizationCodeService) checkSeatPoolAttach(ctx context.Context, authCode *models.AuthorizationCode) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	pool, err := s.getSeatPool(ctx, authCode.CustomerID)
+	if err != nil {
+		return err
+	}
+	activeCount, err := s.licenseRepo.GetActiveLicenseCount(ctx, authCode.ID)
+	if err != nil {
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if activeCount > pool.RemainingSeats {
+		return i18n.NewI18nError("670003", lang)
+	}
+	return nil
+}
@@ -4,21 +4,42 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"sync"
 
 	"license-manager/internal/models"
 	"license-manager/internal/repository"
 	pkgcontext "license-manager/pkg/context"
 	"license-manager/pkg/i18n"
+
+	"github.com/sirupsen/logrus"
 )
 
 type customerService struct {
-	customerRepo repository.CustomerRepository
+	customerRepo    repository.CustomerRepository
+	partnerRepo     repository.PartnerRepository
+	customFieldRepo repository.CustomFieldRepository
+	seatPools       repository.SeatPoolRepository
+	entitlements    EntitlementInvalidator
+	logger          *logrus.Logger
+
+	// 后台导入任务的上下文，Stop 时取消
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 // NewCustomerService 创建客户服务实例
-func NewCustomerService(customerRepo repository.CustomerRepository) CustomerService {
+func NewCustomerService(customerRepo repository.CustomerRepository, partnerRepo repository.PartnerRepository, customFieldRepo repository.CustomFieldRepository, seatPools repository.SeatPoolRepository, entitlements EntitlementInvalidator, logger *logrus.Logger) CustomerService {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &customerService{
-		customerRepo: customerRepo,
+		customerRepo:    customerRepo,
+		partnerRepo:     partnerRepo,
+		customFieldRepo: customFieldRepo,
+		seatPools:       seatPools,
+		entitlements:    entitlements,
+		logger:          logger,
+		ctx:             ctx,
+		cancel:          cancel,
 	}
 }
 
@@ -31,6 +52,14 @@ func (s *customerService) GetCustomerList(ctx context.Context, req *models.Custo
 		return nil, i18n.NewI18nError("900001", lang) // 业务错误，不覆盖多语言message
 	}
 
+	if err := normalizeCustomFieldFilter(ctx, s.customFieldRepo, models.CustomFieldEntityCustomer, &req.CustomFieldFilter); err != nil {
+		return nil, err
+	}
+
+	if err := s.scopeCustomerListRequest(ctx, req); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
 	// 委托给Repository层进行数据访问
 	result, err := s.customerRepo.GetCustomerList(ctx, req)
 	if err != nil {
@@ -48,9 +77,69 @@ func (s *customerService) GetCustomerList(ctx context.Context, req *models.Custo
 		s.fillDisplayFields(&result.List[i], lang)
 	}
 
+	// 授权统计按客户层级汇总（包含全部下级客户），只加载当前页客户的下级，统计失败不影响列表返回
+	pageIDs := make([]string, 0, len(result.List))
+	for _, item := range result.List {
+		pageIDs = append(pageIDs, item.ID)
+	}
+	tree, err := loadCustomerSubtrees(ctx, s.customerRepo, pageIDs...)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	subtrees := make(map[string][]string, len(result.List))
+	var customerIDs []string
+	for _, item := range result.List {
+		subtrees[item.ID] = tree.subtree(item.ID)
+		customerIDs = append(customerIDs, subtrees[item.ID]...)
+	}
+	statsByCustomer, err := s.customerRepo.GetAuthorizationStatsByCustomers(ctx, customerIDs)
+	for i := range result.List {
+		item := &result.List[i]
+		item.SubsidiaryCount = len(tree.children[item.ID])
+		if err == nil {
+			item.AuthorizationStats = rollUpAuthorizationStats(statsByCustomer, subtrees[item.ID])
+		}
+	}
+
+	// 自定义字段和标签
+	ids := make([]string, 0, len(result.List))
+	for _, item := range result.List {
+		ids = append(ids, item.ID)
+	}
+	fields, err := s.customFieldRepo.GetEntityCustomFields(ctx, models.CustomFieldEntityCustomer, ids)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	for i := range result.List {
+		result.List[i].CustomFields = fields[result.List[i].ID].CustomFields
+		result.List[i].Tags = fields[result.List[i].ID].Tags
+	}
+
 	return result, nil
 }
 
+// scopeCustomerListRequest 按当前账号和客户层级补全列表筛选条件（列表查询与导出共用）
+func (s *customerService) scopeCustomerListRequest(ctx context.Context, req *models.CustomerListRequest) error {
+	// 经销商账号只能查看自己名下的客户
+	if partnerID := pkgcontext.GetPartnerIDFromContext(ctx); partnerID != "" {
+		req.PartnerID = partnerID
+	}
+
+	// 客户层级：按上级客户筛选直接下级，或包含所有层级的下级客户
+	if req.ParentID != "" {
+		tree, err := loadCustomerSubtrees(ctx, s.customerRepo, req.ParentID)
+		if err != nil {
+			return err
+		}
+		if req.IncludeSubsidiaries {
+			req.CustomerIDs = tree.subtree(req.ParentID)[1:]
+		} else {
+			req.CustomerIDs = append([]string{}, tree.children[req.ParentID]...)
+		}
+	}
+	return nil
+}
+
 // GetCustomer 获取单个客户详情
 func (s *customerService) GetCustomer(ctx context.Context, id string) (*models.Customer, error) {
 	lang := pkgcontext.GetLanguageFromContext(ctx)
@@ -72,15 +161,47 @@ func (s *customerService) GetCustomer(ctx context.Context, id string) (*models.C
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
-	// 获取授权统计信息
-	stats, err := s.customerRepo.GetCustomerAuthorizationStats(ctx, id)
+	if !customerInPartnerScope(pkgcontext.GetPartnerIDFromContext(ctx), customer) {
+		return nil, i18n.NewI18nError("200001", lang) // 不在经销商名下按客户不存在处理
+	}
+
+	// 获取授权统计信息（汇总全部下级客户）
+	subtree := []string{id}
+	if tree, err := loadCustomerSubtrees(ctx, s.customerRepo, id); err == nil {
+		subtree = tree.subtree(id)
+		customer.SubsidiaryCount = len(tree.children[id])
+	}
+	statsByCustomer, err := s.customerRepo.GetAuthorizationStatsByCustomers(ctx, subtree)
 	if err != nil {
 		// 统计信息获取失败不影响主流程，记录错误但继续执行
 		// 如果获取失败，stats 为 nil，客户详情仍然可以返回，只是没有统计信息
 		// 根据需求文档，空数据时应该返回0，所以这里创建一个空的统计对象
-		stats = &models.AuthorizationStats{}
+		statsByCustomer = map[string]*models.AuthorizationStats{}
+	}
+	customer.AuthorizationStats = rollUpAuthorizationStats(statsByCustomer, subtree)
+	if len(subtree) > 1 {
+		customer.OwnAuthorizationStats = rollUpAuthorizationStats(statsByCustomer, []string{id})
 	}
-	customer.AuthorizationStats = stats
+
+	// 席位池使用情况，获取失败不影响详情返回
+	if pool, err := s.seatPools.GetSeatPool(ctx, id); err == nil {
+		customer.SeatPool = pool
+	}
+
+	// 上级客户名称
+	if customer.ParentID != nil {
+		if parent, err := s.customerRepo.GetCustomerByID(ctx, *customer.ParentID); err == nil {
+			customer.ParentName = parent.CustomerName
+		}
+	}
+
+	// 自定义字段和标签
+	fields, err := s.customFieldRepo.GetEntityCustomFields(ctx, models.CustomFieldEntityCustomer, []string{id})
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	customer.CustomFields = fields[id].CustomFields
+	customer.Tags = fields[id].Tags
 
 	// 填充多语言显示字段
 	s.fillCustomerDisplayFields(customer, lang)
@@ -108,9 +229,29 @@ func (s *customerService) CreateCustomer(ctx context.Context, req *models.Custom
 	// TODO: 实现用户上下文获取，这里暂时使用硬编码
 	currentUserID := "admin_uuid" // 实际应该从JWT token中获取
 
+	// 校验上级客户
+	parentID, err := s.resolveParentCustomer(ctx, req.ParentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 所属经销商：经销商账号创建的客户归属自身，管理员可指定经销商
+	partnerID, err := s.resolvePartner(ctx, req.PartnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 自定义字段和标签在创建客户前校验，必填字段必须提供
+	customFields, tags, err := prepareEntityCustomFields(ctx, s.customFieldRepo, models.CustomFieldEntityCustomer, req.CustomFields, req.Tags, true)
+	if err != nil {
+		return nil, err
+	}
+
 	// 构建客户实体
 	customer := &models.Customer{
 		CustomerName:  req.CustomerName,
+		ParentID:      parentID,
+		PartnerID:     partnerID,
 		CustomerType:  req.CustomerType,
 		ContactPerson: req.ContactPerson,
 		ContactTitle:  req.ContactTitle,
@@ -137,12 +278,40 @@ func (s *customerService) CreateCustomer(ctx context.Context, req *models.Custom
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
+	if err := s.customFieldRepo.SaveEntityCustomFields(ctx, models.CustomFieldEntityCustomer, []string{customer.ID}, customFields, tags); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	customer.CustomFields = customFields
+	customer.Tags = tags
+
 	// 填充多语言显示字段
 	s.fillCustomerDisplayFields(customer, lang)
 
 	return customer, nil
 }
 
+// UpdateCustomerCustomFields 修改客户的自定义字段和标签
+func (s *customerService) UpdateCustomerCustomFields(ctx context.Context, id string, req *models.EntityCustomFieldsUpdateRequest) (*models.EntityCustomFields, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if id == "" || req == nil {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+
+	customer, err := s.customerRepo.GetCustomerByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrCustomerNotFound) {
+			return nil, i18n.NewI18nError("200001", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if !customerInPartnerScope(pkgcontext.GetPartnerIDFromContext(ctx), customer) {
+		return nil, i18n.NewI18nError("200001", lang) // 不在经销商名下按客户不存在处理
+	}
+
+	return updateEntityCustomFields(ctx, s.customFieldRepo, models.CustomFieldEntityCustomer, id, req)
+}
+
 // UpdateCustomer 更新客户信息
 func (s *customerService) UpdateCustomer(ctx context.Context, id string, req *models.CustomerUpdateRequest) (*models.Customer, error) {
 	lang := pkgcontext.GetLanguageFromContext(ctx)
@@ -164,6 +333,10 @@ func (s *customerService) UpdateCustomer(ctx context.Context, id string, req *mo
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
+	if !customerInPartnerScope(pkgcontext.GetPartnerIDFromContext(ctx), existingCustomer) {
+		return nil, i18n.NewI18nError("200001", lang) // 不在经销商名下按客户不存在处理
+	}
+
 	// 获取当前用户ID
 	currentUserID := "admin_uuid" // TODO: 从JWT token中获取
 
@@ -171,6 +344,22 @@ func (s *customerService) UpdateCustomer(ctx context.Context, id string, req *mo
 	if req.CustomerName != nil {
 		existingCustomer.CustomerName = *req.CustomerName
 	}
+	if req.ParentID != nil {
+		// 传空字符串表示取消上级；设置上级时保存前在事务中校验不能形成循环
+		parentID, err := s.resolveParentCustomer(ctx, req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		existingCustomer.ParentID = parentID
+	}
+	if req.PartnerID != nil && pkgcontext.GetPartnerIDFromContext(ctx) == "" {
+		// 仅管理员可调整所属经销商，传空字符串表示转为直营
+		partnerID, err := s.resolvePartner(ctx, req.PartnerID)
+		if err != nil {
+			return nil, err
+		}
+		existingCustomer.PartnerID = partnerID
+	}
 	if req.CustomerType != nil {
 		existingCustomer.CustomerType = *req.CustomerType
 	}
@@ -205,8 +394,20 @@ func (s *customerService) UpdateCustomer(ctx context.Context, id string, req *mo
 	// 设置更新者
 	existingCustomer.UpdatedBy = &currentUserID
 
-	// 委托给Repository层进行数据更新
-	if err := s.customerRepo.UpdateCustomer(ctx, existingCustomer); err != nil {
+	// 委托给Repository层进行数据更新，调整上级客户时在同一事务中加锁校验不会形成循环
+	update := s.customerRepo.UpdateCustomer
+	if req.ParentID != nil {
+		update = s.customerRepo.UpdateCustomerWithParent
+	}
+	if err := update(ctx, existingCustomer); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCustomerHierarchyCycle):
+			return nil, i18n.NewI18nError("200011", lang) // 不能将客户自身或其下级设为上级
+		case errors.Is(err, repository.ErrCustomerParentNotFound):
+			return nil, i18n.NewI18nError("200010", lang) // 上级客户不存在
+		case errors.Is(err, repository.ErrCustomerNotFound):
+			return nil, i18n.NewI18nError("200001", lang)
+		}
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
@@ -216,7 +417,7 @@ func (s *customerService) UpdateCustomer(ctx context.Context, id string, req *mo
 	return existingCustomer, nil
 }
 
-// DeleteCustomer 删除客户（物理删除，删除前检查是否有授权）
+// DeleteCustomer 删除客户（软删除移入回收站，删除前检查是否有授权）
 func (s *customerService) DeleteCustomer(ctx context.Context, id string) error {
 	lang := pkgcontext.GetLanguageFromContext(ctx)
 
@@ -226,13 +427,25 @@ func (s *customerService) DeleteCustomer(ctx context.Context, id string) error {
 	}
 
 	// 检查客户是否存在
-	_, err := s.customerRepo.GetCustomerByID(ctx, id)
+	customer, err := s.customerRepo.GetCustomerByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrCustomerNotFound) {
 			return i18n.NewI18nError("200001", lang)
 		}
 		return i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if !customerInPartnerScope(pkgcontext.GetPartnerIDFromContext(ctx), customer) {
+		return i18n.NewI18nError("200001", lang)
+	}
+
+	// 检查是否有下级客户
+	tree, err := loadCustomerSubtrees(ctx, s.customerRepo, id)
+	if err != nil {
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if len(tree.children[id]) > 0 {
+		return i18n.NewI18nError("200012", lang) // 客户仍有下级客户
+	}
 
 	// 检查是否有关联的授权码
 	hasAuthCodes, err := s.customerRepo.CheckCustomerHasAuthorizationCodes(ctx, id)
@@ -252,7 +465,7 @@ func (s *customerService) DeleteCustomer(ctx context.Context, id string) error {
 		return i18n.NewI18nError("200008", lang)
 	}
 
-	// 委托给Repository层进行物理删除
+	// 委托给Repository层进行软删除
 	if err := s.customerRepo.DeleteCustomer(ctx, id); err != nil {
 		if errors.Is(err, repository.ErrCustomerNotFound) {
 			return i18n.NewI18nError("200001", lang)
@@ -284,6 +497,10 @@ func (s *customerService) UpdateCustomerStatus(ctx context.Context, id string, r
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
+	if !customerInPartnerScope(pkgcontext.GetPartnerIDFromContext(ctx), existingCustomer) {
+		return nil, i18n.NewI18nError("200001", lang) // 不在经销商名下按客户不存在处理
+	}
+
 	// 获取当前用户ID
 	currentUserID := "admin_uuid" // TODO: 从JWT token中获取
 
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+)
+
+// customerTimelineStatusEnums 各对象类型的状态对应的枚举类型，授权变更没有状态
+var customerTimelineStatusEnums = map[string]string{
+	models.TimelineEntityCustomer:          "customer_status",
+	models.TimelineEntityAuthorizationCode: "authorization_code_status",
+	models.TimelineEntityLicense:           "license_status",
+	models.TimelineEntityOrder:             "order_status",
+	models.TimelineEntityPayment:           "payment_status",
+	models.TimelineEntityInvoice:           "invoice_status",
+}
+
+// GetCustomerTimeline 获取客户360时间线，合并客户、授权码、授权变更、许可证、订单、支付和发票的事件，按发生时间倒序分页
+func (s *customerService) GetCustomerTimeline(ctx context.Context, id string, req *models.CustomerTimelineRequest) (*models.CustomerTimelineResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	from, to, err := parseCustomerTimelineRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, i18n.NewI18nError("900001", lang, err.Error())
+	}
+	req.From, req.To = from, to
+
+	if _, err := s.customerRepo.GetCustomerByID(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrCustomerNotFound) {
+			return nil, i18n.NewI18nError("200001", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	req.CustomerIDs = []string{id}
+	if req.IncludeSubsidiaries {
+		ids, err := expandCustomerSubtree(ctx, s.customerRepo, id)
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		req.CustomerIDs = ids
+	}
+
+	entries, total, err := s.customerRepo.GetCustomerTimeline(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	for _, entry := range entries {
+		fillCustomerTimelineDisplay(entry, lang)
+	}
+
+	return &models.CustomerTimelineResponse{
+		List:     entries,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// parseCustomerTimelineRange 解析时间线日期范围，结束日期包含当天，返回左闭右开区间
+func parseCustomerTimelineRange(startDate, endDate string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if startDate != "" {
+		start, err := time.ParseInLocation("2006-01-02", startDate, time.Local)
+		if err != nil {
+			return nil, nil, errors.New("invalid start_date, expected YYYY-MM-DD")
+		}
+		from = &start
+	}
+	if endDate != "" {
+		end, err := time.ParseInLocation("2006-01-02", endDate, time.Local)
+		if err != nil {
+			return nil, nil, errors.New("invalid end_date, expected YYYY-MM-DD")
+		}
+		end = end.AddDate(0, 0, 1)
+		to = &end
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, errors.New("start_date must not be after end_date")
+	}
+	return from, to, nil
+}
+
+// fillCustomerTimelineDisplay 填充时间线条目的多语言显示字段
+func fillCustomerTimelineDisplay(entry *models.CustomerTimelineEntry, lang string) {
+	entry.EntityTypeDisplay = i18n.GetEnumMessage("customer_timeline_entity_type", entry.EntityType, lang)
+	if entry.EntityType == models.TimelineEntityAuthorizationChange {
+		entry.EventDisplay = i18n.GetEnumMessage("authorization_change_type", entry.Event, lang)
+	} else {
+		entry.EventDisplay = i18n.GetEnumMessage("customer_timeline_event", entry.Event, lang)
+	}
+	if enumType, ok := customerTimelineStatusEnums[entry.EntityType]; ok && entry.Status != nil {
+		entry.StatusDisplay = i18n.GetEnumMessage(enumType, *entry.Status, lang)
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+)
+
+func TestParseCustomerTimelineRange(t *testing.T) {
+	from, to, err := parseCustomerTimelineRange("2024-03-01", "2024-03-31")
+	if err != nil {
+		t.Fatalf("parseCustomerTimelineRange: %v", err)
+	}
+	if want := time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local); !from.Equal(want) {
+		t.Errorf("from = %v, want %v", from, want)
+	}
+	// 结束日期包含当天，区间右端为次日零点
+	if want := time.Date(2024, 4, 1, 0, 0, 0, 0, time.Local); !to.Equal(want) {
+		t.Errorf("to = %v, want %v", to, want)
+	}
+
+	from, to, err = parseCustomerTimelineRange("", "")
+	if err != nil || from != nil || to != nil {
+		t.Errorf("empty range = %v, %v, %v, want nil bounds", from, to, err)
+	}
+
+	if _, _, err := parseCustomerTimelineRange("2024-03-01", "2024-03-01"); err != nil {
+		t.Errorf("single day range: unexpected error %v", err)
+	}
+
+	invalid := [][2]string{
+		{"2024-03-02", "2024-03-01"},
+		{"2024/03/01", ""},
+		{"", "tomorrow"},
+	}
+	for _, r := range invalid {
+		if _, _, err := parseCustomerTimelineRange(r[0], r[1]); err == nil {
+			t.Errorf("range %q: expected error", r)
+		}
+	}
+}
+
+func TestFillCustomerTimelineDisplay(t *testing.T) {
+	status := "paid"
+	entry := &models.CustomerTimelineEntry{EntityType: models.TimelineEntityPayment, Event: "paid", Status: &status}
+	fillCustomerTimelineDisplay(entry, "en-US")
+	if entry.EntityTypeDisplay == "" || entry.EventDisplay == "" || entry.StatusDisplay == "" {
+		t.Errorf("payment entry display not filled: %+v", entry)
+	}
+
+	change := &models.CustomerTimelineEntry{EntityType: models.TimelineEntityAuthorizationChange, Event: "lock"}
+	fillCustomerTimelineDisplay(change, "en-US")
+	if change.EventDisplay == "" {
+		t.Errorf("change event display not filled: %+v", change)
+	}
+	if change.StatusDisplay != "" {
+		t.Errorf("change entry has no status, got status display %q", change.StatusDisplay)
+	}
+}
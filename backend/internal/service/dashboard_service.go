@@ -31,77 +31,9 @@ func (s *dashboardService) GetAuthorizationTrend(ctx context.Context, req *model
 		return nil, i18n.NewI18nError("900001", lang)
 	}
 
-	var startDate, endDate time.Time
-	var descriptionDisplay string
-	
-	// 获取时区，默认使用本地时区
-	loc := time.Local
-	if req.Timezone != "" {
-		var err error
-		loc, err = time.LoadLocation(req.Timezone)
-		if err != nil {
-			// 时区解析失败，使用本地时区
-			loc = time.Local
-		}
-	}
-	
-	now := time.Now().In(loc)
-
-	// 根据类型计算时间范围
-	switch req.Type {
-	case "week":
-		// 本周：从周一到周日
-		weekday := int(now.Weekday())
-		if weekday == 0 { // Sunday
-			weekday = 7
-		}
-		startDate = now.AddDate(0, 0, -(weekday-1))
-		startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, loc)
-		endDate = startDate.AddDate(0, 0, 6)
-		endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, loc)
-		descriptionDisplay = i18n.GetEnumMessage("dashboard_period", "week", lang)
-
-	case "month":
-		// 本月：从1号到月末
-		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
-		endDate = startDate.AddDate(0, 1, -1)
-		endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, loc)
-		descriptionDisplay = i18n.GetEnumMessage("dashboard_period", "month", lang)
-
-	case "custom":
-		// 自定义时间范围
-		if req.StartDate == "" || req.EndDate == "" {
-			return nil, i18n.NewI18nError("400002", lang) // 开始日期和结束日期都是必填的
-		}
-
-		var err error
-		startDate, err = time.ParseInLocation("2006-01-02", req.StartDate, loc)
-		if err != nil {
-			return nil, i18n.NewI18nError("400002", lang) // 开始日期格式错误
-		}
-
-		endDate, err = time.ParseInLocation("2006-01-02", req.EndDate, loc)
-		if err != nil {
-			return nil, i18n.NewI18nError("400003", lang) // 结束日期格式错误
-		}
-
-		// 验证时间范围
-		if startDate.After(endDate) {
-			return nil, i18n.NewI18nError("400005", lang) // 开始日期不能晚于结束日期
-		}
-
-		// 验证时间跨度不超过一年
-		if endDate.Sub(startDate) > 365*24*time.Hour {
-			return nil, i18n.NewI18nError("400004", lang) // 时间范围超过限制
-		}
-
-		// 设置为全天
-		startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
-		endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, endDate.Location())
-		descriptionDisplay = fmt.Sprintf("%s - %s", req.StartDate, req.EndDate)
-
-	default:
-		return nil, i18n.NewI18nError("400001", lang) // 时间类型参数错误
+	startDate, endDate, descriptionDisplay, err := resolveDashboardPeriod(req.Type, req.StartDate, req.EndDate, req.Timezone, lang)
+	if err != nil {
+		return nil, err
 	}
 
 	// 获取趋势数据
@@ -171,4 +103,112 @@ func (s *dashboardService) GetRecentAuthorizations(ctx context.Context, req *mod
 
 	// 调用repository获取数据
 	return s.dashboardRepo.GetRecentAuthorizations(ctx, req)
-}
\ No newline at end of file
+}
+
+// GetLeadConversion 获取线索转化客户统计
+func (s *dashboardService) GetLeadConversion(ctx context.Context, req *models.DashboardLeadConversionRequest) (*models.DashboardLeadConversionResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if req == nil {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+
+	startDate, endDate, descriptionDisplay, err := resolveDashboardPeriod(req.Type, req.StartDate, req.EndDate, req.Timezone, lang)
+	if err != nil {
+		return nil, err
+	}
+
+	trendData, err := s.dashboardRepo.GetLeadConversionTrendData(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.dashboardRepo.GetLeadConversionSummary(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DashboardLeadConversionResponse{
+		Period: models.TrendPeriod{
+			Type:               req.Type,
+			StartDate:          startDate.Format("2006-01-02"),
+			EndDate:            endDate.Format("2006-01-02"),
+			DescriptionDisplay: descriptionDisplay,
+		},
+		TrendData: trendData,
+		Summary:   *summary,
+	}, nil
+}
+
+// resolveDashboardPeriod 根据时间类型（本周/本月/自定义）计算统计时间范围及多语言描述
+func resolveDashboardPeriod(periodType, startDateStr, endDateStr, timezone, lang string) (startDate, endDate time.Time, descriptionDisplay string, err error) {
+	// 获取时区，默认使用本地时区
+	loc := time.Local
+	if timezone != "" {
+		loc, err = time.LoadLocation(timezone)
+		if err != nil {
+			// 时区解析失败，使用本地时区
+			loc = time.Local
+		}
+	}
+	
+	now := time.Now().In(loc)
+
+	// 根据类型计算时间范围
+	switch periodType {
+	case "week":
+		// 本周：从周一到周日
+		weekday := int(now.Weekday())
+		if weekday == 0 { // Sunday
+			weekday = 7
+		}
+		startDate = now.AddDate(0, 0, -(weekday-1))
+		startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, loc)
+		endDate = startDate.AddDate(0, 0, 6)
+		endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, loc)
+		descriptionDisplay = i18n.GetEnumMessage("dashboard_period", "week", lang)
+
+	case "month":
+		// 本月：从1号到月末
+		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+		endDate = startDate.AddDate(0, 1, -1)
+		endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, loc)
+		descriptionDisplay = i18n.GetEnumMessage("dashboard_period", "month", lang)
+
+	case "custom":
+		// 自定义时间范围
+		if startDateStr == "" || endDateStr == "" {
+			return time.Time{}, time.Time{}, "", i18n.NewI18nError("400002", lang) // 开始日期和结束日期都是必填的
+		}
+
+		startDate, err = time.ParseInLocation("2006-01-02", startDateStr, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", i18n.NewI18nError("400002", lang) // 开始日期格式错误
+		}
+
+		endDate, err = time.ParseInLocation("2006-01-02", endDateStr, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, "", i18n.NewI18nError("400003", lang) // 结束日期格式错误
+		}
+
+		// 验证时间范围
+		if startDate.After(endDate) {
+			return time.Time{}, time.Time{}, "", i18n.NewI18nError("400005", lang) // 开始日期不能晚于结束日期
+		}
+
+		// 验证时间跨度不超过一年
+		if endDate.Sub(startDate) > 365*24*time.Hour {
+			return time.Time{}, time.Time{}, "", i18n.NewI18nError("400004", lang) // 时间范围超过限制
+		}
+
+		// 设置为全天
+		startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
+		endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 23, 59, 59, 999999999, endDate.Location())
+		descriptionDisplay = fmt.Sprintf("%s - %s", startDateStr, endDateStr)
+
+	default:
+		return time.Time{}, time.Time{}, "", i18n.NewI18nError("400001", lang) // 时间类型参数错误
+	}
+
+	return startDate, endDate, descriptionDisplay, nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"license-manager/internal/config"
+	"license-manager/internal/models"
+)
+
+// 部署类型规则校验错误码
+const (
+	errCodeCloudOfflineActivation = "301001" // 云部署授权码不支持离线激活
+	errCodeRelayRequired          = "301002" // 混合部署须通过指定的本地中继激活和心跳
+	errCodeRelayNotSupported      = "301004" // 仅混合部署授权码支持中继激活
+	errCodeCloudLicenseFile       = "301005" // 云部署许可证不支持导出离线许可证文件
+	errCodeRelaySignatureInvalid  = "301006" // 中继签名无效或已过期
+)
+
+// normalizeActivationMode 规范化激活方式，未指定时为在线激活；无法识别时返回空字符串
+func normalizeActivationMode(mode string) string {
+	switch mode {
+	case "":
+		return models.ActivationModeOnline
+	case models.ActivationModeOnline, models.ActivationModeOffline, models.ActivationModeRelay:
+		return mode
+	}
+	return ""
+}
+
+// normalizeRelayFingerprint 去除首尾空白，空字符串表示未指定中继
+func normalizeRelayFingerprint(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// relaySignatureWindow 中继签名时间与服务器时间允许的最大偏差
+const relaySignatureWindow = 5 * time.Minute
+
+// relayProof 经本地中继转发的请求附带的中继签名
+type relayProof struct {
+	Timestamp int64  // 中继签名时间（Unix 秒）
+	Signature string // 中继签名（十六进制）
+}
+
+// relayDesignated 授权码是否已指定本地中继节点
+func relayDesignated(authCode *models.AuthorizationCode) bool {
+	return authCode.RelayFingerprint != nil && *authCode.RelayFingerprint != ""
+}
+
+// isRelayDevice 设备是否为授权码指定的本地中继节点
+func isRelayDevice(authCode *models.AuthorizationCode, hardwareFingerprint string) bool {
+	return relayDesignated(authCode) && *authCode.RelayFingerprint == hardwareFingerprint
+}
+
+// relaySignature 中继使用自身的许可证密钥对转发的设备请求签名：HMAC-SHA256(许可证密钥, "硬件指纹\n时间戳")
+func relaySignature(relayLicenseKey, hardwareFingerprint string, timestamp int64) string {
+	mac := hmac.New(sha256.New, []byte(relayLicenseKey))
+	mac.Write([]byte(hardwareFingerprint + "\n" + strconv.FormatInt(timestamp, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyRelayProof 校验请求确由持有中继许可证密钥的中继签名，且签名时间在允许偏差内
+// 中继指纹会随设备信息暴露，只有许可证密钥能证明请求经过中继
+func verifyRelayProof(relayLicense *models.License, hardwareFingerprint string, proof relayProof, now time.Time) bool {
+	if relayLicense == nil || proof.Signature == "" {
+		return false
+	}
+	signedAt := time.Unix(proof.Timestamp, 0)
+	if now.Sub(signedAt) > relaySignatureWindow || signedAt.Sub(now) > relaySignatureWindow {
+		return false
+	}
+	expected := relaySignature(relayLicense.LicenseKey, hardwareFingerprint, proof.Timestamp)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(proof.Signature)))
+}
+
+// checkRelayProof 混合部署：非中继节点的设备必须经指定中继转发请求，并附带中继签名
+// relayLicense 为中继节点的许可证，中继尚未激活时为 nil
+func checkRelayProof(relayLicense *models.License, hardwareFingerprint string, proof relayProof, now time.Time) string {
+	if relayLicense == nil {
+		return errCodeRelayRequired
+	}
+	if !verifyRelayProof(relayLicense, hardwareFingerprint, proof, now) {
+		return errCodeRelaySignatureInvalid
+	}
+	return ""
+}
+
+// checkActivationDeployment 校验激活方式是否符合授权码的部署类型，返回错误码，符合时返回空字符串
+// standalone：在线或离线激活；cloud：仅在线激活；hybrid：经指定中继激活，尚未指定中继时与心跳一致不做限制。
+// 中继节点首次激活时尚无许可证可供签名，可直接激活；之后重新激活须使用已有的中继许可证密钥签名，防止他人冒用中继指纹
+func checkActivationDeployment(authCode *models.AuthorizationCode, mode, hardwareFingerprint string, relayLicense *models.License, proof relayProof, now time.Time) string {
+	switch authCode.DeploymentType {
+	case models.DeploymentTypeHybrid:
+		if !relayDesignated(authCode) {
+			return ""
+		}
+		if mode == models.ActivationModeOffline {
+			return errCodeRelayRequired
+		}
+		if isRelayDevice(authCode, hardwareFingerprint) && relayLicense == nil {
+			return ""
+		}
+		return checkRelayProof(relayLicense, hardwareFingerprint, proof, now)
+	case models.DeploymentTypeCloud:
+		if mode == models.ActivationModeOffline {
+			return errCodeCloudOfflineActivation
+		}
+	}
+	if mode == models.ActivationModeRelay {
+		return errCodeRelayNotSupported
+	}
+	return ""
+}
+
+// checkHeartbeatDeployment 校验心跳是否符合授权码的部署类型
+// 混合部署指定中继后，非中继节点的设备须经中继转发心跳；尚未指定中继的授权码（如规则上线前创建的）不做限制
+// 中继节点自身的心跳已由其许可证密钥认证
+func checkHeartbeatDeployment(authCode *models.AuthorizationCode, hardwareFingerprint string, relayLicense *models.License, proof relayProof, now time.Time) string {
+	if authCode.DeploymentType != models.DeploymentTypeHybrid || !relayDesignated(authCode) {
+		return ""
+	}
+	if isRelayDevice(authCode, hardwareFingerprint) {
+		return ""
+	}
+	return checkRelayProof(relayLicense, hardwareFingerprint, proof, now)
+}
+
+// effectiveActivationMode 记录到许可证的激活方式：混合部署中经中继激活的设备记为 relay
+func effectiveActivationMode(authCode *models.AuthorizationCode, mode, hardwareFingerprint string) string {
+	if authCode.DeploymentType == models.DeploymentTypeHybrid {
+		if isRelayDevice(authCode, hardwareFingerprint) {
+			return models.ActivationModeOnline
+		}
+		return models.ActivationModeRelay
+	}
+	return mode
+}
+
+// deploymentOfflineGrace 部署类型对应的离线容忍时间
+func deploymentOfflineGrace(deploymentType string, grace config.OfflineGraceConfig) time.Duration {
+	switch deploymentType {
+	case models.DeploymentTypeCloud:
+		return grace.Cloud
+	case models.DeploymentTypeHybrid:
+		return grace.Hybrid
+	}
+	return grace.Standalone
+}
+
+// buildDeploymentPolicy 生成下发给客户端的部署类型规则
+func buildDeploymentPolicy(authCode *models.AuthorizationCode, activationMode string, grace config.OfflineGraceConfig) *models.DeploymentPolicy {
+	if activationMode == "" {
+		activationMode = models.ActivationModeOnline
+	}
+	policy := &models.DeploymentPolicy{
+		DeploymentType:      authCode.DeploymentType,
+		ActivationMode:      activationMode,
+		RequiresHeartbeat:   authCode.DeploymentType != models.DeploymentTypeStandalone,
+		OfflineGraceSeconds: int64(deploymentOfflineGrace(authCode.DeploymentType, grace) / time.Second),
+	}
+	return policy
+}
+
+// offlineGraceConfig 读取离线容忍时间配置
+func offlineGraceConfig() config.OfflineGraceConfig {
+	if cfg := config.GetConfig(); cfg != nil {
+		return cfg.License.OfflineGrace
+	}
+	return config.OfflineGraceConfig{}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"license-manager/internal/config"
+	"license-manager/internal/models"
+)
+
+func TestCheckActivationDeployment(t *testing.T) {
+	relay := "RELAY-FP"
+	now := time.Unix(1700000000, 0)
+	relayLicense := &models.License{HardwareFingerprint: relay, LicenseKey: "LIC-DEVICE-RELAY"}
+	signed := relayProof{Timestamp: now.Unix(), Signature: relaySignature(relayLicense.LicenseKey, "DEV", now.Unix())}
+	forged := relayProof{Timestamp: now.Unix(), Signature: relaySignature("LIC-DEVICE-OTHER", "DEV", now.Unix())}
+	stale := relayProof{Timestamp: now.Add(-time.Hour).Unix(), Signature: relaySignature(relayLicense.LicenseKey, "DEV", now.Add(-time.Hour).Unix())}
+	relaySigned := relayProof{Timestamp: now.Unix(), Signature: relaySignature(relayLicense.LicenseKey, relay, now.Unix())}
+
+	standalone := &models.AuthorizationCode{DeploymentType: models.DeploymentTypeStandalone}
+	cloud := &models.AuthorizationCode{DeploymentType: models.DeploymentTypeCloud}
+	hybrid := &models.AuthorizationCode{DeploymentType: models.DeploymentTypeHybrid, RelayFingerprint: &relay}
+	hybridNoRelay := &models.AuthorizationCode{DeploymentType: models.DeploymentTypeHybrid}
+
+	cases := []struct {
+		name         string
+		authCode     *models.AuthorizationCode
+		mode         string
+		fingerprint  string
+		relayLicense *models.License
+		proof        relayProof
+		want         string
+	}{
+		{"standalone online", standalone, models.ActivationModeOnline, "DEV", nil, relayProof{}, ""},
+		{"standalone offline", standalone, models.ActivationModeOffline, "DEV", nil, relayProof{}, ""},
+		{"standalone relay", standalone, models.ActivationModeRelay, "DEV", nil, signed, errCodeRelayNotSupported},
+		{"cloud online", cloud, models.ActivationModeOnline, "DEV", nil, relayProof{}, ""},
+		{"cloud offline", cloud, models.ActivationModeOffline, "DEV", nil, relayProof{}, errCodeCloudOfflineActivation},
+		{"cloud relay", cloud, models.ActivationModeRelay, "DEV", nil, signed, errCodeRelayNotSupported},
+		{"hybrid via relay", hybrid, models.ActivationModeRelay, "DEV", relayLicense, signed, ""},
+		{"hybrid relay first activation", hybrid, models.ActivationModeOnline, relay, nil, relayProof{}, ""},
+		{"hybrid relay reactivation unsigned", hybrid, models.ActivationModeOnline, relay, relayLicense, relayProof{}, errCodeRelaySignatureInvalid},
+		{"hybrid relay reactivation signed", hybrid, models.ActivationModeOnline, relay, relayLicense, relaySigned, ""},
+		{"hybrid relay not activated", hybrid, models.ActivationModeRelay, "DEV", nil, signed, errCodeRelayRequired},
+		{"hybrid direct", hybrid, models.ActivationModeOnline, "DEV", relayLicense, relayProof{}, errCodeRelaySignatureInvalid},
+		{"hybrid forged signature", hybrid, models.ActivationModeRelay, "DEV", relayLicense, forged, errCodeRelaySignatureInvalid},
+		{"hybrid stale signature", hybrid, models.ActivationModeRelay, "DEV", relayLicense, stale, errCodeRelaySignatureInvalid},
+		{"hybrid offline", hybrid, models.ActivationModeOffline, "DEV", relayLicense, signed, errCodeRelayRequired},
+		{"hybrid no relay via relay", hybridNoRelay, models.ActivationModeRelay, "DEV", nil, signed, ""},
+		{"hybrid no relay online", hybridNoRelay, models.ActivationModeOnline, "DEV", nil, relayProof{}, ""},
+		{"hybrid no relay offline", hybridNoRelay, models.ActivationModeOffline, "DEV", nil, relayProof{}, ""},
+	}
+	for _, c := range cases {
+		if got := checkActivationDeployment(c.authCode, c.mode, c.fingerprint, c.relayLicense, c.proof, now); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+
+	if got := checkHeartbeatDeployment(hybrid, "DEV", relayLicense, relayProof{}, now); got != errCodeRelaySignatureInvalid {
+		t.Errorf("hybrid heartbeat without relay signature: got %q", got)
+	}
+	if got := checkHeartbeatDeployment(hybrid, "DEV", relayLicense, signed, now); got != "" {
+		t.Errorf("hybrid heartbeat via relay: got %q", got)
+	}
+	if got := checkHeartbeatDeployment(hybrid, relay, relayLicense, relayProof{}, now); got != "" {
+		t.Errorf("hybrid relay heartbeat: got %q", got)
+	}
+	if got := checkHeartbeatDeployment(hybridNoRelay, "DEV", nil, relayProof{}, now); got != "" {
+		t.Errorf("hybrid heartbeat before relay designated: got %q", got)
+	}
+	if got := checkHeartbeatDeployment(cloud, "DEV", nil, relayProof{}, now); got != "" {
+		t.Errorf("cloud heartbeat: got %q", got)
+	}
+	if got := effectiveActivationMode(hybrid, models.ActivationModeOnline, "DEV"); got != models.ActivationModeRelay {
+		t.Errorf("hybrid device mode: got %q", got)
+	}
+}
+
+func TestBuildDeploymentPolicy(t *testing.T) {
+	grace := config.OfflineGraceConfig{Standalone: 720 * time.Hour, Cloud: 30 * time.Minute, Hybrid: 72 * time.Hour}
+
+	policy := buildDeploymentPolicy(&models.AuthorizationCode{DeploymentType: models.DeploymentTypeCloud}, "", grace)
+	if !policy.RequiresHeartbeat || policy.OfflineGraceSeconds != 1800 || policy.ActivationMode != models.ActivationModeOnline {
+		t.Fatalf("unexpected cloud policy: %+v", policy)
+	}
+
+	policy = buildDeploymentPolicy(&models.AuthorizationCode{DeploymentType: models.DeploymentTypeStandalone}, models.ActivationModeOffline, grace)
+	if policy.RequiresHeartbeat || policy.OfflineGraceSeconds != 720*3600 {
+		t.Fatalf("unexpected standalone policy: %+v", policy)
+	}
+}
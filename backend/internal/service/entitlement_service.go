@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	"license-manager/pkg/cache"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+	"license-manager/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 授权检查缓存键的范围
+const (
+	entitlementScopeCode     = "code"
+	entitlementScopeCustomer = "customer"
+)
+
+// EntitlementInvalidator 授权码变更后使授权检查缓存失效
+type EntitlementInvalidator interface {
+	InvalidateAuthorizationCode(ctx context.Context, authCode *models.AuthorizationCode)
+	// InvalidateCustomers 清除客户及其全部授权码的授权检查缓存，用于授权码在客户间迁移后
+	InvalidateCustomers(ctx context.Context, customerIDs ...string)
+}
+
+// EntitlementService 云部署授权检查服务接口
+type EntitlementService interface {
+	EntitlementInvalidator
+	CheckEntitlement(ctx context.Context, req *models.EntitlementCheckRequest) (*models.EntitlementCheckResponse, error)
+}
+
+type entitlementService struct {
+	entitlementRepo repository.EntitlementRepository
+	cache           cache.Cache
+	keys            *cache.KeyBuilder
+	ttl             time.Duration
+	logger          *logrus.Logger
+}
+
+// NewEntitlementService 创建授权检查服务，解析结果按 ttl 缓存
+func NewEntitlementService(entitlementRepo repository.EntitlementRepository, cacheInstance cache.Cache, ttl time.Duration, logger *logrus.Logger) EntitlementService {
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+	return &entitlementService{
+		entitlementRepo: entitlementRepo,
+		cache:           cacheInstance,
+		keys:            cache.NewKeyBuilder(""),
+		ttl:             ttl,
+		logger:          logger,
+	}
+}
+
+// CheckEntitlement 按授权码或客户查询当前有效的功能、限制和剩余配额，可同时检查指定功能
+func (s *entitlementService) CheckEntitlement(ctx context.Context, req *models.EntitlementCheckRequest) (*models.EntitlementCheckResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	code := utils.NormalizeAuthorizationCode(strings.TrimSpace(req.AuthorizationCode))
+	customerID := strings.TrimSpace(req.CustomerID)
+	if (code == "") == (customerID == "") {
+		return nil, i18n.NewI18nError("900001", lang, "exactly one of authorization_code and customer_id is required")
+	}
+
+	scope, id := entitlementScopeCustomer, customerID
+	if code != "" {
+		scope, id = entitlementScopeCode, code
+	}
+	cacheKey := s.keys.Entitlement(scope, id)
+
+	snapshot, cached := s.getCachedSnapshot(ctx, cacheKey)
+	if snapshot == nil {
+		var err error
+		snapshot, err = s.resolveSnapshot(ctx, code, customerID)
+		if err != nil {
+			return nil, err
+		}
+		s.setCachedSnapshot(ctx, cacheKey, snapshot)
+	}
+
+	response := &models.EntitlementCheckResponse{
+		Allowed:             snapshot.Reason == models.EntitlementReasonActive,
+		Feature:             strings.TrimSpace(req.Feature),
+		Cached:              cached,
+		EntitlementSnapshot: *snapshot,
+	}
+	if response.Allowed && response.Feature != "" && !entitlementFeatureEnabled(snapshot.Features, response.Feature) {
+		response.Allowed = false
+		response.Reason = models.EntitlementReasonFeatureNotEnabled
+	}
+	return response, nil
+}
+
+// InvalidateAuthorizationCode 清除授权码及其客户的授权检查缓存
+func (s *entitlementService) InvalidateAuthorizationCode(ctx context.Context, authCode *models.AuthorizationCode) {
+	if s.cache == nil || authCode == nil {
+		return
+	}
+	keys := []string{s.keys.Entitlement(entitlementScopeCode, authCode.Code)}
+	if authCode.CustomerID != "" {
+		keys = append(keys, s.keys.Entitlement(entitlementScopeCustomer, authCode.CustomerID))
+	}
+	if err := s.cache.Del(ctx, keys...); err != nil {
+		s.logger.WithError(err).WithField("authorization_code_id", authCode.ID).Warn("清除授权检查缓存失败")
+	}
+}
+
+// InvalidateCustomers 清除客户及其当前全部授权码的授权检查缓存
+func (s *entitlementService) InvalidateCustomers(ctx context.Context, customerIDs ...string) {
+	if s.cache == nil || len(customerIDs) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(customerIDs))
+	for _, customerID := range customerIDs {
+		keys = append(keys, s.keys.Entitlement(entitlementScopeCustomer, customerID))
+		authCodes, err := s.entitlementRepo.GetCustomerAuthorizationCodes(ctx, customerID)
+		if err != nil {
+			s.logger.WithError(err).WithField("customer_id", customerID).Warn("查询客户授权码失败，仅清除客户授权检查缓存")
+			continue
+		}
+		for _, authCode := range authCodes {
+			keys = append(keys, s.keys.Entitlement(entitlementScopeCode, authCode.Code))
+		}
+	}
+	if err := s.cache.Del(ctx, keys...); err != nil {
+		s.logger.WithError(err).WithField("customer_ids", customerIDs).Warn("清除授权检查缓存失败")
+	}
+}
+
+func (s *entitlementService) resolveSnapshot(ctx context.Context, code, customerID string) (*models.EntitlementSnapshot, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	var authCodes []*models.AuthorizationCode
+	if code != "" {
+		authCode, err := s.entitlementRepo.GetAuthorizationCodeByCode(ctx, code)
+		if err != nil {
+			if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+				return nil, i18n.NewI18nError("300001", lang) // 授权码不存在
+			}
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		authCodes = append(authCodes, authCode)
+		customerID = authCode.CustomerID
+	} else {
+		var err error
+		authCodes, err = s.entitlementRepo.GetCustomerAuthorizationCodes(ctx, customerID)
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+	}
+
+	ids := make([]string, 0, len(authCodes))
+	for _, authCode := range authCodes {
+		ids = append(ids, authCode.ID)
+	}
+	licenses, err := s.entitlementRepo.GetActiveLicenses(ctx, ids)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	snapshot := resolveEntitlements(authCodes, licenses, time.Now())
+	snapshot.CustomerID = customerID
+	return snapshot, nil
+}
+
+// getCachedSnapshot 读取缓存，未命中或缓存异常时返回 nil
+func (s *entitlementService) getCachedSnapshot(ctx context.Context, key string) (*models.EntitlementSnapshot, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	value, err := s.cache.Get(ctx, key)
+	if err != nil {
+		if err != cache.ErrCacheMiss {
+			s.logger.WithError(err).Warn("读取授权检查缓存失败")
+		}
+		return nil, false
+	}
+	var snapshot models.EntitlementSnapshot
+	if err := json.Unmarshal([]byte(value), &snapshot); err != nil {
+		return nil, false
+	}
+	return &snapshot, true
+}
+
+func (s *entitlementService) setCachedSnapshot(ctx context.Context, key string, snapshot *models.EntitlementSnapshot) {
+	if s.cache == nil {
+		return
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return
+	}
+	if err := s.cache.Set(ctx, key, string(data), s.ttl); err != nil {
+		s.logger.WithError(err).Warn("写入授权检查缓存失败")
+	}
+}
+
+// entitlementCodeStatus 授权码在 now 时刻的授权状态
+func entitlementCodeStatus(authCode *models.AuthorizationCode, now time.Time) string {
+	switch {
+	case authCode.IsLocked:
+		return models.EntitlementReasonLocked
+	case now.Before(authCode.StartDate):
+		return models.EntitlementReasonNotStarted
+	case now.After(authCode.EndDate):
+		return models.EntitlementReasonExpired
+	}
+	return models.EntitlementReasonActive
+}
+
+// resolveEntitlements 汇总有效授权码的功能、限制和配额
+// 多个授权码时：功能取任一授权码启用的值（数值取最大），数值限制累加（与席位一致），
+// 已用量按激活设备心跳上报的 usage_data 中同名数值汇总
+func resolveEntitlements(authCodes []*models.AuthorizationCode, licenses []*models.License, now time.Time) *models.EntitlementSnapshot {
+	snapshot := &models.EntitlementSnapshot{
+		Features:           map[string]interface{}{},
+		Limits:             map[string]interface{}{},
+		Quotas:             []models.EntitlementQuota{},
+		AuthorizationCodes: []models.EntitlementCode{},
+		ResolvedAt:         now,
+	}
+
+	activeLicenses := make(map[string]int64)
+	usage := make(map[string]map[string]float64)
+	for _, license := range licenses {
+		activeLicenses[license.AuthorizationCodeID]++
+		for key, value := range parseJSONField(license.UsageData) {
+			number, ok := value.(float64)
+			if !ok {
+				continue
+			}
+			if usage[license.AuthorizationCodeID] == nil {
+				usage[license.AuthorizationCodeID] = make(map[string]float64)
+			}
+			usage[license.AuthorizationCodeID][key] += number
+		}
+	}
+
+	var seatLimit, seatUsed float64
+	used := make(map[string]float64)
+	activeCodes := 0
+	for _, authCode := range authCodes {
+		status := entitlementCodeStatus(authCode, now)
+		snapshot.AuthorizationCodes = append(snapshot.AuthorizationCodes, models.EntitlementCode{
+			ID:             authCode.ID,
+			Code:           authCode.Code,
+			Status:         status,
+			DeploymentType: authCode.DeploymentType,
+			StartDate:      authCode.StartDate,
+			EndDate:        authCode.EndDate,
+			MaxActivations: authCode.MaxActivations,
+			ActiveLicenses: activeLicenses[authCode.ID],
+		})
+		if status != models.EntitlementReasonActive {
+			continue
+		}
+		activeCodes++
+
+		for key, value := range parseJSONField(authCode.FeatureConfig) {
+			existing, ok := snapshot.Features[key]
+			if !ok || mergedFeatureValueWins(existing, value) {
+				snapshot.Features[key] = value
+			}
+		}
+		for key, value := range parseJSONField(authCode.UsageLimits) {
+			existing, ok := snapshot.Limits[key].(float64)
+			number, isNumber := value.(float64)
+			switch {
+			case !ok && snapshot.Limits[key] == nil:
+				snapshot.Limits[key] = value
+			case ok && isNumber:
+				snapshot.Limits[key] = existing + number
+			}
+		}
+
+		seatLimit += float64(authCode.MaxActivations)
+		seatUsed += float64(activeLicenses[authCode.ID])
+		for key, value := range usage[authCode.ID] {
+			used[key] += value
+		}
+		if snapshot.ExpiresAt == nil || authCode.EndDate.After(*snapshot.ExpiresAt) {
+			endDate := authCode.EndDate
+			snapshot.ExpiresAt = &endDate
+		}
+	}
+
+	switch {
+	case activeCodes > 0:
+		snapshot.Reason = models.EntitlementReasonActive
+	case len(snapshot.AuthorizationCodes) == 1:
+		snapshot.Reason = snapshot.AuthorizationCodes[0].Status
+	default:
+		snapshot.Reason = models.EntitlementReasonNoEntitlement
+	}
+	if activeCodes == 0 {
+		return snapshot
+	}
+
+	snapshot.Quotas = append(snapshot.Quotas, newEntitlementQuota(models.EntitlementQuotaSeats, seatLimit, seatUsed))
+	keys := make([]string, 0, len(snapshot.Limits))
+	for key := range snapshot.Limits {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if limit, ok := snapshot.Limits[key].(float64); ok {
+			snapshot.Quotas = append(snapshot.Quotas, newEntitlementQuota(key, limit, used[key]))
+		}
+	}
+	return snapshot
+}
+
+func newEntitlementQuota(name string, limit, used float64) models.EntitlementQuota {
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return models.EntitlementQuota{Name: name, Limit: limit, Used: used, Remaining: remaining}
+}
+
+// mergedFeatureValueWins 合并多个授权码的同名功能时新值是否覆盖旧值：启用优先，数值取最大
+func mergedFeatureValueWins(existing, value interface{}) bool {
+	existingNumber, existingIsNumber := existing.(float64)
+	number, isNumber := value.(float64)
+	if existingIsNumber && isNumber {
+		return number > existingNumber
+	}
+	return !entitlementValueEnabled(existing) && entitlementValueEnabled(value)
+}
+
+// entitlementFeatureEnabled 功能是否启用：feature_config 中存在且值为真
+func entitlementFeatureEnabled(features map[string]interface{}, feature string) bool {
+	value, ok := features[feature]
+	return ok && entitlementValueEnabled(value)
+}
+
+// entitlementValueEnabled 功能配置值的真值判断：false、0、空字符串、"false" 和 null 视为未启用
+func entitlementValueEnabled(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != "" && v != "0" && !strings.EqualFold(v, "false")
+	}
+	return true
+}
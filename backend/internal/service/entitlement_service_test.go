@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+)
+
+func TestResolveEntitlements(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	codes := []*models.AuthorizationCode{
+		{
+			ID:             "a",
+			StartDate:      now.AddDate(0, -1, 0),
+			EndDate:        now.AddDate(0, 1, 0),
+			MaxActivations: 3,
+			FeatureConfig:  models.JSON(`{"reports":false,"max_projects":5}`),
+			UsageLimits:    models.JSON(`{"api_calls":1000}`),
+		},
+		{
+			ID:             "b",
+			StartDate:      now.AddDate(0, -1, 0),
+			EndDate:        now.AddDate(0, 6, 0),
+			MaxActivations: 2,
+			FeatureConfig:  models.JSON(`{"reports":true,"max_projects":3}`),
+			UsageLimits:    models.JSON(`{"api_calls":500}`),
+		},
+		{
+			ID:             "c",
+			IsLocked:       true,
+			StartDate:      now.AddDate(0, -1, 0),
+			EndDate:        now.AddDate(1, 0, 0),
+			MaxActivations: 10,
+			FeatureConfig:  models.JSON(`{"sso":true}`),
+		},
+	}
+	licenses := []*models.License{
+		{AuthorizationCodeID: "a", UsageData: models.JSON(`{"api_calls":900}`)},
+		{AuthorizationCodeID: "b", UsageData: models.JSON(`{"api_calls":700}`)},
+		{AuthorizationCodeID: "c", UsageData: models.JSON(`{"api_calls":50}`)},
+	}
+
+	snapshot := resolveEntitlements(codes, licenses, now)
+	if snapshot.Reason != models.EntitlementReasonActive {
+		t.Fatalf("expected active, got %s", snapshot.Reason)
+	}
+	if snapshot.Features["reports"] != true || snapshot.Features["max_projects"] != float64(5) {
+		t.Fatalf("unexpected features: %v", snapshot.Features)
+	}
+	if _, ok := snapshot.Features["sso"]; ok {
+		t.Fatal("locked code must not contribute features")
+	}
+	if len(snapshot.Quotas) != 2 {
+		t.Fatalf("unexpected quotas: %+v", snapshot.Quotas)
+	}
+	seats, apiCalls := snapshot.Quotas[0], snapshot.Quotas[1]
+	if seats.Name != models.EntitlementQuotaSeats || seats.Limit != 5 || seats.Used != 2 || seats.Remaining != 3 {
+		t.Fatalf("unexpected seats quota: %+v", seats)
+	}
+	if apiCalls.Name != "api_calls" || apiCalls.Limit != 1500 || apiCalls.Used != 1600 || apiCalls.Remaining != 0 {
+		t.Fatalf("unexpected api_calls quota: %+v", apiCalls)
+	}
+	if snapshot.ExpiresAt == nil || !snapshot.ExpiresAt.Equal(codes[1].EndDate) {
+		t.Fatalf("unexpected expires_at: %v", snapshot.ExpiresAt)
+	}
+
+	single := resolveEntitlements(codes[2:], nil, now)
+	if single.Reason != models.EntitlementReasonLocked || len(single.Quotas) != 0 {
+		t.Fatalf("unexpected single code snapshot: %+v", single)
+	}
+	if empty := resolveEntitlements(nil, nil, now); empty.Reason != models.EntitlementReasonNoEntitlement {
+		t.Fatalf("expected no_entitlement, got %s", empty.Reason)
+	}
+}
+
+func TestEntitlementValueEnabled(t *testing.T) {
+	cases := []struct {
+		value interface{}
+		want  bool
+	}{
+		{nil, false},
+		{false, false},
+		{true, true},
+		{float64(0), false},
+		{float64(3), true},
+		{"", false},
+		{"false", false},
+		{"0", false},
+		{"pro", true},
+		{map[string]interface{}{}, true},
+	}
+	for _, c := range cases {
+		if got := entitlementValueEnabled(c.value); got != c.want {
+			t.Errorf("entitlementValueEnabled(%v) = %v, want %v", c.value, got, c.want)
+		}
+	}
+}
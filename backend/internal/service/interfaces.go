@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"io"
 	"license-manager/internal/models"
 )
 
@@ -26,6 +27,22 @@ type CustomerService interface {
 	UpdateCustomer(ctx context.Context, id string, req *models.CustomerUpdateRequest) (*models.Customer, error)
 	DeleteCustomer(ctx context.Context, id string) error
 	UpdateCustomerStatus(ctx context.Context, id string, req *models.CustomerStatusUpdateRequest) (*models.Customer, error)
+	UpdateCustomerCustomFields(ctx context.Context, id string, req *models.EntityCustomFieldsUpdateRequest) (*models.EntityCustomFields, error)
+	GetCustomerHierarchy(ctx context.Context, id string) (*models.CustomerHierarchyResponse, error)
+	MergeCustomers(ctx context.Context, operatorID string, req *models.CustomerMergeRequest) (*models.CustomerMergeResponse, error)
+	GetCustomerMerges(ctx context.Context, req *models.CustomerMergeListRequest) (*models.CustomerMergeListResponse, error)
+	GetCustomerTimeline(ctx context.Context, id string, req *models.CustomerTimelineRequest) (*models.CustomerTimelineResponse, error)
+	GetCustomerSeatPool(ctx context.Context, id string) (*models.CustomerSeatPool, error)
+	SaveCustomerSeatPool(ctx context.Context, operatorID, id string, req *models.CustomerSeatPoolSaveRequest) (*models.CustomerSeatPool, error)
+	DeleteCustomerSeatPool(ctx context.Context, id string) error
+	ImportCustomers(ctx context.Context, operatorID, fileName string, data []byte, req *models.CustomerImportRequest) (*models.CustomerImportJob, error)
+	GetImportJob(ctx context.Context, id string) (*models.CustomerImportJob, error)
+	ExportImportErrors(ctx context.Context, id, format string) ([]byte, string, string, error)
+	ExportCustomers(ctx context.Context, req *models.CustomerExportRequest) (string, string, func(w io.Writer) error, error)
+	// ResumeUnfinishedImports 续跑服务重启前未完成的导入任务，其他实例执行中的任务等待其租约过期后接管
+	ResumeUnfinishedImports()
+	// Stop 停止后台导入并等待退出，未完成的任务由续跑继续
+	Stop()
 }
 
 // EnumService 枚举服务接口
@@ -42,11 +59,21 @@ type AuthorizationCodeService interface {
 	UpdateAuthorizationCode(ctx context.Context, id string, req *models.AuthorizationCodeUpdateRequest) (*models.AuthorizationCode, error)
 	LockUnlockAuthorizationCode(ctx context.Context, id string, req *models.AuthorizationCodeLockRequest) (*models.AuthorizationCode, error)
 	DeleteAuthorizationCode(ctx context.Context, id string) error
+	UpdateAuthorizationCodeCustomFields(ctx context.Context, id string, req *models.EntityCustomFieldsUpdateRequest) (*models.EntityCustomFields, error)
 	GetAuthorizationChangeList(ctx context.Context, authCodeID string, req *models.AuthorizationChangeListRequest) (*models.AuthorizationChangeListResponse, error)
+	GetAuthorizationChangeDiff(ctx context.Context, authCodeID string, req *models.AuthorizationChangeDiffRequest) (*models.AuthorizationChangeDiffResponse, error)
+	RevertAuthorizationCode(ctx context.Context, id string, req *models.AuthorizationCodeRevertRequest) (*models.AuthorizationCode, error)
 	GenerateAuthorizationFile(ctx context.Context, id string) ([]byte, string, string, error)
+	SplitAuthorizationCode(ctx context.Context, id string, req *models.AuthorizationCodeSplitRequest) (*models.AuthorizationCodeSplitResponse, error)
+	MergeAuthorizationCodes(ctx context.Context, req *models.AuthorizationCodeMergeRequest) (*models.AuthorizationCodeMergeResponse, error)
 
 	// 用户端分享功能
 	ShareAuthorizationCode(ctx context.Context, authCodeID, userID string, req *models.AuthorizationCodeShareRequest) (*models.AuthorizationCodeShareResponse, error)
+	GetShareList(ctx context.Context, userID string, req *models.AuthorizationCodeShareListRequest) (*models.AuthorizationCodeShareListResponse, error)
+	AcceptShare(ctx context.Context, shareID, userID string) (*models.AuthorizationCodeShareResponse, error)
+	DeclineShare(ctx context.Context, shareID, userID string) (*models.AuthorizationCodeShare, error)
+	CancelShare(ctx context.Context, shareID, userID string) (*models.AuthorizationCodeShare, error)
+	ReclaimShare(ctx context.Context, shareID, userID string, req *models.AuthorizationCodeShareReclaimRequest) (*models.AuthorizationCodeShare, error)
 
 	// 用户端获取产品激活码
 	GetProductActivationCode(ctx context.Context, customerID string, req *models.ProductActivationCodeRequest) (*models.ProductActivationCodeResponse, error)
@@ -63,11 +90,19 @@ type LicenseService interface {
 	CreateLicense(ctx context.Context, req *models.LicenseCreateRequest) (*models.License, error)
 	RevokeLicense(ctx context.Context, id string, req *models.LicenseRevokeRequest) (*models.License, error)
 	GenerateLicenseFile(ctx context.Context, id string) ([]byte, string, string, error)
+	UpdateLicenseCustomFields(ctx context.Context, id string, req *models.EntityCustomFieldsUpdateRequest) (*models.EntityCustomFields, error)
+
+	// 设备转移接口（customerID 不为空时校验归属）
+	TransferLicense(ctx context.Context, id, customerID string, operatorType models.LicenseTransferOperatorType, operatorID string, req *models.LicenseTransferRequest) (*models.LicenseTransferResponse, error)
+	GetLicenseTransferList(ctx context.Context, req *models.LicenseTransferListRequest) (*models.LicenseTransferListResponse, error)
 
 	// 客户端激活和心跳接口
 	ActivateLicense(ctx context.Context, req *models.ActivateRequest, clientIP string) (*models.ActivateResponse, error)
 	Heartbeat(ctx context.Context, req *models.HeartbeatRequest, clientIP string) (*models.HeartbeatResponse, error)
 
+	// 使用限制违规事件
+	GetActivationViolationList(ctx context.Context, req *models.ActivationViolationListRequest) (*models.ActivationViolationListResponse, error)
+
 	// 统计接口
 	GetStatsOverview(ctx context.Context) (*models.StatsOverviewResponse, error)
 }
@@ -79,6 +114,9 @@ type DashboardService interface {
 
 	// 获取最近授权列表
 	GetRecentAuthorizations(ctx context.Context, req *models.DashboardRecentAuthorizationsRequest) (*models.DashboardRecentAuthorizationsResponse, error)
+
+	// 获取线索转化客户统计
+	GetLeadConversion(ctx context.Context, req *models.DashboardLeadConversionRequest) (*models.DashboardLeadConversionResponse, error)
 }
 
 // CuDeviceService 客户设备服务接口
@@ -2,35 +2,85 @@ package service
 
 import (
 	"context"
+	"errors"
 	"license-manager/internal/models"
 	"license-manager/internal/repository"
 	pkgcontext "license-manager/pkg/context"
 	"license-manager/pkg/i18n"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
+// 线索相关错误码
+const (
+	errCodeLeadPhoneRegistered = "200016" // 联系人手机号已注册客户端用户
+	errCodeLeadAssigneeInvalid = "650001" // 负责人不存在或不是有效的后台账号
+	errCodeLeadMergeSelf       = "650002" // 不能将线索合并到自身
+	errCodeLeadMerged          = "650003" // 线索已被合并
+	errCodeLeadMergeConverted  = "650004" // 已转化的线索不能被合并
+)
+
+// 疑似重复线索最多返回的数量
+const leadDuplicateLimit = 20
+
+// 每轮提醒任务最多处理的线索数量
+const leadReminderBatchSize = 200
+
+// 线索转化试用授权码默认值
+const (
+	defaultLeadTrialValidityDays   = 30
+	defaultLeadTrialMaxActivations = 1
+)
+
 // LeadService 线索服务接口
 type LeadService interface {
 	CreateLead(ctx context.Context, req *models.LeadCreateRequest) (*models.Lead, error)
-	UpdateLead(ctx context.Context, id string, req *models.LeadUpdateRequest) (*models.Lead, error)
+	UpdateLead(ctx context.Context, id, operatorID string, req *models.LeadUpdateRequest) (*models.Lead, error)
 	DeleteLead(ctx context.Context, id string) error
 	GetLead(ctx context.Context, id string) (*models.Lead, error)
 	GetLeadList(ctx context.Context, req *models.LeadListRequest) (*models.LeadListResponse, error)
 	GetLeadSummary(ctx context.Context) (*models.LeadSummaryResponse, error)
+	// ConvertLead 将线索转化为客户，重复调用返回首次转化的结果
+	ConvertLead(ctx context.Context, id, operatorID string, req *models.LeadConvertRequest) (*models.LeadConvertResponse, error)
+
+	AssignLead(ctx context.Context, id, operatorID string, req *models.LeadAssignRequest) (*models.Lead, error)
+	// GetLeadDuplicates 获取疑似重复线索作为合并建议
+	GetLeadDuplicates(ctx context.Context, id string) (*models.LeadDuplicatesResponse, error)
+	// MergeLead 将线索合并到目标线索，来源线索标记为已失效
+	MergeLead(ctx context.Context, id, operatorID string, req *models.LeadMergeRequest) (*models.Lead, error)
+	AddLeadActivity(ctx context.Context, id, operatorID string, req *models.LeadActivityCreateRequest) (*models.LeadActivity, error)
+	GetLeadActivities(ctx context.Context, id string, req *models.LeadActivityListRequest) (*models.LeadActivityListResponse, error)
+
+	// SendLeadReminders 发送跟进日期到期及长时间待联系的线索提醒，供定时任务调用，返回发送提醒的线索数量
+	SendLeadReminders(ctx context.Context, pendingTimeout time.Duration) (int, error)
 }
 
 type leadService struct {
-	repo repository.LeadRepository
-	db   *gorm.DB
+	repo                repository.LeadRepository
+	customerRepo        repository.CustomerRepository
+	cuUserRepo          repository.CuUserRepository
+	authCodeRepo        repository.AuthorizationCodeRepository
+	partnerRepo         repository.PartnerRepository
+	notificationService NotificationService
+	db                  *gorm.DB
+	logger              *logrus.Logger
 }
 
 // NewLeadService 创建线索服务
-func NewLeadService(repo repository.LeadRepository, db *gorm.DB) LeadService {
+func NewLeadService(repo repository.LeadRepository, customerRepo repository.CustomerRepository, cuUserRepo repository.CuUserRepository, authCodeRepo repository.AuthorizationCodeRepository, partnerRepo repository.PartnerRepository, notificationService NotificationService, db *gorm.DB, logger *logrus.Logger) LeadService {
 	return &leadService{
-		repo: repo,
-		db:   db,
+		repo:                repo,
+		customerRepo:        customerRepo,
+		cuUserRepo:          cuUserRepo,
+		authCodeRepo:        authCodeRepo,
+		partnerRepo:         partnerRepo,
+		notificationService: notificationService,
+		db:                  db,
+		logger:              logger,
 	}
 }
 
@@ -53,6 +103,22 @@ func (s *leadService) CreateLead(ctx context.Context, req *models.LeadCreateRequ
 		Status:       string(models.LeadStatusPending),
 	}
 
+	// 重复提交不拒绝，标记疑似重复的最早线索，供销售合并
+	lead.NormalizedPhone = models.NormalizeLeadPhone(lead.ContactPhone)
+	lead.NormalizedEmail = models.NormalizeLeadEmail(lead.ContactEmail)
+	lead.NormalizedCompany = models.NormalizeLeadCompany(lead.CompanyName)
+	duplicates, err := s.repo.FindDuplicates(ctx, lead, 1)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if len(duplicates) > 0 {
+		original := duplicates[0]
+		if original.DuplicateOfID != nil {
+			original.ID = *original.DuplicateOfID
+		}
+		lead.DuplicateOfID = &original.ID
+	}
+
 	if err := s.repo.Create(lead); err != nil {
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
@@ -60,13 +126,18 @@ func (s *leadService) CreateLead(ctx context.Context, req *models.LeadCreateRequ
 	return lead, nil
 }
 
-func (s *leadService) UpdateLead(ctx context.Context, id string, req *models.LeadUpdateRequest) (*models.Lead, error) {
+func (s *leadService) UpdateLead(ctx context.Context, id, operatorID string, req *models.LeadUpdateRequest) (*models.Lead, error) {
 	lang := pkgcontext.GetLanguageFromContext(ctx)
 
 	lead, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, i18n.NewI18nError("900002", lang)
 	}
+	if lead.MergedIntoID != nil {
+		return nil, i18n.NewI18nError(errCodeLeadMerged, lang)
+	}
+
+	var activities []*models.LeadActivity
 
 	// 更新字段
 	if req.CompanyName != "" {
@@ -87,11 +158,18 @@ func (s *leadService) UpdateLead(ctx context.Context, id string, req *models.Lea
 	if req.ExtraInfo != "" {
 		lead.ExtraInfo = req.ExtraInfo
 	}
-	if req.Status != "" {
+	if req.Status != "" && req.Status != lead.Status {
+		activities = append(activities, models.NewLeadActivity(lead.ID, models.LeadActivityTypeStatusChange, lead.Status+" -> "+req.Status, operatorID))
 		lead.Status = req.Status
 	}
+	if !sameFollowUpDate(lead.FollowUpDate, req.FollowUpDate) {
+		// 跟进日期变更后重新提醒
+		lead.FollowUpRemindedAt = nil
+	}
 	lead.FollowUpDate = req.FollowUpDate
-	if req.FollowUpRecord != "" {
+	// 跟进记录只追加：与最近一次记录不同时记为新的跟进动态
+	if req.FollowUpRecord != "" && req.FollowUpRecord != lead.FollowUpRecord {
+		activities = append(activities, models.NewLeadActivity(lead.ID, models.LeadActivityTypeFollowUp, req.FollowUpRecord, operatorID))
 		lead.FollowUpRecord = req.FollowUpRecord
 	}
 	if req.InternalNote != "" {
@@ -99,7 +177,7 @@ func (s *leadService) UpdateLead(ctx context.Context, id string, req *models.Lea
 	}
 	lead.UpdatedAt = time.Now()
 
-	if err := s.repo.Update(lead); err != nil {
+	if err := s.repo.SaveWithActivities(ctx, lead, activities...); err != nil {
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
@@ -175,3 +253,523 @@ func (s *leadService) GetLeadSummary(ctx context.Context) (*models.LeadSummaryRe
 
 	return summary, nil
 }
+
+func (s *leadService) ConvertLead(ctx context.Context, id, operatorID string, req *models.LeadConvertRequest) (*models.LeadConvertResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	lead, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, i18n.NewI18nError("900002", lang)
+	}
+	// 已转化的线索直接返回原转化结果，保证幂等
+	if lead.CustomerID != nil {
+		return s.buildConvertResponse(ctx, lead, true)
+	}
+	if lead.MergedIntoID != nil {
+		return nil, i18n.NewI18nError(errCodeLeadMerged, lang)
+	}
+
+	if operatorID == "" {
+		return nil, i18n.NewI18nError("100004", lang) // 缺少认证信息
+	}
+
+	if req.PartnerID != nil && *req.PartnerID != "" {
+		if _, err := s.partnerRepo.GetPartnerByID(ctx, *req.PartnerID); err != nil {
+			if errors.Is(err, repository.ErrPartnerNotFound) {
+				return nil, i18n.NewI18nError(errCodePartnerNotFound, lang)
+			}
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+	}
+
+	customer := newLeadCustomer(lead, req, operatorID)
+
+	var cuUser *models.CuUser
+	if req.CreateCuUser {
+		phone := strings.TrimSpace(lead.ContactPhone)
+		exists, err := s.cuUserRepo.CheckPhoneExists(phone, "+86", "")
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		if exists {
+			return nil, i18n.NewI18nError(errCodeLeadPhoneRegistered, lang)
+		}
+		cuUser = newLeadCuUser(lead, phone)
+	}
+
+	var authCode *models.AuthorizationCode
+	if req.TrialAuthorization != nil {
+		authReq := newLeadTrialAuthorizationRequest(customer.ID, lead.LeadNo, req.TrialAuthorization)
+		startDate, endDate := authorizationCodeValidity(time.Now(), authReq.ValidityDays)
+		codeFormat, codeParams := authorizationCodeGenerationParams(authReq, endDate)
+		code, err := generateAuthorizationCodeWithFormat(codeFormat, codeParams)
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		authCode, err = newAuthorizationCodeEntity(authReq, code, codeFormat, operatorID, startDate, endDate)
+		if err != nil {
+			return nil, i18n.NewI18nError("300010", lang) // 配置参数错误
+		}
+	}
+
+	converted, err := s.repo.ConvertLead(ctx, id, customer, cuUser, authCode)
+	if err != nil {
+		// 并发请求已先完成转化，返回已有结果
+		if errors.Is(err, repository.ErrLeadAlreadyConverted) {
+			return s.buildConvertResponse(ctx, converted, true)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, i18n.NewI18nError("900002", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	resp := &models.LeadConvertResponse{
+		Lead:                     converted.ToResponse(),
+		CustomerID:               customer.ID,
+		CustomerCode:             customer.CustomerCode,
+		CustomerName:             customer.CustomerName,
+		CuUserID:                 converted.CuUserID,
+		TrialAuthorizationCodeID: converted.TrialAuthorizationCodeID,
+	}
+	if authCode != nil {
+		resp.TrialAuthorizationCode = &authCode.Code
+	}
+	return resp, nil
+}
+
+// buildConvertResponse 根据线索记录的关联信息组装已有的转化结果
+func (s *leadService) buildConvertResponse(ctx context.Context, lead *models.Lead, alreadyConverted bool) (*models.LeadConvertResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	resp := &models.LeadConvertResponse{
+		Lead:                     lead.ToResponse(),
+		CustomerID:               *lead.CustomerID,
+		CuUserID:                 lead.CuUserID,
+		TrialAuthorizationCodeID: lead.TrialAuthorizationCodeID,
+		AlreadyConverted:         alreadyConverted,
+	}
+
+	customer, err := s.customerRepo.GetCustomerByID(ctx, *lead.CustomerID)
+	if err != nil && !repository.IsCustomerNotFound(err) {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if customer != nil {
+		resp.CustomerCode = customer.CustomerCode
+		resp.CustomerName = customer.CustomerName
+	}
+
+	if lead.TrialAuthorizationCodeID != nil {
+		authCode, err := s.authCodeRepo.GetAuthorizationCodeByID(ctx, *lead.TrialAuthorizationCodeID)
+		if err != nil && !errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		if authCode != nil {
+			resp.TrialAuthorizationCode = &authCode.Code
+		}
+	}
+	return resp, nil
+}
+
+// newLeadCustomer 根据线索信息构建客户，客户ID预先生成以便试用授权码引用
+func newLeadCustomer(lead *models.Lead, req *models.LeadConvertRequest, operatorID string) *models.Customer {
+	customerType := req.CustomerType
+	if customerType == "" {
+		customerType = "enterprise"
+	}
+	customerLevel := req.CustomerLevel
+	if customerLevel == "" {
+		customerLevel = "basic"
+	}
+
+	customer := &models.Customer{
+		ID:            uuid.New().String(),
+		CustomerName:  lead.CompanyName,
+		CustomerType:  customerType,
+		ContactPerson: lead.ContactName,
+		CompanySize:   req.CompanySize,
+		CustomerLevel: customerLevel,
+		Status:        "active",
+		CreatedBy:     operatorID,
+	}
+	if req.PartnerID != nil && *req.PartnerID != "" {
+		customer.PartnerID = req.PartnerID
+	}
+	if phone := strings.TrimSpace(lead.ContactPhone); phone != "" {
+		customer.Phone = &phone
+	}
+	if email := strings.TrimSpace(lead.ContactEmail); email != "" {
+		customer.Email = &email
+	}
+	// 创建线索时未填写需求会记为 "Empty"，不作为客户描述
+	if lead.Requirement != "" && lead.Requirement != "Empty" {
+		description := lead.Requirement
+		customer.Description = &description
+	}
+	return customer
+}
+
+// newLeadCuUser 为线索联系人创建客户端用户，未设置密码，需通过短信验证码登录
+func newLeadCuUser(lead *models.Lead, phone string) *models.CuUser {
+	cuUser := &models.CuUser{
+		Phone:            phone,
+		PhoneCountryCode: "+86",
+		UserRole:         "admin", // 联系人作为客户的管理员
+		Status:           "active",
+		PhoneVerified:    false,
+		EmailVerified:    false,
+		Language:         "zh-CN",
+		Timezone:         "Asia/Shanghai",
+	}
+	if name := strings.TrimSpace(lead.ContactName); name != "" {
+		cuUser.RealName = &name
+	}
+	if email := strings.TrimSpace(lead.ContactEmail); email != "" {
+		cuUser.Email = &email
+	}
+	return cuUser
+}
+
+// newLeadTrialAuthorizationRequest 将试用授权配置转换为授权码创建请求并补齐默认值
+func newLeadTrialAuthorizationRequest(customerID, leadNo string, trial *models.LeadTrialAuthorization) *models.AuthorizationCodeCreateRequest {
+	req := &models.AuthorizationCodeCreateRequest{
+		CustomerID:     customerID,
+		SoftwareID:     trial.SoftwareID,
+		ValidityDays:   trial.ValidityDays,
+		DeploymentType: trial.DeploymentType,
+		MaxActivations: trial.MaxActivations,
+		FeatureConfig:  trial.FeatureConfig,
+	}
+	if req.ValidityDays <= 0 {
+		req.ValidityDays = defaultLeadTrialValidityDays
+	}
+	if req.MaxActivations <= 0 {
+		req.MaxActivations = defaultLeadTrialMaxActivations
+	}
+	if req.DeploymentType == "" {
+		req.DeploymentType = "standalone"
+	}
+	description := "线索转化试用授权（" + leadNo + "）"
+	req.Description = &description
+	return req
+}
+
+func (s *leadService) AssignLead(ctx context.Context, id, operatorID string, req *models.LeadAssignRequest) (*models.Lead, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	lead, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, i18n.NewI18nError("900002", lang)
+	}
+	if lead.MergedIntoID != nil {
+		return nil, i18n.NewI18nError(errCodeLeadMerged, lang)
+	}
+
+	var assigneeID string
+	if req.AssigneeID != nil {
+		assigneeID = strings.TrimSpace(*req.AssigneeID)
+	}
+	if assigneeID != "" {
+		isStaff, err := s.repo.IsStaffUser(ctx, assigneeID)
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		if !isStaff {
+			return nil, i18n.NewI18nError(errCodeLeadAssigneeInvalid, lang)
+		}
+	}
+
+	var previous string
+	if lead.AssigneeID != nil {
+		previous = *lead.AssigneeID
+	}
+	if previous == assigneeID {
+		return lead, nil
+	}
+
+	if assigneeID == "" {
+		lead.AssigneeID = nil
+	} else {
+		lead.AssigneeID = &assigneeID
+	}
+	lead.UpdatedAt = time.Now()
+
+	activity := models.NewLeadActivity(lead.ID, models.LeadActivityTypeAssignment, previous+" -> "+assigneeID, operatorID)
+	if err := s.repo.SaveWithActivities(ctx, lead, activity); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	// 通知新负责人，自己分配给自己时不通知
+	if assigneeID != "" && assigneeID != operatorID {
+		s.notifyLead(ctx, assigneeID, models.NotificationTypeLeadAssigned, lead)
+	}
+
+	return lead, nil
+}
+
+func (s *leadService) GetLeadDuplicates(ctx context.Context, id string) (*models.LeadDuplicatesResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	lead, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, i18n.NewI18nError("900002", lang)
+	}
+
+	duplicates, err := s.repo.FindDuplicates(ctx, lead, leadDuplicateLimit)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	list := make([]*models.LeadDuplicateCandidate, 0, len(duplicates))
+	for _, duplicate := range duplicates {
+		list = append(list, &models.LeadDuplicateCandidate{
+			Lead:          duplicate.ToResponse(),
+			MatchedFields: leadMatchedFields(lead, duplicate),
+		})
+	}
+	return &models.LeadDuplicatesResponse{List: list}, nil
+}
+
+func (s *leadService) MergeLead(ctx context.Context, id, operatorID string, req *models.LeadMergeRequest) (*models.Lead, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if id == req.TargetID {
+		return nil, i18n.NewI18nError(errCodeLeadMergeSelf, lang)
+	}
+
+	source, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, i18n.NewI18nError("900002", lang)
+	}
+	target, err := s.repo.GetByID(req.TargetID)
+	if err != nil {
+		return nil, i18n.NewI18nError("900002", lang)
+	}
+	if source.MergedIntoID != nil || target.MergedIntoID != nil {
+		return nil, i18n.NewI18nError(errCodeLeadMerged, lang)
+	}
+	// 已转化的线索关联了客户，只能作为合并目标保留
+	if source.CustomerID != nil {
+		return nil, i18n.NewI18nError(errCodeLeadMergeConverted, lang)
+	}
+
+	mergeLeadInto(source, target)
+
+	now := time.Now()
+	source.MergedIntoID = &target.ID
+	source.DuplicateOfID = nil
+	source.Status = string(models.LeadStatusInvalid)
+	source.UpdatedAt = now
+	target.UpdatedAt = now
+
+	activities := []*models.LeadActivity{
+		models.NewLeadActivity(source.ID, models.LeadActivityTypeMerge, "-> "+target.LeadNo, operatorID),
+		models.NewLeadActivity(target.ID, models.LeadActivityTypeMerge, "<- "+source.LeadNo, operatorID),
+	}
+	if err := s.repo.MergeLead(ctx, source, target, activities...); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	return target, nil
+}
+
+func (s *leadService) AddLeadActivity(ctx context.Context, id, operatorID string, req *models.LeadActivityCreateRequest) (*models.LeadActivity, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	lead, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, i18n.NewI18nError("900002", lang)
+	}
+	if lead.MergedIntoID != nil {
+		return nil, i18n.NewI18nError(errCodeLeadMerged, lang)
+	}
+
+	activityType := req.Type
+	if activityType == "" {
+		activityType = models.LeadActivityTypeFollowUp
+	}
+	activity := models.NewLeadActivity(lead.ID, activityType, req.Content, operatorID)
+
+	// 跟进记录同步为线索的最近一次跟进记录
+	if activityType == models.LeadActivityTypeFollowUp {
+		lead.FollowUpRecord = req.Content
+		lead.UpdatedAt = time.Now()
+		err = s.repo.SaveWithActivities(ctx, lead, activity)
+	} else {
+		err = s.repo.CreateActivity(ctx, activity)
+	}
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	activity.TypeDisplay = i18n.GetEnumMessage("lead_activity_type", activity.Type, lang)
+	return activity, nil
+}
+
+func (s *leadService) GetLeadActivities(ctx context.Context, id string, req *models.LeadActivityListRequest) (*models.LeadActivityListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, i18n.NewI18nError("900002", lang)
+	}
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	activities, total, err := s.repo.GetActivities(ctx, id, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	for _, activity := range activities {
+		activity.TypeDisplay = i18n.GetEnumMessage("lead_activity_type", activity.Type, lang)
+	}
+
+	return &models.LeadActivityListResponse{
+		List:     activities,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+func (s *leadService) SendLeadReminders(ctx context.Context, pendingTimeout time.Duration) (int, error) {
+	now := time.Now()
+	var adminIDs []string
+	adminsLoaded := false
+	recipients := func(lead *models.Lead) ([]string, error) {
+		if lead.AssigneeID != nil {
+			return []string{*lead.AssigneeID}, nil
+		}
+		// 未分配负责人的线索提醒所有管理员
+		if !adminsLoaded {
+			ids, err := s.repo.GetAdminUserIDs(ctx)
+			if err != nil {
+				return nil, err
+			}
+			adminIDs, adminsLoaded = ids, true
+		}
+		return adminIDs, nil
+	}
+
+	reminded := 0
+
+	dueLeads, err := s.repo.GetDueFollowUps(ctx, now, leadReminderBatchSize)
+	if err != nil {
+		return reminded, err
+	}
+	dueIDs := make([]string, 0, len(dueLeads))
+	for _, lead := range dueLeads {
+		userIDs, err := recipients(lead)
+		if err != nil {
+			return reminded, err
+		}
+		for _, userID := range userIDs {
+			s.notifyLead(ctx, userID, models.NotificationTypeLeadFollowUpDue, lead)
+		}
+		dueIDs = append(dueIDs, lead.ID)
+	}
+	if err := s.repo.MarkFollowUpReminded(ctx, dueIDs, now); err != nil {
+		return reminded, err
+	}
+	reminded += len(dueIDs)
+
+	if pendingTimeout > 0 {
+		staleLeads, err := s.repo.GetStalePending(ctx, now.Add(-pendingTimeout), leadReminderBatchSize)
+		if err != nil {
+			return reminded, err
+		}
+		staleIDs := make([]string, 0, len(staleLeads))
+		for _, lead := range staleLeads {
+			userIDs, err := recipients(lead)
+			if err != nil {
+				return reminded, err
+			}
+			for _, userID := range userIDs {
+				s.notifyLead(ctx, userID, models.NotificationTypeLeadPendingTooLong, lead)
+			}
+			staleIDs = append(staleIDs, lead.ID)
+		}
+		if err := s.repo.MarkPendingReminded(ctx, staleIDs, now); err != nil {
+			return reminded, err
+		}
+		reminded += len(staleIDs)
+	}
+
+	if reminded > 0 {
+		s.logger.Infof("线索提醒已发送: %d 条", reminded)
+	}
+	return reminded, nil
+}
+
+// notifyLead 向后台账号发送线索相关通知，发送失败只记录日志不影响主流程
+func (s *leadService) notifyLead(ctx context.Context, userID, notificationType string, lead *models.Lead) {
+	payload := map[string]interface{}{
+		"lead_id":      lead.ID,
+		"lead_no":      lead.LeadNo,
+		"company_name": lead.CompanyName,
+		"contact_name": lead.ContactName,
+	}
+	if lead.FollowUpDate != nil {
+		payload["follow_up_date"] = lead.FollowUpDate
+	}
+	if err := s.notificationService.Notify(ctx, models.NotificationRecipientUser, userID, notificationType, payload); err != nil {
+		s.logger.WithError(err).Warnf("发送线索通知失败: lead=%s user=%s type=%s", lead.ID, userID, notificationType)
+	}
+}
+
+// leadMatchedFields 返回两条线索标准化后相同的字段
+func leadMatchedFields(a, b *models.Lead) []string {
+	fields := []string{}
+	if a.NormalizedPhone != "" && a.NormalizedPhone == b.NormalizedPhone {
+		fields = append(fields, "phone")
+	}
+	if a.NormalizedEmail != "" && a.NormalizedEmail == b.NormalizedEmail {
+		fields = append(fields, "email")
+	}
+	if a.NormalizedCompany != "" && a.NormalizedCompany == b.NormalizedCompany {
+		fields = append(fields, "company")
+	}
+	return fields
+}
+
+// mergeLeadInto 用来源线索补全目标线索缺失的信息，目标线索已有的内容保持不变
+func mergeLeadInto(source, target *models.Lead) {
+	if target.ContactEmail == "" {
+		target.ContactEmail = source.ContactEmail
+	}
+	if target.Requirement == "" || target.Requirement == "Empty" {
+		target.Requirement = source.Requirement
+	}
+	if source.ExtraInfo != "" && source.ExtraInfo != target.ExtraInfo {
+		if target.ExtraInfo == "" {
+			target.ExtraInfo = source.ExtraInfo
+		} else {
+			target.ExtraInfo += "\n" + source.ExtraInfo
+		}
+	}
+	if target.AssigneeID == nil {
+		target.AssigneeID = source.AssigneeID
+	}
+	if target.FollowUpDate == nil {
+		target.FollowUpDate = source.FollowUpDate
+	}
+	if target.DuplicateOfID != nil && *target.DuplicateOfID == source.ID {
+		target.DuplicateOfID = nil
+	}
+}
+
+// sameFollowUpDate 跟进日期是否相同
+func sameFollowUpDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"license-manager/internal/models"
+)
+
+func TestNewLeadCustomer(t *testing.T) {
+	lead := &models.Lead{
+		CompanyName:  "Acme",
+		ContactName:  "Alice",
+		ContactPhone: " 13800000000 ",
+		Requirement:  "Empty",
+	}
+
+	customer := newLeadCustomer(lead, &models.LeadConvertRequest{}, "op-1")
+	if customer.ID == "" {
+		t.Error("customer ID should be generated before creation")
+	}
+	if customer.CustomerType != "enterprise" || customer.CustomerLevel != "basic" {
+		t.Errorf("unexpected defaults: type=%s level=%s", customer.CustomerType, customer.CustomerLevel)
+	}
+	if customer.Phone == nil || *customer.Phone != "13800000000" {
+		t.Errorf("Phone = %v, want trimmed contact phone", customer.Phone)
+	}
+	if customer.Email != nil || customer.Description != nil {
+		t.Error("empty email and placeholder requirement should not be copied")
+	}
+	if customer.CreatedBy != "op-1" {
+		t.Errorf("CreatedBy = %s, want op-1", customer.CreatedBy)
+	}
+}
+
+func TestNewLeadTrialAuthorizationRequestDefaults(t *testing.T) {
+	req := newLeadTrialAuthorizationRequest("c1", "LEAD001", &models.LeadTrialAuthorization{})
+	if req.CustomerID != "c1" {
+		t.Errorf("CustomerID = %s, want c1", req.CustomerID)
+	}
+	if req.ValidityDays != defaultLeadTrialValidityDays || req.MaxActivations != defaultLeadTrialMaxActivations {
+		t.Errorf("unexpected defaults: days=%d activations=%d", req.ValidityDays, req.MaxActivations)
+	}
+	if req.DeploymentType != "standalone" {
+		t.Errorf("DeploymentType = %s, want standalone", req.DeploymentType)
+	}
+
+	req = newLeadTrialAuthorizationRequest("c1", "LEAD001", &models.LeadTrialAuthorization{ValidityDays: 14, MaxActivations: 3, DeploymentType: "cloud"})
+	if req.ValidityDays != 14 || req.MaxActivations != 3 || req.DeploymentType != "cloud" {
+		t.Errorf("explicit values should be kept: %+v", req)
+	}
+}
+
+func newNormalizedLead(phone, email, company string) *models.Lead {
+	return &models.Lead{
+		NormalizedPhone:   models.NormalizeLeadPhone(phone),
+		NormalizedEmail:   models.NormalizeLeadEmail(email),
+		NormalizedCompany: models.NormalizeLeadCompany(company),
+	}
+}
+
+func TestLeadMatchedFields(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b *models.Lead
+		want []string
+	}{
+		{
+			"phone with country code and separators",
+			newNormalizedLead("+86 138-0000-0000", "", "甲公司"),
+			newNormalizedLead("13800000000", "", "乙公司"),
+			[]string{"phone"},
+		},
+		{
+			"email case and company suffix",
+			newNormalizedLead("13800000000", " Sales@Acme.com ", "Acme Co., Ltd."),
+			newNormalizedLead("13900000000", "sales@acme.com", "ACME"),
+			[]string{"email", "company"},
+		},
+		{
+			"chinese company suffix",
+			newNormalizedLead("", "", "北京某某科技有限公司"),
+			newNormalizedLead("", "", "北京某某科技"),
+			[]string{"company"},
+		},
+		{
+			"empty values never match",
+			newNormalizedLead("", "", ""),
+			newNormalizedLead("", "", ""),
+			[]string{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := leadMatchedFields(tc.a, tc.b)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("leadMatchedFields = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMergeLeadInto(t *testing.T) {
+	assignee := "u1"
+	source := &models.Lead{ID: "s1", ContactEmail: "a@b.com", Requirement: "need 10 seats", ExtraInfo: "from expo", AssigneeID: &assignee}
+	sourceID := source.ID
+	target := &models.Lead{ID: "t1", Requirement: "Empty", ExtraInfo: "web form", DuplicateOfID: &sourceID}
+
+	mergeLeadInto(source, target)
+	if target.ContactEmail != "a@b.com" || target.Requirement != "need 10 seats" {
+		t.Errorf("missing fields should be filled from source: %+v", target)
+	}
+	if target.ExtraInfo != "web form\nfrom expo" {
+		t.Errorf("ExtraInfo = %q", target.ExtraInfo)
+	}
+	if target.AssigneeID == nil || *target.AssigneeID != "u1" {
+		t.Error("unassigned target should take the source assignee")
+	}
+	if target.DuplicateOfID != nil {
+		t.Error("target should no longer be marked as duplicate of the merged lead")
+	}
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultLicenseCommandTTL 远程命令默认有效期
+const defaultLicenseCommandTTL = 72 * time.Hour
+
+// LicenseCommandService 远程命令服务接口（customerID 不为空时校验许可证归属）
+type LicenseCommandService interface {
+	CreateCommand(ctx context.Context, licenseID, customerID string, issuerType models.LicenseCommandIssuerType, issuerID string, req *models.LicenseCommandCreateRequest) (*models.LicenseCommand, error)
+	GetCommandList(ctx context.Context, licenseID, customerID string, req *models.LicenseCommandListRequest) (*models.LicenseCommandListResponse, error)
+	CancelCommand(ctx context.Context, licenseID, commandID, customerID string) (*models.LicenseCommand, error)
+}
+
+type licenseCommandService struct {
+	commandRepo repository.LicenseCommandRepository
+	licenseRepo repository.LicenseRepository
+	logger      *logrus.Logger
+}
+
+// NewLicenseCommandService 创建远程命令服务
+func NewLicenseCommandService(commandRepo repository.LicenseCommandRepository, licenseRepo repository.LicenseRepository, logger *logrus.Logger) LicenseCommandService {
+	return &licenseCommandService{
+		commandRepo: commandRepo,
+		licenseRepo: licenseRepo,
+		logger:      logger,
+	}
+}
+
+// CreateCommand 为许可证创建远程命令，设备下次心跳时下发
+func (s *licenseCommandService) CreateCommand(ctx context.Context, licenseID, customerID string, issuerType models.LicenseCommandIssuerType, issuerID string, req *models.LicenseCommandCreateRequest) (*models.LicenseCommand, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	license, err := s.getLicense(ctx, licenseID, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if license.Status != "active" {
+		return nil, i18n.NewI18nError("300903", lang) // 仅激活状态的许可证可以下发命令
+	}
+
+	payload, err := buildLicenseCommandPayload(req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900001", lang, err.Error())
+	}
+
+	ttl := defaultLicenseCommandTTL
+	if req.ExpiresInHours != nil {
+		ttl = time.Duration(*req.ExpiresInHours) * time.Hour
+	}
+
+	command := &models.LicenseCommand{
+		LicenseID:           license.ID,
+		AuthorizationCodeID: license.AuthorizationCodeID,
+		CustomerID:          license.CustomerID,
+		CommandType:         req.CommandType,
+		Payload:             payload,
+		Status:              models.LicenseCommandStatusPending,
+		IssuerType:          string(issuerType),
+		IssuerID:            issuerID,
+		ExpiresAt:           time.Now().Add(ttl),
+	}
+	if err := s.commandRepo.Create(ctx, command); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	fillLicenseCommandDisplay(command, time.Now(), lang)
+	return command, nil
+}
+
+// GetCommandList 查询许可证的远程命令及执行状态
+func (s *licenseCommandService) GetCommandList(ctx context.Context, licenseID, customerID string, req *models.LicenseCommandListRequest) (*models.LicenseCommandListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if _, err := s.getLicense(ctx, licenseID, customerID); err != nil {
+		return nil, err
+	}
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	now := time.Now()
+	commands, total, err := s.commandRepo.GetList(ctx, licenseID, req, now)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	for _, command := range commands {
+		fillLicenseCommandDisplay(command, now, lang)
+	}
+
+	return &models.LicenseCommandListResponse{
+		List:       commands,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+// CancelCommand 取消尚未确认的命令，已下发的命令客户端可能已执行
+func (s *licenseCommandService) CancelCommand(ctx context.Context, licenseID, commandID, customerID string) (*models.LicenseCommand, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if _, err := s.getLicense(ctx, licenseID, customerID); err != nil {
+		return nil, err
+	}
+
+	command, err := s.commandRepo.GetByID(ctx, commandID)
+	if err != nil {
+		if errors.Is(err, repository.ErrLicenseCommandNotFound) {
+			return nil, i18n.NewI18nError("300901", lang) // 命令不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if command.LicenseID != licenseID {
+		return nil, i18n.NewI18nError("300901", lang)
+	}
+
+	if err := s.commandRepo.Cancel(ctx, command.ID); err != nil {
+		if errors.Is(err, repository.ErrLicenseCommandNotOpen) {
+			return nil, i18n.NewI18nError("300902", lang) // 命令已完成或已取消
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	command.Status = models.LicenseCommandStatusCancelled
+	fillLicenseCommandDisplay(command, time.Now(), lang)
+	return command, nil
+}
+
+// getLicense 获取许可证，customerID 不为空时校验归属
+func (s *licenseCommandService) getLicense(ctx context.Context, licenseID, customerID string) (*models.License, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	license, err := s.licenseRepo.GetLicenseByID(ctx, licenseID)
+	if err != nil {
+		if errors.Is(err, repository.ErrLicenseNotFound) {
+			if customerID != "" {
+				return nil, i18n.NewI18nError("620001", lang) // 设备不存在或无权限访问
+			}
+			return nil, i18n.NewI18nError("300006", lang) // 许可证不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if customerID != "" && license.CustomerID != customerID {
+		return nil, i18n.NewI18nError("620001", lang)
+	}
+	return license, nil
+}
+
+// buildLicenseCommandPayload 组装下发给客户端的命令参数
+func buildLicenseCommandPayload(req *models.LicenseCommandCreateRequest) (models.JSON, error) {
+	payload := map[string]interface{}{}
+	for key, value := range req.Parameters {
+		payload[key] = value
+	}
+
+	if req.CommandType == models.LicenseCommandShowMessage {
+		if req.Message == nil || strings.TrimSpace(*req.Message) == "" {
+			return nil, fmt.Errorf("message is required for show_message command")
+		}
+		payload["message"] = strings.TrimSpace(*req.Message)
+	}
+
+	if len(payload) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return models.JSON(data), nil
+}
+
+// effectiveLicenseCommandStatus 展示用状态：超过有效期仍未确认的命令视为过期
+func effectiveLicenseCommandStatus(command *models.LicenseCommand, now time.Time) string {
+	if command.IsOpen() && command.ExpiresAt.Before(now) {
+		return models.LicenseCommandStatusExpired
+	}
+	return command.Status
+}
+
+func fillLicenseCommandDisplay(command *models.LicenseCommand, now time.Time, lang string) {
+	command.Status = effectiveLicenseCommandStatus(command, now)
+	command.CommandTypeDisplay = i18n.GetEnumMessage("license_command_type", command.CommandType, lang)
+	command.StatusDisplay = i18n.GetEnumMessage("license_command_status", command.Status, lang)
+}
+
+// applyLicenseCommandAcks 记录客户端确认的命令执行结果
+// 停用命令执行成功后释放席位（许可证置为 inactive）；处理失败只记录日志不影响心跳
+func (s *licenseService) applyLicenseCommandAcks(ctx context.Context, license *models.License, acks []models.LicenseCommandAck, now time.Time) {
+	if s.commandRepo == nil {
+		return
+	}
+
+	for _, ack := range acks {
+		if ack.CommandID == "" {
+			continue
+		}
+
+		status := models.LicenseCommandStatusFailed
+		if ack.Success {
+			status = models.LicenseCommandStatusSucceeded
+		}
+		var result models.JSON
+		if len(ack.Result) > 0 {
+			data, err := json.Marshal(ack.Result)
+			if err == nil {
+				result = models.JSON(data)
+			}
+		}
+		errorMessage := ack.Error
+		if errorMessage != nil {
+			if runes := []rune(*errorMessage); len(runes) > 1000 {
+				truncated := string(runes[:1000])
+				errorMessage = &truncated
+			}
+		}
+
+		command, err := s.commandRepo.Acknowledge(ctx, license.ID, ack.CommandID, status, result, errorMessage, now)
+		if err != nil {
+			if !errors.Is(err, repository.ErrLicenseCommandNotFound) && !errors.Is(err, repository.ErrLicenseCommandNotOpen) {
+				s.logger.WithError(err).WithField("command_id", ack.CommandID).Error("记录远程命令执行结果失败")
+			}
+			continue
+		}
+
+		if command.CommandType == models.LicenseCommandDeactivate && status == models.LicenseCommandStatusSucceeded {
+			license.Status = "inactive"
+		}
+	}
+}
+
+// deliverLicenseCommands 在心跳响应中下发待执行的命令
+// 刷新许可证命令随响应直接返回新的许可证文件
+func (s *licenseService) deliverLicenseCommands(ctx context.Context, license *models.License, resp *models.HeartbeatResponse, now time.Time) {
+	if s.commandRepo == nil {
+		return
+	}
+
+	logger := s.logger.WithField("license_id", license.ID)
+	if err := s.commandRepo.ExpireOverdue(ctx, license.ID, now); err != nil {
+		logger.WithError(err).Warn("标记过期远程命令失败")
+	}
+
+	commands, err := s.commandRepo.GetDeliverable(ctx, license.ID, now)
+	if err != nil {
+		logger.WithError(err).Error("查询待下发远程命令失败")
+		return
+	}
+	if len(commands) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(commands))
+	for _, command := range commands {
+		if command.CommandType == models.LicenseCommandRefreshLicense && resp.LicenseFile == nil && license.AuthorizationCode != nil {
+			fileContent, err := s.generateLicenseFileContent(ctx, license, license.AuthorizationCode)
+			if err != nil {
+				logger.WithError(err).Error("生成许可证文件失败")
+				continue
+			}
+			resp.LicenseFile = &fileContent
+			resp.ConfigUpdated = true
+		}
+		resp.Commands = append(resp.Commands, licenseCommandDelivery(command))
+		ids = append(ids, command.ID)
+	}
+
+	if err := s.commandRepo.MarkDelivered(ctx, ids, now); err != nil {
+		logger.WithError(err).Error("标记远程命令已下发失败")
+	}
+}
+
+// licenseCommandDelivery 转换为心跳下发格式
+func licenseCommandDelivery(command *models.LicenseCommand) models.LicenseCommandDelivery {
+	delivery := models.LicenseCommandDelivery{
+		ID:          command.ID,
+		CommandType: command.CommandType,
+		IssuedAt:    command.CreatedAt,
+		ExpiresAt:   command.ExpiresAt,
+	}
+	if len(command.Payload) > 0 {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(command.Payload, &payload); err == nil {
+			delivery.Payload = payload
+		}
+	}
+	return delivery
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+)
+
+func TestBuildLicenseCommandPayload(t *testing.T) {
+	if _, err := buildLicenseCommandPayload(&models.LicenseCommandCreateRequest{CommandType: models.LicenseCommandShowMessage}); err == nil {
+		t.Fatal("expected error for show_message without message")
+	}
+
+	payload, err := buildLicenseCommandPayload(&models.LicenseCommandCreateRequest{CommandType: models.LicenseCommandRefreshLicense})
+	if err != nil || payload != nil {
+		t.Fatalf("expected empty payload, got %s %v", payload, err)
+	}
+
+	message := "  请尽快升级  "
+	payload, err = buildLicenseCommandPayload(&models.LicenseCommandCreateRequest{
+		CommandType: models.LicenseCommandShowMessage,
+		Message:     &message,
+		Parameters:  map[string]interface{}{"level": "warning", "message": "ignored"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["message"] != "请尽快升级" || decoded["level"] != "warning" {
+		t.Fatalf("unexpected payload: %v", decoded)
+	}
+}
+
+func TestEffectiveLicenseCommandStatus(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		status    string
+		expiresAt time.Time
+		want      string
+	}{
+		{models.LicenseCommandStatusPending, now.Add(time.Hour), models.LicenseCommandStatusPending},
+		{models.LicenseCommandStatusDelivered, now.Add(-time.Hour), models.LicenseCommandStatusExpired},
+		{models.LicenseCommandStatusSucceeded, now.Add(-time.Hour), models.LicenseCommandStatusSucceeded},
+	}
+	for _, c := range cases {
+		command := &models.LicenseCommand{Status: c.status, ExpiresAt: c.expiresAt}
+		if got := effectiveLicenseCommandStatus(command, now); got != c.want {
+			t.Errorf("status %s: got %s, want %s", c.status, got, c.want)
+		}
+	}
+}
@@ -20,21 +20,46 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 激活事务内的业务错误
+var (
+	errLicenseTransferredAway = errors.New("license has been transferred to another device")
+	errLicenseRevoked         = errors.New("license has been revoked")
 )
 
 type licenseService struct {
 	licenseRepo   repository.LicenseRepository
+	transferRepo  repository.LicenseTransferRepository
+	violationRepo repository.ActivationViolationRepository
+	releaseRepo   repository.SoftwareReleaseRepository
+	commandRepo   repository.LicenseCommandRepository
+	customerRepo  repository.CustomerRepository
+	partnerRepo   repository.PartnerRepository
+	customFields  repository.CustomFieldRepository
+	seatPools     repository.SeatPoolRepository
+	geoIP         CountryResolver // 离线GeoIP库，未配置时为nil
 	db            *gorm.DB
 	logger        *logrus.Logger
 	rsaPrivateKey *utils.RSAPrivateKey // RSA私钥（缓存）
 }
 
 // NewLicenseService 创建许可证服务实例
-func NewLicenseService(licenseRepo repository.LicenseRepository, db *gorm.DB, logger *logrus.Logger) LicenseService {
+func NewLicenseService(licenseRepo repository.LicenseRepository, transferRepo repository.LicenseTransferRepository, violationRepo repository.ActivationViolationRepository, releaseRepo repository.SoftwareReleaseRepository, commandRepo repository.LicenseCommandRepository, customerRepo repository.CustomerRepository, partnerRepo repository.PartnerRepository, customFields repository.CustomFieldRepository, seatPools repository.SeatPoolRepository, geoIP CountryResolver, db *gorm.DB, logger *logrus.Logger) LicenseService {
 	return &licenseService{
-		licenseRepo: licenseRepo,
-		db:          db,
-		logger:      logger,
+		licenseRepo:   licenseRepo,
+		transferRepo:  transferRepo,
+		violationRepo: violationRepo,
+		releaseRepo:   releaseRepo,
+		commandRepo:   commandRepo,
+		customerRepo:  customerRepo,
+		partnerRepo:   partnerRepo,
+		customFields:  customFields,
+		seatPools:     seatPools,
+		geoIP:         geoIP,
+		db:            db,
+		logger:        logger,
 	}
 }
 
@@ -47,6 +72,26 @@ func (s *licenseService) GetLicenseList(ctx context.Context, req *models.License
 		return nil, i18n.NewI18nError("900001", lang) // 业务错误，不覆盖多语言message
 	}
 
+	// 按客户筛选时可包含其所有下级客户
+	if req.IncludeSubsidiaries && req.CustomerID != "" {
+		customerIDs, err := expandCustomerSubtree(ctx, s.customerRepo, req.CustomerID)
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		req.CustomerIDs = customerIDs
+	}
+
+	// 经销商账号只能查看名下客户的许可证
+	customerIDs, err := scopeCustomerFilter(ctx, s.partnerRepo, req.CustomerIDs, req.CustomerID)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	req.CustomerIDs = customerIDs
+
+	if err := normalizeCustomFieldFilter(ctx, s.customFields, models.CustomFieldEntityLicense, &req.CustomFieldFilter); err != nil {
+		return nil, err
+	}
+
 	// 委托给Repository层进行数据访问
 	result, err := s.licenseRepo.GetLicenseList(ctx, req)
 	if err != nil {
@@ -59,6 +104,20 @@ func (s *licenseService) GetLicenseList(ctx context.Context, req *models.License
 		s.fillDisplayFields(&result.List[i], lang)
 	}
 
+	// 自定义字段和标签
+	ids := make([]string, 0, len(result.List))
+	for _, item := range result.List {
+		ids = append(ids, item.ID)
+	}
+	fields, err := s.customFields.GetEntityCustomFields(ctx, models.CustomFieldEntityLicense, ids)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	for i := range result.List {
+		result.List[i].CustomFields = fields[result.List[i].ID].CustomFields
+		result.List[i].Tags = fields[result.List[i].ID].Tags
+	}
+
 	return result, nil
 }
 
@@ -95,13 +154,46 @@ func (s *licenseService) GetLicense(ctx context.Context, id string) (*models.Lic
 		// 数据库相关错误，使用系统错误码，覆盖message显示详细信息
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if err := s.ensureLicenseInScope(ctx, license); err != nil {
+		return nil, err
+	}
 
 	// 转换为详情响应格式
 	response := s.convertToDetailResponse(license, lang)
 
+	// 自定义字段和标签
+	fields, err := s.customFields.GetEntityCustomFields(ctx, models.CustomFieldEntityLicense, []string{id})
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	response.CustomFields = fields[id].CustomFields
+	response.Tags = fields[id].Tags
+
 	return response, nil
 }
 
+// UpdateLicenseCustomFields 修改许可证的自定义字段和标签
+func (s *licenseService) UpdateLicenseCustomFields(ctx context.Context, id string, req *models.EntityCustomFieldsUpdateRequest) (*models.EntityCustomFields, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if id == "" || req == nil {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+
+	license, err := s.licenseRepo.GetLicenseByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrLicenseNotFound) {
+			return nil, i18n.NewI18nError("300006", lang) // 许可证不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if err := s.ensureLicenseInScope(ctx, license); err != nil {
+		return nil, err
+	}
+
+	return updateEntityCustomFields(ctx, s.customFields, models.CustomFieldEntityLicense, id, req)
+}
+
 // CreateLicense 创建许可证
 func (s *licenseService) CreateLicense(ctx context.Context, req *models.LicenseCreateRequest) (*models.License, error) {
 	lang := pkgcontext.GetLanguageFromContext(ctx)
@@ -170,6 +262,7 @@ func (s *licenseService) convertToDetailResponse(license *models.License, lang s
 		CustomerID:          license.CustomerID,
 		HardwareFingerprint: license.HardwareFingerprint,
 		ActivationIP:        license.ActivationIP,
+		ActivationMode:      license.ActivationMode,
 		Status:              license.Status,
 		IsOnline:            license.IsOnline,
 		LastOnlineIP:        license.LastOnlineIP,
@@ -244,6 +337,9 @@ func (s *licenseService) RevokeLicense(ctx context.Context, id string, req *mode
 		}
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if err := s.ensureLicenseInScope(ctx, existingLicense); err != nil {
+		return nil, err
+	}
 
 	// 检查许可证是否已经被撤销
 	if existingLicense.Status == "revoked" {
@@ -305,6 +401,9 @@ func (s *licenseService) GenerateLicenseFile(ctx context.Context, id string) ([]
 		}
 		return nil, "", "", i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if err := s.ensureLicenseInScope(ctx, license); err != nil {
+		return nil, "", "", err
+	}
 
 	s.logger.Infof("[GenerateLicenseFile] 许可证信息查询成功，license_key: %s, status: %s", license.LicenseKey, license.Status)
 
@@ -314,6 +413,12 @@ func (s *licenseService) GenerateLicenseFile(ctx context.Context, id string) ([]
 		return nil, "", "", i18n.NewI18nError("300007", lang) // 许可证已被撤销
 	}
 
+	// 云部署的许可证须在线激活并保持心跳，不提供离线许可证文件
+	if license.AuthorizationCode != nil && license.AuthorizationCode.DeploymentType == models.DeploymentTypeCloud {
+		s.logger.Warnf("[GenerateLicenseFile] 云部署许可证不支持导出离线许可证文件，license_id: %s", id)
+		return nil, "", "", i18n.NewI18nError(errCodeCloudLicenseFile, lang)
+	}
+
 	// 构建许可证文件内容
 	licenseFileData := map[string]interface{}{
 		"license_key":           license.LicenseKey,
@@ -330,7 +435,12 @@ func (s *licenseService) GenerateLicenseFile(ctx context.Context, id string) ([]
 		licenseFileData["authorization_code"] = license.AuthorizationCode.Code
 		licenseFileData["start_date"] = license.AuthorizationCode.StartDate
 		licenseFileData["end_date"] = license.AuthorizationCode.EndDate
+		if license.AuthorizationCode.MaintenanceUntil != nil {
+			// 离线许可证文件与在线激活下发的文件一致，包含维护截止时间
+			licenseFileData["maintenance_until"] = license.AuthorizationCode.MaintenanceUntil
+		}
 		licenseFileData["deployment_type"] = license.AuthorizationCode.DeploymentType
+		licenseFileData["deployment_policy"] = buildDeploymentPolicy(license.AuthorizationCode, license.ActivationMode, offlineGraceConfig())
 		licenseFileData["max_activations"] = license.AuthorizationCode.MaxActivations
 
 		// 包含功能配置
@@ -345,6 +455,15 @@ func (s *licenseService) GenerateLicenseFile(ctx context.Context, id string) ([]
 		if customParameters := parseJSONField(license.AuthorizationCode.CustomParameters); len(customParameters) > 0 {
 			licenseFileData["custom_parameters"] = customParameters
 		}
+
+		// 授权码上标记为写入许可证文件的自定义字段
+		customFields, err := licenseCustomFields(ctx, s.customFields, license.AuthorizationCode.ID)
+		if err != nil {
+			return nil, "", "", i18n.NewI18nError("900004", lang, err.Error())
+		}
+		if len(customFields) > 0 {
+			licenseFileData["custom_fields"] = customFields
+		}
 	}
 
 	// 序列化许可证数据
@@ -365,6 +484,25 @@ func (s *licenseService) GenerateLicenseFile(ctx context.Context, id string) ([]
 	return encryptedData, fileName, license.LicenseKey, nil
 }
 
+// getRelayLicense 获取混合部署授权码所指定中继节点的激活许可证，用于校验中继签名；未指定中继、中继尚未激活或许可证已失效时返回 nil
+func (s *licenseService) getRelayLicense(ctx context.Context, authCode *models.AuthorizationCode) (*models.License, error) {
+	if authCode.DeploymentType != models.DeploymentTypeHybrid || !relayDesignated(authCode) {
+		return nil, nil
+	}
+	license, err := s.licenseRepo.GetLicenseByHardwareFingerprint(ctx, authCode.ID, *authCode.RelayFingerprint)
+	if err != nil {
+		if errors.Is(err, repository.ErrLicenseNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// 已停用、撤销或转移的中继许可证不能再为其他设备签名
+	if license.Status != "active" {
+		return nil, nil
+	}
+	return license, nil
+}
+
 // generateLicenseKey 生成许可证密钥
 func (s *licenseService) generateLicenseKey() (string, error) {
 	// 生成12字节随机数据
@@ -393,6 +531,8 @@ func (s *licenseService) ActivateLicense(ctx context.Context, req *models.Activa
 	if idx := strings.Index(req.AuthorizationCode, "&"); idx > 0 {
 		req.AuthorizationCode = strings.TrimSpace(req.AuthorizationCode[:idx])
 	}
+	// 兼容口述录入的 Base32 码（大小写、分隔符、易混字符）
+	req.AuthorizationCode = utils.NormalizeAuthorizationCode(req.AuthorizationCode)
 
 	if req.AuthorizationCode == "" {
 		return nil, i18n.NewI18nError("900001", lang)
@@ -412,16 +552,66 @@ func (s *licenseService) ActivateLicense(ctx context.Context, req *models.Activa
 	if authCode.IsLocked {
 		return nil, i18n.NewI18nError("300003", lang) // 授权码已被锁定
 	}
-	if now.Before(authCode.StartDate) || now.After(authCode.EndDate) {
+	if now.Before(authCode.StartDate) {
+		return nil, i18n.NewI18nError("300011", lang) // 授权码尚未生效
+	}
+	if now.After(authCode.EndDate) {
 		return nil, i18n.NewI18nError("300001", lang) // 授权码已过期
 	}
 
+	// 使用限制策略：IP网段、国家/地区、时间窗口
+	policy, err := authCode.GetActivationPolicy()
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	country := s.lookupCountry(clientIP)
+	if violation := checkActivationPolicy(policy, clientIP, country, now); violation != "" {
+		s.recordActivationViolation(ctx, authCode, nil, models.ActivationViolationStageActivation, violation, clientIP, country, req.HardwareFingerprint)
+		return nil, i18n.NewI18nError(activationViolationErrorCodes[violation], lang)
+	}
+
+	// 部署类型：单机可离线激活，云部署仅在线激活，混合部署须经指定的本地中继
+	activationMode := normalizeActivationMode(strings.TrimSpace(req.ActivationMode))
+	if activationMode == "" {
+		return nil, i18n.NewI18nError("900001", lang, "activation_mode must be one of online, offline, relay")
+	}
+	relayLicense, err := s.getRelayLicense(ctx, authCode)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	relay := relayProof{Timestamp: req.RelayTimestamp, Signature: strings.TrimSpace(req.RelaySignature)}
+	if errCode := checkActivationDeployment(authCode, activationMode, req.HardwareFingerprint, relayLicense, relay, now); errCode != "" {
+		return nil, i18n.NewI18nError(errCode, lang)
+	}
+	activationMode = effectiveActivationMode(authCode, activationMode, req.HardwareFingerprint)
+	// 离线激活的设备不发送心跳，不记录心跳时间（也不参与闲置席位回收）
+	var lastHeartbeat *time.Time
+	if activationMode != models.ActivationModeOffline {
+		lastHeartbeat = &now
+	}
+	deploymentPolicy := buildDeploymentPolicy(authCode, activationMode, offlineGraceConfig())
+
 	// 使用事务确保并发安全
 	var response *models.ActivateResponse
 	err = s.db.Transaction(func(tx *gorm.DB) error {
-		// 检查当前激活数量
-		count, err := s.licenseRepo.GetActiveLicenseCount(ctx, authCode.ID)
-		if err != nil {
+		// 从客户席位池取用席位的授权码先锁定席位池，同一客户的激活串行执行，以席位池的剩余席位为上限
+		seatPoolErr := repository.ErrSeatPoolNotFound
+		if authCode.UseSeatPool {
+			seatPoolErr = s.seatPools.CheckSeatPoolWithTx(ctx, tx, authCode.CustomerID)
+			if seatPoolErr != nil && !errors.Is(seatPoolErr, repository.ErrSeatPoolExhausted) && !errors.Is(seatPoolErr, repository.ErrSeatPoolNotFound) {
+				return seatPoolErr
+			}
+		}
+
+		// 锁定授权码后在事务内统计激活数量，与同一授权码的并发激活、席位分享和收回串行执行
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").Where("id = ?", authCode.ID).First(&models.AuthorizationCode{}).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.License{}).
+			Where("authorization_code_id = ? AND status = ?", authCode.ID, "active").
+			Count(&count).Error; err != nil {
 			return err
 		}
 
@@ -431,12 +621,31 @@ func (s *licenseService) ActivateLicense(ctx context.Context, req *models.Activa
 			authCode.ID, req.HardwareFingerprint).First(&existingLicense).Error
 
 		if err == nil {
+			// 已转移到其他设备或已撤销的许可证不能通过重新激活恢复，否则停用回执和撤销失去意义
+			switch existingLicense.Status {
+			case models.LicenseStatusTransferred:
+				return errLicenseTransferredAway
+			case "revoked":
+				return errLicenseRevoked
+			}
+
+			// 已存在但不在激活状态（如闲置回收后重新上线），需重新占用席位
+			if existingLicense.Status != "active" {
+				if err := checkActivationSeats(count, authCode.MaxActivations, seatPoolErr); err != nil {
+					return err
+				}
+				if err := checkDailyActivationLimit(tx, authCode.ID, policy, now); err != nil {
+					return err
+				}
+			}
+
 			// 已存在，直接激活
 			existingLicense.Status = "active"
 			existingLicense.ActivationIP = &clientIP
+			existingLicense.ActivationMode = activationMode
 			now := time.Now()
 			existingLicense.ActivatedAt = &now
-			existingLicense.LastHeartbeat = &now
+			existingLicense.LastHeartbeat = lastHeartbeat
 			existingLicense.LastOnlineIP = &clientIP
 
 			if err := tx.Save(&existingLicense).Error; err != nil {
@@ -444,7 +653,7 @@ func (s *licenseService) ActivateLicense(ctx context.Context, req *models.Activa
 			}
 
 			// 生成许可证文件
-			licenseFile, err := s.generateLicenseFileContent(&existingLicense, authCode)
+			licenseFile, err := s.generateLicenseFileContent(ctx, &existingLicense, authCode)
 			if err != nil {
 				return err
 			}
@@ -453,6 +662,7 @@ func (s *licenseService) ActivateLicense(ctx context.Context, req *models.Activa
 				LicenseKey:        existingLicense.LicenseKey,
 				LicenseFile:       licenseFile,
 				HeartbeatInterval: 300, // 5分钟
+				DeploymentPolicy:  deploymentPolicy,
 			}
 			return nil
 		} else if err != gorm.ErrRecordNotFound {
@@ -460,8 +670,11 @@ func (s *licenseService) ActivateLicense(ctx context.Context, req *models.Activa
 		}
 
 		// 检查激活数量限制
-		if count >= int64(authCode.MaxActivations) {
-			return repository.ErrLicenseNotFound // 使用已有错误，表示激活数量已达上限
+		if err := checkActivationSeats(count, authCode.MaxActivations, seatPoolErr); err != nil {
+			return err
+		}
+		if err := checkDailyActivationLimit(tx, authCode.ID, policy, now); err != nil {
+			return err
 		}
 
 		// 生成新的许可证
@@ -476,9 +689,10 @@ func (s *licenseService) ActivateLicense(ctx context.Context, req *models.Activa
 			CustomerID:          authCode.CustomerID,
 			HardwareFingerprint: req.HardwareFingerprint,
 			ActivationIP:        &clientIP,
+			ActivationMode:      activationMode,
 			Status:              "active",
 			ActivatedAt:         &now,
-			LastHeartbeat:       &now,
+			LastHeartbeat:       lastHeartbeat,
 			LastOnlineIP:        &clientIP,
 		}
 
@@ -495,7 +709,7 @@ func (s *licenseService) ActivateLicense(ctx context.Context, req *models.Activa
 		}
 
 		// 生成许可证文件
-		licenseFile, err := s.generateLicenseFileContent(license, authCode)
+		licenseFile, err := s.generateLicenseFileContent(ctx, license, authCode)
 		if err != nil {
 			return err
 		}
@@ -504,6 +718,7 @@ func (s *licenseService) ActivateLicense(ctx context.Context, req *models.Activa
 			LicenseKey:        license.LicenseKey,
 			LicenseFile:       licenseFile,
 			HeartbeatInterval: 300,
+			DeploymentPolicy:  deploymentPolicy,
 		}
 		return nil
 	})
@@ -512,6 +727,19 @@ func (s *licenseService) ActivateLicense(ctx context.Context, req *models.Activa
 		if errors.Is(err, repository.ErrLicenseNotFound) {
 			return nil, i18n.NewI18nError("300004", lang) // 激活数量已达上限
 		}
+		if errors.Is(err, repository.ErrSeatPoolExhausted) {
+			return nil, i18n.NewI18nError("300016", lang) // 客户席位池已无剩余席位
+		}
+		if errors.Is(err, errLicenseTransferredAway) {
+			return nil, i18n.NewI18nError("300017", lang) // 该设备的席位已转移到其他设备
+		}
+		if errors.Is(err, errLicenseRevoked) {
+			return nil, i18n.NewI18nError("300007", lang) // 许可证已被撤销
+		}
+		if errors.Is(err, errDailyActivationLimitExceeded) {
+			s.recordActivationViolation(ctx, authCode, nil, models.ActivationViolationStageActivation, models.ActivationViolationDailyLimit, clientIP, country, req.HardwareFingerprint)
+			return nil, i18n.NewI18nError("300015", lang) // 今日新增激活数已达上限
+		}
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
 
@@ -541,19 +769,60 @@ func (s *licenseService) Heartbeat(ctx context.Context, req *models.HeartbeatReq
 		return nil, i18n.NewI18nError("300007", lang) // 许可证已被撤销
 	}
 
-	// 更新心跳时间和使用数据
+	// 已转移到其他设备：下发停用回执，不再更新心跳
+	if license.Status == models.LicenseStatusTransferred {
+		return s.transferredHeartbeatResponse(ctx, license), nil
+	}
+
+	// 授权码已删除（预加载不到）的许可证不能继续使用，否则会跳过以下全部检查
+	if license.AuthorizationCode == nil {
+		return nil, i18n.NewI18nError("300001", lang) // 授权码不存在
+	}
+
+	// 使用限制策略：IP网段、国家/地区、时间窗口
 	now := time.Now()
+	policy, err := license.AuthorizationCode.GetActivationPolicy()
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	country := s.lookupCountry(clientIP)
+	if violation := checkActivationPolicy(policy, clientIP, country, now); violation != "" {
+		s.recordActivationViolation(ctx, license.AuthorizationCode, &license.ID, models.ActivationViolationStageHeartbeat, violation, clientIP, country, license.HardwareFingerprint)
+		return nil, i18n.NewI18nError(activationViolationErrorCodes[violation], lang)
+	}
+
+	// 部署类型：混合部署的设备须经指定的本地中继转发心跳
+	relayLicense, err := s.getRelayLicense(ctx, license.AuthorizationCode)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	relay := relayProof{Timestamp: req.RelayTimestamp, Signature: strings.TrimSpace(req.RelaySignature)}
+	if errCode := checkHeartbeatDeployment(license.AuthorizationCode, license.HardwareFingerprint, relayLicense, relay, now); errCode != "" {
+		return nil, i18n.NewI18nError(errCode, lang)
+	}
+
+	// 更新心跳时间和使用数据
+	loadedStatus := license.Status
 	license.LastHeartbeat = &now
 	license.LastOnlineIP = &clientIP
+	updates := map[string]interface{}{
+		"last_heartbeat": now,
+		"last_online_ip": clientIP,
+		"updated_at":     now,
+	}
 
 	// 更新使用数据
 	if req.UsageData != nil {
 		usageDataBytes, err := json.Marshal(req.UsageData)
 		if err == nil {
 			license.UsageData = models.JSON(usageDataBytes)
+			updates["usage_data"] = license.UsageData
 		}
 	}
 
+	// 远程命令：记录上次下发命令的执行结果
+	s.applyLicenseCommandAcks(ctx, license, req.CommandAcks, now)
+
 	// 检查配置是否有更新
 	configUpdated := false
 	var licenseFile *string
@@ -563,31 +832,87 @@ func (s *licenseService) Heartbeat(ctx context.Context, req *models.HeartbeatReq
 		if err == nil && license.AuthorizationCode.UpdatedAt.After(clientConfigTime) {
 			configUpdated = true
 			// 生成新的许可证文件
-			fileContent, err := s.generateLicenseFileContent(license, license.AuthorizationCode)
+			fileContent, err := s.generateLicenseFileContent(ctx, license, license.AuthorizationCode)
 			if err == nil {
 				licenseFile = &fileContent
 				license.ConfigUpdatedAt = &now
+				updates["config_updated_at"] = now
 			}
 		}
 	}
 
-	// 保存更新
-	if err := s.licenseRepo.UpdateLicense(ctx, license); err != nil {
+	// 停用命令执行成功时同步更新状态
+	if license.Status != loadedStatus {
+		updates["status"] = license.Status
+	}
+
+	// 仅更新心跳相关字段，并以读取时的状态为条件，不覆盖并发的转移、撤销或回收
+	updated, err := s.licenseRepo.UpdateLicenseHeartbeat(ctx, license.ID, loadedStatus, updates)
+	if err != nil {
 		return nil, i18n.NewI18nError("900004", lang, err.Error())
 	}
+	if !updated {
+		return s.concurrentHeartbeatResponse(ctx, license.LicenseKey)
+	}
 
 	response := &models.HeartbeatResponse{
 		Status:            license.Status,
 		ConfigUpdated:     configUpdated,
 		LicenseFile:       licenseFile,
 		HeartbeatInterval: 300,
+		MaintenanceActive: true,
+	}
+	if license.AuthorizationCode != nil {
+		response.MaintenanceUntil = license.AuthorizationCode.MaintenanceUntil
+		response.MaintenanceActive = maintenanceActive(license.AuthorizationCode.MaintenanceUntil, now)
+		response.DeploymentPolicy = buildDeploymentPolicy(license.AuthorizationCode, license.ActivationMode, offlineGraceConfig())
+		s.advertiseLatestRelease(ctx, license.AuthorizationCode, req, response, now)
+	}
+	if license.Status == "active" {
+		s.deliverLicenseCommands(ctx, license, response, now)
 	}
 
 	return response, nil
 }
 
+// transferredHeartbeatResponse 已转移许可证的心跳响应，附带停用回执
+func (s *licenseService) transferredHeartbeatResponse(ctx context.Context, license *models.License) *models.HeartbeatResponse {
+	response := &models.HeartbeatResponse{
+		Status:            license.Status,
+		HeartbeatInterval: 300,
+	}
+	if transfer, err := s.transferRepo.GetLatestByFromLicenseID(ctx, license.ID); err == nil {
+		response.DeactivationReceipt = &transfer.DeactivationReceipt
+	}
+	return response
+}
+
+// concurrentHeartbeatResponse 心跳期间许可证状态被并发修改时，按最新状态响应，不再下发文件和命令
+func (s *licenseService) concurrentHeartbeatResponse(ctx context.Context, licenseKey string) (*models.HeartbeatResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	license, err := s.licenseRepo.GetLicenseByKey(ctx, licenseKey)
+	if err != nil {
+		if errors.Is(err, repository.ErrLicenseNotFound) {
+			return nil, i18n.NewI18nError("300006", lang) // 许可证不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	switch license.Status {
+	case "revoked":
+		return nil, i18n.NewI18nError("300007", lang) // 许可证已被撤销
+	case models.LicenseStatusTransferred:
+		return s.transferredHeartbeatResponse(ctx, license), nil
+	}
+	return &models.HeartbeatResponse{
+		Status:            license.Status,
+		HeartbeatInterval: 300,
+	}, nil
+}
+
 // generateLicenseFileContent 生成许可证文件内容
-func (s *licenseService) generateLicenseFileContent(license *models.License, authCode *models.AuthorizationCode) (string, error) {
+func (s *licenseService) generateLicenseFileContent(ctx context.Context, license *models.License, authCode *models.AuthorizationCode) (string, error) {
 	// 构建许可证文件内容
 	licenseFileData := map[string]interface{}{
 		"license_key":           license.LicenseKey,
@@ -603,7 +928,12 @@ func (s *licenseService) generateLicenseFileContent(license *models.License, aut
 		licenseFileData["authorization_code"] = authCode.Code
 		licenseFileData["start_date"] = authCode.StartDate
 		licenseFileData["end_date"] = authCode.EndDate
+		if authCode.MaintenanceUntil != nil {
+			// 维护截止时间随许可证文件签名，客户端据此允许使用此前发布的版本
+			licenseFileData["maintenance_until"] = authCode.MaintenanceUntil
+		}
 		licenseFileData["deployment_type"] = authCode.DeploymentType
+		licenseFileData["deployment_policy"] = buildDeploymentPolicy(authCode, license.ActivationMode, offlineGraceConfig())
 		licenseFileData["max_activations"] = authCode.MaxActivations
 
 		// 包含功能配置等
@@ -618,6 +948,15 @@ func (s *licenseService) generateLicenseFileContent(license *models.License, aut
 		if customParameters := parseJSONField(authCode.CustomParameters); len(customParameters) > 0 {
 			licenseFileData["custom_parameters"] = customParameters
 		}
+
+		// 授权码上标记为写入许可证文件的自定义字段
+		customFields, err := licenseCustomFields(ctx, s.customFields, authCode.ID)
+		if err != nil {
+			return "", err
+		}
+		if len(customFields) > 0 {
+			licenseFileData["custom_fields"] = customFields
+		}
 	}
 
 	// 序列化
@@ -820,3 +1159,16 @@ func (s *licenseService) GetStatsOverview(ctx context.Context) (*models.StatsOve
 
 	return &stats, nil
 }
+
+// checkActivationSeats 检查激活能否再占用一个席位
+// 从客户席位池取用席位时以席位池的剩余席位为上限，不受授权码自身的最大激活次数限制；
+// seatPoolErr 为席位池检查结果，ErrSeatPoolNotFound 表示未从席位池取用（或客户未设置席位池），此时按最大激活次数限制
+func checkActivationSeats(activeCount int64, maxActivations int, seatPoolErr error) error {
+	if !errors.Is(seatPoolErr, repository.ErrSeatPoolNotFound) {
+		return seatPoolErr
+	}
+	if activeCount >= int64(maxActivations) {
+		return repository.ErrLicenseNotFound // 使用已有错误，表示激活数量已达上限
+	}
+	return nil
+}
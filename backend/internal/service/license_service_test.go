@@ -0,0 +1,29 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"license-manager/internal/repository"
+)
+
+func TestCheckActivationSeats(t *testing.T) {
+	cases := []struct {
+		name        string
+		activeCount int64
+		max         int
+		seatPoolErr error
+		want        error
+	}{
+		{"below max activations", 2, 3, repository.ErrSeatPoolNotFound, nil},
+		{"max activations reached", 3, 3, repository.ErrSeatPoolNotFound, repository.ErrLicenseNotFound},
+		// 从席位池取用时以席位池剩余席位为上限，不受授权码最大激活次数限制
+		{"seat pool beyond max activations", 5, 3, nil, nil},
+		{"seat pool exhausted", 1, 3, repository.ErrSeatPoolExhausted, repository.ErrSeatPoolExhausted},
+	}
+	for _, c := range cases {
+		if got := checkActivationSeats(c.activeCount, c.max, c.seatPoolErr); !errors.Is(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 转移事务内的业务错误
+var (
+	errLicenseNotTransferable = errors.New("license is not active")
+	errTransferTargetBound    = errors.New("target fingerprint already bound")
+	errTransferTargetRevoked  = errors.New("target fingerprint license is revoked")
+	errTransferLimitReached   = errors.New("seat transfer limit reached")
+)
+
+// TransferLicense 将许可证席位转移到新设备
+// 原许可证标记为 transferred 并签发停用回执，新设备获得同一席位的许可证；
+// customerID 不为空时校验许可证归属（客户自助转移）
+func (s *licenseService) TransferLicense(ctx context.Context, id, customerID string, operatorType models.LicenseTransferOperatorType, operatorID string, req *models.LicenseTransferRequest) (*models.LicenseTransferResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if id == "" || req == nil {
+		return nil, i18n.NewI18nError("900001", lang)
+	}
+
+	license, err := s.licenseRepo.GetLicenseByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrLicenseNotFound) {
+			if customerID != "" {
+				return nil, i18n.NewI18nError("620001", lang) // 设备不存在或无权限访问
+			}
+			return nil, i18n.NewI18nError("300006", lang) // 许可证不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if customerID != "" && license.CustomerID != customerID {
+		return nil, i18n.NewI18nError("620001", lang)
+	}
+	if license.Status != "active" {
+		return nil, i18n.NewI18nError("300201", lang) // 仅激活状态的许可证可以转移
+	}
+	if license.HardwareFingerprint == req.NewHardwareFingerprint {
+		return nil, i18n.NewI18nError("300202", lang) // 新设备与原设备相同
+	}
+
+	authCode := license.AuthorizationCode
+	if authCode == nil {
+		authCode, err = s.licenseRepo.GetAuthorizationCodeByID(ctx, license.AuthorizationCodeID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAuthorizationCodeNotFound) {
+				return nil, i18n.NewI18nError("300001", lang)
+			}
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+	}
+
+	now := time.Now()
+	if authCode.IsLocked {
+		return nil, i18n.NewI18nError("300003", lang) // 授权码已被锁定
+	}
+	if now.After(authCode.EndDate) {
+		return nil, i18n.NewI18nError("300001", lang) // 授权码已过期
+	}
+
+	since := transferPeriodStart(authCode, now)
+
+	transferID := uuid.New().String()
+	receipt, err := s.generateDeactivationReceipt(transferID, license, authCode, req.Reason, now)
+	if err != nil {
+		return nil, i18n.NewI18nError("300009", lang, err.Error())
+	}
+
+	var newLicense *models.License
+	var used int64
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// 锁定原许可证，同一席位的并发转移串行执行（席位当前只有这一个激活的许可证）
+		var locked models.License
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", license.ID, "active").
+			First(&locked).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errLicenseNotTransferable
+			}
+			return err
+		}
+
+		// 按席位统计周期内的转移次数
+		seatID, err := s.transferRepo.GetSeatIDWithTx(ctx, tx, license.ID)
+		if err != nil {
+			return err
+		}
+		used, err = s.transferRepo.CountSeatTransfersWithTx(ctx, tx, seatID, since)
+		if err != nil {
+			return err
+		}
+		if transferLimitReached(authCode, used) {
+			return errTransferLimitReached
+		}
+
+		if err := tx.Model(&models.License{}).
+			Where("id = ?", license.ID).
+			Updates(map[string]interface{}{
+				"status":     models.LicenseStatusTransferred,
+				"updated_at": now,
+			}).Error; err != nil {
+			return err
+		}
+
+		// 新设备若曾在该授权码下激活过，复用原记录以保持指纹唯一
+		var existing *models.License
+		var found models.License
+		err = tx.Where("authorization_code_id = ? AND hardware_fingerprint = ?",
+			authCode.ID, req.NewHardwareFingerprint).First(&found).Error
+		if err == nil {
+			existing = &found
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := checkTransferTarget(existing); err != nil {
+			return err
+		}
+
+		var licenseKey string
+		if existing == nil {
+			if licenseKey, err = s.generateLicenseKey(); err != nil {
+				return err
+			}
+		}
+		newLicense = buildTransferTarget(existing, licenseKey, license, authCode, req, now)
+		if err := tx.Save(newLicense).Error; err != nil {
+			return err
+		}
+
+		record := &models.LicenseTransfer{
+			ID:                  transferID,
+			SeatID:              seatID,
+			AuthorizationCodeID: authCode.ID,
+			CustomerID:          license.CustomerID,
+			FromLicenseID:       license.ID,
+			FromLicenseKey:      license.LicenseKey,
+			FromFingerprint:     license.HardwareFingerprint,
+			ToLicenseID:         newLicense.ID,
+			ToLicenseKey:        newLicense.LicenseKey,
+			ToFingerprint:       newLicense.HardwareFingerprint,
+			OperatorType:        string(operatorType),
+			OperatorID:          operatorID,
+			Reason:              req.Reason,
+			DeactivationReceipt: receipt,
+			CreatedAt:           now,
+		}
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errLicenseNotTransferable):
+			return nil, i18n.NewI18nError("300201", lang)
+		case errors.Is(err, errTransferTargetBound):
+			return nil, i18n.NewI18nError("300204", lang) // 新设备已绑定该授权码
+		case errors.Is(err, errTransferTargetRevoked):
+			return nil, i18n.NewI18nError("300205", lang) // 新设备的许可证已被撤销
+		case errors.Is(err, errTransferLimitReached):
+			return nil, i18n.NewI18nError("300203", lang) // 转移次数已达上限
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	licenseFile, err := s.generateLicenseFileContent(ctx, newLicense, authCode)
+	if err != nil {
+		return nil, i18n.NewI18nError("300009", lang, err.Error())
+	}
+
+	response := &models.LicenseTransferResponse{
+		TransferID:          transferID,
+		OldLicenseID:        license.ID,
+		OldLicenseKey:       license.LicenseKey,
+		NewLicenseID:        newLicense.ID,
+		NewLicenseKey:       newLicense.LicenseKey,
+		NewLicenseFile:      licenseFile,
+		DeactivationReceipt: receipt,
+		TransfersUsed:       used + 1,
+	}
+	if authCode.MaxTransfers != nil {
+		remaining := int64(*authCode.MaxTransfers) - response.TransfersUsed
+		response.TransfersRemaining = &remaining
+	}
+
+	s.logger.Infof("[TransferLicense] 许可证 %s 已转移到新设备，新许可证 %s，操作人 %s:%s",
+		license.ID, newLicense.ID, operatorType, operatorID)
+
+	return response, nil
+}
+
+// transferPeriodStart 转移次数统计周期的起点，未配置周期时统计全部转移记录
+func transferPeriodStart(authCode *models.AuthorizationCode, now time.Time) *time.Time {
+	if authCode.TransferPeriodDays == nil || *authCode.TransferPeriodDays <= 0 {
+		return nil
+	}
+	periodStart := now.AddDate(0, 0, -*authCode.TransferPeriodDays)
+	return &periodStart
+}
+
+// transferLimitReached 席位在统计周期内的转移次数是否已达授权码上限，未配置上限时不限制
+func transferLimitReached(authCode *models.AuthorizationCode, used int64) bool {
+	return authCode.MaxTransfers != nil && used >= int64(*authCode.MaxTransfers)
+}
+
+// checkTransferTarget 校验新设备在该授权码下已有的许可证记录（nil 表示未激活过）
+func checkTransferTarget(existing *models.License) error {
+	if existing == nil {
+		return nil
+	}
+	switch existing.Status {
+	case "active":
+		return errTransferTargetBound
+	case "revoked":
+		// 已撤销的设备不能通过转移恢复
+		return errTransferTargetRevoked
+	}
+	return nil
+}
+
+// buildTransferTarget 构建新设备的许可证：曾激活过的复用原记录以保持指纹唯一，否则以 licenseKey 新建
+func buildTransferTarget(existing *models.License, licenseKey string, from *models.License, authCode *models.AuthorizationCode, req *models.LicenseTransferRequest, now time.Time) *models.License {
+	target := existing
+	if target == nil {
+		target = &models.License{
+			LicenseKey:          licenseKey,
+			AuthorizationCodeID: authCode.ID,
+			CustomerID:          from.CustomerID,
+			HardwareFingerprint: req.NewHardwareFingerprint,
+		}
+	}
+
+	target.Status = "active"
+	target.ActivatedAt = &now
+	target.ActivationMode = effectiveActivationMode(authCode, from.ActivationMode, target.HardwareFingerprint)
+	// 以转移时间作为最近心跳，新设备一直不上线时也能被闲置席位回收
+	target.LastHeartbeat = &now
+	if req.DeviceInfo != nil {
+		if deviceInfoBytes, err := json.Marshal(req.DeviceInfo); err == nil {
+			target.DeviceInfo = models.JSON(deviceInfoBytes)
+		}
+	}
+	return target
+}
+
+// GetLicenseTransferList 查询许可证转移记录
+func (s *licenseService) GetLicenseTransferList(ctx context.Context, req *models.LicenseTransferListRequest) (*models.LicenseTransferListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	transfers, total, err := s.transferRepo.GetList(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	return &models.LicenseTransferListResponse{
+		List:       transfers,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+// generateDeactivationReceipt 生成原设备的停用回执，与许可证文件使用相同的RSA签名格式，
+// 客户端可用公钥离线校验后自行停用
+func (s *licenseService) generateDeactivationReceipt(transferID string, license *models.License, authCode *models.AuthorizationCode, reason *string, deactivatedAt time.Time) (string, error) {
+	receiptData := map[string]interface{}{
+		"type":                  "deactivation_receipt",
+		"transfer_id":           transferID,
+		"license_key":           license.LicenseKey,
+		"authorization_code_id": license.AuthorizationCodeID,
+		"authorization_code":    authCode.Code,
+		"hardware_fingerprint":  license.HardwareFingerprint,
+		"status":                models.LicenseStatusTransferred,
+		"deactivated_at":        deactivatedAt.Format(time.RFC3339),
+	}
+	if reason != nil && *reason != "" {
+		receiptData["reason"] = *reason
+	}
+
+	receiptJSON, err := json.Marshal(receiptData)
+	if err != nil {
+		return "", err
+	}
+
+	signed, err := s.signLicenseFile(receiptJSON)
+	if err != nil {
+		return "", err
+	}
+
+	return string(signed), nil
+}
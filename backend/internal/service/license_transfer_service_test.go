@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+)
+
+func TestTransferPeriodStart(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	days := func(n int) *int { return &n }
+
+	if got := transferPeriodStart(&models.AuthorizationCode{}, now); got != nil {
+		t.Fatalf("no period: got %v, want nil", got)
+	}
+	if got := transferPeriodStart(&models.AuthorizationCode{TransferPeriodDays: days(0)}, now); got != nil {
+		t.Fatalf("zero period: got %v, want nil", got)
+	}
+	got := transferPeriodStart(&models.AuthorizationCode{TransferPeriodDays: days(30)}, now)
+	if got == nil || !got.Equal(now.AddDate(0, 0, -30)) {
+		t.Fatalf("30 day period: got %v", got)
+	}
+}
+
+func TestTransferLimitReached(t *testing.T) {
+	max := func(n int) *int { return &n }
+
+	cases := []struct {
+		name string
+		code *models.AuthorizationCode
+		used int64
+		want bool
+	}{
+		{"no limit", &models.AuthorizationCode{}, 100, false},
+		{"below limit", &models.AuthorizationCode{MaxTransfers: max(3)}, 2, false},
+		{"at limit", &models.AuthorizationCode{MaxTransfers: max(3)}, 3, true},
+		{"zero limit", &models.AuthorizationCode{MaxTransfers: max(0)}, 0, true},
+	}
+	for _, c := range cases {
+		if got := transferLimitReached(c.code, c.used); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestCheckTransferTarget(t *testing.T) {
+	cases := []struct {
+		name     string
+		existing *models.License
+		want     error
+	}{
+		{"new device", nil, nil},
+		{"inactive record", &models.License{Status: "inactive"}, nil},
+		{"transferred record", &models.License{Status: models.LicenseStatusTransferred}, nil},
+		{"active record", &models.License{Status: "active"}, errTransferTargetBound},
+		{"revoked record", &models.License{Status: "revoked"}, errTransferTargetRevoked},
+	}
+	for _, c := range cases {
+		if got := checkTransferTarget(c.existing); !errors.Is(got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestBuildTransferTarget(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	authCode := &models.AuthorizationCode{ID: "code-1", DeploymentType: models.DeploymentTypeCloud}
+	from := &models.License{ID: "lic-1", CustomerID: "cust-1", ActivationMode: models.ActivationModeOnline}
+	req := &models.LicenseTransferRequest{
+		NewHardwareFingerprint: "FP-NEW",
+		DeviceInfo:             map[string]interface{}{"name": "new"},
+	}
+
+	t.Run("reuses existing record", func(t *testing.T) {
+		existing := &models.License{
+			ID:                  "lic-old",
+			LicenseKey:          "KEY-OLD",
+			AuthorizationCodeID: "code-1",
+			CustomerID:          "cust-1",
+			HardwareFingerprint: "FP-NEW",
+			Status:              models.LicenseStatusTransferred,
+		}
+		got := buildTransferTarget(existing, "", from, authCode, req, now)
+		if got != existing {
+			t.Fatal("expected existing record to be reused")
+		}
+		if got.ID != "lic-old" || got.LicenseKey != "KEY-OLD" {
+			t.Fatalf("reused record lost identity: %s %s", got.ID, got.LicenseKey)
+		}
+		if got.Status != "active" || got.ActivatedAt == nil || !got.ActivatedAt.Equal(now) {
+			t.Fatalf("reused record not reactivated: %+v", got)
+		}
+		if got.LastHeartbeat == nil || !got.LastHeartbeat.Equal(now) {
+			t.Fatalf("unexpected last heartbeat: %v", got.LastHeartbeat)
+		}
+		if string(got.DeviceInfo) != `{"name":"new"}` {
+			t.Fatalf("unexpected device info: %s", got.DeviceInfo)
+		}
+	})
+
+	t.Run("creates new record", func(t *testing.T) {
+		got := buildTransferTarget(nil, "KEY-NEW", from, authCode, req, now)
+		if got.ID != "" || got.LicenseKey != "KEY-NEW" {
+			t.Fatalf("unexpected identity: %s %s", got.ID, got.LicenseKey)
+		}
+		if got.AuthorizationCodeID != "code-1" || got.CustomerID != "cust-1" || got.HardwareFingerprint != "FP-NEW" {
+			t.Fatalf("unexpected ownership: %+v", got)
+		}
+		if got.Status != "active" || got.ActivationMode != models.ActivationModeOnline {
+			t.Fatalf("unexpected status/mode: %s %s", got.Status, got.ActivationMode)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationService 站内通知服务接口
+type NotificationService interface {
+	// 内部调用：发送通知
+	Notify(ctx context.Context, recipientType models.NotificationRecipientType, recipientID, notificationType string, payload map[string]interface{}) error
+
+	// 用户端接口
+	GetCuNotificationList(ctx context.Context, customerID string, req *models.NotificationListRequest) (*models.NotificationListResponse, error)
+	MarkCuNotificationRead(ctx context.Context, customerID, id string) error
+
+	// 管理端接口
+	GetUserNotificationList(ctx context.Context, userID string, req *models.NotificationListRequest) (*models.NotificationListResponse, error)
+	MarkUserNotificationRead(ctx context.Context, userID, id string) error
+}
+
+type notificationService struct {
+	repo repository.NotificationRepository
+}
+
+// NewNotificationService 创建站内通知服务
+func NewNotificationService(repo repository.NotificationRepository) NotificationService {
+	return &notificationService{repo: repo}
+}
+
+// BuildNotification 构建通知实体，供需要在事务中写入通知的模块复用
+func BuildNotification(recipientType models.NotificationRecipientType, recipientID, notificationType string, payload map[string]interface{}) (*models.Notification, error) {
+	notification := &models.Notification{
+		RecipientType: string(recipientType),
+		RecipientID:   recipientID,
+		Type:          notificationType,
+	}
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		notification.Payload = models.JSON(payloadBytes)
+	}
+	return notification, nil
+}
+
+func (s *notificationService) Notify(ctx context.Context, recipientType models.NotificationRecipientType, recipientID, notificationType string, payload map[string]interface{}) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	notification, err := BuildNotification(recipientType, recipientID, notificationType, payload)
+	if err != nil {
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if err := s.repo.Create(ctx, notification); err != nil {
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return nil
+}
+
+func (s *notificationService) GetCuNotificationList(ctx context.Context, customerID string, req *models.NotificationListRequest) (*models.NotificationListResponse, error) {
+	return s.getNotificationList(ctx, models.NotificationRecipientCustomer, customerID, req)
+}
+
+func (s *notificationService) MarkCuNotificationRead(ctx context.Context, customerID, id string) error {
+	return s.markNotificationRead(ctx, models.NotificationRecipientCustomer, customerID, id)
+}
+
+func (s *notificationService) GetUserNotificationList(ctx context.Context, userID string, req *models.NotificationListRequest) (*models.NotificationListResponse, error) {
+	return s.getNotificationList(ctx, models.NotificationRecipientUser, userID, req)
+}
+
+func (s *notificationService) MarkUserNotificationRead(ctx context.Context, userID, id string) error {
+	return s.markNotificationRead(ctx, models.NotificationRecipientUser, userID, id)
+}
+
+func (s *notificationService) getNotificationList(ctx context.Context, recipient models.NotificationRecipientType, recipientID string, req *models.NotificationListRequest) (*models.NotificationListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	recipientType := string(recipient)
+	notifications, total, err := s.repo.GetList(ctx, recipientType, recipientID, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	unreadCount, err := s.repo.CountUnread(ctx, recipientType, recipientID)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	for i := range notifications {
+		notifications[i].TypeDisplay = i18n.GetEnumMessage("notification_type", notifications[i].Type, lang)
+	}
+
+	return &models.NotificationListResponse{
+		List:        notifications,
+		Total:       total,
+		UnreadCount: unreadCount,
+		Page:        req.Page,
+		PageSize:    req.PageSize,
+	}, nil
+}
+
+func (s *notificationService) markNotificationRead(ctx context.Context, recipient models.NotificationRecipientType, recipientID, id string) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	notification, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return i18n.NewI18nError("630001", lang)
+		}
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	// 只能操作发给自己的通知
+	if notification.RecipientType != string(recipient) || notification.RecipientID != recipientID {
+		return i18n.NewI18nError("630001", lang)
+	}
+
+	if notification.ReadAt != nil {
+		return nil
+	}
+
+	if err := s.repo.MarkRead(ctx, id, time.Now()); err != nil {
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return nil
+}
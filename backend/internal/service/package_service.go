@@ -55,6 +55,9 @@ func (s *packageService) CreatePackage(ctx context.Context, req *models.PackageC
 		Status:              req.Status,
 		SortOrder:           req.SortOrder,
 		Remark:              req.Remark,
+		DormantReclaimDays:  req.DormantReclaimDays,
+		CodeFormat:          req.CodeFormat,
+		MaintenanceMonths:   req.MaintenanceMonths,
 	}
 
 	if err := s.repo.Create(pkg); err != nil {
@@ -103,6 +106,30 @@ func (s *packageService) UpdatePackage(ctx context.Context, id string, req *mode
 	if req.Remark != "" {
 		pkg.Remark = req.Remark
 	}
+	if req.DormantReclaimDays != nil {
+		// 传0表示关闭闲置席位回收
+		if *req.DormantReclaimDays == 0 {
+			pkg.DormantReclaimDays = nil
+		} else {
+			pkg.DormantReclaimDays = req.DormantReclaimDays
+		}
+	}
+	if req.CodeFormat != nil {
+		// 传default表示使用系统配置
+		if *req.CodeFormat == "default" {
+			pkg.CodeFormat = ""
+		} else {
+			pkg.CodeFormat = *req.CodeFormat
+		}
+	}
+	if req.MaintenanceMonths != nil {
+		// 传0表示不限制版本
+		if *req.MaintenanceMonths == 0 {
+			pkg.MaintenanceMonths = nil
+		} else {
+			pkg.MaintenanceMonths = req.MaintenanceMonths
+		}
+	}
 
 	pkg.UpdatedAt = time.Now()
 
@@ -216,15 +243,16 @@ func (s *packageService) toCuPackageResponse(pkg *models.Package) *models.CuPack
 	}
 
 	return &models.CuPackageResponse{
-		ID:               pkg.ID,
-		Name:             pkg.Name,
-		Type:             pkg.Type,
-		Price:            pkg.Price,
-		DisplayPrice:     displayPrice,
-		PriceDescription: pkg.PriceDescription,
-		MaxDevices:       maxDevices,
-		Description:      pkg.Description,
-		Features:         pkg.Features,
-		Details:          details,
+		ID:                pkg.ID,
+		Name:              pkg.Name,
+		Type:              pkg.Type,
+		Price:             pkg.Price,
+		DisplayPrice:      displayPrice,
+		PriceDescription:  pkg.PriceDescription,
+		MaxDevices:        maxDevices,
+		Description:       pkg.Description,
+		Features:          pkg.Features,
+		Details:           details,
+		MaintenanceMonths: pkg.MaintenanceMonths,
 	}
 }
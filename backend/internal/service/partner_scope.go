@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+
+	"gorm.io/gorm"
+)
+
+// 经销商相关错误码
+const (
+	errCodePartnerNotFound      = "200013" // 经销商账号不存在
+	errCodePartnerQuotaExceeded = "200014" // 经销商席位额度不足
+	errCodePartnerAccountExists = "200015" // 用户名或邮箱已存在
+)
+
+// customerInPartnerScope 客户是否在经销商的数据范围内，partnerID 为空（非经销商账号）时不限定
+func customerInPartnerScope(partnerID string, customer *models.Customer) bool {
+	return partnerID == "" || (customer.PartnerID != nil && *customer.PartnerID == partnerID)
+}
+
+// restrictCustomerIDs 将列表的客户筛选条件限定在经销商名下客户范围内
+// requested 为按层级展开的客户范围，customerID 为单个客户筛选，均未指定时返回名下全部客户
+func restrictCustomerIDs(owned, requested []string, customerID string) []string {
+	allowed := make(map[string]bool, len(owned))
+	for _, id := range owned {
+		allowed[id] = true
+	}
+
+	candidates := owned
+	if requested != nil {
+		candidates = requested
+	} else if customerID != "" {
+		candidates = []string{customerID}
+	}
+
+	result := []string{}
+	for _, id := range candidates {
+		if allowed[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// scopeCustomerFilter 经销商账号查询列表时限定客户ID范围，非经销商账号原样返回
+func scopeCustomerFilter(ctx context.Context, partnerRepo repository.PartnerRepository, requested []string, customerID string) ([]string, error) {
+	partnerID := pkgcontext.GetPartnerIDFromContext(ctx)
+	if partnerID == "" {
+		return requested, nil
+	}
+	owned, err := partnerRepo.GetPartnerCustomerIDs(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	return restrictCustomerIDs(owned, requested, customerID), nil
+}
+
+// customerIDInScope 客户是否存在且在当前账号的数据范围内
+func customerIDInScope(ctx context.Context, customerRepo repository.CustomerRepository, customerID string) (bool, error) {
+	partnerID := pkgcontext.GetPartnerIDFromContext(ctx)
+	if partnerID == "" {
+		return true, nil
+	}
+	customer, err := customerRepo.GetCustomerByID(ctx, customerID)
+	if err != nil {
+		if repository.IsCustomerNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return customerInPartnerScope(partnerID, customer), nil
+}
+
+// saveWithPartnerSeatQuota 在同一事务中校验经销商席位额度并写入授权码，非经销商账号或未新增席位时不校验
+// 额度校验锁定经销商账号，同一经销商的并发新增席位串行执行，按授权码写入后的席位数计算
+func (s *authorizationCodeService) saveWithPartnerSeatQuota(ctx context.Context, authCode *models.AuthorizationCode, addsSeats bool, write func(tx interface{}) error) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	tx, ok := s.authCodeRepo.BeginTransaction(ctx).(*gorm.DB)
+	if !ok {
+		return i18n.NewI18nError("900004", lang, repository.ErrInvalidTransaction.Error())
+	}
+	if tx.Error != nil {
+		return i18n.NewI18nError("900004", lang, tx.Error.Error())
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if partnerID := pkgcontext.GetPartnerIDFromContext(ctx); partnerID != "" && addsSeats {
+		if err := s.partnerRepo.CheckSeatQuotaWithTx(ctx, tx, partnerID, authCode.ID, authCode.MaxActivations); err != nil {
+			tx.Rollback()
+			if errors.Is(err, repository.ErrPartnerQuotaExceeded) {
+				return i18n.NewI18nError(errCodePartnerQuotaExceeded, lang)
+			}
+			return i18n.NewI18nError("900004", lang, err.Error())
+		}
+	}
+	if err := write(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return nil
+}
+
+// resolvePartner 确定客户的所属经销商：经销商账号固定为自身，管理员指定时校验经销商存在
+func (s *customerService) resolvePartner(ctx context.Context, partnerID *string) (*string, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if current := pkgcontext.GetPartnerIDFromContext(ctx); current != "" {
+		return &current, nil
+	}
+	if partnerID == nil || *partnerID == "" {
+		return nil, nil
+	}
+	if _, err := s.partnerRepo.GetPartnerByID(ctx, *partnerID); err != nil {
+		if errors.Is(err, repository.ErrPartnerNotFound) {
+			return nil, i18n.NewI18nError(errCodePartnerNotFound, lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return partnerID, nil
+}
+
+// ensureAuthorizationCodeInScope 经销商账号只能访问名下客户的授权码，范围外按授权码不存在处理
+func (s *authorizationCodeService) ensureAuthorizationCodeInScope(ctx context.Context, authCode *models.AuthorizationCode) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	inScope, err := customerIDInScope(ctx, s.customerRepo, authCode.CustomerID)
+	if err != nil {
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if !inScope {
+		return i18n.NewI18nError("300001", lang)
+	}
+	return nil
+}
+
+// ensureLicenseInScope 经销商账号只能访问名下客户的许可证，范围外按许可证不存在处理
+func (s *licenseService) ensureLicenseInScope(ctx context.Context, license *models.License) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	inScope, err := customerIDInScope(ctx, s.customerRepo, license.CustomerID)
+	if err != nil {
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if !inScope {
+		return i18n.NewI18nError("300006", lang)
+	}
+	return nil
+}
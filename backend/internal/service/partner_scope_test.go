@@ -0,0 +1,67 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"license-manager/internal/models"
+)
+
+func TestCustomerInPartnerScope(t *testing.T) {
+	partnerA := "partner-a"
+	direct := &models.Customer{}
+	owned := &models.Customer{PartnerID: &partnerA}
+
+	if !customerInPartnerScope("", direct) || !customerInPartnerScope("", owned) {
+		t.Error("staff accounts should see every customer")
+	}
+	if !customerInPartnerScope("partner-a", owned) {
+		t.Error("partner should see its own customer")
+	}
+	if customerInPartnerScope("partner-b", owned) {
+		t.Error("partner should not see another partner's customer")
+	}
+	if customerInPartnerScope("partner-a", direct) {
+		t.Error("partner should not see direct customers")
+	}
+}
+
+func TestRestrictCustomerIDs(t *testing.T) {
+	owned := []string{"c1", "c2", "c3"}
+
+	cases := []struct {
+		name       string
+		requested  []string
+		customerID string
+		want       []string
+	}{
+		{"no filter", nil, "", []string{"c1", "c2", "c3"}},
+		{"own customer", nil, "c2", []string{"c2"}},
+		{"foreign customer", nil, "x9", []string{}},
+		{"subtree", []string{"c1", "x9", "c3"}, "c1", []string{"c1", "c3"}},
+		{"empty subtree", []string{}, "", []string{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := restrictCustomerIDs(owned, tc.requested, tc.customerID)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("restrictCustomerIDs = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewPartnerItemAvailableSeats(t *testing.T) {
+	partner := &models.User{ID: "p1", SeatQuota: 10}
+
+	item := newPartnerItem(partner, &models.PartnerUsage{PartnerID: "p1", UsedSeats: 4, CustomerCount: 2})
+	if item.AvailableSeats != 6 || item.UsedSeats != 4 || item.CustomerCount != 2 {
+		t.Errorf("unexpected usage: %+v", item)
+	}
+
+	// 额度下调到低于已分配席位时剩余席位为0
+	item = newPartnerItem(partner, &models.PartnerUsage{PartnerID: "p1", UsedSeats: 12})
+	if item.AvailableSeats != 0 {
+		t.Errorf("AvailableSeats = %d, want 0", item.AvailableSeats)
+	}
+}
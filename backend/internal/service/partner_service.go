@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+	"license-manager/pkg/utils"
+)
+
+// PartnerService 经销商账号管理服务接口
+type PartnerService interface {
+	CreatePartner(ctx context.Context, req *models.PartnerCreateRequest) (*models.PartnerItem, error)
+	UpdatePartner(ctx context.Context, id string, req *models.PartnerUpdateRequest) (*models.PartnerItem, error)
+	GetPartnerList(ctx context.Context, req *models.PartnerListRequest) (*models.PartnerListResponse, error)
+	GetPartner(ctx context.Context, id string) (*models.PartnerItem, error)
+	// GetPartnerOrders 经销商查看名下客户的订单及支付情况
+	GetPartnerOrders(ctx context.Context, req *models.PartnerOrderListRequest) (*models.PartnerOrderListResponse, error)
+}
+
+type partnerService struct {
+	partnerRepo repository.PartnerRepository
+}
+
+// NewPartnerService 创建经销商账号管理服务
+func NewPartnerService(partnerRepo repository.PartnerRepository) PartnerService {
+	return &partnerService{partnerRepo: partnerRepo}
+}
+
+// CreatePartner 创建经销商账号
+func (s *partnerService) CreatePartner(ctx context.Context, req *models.PartnerCreateRequest) (*models.PartnerItem, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	username := strings.TrimSpace(req.Username)
+	email := strings.TrimSpace(req.Email)
+	exists, err := s.partnerRepo.ExistsUsernameOrEmail(ctx, username, email, "")
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if exists {
+		return nil, i18n.NewI18nError(errCodePartnerAccountExists, lang)
+	}
+
+	passwordHash, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	partner := &models.User{
+		Username:     username,
+		Email:        email,
+		PasswordHash: passwordHash,
+		FullName:     req.FullName,
+		Phone:        req.Phone,
+		Role:         models.UserRolePartner,
+		Status:       "active",
+		SeatQuota:    req.SeatQuota,
+	}
+	if err := s.partnerRepo.CreatePartner(ctx, partner); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	return s.buildPartnerItem(ctx, partner)
+}
+
+// UpdatePartner 更新经销商账号信息、席位额度或状态
+func (s *partnerService) UpdatePartner(ctx context.Context, id string, req *models.PartnerUpdateRequest) (*models.PartnerItem, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	partner, err := s.getPartner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		exists, err := s.partnerRepo.ExistsUsernameOrEmail(ctx, partner.Username, email, partner.ID)
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		if exists {
+			return nil, i18n.NewI18nError(errCodePartnerAccountExists, lang)
+		}
+		partner.Email = email
+	}
+	if req.FullName != nil {
+		partner.FullName = *req.FullName
+	}
+	if req.Phone != nil {
+		partner.Phone = req.Phone
+	}
+	if req.SeatQuota != nil {
+		// 额度可以下调到低于已分配席位，此后经销商无法再新增席位
+		partner.SeatQuota = *req.SeatQuota
+	}
+	if req.Status != nil {
+		partner.Status = *req.Status
+	}
+
+	if err := s.partnerRepo.UpdatePartner(ctx, partner); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	return s.buildPartnerItem(ctx, partner)
+}
+
+// GetPartnerList 查询经销商列表及额度使用情况
+func (s *partnerService) GetPartnerList(ctx context.Context, req *models.PartnerListRequest) (*models.PartnerListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	partners, total, err := s.partnerRepo.GetPartnerList(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	partnerIDs := make([]string, 0, len(partners))
+	for _, partner := range partners {
+		partnerIDs = append(partnerIDs, partner.ID)
+	}
+	usage, err := s.partnerRepo.GetPartnerUsage(ctx, partnerIDs)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	list := make([]*models.PartnerItem, 0, len(partners))
+	for _, partner := range partners {
+		list = append(list, newPartnerItem(partner, usage[partner.ID]))
+	}
+
+	return &models.PartnerListResponse{
+		List:       list,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+// GetPartnerOrders 经销商查看名下客户的订单及支付情况，非经销商账号返回经销商不存在
+func (s *partnerService) GetPartnerOrders(ctx context.Context, req *models.PartnerOrderListRequest) (*models.PartnerOrderListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	partnerID := pkgcontext.GetPartnerIDFromContext(ctx)
+	if partnerID == "" {
+		return nil, i18n.NewI18nError(errCodePartnerNotFound, lang)
+	}
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	orders, total, err := s.partnerRepo.GetPartnerOrders(ctx, partnerID, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	return &models.PartnerOrderListResponse{
+		List:       orders,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+// GetPartner 获取经销商账号及额度使用情况（经销商查看自身额度时使用）
+func (s *partnerService) GetPartner(ctx context.Context, id string) (*models.PartnerItem, error) {
+	partner, err := s.getPartner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildPartnerItem(ctx, partner)
+}
+
+func (s *partnerService) getPartner(ctx context.Context, id string) (*models.User, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	partner, err := s.partnerRepo.GetPartnerByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPartnerNotFound) {
+			return nil, i18n.NewI18nError(errCodePartnerNotFound, lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return partner, nil
+}
+
+func (s *partnerService) buildPartnerItem(ctx context.Context, partner *models.User) (*models.PartnerItem, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	usage, err := s.partnerRepo.GetPartnerUsage(ctx, []string{partner.ID})
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return newPartnerItem(partner, usage[partner.ID]), nil
+}
+
+// newPartnerItem 组装经销商账号及额度使用情况，剩余席位最小为0
+func newPartnerItem(partner *models.User, usage *models.PartnerUsage) *models.PartnerItem {
+	item := &models.PartnerItem{
+		ID:          partner.ID,
+		Username:    partner.Username,
+		Email:       partner.Email,
+		FullName:    partner.FullName,
+		Phone:       partner.Phone,
+		Status:      partner.Status,
+		SeatQuota:   partner.SeatQuota,
+		LastLoginAt: partner.LastLoginAt,
+		CreatedAt:   partner.CreatedAt,
+	}
+	if usage != nil {
+		item.UsedSeats = usage.UsedSeats
+		item.CustomerCount = usage.CustomerCount
+	}
+	if available := int64(partner.SeatQuota) - item.UsedSeats; available > 0 {
+		item.AvailableSeats = available
+	}
+	return item
+}
@@ -293,14 +293,28 @@ func (s *paymentService) ProcessAlipayCallback(ctx context.Context, values url.V
 			return nil
 		}
 
-		// 生成授权码并写入授权码表 & 订单（沿用当前 free order 的规则）
-		authCode, err := utils.GenerateLegacyAuthorizationCode(order.CustomerID)
-		if err != nil {
-			return err
+		// 订单的套餐（下单后被删除的套餐仍按其配置生成授权码），套餐不存在时不生成授权码，回滚后等待支付平台重试通知
+		var pkg models.Package
+		if err := tx.Unscoped().Where("id = ?", order.PackageID).First(&pkg).Error; err != nil {
+			return fmt.Errorf("failed to load package %s of order %s: %w", order.PackageID, order.ID, err)
 		}
+		pkgEntity := &pkg
 
 		now := time.Now()
 
+		// 续订维护：顺延已有授权码的维护截止时间，不生成新授权码
+		if order.OrderType == models.CuOrderTypeMaintenanceRenewal {
+			renewedCode, err := renewOrderMaintenance(tx, &order, pkgEntity, now)
+			if err != nil {
+				return err
+			}
+			return tx.Model(&models.CuOrder{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+				"status":             "paid",
+				"authorization_code": renewedCode.Code,
+				"updated_at":         now,
+			}).Error
+		}
+
 		var startDate, endDate time.Time
 		var expiredAt *time.Time
 		if order.PackageID == "trial" {
@@ -314,6 +328,17 @@ func (s *paymentService) ProcessAlipayCallback(ctx context.Context, values url.V
 			expiredAt = nil
 		}
 
+		// 生成授权码并写入授权码表 & 订单（按套餐/系统配置确定授权码格式）
+		codeFormat := resolveCodeFormat("", pkgEntity.CodeFormat, nil)
+		authCode, err := generateAuthorizationCodeWithFormat(codeFormat, utils.AuthorizationCodeParams{
+			CustomerID: order.CustomerID,
+			Edition:    pkgEntity.Type,
+			ExpiresAt:  endDate,
+		})
+		if err != nil {
+			return err
+		}
+
 		var featureConfigMap, usageLimitsMap map[string]interface{}
 		switch order.PackageID {
 		case "basic":
@@ -363,7 +388,11 @@ func (s *paymentService) ProcessAlipayCallback(ctx context.Context, values url.V
 			IsLocked:       false,
 			FeatureConfig:  featureConfig,
 			UsageLimits:    usageLimits,
+			CodeFormat:     codeFormat,
 		}
+		// 继承套餐的闲置席位回收策略和维护期
+		authCodeEntity.DormantReclaimDays = pkgEntity.DormantReclaimDays
+		authCodeEntity.MaintenanceUntil = packageMaintenanceUntil(pkgEntity, startDate)
 		if err := tx.Create(authCodeEntity).Error; err != nil {
 			return err
 		}
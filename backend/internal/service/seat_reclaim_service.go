@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SeatReclaimService 闲置席位回收服务接口
+type SeatReclaimService interface {
+	// ReclaimDormantSeats 按授权码的回收策略释放长时间无心跳的许可证席位
+	ReclaimDormantSeats(ctx context.Context, trigger models.SeatReclaimTrigger) (*models.SeatReclaimRunResponse, error)
+	// GetReclamationList 查询席位回收记录
+	GetReclamationList(ctx context.Context, req *models.SeatReclamationListRequest) (*models.SeatReclamationListResponse, error)
+}
+
+type seatReclaimService struct {
+	repo   repository.SeatReclaimRepository
+	logger *logrus.Logger
+}
+
+// NewSeatReclaimService 创建闲置席位回收服务
+func NewSeatReclaimService(repo repository.SeatReclaimRepository, logger *logrus.Logger) SeatReclaimService {
+	return &seatReclaimService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// ReclaimDormantSeats 释放超过授权码回收策略天数未心跳的许可证席位，逐个在事务中回收并通知客户
+// 扫描后已恢复心跳或已被解绑的许可证不会被回收
+func (s *seatReclaimService) ReclaimDormantSeats(ctx context.Context, trigger models.SeatReclaimTrigger) (*models.SeatReclaimRunResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	now := time.Now()
+	licenses, err := s.repo.FindDormantLicenses(ctx, now)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	result := &models.SeatReclaimRunResponse{Scanned: len(licenses)}
+	for _, license := range licenses {
+		// 仓储已按回收策略筛选，这里按同一口径再次确认，未配置策略或未到期的许可证不回收
+		if !isDormantLicense(license, now) {
+			continue
+		}
+
+		record := &models.SeatReclamation{
+			LicenseID:           license.ID,
+			LicenseKey:          license.LicenseKey,
+			AuthorizationCodeID: license.AuthorizationCodeID,
+			CustomerID:          license.CustomerID,
+			HardwareFingerprint: license.HardwareFingerprint,
+			LastHeartbeat:       license.LastHeartbeat,
+			PolicyDays:          license.DormantReclaimDays,
+			TriggerType:         string(trigger),
+			ReclaimedAt:         now,
+		}
+
+		payload := map[string]interface{}{
+			"license_id":            license.ID,
+			"authorization_code_id": license.AuthorizationCodeID,
+			"authorization_code":    license.AuthorizationCode,
+			"device_name":           deviceNameFromInfo(license.DeviceInfo),
+			"policy_days":           license.DormantReclaimDays,
+		}
+		if license.LastHeartbeat != nil {
+			payload["last_heartbeat"] = license.LastHeartbeat.Format(time.RFC3339)
+		}
+		notification, err := BuildNotification(models.NotificationRecipientCustomer, license.CustomerID, models.NotificationTypeSeatReclaimed, payload)
+		if err != nil {
+			result.Failed++
+			s.logger.WithError(err).WithField("license_id", license.ID).Error("构建席位回收通知失败")
+			continue
+		}
+
+		reclaimed, err := s.repo.ReclaimLicense(ctx, record, notification)
+		if err != nil {
+			result.Failed++
+			s.logger.WithError(err).WithField("license_id", license.ID).Error("回收闲置席位失败")
+			continue
+		}
+		if reclaimed {
+			result.Reclaimed++
+		}
+	}
+
+	if result.Scanned > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"trigger":   trigger,
+			"scanned":   result.Scanned,
+			"reclaimed": result.Reclaimed,
+			"failed":    result.Failed,
+		}).Info("闲置席位回收完成")
+	}
+
+	return result, nil
+}
+
+// GetReclamationList 分页查询席位回收记录
+func (s *seatReclaimService) GetReclamationList(ctx context.Context, req *models.SeatReclamationListRequest) (*models.SeatReclamationListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	items, total, err := s.repo.GetReclamationList(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	list := make([]models.SeatReclamationListItem, 0, len(items))
+	for _, item := range items {
+		item.TriggerTypeDisplay = i18n.GetEnumMessage("seat_reclaim_trigger", item.TriggerType, lang)
+		list = append(list, *item)
+	}
+
+	return &models.SeatReclamationListResponse{
+		List:       list,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+// isDormantLicense 许可证是否已超过授权码回收策略的天数未心跳
+// 未配置回收策略（天数为0）或从未上报过心跳的许可证不参与回收
+func isDormantLicense(license *models.DormantLicense, now time.Time) bool {
+	if license.DormantReclaimDays <= 0 || license.LastHeartbeat == nil {
+		return false
+	}
+	return license.LastHeartbeat.Before(now.AddDate(0, 0, -license.DormantReclaimDays))
+}
+
+// deviceNameFromInfo 从设备信息JSON中提取设备名称
+func deviceNameFromInfo(deviceInfo models.JSON) string {
+	info := parseJSONField(deviceInfo)
+	if name, ok := info["name"].(string); ok {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestIsDormantLicense(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		heartbeat := now.AddDate(0, 0, -days)
+		return &heartbeat
+	}
+
+	cases := []struct {
+		name    string
+		license *models.DormantLicense
+		want    bool
+	}{
+		{"past policy days", &models.DormantLicense{DormantReclaimDays: 30, LastHeartbeat: at(31)}, true},
+		{"within policy days", &models.DormantLicense{DormantReclaimDays: 30, LastHeartbeat: at(29)}, false},
+		{"exactly policy days", &models.DormantLicense{DormantReclaimDays: 30, LastHeartbeat: at(30)}, false},
+		{"no policy", &models.DormantLicense{DormantReclaimDays: 0, LastHeartbeat: at(365)}, false},
+		{"never sent heartbeat", &models.DormantLicense{DormantReclaimDays: 30}, false},
+	}
+	for _, c := range cases {
+		if got := isDormantLicense(c.license, now); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+// stubSeatReclaimRepository 返回固定的闲置许可证，ReclaimLicense 按许可证ID决定是否实际回收
+type stubSeatReclaimRepository struct {
+	licenses  []*models.DormantLicense
+	active    map[string]bool
+	reclaimed []*models.SeatReclamation
+}
+
+func (r *stubSeatReclaimRepository) FindDormantLicenses(ctx context.Context, now time.Time) ([]*models.DormantLicense, error) {
+	return r.licenses, nil
+}
+
+func (r *stubSeatReclaimRepository) ReclaimLicense(ctx context.Context, record *models.SeatReclamation, notification *models.Notification) (bool, error) {
+	if !r.active[record.LicenseID] {
+		return false, nil
+	}
+	r.reclaimed = append(r.reclaimed, record)
+	return true, nil
+}
+
+func (r *stubSeatReclaimRepository) GetReclamationList(ctx context.Context, req *models.SeatReclamationListRequest) ([]*models.SeatReclamationListItem, int64, error) {
+	return nil, 0, nil
+}
+
+func TestReclaimDormantSeats(t *testing.T) {
+	lastHeartbeat := time.Now().AddDate(0, 0, -60)
+	repo := &stubSeatReclaimRepository{
+		licenses: []*models.DormantLicense{
+			{ID: "dormant", CustomerID: "c1", DormantReclaimDays: 30, LastHeartbeat: &lastHeartbeat},
+			// 扫描后恢复心跳或已被解绑，仓储带状态条件更新时不再回收
+			{ID: "resumed", CustomerID: "c1", DormantReclaimDays: 30, LastHeartbeat: &lastHeartbeat},
+			// 回收策略已被关闭
+			{ID: "no-policy", CustomerID: "c1", DormantReclaimDays: 0, LastHeartbeat: &lastHeartbeat},
+		},
+		active: map[string]bool{"dormant": true, "no-policy": true},
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	service := NewSeatReclaimService(repo, logger)
+
+	result, err := service.ReclaimDormantSeats(context.Background(), models.SeatReclaimTriggerScheduled)
+	if err != nil {
+		t.Fatalf("ReclaimDormantSeats: %v", err)
+	}
+	if result.Scanned != 3 || result.Reclaimed != 1 || result.Failed != 0 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(repo.reclaimed) != 1 {
+		t.Fatalf("unexpected reclamations: %+v", repo.reclaimed)
+	}
+	record := repo.reclaimed[0]
+	if record.LicenseID != "dormant" || record.PolicyDays != 30 || record.TriggerType != string(models.SeatReclaimTriggerScheduled) {
+		t.Fatalf("unexpected reclamation record: %+v", record)
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+	"license-manager/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SoftwareReleaseService 软件版本发布服务接口
+type SoftwareReleaseService interface {
+	CreateRelease(ctx context.Context, operatorID string, req *models.SoftwareReleaseCreateRequest) (*models.SoftwareRelease, error)
+	UpdateRelease(ctx context.Context, id string, req *models.SoftwareReleaseUpdateRequest) (*models.SoftwareRelease, error)
+	DeleteRelease(ctx context.Context, id string) error
+	GetReleaseList(ctx context.Context, req *models.SoftwareReleaseListRequest) (*models.SoftwareReleaseListResponse, error)
+}
+
+type softwareReleaseService struct {
+	releaseRepo repository.SoftwareReleaseRepository
+	logger      *logrus.Logger
+}
+
+// NewSoftwareReleaseService 创建软件版本发布服务
+func NewSoftwareReleaseService(releaseRepo repository.SoftwareReleaseRepository, logger *logrus.Logger) SoftwareReleaseService {
+	return &softwareReleaseService{
+		releaseRepo: releaseRepo,
+		logger:      logger,
+	}
+}
+
+// CreateRelease 登记产品新版本
+func (s *softwareReleaseService) CreateRelease(ctx context.Context, operatorID string, req *models.SoftwareReleaseCreateRequest) (*models.SoftwareRelease, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	release := &models.SoftwareRelease{
+		SoftwareID:   strings.TrimSpace(req.SoftwareID),
+		Version:      strings.TrimSpace(req.Version),
+		Channel:      req.Channel,
+		ReleasedAt:   time.Now(),
+		DownloadURL:  req.DownloadURL,
+		Checksum:     strings.TrimSpace(req.Checksum),
+		ReleaseNotes: req.ReleaseNotes,
+		IsPublished:  true,
+		CreatedBy:    operatorID,
+	}
+	if release.SoftwareID == "" || release.Version == "" {
+		return nil, i18n.NewI18nError("900001", lang, "software_id and version are required")
+	}
+	if release.Channel == "" {
+		release.Channel = models.ReleaseChannelStable
+	}
+	if req.ReleasedAt != nil {
+		release.ReleasedAt = *req.ReleasedAt
+	}
+	if req.IsPublished != nil {
+		release.IsPublished = *req.IsPublished
+	}
+
+	if err := s.releaseRepo.Create(ctx, release); err != nil {
+		if errors.Is(err, repository.ErrSoftwareReleaseExists) {
+			return nil, i18n.NewI18nError("300802", lang) // 版本号已存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	s.fillDisplay(release, lang)
+	return release, nil
+}
+
+// UpdateRelease 更新版本信息，产品和版本号不可修改
+func (s *softwareReleaseService) UpdateRelease(ctx context.Context, id string, req *models.SoftwareReleaseUpdateRequest) (*models.SoftwareRelease, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	release, err := s.getRelease(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Channel != nil {
+		release.Channel = *req.Channel
+	}
+	if req.ReleasedAt != nil {
+		release.ReleasedAt = *req.ReleasedAt
+	}
+	if req.DownloadURL != nil {
+		release.DownloadURL = *req.DownloadURL
+	}
+	if req.Checksum != nil {
+		release.Checksum = strings.TrimSpace(*req.Checksum)
+	}
+	if req.ReleaseNotes != nil {
+		release.ReleaseNotes = req.ReleaseNotes
+	}
+	if req.IsPublished != nil {
+		release.IsPublished = *req.IsPublished
+	}
+
+	if err := s.releaseRepo.Update(ctx, release); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	s.fillDisplay(release, lang)
+	return release, nil
+}
+
+// DeleteRelease 删除版本，删除后不再推送给客户端
+func (s *softwareReleaseService) DeleteRelease(ctx context.Context, id string) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if err := s.releaseRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrSoftwareReleaseNotFound) {
+			return i18n.NewI18nError("300801", lang) // 版本不存在
+		}
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return nil
+}
+
+// GetReleaseList 查询版本列表
+func (s *softwareReleaseService) GetReleaseList(ctx context.Context, req *models.SoftwareReleaseListRequest) (*models.SoftwareReleaseListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	releases, total, err := s.releaseRepo.GetList(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	for _, release := range releases {
+		s.fillDisplay(release, lang)
+	}
+
+	return &models.SoftwareReleaseListResponse{
+		List:       releases,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+func (s *softwareReleaseService) getRelease(ctx context.Context, id string) (*models.SoftwareRelease, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	release, err := s.releaseRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSoftwareReleaseNotFound) {
+			return nil, i18n.NewI18nError("300801", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	return release, nil
+}
+
+func (s *softwareReleaseService) fillDisplay(release *models.SoftwareRelease, lang string) {
+	release.ChannelDisplay = i18n.GetEnumMessage("release_channel", release.Channel, lang)
+}
+
+// releaseChannels 客户端更新渠道可获取的发布渠道，beta 渠道同时包含正式版，未知渠道按 stable 处理
+func releaseChannels(channel string) []string {
+	if channel == models.ReleaseChannelBeta {
+		return []string{models.ReleaseChannelStable, models.ReleaseChannelBeta}
+	}
+	return []string{models.ReleaseChannelStable}
+}
+
+// selectLatestRelease 从已发布版本中选出客户端可安装的最新版本
+// maintenanceUntil 不为空时仅可安装维护期内发布的版本；若维护期后还有更高的版本，
+// requiresRenewal 返回 true 提示客户续订维护
+func selectLatestRelease(releases []*models.SoftwareRelease, maintenanceUntil *time.Time) (latest *models.SoftwareRelease, requiresRenewal bool) {
+	var newest *models.SoftwareRelease
+	for _, release := range releases {
+		if newest == nil || utils.CompareVersions(release.Version, newest.Version) > 0 {
+			newest = release
+		}
+		if maintenanceUntil != nil && release.ReleasedAt.After(*maintenanceUntil) {
+			continue
+		}
+		if latest == nil || utils.CompareVersions(release.Version, latest.Version) > 0 {
+			latest = release
+		}
+	}
+	requiresRenewal = newest != nil && newest != latest
+	return latest, requiresRenewal
+}
+
+// advertiseLatestRelease 为心跳响应填充可安装的最新版本，查询失败只记录日志不影响心跳
+func (s *licenseService) advertiseLatestRelease(ctx context.Context, authCode *models.AuthorizationCode, req *models.HeartbeatRequest, resp *models.HeartbeatResponse, now time.Time) {
+	if s.releaseRepo == nil || authCode.SoftwareID == nil || *authCode.SoftwareID == "" {
+		return
+	}
+
+	releases, err := s.releaseRepo.FindPublished(ctx, *authCode.SoftwareID, releaseChannels(req.UpdateChannel), now)
+	if err != nil {
+		s.logger.WithError(err).WithField("authorization_code_id", authCode.ID).Warn("查询可用版本失败")
+		return
+	}
+
+	latest, requiresRenewal := selectLatestRelease(releases, authCode.MaintenanceUntil)
+	resp.UpgradeRequiresRenewal = requiresRenewal
+	if latest == nil {
+		return
+	}
+
+	resp.LatestVersion = &models.ReleaseAdvertisement{
+		Version:      latest.Version,
+		Channel:      latest.Channel,
+		ReleasedAt:   latest.ReleasedAt,
+		DownloadURL:  latest.DownloadURL,
+		Checksum:     latest.Checksum,
+		ReleaseNotes: latest.ReleaseNotes,
+	}
+	if req.SoftwareVersion != nil && *req.SoftwareVersion != "" {
+		resp.UpdateAvailable = utils.CompareVersions(latest.Version, *req.SoftwareVersion) > 0
+	}
+}
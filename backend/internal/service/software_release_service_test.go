@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+)
+
+func TestSelectLatestRelease(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	releases := []*models.SoftwareRelease{
+		{Version: "1.9.0", ReleasedAt: day(1)},
+		{Version: "1.10.0", ReleasedAt: day(10)},
+		{Version: "2.0.0", ReleasedAt: day(20)},
+	}
+
+	latest, requiresRenewal := selectLatestRelease(releases, nil)
+	if latest == nil || latest.Version != "2.0.0" || requiresRenewal {
+		t.Fatalf("unexpected result without maintenance cap: %v %v", latest, requiresRenewal)
+	}
+
+	until := day(15)
+	latest, requiresRenewal = selectLatestRelease(releases, &until)
+	if latest == nil || latest.Version != "1.10.0" || !requiresRenewal {
+		t.Fatalf("unexpected result with maintenance cap: %v %v", latest, requiresRenewal)
+	}
+
+	expired := day(0)
+	latest, requiresRenewal = selectLatestRelease(releases, &expired)
+	if latest != nil || !requiresRenewal {
+		t.Fatalf("unexpected result with expired maintenance: %v %v", latest, requiresRenewal)
+	}
+
+	if latest, requiresRenewal = selectLatestRelease(nil, &until); latest != nil || requiresRenewal {
+		t.Fatalf("unexpected result without releases: %v %v", latest, requiresRenewal)
+	}
+}
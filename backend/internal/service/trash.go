@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+
+	"github.com/sirupsen/logrus"
+)
+
+// trashPurgeBatchSize 回收站自动清理每批处理的对象数
+const trashPurgeBatchSize = 100
+
+// TrashService 回收站服务接口
+type TrashService interface {
+	ListTrash(ctx context.Context, req *models.TrashListRequest) (*models.TrashListResponse, error)
+	Restore(ctx context.Context, entityType, id string) error
+	Purge(ctx context.Context, entityType, id string) error
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+type trashService struct {
+	trashRepo    repository.TrashRepository
+	entitlements EntitlementInvalidator
+	retention    time.Duration // 回收站保留时长，超过后由定时任务彻底删除，0表示不自动清理
+	logger       *logrus.Logger
+}
+
+// NewTrashService 创建回收站服务
+func NewTrashService(trashRepo repository.TrashRepository, entitlements EntitlementInvalidator, retention time.Duration, logger *logrus.Logger) TrashService {
+	return &trashService{
+		trashRepo:    trashRepo,
+		entitlements: entitlements,
+		retention:    retention,
+		logger:       logger,
+	}
+}
+
+// ListTrash 分页查询回收站中的客户、授权码、套餐、线索和客户端用户，按删除时间倒序
+func (s *trashService) ListTrash(ctx context.Context, req *models.TrashListRequest) (*models.TrashListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	items, total, err := s.trashRepo.ListTrash(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	for _, item := range items {
+		item.EntityTypeDisplay = i18n.GetEnumMessage("trash_entity_type", item.EntityType, lang)
+		item.PurgeAt = trashPurgeAt(item.DeletedAt, s.retention)
+	}
+
+	return &models.TrashListResponse{
+		List:     items,
+		Total:    total,
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}, nil
+}
+
+// Restore 恢复回收站中的对象，客户端用户恢复前校验手机号未被占用，授权码和客户端用户要求所属客户未删除
+func (s *trashService) Restore(ctx context.Context, entityType, id string) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	item, err := s.trashRepo.GetTrashItem(ctx, entityType, id)
+	if err != nil {
+		return trashError(err, lang)
+	}
+	if err := s.trashRepo.Restore(ctx, entityType, id); err != nil {
+		return trashError(err, lang)
+	}
+
+	// 授权码恢复后清除授权检查缓存，避免继续返回删除期间缓存的结果
+	if entityType == models.TrashEntityAuthorizationCode && s.entitlements != nil {
+		authCode := &models.AuthorizationCode{ID: item.EntityID, Code: item.Name}
+		if item.CustomerID != nil {
+			authCode.CustomerID = *item.CustomerID
+		}
+		s.entitlements.InvalidateAuthorizationCode(ctx, authCode)
+	}
+	return nil
+}
+
+// Purge 彻底删除回收站中的对象，仍被其他数据（包括回收站中的数据）引用时不允许删除
+func (s *trashService) Purge(ctx context.Context, entityType, id string) error {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	if err := s.trashRepo.Purge(ctx, entityType, id); err != nil {
+		return trashError(err, lang)
+	}
+	return nil
+}
+
+// PurgeExpired 彻底删除超过保留时长的回收站对象，仍被引用的对象跳过，返回删除数量
+func (s *trashService) PurgeExpired(ctx context.Context) (int, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	before := time.Now().Add(-s.retention)
+	purged, skipped := 0, 0
+	for {
+		// 已删除的对象不再出现在结果中，跳过的对象排在最前面
+		items, err := s.trashRepo.GetExpiredTrash(ctx, before, skipped, trashPurgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		for _, item := range items {
+			err := s.trashRepo.Purge(ctx, item.EntityType, item.EntityID)
+			switch {
+			case err == nil:
+				purged++
+			case errors.Is(err, repository.ErrTrashItemReferenced), errors.Is(err, repository.ErrTrashItemNotFound):
+				skipped++
+			default:
+				return purged, err
+			}
+		}
+		if len(items) < trashPurgeBatchSize {
+			break
+		}
+	}
+
+	if purged > 0 || skipped > 0 {
+		s.logger.Infof("回收站自动清理完成: 彻底删除 %d 个, 仍被引用跳过 %d 个", purged, skipped)
+	}
+	return purged, nil
+}
+
+// trashPurgeAt 计算回收站对象的自动彻底删除时间，未开启自动清理时返回nil
+func trashPurgeAt(deletedAt time.Time, retention time.Duration) *time.Time {
+	if retention <= 0 {
+		return nil
+	}
+	purgeAt := deletedAt.Add(retention)
+	return &purgeAt
+}
+
+// trashError 将回收站仓储错误转换为多语言错误
+func trashError(err error, lang string) error {
+	switch {
+	case errors.Is(err, repository.ErrTrashItemNotFound):
+		return i18n.NewI18nError("680001", lang)
+	case errors.Is(err, repository.ErrTrashItemReferenced):
+		return i18n.NewI18nError("680002", lang)
+	case errors.Is(err, repository.ErrTrashParentDeleted):
+		return i18n.NewI18nError("680003", lang)
+	case errors.Is(err, repository.ErrTrashCuUserPhoneInUse):
+		return i18n.NewI18nError("680004", lang)
+	default:
+		return i18n.NewI18nError("900004", lang, err.Error())
+	}
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"license-manager/internal/repository"
+	"license-manager/pkg/i18n"
+)
+
+func TestTrashPurgeAt(t *testing.T) {
+	deletedAt := time.Date(2026, 3, 1, 10, 30, 0, 0, time.Local)
+
+	purgeAt := trashPurgeAt(deletedAt, 30*24*time.Hour)
+	if purgeAt == nil {
+		t.Fatal("purgeAt = nil, want deleted_at + retention")
+	}
+	if want := time.Date(2026, 3, 31, 10, 30, 0, 0, time.Local); !purgeAt.Equal(want) {
+		t.Errorf("purgeAt = %v, want %v", purgeAt, want)
+	}
+
+	// 未开启自动清理时没有预计删除时间
+	if got := trashPurgeAt(deletedAt, 0); got != nil {
+		t.Errorf("retention 0: purgeAt = %v, want nil", got)
+	}
+}
+
+func TestTrashError(t *testing.T) {
+	cases := []struct {
+		err  error
+		code string
+	}{
+		{repository.ErrTrashItemNotFound, "680001"},
+		{fmt.Errorf("purge: %w", repository.ErrTrashItemReferenced), "680002"},
+		{repository.ErrTrashParentDeleted, "680003"},
+		{repository.ErrTrashCuUserPhoneInUse, "680004"},
+		{errors.New("connection refused"), "900004"},
+	}
+	for _, c := range cases {
+		var i18nErr *i18n.I18nError
+		if err := trashError(c.err, "en-US"); !errors.As(err, &i18nErr) || i18nErr.Code != c.code {
+			t.Errorf("trashError(%v) = %v, want code %s", c.err, err, c.code)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	pkgcontext "license-manager/pkg/context"
+	"license-manager/pkg/i18n"
+	"license-manager/pkg/utils"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// VoucherService 兑换券服务接口
+type VoucherService interface {
+	CreateVouchers(ctx context.Context, operatorID string, req *models.VoucherCreateRequest) (*models.VoucherCreateResponse, error)
+	GetVoucherList(ctx context.Context, req *models.VoucherListRequest) (*models.VoucherListResponse, error)
+	GetRedemptionReport(ctx context.Context, req *models.VoucherRedemptionReportRequest) (*models.VoucherRedemptionReportResponse, error)
+	RedeemVoucher(ctx context.Context, cuUserID, customerID string, req *models.VoucherRedeemRequest) (*models.VoucherRedeemResponse, error)
+}
+
+type voucherService struct {
+	voucherRepo repository.VoucherRepository
+	packageRepo repository.PackageRepository
+	logger      *logrus.Logger
+}
+
+// NewVoucherService 创建兑换券服务
+func NewVoucherService(voucherRepo repository.VoucherRepository, packageRepo repository.PackageRepository, logger *logrus.Logger) VoucherService {
+	return &voucherService{
+		voucherRepo: voucherRepo,
+		packageRepo: packageRepo,
+		logger:      logger,
+	}
+}
+
+// CreateVouchers 批量生成未绑定客户的兑换券
+func (s *voucherService) CreateVouchers(ctx context.Context, operatorID string, req *models.VoucherCreateRequest) (*models.VoucherCreateResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	pkgEntity, err := s.getEnabledPackage(ctx, req.PackageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, i18n.NewI18nError("900001", lang, "expires_at must be in the future")
+	}
+
+	// 兑换码使用带校验位的 Base32 格式，便于用户手工输入
+	generator, err := utils.NewAuthorizationCodeGenerator(utils.AuthorizationCodeFormatBase32, nil)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	vouchers := make([]*models.Voucher, 0, req.Quantity)
+	for i := 0; i < req.Quantity; i++ {
+		code, err := generator.Generate(utils.AuthorizationCodeParams{})
+		if err != nil {
+			return nil, i18n.NewI18nError("900004", lang, err.Error())
+		}
+		vouchers = append(vouchers, &models.Voucher{
+			ID:           uuid.New().String(),
+			Code:         code,
+			Label:        req.Label,
+			PackageID:    pkgEntity.ID,
+			PackageName:  pkgEntity.Name,
+			LicenseCount: req.LicenseCount,
+			CreateOrder:  req.CreateOrder,
+			ExpiresAt:    req.ExpiresAt,
+			Status:       string(models.VoucherStatusUnused),
+			Remark:       req.Remark,
+			CreatedBy:    operatorID,
+		})
+	}
+
+	if err := s.voucherRepo.CreateBatch(ctx, vouchers); err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"package_id": pkgEntity.ID,
+		"quantity":   req.Quantity,
+		"label":      req.Label,
+	}).Info("Vouchers created")
+
+	now := time.Now()
+	for _, voucher := range vouchers {
+		s.fillStatusDisplay(voucher, now, lang)
+	}
+
+	return &models.VoucherCreateResponse{
+		Count:    len(vouchers),
+		Vouchers: vouchers,
+	}, nil
+}
+
+// GetVoucherList 查询兑换券列表
+func (s *voucherService) GetVoucherList(ctx context.Context, req *models.VoucherListRequest) (*models.VoucherListResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+	if req.Code != "" {
+		req.Code = utils.NormalizeAuthorizationCode(req.Code)
+	}
+
+	now := time.Now()
+	vouchers, total, err := s.voucherRepo.GetList(ctx, req, now)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	for _, voucher := range vouchers {
+		s.fillStatusDisplay(voucher, now, lang)
+	}
+
+	return &models.VoucherListResponse{
+		List:       vouchers,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+// GetRedemptionReport 兑换报表：统计汇总 + 兑换记录明细
+func (s *voucherService) GetRedemptionReport(ctx context.Context, req *models.VoucherRedemptionReportRequest) (*models.VoucherRedemptionReportResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	// 设置默认值
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 20
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	summary, err := s.voucherRepo.GetRedemptionSummary(ctx, req, time.Now())
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	items, total, err := s.voucherRepo.GetRedemptionList(ctx, req)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	return &models.VoucherRedemptionReportResponse{
+		Summary:    *summary,
+		List:       items,
+		Total:      total,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(req.PageSize))),
+	}, nil
+}
+
+// RedeemVoucher 客户用户兑换兑换券，在其所属客户下生成授权码（按配置生成零金额订单）
+func (s *voucherService) RedeemVoucher(ctx context.Context, cuUserID, customerID string, req *models.VoucherRedeemRequest) (*models.VoucherRedeemResponse, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	voucher, err := s.voucherRepo.GetByCode(ctx, utils.NormalizeAuthorizationCode(req.Code))
+	if err != nil {
+		if errors.Is(err, repository.ErrVoucherNotFound) {
+			return nil, i18n.NewI18nError("640001", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	now := time.Now()
+	switch voucher.EffectiveStatus(now) {
+	case string(models.VoucherStatusRedeemed):
+		return nil, i18n.NewI18nError("640002", lang)
+	case string(models.VoucherStatusExpired):
+		return nil, i18n.NewI18nError("640003", lang)
+	}
+
+	// 兑换券售出后套餐下架或删除不影响兑换，仍按套餐配置生成授权码
+	pkgEntity, err := s.packageRepo.GetByIDUnscoped(voucher.PackageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, i18n.NewI18nError("600001", lang) // 套餐不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	authCode, expiredAt, err := newPackageAuthorizationCode(pkgEntity, customerID, cuUserID, voucher.LicenseCount, now)
+	if err != nil {
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	var order *models.CuOrder
+	if voucher.CreateOrder {
+		order = &models.CuOrder{
+			ID:                uuid.New().String(),
+			OrderNo:           generateOrderNo(),
+			CustomerID:        customerID,
+			CuUserID:          cuUserID,
+			PackageID:         pkgEntity.ID,
+			PackageName:       pkgEntity.Name,
+			LicenseCount:      voucher.LicenseCount,
+			UnitPrice:         0,
+			DiscountRate:      1,
+			TotalAmount:       0,
+			Status:            "paid", // 兑换券订单为零金额，直接视为已支付
+			AuthorizationCode: &authCode.Code,
+			ExpiredAt:         expiredAt,
+		}
+	}
+
+	voucher.Status = string(models.VoucherStatusRedeemed)
+	voucher.RedeemedAt = &now
+	voucher.RedeemedCustomerID = &customerID
+	voucher.RedeemedCuUserID = &cuUserID
+	voucher.AuthorizationCodeID = &authCode.ID
+	if order != nil {
+		voucher.OrderID = &order.ID
+	}
+
+	if err := s.voucherRepo.Redeem(ctx, voucher, authCode, order); err != nil {
+		if errors.Is(err, repository.ErrVoucherAlreadyRedeemed) {
+			// 并发兑换时仅第一个请求成功
+			return nil, i18n.NewI18nError("640002", lang)
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"voucher_id":  voucher.ID,
+		"customer_id": customerID,
+		"cu_user_id":  cuUserID,
+	}).Info("Voucher redeemed")
+
+	resp := &models.VoucherRedeemResponse{
+		VoucherID:            voucher.ID,
+		PackageName:          voucher.PackageName,
+		LicenseCount:         voucher.LicenseCount,
+		AuthorizationCodeID:  authCode.ID,
+		AuthorizationCode:    authCode.Code,
+		AuthorizationEndDate: authCode.EndDate,
+		RedeemedAt:           now,
+	}
+	if order != nil {
+		resp.OrderID = &order.ID
+		resp.OrderNo = &order.OrderNo
+	}
+	return resp, nil
+}
+
+// getEnabledPackage 获取已上架的套餐
+func (s *voucherService) getEnabledPackage(ctx context.Context, packageID string) (*models.Package, error) {
+	lang := pkgcontext.GetLanguageFromContext(ctx)
+
+	pkgEntity, err := s.packageRepo.GetByID(packageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, i18n.NewI18nError("600001", lang) // 套餐不存在
+		}
+		return nil, i18n.NewI18nError("900004", lang, err.Error())
+	}
+	if pkgEntity.Status != 1 {
+		return nil, i18n.NewI18nError("600002", lang) // 套餐已下架
+	}
+	return pkgEntity, nil
+}
+
+// fillStatusDisplay 按当前时间计算状态并填充多语言显示
+func (s *voucherService) fillStatusDisplay(voucher *models.Voucher, now time.Time, lang string) {
+	voucher.StatusDisplay = i18n.GetEnumMessage("voucher_status", voucher.EffectiveStatus(now), lang)
+}
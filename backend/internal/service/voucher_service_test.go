@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"license-manager/internal/models"
+	"license-manager/internal/repository"
+	"license-manager/pkg/i18n"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// stubVoucherRepository 按兑换码保存兑换券，Redeem 仅允许未兑换的兑换券兑换一次
+type stubVoucherRepository struct {
+	repository.VoucherRepository
+	vouchers map[string]models.Voucher
+	orders   []*models.CuOrder
+}
+
+func (r *stubVoucherRepository) GetByCode(ctx context.Context, code string) (*models.Voucher, error) {
+	voucher, ok := r.vouchers[code]
+	if !ok {
+		return nil, repository.ErrVoucherNotFound
+	}
+	return &voucher, nil
+}
+
+func (r *stubVoucherRepository) Redeem(ctx context.Context, voucher *models.Voucher, authCode *models.AuthorizationCode, order *models.CuOrder) error {
+	if r.vouchers[voucher.Code].Status != string(models.VoucherStatusUnused) {
+		return repository.ErrVoucherAlreadyRedeemed
+	}
+	r.vouchers[voucher.Code] = *voucher
+	if order != nil {
+		r.orders = append(r.orders, order)
+	}
+	return nil
+}
+
+// stubPackageRepository 按ID返回套餐，包括已下架和已删除的套餐
+type stubPackageRepository struct {
+	repository.PackageRepository
+	packages map[string]*models.Package
+}
+
+func (r *stubPackageRepository) GetByIDUnscoped(id string) (*models.Package, error) {
+	pkg, ok := r.packages[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return pkg, nil
+}
+
+func newTestVoucherService(vouchers ...models.Voucher) (*voucherService, *stubVoucherRepository) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	voucherRepo := &stubVoucherRepository{vouchers: map[string]models.Voucher{}}
+	for _, voucher := range vouchers {
+		voucherRepo.vouchers[voucher.Code] = voucher
+	}
+	packageRepo := &stubPackageRepository{packages: map[string]*models.Package{
+		"pkg-enabled":  {ID: "pkg-enabled", Name: "Basic", Type: string(models.PackageTypeBasic), Status: 1},
+		"pkg-disabled": {ID: "pkg-disabled", Name: "Legacy", Type: string(models.PackageTypeBasic), Status: 0},
+		"pkg-deleted": {ID: "pkg-deleted", Name: "Retired", Type: string(models.PackageTypeBasic), Status: 1,
+			DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}},
+	}}
+
+	return &voucherService{voucherRepo: voucherRepo, packageRepo: packageRepo, logger: logger}, voucherRepo
+}
+
+func assertI18nErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var i18nErr *i18n.I18nError
+	if !errors.As(err, &i18nErr) || i18nErr.Code != code {
+		t.Fatalf("err = %v, want code %s", err, code)
+	}
+}
+
+func TestRedeemVoucher(t *testing.T) {
+	ctx := context.Background()
+	unused := func(code, packageID string, createOrder bool) models.Voucher {
+		return models.Voucher{
+			ID:           "voucher-" + code,
+			Code:         code,
+			PackageID:    packageID,
+			PackageName:  "snapshot",
+			LicenseCount: 3,
+			CreateOrder:  createOrder,
+			Status:       string(models.VoucherStatusUnused),
+		}
+	}
+
+	t.Run("redeems once", func(t *testing.T) {
+		svc, repo := newTestVoucherService(unused("CODE1", "pkg-enabled", false))
+
+		resp, err := svc.RedeemVoucher(ctx, "user-1", "cust-1", &models.VoucherRedeemRequest{Code: "CODE1"})
+		if err != nil {
+			t.Fatalf("first redemption: %v", err)
+		}
+		if resp.AuthorizationCode == "" || resp.LicenseCount != 3 || resp.OrderID != nil {
+			t.Fatalf("unexpected response: %+v", resp)
+		}
+		stored := repo.vouchers["CODE1"]
+		if stored.Status != string(models.VoucherStatusRedeemed) || stored.RedeemedCustomerID == nil || *stored.RedeemedCustomerID != "cust-1" {
+			t.Fatalf("voucher not marked redeemed: %+v", stored)
+		}
+
+		_, err = svc.RedeemVoucher(ctx, "user-2", "cust-2", &models.VoucherRedeemRequest{Code: "CODE1"})
+		assertI18nErrorCode(t, err, "640002")
+	})
+
+	t.Run("concurrent redemption", func(t *testing.T) {
+		svc, repo := newTestVoucherService(unused("CODE2", "pkg-enabled", false))
+		// 读取后被其他请求抢先兑换，仓储的条件更新拒绝本次兑换
+		voucher := repo.vouchers["CODE2"]
+		voucher.Status = string(models.VoucherStatusRedeemed)
+		repo.vouchers["CODE2"] = voucher
+		svc.voucherRepo = &racingVoucherRepository{stubVoucherRepository: repo, loaded: unused("CODE2", "pkg-enabled", false)}
+
+		_, err := svc.RedeemVoucher(ctx, "user-1", "cust-1", &models.VoucherRedeemRequest{Code: "CODE2"})
+		assertI18nErrorCode(t, err, "640002")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		voucher := unused("CODE3", "pkg-enabled", false)
+		expiresAt := time.Now().Add(-time.Hour)
+		voucher.ExpiresAt = &expiresAt
+		svc, _ := newTestVoucherService(voucher)
+
+		_, err := svc.RedeemVoucher(ctx, "user-1", "cust-1", &models.VoucherRedeemRequest{Code: "CODE3"})
+		assertI18nErrorCode(t, err, "640003")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		svc, _ := newTestVoucherService()
+
+		_, err := svc.RedeemVoucher(ctx, "user-1", "cust-1", &models.VoucherRedeemRequest{Code: "MISSING"})
+		assertI18nErrorCode(t, err, "640001")
+	})
+
+	t.Run("creates zero amount order", func(t *testing.T) {
+		svc, repo := newTestVoucherService(unused("CODE4", "pkg-enabled", true))
+
+		resp, err := svc.RedeemVoucher(ctx, "user-1", "cust-1", &models.VoucherRedeemRequest{Code: "CODE4"})
+		if err != nil {
+			t.Fatalf("redeem: %v", err)
+		}
+		if len(repo.orders) != 1 || resp.OrderID == nil || *resp.OrderID != repo.orders[0].ID {
+			t.Fatalf("expected one order in response, got %+v", repo.orders)
+		}
+		order := repo.orders[0]
+		if order.Status != "paid" || order.TotalAmount != 0 || order.LicenseCount != 3 || order.CustomerID != "cust-1" {
+			t.Fatalf("unexpected order: %+v", order)
+		}
+		if order.AuthorizationCode == nil || *order.AuthorizationCode != resp.AuthorizationCode {
+			t.Fatalf("order not linked to authorization code: %+v", order)
+		}
+		if stored := repo.vouchers["CODE4"]; stored.OrderID == nil || *stored.OrderID != order.ID {
+			t.Fatalf("voucher not linked to order: %+v", stored)
+		}
+	})
+
+	t.Run("package disabled or deleted after sale", func(t *testing.T) {
+		svc, _ := newTestVoucherService(unused("CODE5", "pkg-disabled", false), unused("CODE6", "pkg-deleted", false))
+
+		for _, code := range []string{"CODE5", "CODE6"} {
+			if _, err := svc.RedeemVoucher(ctx, "user-1", "cust-1", &models.VoucherRedeemRequest{Code: code}); err != nil {
+				t.Fatalf("%s: %v", code, err)
+			}
+		}
+	})
+
+	t.Run("package missing", func(t *testing.T) {
+		svc, _ := newTestVoucherService(unused("CODE7", "pkg-missing", false))
+
+		_, err := svc.RedeemVoucher(ctx, "user-1", "cust-1", &models.VoucherRedeemRequest{Code: "CODE7"})
+		assertI18nErrorCode(t, err, "600001")
+	})
+}
+
+// racingVoucherRepository 读取时返回未兑换的快照，模拟读取后被并发兑换
+type racingVoucherRepository struct {
+	*stubVoucherRepository
+	loaded models.Voucher
+}
+
+func (r *racingVoucherRepository) GetByCode(ctx context.Context, code string) (*models.Voucher, error) {
+	voucher := r.loaded
+	return &voucher, nil
+}
@@ -0,0 +1,37 @@
+-- 闲置席位回收策略：授权码/套餐新增回收天数字段（为空表示不回收）
+ALTER TABLE authorization_codes ADD COLUMN dormant_reclaim_days INT NULL COMMENT '闲置席位回收天数：超过N天无心跳自动释放，为空不回收' AFTER custom_parameters;
+ALTER TABLE packages ADD COLUMN dormant_reclaim_days INT NULL COMMENT '闲置席位回收天数，下单生成授权码时继承' AFTER remark;
+
+-- 闲置席位回收记录表
+CREATE TABLE seat_reclamations (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    license_id VARCHAR(36) NOT NULL COMMENT '许可证ID',
+    license_key VARCHAR(200) NOT NULL COMMENT '许可证密钥',
+    authorization_code_id VARCHAR(36) NOT NULL COMMENT '授权码ID',
+    customer_id VARCHAR(36) NOT NULL COMMENT '客户ID',
+    hardware_fingerprint VARCHAR(200) NOT NULL COMMENT '硬件指纹',
+    last_heartbeat DATETIME(3) COMMENT '回收时的最后心跳时间',
+    policy_days INT NOT NULL COMMENT '生效的回收策略（无心跳天数）',
+    trigger_type VARCHAR(20) NOT NULL COMMENT '触发方式: scheduled-定时任务, manual-手动',
+    reclaimed_at DATETIME(3) NOT NULL COMMENT '回收时间',
+
+    INDEX idx_seat_reclamations_license_id (license_id),
+    INDEX idx_seat_reclamations_authorization_code_id (authorization_code_id),
+    INDEX idx_seat_reclamations_customer_id (customer_id),
+    INDEX idx_seat_reclamations_reclaimed_at (reclaimed_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='闲置席位回收记录表';
+
+-- 站内通知表
+CREATE TABLE notifications (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    recipient_type VARCHAR(20) NOT NULL COMMENT '接收方类型: customer-客户, user-管理端用户',
+    recipient_id VARCHAR(36) NOT NULL COMMENT '接收方ID',
+    type VARCHAR(50) NOT NULL COMMENT '通知类型，如 seat_reclaimed',
+    payload JSON COMMENT '通知内容参数',
+    read_at DATETIME(3) COMMENT '阅读时间，为空表示未读',
+    created_at DATETIME(3) NOT NULL,
+
+    INDEX idx_notifications_recipient (recipient_type, recipient_id),
+    INDEX idx_notifications_type (type),
+    INDEX idx_notifications_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='站内通知表';
@@ -0,0 +1,29 @@
+-- 设备转移限制：授权码新增每席位转移次数上限与统计周期
+ALTER TABLE authorization_codes ADD COLUMN max_transfers INT NULL COMMENT '每个席位在一个周期内最多可转移设备次数，为空不限制' AFTER dormant_reclaim_days;
+ALTER TABLE authorization_codes ADD COLUMN transfer_period_days INT NULL COMMENT '转移次数统计周期（天），为空表示整个授权期' AFTER max_transfers;
+
+-- 许可证设备转移记录表
+CREATE TABLE license_transfers (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    seat_id VARCHAR(36) NOT NULL COMMENT '席位ID（该席位最初的许可证ID）',
+    authorization_code_id VARCHAR(36) NOT NULL COMMENT '授权码ID',
+    customer_id VARCHAR(36) NOT NULL COMMENT '客户ID',
+    from_license_id VARCHAR(36) NOT NULL COMMENT '原许可证ID',
+    from_license_key VARCHAR(200) NOT NULL COMMENT '原许可证密钥',
+    from_fingerprint VARCHAR(200) NOT NULL COMMENT '原设备硬件指纹',
+    to_license_id VARCHAR(36) NOT NULL COMMENT '新许可证ID',
+    to_license_key VARCHAR(200) NOT NULL COMMENT '新许可证密钥',
+    to_fingerprint VARCHAR(200) NOT NULL COMMENT '新设备硬件指纹',
+    operator_type VARCHAR(20) NOT NULL COMMENT '操作人类型: cu_user-客户用户, admin-管理员',
+    operator_id VARCHAR(36) COMMENT '操作人ID',
+    reason VARCHAR(500) COMMENT '转移原因',
+    deactivation_receipt TEXT NOT NULL COMMENT '签名的停用回执',
+    created_at DATETIME(3) NOT NULL,
+
+    INDEX idx_license_transfers_seat_id (seat_id),
+    INDEX idx_license_transfers_authorization_code_id (authorization_code_id),
+    INDEX idx_license_transfers_customer_id (customer_id),
+    INDEX idx_license_transfers_from_license_id (from_license_id),
+    INDEX idx_license_transfers_to_license_id (to_license_id),
+    INDEX idx_license_transfers_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='许可证设备转移记录表';
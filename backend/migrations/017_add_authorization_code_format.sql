@@ -0,0 +1,4 @@
+-- 可插拔授权码格式：记录授权码格式，套餐可指定下单生成的授权码格式
+-- legacy: LIC-{客户}-{随机}-{校验}；base32: Crockford Base32 分组码；signed: 自包含签名码
+ALTER TABLE authorization_codes ADD COLUMN code_format VARCHAR(20) NOT NULL DEFAULT 'legacy' COMMENT '授权码格式: legacy/base32/signed' AFTER code;
+ALTER TABLE packages ADD COLUMN code_format VARCHAR(20) NOT NULL DEFAULT '' COMMENT '下单生成授权码的格式，为空使用系统配置' AFTER dormant_reclaim_days;
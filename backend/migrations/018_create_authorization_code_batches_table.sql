@@ -0,0 +1,27 @@
+-- 授权码批量生成：批次表，授权码记录所属批次
+CREATE TABLE authorization_code_batches (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    label VARCHAR(100) NOT NULL COMMENT '批次标签，用于渠道追踪',
+    customer_id VARCHAR(36) NOT NULL COMMENT '客户ID（分销商）',
+    quantity INT NOT NULL COMMENT '计划生成数量',
+    generated_count INT NOT NULL DEFAULT 0 COMMENT '已生成数量',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '状态: pending-等待生成, running-生成中, completed-已完成, failed-生成失败, revoked-已撤销',
+    settings JSON COMMENT '授权码配置（创建请求快照）',
+    start_date DATETIME(3) NOT NULL COMMENT '生效日期',
+    end_date DATETIME(3) NOT NULL COMMENT '失效日期',
+    error_message TEXT COMMENT '生成失败原因',
+    is_locked BOOLEAN NOT NULL DEFAULT FALSE COMMENT '是否已整批锁定',
+    revoke_reason VARCHAR(500) COMMENT '撤销原因',
+    revoked_at DATETIME(3) COMMENT '撤销时间',
+    created_by VARCHAR(36) NOT NULL COMMENT '创建人ID',
+    completed_at DATETIME(3) COMMENT '生成完成时间',
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '更新时间',
+
+    INDEX idx_authorization_code_batches_label (label),
+    INDEX idx_authorization_code_batches_customer_id (customer_id),
+    INDEX idx_authorization_code_batches_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='授权码批次表';
+
+ALTER TABLE authorization_codes ADD COLUMN batch_id VARCHAR(36) NULL COMMENT '所属批次ID（批量生成时）' AFTER code_format;
+CREATE INDEX idx_authorization_codes_batch_id ON authorization_codes(batch_id);
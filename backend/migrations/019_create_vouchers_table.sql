@@ -0,0 +1,29 @@
+-- 兑换券：生成时不绑定客户，兑换后在兑换人所属客户下生成授权码
+CREATE TABLE vouchers (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    code VARCHAR(64) NOT NULL COMMENT '兑换码',
+    label VARCHAR(100) NOT NULL DEFAULT '' COMMENT '标签（渠道/批次追踪）',
+    package_id VARCHAR(36) NOT NULL COMMENT '套餐ID',
+    package_name VARCHAR(100) NOT NULL COMMENT '套餐名称（生成时快照）',
+    license_count INT NOT NULL COMMENT '席位数',
+    create_order BOOLEAN NOT NULL DEFAULT FALSE COMMENT '兑换时是否生成零金额订单',
+    expires_at DATETIME(3) COMMENT '兑换截止时间，为空不过期',
+    status VARCHAR(20) NOT NULL DEFAULT 'unused' COMMENT '状态: unused-未兑换, redeemed-已兑换',
+    remark VARCHAR(500) COMMENT '备注',
+    created_by VARCHAR(36) NOT NULL COMMENT '创建人ID',
+    redeemed_at DATETIME(3) COMMENT '兑换时间',
+    redeemed_customer_id VARCHAR(36) COMMENT '兑换客户ID',
+    redeemed_cu_user_id VARCHAR(36) COMMENT '兑换用户ID',
+    authorization_code_id VARCHAR(36) COMMENT '兑换生成的授权码ID',
+    order_id VARCHAR(36) COMMENT '兑换生成的订单ID',
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '更新时间',
+
+    UNIQUE INDEX idx_vouchers_code (code),
+    INDEX idx_vouchers_label (label),
+    INDEX idx_vouchers_package_id (package_id),
+    INDEX idx_vouchers_expires_at (expires_at),
+    INDEX idx_vouchers_status (status),
+    INDEX idx_vouchers_redeemed_at (redeemed_at),
+    INDEX idx_vouchers_redeemed_customer_id (redeemed_customer_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='兑换券表';
@@ -0,0 +1,25 @@
+-- 授权码分享记录：关联原授权码与分享生成的授权码，支持收回未使用席位和邀请确认
+CREATE TABLE authorization_code_shares (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    source_code_id VARCHAR(36) NOT NULL COMMENT '原授权码ID',
+    target_code_id VARCHAR(36) COMMENT '分享生成的授权码ID（邀请未接受时为空）',
+    source_customer_id VARCHAR(36) NOT NULL COMMENT '分享方客户ID',
+    source_user_id VARCHAR(36) NOT NULL COMMENT '分享方用户ID',
+    target_customer_id VARCHAR(36) NOT NULL COMMENT '受赠方客户ID',
+    target_user_id VARCHAR(36) NOT NULL COMMENT '受赠方用户ID',
+    share_count INT NOT NULL COMMENT '分享席位数',
+    reclaimed_count INT NOT NULL DEFAULT 0 COMMENT '已收回席位数',
+    mode VARCHAR(20) NOT NULL DEFAULT 'direct' COMMENT '分享方式: direct-直接分享, invite-邀请分享',
+    status VARCHAR(20) NOT NULL DEFAULT 'accepted' COMMENT '状态: pending-待接受, accepted-已生效, declined-已拒绝, cancelled-已取消',
+    responded_at DATETIME(3) COMMENT '受赠方响应/取消时间',
+    last_reclaimed_at DATETIME(3) COMMENT '最近一次收回时间',
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '分享时间',
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '更新时间',
+
+    INDEX idx_authorization_code_shares_source_code_id (source_code_id),
+    INDEX idx_authorization_code_shares_target_code_id (target_code_id),
+    INDEX idx_authorization_code_shares_source_user_id (source_user_id),
+    INDEX idx_authorization_code_shares_target_user_id (target_user_id),
+    INDEX idx_authorization_code_shares_status (status),
+    INDEX idx_authorization_code_shares_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='授权码分享记录表';
@@ -0,0 +1,24 @@
+-- 授权码计划操作：在指定时间或关联订单支付后执行锁定、解锁、延期、调整最大激活数，执行结果记录到授权变更历史
+CREATE TABLE authorization_code_scheduled_actions (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    authorization_code_id VARCHAR(36) NOT NULL COMMENT '授权码ID',
+    action_type VARCHAR(30) NOT NULL COMMENT '操作类型: lock-锁定, unlock-解锁, extend-延期, set_max_activations-调整最大激活数',
+    trigger_type VARCHAR(20) NOT NULL COMMENT '触发方式: at-指定时间, order_paid-关联订单支付后',
+    run_at DATETIME(3) COMMENT '计划执行时间',
+    order_id VARCHAR(36) COMMENT '关联订单ID',
+    extend_days INT COMMENT '延长天数',
+    max_activations INT COMMENT '新的最大激活数',
+    reason VARCHAR(500) COMMENT '操作原因',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '状态: pending-待执行, done-已执行, failed-执行失败, cancelled-已取消',
+    executed_at DATETIME(3) COMMENT '执行（或失败、取消）时间',
+    change_id VARCHAR(36) COMMENT '执行后生成的授权变更记录ID',
+    error_message VARCHAR(500) COMMENT '执行失败原因',
+    created_by VARCHAR(36) NOT NULL COMMENT '创建人ID',
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '更新时间',
+
+    INDEX idx_authorization_code_scheduled_actions_authorization_code_id (authorization_code_id),
+    INDEX idx_authorization_code_scheduled_actions_run_at (run_at),
+    INDEX idx_authorization_code_scheduled_actions_order_id (order_id),
+    INDEX idx_authorization_code_scheduled_actions_status (status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='授权码计划操作表';
@@ -0,0 +1,22 @@
+-- 授权码使用限制：按IP网段、国家/地区、时间窗口和每日新增激活数限制激活与心跳
+ALTER TABLE authorization_codes ADD COLUMN activation_policy JSON COMMENT '使用限制策略（IP网段/国家/时间窗口/每日激活数），为空不限制' AFTER custom_parameters;
+
+-- 使用限制违规事件
+CREATE TABLE activation_violations (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    authorization_code_id VARCHAR(36) NOT NULL COMMENT '授权码ID',
+    customer_id VARCHAR(36) NOT NULL COMMENT '客户ID',
+    license_id VARCHAR(36) COMMENT '许可证ID（心跳时）',
+    stage VARCHAR(20) NOT NULL COMMENT '环节: activation-激活, heartbeat-心跳',
+    violation_type VARCHAR(30) NOT NULL COMMENT '违规类型: ip_not_allowed-IP不在允许网段, country_not_allowed-国家/地区不允许, outside_time_window-不在允许时间段, daily_limit_exceeded-超过每日激活数',
+    client_ip VARCHAR(45) COMMENT '客户端IP',
+    country VARCHAR(2) COMMENT 'GeoIP识别的国家/地区',
+    hardware_fingerprint VARCHAR(200) COMMENT '硬件指纹',
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '发生时间',
+
+    INDEX idx_activation_violations_authorization_code_id (authorization_code_id),
+    INDEX idx_activation_violations_customer_id (customer_id),
+    INDEX idx_activation_violations_license_id (license_id),
+    INDEX idx_activation_violations_violation_type (violation_type),
+    INDEX idx_activation_violations_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='使用限制违规事件表';
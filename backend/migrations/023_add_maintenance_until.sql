@@ -0,0 +1,8 @@
+-- 维护（升级）授权与许可证有效期分离：永久授权可单独销售一年升级服务
+ALTER TABLE authorization_codes ADD COLUMN maintenance_until DATETIME(3) COMMENT '维护（升级）截止时间，客户端可使用此前发布的任何版本，为空不限制' AFTER end_date;
+
+ALTER TABLE packages ADD COLUMN maintenance_months INT COMMENT '维护月数：新购授权码的维护期，续订维护订单按此顺延；为空不限制版本且不支持续订' AFTER code_format;
+
+ALTER TABLE cu_orders ADD COLUMN order_type VARCHAR(30) NOT NULL DEFAULT 'new' COMMENT '订单类型: new-新购, maintenance_renewal-续订维护' AFTER authorization_code;
+ALTER TABLE cu_orders ADD COLUMN renewal_code_id VARCHAR(36) COMMENT '续订维护的授权码ID' AFTER order_type;
+ALTER TABLE cu_orders ADD INDEX idx_cu_orders_renewal_code_id (renewal_code_id);
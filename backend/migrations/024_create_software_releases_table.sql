@@ -0,0 +1,19 @@
+-- 产品版本发布登记：心跳时按授权码的产品、更新渠道和维护期下发可安装的最新版本
+CREATE TABLE software_releases (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    software_id VARCHAR(50) NOT NULL COMMENT '产品（授权码的软件ID）',
+    version VARCHAR(50) NOT NULL COMMENT '版本号',
+    channel VARCHAR(20) NOT NULL DEFAULT 'stable' COMMENT '发布渠道: stable-正式版, beta-测试版',
+    released_at DATETIME(3) NOT NULL COMMENT '发布时间，早于维护截止时间的版本才可安装',
+    download_url VARCHAR(1000) NOT NULL COMMENT '下载地址',
+    checksum VARCHAR(200) NOT NULL COMMENT '安装包校验值',
+    release_notes TEXT COMMENT '更新说明',
+    is_published BOOLEAN NOT NULL DEFAULT TRUE COMMENT '是否发布，未发布的版本不会推送给客户端',
+    created_by VARCHAR(36) NOT NULL COMMENT '创建人ID',
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '更新时间',
+
+    UNIQUE KEY uk_software_releases_version (software_id, version),
+    INDEX idx_software_releases_channel (channel),
+    INDEX idx_software_releases_released_at (released_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='产品版本发布表';
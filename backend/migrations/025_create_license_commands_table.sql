@@ -0,0 +1,26 @@
+-- 远程命令：管理员或客户为设备创建命令，随心跳下发，客户端在下一次心跳中确认执行结果
+CREATE TABLE license_commands (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    license_id VARCHAR(36) NOT NULL COMMENT '许可证ID',
+    authorization_code_id VARCHAR(36) NOT NULL COMMENT '授权码ID',
+    customer_id VARCHAR(36) NOT NULL COMMENT '客户ID',
+    command_type VARCHAR(30) NOT NULL COMMENT '命令类型: refresh_license-刷新许可证, deactivate-停用并删除本地文件, show_message-显示消息, collect_diagnostics-收集诊断信息',
+    payload JSON COMMENT '命令参数',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '状态: pending-待下发, delivered-已下发, succeeded-执行成功, failed-执行失败, cancelled-已取消, expired-已过期',
+    result JSON COMMENT '客户端上报的执行结果',
+    error_message VARCHAR(1000) COMMENT '客户端上报的失败原因',
+    issuer_type VARCHAR(20) NOT NULL COMMENT '发起人类型: admin-管理员, cu_user-客户用户',
+    issuer_id VARCHAR(36) NOT NULL COMMENT '发起人ID',
+    expires_at DATETIME(3) NOT NULL COMMENT '有效期，过期未确认的命令不再下发',
+    delivered_at DATETIME(3) COMMENT '首次下发时间',
+    delivery_count INT NOT NULL DEFAULT 0 COMMENT '下发次数',
+    acknowledged_at DATETIME(3) COMMENT '客户端确认时间',
+    created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '创建时间',
+    updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) COMMENT '更新时间',
+
+    INDEX idx_license_commands_license_id (license_id),
+    INDEX idx_license_commands_authorization_code_id (authorization_code_id),
+    INDEX idx_license_commands_customer_id (customer_id),
+    INDEX idx_license_commands_status (status),
+    INDEX idx_license_commands_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='远程命令表';
@@ -0,0 +1,4 @@
+-- 部署类型规则：混合部署指定本地中继节点，许可证记录激活方式
+ALTER TABLE authorization_codes ADD COLUMN relay_fingerprint VARCHAR(200) COMMENT '混合部署指定的本地中继节点硬件指纹' AFTER deployment_type;
+
+ALTER TABLE licenses ADD COLUMN activation_mode VARCHAR(20) NOT NULL DEFAULT 'online' COMMENT '激活方式: online-在线, offline-离线, relay-经本地中继' AFTER activation_ip;
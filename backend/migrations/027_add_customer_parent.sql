@@ -0,0 +1,4 @@
+-- 客户层级：客户可指定上级客户（集团/子公司）
+ALTER TABLE customers ADD COLUMN parent_id VARCHAR(36) COMMENT '上级客户ID' AFTER customer_name;
+
+CREATE INDEX idx_customers_parent_id ON customers(parent_id);
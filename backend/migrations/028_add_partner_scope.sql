@@ -0,0 +1,6 @@
+-- 经销商：后台账号新增 partner 角色及席位额度，客户记录所属经销商
+ALTER TABLE users ADD COLUMN seat_quota INT NOT NULL DEFAULT 0 COMMENT '经销商席位额度（仅经销商账号使用）' AFTER locked_until;
+
+ALTER TABLE customers ADD COLUMN partner_id VARCHAR(36) COMMENT '所属经销商（经销商账号ID），为空表示直营客户' AFTER parent_id;
+
+CREATE INDEX idx_customers_partner_id ON customers(partner_id);
@@ -0,0 +1,8 @@
+-- 线索转化：记录线索转化后关联的客户、客户端用户及试用授权码
+ALTER TABLE leads ADD COLUMN customer_id VARCHAR(36) COMMENT '转化后的客户ID' AFTER internal_note;
+ALTER TABLE leads ADD COLUMN cu_user_id VARCHAR(36) COMMENT '转化时创建的客户端用户ID' AFTER customer_id;
+ALTER TABLE leads ADD COLUMN trial_authorization_code_id VARCHAR(36) COMMENT '转化时签发的试用授权码ID' AFTER cu_user_id;
+ALTER TABLE leads ADD COLUMN converted_at TIMESTAMP NULL COMMENT '转化时间' AFTER trial_authorization_code_id;
+
+CREATE INDEX idx_leads_customer_id ON leads(customer_id);
+CREATE INDEX idx_leads_converted_at ON leads(converted_at);
@@ -0,0 +1,36 @@
+-- 线索分配、去重及跟进动态
+ALTER TABLE leads ADD COLUMN assignee_id VARCHAR(36) COMMENT '负责人（后台账号ID）' AFTER converted_at;
+ALTER TABLE leads ADD COLUMN duplicate_of_id VARCHAR(36) COMMENT '疑似重复的原线索ID' AFTER assignee_id;
+ALTER TABLE leads ADD COLUMN merged_into_id VARCHAR(36) COMMENT '已合并到的线索ID' AFTER duplicate_of_id;
+ALTER TABLE leads ADD COLUMN normalized_phone VARCHAR(20) COMMENT '标准化手机号（去重用）' AFTER merged_into_id;
+ALTER TABLE leads ADD COLUMN normalized_email VARCHAR(100) COMMENT '标准化邮箱（去重用）' AFTER normalized_phone;
+ALTER TABLE leads ADD COLUMN normalized_company VARCHAR(200) COMMENT '标准化公司名称（去重用）' AFTER normalized_email;
+ALTER TABLE leads ADD COLUMN follow_up_reminded_at DATETIME(3) NULL COMMENT '最近一次跟进日期提醒时间' AFTER normalized_company;
+ALTER TABLE leads ADD COLUMN pending_reminded_at DATETIME(3) NULL COMMENT '长时间未跟进提醒时间' AFTER follow_up_reminded_at;
+
+CREATE INDEX idx_leads_assignee_id ON leads(assignee_id);
+CREATE INDEX idx_leads_duplicate_of_id ON leads(duplicate_of_id);
+CREATE INDEX idx_leads_merged_into_id ON leads(merged_into_id);
+CREATE INDEX idx_leads_normalized_phone ON leads(normalized_phone);
+CREATE INDEX idx_leads_normalized_email ON leads(normalized_email);
+CREATE INDEX idx_leads_normalized_company ON leads(normalized_company);
+
+-- 标准化字段由服务启动时的自动迁移补齐（公司名称规则在应用层实现）
+
+CREATE TABLE lead_activities (
+    id VARCHAR(36) PRIMARY KEY COMMENT '动态ID',
+    lead_id VARCHAR(36) NOT NULL COMMENT '线索ID',
+    type VARCHAR(20) NOT NULL COMMENT '动态类型: note/follow_up/status_change/assignment/merge/conversion',
+    content TEXT NOT NULL COMMENT '内容',
+    operator_id VARCHAR(36) COMMENT '操作人（后台账号ID），系统生成时为空',
+    created_at DATETIME(3) NOT NULL COMMENT '创建时间',
+
+    INDEX idx_lead_activities_lead_id (lead_id),
+    INDEX idx_lead_activities_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='线索跟进动态表';
+
+-- 原有的单条跟进记录迁移为第一条跟进动态
+INSERT INTO lead_activities (id, lead_id, type, content, operator_id, created_at)
+SELECT UUID(), id, 'follow_up', follow_up_record, NULL, updated_at
+FROM leads
+WHERE follow_up_record IS NOT NULL AND follow_up_record <> '';
@@ -0,0 +1,15 @@
+-- 重复客户合并：来源客户的数据迁移到目标客户后删除来源客户，合并过程记录到审计表
+CREATE TABLE customer_merges (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    target_customer_id VARCHAR(36) NOT NULL COMMENT '目标客户ID',
+    source_customer_ids JSON NOT NULL COMMENT '来源客户ID列表',
+    source_customers JSON COMMENT '合并前的来源客户快照',
+    moved_counts JSON COMMENT '各类数据迁移数量',
+    operator_id VARCHAR(36) NOT NULL COMMENT '操作人ID',
+    reason VARCHAR(500) COMMENT '合并原因',
+    created_at DATETIME(3) NOT NULL COMMENT '合并时间',
+
+    INDEX idx_customer_merges_target_customer_id (target_customer_id),
+    INDEX idx_customer_merges_operator_id (operator_id),
+    INDEX idx_customer_merges_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='客户合并审计记录表';
@@ -0,0 +1,23 @@
+-- 客户批量导入任务：上传的文件解析后保存数据行快照，由后台任务逐行创建或更新客户，逐行错误保存在错误报告中
+CREATE TABLE customer_import_jobs (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    file_name VARCHAR(255) NOT NULL COMMENT '上传的文件名',
+    file_format VARCHAR(10) NOT NULL COMMENT '文件格式：csv/xlsx',
+    upsert_key VARCHAR(20) NOT NULL COMMENT '匹配已有客户的方式：customer_code/email',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '状态：pending/running/completed/failed',
+    total_rows INT NOT NULL DEFAULT 0 COMMENT '数据行数（不含表头）',
+    processed_rows INT NOT NULL DEFAULT 0 COMMENT '已处理行数',
+    created_count INT NOT NULL DEFAULT 0 COMMENT '新建客户数',
+    updated_count INT NOT NULL DEFAULT 0 COMMENT '更新客户数',
+    failed_count INT NOT NULL DEFAULT 0 COMMENT '失败行数',
+    `rows` JSON COMMENT '待导入的数据行快照',
+    error_report JSON COMMENT '逐行错误报告',
+    error_message TEXT COMMENT '任务失败原因',
+    created_by VARCHAR(36) NOT NULL COMMENT '创建人ID',
+    completed_at DATETIME(3) COMMENT '完成时间',
+    created_at DATETIME(3) NOT NULL COMMENT '创建时间',
+    updated_at DATETIME(3) NOT NULL COMMENT '更新时间',
+
+    INDEX idx_customer_import_jobs_status (status),
+    INDEX idx_customer_import_jobs_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='客户导入任务表';
@@ -0,0 +1,40 @@
+-- 自定义字段与标签：管理员为客户、授权码和许可证定义自定义字段，字段值和标签按对象类型+对象ID保存，支持列表筛选
+CREATE TABLE custom_field_definitions (
+    id VARCHAR(36) PRIMARY KEY DEFAULT (UUID()),
+    entity_type VARCHAR(20) NOT NULL COMMENT '对象类型：customer/authorization_code/license',
+    field_key VARCHAR(64) NOT NULL COMMENT '字段标识，同一对象类型内唯一',
+    name VARCHAR(100) NOT NULL COMMENT '字段名称',
+    field_type VARCHAR(20) NOT NULL COMMENT '字段类型：text/number/boolean/date/select',
+    options JSON COMMENT '单选字段的可选值',
+    required BOOLEAN NOT NULL DEFAULT FALSE COMMENT '是否必填',
+    pattern VARCHAR(200) COMMENT '文本字段的校验正则表达式',
+    embed_in_license BOOLEAN NOT NULL DEFAULT FALSE COMMENT '是否写入签名许可证文件（仅授权码字段）',
+    sort_order INT NOT NULL DEFAULT 0 COMMENT '排序，越小越靠前',
+    description VARCHAR(500) COMMENT '字段说明',
+    created_by VARCHAR(36) NOT NULL COMMENT '创建人ID',
+    created_at DATETIME(3) NOT NULL COMMENT '创建时间',
+    updated_at DATETIME(3) NOT NULL COMMENT '更新时间',
+
+    UNIQUE KEY uk_custom_field_definitions_entity_key (entity_type, field_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='自定义字段定义表';
+
+CREATE TABLE custom_field_values (
+    entity_type VARCHAR(20) NOT NULL COMMENT '对象类型',
+    entity_id VARCHAR(36) NOT NULL COMMENT '对象ID',
+    field_key VARCHAR(64) NOT NULL COMMENT '字段标识',
+    value VARCHAR(500) NOT NULL COMMENT '字段值（规范化后的字符串）',
+    updated_at DATETIME(3) NOT NULL COMMENT '更新时间',
+
+    PRIMARY KEY (entity_type, entity_id, field_key),
+    INDEX idx_custom_field_values_lookup (entity_type, field_key, value)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='自定义字段值表';
+
+CREATE TABLE entity_tags (
+    entity_type VARCHAR(20) NOT NULL COMMENT '对象类型',
+    entity_id VARCHAR(36) NOT NULL COMMENT '对象ID',
+    tag VARCHAR(50) NOT NULL COMMENT '标签',
+    created_at DATETIME(3) NOT NULL COMMENT '添加时间',
+
+    PRIMARY KEY (entity_type, entity_id, tag),
+    INDEX idx_entity_tags_tag (entity_type, tag)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='对象标签表';
@@ -0,0 +1,15 @@
+-- 客户席位池：客户级总席位，开启 use_seat_pool 的授权码共享席位池的席位
+CREATE TABLE customer_seat_pools (
+    id VARCHAR(36) PRIMARY KEY COMMENT '席位池ID',
+    customer_id VARCHAR(36) NOT NULL COMMENT '客户ID',
+    total_seats INT NOT NULL COMMENT '总席位数',
+    description VARCHAR(500) COMMENT '说明',
+    created_by VARCHAR(36) NOT NULL COMMENT '创建人ID',
+    updated_by VARCHAR(36) NOT NULL COMMENT '最后修改人ID',
+    created_at DATETIME(3) NOT NULL COMMENT '创建时间',
+    updated_at DATETIME(3) NOT NULL COMMENT '更新时间',
+
+    UNIQUE KEY uk_customer_seat_pools_customer_id (customer_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='客户席位池表';
+
+ALTER TABLE authorization_codes ADD COLUMN use_seat_pool BOOLEAN NOT NULL DEFAULT FALSE COMMENT '是否从客户席位池取用席位' AFTER batch_id;
@@ -0,0 +1,6 @@
+-- 回收站：授权码和线索改为软删除，删除后进入回收站，可恢复或彻底删除
+ALTER TABLE authorization_codes ADD COLUMN deleted_at DATETIME(3) NULL COMMENT '删除时间（软删除）' AFTER updated_at;
+CREATE INDEX idx_authorization_codes_deleted_at ON authorization_codes(deleted_at);
+
+ALTER TABLE leads ADD COLUMN deleted_at DATETIME NULL COMMENT '删除时间（软删除）' AFTER updated_at;
+CREATE INDEX idx_leads_deleted_at ON leads(deleted_at);
@@ -0,0 +1,4 @@
+-- 回收站：已删除授权码下的许可证随授权码一并软删除（删除时间与授权码一致，恢复授权码时一并恢复）
+UPDATE licenses l JOIN authorization_codes a ON a.id = l.authorization_code_id
+SET l.deleted_at = a.deleted_at
+WHERE a.deleted_at IS NOT NULL AND l.deleted_at IS NULL;
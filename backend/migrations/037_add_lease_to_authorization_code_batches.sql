@@ -0,0 +1,3 @@
+-- 授权码批次：多实例下以租约方式领取批次，租约过期后其他实例可接管续跑
+ALTER TABLE authorization_code_batches ADD COLUMN lease_owner VARCHAR(36) NULL COMMENT '执行租约持有者' AFTER completed_at;
+ALTER TABLE authorization_code_batches ADD COLUMN lease_expires_at DATETIME(3) NULL COMMENT '执行租约到期时间' AFTER lease_owner;
@@ -0,0 +1,3 @@
+-- 客户导入任务：多实例下以租约方式领取任务，租约过期后其他实例可接管续跑
+ALTER TABLE customer_import_jobs ADD COLUMN lease_owner VARCHAR(36) NULL COMMENT '执行租约持有者' AFTER completed_at;
+ALTER TABLE customer_import_jobs ADD COLUMN lease_expires_at DATETIME(3) NULL COMMENT '执行租约到期时间' AFTER lease_owner;
@@ -61,6 +61,11 @@ func (k *KeyBuilder) Counter(name string) string {
 	return k.Build("counter", name)
 }
 
+// Entitlement 构建授权检查结果键，scope 为 code 或 customer
+func (k *KeyBuilder) Entitlement(scope, id string) string {
+	return k.Build("entitlement", scope, id)
+}
+
 // 预定义键模式常量
 const (
 	UserPrefix      = "user"
@@ -18,6 +18,8 @@ const (
 	UserIDKey contextKey = "user_id"
 	// TraceIDKey 存储链路追踪ID的上下文键
 	TraceIDKey contextKey = "trace_id"
+	// PartnerIDKey 存储经销商ID的上下文键（经销商账号访问时用于限定数据范围）
+	PartnerIDKey contextKey = "partner_id"
 )
 
 // DefaultLanguage 默认语言
@@ -116,6 +118,28 @@ func GetUserRoleFromContext(ctx context.Context) string {
 	return ""
 }
 
+// WithPartnerID 将经销商ID添加到Context中
+func WithPartnerID(ctx context.Context, partnerID string) context.Context {
+	return context.WithValue(ctx, PartnerIDKey, partnerID)
+}
+
+// GetPartnerIDFromContext 从context中获取当前经销商ID，非经销商账号返回空字符串
+func GetPartnerIDFromContext(ctx context.Context) string {
+	if ginCtx, ok := ctx.(*gin.Context); ok {
+		if partnerID, exists := ginCtx.Get("partner_id"); exists {
+			if id, ok := partnerID.(string); ok {
+				return id
+			}
+		}
+	}
+
+	if partnerID, ok := ctx.Value(PartnerIDKey).(string); ok {
+		return partnerID
+	}
+
+	return ""
+}
+
 // WithTraceID 将链路追踪ID添加到Context中
 func WithTraceID(ctx context.Context, traceID string) context.Context {
 	return context.WithValue(ctx, TraceIDKey, traceID)
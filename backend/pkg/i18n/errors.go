@@ -136,6 +136,18 @@ func getHTTPStatusByCode(code string) int {
 				return StatusOK
 			case "62": // 设备模块错误，默认200（业务错误通过响应体区分）
 				return StatusOK
+			case "63": // 站内通知模块错误，默认200（业务错误通过响应体区分）
+				return StatusOK
+			case "64": // 兑换券模块错误，默认200（业务错误通过响应体区分）
+				return StatusOK
+			case "65": // 线索模块错误，默认200（业务错误通过响应体区分）
+				return StatusOK
+			case "66": // 自定义字段模块错误，默认200（业务错误通过响应体区分）
+				return StatusOK
+			case "67": // 客户席位池模块错误，默认200（业务错误通过响应体区分）
+				return StatusOK
+			case "68": // 回收站模块错误，默认200（业务错误通过响应体区分）
+				return StatusOK
 			case "70": // 发票模块错误，默认200（业务错误通过响应体区分）
 				return StatusOK
 			case "90": // 系统错误，默认500
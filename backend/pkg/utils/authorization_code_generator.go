@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 授权码格式
+const (
+	AuthorizationCodeFormatLegacy = "legacy" // LIC-{客户ID前4位}-{随机}-{校验}
+	AuthorizationCodeFormatBase32 = "base32" // Crockford Base32 分组码，末位为校验字符，便于电话口述
+	AuthorizationCodeFormatSigned = "signed" // 自包含签名码，编码到期时间与版本，可离线校验
+)
+
+// SignedAuthorizationCodePrefix 自包含签名码前缀（含版本号）
+const SignedAuthorizationCodePrefix = "SC1"
+
+// crockfordAlphabet Crockford Base32 字母表（去掉 I、L、O、U 避免混淆）
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const (
+	base32CodeGroups    = 5 // 分组数
+	base32CodeGroupSize = 5 // 每组字符数（最后一组含1位校验字符）
+)
+
+// AuthorizationCodeParams 生成授权码所需的参数
+type AuthorizationCodeParams struct {
+	CustomerID string    // 客户ID（仅旧格式使用）
+	Edition    string    // 版本/套餐类型（签名码使用）
+	ExpiresAt  time.Time // 到期时间（签名码使用）
+}
+
+// AuthorizationCodeGenerator 授权码生成器
+type AuthorizationCodeGenerator interface {
+	Format() string
+	Generate(params AuthorizationCodeParams) (string, error)
+}
+
+// NewAuthorizationCodeGenerator 根据格式创建生成器，签名码需要提供RSA私钥
+func NewAuthorizationCodeGenerator(format string, privateKey *RSAPrivateKey) (AuthorizationCodeGenerator, error) {
+	switch format {
+	case "", AuthorizationCodeFormatLegacy:
+		return legacyCodeGenerator{}, nil
+	case AuthorizationCodeFormatBase32:
+		return base32CodeGenerator{}, nil
+	case AuthorizationCodeFormatSigned:
+		if privateKey == nil {
+			return nil, errors.New("signed authorization code requires RSA private key")
+		}
+		return signedCodeGenerator{privateKey: privateKey}, nil
+	default:
+		return nil, fmt.Errorf("unsupported authorization code format: %s", format)
+	}
+}
+
+// IsValidAuthorizationCodeFormat 判断授权码格式是否受支持
+func IsValidAuthorizationCodeFormat(format string) bool {
+	switch format {
+	case AuthorizationCodeFormatLegacy, AuthorizationCodeFormatBase32, AuthorizationCodeFormatSigned:
+		return true
+	}
+	return false
+}
+
+// legacyCodeGenerator 旧规则授权码
+type legacyCodeGenerator struct{}
+
+func (legacyCodeGenerator) Format() string { return AuthorizationCodeFormatLegacy }
+
+func (legacyCodeGenerator) Generate(params AuthorizationCodeParams) (string, error) {
+	return GenerateLegacyAuthorizationCode(params.CustomerID)
+}
+
+// base32CodeGenerator Crockford Base32 授权码：XXXXX-XXXXX-XXXXX-XXXXX-XXXXC
+type base32CodeGenerator struct{}
+
+func (base32CodeGenerator) Format() string { return AuthorizationCodeFormatBase32 }
+
+func (base32CodeGenerator) Generate(params AuthorizationCodeParams) (string, error) {
+	length := base32CodeGroups*base32CodeGroupSize - 1
+	bytes := make([]byte, length)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	body := make([]byte, length)
+	for i, b := range bytes {
+		body[i] = crockfordAlphabet[b%byte(len(crockfordAlphabet))] // 256是32的整数倍，无取模偏差
+	}
+
+	check := crockfordCheckChar(string(body))
+	return groupCrockfordCode(string(body) + string(check)), nil
+}
+
+// crockfordCheckChar 使用 Luhn mod 32 算法计算校验字符，可检出单字符错误和相邻字符互换
+func crockfordCheckChar(input string) byte {
+	n := len(crockfordAlphabet)
+	factor := 2
+	sum := 0
+	for i := len(input) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(crockfordAlphabet, input[i])
+		factor = 3 - factor
+		sum += addend/n + addend%n
+	}
+	return crockfordAlphabet[(n-sum%n)%n]
+}
+
+// validCrockfordChecksum 校验包含末位校验字符的完整码
+func validCrockfordChecksum(input string) bool {
+	n := len(crockfordAlphabet)
+	factor := 1
+	sum := 0
+	for i := len(input) - 1; i >= 0; i-- {
+		idx := strings.IndexByte(crockfordAlphabet, input[i])
+		if idx < 0 {
+			return false
+		}
+		addend := factor * idx
+		factor = 3 - factor
+		sum += addend/n + addend%n
+	}
+	return sum%n == 0
+}
+
+func groupCrockfordCode(raw string) string {
+	groups := make([]string, 0, base32CodeGroups)
+	for i := 0; i < len(raw); i += base32CodeGroupSize {
+		end := i + base32CodeGroupSize
+		if end > len(raw) {
+			end = len(raw)
+		}
+		groups = append(groups, raw[i:end])
+	}
+	return strings.Join(groups, "-")
+}
+
+// NormalizeAuthorizationCode 规范化用户输入的授权码
+// 对 Crockford Base32 码：忽略大小写、空格和分隔符，将 O→0、I/L→1 后校验，通过则返回标准分组格式；
+// 其他格式原样返回
+func NormalizeAuthorizationCode(code string) string {
+	code = strings.TrimSpace(code)
+
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		switch r {
+		case '-', ' ':
+			continue
+		case 'O':
+			r = '0'
+		case 'I', 'L':
+			r = '1'
+		}
+		b.WriteRune(r)
+	}
+	raw := b.String()
+
+	if len(raw) != base32CodeGroups*base32CodeGroupSize || !validCrockfordChecksum(raw) {
+		return code
+	}
+	return groupCrockfordCode(raw)
+}
+
+// SignedAuthorizationCodePayload 自包含签名码中的数据
+type SignedAuthorizationCodePayload struct {
+	Edition   string `json:"e,omitempty"` // 版本/套餐类型
+	ExpiresAt int64  `json:"x"`           // 到期时间（Unix秒）
+	Nonce     string `json:"n"`           // 随机数，保证唯一
+}
+
+// signedCodeGenerator 自包含签名码：SC1.{base64url(payload)}.{base64url(RSA-PSS签名)}
+type signedCodeGenerator struct {
+	privateKey *RSAPrivateKey
+}
+
+func (signedCodeGenerator) Format() string { return AuthorizationCodeFormatSigned }
+
+func (g signedCodeGenerator) Generate(params AuthorizationCodeParams) (string, error) {
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(SignedAuthorizationCodePayload{
+		Edition:   params.Edition,
+		ExpiresAt: params.ExpiresAt.Unix(),
+		Nonce:     hex.EncodeToString(nonce),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	signingInput := SignedAuthorizationCodePrefix + "." + encodedPayload
+
+	signature, err := g.privateKey.SignData([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	rawSignature, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(rawSignature), nil
+}
+
+// ParseSignedAuthorizationCode 解析自包含签名码，publicKey 不为空时同时校验签名
+func ParseSignedAuthorizationCode(code string, publicKey *RSAPublicKey) (*SignedAuthorizationCodePayload, error) {
+	parts := strings.Split(code, ".")
+	if len(parts) != 3 || parts[0] != SignedAuthorizationCodePrefix {
+		return nil, errors.New("not a signed authorization code")
+	}
+
+	if publicKey != nil {
+		rawSignature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature encoding: %w", err)
+		}
+		signingInput := parts[0] + "." + parts[1]
+		if err := publicKey.VerifySignature([]byte(signingInput), base64.StdEncoding.EncodeToString(rawSignature)); err != nil {
+			return nil, err
+		}
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+	var payload SignedAuthorizationCodePayload
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	return &payload, nil
+}
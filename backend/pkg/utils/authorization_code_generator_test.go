@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+	"time"
+
+	"license-manager/internal/config"
+)
+
+func TestBase32AuthorizationCode(t *testing.T) {
+	gen, err := NewAuthorizationCodeGenerator(AuthorizationCodeFormatBase32, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	code, err := gen.Generate(AuthorizationCodeParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(code) != 29 || strings.Count(code, "-") != 4 {
+		t.Fatalf("unexpected code layout: %s", code)
+	}
+	if got := NormalizeAuthorizationCode(code); got != code {
+		t.Fatalf("canonical code should normalize to itself: %s -> %s", code, got)
+	}
+
+	t.Run("lenient input", func(t *testing.T) {
+		input := strings.ToLower(strings.ReplaceAll(code, "-", " "))
+		input = strings.NewReplacer("0", "o", "1", "l").Replace(input)
+		if got := NormalizeAuthorizationCode(input); got != code {
+			t.Fatalf("expected %s, got %s", code, got)
+		}
+	})
+
+	t.Run("single char error", func(t *testing.T) {
+		raw := []byte(strings.ReplaceAll(code, "-", ""))
+		for i := range raw {
+			original := raw[i]
+			for _, c := range []byte(crockfordAlphabet) {
+				if c == original {
+					continue
+				}
+				raw[i] = c
+				if validCrockfordChecksum(string(raw)) {
+					t.Fatalf("checksum accepted altered code %s", raw)
+				}
+			}
+			raw[i] = original
+		}
+	})
+
+	t.Run("other formats untouched", func(t *testing.T) {
+		legacy := "LIC-ABCD-abcdefghijkl-WXYZ"
+		if got := NormalizeAuthorizationCode(legacy); got != legacy {
+			t.Fatalf("expected %s, got %s", legacy, got)
+		}
+	})
+}
+
+func TestSignedAuthorizationCode(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	privateKey := &RSAPrivateKey{PrivateKey: key}
+	publicKey := &RSAPublicKey{PublicKey: &key.PublicKey}
+
+	if _, err := NewAuthorizationCodeGenerator(AuthorizationCodeFormatSigned, nil); err == nil {
+		t.Fatal("expected error without private key")
+	}
+
+	gen, err := NewAuthorizationCodeGenerator(AuthorizationCodeFormatSigned, privateKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expiresAt := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	code, err := gen.Generate(AuthorizationCodeParams{Edition: "professional", ExpiresAt: expiresAt})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(code, "&") {
+		t.Fatalf("code must not contain the product activation separator: %s", code)
+	}
+
+	payload, err := ParseSignedAuthorizationCode(code, publicKey)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if payload.Edition != "professional" || payload.ExpiresAt != expiresAt.Unix() {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+
+	tampered := strings.Replace(code, ".", ".e", 1)
+	if _, err := ParseSignedAuthorizationCode(tampered, publicKey); err == nil {
+		t.Fatal("expected tampered code to fail verification")
+	}
+}
+
+func TestSignedAuthorizationCodeMaxLength(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	gen, err := NewAuthorizationCodeGenerator(AuthorizationCodeFormatSigned, &RSAPrivateKey{PrivateKey: key})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 最长版本标识与最大到期时间下，生成的签名码长度等于配置校验计算的上限
+	code, err := gen.Generate(AuthorizationCodeParams{
+		Edition:   strings.Repeat("e", 50),
+		ExpiresAt: time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := config.SignedAuthorizationCodeMaxLength(key.Size()); len(code) != want {
+		t.Errorf("len(code) = %d, want %d", len(code), want)
+	}
+
+	if got := config.SignedAuthorizationCodeMaxLength(2048 / 8); got > config.MaxAuthorizationCodeLength {
+		t.Errorf("2048-bit key: max length %d exceeds %d", got, config.MaxAuthorizationCodeLength)
+	}
+	if got := config.SignedAuthorizationCodeMaxLength(4096 / 8); got <= config.MaxAuthorizationCodeLength {
+		t.Errorf("4096-bit key: max length %d expected to exceed %d", got, config.MaxAuthorizationCodeLength)
+	}
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// GeoIPDatabase 离线 GeoIP 国家/地区库
+// 从 CSV 文件加载，每行一个 IP 段，支持两种格式（可混用，# 开头为注释，首行表头自动跳过）：
+//
+//	network,country_code          如 1.0.0.0/24,AU（可由 GeoLite2-Country CSV 转换）
+//	start_ip,end_ip,country_code  如 1.0.0.0,1.0.0.255,AU（DB-IP / IP2Location Lite 格式）
+type GeoIPDatabase struct {
+	ranges []geoIPRange
+}
+
+type geoIPRange struct {
+	start   netip.Addr
+	end     netip.Addr
+	country string
+}
+
+// LoadGeoIPDatabase 从文件加载离线 GeoIP 库
+func LoadGeoIPDatabase(path string) (*GeoIPDatabase, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseGeoIPDatabase(file)
+}
+
+// ParseGeoIPDatabase 解析 CSV 格式的 GeoIP 数据
+func ParseGeoIPDatabase(r io.Reader) (*GeoIPDatabase, error) {
+	reader := csv.NewReader(bufio.NewReader(r))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	db := &GeoIPDatabase{}
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line++
+
+		entry, err := parseGeoIPRecord(record)
+		if err != nil {
+			if line == 1 {
+				// 首行可能是表头
+				continue
+			}
+			return nil, fmt.Errorf("geoip line %d: %w", line, err)
+		}
+		db.ranges = append(db.ranges, entry)
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool {
+		return db.ranges[i].start.Less(db.ranges[j].start)
+	})
+	return db, nil
+}
+
+func parseGeoIPRecord(record []string) (geoIPRange, error) {
+	switch len(record) {
+	case 2:
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(record[0]))
+		if err != nil {
+			return geoIPRange{}, err
+		}
+		prefix = prefix.Masked()
+		return geoIPRange{
+			start:   prefix.Addr().Unmap(),
+			end:     lastAddrOfPrefix(prefix).Unmap(),
+			country: normalizeCountryCode(record[1]),
+		}, nil
+	case 3:
+		start, err := netip.ParseAddr(strings.TrimSpace(record[0]))
+		if err != nil {
+			return geoIPRange{}, err
+		}
+		end, err := netip.ParseAddr(strings.TrimSpace(record[1]))
+		if err != nil {
+			return geoIPRange{}, err
+		}
+		start, end = start.Unmap(), end.Unmap()
+		if start.BitLen() != end.BitLen() || end.Less(start) {
+			return geoIPRange{}, fmt.Errorf("invalid range %s-%s", start, end)
+		}
+		return geoIPRange{start: start, end: end, country: normalizeCountryCode(record[2])}, nil
+	}
+	return geoIPRange{}, fmt.Errorf("unexpected field count %d", len(record))
+}
+
+// lastAddrOfPrefix 计算网段的最后一个地址
+func lastAddrOfPrefix(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Addr()
+	bytes := addr.AsSlice()
+	for bit := prefix.Bits(); bit < addr.BitLen(); bit++ {
+		bytes[bit/8] |= 1 << (7 - uint(bit%8))
+	}
+	last, _ := netip.AddrFromSlice(bytes)
+	return last
+}
+
+func normalizeCountryCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// LookupCountry 查询 IP 所属国家/地区代码，未收录时返回空字符串
+func (db *GeoIPDatabase) LookupCountry(ip string) string {
+	if db == nil {
+		return ""
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+
+	// 找到最后一个起始地址不大于 addr 的区间
+	idx := sort.Search(len(db.ranges), func(i int) bool {
+		return addr.Less(db.ranges[i].start)
+	})
+	if idx == 0 {
+		return ""
+	}
+	entry := db.ranges[idx-1]
+	if entry.start.BitLen() != addr.BitLen() || entry.end.Less(addr) {
+		return ""
+	}
+	return entry.country
+}
+
+// Size 返回库中 IP 段数量
+func (db *GeoIPDatabase) Size() int {
+	if db == nil {
+		return 0
+	}
+	return len(db.ranges)
+}
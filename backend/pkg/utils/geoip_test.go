@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeoIPDatabaseLookup(t *testing.T) {
+	data := `network,country_iso_code
+# comment
+1.0.0.0/24,au
+8.8.8.0,8.8.8.255,US
+2001:db8::/32,JP
+`
+	db, err := ParseGeoIPDatabase(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Size() != 3 {
+		t.Fatalf("unexpected size: %d", db.Size())
+	}
+
+	cases := map[string]string{
+		"1.0.0.1":        "AU",
+		"1.0.0.255":      "AU",
+		"1.0.1.0":        "",
+		"8.8.8.8":        "US",
+		"::ffff:8.8.8.8": "US",
+		"2001:db8::1":    "JP",
+		"2001:db9::1":    "",
+		"0.0.0.1":        "",
+		"not-an-ip":      "",
+	}
+	for ip, want := range cases {
+		if got := db.LookupCountry(ip); got != want {
+			t.Errorf("LookupCountry(%s) = %q, want %q", ip, got, want)
+		}
+	}
+}
+
+func TestGeoIPDatabaseInvalidLine(t *testing.T) {
+	if _, err := ParseGeoIPDatabase(strings.NewReader("1.0.0.0/24,AU\nbad,line\n")); err == nil {
+		t.Fatal("expected error for invalid line")
+	}
+}
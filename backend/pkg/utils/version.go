@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersions 比较两个版本号，a<b 返回 -1，相等返回 0，a>b 返回 1
+// 按语义化版本规则处理：忽略前缀 v 和 +build 元数据，数字段逐段比较（缺省补 0），
+// 带预发布标识（如 -beta.1）的版本低于对应的正式版本
+func CompareVersions(a, b string) int {
+	coreA, preA := splitVersion(a)
+	coreB, preB := splitVersion(b)
+
+	if c := compareDotted(coreA, coreB, true); c != 0 {
+		return c
+	}
+
+	switch {
+	case preA == "" && preB == "":
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+	return compareDotted(preA, preB, false)
+}
+
+// splitVersion 拆分为主版本部分和预发布标识
+func splitVersion(version string) (string, string) {
+	version = strings.TrimSpace(version)
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	if idx := strings.Index(version, "+"); idx >= 0 {
+		version = version[:idx]
+	}
+	if idx := strings.Index(version, "-"); idx >= 0 {
+		return version[:idx], version[idx+1:]
+	}
+	return version, ""
+}
+
+// compareDotted 逐段比较点分版本，数字段按数值比较，其余按字符串比较（数字段低于非数字段）
+// padMissing 为 true 时缺少的段视为 0，否则段数少的一方较低
+func compareDotted(a, b string, padMissing bool) int {
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+
+	n := len(partsA)
+	if len(partsB) > n {
+		n = len(partsB)
+	}
+	for i := 0; i < n; i++ {
+		if i >= len(partsA) || i >= len(partsB) {
+			if !padMissing {
+				if i >= len(partsA) {
+					return -1
+				}
+				return 1
+			}
+		}
+		partA, partB := "0", "0"
+		if i < len(partsA) {
+			partA = partsA[i]
+		}
+		if i < len(partsB) {
+			partB = partsB[i]
+		}
+
+		numA, errA := strconv.ParseUint(partA, 10, 64)
+		numB, errB := strconv.ParseUint(partB, 10, 64)
+		switch {
+		case errA == nil && errB == nil:
+			if numA != numB {
+				if numA < numB {
+					return -1
+				}
+				return 1
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(partA, partB); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.2.10", "1.2.9", 1},
+		{"1.10.0", "1.9.9", 1},
+		{"2.0.0", "10.0.0", -1},
+		{"2.0.0-beta.1", "2.0.0", -1},
+		{"2.0.0-beta.2", "2.0.0-beta.10", -1},
+		{"2.0.0-alpha", "2.0.0-beta", -1},
+		{"2.0.0-beta", "2.0.0-beta.1", -1},
+		{"2.0.0-beta.1", "1.9.9", 1},
+		{"1.0.0+build.5", "1.0.0", 0},
+	}
+	for _, c := range cases {
+		if got := CompareVersions(c.a, c.b); got != c.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
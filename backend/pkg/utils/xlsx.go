@@ -0,0 +1,300 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxXLSXPartSize 读取时单个 XML 部件解压后的最大字节数，防止压缩炸弹
+const maxXLSXPartSize = 64 << 20
+
+// WriteXLSX 将二维表写成单工作表的 XLSX 文件（全部按文本单元格写入）
+// 仅覆盖导出所需的最小 OOXML 结构，避免引入额外依赖
+func WriteXLSX(sheetName string, rows [][]string) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer, err := NewXLSXWriter(&buffer, sheetName)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// XLSXWriter 逐行写出单工作表 XLSX 文件，行数据直接写入底层 Writer，适合大数据量流式导出
+type XLSXWriter struct {
+	zipWriter *zip.Writer
+	sheet     io.Writer
+	rows      int
+}
+
+// NewXLSXWriter 创建流式 XLSX 写入器，写完所有行后必须调用 Close
+func NewXLSXWriter(w io.Writer, sheetName string) (*XLSXWriter, error) {
+	var escapedName bytes.Buffer
+	if err := xml.EscapeText(&escapedName, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="` + escapedName.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+	}
+
+	zipWriter := zip.NewWriter(w)
+	for _, file := range files {
+		writer, err := zipWriter.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write([]byte(file.content)); err != nil {
+			return nil, err
+		}
+	}
+
+	// 工作表放在压缩包最后，行数据写入时直接压缩输出
+	sheet, err := zipWriter.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`); err != nil {
+		return nil, err
+	}
+	return &XLSXWriter{zipWriter: zipWriter, sheet: sheet}, nil
+}
+
+// WriteRow 写入一行（全部按文本单元格写入）
+func (x *XLSXWriter) WriteRow(row []string) error {
+	x.rows++
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, `<row r="%d">`, x.rows)
+	for c, value := range row {
+		fmt.Fprintf(&buffer, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, xlsxColumnName(c), x.rows)
+		if err := xml.EscapeText(&buffer, []byte(value)); err != nil {
+			return err
+		}
+		buffer.WriteString(`</t></is></c>`)
+	}
+	buffer.WriteString(`</row>`)
+	_, err := x.sheet.Write(buffer.Bytes())
+	return err
+}
+
+// Close 结束工作表并写出压缩包目录，不关闭底层 Writer
+func (x *XLSXWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return x.zipWriter.Close()
+}
+
+// xlsxColumnName 将从0开始的列序号转换为 A、B、…、Z、AA 形式的列名
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// xlsxColumnIndex 将单元格引用（如 C5）的列部分转换为从0开始的列序号，无法解析时返回 -1
+func xlsxColumnIndex(ref string) int {
+	index := 0
+	letters := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		index = index*26 + int(ch-'A'+1)
+		letters++
+	}
+	if letters == 0 {
+		return -1
+	}
+	return index - 1
+}
+
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var builder strings.Builder
+	for _, run := range t.Runs {
+		builder.WriteString(run.Text)
+	}
+	return builder.String()
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadXLSX 读取 XLSX 文件第一个工作表的全部行，单元格统一按文本返回，第 i 个元素对应表格第 i+1 行
+// 支持共享字符串、内联字符串、数字和布尔值，不计算公式（使用缓存的计算结果）
+func ReadXLSX(data []byte) ([][]string, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx file: %w", err)
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	var sharedStrings []string
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		var sst struct {
+			Items []xlsxRichText `xml:"si"`
+		}
+		if err := decodeZipXML(file, &sst); err != nil {
+			return nil, err
+		}
+		for _, item := range sst.Items {
+			sharedStrings = append(sharedStrings, item.String())
+		}
+	}
+
+	sheetFile, err := firstXLSXSheet(files)
+	if err != nil {
+		return nil, err
+	}
+	var sheet xlsxSheet
+	if err := decodeZipXML(sheetFile, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, sheetRow := range sheet.Rows {
+		// 空行在文件中通常被省略，按行号补齐，保证返回的行序号与表格一致
+		for sheetRow.Number > len(rows)+1 {
+			rows = append(rows, nil)
+		}
+		var row []string
+		for _, cell := range sheetRow.Cells {
+			column := xlsxColumnIndex(cell.Ref)
+			if column < 0 {
+				column = len(row)
+			}
+			for len(row) <= column {
+				row = append(row, "")
+			}
+
+			value := cell.Value
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+				if err != nil || index < 0 || index >= len(sharedStrings) {
+					return nil, fmt.Errorf("invalid shared string index %q in cell %s", cell.Value, cell.Ref)
+				}
+				value = sharedStrings[index]
+			case "inlineStr":
+				value = cell.Inline.String()
+			case "b":
+				value = strconv.FormatBool(cell.Value == "1")
+			}
+			row[column] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// firstXLSXSheet 按工作簿定义找到第一个工作表文件
+func firstXLSXSheet(files map[string]*zip.File) (*zip.File, error) {
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return nil, errors.New("invalid xlsx file: missing workbook")
+	}
+	var workbook struct {
+		Sheets []struct {
+			RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeZipXML(workbookFile, &workbook); err != nil {
+		return nil, err
+	}
+
+	if relsFile, ok := files["xl/_rels/workbook.xml.rels"]; ok && len(workbook.Sheets) > 0 {
+		var rels struct {
+			Relationships []struct {
+				ID     string `xml:"Id,attr"`
+				Target string `xml:"Target,attr"`
+			} `xml:"Relationship"`
+		}
+		if err := decodeZipXML(relsFile, &rels); err != nil {
+			return nil, err
+		}
+		for _, rel := range rels.Relationships {
+			if rel.ID != workbook.Sheets[0].RelID {
+				continue
+			}
+			target := strings.TrimPrefix(rel.Target, "/")
+			if !strings.HasPrefix(target, "xl/") {
+				target = path.Join("xl", target)
+			}
+			if file, ok := files[target]; ok {
+				return file, nil
+			}
+		}
+	}
+
+	if file, ok := files["xl/worksheets/sheet1.xml"]; ok {
+		return file, nil
+	}
+	return nil, errors.New("invalid xlsx file: missing worksheet")
+}
+
+func decodeZipXML(file *zip.File, v interface{}) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("invalid xlsx file: %s: %w", file.Name, err)
+	}
+	return nil
+}
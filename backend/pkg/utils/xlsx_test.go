@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteXLSX(t *testing.T) {
+	data, err := WriteXLSX("codes", [][]string{{"id", "code"}, {"1", "A<&>B"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	var sheet string
+	for _, file := range reader.File {
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("open sheet: %v", err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		sheet = string(content)
+	}
+	if !strings.Contains(sheet, `r="B2"`) || !strings.Contains(sheet, "A&lt;&amp;&gt;B") {
+		t.Fatalf("unexpected sheet content: %s", sheet)
+	}
+
+	for index, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		if got := xlsxColumnName(index); got != want {
+			t.Fatalf("column %d: expected %s, got %s", index, want, got)
+		}
+	}
+}
+
+func TestReadXLSXRoundTrip(t *testing.T) {
+	rows := [][]string{{"name", "email"}, {"A<&>B", ""}, {"", "x@example.com"}}
+	var buffer bytes.Buffer
+	writer, err := NewXLSXWriter(&buffer, "customers")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, row := range rows {
+		if err := writer.WriteRow(row); err != nil {
+			t.Fatalf("write row: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	got, err := ReadXLSX(buffer.Bytes())
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(got) != len(rows) {
+		t.Fatalf("expected %d rows, got %d", len(rows), len(got))
+	}
+	for i := range rows {
+		if strings.Join(got[i], "|") != strings.Join(rows[i], "|") {
+			t.Fatalf("row %d: expected %v, got %v", i, rows[i], got[i])
+		}
+	}
+}
+
+func TestReadXLSXSharedStrings(t *testing.T) {
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Data" sheetId="1" r:id="rId7"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId7" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<si><t>name</t></si><si><r><t>Ac</t></r><r><t>me</t></r></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="b"><v>1</v></c></row>` +
+			`<row r="3"><c r="A3" t="s"><v>1</v></c><c r="B3"><v>42</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	for name, content := range parts {
+		writer, _ := zipWriter.Create(name)
+		writer.Write([]byte(content))
+	}
+	zipWriter.Close()
+
+	rows, err := ReadXLSX(buffer.Bytes())
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := [][]string{{"name", "", "true"}, nil, {"Acme", "42"}}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %v, got %v", want, rows)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Fatalf("row %d: expected %v, got %v", i, want[i], rows[i])
+		}
+	}
+
+	if _, err := ReadXLSX([]byte("not a zip")); err == nil {
+		t.Fatal("expected error for invalid file")
+	}
+}